@@ -64,3 +64,41 @@ func Convert_v1beta1_ExperimentSpec_To_v1alpha1_ExperimentSpec(in *v1beta1.Exper
 func Convert_v1beta1_Metric_To_v1alpha1_Metric(in *v1beta1.Metric, out *Metric, s conversion.Scope) error {
 	return autoConvert_v1beta1_Metric_To_v1alpha1_Metric(in, out, s)
 }
+
+func Convert_v1alpha1_Parameter_To_v1beta1_Parameter(in *Parameter, out *v1beta1.Parameter, s conversion.Scope) error {
+	// Bounds are now int-or-string to allow quantities
+	out.Min = v1beta1.NewParameterBound(in.Min)
+	out.Max = v1beta1.NewParameterBound(in.Max)
+
+	// Continue
+	return autoConvert_v1alpha1_Parameter_To_v1beta1_Parameter(in, out, s)
+}
+
+func Convert_v1beta1_Parameter_To_v1alpha1_Parameter(in *v1beta1.Parameter, out *Parameter, s conversion.Scope) error {
+	out.Min = in.Min.Int64Value()
+	out.Max = in.Max.Int64Value()
+
+	// Continue
+	return autoConvert_v1beta1_Parameter_To_v1alpha1_Parameter(in, out, s)
+}
+
+func Convert_v1beta1_ExperimentStatus_To_v1alpha1_ExperimentStatus(in *v1beta1.ExperimentStatus, out *ExperimentStatus, s conversion.Scope) error {
+	// NOTE: The queue, preemption, condition, estimate and cost fields do not exist in v1alpha1 and are dropped
+
+	// Continue
+	return autoConvert_v1beta1_ExperimentStatus_To_v1alpha1_ExperimentStatus(in, out, s)
+}
+
+func Convert_v1beta1_PatchTemplate_To_v1alpha1_PatchTemplate(in *v1beta1.PatchTemplate, out *PatchTemplate, s conversion.Scope) error {
+	// NOTE: The StatefulSet `Partition` does not exist in v1alpha1 and is dropped
+
+	// Continue
+	return autoConvert_v1beta1_PatchTemplate_To_v1alpha1_PatchTemplate(in, out, s)
+}
+
+func Convert_v1beta1_PatchReadinessGate_To_v1alpha1_PatchReadinessGate(in *v1beta1.PatchReadinessGate, out *PatchReadinessGate, s conversion.Scope) error {
+	// NOTE: The `ReadinessExpression` does not exist in v1alpha1 and is dropped
+
+	// Continue
+	return autoConvert_v1beta1_PatchReadinessGate_To_v1alpha1_PatchReadinessGate(in, out, s)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+
 	"github.com/redskyops/redskyops-controller/api/v1beta1"
 	"k8s.io/apimachinery/pkg/conversion"
 	conv "sigs.k8s.io/controller-runtime/pkg/conversion"
@@ -118,9 +120,41 @@ func Convert_v1beta1_TrialSpec_To_v1alpha1_TrialSpec(in *v1beta1.TrialSpec, out
 	return autoConvert_v1beta1_TrialSpec_To_v1alpha1_TrialSpec(in, out, s)
 }
 
+func Convert_v1alpha1_Assignment_To_v1beta1_Assignment(in *Assignment, out *v1beta1.Assignment, s conversion.Scope) error {
+	// Numeric assignments are now int-or-string to allow categorical values
+	out.Value = v1beta1.NewAssignmentValue(in.Value)
+
+	// Continue
+	return autoConvert_v1alpha1_Assignment_To_v1beta1_Assignment(in, out, s)
+}
+
+func Convert_v1beta1_Assignment_To_v1alpha1_Assignment(in *v1beta1.Assignment, out *Assignment, s conversion.Scope) error {
+	// Categorical (string) assignments cannot be represented as integers
+	v, ok := in.Int64Value()
+	if !ok {
+		return fmt.Errorf("unable to convert categorical assignment '%s' to v1alpha1", in.Name)
+	}
+	out.Value = v
+
+	// Continue
+	return autoConvert_v1beta1_Assignment_To_v1alpha1_Assignment(in, out, s)
+}
+
 func Convert_v1beta1_TrialStatus_To_v1alpha1_TrialStatus(in *v1beta1.TrialStatus, out *TrialStatus, s conversion.Scope) error {
 	// NOTE: Generation skips this function, but we handle the incompatible change in the `Trial` conversion
 
 	// Continue
 	return autoConvert_v1beta1_TrialStatus_To_v1alpha1_TrialStatus(in, out, s)
 }
+
+// Convert_v1beta1_PatchOperation_To_v1alpha1_PatchOperation is an autogenerated conversion function.
+func Convert_v1beta1_PatchOperation_To_v1alpha1_PatchOperation(in *v1beta1.PatchOperation, out *PatchOperation, s conversion.Scope) error {
+	return autoConvert_v1beta1_PatchOperation_To_v1alpha1_PatchOperation(in, out, s)
+}
+
+func Convert_v1beta1_ReadinessCheck_To_v1alpha1_ReadinessCheck(in *v1beta1.ReadinessCheck, out *ReadinessCheck, s conversion.Scope) error {
+	// NOTE: The readiness `Expressions` do not exist in v1alpha1 and are dropped
+
+	// Continue
+	return autoConvert_v1beta1_ReadinessCheck_To_v1alpha1_ReadinessCheck(in, out, s)
+}
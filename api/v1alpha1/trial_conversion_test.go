@@ -108,6 +108,22 @@ func TestTrial_ConvertRoundTrip(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "assignments 64-bit",
+			t: &Trial{
+				Spec: TrialSpec{
+					Assignments: []Assignment{
+						{
+							Name:  "memory",
+							Value: 4294967296,
+						},
+					},
+				},
+				Status: TrialStatus{
+					Assignments: "memory=4294967296",
+				},
+			},
+		},
 		{
 			desc: "patches",
 			t: &Trial{
@@ -35,16 +35,6 @@ func init() {
 // RegisterConversions adds conversion functions to the given scheme.
 // Public to allow building arbitrary schemes.
 func RegisterConversions(s *runtime.Scheme) error {
-	if err := s.AddGeneratedConversionFunc((*Assignment)(nil), (*v1beta1.Assignment)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha1_Assignment_To_v1beta1_Assignment(a.(*Assignment), b.(*v1beta1.Assignment), scope)
-	}); err != nil {
-		return err
-	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.Assignment)(nil), (*Assignment)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_Assignment_To_v1alpha1_Assignment(a.(*v1beta1.Assignment), b.(*Assignment), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ConfigMapHelmValuesFromSource)(nil), (*v1beta1.ConfigMapHelmValuesFromSource)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ConfigMapHelmValuesFromSource_To_v1beta1_ConfigMapHelmValuesFromSource(a.(*ConfigMapHelmValuesFromSource), b.(*v1beta1.ConfigMapHelmValuesFromSource), scope)
 	}); err != nil {
@@ -90,11 +80,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.ExperimentStatus)(nil), (*ExperimentStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_ExperimentStatus_To_v1alpha1_ExperimentStatus(a.(*v1beta1.ExperimentStatus), b.(*ExperimentStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*HelmValue)(nil), (*v1beta1.HelmValue)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_HelmValue_To_v1beta1_HelmValue(a.(*HelmValue), b.(*v1beta1.HelmValue), scope)
 	}); err != nil {
@@ -130,11 +115,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.Metric)(nil), (*Metric)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_Metric_To_v1alpha1_Metric(a.(*v1beta1.Metric), b.(*Metric), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*NamespaceTemplateSpec)(nil), (*v1beta1.NamespaceTemplateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_NamespaceTemplateSpec_To_v1beta1_NamespaceTemplateSpec(a.(*NamespaceTemplateSpec), b.(*v1beta1.NamespaceTemplateSpec), scope)
 	}); err != nil {
@@ -165,16 +145,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*Parameter)(nil), (*v1beta1.Parameter)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha1_Parameter_To_v1beta1_Parameter(a.(*Parameter), b.(*v1beta1.Parameter), scope)
-	}); err != nil {
-		return err
-	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.Parameter)(nil), (*Parameter)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_Parameter_To_v1alpha1_Parameter(a.(*v1beta1.Parameter), b.(*Parameter), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ParameterSelector)(nil), (*v1beta1.ParameterSelector)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ParameterSelector_To_v1beta1_ParameterSelector(a.(*ParameterSelector), b.(*v1beta1.ParameterSelector), scope)
 	}); err != nil {
@@ -190,41 +160,21 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.PatchOperation)(nil), (*PatchOperation)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_PatchOperation_To_v1alpha1_PatchOperation(a.(*v1beta1.PatchOperation), b.(*PatchOperation), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*PatchReadinessGate)(nil), (*v1beta1.PatchReadinessGate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_PatchReadinessGate_To_v1beta1_PatchReadinessGate(a.(*PatchReadinessGate), b.(*v1beta1.PatchReadinessGate), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.PatchReadinessGate)(nil), (*PatchReadinessGate)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_PatchReadinessGate_To_v1alpha1_PatchReadinessGate(a.(*v1beta1.PatchReadinessGate), b.(*PatchReadinessGate), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*PatchTemplate)(nil), (*v1beta1.PatchTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_PatchTemplate_To_v1beta1_PatchTemplate(a.(*PatchTemplate), b.(*v1beta1.PatchTemplate), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.PatchTemplate)(nil), (*PatchTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_PatchTemplate_To_v1alpha1_PatchTemplate(a.(*v1beta1.PatchTemplate), b.(*PatchTemplate), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ReadinessCheck)(nil), (*v1beta1.ReadinessCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ReadinessCheck_To_v1beta1_ReadinessCheck(a.(*ReadinessCheck), b.(*v1beta1.ReadinessCheck), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.ReadinessCheck)(nil), (*ReadinessCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_ReadinessCheck_To_v1alpha1_ReadinessCheck(a.(*v1beta1.ReadinessCheck), b.(*ReadinessCheck), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*SetupTask)(nil), (*v1beta1.SetupTask)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_SetupTask_To_v1beta1_SetupTask(a.(*SetupTask), b.(*v1beta1.SetupTask), scope)
 	}); err != nil {
@@ -310,11 +260,21 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*Assignment)(nil), (*v1beta1.Assignment)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Assignment_To_v1beta1_Assignment(a.(*Assignment), b.(*v1beta1.Assignment), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*ExperimentSpec)(nil), (*v1beta1.ExperimentSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ExperimentSpec_To_v1beta1_ExperimentSpec(a.(*ExperimentSpec), b.(*v1beta1.ExperimentSpec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*Parameter)(nil), (*v1beta1.Parameter)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_Parameter_To_v1beta1_Parameter(a.(*Parameter), b.(*v1beta1.Parameter), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*TrialSpec)(nil), (*v1beta1.TrialSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_TrialSpec_To_v1beta1_TrialSpec(a.(*TrialSpec), b.(*v1beta1.TrialSpec), scope)
 	}); err != nil {
@@ -325,11 +285,51 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.Assignment)(nil), (*Assignment)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Assignment_To_v1alpha1_Assignment(a.(*v1beta1.Assignment), b.(*Assignment), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.ExperimentSpec)(nil), (*ExperimentSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_ExperimentSpec_To_v1alpha1_ExperimentSpec(a.(*v1beta1.ExperimentSpec), b.(*ExperimentSpec), scope)
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta1.ExperimentStatus)(nil), (*ExperimentStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ExperimentStatus_To_v1alpha1_ExperimentStatus(a.(*v1beta1.ExperimentStatus), b.(*ExperimentStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.Metric)(nil), (*Metric)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Metric_To_v1alpha1_Metric(a.(*v1beta1.Metric), b.(*Metric), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.Parameter)(nil), (*Parameter)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_Parameter_To_v1alpha1_Parameter(a.(*v1beta1.Parameter), b.(*Parameter), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.PatchOperation)(nil), (*PatchOperation)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PatchOperation_To_v1alpha1_PatchOperation(a.(*v1beta1.PatchOperation), b.(*PatchOperation), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.PatchReadinessGate)(nil), (*PatchReadinessGate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PatchReadinessGate_To_v1alpha1_PatchReadinessGate(a.(*v1beta1.PatchReadinessGate), b.(*PatchReadinessGate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.PatchTemplate)(nil), (*PatchTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_PatchTemplate_To_v1alpha1_PatchTemplate(a.(*v1beta1.PatchTemplate), b.(*PatchTemplate), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*v1beta1.ReadinessCheck)(nil), (*ReadinessCheck)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_ReadinessCheck_To_v1alpha1_ReadinessCheck(a.(*v1beta1.ReadinessCheck), b.(*ReadinessCheck), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta1.TrialSpec)(nil), (*TrialSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_TrialSpec_To_v1alpha1_TrialSpec(a.(*v1beta1.TrialSpec), b.(*TrialSpec), scope)
 	}); err != nil {
@@ -350,26 +350,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 
 func autoConvert_v1alpha1_Assignment_To_v1beta1_Assignment(in *Assignment, out *v1beta1.Assignment, s conversion.Scope) error {
 	out.Name = in.Name
-	out.Value = in.Value
+	// WARNING: in.Value requires manual conversion: inconvertible types (int64 vs github.com/redskyops/redskyops-controller/api/v1beta1.AssignmentValue)
 	return nil
 }
 
-// Convert_v1alpha1_Assignment_To_v1beta1_Assignment is an autogenerated conversion function.
-func Convert_v1alpha1_Assignment_To_v1beta1_Assignment(in *Assignment, out *v1beta1.Assignment, s conversion.Scope) error {
-	return autoConvert_v1alpha1_Assignment_To_v1beta1_Assignment(in, out, s)
-}
-
 func autoConvert_v1beta1_Assignment_To_v1alpha1_Assignment(in *v1beta1.Assignment, out *Assignment, s conversion.Scope) error {
 	out.Name = in.Name
-	out.Value = in.Value
+	// WARNING: in.Value requires manual conversion: inconvertible types (github.com/redskyops/redskyops-controller/api/v1beta1.AssignmentValue vs int64)
 	return nil
 }
 
-// Convert_v1beta1_Assignment_To_v1alpha1_Assignment is an autogenerated conversion function.
-func Convert_v1beta1_Assignment_To_v1alpha1_Assignment(in *v1beta1.Assignment, out *Assignment, s conversion.Scope) error {
-	return autoConvert_v1beta1_Assignment_To_v1alpha1_Assignment(in, out, s)
-}
-
 func autoConvert_v1alpha1_ConfigMapHelmValuesFromSource_To_v1beta1_ConfigMapHelmValuesFromSource(in *ConfigMapHelmValuesFromSource, out *v1beta1.ConfigMapHelmValuesFromSource, s conversion.Scope) error {
 	out.LocalObjectReference = in.LocalObjectReference
 	return nil
@@ -594,6 +584,7 @@ func autoConvert_v1alpha1_ExperimentSpec_To_v1beta1_ExperimentSpec(in *Experimen
 
 func autoConvert_v1beta1_ExperimentSpec_To_v1alpha1_ExperimentSpec(in *v1beta1.ExperimentSpec, out *ExperimentSpec, s conversion.Scope) error {
 	out.Replicas = in.Replicas
+	// WARNING: in.Priority requires manual conversion: does not exist in peer-type
 	if in.Optimization != nil {
 		in, out := &in.Optimization, &out.Optimization
 		*out = make([]Optimization, len(*in))
@@ -638,6 +629,8 @@ func autoConvert_v1beta1_ExperimentSpec_To_v1alpha1_ExperimentSpec(in *v1beta1.E
 	} else {
 		out.Metrics = nil
 	}
+	// WARNING: in.Convergence requires manual conversion: does not exist in peer-type
+	// WARNING: in.CostModel requires manual conversion: does not exist in peer-type
 	if in.Patches != nil {
 		in, out := &in.Patches, &out.Patches
 		*out = make([]PatchTemplate, len(*in))
@@ -649,6 +642,7 @@ func autoConvert_v1beta1_ExperimentSpec_To_v1alpha1_ExperimentSpec(in *v1beta1.E
 	} else {
 		out.Patches = nil
 	}
+	// WARNING: in.AssignmentWebhook requires manual conversion: does not exist in peer-type
 	out.NamespaceSelector = in.NamespaceSelector
 	if in.NamespaceTemplate != nil {
 		in, out := &in.NamespaceTemplate, &out.NamespaceTemplate
@@ -661,6 +655,7 @@ func autoConvert_v1beta1_ExperimentSpec_To_v1alpha1_ExperimentSpec(in *v1beta1.E
 	}
 	out.Selector = in.Selector
 	// WARNING: in.TrialTemplate requires manual conversion: does not exist in peer-type
+	// WARNING: in.PodTemplateOverlay requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -678,14 +673,18 @@ func Convert_v1alpha1_ExperimentStatus_To_v1beta1_ExperimentStatus(in *Experimen
 func autoConvert_v1beta1_ExperimentStatus_To_v1alpha1_ExperimentStatus(in *v1beta1.ExperimentStatus, out *ExperimentStatus, s conversion.Scope) error {
 	out.Phase = in.Phase
 	out.ActiveTrials = in.ActiveTrials
+	// WARNING: in.Queue requires manual conversion: does not exist in peer-type
+	// WARNING: in.Waiting requires manual conversion: does not exist in peer-type
+	// WARNING: in.PreemptedBy requires manual conversion: does not exist in peer-type
+	// WARNING: in.Preemptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
+	// WARNING: in.AverageTrialDuration requires manual conversion: does not exist in peer-type
+	// WARNING: in.EstimatedCompletionTime requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResourceHours requires manual conversion: does not exist in peer-type
+	// WARNING: in.Cost requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_ExperimentStatus_To_v1alpha1_ExperimentStatus is an autogenerated conversion function.
-func Convert_v1beta1_ExperimentStatus_To_v1alpha1_ExperimentStatus(in *v1beta1.ExperimentStatus, out *ExperimentStatus, s conversion.Scope) error {
-	return autoConvert_v1beta1_ExperimentStatus_To_v1alpha1_ExperimentStatus(in, out, s)
-}
-
 func autoConvert_v1alpha1_HelmValue_To_v1beta1_HelmValue(in *HelmValue, out *v1beta1.HelmValue, s conversion.Scope) error {
 	out.Name = in.Name
 	out.ForceString = in.ForceString
@@ -824,6 +823,9 @@ func autoConvert_v1beta1_Metric_To_v1alpha1_Metric(in *v1beta1.Metric, out *Metr
 	out.Type = MetricType(in.Type)
 	out.Query = in.Query
 	out.ErrorQuery = in.ErrorQuery
+	// WARNING: in.Guard requires manual conversion: does not exist in peer-type
+	// WARNING: in.Warmup requires manual conversion: does not exist in peer-type
+	// WARNING: in.Window requires manual conversion: does not exist in peer-type
 	out.Scheme = in.Scheme
 	out.Selector = in.Selector
 	out.Port = in.Port
@@ -901,28 +903,22 @@ func Convert_v1beta1_OrderConstraint_To_v1alpha1_OrderConstraint(in *v1beta1.Ord
 
 func autoConvert_v1alpha1_Parameter_To_v1beta1_Parameter(in *Parameter, out *v1beta1.Parameter, s conversion.Scope) error {
 	out.Name = in.Name
-	out.Min = in.Min
-	out.Max = in.Max
+	// WARNING: in.Min requires manual conversion: inconvertible types (int64 vs github.com/redskyops/redskyops-controller/api/v1beta1.ParameterBound)
+	// WARNING: in.Max requires manual conversion: inconvertible types (int64 vs github.com/redskyops/redskyops-controller/api/v1beta1.ParameterBound)
 	return nil
 }
 
-// Convert_v1alpha1_Parameter_To_v1beta1_Parameter is an autogenerated conversion function.
-func Convert_v1alpha1_Parameter_To_v1beta1_Parameter(in *Parameter, out *v1beta1.Parameter, s conversion.Scope) error {
-	return autoConvert_v1alpha1_Parameter_To_v1beta1_Parameter(in, out, s)
-}
-
 func autoConvert_v1beta1_Parameter_To_v1alpha1_Parameter(in *v1beta1.Parameter, out *Parameter, s conversion.Scope) error {
 	out.Name = in.Name
-	out.Min = in.Min
-	out.Max = in.Max
+	// WARNING: in.Min requires manual conversion: inconvertible types (github.com/redskyops/redskyops-controller/api/v1beta1.ParameterBound vs int64)
+	// WARNING: in.Max requires manual conversion: inconvertible types (github.com/redskyops/redskyops-controller/api/v1beta1.ParameterBound vs int64)
+	// WARNING: in.Values requires manual conversion: does not exist in peer-type
+	// WARNING: in.Sensitive requires manual conversion: does not exist in peer-type
+	// WARNING: in.Reference requires manual conversion: does not exist in peer-type
+	// WARNING: in.Catalog requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_Parameter_To_v1alpha1_Parameter is an autogenerated conversion function.
-func Convert_v1beta1_Parameter_To_v1alpha1_Parameter(in *v1beta1.Parameter, out *Parameter, s conversion.Scope) error {
-	return autoConvert_v1beta1_Parameter_To_v1alpha1_Parameter(in, out, s)
-}
-
 func autoConvert_v1alpha1_ParameterSelector_To_v1beta1_ParameterSelector(in *ParameterSelector, out *v1beta1.ParameterSelector, s conversion.Scope) error {
 	out.Name = in.Name
 	return nil
@@ -964,15 +960,11 @@ func autoConvert_v1beta1_PatchOperation_To_v1alpha1_PatchOperation(in *v1beta1.P
 	if err := conversion.Convert_Slice_byte_To_Slice_byte(&in.Data, &out.Data, s); err != nil {
 		return err
 	}
+	// WARNING: in.DataRef requires manual conversion: does not exist in peer-type
 	out.AttemptsRemaining = in.AttemptsRemaining
 	return nil
 }
 
-// Convert_v1beta1_PatchOperation_To_v1alpha1_PatchOperation is an autogenerated conversion function.
-func Convert_v1beta1_PatchOperation_To_v1alpha1_PatchOperation(in *v1beta1.PatchOperation, out *PatchOperation, s conversion.Scope) error {
-	return autoConvert_v1beta1_PatchOperation_To_v1alpha1_PatchOperation(in, out, s)
-}
-
 func autoConvert_v1alpha1_PatchReadinessGate_To_v1beta1_PatchReadinessGate(in *PatchReadinessGate, out *v1beta1.PatchReadinessGate, s conversion.Scope) error {
 	out.ConditionType = in.ConditionType
 	return nil
@@ -985,14 +977,10 @@ func Convert_v1alpha1_PatchReadinessGate_To_v1beta1_PatchReadinessGate(in *Patch
 
 func autoConvert_v1beta1_PatchReadinessGate_To_v1alpha1_PatchReadinessGate(in *v1beta1.PatchReadinessGate, out *PatchReadinessGate, s conversion.Scope) error {
 	out.ConditionType = in.ConditionType
+	// WARNING: in.ReadinessExpression requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_PatchReadinessGate_To_v1alpha1_PatchReadinessGate is an autogenerated conversion function.
-func Convert_v1beta1_PatchReadinessGate_To_v1alpha1_PatchReadinessGate(in *v1beta1.PatchReadinessGate, out *PatchReadinessGate, s conversion.Scope) error {
-	return autoConvert_v1beta1_PatchReadinessGate_To_v1alpha1_PatchReadinessGate(in, out, s)
-}
-
 func autoConvert_v1alpha1_PatchTemplate_To_v1beta1_PatchTemplate(in *PatchTemplate, out *v1beta1.PatchTemplate, s conversion.Scope) error {
 	out.Type = v1beta1.PatchType(in.Type)
 	out.Patch = in.Patch
@@ -1020,6 +1008,7 @@ func autoConvert_v1beta1_PatchTemplate_To_v1alpha1_PatchTemplate(in *v1beta1.Pat
 	out.Type = PatchType(in.Type)
 	out.Patch = in.Patch
 	out.TargetRef = in.TargetRef
+	// WARNING: in.Partition requires manual conversion: does not exist in peer-type
 	if in.ReadinessGates != nil {
 		in, out := &in.ReadinessGates, &out.ReadinessGates
 		*out = make([]PatchReadinessGate, len(*in))
@@ -1034,11 +1023,6 @@ func autoConvert_v1beta1_PatchTemplate_To_v1alpha1_PatchTemplate(in *v1beta1.Pat
 	return nil
 }
 
-// Convert_v1beta1_PatchTemplate_To_v1alpha1_PatchTemplate is an autogenerated conversion function.
-func Convert_v1beta1_PatchTemplate_To_v1alpha1_PatchTemplate(in *v1beta1.PatchTemplate, out *PatchTemplate, s conversion.Scope) error {
-	return autoConvert_v1beta1_PatchTemplate_To_v1alpha1_PatchTemplate(in, out, s)
-}
-
 func autoConvert_v1alpha1_ReadinessCheck_To_v1beta1_ReadinessCheck(in *ReadinessCheck, out *v1beta1.ReadinessCheck, s conversion.Scope) error {
 	out.TargetRef = in.TargetRef
 	out.Selector = in.Selector
@@ -1063,14 +1047,10 @@ func autoConvert_v1beta1_ReadinessCheck_To_v1alpha1_ReadinessCheck(in *v1beta1.R
 	out.PeriodSeconds = in.PeriodSeconds
 	out.AttemptsRemaining = in.AttemptsRemaining
 	out.LastCheckTime = in.LastCheckTime
+	// WARNING: in.Expressions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_ReadinessCheck_To_v1alpha1_ReadinessCheck is an autogenerated conversion function.
-func Convert_v1beta1_ReadinessCheck_To_v1alpha1_ReadinessCheck(in *v1beta1.ReadinessCheck, out *ReadinessCheck, s conversion.Scope) error {
-	return autoConvert_v1beta1_ReadinessCheck_To_v1alpha1_ReadinessCheck(in, out, s)
-}
-
 func autoConvert_v1alpha1_SetupTask_To_v1beta1_SetupTask(in *SetupTask, out *v1beta1.SetupTask, s conversion.Scope) error {
 	out.Name = in.Name
 	out.Image = in.Image
@@ -1418,9 +1398,13 @@ func autoConvert_v1beta1_TrialSpec_To_v1alpha1_TrialSpec(in *v1beta1.TrialSpec,
 	}
 	out.Selector = in.Selector
 	// WARNING: in.JobTemplate requires manual conversion: does not exist in peer-type
+	// WARNING: in.JobTemplateRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.PodTemplateOverlay requires manual conversion: does not exist in peer-type
 	out.InitialDelaySeconds = in.InitialDelaySeconds
 	out.StartTimeOffset = in.StartTimeOffset
 	out.ApproximateRuntime = in.ApproximateRuntime
+	// WARNING: in.Warmup requires manual conversion: does not exist in peer-type
+	// WARNING: in.CompletionPolicy requires manual conversion: does not exist in peer-type
 	out.TTLSecondsAfterFinished = in.TTLSecondsAfterFinished
 	out.TTLSecondsAfterFailure = in.TTLSecondsAfterFailure
 	if in.ReadinessGates != nil {
@@ -1434,6 +1418,7 @@ func autoConvert_v1beta1_TrialSpec_To_v1alpha1_TrialSpec(in *v1beta1.TrialSpec,
 	} else {
 		out.ReadinessGates = nil
 	}
+	// WARNING: in.Abort requires manual conversion: does not exist in peer-type
 	if in.Values != nil {
 		in, out := &in.Values, &out.Values
 		*out = make([]Value, len(*in))
@@ -1492,6 +1477,7 @@ func autoConvert_v1beta1_TrialStatus_To_v1alpha1_TrialStatus(in *v1beta1.TrialSt
 	out.Phase = in.Phase
 	out.Assignments = in.Assignments
 	out.Values = in.Values
+	// WARNING: in.WarmupStartTime requires manual conversion: does not exist in peer-type
 	out.StartTime = in.StartTime
 	out.CompletionTime = in.CompletionTime
 	if in.Conditions != nil {
@@ -1505,8 +1491,12 @@ func autoConvert_v1beta1_TrialStatus_To_v1alpha1_TrialStatus(in *v1beta1.TrialSt
 	} else {
 		out.Conditions = nil
 	}
+	// WARNING: in.MetricCollection requires manual conversion: does not exist in peer-type
 	// WARNING: in.PatchOperations requires manual conversion: does not exist in peer-type
 	// WARNING: in.ReadinessChecks requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResolvedAssignments requires manual conversion: does not exist in peer-type
+	// WARNING: in.ResourceHours requires manual conversion: does not exist in peer-type
+	// WARNING: in.Cost requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -17,6 +17,11 @@ limitations under the License.
 package v1beta1
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -54,3 +59,68 @@ func (in *Experiment) TrialSelector() *metav1.LabelSelector {
 		},
 	}
 }
+
+// ParseAssignmentValue returns the value assigned to the named parameter from its string representation; the values
+// of categorical parameters are always strings, even when they look like numbers
+func ParseAssignmentValue(parameters []Parameter, name, value string) AssignmentValue {
+	for i := range parameters {
+		if parameters[i].Name == name && len(parameters[i].Values) > 0 {
+			return NewCategoricalAssignmentValue(value)
+		}
+	}
+	if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return NewAssignmentValue(v)
+	}
+	return NewCategoricalAssignmentValue(value)
+}
+
+// NewParameterBound returns a parameter bound for the supplied value
+func NewParameterBound(v int64) ParameterBound {
+	return ParameterBound{IntOrString: fromInt64(v)}
+}
+
+// Int64Value returns the value of the bound
+func (in ParameterBound) Int64Value() int64 {
+	v, _ := int64Value(in.IntOrString)
+	return v
+}
+
+// String returns the decimal representation of the bound
+func (in ParameterBound) String() string {
+	return strconv.FormatInt(in.Int64Value(), 10)
+}
+
+// MarshalJSON writes the bound as a JSON number
+func (in ParameterBound) MarshalJSON() ([]byte, error) {
+	return []byte(in.String()), nil
+}
+
+// UnmarshalJSON reads the bound from a JSON number or a quantity string
+func (in *ParameterBound) UnmarshalJSON(b []byte) error {
+	if len(b) == 0 || string(b) == "null" {
+		*in = ParameterBound{}
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		// Not a string, parse the JSON number directly to avoid a lossy float64 conversion
+		v, err := strconv.ParseInt(string(b), 10, 64)
+		if err != nil {
+			return err
+		}
+		*in = NewParameterBound(v)
+		return nil
+	}
+
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return err
+	}
+	v, ok := q.AsInt64()
+	if !ok {
+		return fmt.Errorf("%s is not a 64-bit integer", s)
+	}
+	*in = NewParameterBound(v)
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParameterBound_UnmarshalJSON(t *testing.T) {
+	cases := []struct {
+		desc     string
+		data     string
+		expected ParameterBound
+	}{
+		{
+			desc:     "integer",
+			data:     `100`,
+			expected: NewParameterBound(100),
+		},
+		{
+			desc:     "64-bit integer",
+			data:     `9007199254740993`,
+			expected: NewParameterBound(9007199254740993),
+		},
+		{
+			desc:     "quantity",
+			data:     `"4Gi"`,
+			expected: NewParameterBound(4 * 1024 * 1024 * 1024),
+		},
+		{
+			desc:     "decimal string",
+			data:     `"250"`,
+			expected: NewParameterBound(250),
+		},
+		{
+			desc: "null",
+			data: `null`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			var actual ParameterBound
+			if assert.NoError(t, json.Unmarshal([]byte(c.data), &actual)) {
+				assert.Equal(t, c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParameterBound_UnmarshalInvalid(t *testing.T) {
+	var b ParameterBound
+	assert.Error(t, json.Unmarshal([]byte(`1.5`), &b))
+	assert.Error(t, json.Unmarshal([]byte(`"500m"`), &b))
+	assert.Error(t, json.Unmarshal([]byte(`"fast"`), &b))
+}
+
+func TestParameter_JSON(t *testing.T) {
+	p := &Parameter{}
+	if assert.NoError(t, json.Unmarshal([]byte(`{"name":"memory","min":"512Mi","max":"4Gi"}`), p)) {
+		assert.Equal(t, &Parameter{Name: "memory", Min: NewParameterBound(512 * 1024 * 1024), Max: NewParameterBound(4 * 1024 * 1024 * 1024)}, p)
+	}
+
+	b, err := json.Marshal(p)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"name":"memory","min":536870912,"max":4294967296}`, string(b))
+	}
+}
@@ -23,7 +23,8 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-// Optimization is a configuration setting for the optimizer
+// Optimization is a configuration setting for the optimizer, for example the "seed" setting makes the burn-in trials
+// reproducible across experiment runs
 type Optimization struct {
 	// Name is the name of the optimization configuration to set
 	Name string `json:"name"`
@@ -36,9 +37,44 @@ type Parameter struct {
 	// The name of the parameter
 	Name string `json:"name"`
 	// The inclusive minimum value of the parameter
-	Min int64 `json:"min,omitempty"`
+	Min ParameterBound `json:"min,omitempty"`
 	// The inclusive maximum value of the parameter
-	Max int64 `json:"max,omitempty"`
+	Max ParameterBound `json:"max,omitempty"`
+	// The discrete values of a categorical parameter, the minimum and maximum are ignored when specified
+	Values []string `json:"values,omitempty"`
+	// Sensitive parameters have their values redacted from logs and trial summaries
+	Sensitive bool `json:"sensitive,omitempty"`
+	// Reference makes this a percentage parameter: the minimum and maximum are percentages (e.g. 50 to 150) of the
+	// referenced value, which is read before the first trial is patched and pinned on the experiment
+	Reference *ParameterReference `json:"reference,omitempty"`
+	// Catalog maps the assigned value to the entry with the nearest capacity (e.g. a real instance type); the patches
+	// are rendered using the name of the selected entry
+	Catalog []CatalogEntry `json:"catalog,omitempty"`
+}
+
+// ParameterBound is the 64-bit integer value of a parameter bound, it is serialized as a JSON number but may also be
+// expressed as a quantity string (e.g. "4Gi"). The bound embeds an IntOrString so the generated schema accepts either
+// form, use Int64Value to read it
+// +kubebuilder:validation:Type=""
+type ParameterBound struct {
+	intstr.IntOrString `json:",inline"`
+}
+
+// CatalogEntry is a discrete choice, such as an instance type or node pool, a numeric parameter can be mapped to
+type CatalogEntry struct {
+	// Name of the entry, e.g. "m5.xlarge"
+	Name string `json:"name"`
+	// Capacity of the entry expressed on the same scale as the parameter
+	Capacity resource.Quantity `json:"capacity"`
+}
+
+// ParameterReference identifies the current value in the cluster a percentage parameter is relative to
+type ParameterReference struct {
+	// TargetRef is the object containing the referenced value, defaults to the trial namespace
+	TargetRef corev1.ObjectReference `json:"targetRef"`
+	// FieldPath is a JSONPath expression selecting a numeric or quantity value from the target, e.g.
+	// "{.spec.template.spec.containers[0].resources.requests.memory}"
+	FieldPath string `json:"fieldPath"`
 }
 
 // Constraint represents a constraint to the domain of the parameters
@@ -87,12 +123,18 @@ const (
 	// MetricPods metrics are similar to local metrics, however the list of pods in the trial namespace matched by the selector
 	// is also available.
 	MetricPods MetricType = "pods"
+	// MetricHPA metrics are similar to local metrics, however the horizontal pod autoscalers in the trial namespace
+	// matched by the selector and their scaling events are also available.
+	MetricHPA MetricType = "hpa"
 	// MetricPrometheus metrics issue PromQL queries to a matched service. Queries MUST evaluate to a scalar value.
 	MetricPrometheus MetricType = "prometheus"
 	// MetricDatadog metrics issue queries to the Datadog service. Requires API and application key configuration.
 	MetricDatadog MetricType = "datadog"
 	// MetricJSONPath metrics fetch a JSON resource from the matched service. Queries are JSON path expression evaluated against the resource.
 	MetricJSONPath MetricType = "jsonpath"
+	// MetricCEL metrics are derived from the other metrics of the trial. Queries are CEL expressions evaluated against the
+	// collected metric values (available by name from the "metrics" map).
+	MetricCEL MetricType = "cel"
 )
 
 // Metric represents an observable outcome from a trial run
@@ -102,12 +144,19 @@ type Metric struct {
 	// Indicator that the goal of the experiment is to minimize the value of this metric
 	Minimize bool `json:"minimize,omitempty"`
 
-	// The metric collection type, one of: local|pods|prometheus|datadog|jsonpath, default: local
+	// The metric collection type, one of: local|pods|hpa|prometheus|datadog|jsonpath|cel, default: local
 	Type MetricType `json:"type,omitempty"`
 	// Collection type specific query, e.g. Go template for "local", PromQL for "prometheus" or a JSON pointer expression (with curly braces) for "jsonpath"
 	Query string `json:"query"`
 	// Collection type specific query for the error associated with collected metric value
 	ErrorQuery string `json:"errorQuery,omitempty"`
+	// Guard is a CEL expression evaluated against the collected value (available as "value" and "error"), the trial
+	// fails if the expression does not evaluate to true
+	Guard string `json:"guard,omitempty"`
+	// Warmup is the amount of time at the start of the trial run excluded from the measurement
+	Warmup *metav1.Duration `json:"warmup,omitempty"`
+	// Window limits the measurement to the amount of time immediately preceding the completion of the trial run
+	Window *metav1.Duration `json:"window,omitempty"`
 
 	// The scheme to use when collecting metrics
 	Scheme string `json:"scheme,omitempty"`
@@ -123,10 +172,34 @@ type Metric struct {
 	URL string `json:"url,omitempty"`
 }
 
+// ConvergenceCriteria describes when an experiment is considered to have stopped making progress
+type ConvergenceCriteria struct {
+	// Trials is the number of most recently completed trials that must fail to improve any metric for the experiment
+	// to be considered converged
+	Trials int32 `json:"trials"`
+	// Tolerance is the smallest relative improvement of a metric value which is still considered progress, e.g. "0.01"
+	// or "10m" for one percent; defaults to zero
+	Tolerance resource.Quantity `json:"tolerance,omitempty"`
+	// Complete indicates the experiment should be completed once it converges instead of running the remaining budget
+	Complete bool `json:"complete,omitempty"`
+}
+
+// CostModel describes how the resources consumed by the trial workloads are accounted for
+type CostModel struct {
+	// Selector matches the pods of the trial workloads whose resource requests are accounted for
+	Selector *metav1.LabelSelector `json:"selector"`
+	// Weights is the cost of a resource for one hour, using the same comma separated "name=weight" syntax and units as
+	// the weights of the `resourceRequests` metric function (e.g. "cpu=0.017,memory=0.000000000003")
+	Weights string `json:"weights,omitempty"`
+}
+
 // PatchReadinessGate contains a reference to a condition
 type PatchReadinessGate struct {
 	// ConditionType refers to a condition in the patched target's condition list
-	ConditionType string `json:"conditionType"`
+	ConditionType string `json:"conditionType,omitempty"`
+	// ReadinessExpression is evaluated against the patched target instead of a condition, this allows the readiness of
+	// arbitrary custom resources to be determined from their status
+	ReadinessExpression `json:",inline"`
 }
 
 // PatchType represents the allowable types of patches
@@ -149,6 +222,9 @@ type PatchTemplate struct {
 	Patch string `json:"patch"`
 	// Direct reference to the object the patch should be applied to
 	TargetRef *corev1.ObjectReference `json:"targetRef,omitempty"`
+	// Partition limits a StatefulSet patch to the pods with an ordinal greater than or equal to the partition, this can
+	// be used to measure a "canary" subset of the pods
+	Partition *int32 `json:"partition,omitempty"`
 	// ReadinessGates will be evaluated for patch target readiness. A patch target is ready if all conditions specified
 	// in the readiness gates have a status equal to "True". If no readiness gates are specified, some target types may
 	// have default gates assigned to them. Some condition checks may result in errors, e.g. a condition type of "Ready"
@@ -157,6 +233,27 @@ type PatchTemplate struct {
 	ReadinessGates []PatchReadinessGate `json:"readinessGates,omitempty"`
 }
 
+// WebhookFailurePolicy describes how a failed webhook call is handled
+type WebhookFailurePolicy string
+
+const (
+	// FailWebhook fails the trial when the webhook cannot be called
+	FailWebhook WebhookFailurePolicy = "Fail"
+	// IgnoreWebhook ignores webhook failures and continues with the original values
+	IgnoreWebhook WebhookFailurePolicy = "Ignore"
+)
+
+// AssignmentWebhook is an HTTP endpoint used to transform trial assignments; the trial assignments are sent in a JSON
+// POST request and the response contains the assignments that should be used instead
+type AssignmentWebhook struct {
+	// URL of the webhook
+	URL string `json:"url"`
+	// TimeoutSeconds is the maximum amount of time to wait for the webhook to respond, defaults to 10 seconds
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// FailurePolicy specifies how to treat webhook failures, one of: Fail|Ignore, default: Fail
+	FailurePolicy WebhookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
 // NamespaceTemplateSpec is used as a template for creating new namespaces
 type NamespaceTemplateSpec struct {
 	// Standard object metadata
@@ -177,6 +274,9 @@ type TrialTemplateSpec struct {
 type ExperimentSpec struct {
 	// Replicas is the number of trials to execute concurrently, defaults to 1
 	Replicas *int32 `json:"replicas,omitempty"`
+	// Priority of the experiment relative to other experiments competing for the same cluster capacity; lower priority
+	// experiments stop starting new trials while a higher priority experiment is waiting for capacity
+	Priority int32 `json:"priority,omitempty"`
 	// Optimization defines additional configuration for the optimization
 	Optimization []Optimization `json:"optimization,omitempty"`
 	// Parameters defines the search space for the experiment
@@ -185,9 +285,16 @@ type ExperimentSpec struct {
 	Constraints []Constraint `json:"constraints,omitempty"`
 	// Metrics defines the outcomes for the experiment
 	Metrics []Metric `json:"metrics"`
+	// Convergence defines when the experiment should be considered to have stopped improving
+	Convergence *ConvergenceCriteria `json:"convergence,omitempty"`
+	// CostModel defines how the resources consumed by the trials of the experiment are accounted for
+	CostModel *CostModel `json:"costModel,omitempty"`
 	// Patches is a sequence of templates written against the experiment parameters that will be used to put the
 	// cluster into the desired state
 	Patches []PatchTemplate `json:"patches,omitempty"`
+	// AssignmentWebhook is called with the trial assignments before the patches are rendered, allowing the suggested
+	// values to be adjusted (e.g. rounded or mapped to discrete sizes) before they are applied to the cluster
+	AssignmentWebhook *AssignmentWebhook `json:"assignmentWebhook,omitempty"`
 	// NamespaceSelector is used to locate existing namespaces for trials
 	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 	// NamespaceTemplate can be specified to create new namespaces for trials; if specified created namespaces must be
@@ -199,6 +306,40 @@ type ExperimentSpec struct {
 	// initial namespace, however other namespaces (matched by NamespaceSelector) will be used if the effective
 	// replica count is more then one
 	TrialTemplate TrialTemplateSpec `json:"trialTemplate,omitempty"`
+	// PodTemplateOverlay is a strategic merge patch (YAML or JSON) applied to the pod template of every job created
+	// for the experiment's trials, e.g. to add the annotations, labels or image pull secrets required by cluster policy
+	PodTemplateOverlay string `json:"podTemplateOverlay,omitempty"`
+}
+
+// ExperimentConditionType represents the possible observable conditions for an experiment
+type ExperimentConditionType string
+
+const (
+	// ExperimentMetricsDegraded is a condition that indicates metric backends are failing, new trials are not started
+	// while the status is "True"
+	ExperimentMetricsDegraded ExperimentConditionType = "redskyops.dev/metrics-degraded"
+	// ExperimentBoundsLimited is a condition that indicates the best trials of a completed experiment were found at the
+	// bounds of one or more parameters, the message suggests expanded bounds for a follow-up experiment
+	ExperimentBoundsLimited ExperimentConditionType = "redskyops.dev/bounds-limited"
+	// ExperimentConverged is a condition that indicates the recently completed trials of an experiment have not improved
+	// upon the best metric values found so far
+	ExperimentConverged ExperimentConditionType = "redskyops.dev/converged"
+)
+
+// ExperimentCondition represents an observed condition of an experiment
+type ExperimentCondition struct {
+	// The condition type, e.g. "redskyops.dev/metrics-degraded"
+	Type ExperimentConditionType `json:"type"`
+	// The status of the condition, one of "True", "False", or "Unknown
+	Status corev1.ConditionStatus `json:"status"`
+	// The last known time the condition was checked
+	LastProbeTime metav1.Time `json:"lastProbeTime"`
+	// The time at which the condition last changed status
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	// A reason code describing the why the condition occurred
+	Reason string `json:"reason,omitempty"`
+	// A human readable message describing the transition
+	Message string `json:"message,omitempty"`
 }
 
 // ExperimentStatus defines the observed state of Experiment
@@ -207,9 +348,40 @@ type ExperimentStatus struct {
 	Phase string `json:"phase"`
 	// ActiveTrials is the observed number of running trials
 	ActiveTrials int32 `json:"activeTrials"`
+	// Queue is the list of pending trials which have been suggested but have not yet started, in the order they
+	// will be started
+	Queue []QueuedTrial `json:"queue,omitempty"`
+	// Waiting indicates the experiment is unable to start a new trial because there is no capacity available
+	Waiting bool `json:"waiting,omitempty"`
+	// PreemptedBy is the higher priority experiment this experiment is currently yielding capacity to
+	PreemptedBy string `json:"preemptedBy,omitempty"`
+	// Preemptions is the number of times this experiment has yielded capacity to a higher priority experiment
+	Preemptions int32 `json:"preemptions,omitempty"`
+	// Conditions is the current state of the experiment
+	Conditions []ExperimentCondition `json:"conditions,omitempty"`
+	// AverageTrialDuration is the rolling average time it took the most recently finished trials to run
+	AverageTrialDuration *metav1.Duration `json:"averageTrialDuration,omitempty"`
+	// EstimatedCompletionTime is the projected time the experiment will exhaust its trial budget
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
+	// ResourceHours is the total resource hours consumed by the trial workloads of the experiment
+	ResourceHours corev1.ResourceList `json:"resourceHours,omitempty"`
+	// Cost is the total cost of the resource hours consumed by the trial workloads of the experiment
+	Cost string `json:"cost,omitempty"`
 	// TODO Number of trials: Succeeded, Failed int32 (this would need to be fetch remotely, falling back to the in cluster count)
 }
 
+// QueuedTrial is a reference to a pending trial
+type QueuedTrial struct {
+	// Name of the trial
+	Name string `json:"name"`
+	// Namespace of the trial
+	Namespace string `json:"namespace"`
+	// Priority of the trial, higher priority trials are started first
+	Priority int32 `json:"priority,omitempty"`
+	// Assignments of the trial
+	Assignments []Assignment `json:"assignments,omitempty"`
+}
+
 // +genclient
 // +kubebuilder:object:root=true
 // +kubebuilder:storageversion
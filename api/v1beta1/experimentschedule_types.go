@@ -0,0 +1,130 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConcurrencyPolicy describes how scheduled experiments are handled when a previous run is still active
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent allows scheduled experiments to run concurrently
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+	// ForbidConcurrent skips a scheduled run if the previous experiment has not completed
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+	// ReplaceConcurrent deletes the active experiment and replaces it with a new one
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+// ExperimentTemplateSpec is used as a template for creating new experiments
+type ExperimentTemplateSpec struct {
+	// Standard object metadata
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the desired behavior for the experiment
+	Spec ExperimentSpec `json:"spec,omitempty"`
+}
+
+// DriftDetection monitors an objective metric once an experiment completes and starts a new run if it degrades
+type DriftDetection struct {
+	// Metric is the objective to monitor, queries are evaluated over the most recent interval; defaults to the first
+	// non-local metric of the experiment template (drift detection is disabled if there is no such metric)
+	Metric *Metric `json:"metric,omitempty"`
+	// Threshold is the percentage the metric may degrade from the baseline value, defaults to 10
+	Threshold *int32 `json:"threshold,omitempty"`
+	// Period is how long the degradation must be sustained before a new run is started, defaults to 1h
+	Period *metav1.Duration `json:"period,omitempty"`
+	// Interval is how often the metric is evaluated, defaults to 5m
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// WarmStart seeds the experiment started when performance degrades with the results of the previous run, defaults
+	// to true regardless of the schedule's warm start setting
+	WarmStart *bool `json:"warmStart,omitempty"`
+}
+
+// ExperimentScheduleSpec defines the desired state of ExperimentSchedule
+type ExperimentScheduleSpec struct {
+	// Schedule in Cron format, e.g. "0 3 * * 0" or "@weekly"; when empty a single run is started immediately and
+	// subsequent runs are only started by drift detection
+	Schedule string `json:"schedule,omitempty"`
+	// Suspend prevents subsequent runs from being scheduled, it does not apply to already started runs
+	Suspend *bool `json:"suspend,omitempty"`
+	// ConcurrencyPolicy specifies how to treat concurrent runs, one of: Allow|Forbid|Replace, default: Forbid
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// WarmStart seeds each new experiment with the results of the previous run
+	WarmStart bool `json:"warmStart,omitempty"`
+	// ExperimentsHistoryLimit is the number of completed experiments to retain, defaults to 3
+	ExperimentsHistoryLimit *int32 `json:"experimentsHistoryLimit,omitempty"`
+	// DriftDetection enables continuous optimization by starting new runs when performance degrades
+	DriftDetection *DriftDetection `json:"driftDetection,omitempty"`
+	// ExperimentTemplate for creating a new experiment on each run
+	ExperimentTemplate ExperimentTemplateSpec `json:"experimentTemplate"`
+}
+
+// ExperimentScheduleStatus defines the observed state of ExperimentSchedule
+type ExperimentScheduleStatus struct {
+	// Active is the list of experiments which have not completed
+	Active []corev1.ObjectReference `json:"active,omitempty"`
+	// LastScheduleTime is the last time an experiment was successfully scheduled
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// Drift is the observed state of drift detection
+	Drift *DriftStatus `json:"drift,omitempty"`
+}
+
+// DriftStatus is the observed state of drift detection for the most recently completed experiment
+type DriftStatus struct {
+	// Baseline is the first metric value observed after the experiment completed
+	Baseline string `json:"baseline,omitempty"`
+	// LastValue is the most recently observed metric value
+	LastValue string `json:"lastValue,omitempty"`
+	// LastProbeTime is the last time the metric was evaluated
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
+	// DegradedSince is the time the metric first exceeded the threshold, it is cleared when the metric recovers
+	DegradedSince *metav1.Time `json:"degradedSince,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+
+// ExperimentSchedule is the Schema for the experimentschedules API
+// +kubebuilder:printcolumn:name="Schedule",type="string",JSONPath=".spec.schedule",description="Cron schedule"
+// +kubebuilder:printcolumn:name="Last Schedule",type="date",JSONPath=".status.lastScheduleTime",description="Last scheduled run"
+type ExperimentSchedule struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object metadata
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the desired behavior for an experiment schedule
+	Spec ExperimentScheduleSpec `json:"spec,omitempty"`
+	// Current status of an experiment schedule
+	Status ExperimentScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ExperimentScheduleList contains a list of ExperimentSchedule
+type ExperimentScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// The list of experiment schedules
+	Items []ExperimentSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ExperimentSchedule{}, &ExperimentScheduleList{})
+}
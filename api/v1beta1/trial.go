@@ -17,10 +17,15 @@ limitations under the License.
 package v1beta1
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // ExperimentNamespacedName returns the namespaced name of the experiment for this trial
@@ -46,13 +51,13 @@ func (in *Trial) HasInitializer() bool {
 }
 
 // GetAssignment returns an assignment value by name
-func (in *Trial) GetAssignment(name string) (int64, bool) {
+func (in *Trial) GetAssignment(name string) (AssignmentValue, bool) {
 	for i := range in.Spec.Assignments {
 		if in.Spec.Assignments[i].Name == name {
 			return in.Spec.Assignments[i].Value, true
 		}
 	}
-	return 0, false
+	return AssignmentValue{}, false
 }
 
 // GetJobSelector returns the job selector
@@ -72,3 +77,77 @@ func (in *Trial) GetJobSelector() *metav1.LabelSelector {
 		},
 	}
 }
+
+// NewAssignmentValue returns the value of a numeric assignment
+func NewAssignmentValue(v int64) AssignmentValue {
+	return AssignmentValue{IntOrString: fromInt64(v)}
+}
+
+// NewCategoricalAssignmentValue returns the value of a categorical assignment
+func NewCategoricalAssignmentValue(v string) AssignmentValue {
+	return AssignmentValue{IntOrString: intstr.FromString(v)}
+}
+
+// Int64Value returns the numeric value of the assignment
+func (in *Assignment) Int64Value() (int64, bool) {
+	return in.Value.Int64Value()
+}
+
+// Int64Value returns the numeric value, false if the value is categorical
+func (in AssignmentValue) Int64Value() (int64, bool) {
+	if in.Type != intstr.Int {
+		return 0, false
+	}
+	return int64Value(in.IntOrString)
+}
+
+// String returns the string representation of the value
+func (in AssignmentValue) String() string {
+	if in.Type == intstr.String || in.StrVal != "" {
+		return in.StrVal
+	}
+	return strconv.FormatInt(int64(in.IntVal), 10)
+}
+
+// MarshalJSON writes the value as a JSON number or string
+func (in AssignmentValue) MarshalJSON() ([]byte, error) {
+	if in.Type == intstr.String {
+		return json.Marshal(in.StrVal)
+	}
+	v, ok := in.Int64Value()
+	if !ok {
+		return nil, fmt.Errorf("invalid numeric assignment value %q", in.StrVal)
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON reads the value from a JSON number or string
+func (in *AssignmentValue) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*in = AssignmentValue{IntOrString: intstr.IntOrString{Type: intstr.String}}
+		return json.Unmarshal(data, &in.StrVal)
+	}
+	var v int64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*in = NewAssignmentValue(v)
+	return nil
+}
+
+// fromInt64 returns an integer IntOrString, values which do not fit in 32 bits are stored using their decimal string
+func fromInt64(v int64) intstr.IntOrString {
+	if v >= math.MinInt32 && v <= math.MaxInt32 {
+		return intstr.FromInt(int(v))
+	}
+	return intstr.IntOrString{Type: intstr.Int, StrVal: strconv.FormatInt(v, 10)}
+}
+
+// int64Value returns the value of an integer IntOrString created using fromInt64
+func int64Value(v intstr.IntOrString) (int64, bool) {
+	if v.StrVal == "" {
+		return int64(v.IntVal), true
+	}
+	i, err := strconv.ParseInt(v.StrVal, 10, 64)
+	return i, err == nil
+}
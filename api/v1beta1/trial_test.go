@@ -0,0 +1,90 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignmentValue_JSON(t *testing.T) {
+	cases := []struct {
+		desc     string
+		value    AssignmentValue
+		expected string
+	}{
+		{
+			desc:     "integer",
+			value:    NewAssignmentValue(5),
+			expected: `5`,
+		},
+		{
+			desc:     "64-bit integer",
+			value:    NewAssignmentValue(1 << 40),
+			expected: `1099511627776`,
+		},
+		{
+			desc:     "negative integer",
+			value:    NewAssignmentValue(-3),
+			expected: `-3`,
+		},
+		{
+			desc:     "categorical",
+			value:    NewCategoricalAssignmentValue("fast"),
+			expected: `"fast"`,
+		},
+		{
+			desc:     "numeric categorical",
+			value:    NewCategoricalAssignmentValue("100"),
+			expected: `"100"`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			b, err := json.Marshal(c.value)
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, string(b))
+			}
+
+			var actual AssignmentValue
+			if assert.NoError(t, json.Unmarshal(b, &actual)) {
+				assert.Equal(t, c.value, actual)
+			}
+		})
+	}
+}
+
+func TestAssignmentValue_UnmarshalInvalid(t *testing.T) {
+	var v AssignmentValue
+	assert.Error(t, json.Unmarshal([]byte(`1.5`), &v))
+	assert.Error(t, json.Unmarshal([]byte(`{}`), &v))
+}
+
+func TestAssignmentValue_Int64Value(t *testing.T) {
+	v, ok := NewAssignmentValue(1 << 40).Int64Value()
+	assert.True(t, ok)
+	assert.Equal(t, int64(1<<40), v)
+
+	v, ok = NewAssignmentValue(-3).Int64Value()
+	assert.True(t, ok)
+	assert.Equal(t, int64(-3), v)
+
+	_, ok = NewCategoricalAssignmentValue("100").Int64Value()
+	assert.False(t, ok)
+}
@@ -30,8 +30,16 @@ import (
 type Assignment struct {
 	// Name of the parameter being assigned
 	Name string `json:"name"`
-	// Value of the assignment
-	Value int64 `json:"value"`
+	// Value of the assignment, categorical parameters are assigned string values
+	Value AssignmentValue `json:"value"`
+}
+
+// AssignmentValue holds either an integer or a string, it is serialized as a JSON number or string respectively. The
+// value embeds an IntOrString so the generated schema accepts either form; numeric values which do not fit in 32 bits
+// keep their decimal representation in StrVal so they are not truncated, use Int64Value to read them
+// +kubebuilder:validation:Type=""
+type AssignmentValue struct {
+	intstr.IntOrString `json:",inline"`
 }
 
 // TrialReadinessGate represents a readiness check on one or more objects that must pass after patches
@@ -58,6 +66,38 @@ type TrialReadinessGate struct {
 	FailureThreshold int32 `json:"failureThreshold,omitempty"`
 }
 
+// TrialCompletionPolicy determines the outcome of a trial run beyond the success of the trial run job, useful for
+// harnesses that always exit with a zero exit code
+type TrialCompletionPolicy struct {
+	// ExitCodes maps trial run container exit codes to failure reasons
+	ExitCodes []ExitCodeFailure `json:"exitCodes,omitempty"`
+	// Container is the name of the container whose logs are searched for markers, defaults to the first container
+	Container string `json:"container,omitempty"`
+	// SuccessMarker is text that must appear on a line of the trial run logs, if it is missing the trial is failed
+	SuccessMarker string `json:"successMarker,omitempty"`
+	// FailureMarker is text that, if it appears on a line of the trial run logs, causes the trial to be failed
+	FailureMarker string `json:"failureMarker,omitempty"`
+}
+
+// TrialWarmup is a stage at the beginning of the trial run that is excluded from the measurement, e.g. to allow JIT
+// compilation or caches to warm up
+type TrialWarmup struct {
+	// Duration is the minimum amount of time the trial run spends warming up
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// ReadinessProbe is added to the trial run containers, the warm-up is not over until every trial run pod is ready
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+}
+
+// ExitCodeFailure maps a container exit code to a trial failure
+type ExitCodeFailure struct {
+	// ExitCode is the container exit code that indicates a failure
+	ExitCode int32 `json:"exitCode"`
+	// Reason is the reason code recorded on the failed trial condition
+	Reason string `json:"reason"`
+	// Message is the human readable message recorded on the failed trial condition
+	Message string `json:"message,omitempty"`
+}
+
 // HelmValue represents a value in a Helm template
 type HelmValue struct {
 	// The name of Helm value as passed to one of the set options
@@ -129,7 +169,9 @@ type PatchOperation struct {
 	// The patch content type, must be a type supported by the Kubernetes API server
 	PatchType types.PatchType `json:"patchType"`
 	// The raw data representing the patch to be applied
-	Data []byte `json:"data"`
+	Data []byte `json:"data,omitempty"`
+	// Reference to a config map key holding the raw data once the patch has been compacted out of the trial
+	DataRef *corev1.ConfigMapKeySelector `json:"dataRef,omitempty"`
 	// The number of remaining attempts to apply the patch, will be automatically set
 	// to zero if the patch is successfully applied
 	AttemptsRemaining int `json:"attemptsRemaining,omitempty"`
@@ -156,6 +198,19 @@ type ReadinessCheck struct {
 	AttemptsRemaining int32 `json:"attemptsRemaining,omitempty"`
 	// LastCheckTime is the timestamp of the last evaluation attempt
 	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// Expressions are evaluated against the target object, all expressions must produce the expected value
+	Expressions []ReadinessExpression `json:"expressions,omitempty"`
+}
+
+// ReadinessExpression is evaluated against the state of an object to determine readiness
+type ReadinessExpression struct {
+	// JSONPath is a JSONPath template evaluated against the object, e.g. "{.status.phase}"
+	JSONPath string `json:"jsonPath,omitempty"`
+	// Value is the expected result of the expression, if empty any result other then "" or "false" is considered ready
+	Value string `json:"value,omitempty"`
+	// CEL is a Common Expression Language expression evaluated against the object (available as "self"), the
+	// expression must evaluate to true
+	CEL string `json:"cel,omitempty"`
 }
 
 // Value represents an observed metric value after a trial run has completed successfully. Value names
@@ -172,6 +227,20 @@ type Value struct {
 	AttemptsRemaining int `json:"attemptsRemaining,omitempty"`
 }
 
+// MetricCollection tracks the progress of collecting a single metric value
+type MetricCollection struct {
+	// The metric name the collection progress corresponds to
+	Name string `json:"name"`
+	// The number of failed attempts to collect the metric
+	Attempts int `json:"attempts,omitempty"`
+	// The last time collection of the metric was attempted
+	LastAttemptTime *metav1.Time `json:"lastAttemptTime,omitempty"`
+	// The earliest time collection of the metric should be attempted again
+	NextAttemptTime *metav1.Time `json:"nextAttemptTime,omitempty"`
+	// A human readable message indicating why the last attempt failed
+	Message string `json:"message,omitempty"`
+}
+
 // TrialConditionType represents the possible observable conditions for a trial
 type TrialConditionType string
 
@@ -184,6 +253,8 @@ const (
 	TrialSetupCreated TrialConditionType = "redskyops.dev/trial-setup-created"
 	// TrialSetupDeleted is a condition that indicates all "delete" setup tasks have finished
 	TrialSetupDeleted TrialConditionType = "redskyops.dev/trial-setup-deleted"
+	// TrialReported is a condition that indicates the trial values have been accepted by the server
+	TrialReported TrialConditionType = "redskyops.dev/trial-reported"
 	// TrialPatched is a condition that indicates patches have been applied for a trial
 	TrialPatched TrialConditionType = "redskyops.dev/trial-patched"
 	// TrialReady is a condition that indicates the application is ready after patches were applied
@@ -219,18 +290,30 @@ type TrialSpec struct {
 	Selector *metav1.LabelSelector `json:"selector,omitempty"`
 	// JobTemplate is the job template used to create trial run jobs
 	JobTemplate *batchv1beta1.JobTemplateSpec `json:"jobTemplate,omitempty"`
+	// JobTemplateRef is a reference to a CronJob whose job template is used to create the trial run job, this allows
+	// the (patched) CronJob itself to be triggered for measurement; takes precedence over the job template
+	JobTemplateRef *corev1.ObjectReference `json:"jobTemplateRef,omitempty"`
+	// PodTemplateOverlay is a strategic merge patch (YAML or JSON) applied to the pod template of the trial run and
+	// setup jobs, defaults to the overlay of the experiment
+	PodTemplateOverlay string `json:"podTemplateOverlay,omitempty"`
 	// InitialDelaySeconds is number of seconds to wait after a trial becomes ready before starting the trial run job
 	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
 	// The offset used to adjust the start time to account for spin up of the trial run
 	StartTimeOffset *metav1.Duration `json:"startTimeOffset,omitempty"`
 	// The approximate amount of time the trial run should execute (not inclusive of the start time offset)
 	ApproximateRuntime *metav1.Duration `json:"approximateRuntime,omitempty"`
+	// Warmup excludes the beginning of the trial run from the measurement
+	Warmup *TrialWarmup `json:"warmup,omitempty"`
+	// CompletionPolicy determines how the success of the trial run is evaluated beyond the success of the job
+	CompletionPolicy *TrialCompletionPolicy `json:"completionPolicy,omitempty"`
 	// The minimum number of seconds before an attempt should be made to clean up the trial, if unset or negative no attempt is made to clean up the trial
 	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
 	// The minimum number of seconds before an attempt should be made to clean up a failed trial, defaults to TTLSecondsAfterFinished
 	TTLSecondsAfterFailure *int32 `json:"ttlSecondsAfterFailure,omitempty"`
 	// The readiness gates to check before running the trial job
 	ReadinessGates []TrialReadinessGate `json:"readinessGates,omitempty"`
+	// Abort indicates the trial should be stopped, the trial run job is deleted and the trial is marked as failed
+	Abort bool `json:"abort,omitempty"`
 
 	// Values are the collected metrics at the end of the trial run
 	Values []Value `json:"values,omitempty"`
@@ -255,16 +338,28 @@ type TrialStatus struct {
 	Assignments string `json:"assignments"`
 	// Values is a string representation of the trial values for reporting purposes
 	Values string `json:"values"`
-	// StartTime is the effective (possibly adjusted) time the trial run job started
+	// WarmupStartTime is the time the trial run job started when the trial run begins with a warm-up
+	WarmupStartTime *metav1.Time `json:"warmupStartTime,omitempty"`
+	// StartTime is the effective (possibly adjusted) time the trial run job started, this is the end of the warm-up
 	StartTime *metav1.Time `json:"startTime,omitempty"`
 	// CompletionTime is the effective (possibly adjusted) time the trial run job completed
 	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
 	// Condition is the current state of the trial
 	Conditions []TrialCondition `json:"conditions,omitempty"`
+	// MetricCollection is the progress of collecting each of the trial values
+	MetricCollection []MetricCollection `json:"metricCollection,omitempty"`
 	// PatchOperations are the patches from the experiment evaluated in the context of this trial
 	PatchOperations []PatchOperation `json:"patchOperations,omitempty"`
 	// ReadinessChecks are the all of the objects whose conditions need to be inspected for this trial
 	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
+	// ResolvedAssignments are the concrete values used in place of the assignments when the patches were evaluated,
+	// e.g. percentage parameters resolved against their referenced values or values changed by an assignment webhook
+	ResolvedAssignments []Assignment `json:"resolvedAssignments,omitempty"`
+	// ResourceHours is the amount of each resource requested by the trial workloads multiplied by the number of hours
+	// the trial ran, only recorded when the experiment has a cost model
+	ResourceHours corev1.ResourceList `json:"resourceHours,omitempty"`
+	// Cost is the cost of the resource hours according to the weights of the experiment cost model
+	Cost string `json:"cost,omitempty"`
 }
 
 // +genclient
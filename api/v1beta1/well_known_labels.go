@@ -25,9 +25,41 @@ const (
 	AnnotationNextTrialURL = "redskyops.dev/next-trial-url"
 	// AnnotationReportTrialURL is the URL used to report trial observations
 	AnnotationReportTrialURL = "redskyops.dev/report-trial-url"
+	// AnnotationWarmStartFrom is the URL of a previous experiment whose results should seed the optimizer
+	AnnotationWarmStartFrom = "redskyops.dev/warm-start-from"
+	// AnnotationHashSensitiveParameters indicates the names of sensitive parameters should be hashed before they are
+	// sent to the remote server
+	AnnotationHashSensitiveParameters = "redskyops.dev/hash-sensitive-parameters"
+	// AnnotationDataResidency controls how metric values are reported to the remote server, when set to "relative" only
+	// values relative to an in-cluster baseline are reported and raw values never leave the cluster
+	AnnotationDataResidency = "redskyops.dev/data-residency"
+	// AnnotationMetricBaseline is a comma-delimited list of "name=value" pairs used as the in-cluster baseline for
+	// relative metric values
+	AnnotationMetricBaseline = "redskyops.dev/metric-baseline"
+	// AnnotationOptimizationChecksum is a checksum of the optimization configuration last sent to the remote server
+	AnnotationOptimizationChecksum = "redskyops.dev/optimization-checksum"
+	// AnnotationSidecarPolicy controls how service mesh sidecars (Istio or Linkerd) are handled in trial run jobs, one
+	// of "disable-injection" to prevent the sidecars from being injected or "shutdown" to stop the sidecars once the
+	// trial run containers exit; it is copied from the experiment to each trial
+	AnnotationSidecarPolicy = "redskyops.dev/sidecar-policy"
+	// AnnotationScaleUpTimeout is the amount of time (e.g. "10m") unschedulable pods are given for a cluster autoscaler
+	// (such as Karpenter or the cluster-autoscaler) to provision new nodes before the trial fails; it is copied from
+	// the experiment to each trial
+	AnnotationScaleUpTimeout = "redskyops.dev/scale-up-timeout"
+	// AnnotationViewPrefix is the prefix of annotations holding JSON encoded trial views (saved filter, sort and output
+	// combinations) shared with everyone working on the experiment; the view name follows the prefix
+	AnnotationViewPrefix = "views.redskyops.dev/"
+	// AnnotationReferenceBaseline is a JSON object of the values referenced by percentage parameters, keyed by parameter
+	// name; the values are recorded before the first trial is patched so every trial is relative to the same baseline
+	AnnotationReferenceBaseline = "redskyops.dev/reference-baseline"
+	// AnnotationPublishedEvents is a comma-delimited list of the lifecycle event types already published for an
+	// experiment or trial, the annotation is added the first time the object is observed by the event publisher
+	AnnotationPublishedEvents = "redskyops.dev/published-events"
 
 	// LabelExperiment is the name of the experiment associated with an object
 	LabelExperiment = "redskyops.dev/experiment"
+	// LabelExperimentSchedule is the name of the experiment schedule which created an experiment
+	LabelExperimentSchedule = "redskyops.dev/experiment-schedule"
 )
 
 // Trial labels and annotations
@@ -36,6 +68,19 @@ const (
 	// AnnotationInitializer is a comma-delimited list of initializing processes. Similar to a "finalizer", the trial
 	// will not start executing until the initializer is empty.
 	AnnotationInitializer = "redskyops.dev/initializer"
+	// AnnotationTrialPriority is the priority of a trial which has not started, pending trials with a higher priority
+	// are started first
+	AnnotationTrialPriority = "redskyops.dev/trial-priority"
+	// AnnotationSensitiveParameters is a comma-delimited list of parameter names whose values should be redacted
+	AnnotationSensitiveParameters = "redskyops.dev/sensitive-parameters"
+	// AnnotationLabelTrialURL is the URL used to label the trial on the remote server
+	AnnotationLabelTrialURL = "redskyops.dev/label-trial-url"
+	// AnnotationSyncedLabels is a comma-delimited list of "name=value" pairs of trial labels last sent to the remote
+	// server, used to detect labels which must be added or removed
+	AnnotationSyncedLabels = "redskyops.dev/synced-labels"
+	// AnnotationSuspendedBy is the name of the trial which suspended a CronJob while using its job template for the
+	// trial run, the CronJob is resumed once that trial run is over
+	AnnotationSuspendedBy = "redskyops.dev/suspended-by"
 
 	// LabelTrial contains the name of the trial associated with an object
 	LabelTrial = "redskyops.dev/trial"
@@ -31,6 +31,7 @@ import (
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Assignment) DeepCopyInto(out *Assignment) {
 	*out = *in
+	out.Value = in.Value
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Assignment.
@@ -43,6 +44,58 @@ func (in *Assignment) DeepCopy() *Assignment {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssignmentValue) DeepCopyInto(out *AssignmentValue) {
+	*out = *in
+	out.IntOrString = in.IntOrString
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssignmentValue.
+func (in *AssignmentValue) DeepCopy() *AssignmentValue {
+	if in == nil {
+		return nil
+	}
+	out := new(AssignmentValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssignmentWebhook) DeepCopyInto(out *AssignmentWebhook) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssignmentWebhook.
+func (in *AssignmentWebhook) DeepCopy() *AssignmentWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(AssignmentWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogEntry) DeepCopyInto(out *CatalogEntry) {
+	*out = *in
+	out.Capacity = in.Capacity.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogEntry.
+func (in *CatalogEntry) DeepCopy() *CatalogEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigMapHelmValuesFromSource) DeepCopyInto(out *ConfigMapHelmValuesFromSource) {
 	*out = *in
@@ -84,13 +137,127 @@ func (in *Constraint) DeepCopy() *Constraint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConvergenceCriteria) DeepCopyInto(out *ConvergenceCriteria) {
+	*out = *in
+	out.Tolerance = in.Tolerance.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConvergenceCriteria.
+func (in *ConvergenceCriteria) DeepCopy() *ConvergenceCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(ConvergenceCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CostModel) DeepCopyInto(out *CostModel) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CostModel.
+func (in *CostModel) DeepCopy() *CostModel {
+	if in == nil {
+		return nil
+	}
+	out := new(CostModel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetection) DeepCopyInto(out *DriftDetection) {
+	*out = *in
+	if in.Metric != nil {
+		in, out := &in.Metric, &out.Metric
+		*out = new(Metric)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Threshold != nil {
+		in, out := &in.Threshold, &out.Threshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.WarmStart != nil {
+		in, out := &in.WarmStart, &out.WarmStart
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetection.
+func (in *DriftDetection) DeepCopy() *DriftDetection {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetection)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftStatus) DeepCopyInto(out *DriftStatus) {
+	*out = *in
+	if in.LastProbeTime != nil {
+		in, out := &in.LastProbeTime, &out.LastProbeTime
+		*out = (*in).DeepCopy()
+	}
+	if in.DegradedSince != nil {
+		in, out := &in.DegradedSince, &out.DegradedSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftStatus.
+func (in *DriftStatus) DeepCopy() *DriftStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExitCodeFailure) DeepCopyInto(out *ExitCodeFailure) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExitCodeFailure.
+func (in *ExitCodeFailure) DeepCopy() *ExitCodeFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(ExitCodeFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Experiment) DeepCopyInto(out *Experiment) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Experiment.
@@ -111,6 +278,23 @@ func (in *Experiment) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExperimentCondition) DeepCopyInto(out *ExperimentCondition) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentCondition.
+func (in *ExperimentCondition) DeepCopy() *ExperimentCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ExperimentCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExperimentList) DeepCopyInto(out *ExperimentList) {
 	*out = *in
@@ -143,6 +327,125 @@ func (in *ExperimentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExperimentSchedule) DeepCopyInto(out *ExperimentSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentSchedule.
+func (in *ExperimentSchedule) DeepCopy() *ExperimentSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ExperimentSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExperimentSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExperimentScheduleList) DeepCopyInto(out *ExperimentScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ExperimentSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentScheduleList.
+func (in *ExperimentScheduleList) DeepCopy() *ExperimentScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ExperimentScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExperimentScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExperimentScheduleSpec) DeepCopyInto(out *ExperimentScheduleSpec) {
+	*out = *in
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExperimentsHistoryLimit != nil {
+		in, out := &in.ExperimentsHistoryLimit, &out.ExperimentsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetection)
+		(*in).DeepCopyInto(*out)
+	}
+	in.ExperimentTemplate.DeepCopyInto(&out.ExperimentTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentScheduleSpec.
+func (in *ExperimentScheduleSpec) DeepCopy() *ExperimentScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExperimentScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExperimentScheduleStatus) DeepCopyInto(out *ExperimentScheduleStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]corev1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(DriftStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentScheduleStatus.
+func (in *ExperimentScheduleStatus) DeepCopy() *ExperimentScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExperimentScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExperimentSpec) DeepCopyInto(out *ExperimentSpec) {
 	*out = *in
@@ -159,7 +462,9 @@ func (in *ExperimentSpec) DeepCopyInto(out *ExperimentSpec) {
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
 		*out = make([]Parameter, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Constraints != nil {
 		in, out := &in.Constraints, &out.Constraints
@@ -175,6 +480,16 @@ func (in *ExperimentSpec) DeepCopyInto(out *ExperimentSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Convergence != nil {
+		in, out := &in.Convergence, &out.Convergence
+		*out = new(ConvergenceCriteria)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CostModel != nil {
+		in, out := &in.CostModel, &out.CostModel
+		*out = new(CostModel)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Patches != nil {
 		in, out := &in.Patches, &out.Patches
 		*out = make([]PatchTemplate, len(*in))
@@ -182,6 +497,11 @@ func (in *ExperimentSpec) DeepCopyInto(out *ExperimentSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AssignmentWebhook != nil {
+		in, out := &in.AssignmentWebhook, &out.AssignmentWebhook
+		*out = new(AssignmentWebhook)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.NamespaceSelector != nil {
 		in, out := &in.NamespaceSelector, &out.NamespaceSelector
 		*out = new(v1.LabelSelector)
@@ -213,6 +533,36 @@ func (in *ExperimentSpec) DeepCopy() *ExperimentSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExperimentStatus) DeepCopyInto(out *ExperimentStatus) {
 	*out = *in
+	if in.Queue != nil {
+		in, out := &in.Queue, &out.Queue
+		*out = make([]QueuedTrial, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ExperimentCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AverageTrialDuration != nil {
+		in, out := &in.AverageTrialDuration, &out.AverageTrialDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ResourceHours != nil {
+		in, out := &in.ResourceHours, &out.ResourceHours
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentStatus.
@@ -225,6 +575,23 @@ func (in *ExperimentStatus) DeepCopy() *ExperimentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExperimentTemplateSpec) DeepCopyInto(out *ExperimentTemplateSpec) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentTemplateSpec.
+func (in *ExperimentTemplateSpec) DeepCopy() *ExperimentTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ExperimentTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HelmValue) DeepCopyInto(out *HelmValue) {
 	*out = *in
@@ -289,6 +656,16 @@ func (in *HelmValuesFromSource) DeepCopy() *HelmValuesFromSource {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metric) DeepCopyInto(out *Metric) {
 	*out = *in
+	if in.Warmup != nil {
+		in, out := &in.Warmup, &out.Warmup
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	if in.Selector != nil {
 		in, out := &in.Selector, &out.Selector
 		*out = new(v1.LabelSelector)
@@ -307,6 +684,29 @@ func (in *Metric) DeepCopy() *Metric {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricCollection) DeepCopyInto(out *MetricCollection) {
+	*out = *in
+	if in.LastAttemptTime != nil {
+		in, out := &in.LastAttemptTime, &out.LastAttemptTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextAttemptTime != nil {
+		in, out := &in.NextAttemptTime, &out.NextAttemptTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricCollection.
+func (in *MetricCollection) DeepCopy() *MetricCollection {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricCollection)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NamespaceTemplateSpec) DeepCopyInto(out *NamespaceTemplateSpec) {
 	*out = *in
@@ -357,6 +757,25 @@ func (in *OrderConstraint) DeepCopy() *OrderConstraint {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Parameter) DeepCopyInto(out *Parameter) {
 	*out = *in
+	out.Min = in.Min
+	out.Max = in.Max
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Reference != nil {
+		in, out := &in.Reference, &out.Reference
+		*out = new(ParameterReference)
+		**out = **in
+	}
+	if in.Catalog != nil {
+		in, out := &in.Catalog, &out.Catalog
+		*out = make([]CatalogEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parameter.
@@ -369,6 +788,38 @@ func (in *Parameter) DeepCopy() *Parameter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterBound) DeepCopyInto(out *ParameterBound) {
+	*out = *in
+	out.IntOrString = in.IntOrString
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterBound.
+func (in *ParameterBound) DeepCopy() *ParameterBound {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterBound)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterReference) DeepCopyInto(out *ParameterReference) {
+	*out = *in
+	out.TargetRef = in.TargetRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterReference.
+func (in *ParameterReference) DeepCopy() *ParameterReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ParameterSelector) DeepCopyInto(out *ParameterSelector) {
 	*out = *in
@@ -393,6 +844,11 @@ func (in *PatchOperation) DeepCopyInto(out *PatchOperation) {
 		*out = make([]byte, len(*in))
 		copy(*out, *in)
 	}
+	if in.DataRef != nil {
+		in, out := &in.DataRef, &out.DataRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchOperation.
@@ -408,6 +864,7 @@ func (in *PatchOperation) DeepCopy() *PatchOperation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PatchReadinessGate) DeepCopyInto(out *PatchReadinessGate) {
 	*out = *in
+	out.ReadinessExpression = in.ReadinessExpression
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PatchReadinessGate.
@@ -428,6 +885,11 @@ func (in *PatchTemplate) DeepCopyInto(out *PatchTemplate) {
 		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(int32)
+		**out = **in
+	}
 	if in.ReadinessGates != nil {
 		in, out := &in.ReadinessGates, &out.ReadinessGates
 		*out = make([]PatchReadinessGate, len(*in))
@@ -445,6 +907,26 @@ func (in *PatchTemplate) DeepCopy() *PatchTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueuedTrial) DeepCopyInto(out *QueuedTrial) {
+	*out = *in
+	if in.Assignments != nil {
+		in, out := &in.Assignments, &out.Assignments
+		*out = make([]Assignment, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueuedTrial.
+func (in *QueuedTrial) DeepCopy() *QueuedTrial {
+	if in == nil {
+		return nil
+	}
+	out := new(QueuedTrial)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReadinessCheck) DeepCopyInto(out *ReadinessCheck) {
 	*out = *in
@@ -463,6 +945,11 @@ func (in *ReadinessCheck) DeepCopyInto(out *ReadinessCheck) {
 		in, out := &in.LastCheckTime, &out.LastCheckTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Expressions != nil {
+		in, out := &in.Expressions, &out.Expressions
+		*out = make([]ReadinessExpression, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessCheck.
@@ -475,6 +962,21 @@ func (in *ReadinessCheck) DeepCopy() *ReadinessCheck {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessExpression) DeepCopyInto(out *ReadinessExpression) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessExpression.
+func (in *ReadinessExpression) DeepCopy() *ReadinessExpression {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessExpression)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SetupTask) DeepCopyInto(out *SetupTask) {
 	*out = *in
@@ -577,6 +1079,26 @@ func (in *Trial) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrialCompletionPolicy) DeepCopyInto(out *TrialCompletionPolicy) {
+	*out = *in
+	if in.ExitCodes != nil {
+		in, out := &in.ExitCodes, &out.ExitCodes
+		*out = make([]ExitCodeFailure, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrialCompletionPolicy.
+func (in *TrialCompletionPolicy) DeepCopy() *TrialCompletionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(TrialCompletionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TrialCondition) DeepCopyInto(out *TrialCondition) {
 	*out = *in
@@ -674,6 +1196,11 @@ func (in *TrialSpec) DeepCopyInto(out *TrialSpec) {
 		*out = new(batchv1beta1.JobTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.JobTemplateRef != nil {
+		in, out := &in.JobTemplateRef, &out.JobTemplateRef
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
 	if in.StartTimeOffset != nil {
 		in, out := &in.StartTimeOffset, &out.StartTimeOffset
 		*out = new(v1.Duration)
@@ -684,6 +1211,16 @@ func (in *TrialSpec) DeepCopyInto(out *TrialSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.Warmup != nil {
+		in, out := &in.Warmup, &out.Warmup
+		*out = new(TrialWarmup)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CompletionPolicy != nil {
+		in, out := &in.CompletionPolicy, &out.CompletionPolicy
+		*out = new(TrialCompletionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TTLSecondsAfterFinished != nil {
 		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
 		*out = new(int32)
@@ -742,6 +1279,10 @@ func (in *TrialSpec) DeepCopy() *TrialSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TrialStatus) DeepCopyInto(out *TrialStatus) {
 	*out = *in
+	if in.WarmupStartTime != nil {
+		in, out := &in.WarmupStartTime, &out.WarmupStartTime
+		*out = (*in).DeepCopy()
+	}
 	if in.StartTime != nil {
 		in, out := &in.StartTime, &out.StartTime
 		*out = (*in).DeepCopy()
@@ -757,6 +1298,13 @@ func (in *TrialStatus) DeepCopyInto(out *TrialStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.MetricCollection != nil {
+		in, out := &in.MetricCollection, &out.MetricCollection
+		*out = make([]MetricCollection, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.PatchOperations != nil {
 		in, out := &in.PatchOperations, &out.PatchOperations
 		*out = make([]PatchOperation, len(*in))
@@ -771,6 +1319,18 @@ func (in *TrialStatus) DeepCopyInto(out *TrialStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ResolvedAssignments != nil {
+		in, out := &in.ResolvedAssignments, &out.ResolvedAssignments
+		*out = make([]Assignment, len(*in))
+		copy(*out, *in)
+	}
+	if in.ResourceHours != nil {
+		in, out := &in.ResourceHours, &out.ResourceHours
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrialStatus.
@@ -800,6 +1360,31 @@ func (in *TrialTemplateSpec) DeepCopy() *TrialTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrialWarmup) DeepCopyInto(out *TrialWarmup) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ReadinessProbe != nil {
+		in, out := &in.ReadinessProbe, &out.ReadinessProbe
+		*out = new(corev1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrialWarmup.
+func (in *TrialWarmup) DeepCopy() *TrialWarmup {
+	if in == nil {
+		return nil
+	}
+	out := new(TrialWarmup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Value) DeepCopyInto(out *Value) {
 	*out = *in
@@ -0,0 +1,239 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Bundle produces an Operator Lifecycle Manager (OLM) bundle from the existing
+// Kustomize manifests. The cluster service version (CSV) is assembled from the
+// deployment and RBAC resources, the CRDs are copied into the bundle as-is.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"sigs.k8s.io/kustomize/api/filesys"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	packageName        = "redskyops"
+	serviceAccountName = "redsky-controller-manager"
+)
+
+func main() {
+	var (
+		path           = flag.String("path", "config/olm", "path of kustomization.yaml")
+		output         = flag.String("output", "bundle", "output directory")
+		version        = flag.String("version", "0.0.0", "bundle version (semver, without the leading 'v')")
+		image          = flag.String("image", "", "controller image, used for the CSV container image annotation")
+		replaces       = flag.String("replaces", "", "version of the bundle being replaced by this bundle")
+		channels       = flag.String("channels", "stable", "comma separated list of channels the bundle belongs to")
+		defaultChannel = flag.String("default-channel", "stable", "default channel of the package")
+	)
+
+	flag.Parse()
+
+	// Run `kustomize build` against path
+	k := krusty.MakeKustomizer(filesys.MakeFsOnDisk(), krusty.MakeDefaultOptions())
+	resources, err := k.Run(*path)
+	if err != nil {
+		log.Fatal("failed to run kustomize build:", err)
+	}
+	yamls, err := resources.AsYaml()
+	if err != nil {
+		log.Fatal("failed to get kustomize yamls:", err)
+	}
+
+	csv := newClusterServiceVersion(*version, *image, *replaces)
+	manifests := filepath.Join(*output, "manifests")
+	if err := os.MkdirAll(manifests, 0755); err != nil {
+		log.Fatal("failed to create manifests directory:", err)
+	}
+
+	// Sort the resources into the CSV or directly into the manifests
+	for _, doc := range bytes.Split(yamls, []byte("\n---\n")) {
+		obj := make(map[string]interface{})
+		if err := yaml.Unmarshal(doc, &obj); err != nil {
+			log.Fatal("failed to parse resource:", err)
+		}
+		kind, _ := obj["kind"].(string)
+		name, _ := field(obj, "metadata")["name"].(string)
+
+		switch kind {
+		case "CustomResourceDefinition":
+			csv.addOwnedCRD(obj)
+			if err := writeYAML(filepath.Join(manifests, name+".crd.yaml"), obj); err != nil {
+				log.Fatal("failed to write CRD:", err)
+			}
+		case "Deployment":
+			csv.addDeployment(name, obj)
+		case "ClusterRole":
+			csv.addPermissions("clusterPermissions", obj)
+		case "Role":
+			csv.addPermissions("permissions", obj)
+		case "Namespace", "ClusterRoleBinding", "RoleBinding", "ServiceAccount":
+			// OLM manages these itself
+		default:
+			if err := writeYAML(filepath.Join(manifests, strings.ToLower(kind)+"-"+name+".yaml"), obj); err != nil {
+				log.Fatal("failed to write resource:", err)
+			}
+		}
+	}
+
+	if err := writeYAML(filepath.Join(manifests, packageName+".clusterserviceversion.yaml"), csv.obj); err != nil {
+		log.Fatal("failed to write CSV:", err)
+	}
+
+	// Write the bundle metadata
+	annotations := map[string]interface{}{
+		"annotations": map[string]interface{}{
+			"operators.operatorframework.io.bundle.mediatype.v1":       "registry+v1",
+			"operators.operatorframework.io.bundle.manifests.v1":       "manifests/",
+			"operators.operatorframework.io.bundle.metadata.v1":        "metadata/",
+			"operators.operatorframework.io.bundle.package.v1":         packageName,
+			"operators.operatorframework.io.bundle.channels.v1":        *channels,
+			"operators.operatorframework.io.bundle.channel.default.v1": *defaultChannel,
+		},
+	}
+	if err := os.MkdirAll(filepath.Join(*output, "metadata"), 0755); err != nil {
+		log.Fatal("failed to create metadata directory:", err)
+	}
+	if err := writeYAML(filepath.Join(*output, "metadata", "annotations.yaml"), annotations); err != nil {
+		log.Fatal("failed to write annotations:", err)
+	}
+}
+
+// clusterServiceVersion is an unstructured CSV
+type clusterServiceVersion struct {
+	obj map[string]interface{}
+}
+
+func newClusterServiceVersion(version, image, replaces string) *clusterServiceVersion {
+	spec := map[string]interface{}{
+		"displayName": "Red Sky Ops",
+		"description": "Red Sky Ops automates the tuning of application configuration and resources through machine learning driven experiments.",
+		"version":     version,
+		"maturity":    "stable",
+		"provider":    map[string]interface{}{"name": "GramLabs, Inc."},
+		"links":       []interface{}{map[string]interface{}{"name": "Red Sky Ops", "url": "https://redskyops.dev/"}},
+		"keywords":    []interface{}{"optimization", "tuning", "machine learning"},
+		"installModes": []interface{}{
+			map[string]interface{}{"type": "OwnNamespace", "supported": true},
+			map[string]interface{}{"type": "SingleNamespace", "supported": true},
+			map[string]interface{}{"type": "MultiNamespace", "supported": false},
+			map[string]interface{}{"type": "AllNamespaces", "supported": true},
+		},
+		"install": map[string]interface{}{
+			"strategy": "deployment",
+			"spec":     map[string]interface{}{},
+		},
+		"customresourcedefinitions": map[string]interface{}{
+			"owned": []interface{}{},
+		},
+	}
+	if replaces != "" {
+		spec["replaces"] = fmt.Sprintf("%s.v%s", packageName, replaces)
+	}
+
+	return &clusterServiceVersion{obj: map[string]interface{}{
+		"apiVersion": "operators.coreos.com/v1alpha1",
+		"kind":       "ClusterServiceVersion",
+		"metadata": map[string]interface{}{
+			"name": fmt.Sprintf("%s.v%s", packageName, version),
+			"annotations": map[string]interface{}{
+				"capabilities":   "Basic Install",
+				"categories":     "Developer Tools",
+				"containerImage": image,
+				"repository":     "https://github.com/redskyops/redskyops-controller",
+			},
+		},
+		"spec": spec,
+	}}
+}
+
+func (c *clusterServiceVersion) installSpec() map[string]interface{} {
+	return field(field(field(c.obj, "spec"), "install"), "spec")
+}
+
+func (c *clusterServiceVersion) addOwnedCRD(crd map[string]interface{}) {
+	spec := field(crd, "spec")
+	names := field(spec, "names")
+	group, _ := spec["group"].(string)
+	kind, _ := names["kind"].(string)
+
+	owned := field(field(c.obj, "spec"), "customresourcedefinitions")
+	list, _ := owned["owned"].([]interface{})
+
+	// Add an entry for each served version
+	versions, _ := spec["versions"].([]interface{})
+	for _, v := range versions {
+		vm, _ := v.(map[string]interface{})
+		if served, _ := vm["served"].(bool); !served {
+			continue
+		}
+		list = append(list, map[string]interface{}{
+			"name":        field(crd, "metadata")["name"],
+			"version":     vm["name"],
+			"kind":        kind,
+			"displayName": kind,
+			"description": fmt.Sprintf("%s (%s)", kind, group),
+		})
+	}
+	owned["owned"] = list
+}
+
+func (c *clusterServiceVersion) addDeployment(name string, deployment map[string]interface{}) {
+	spec := field(deployment, "spec")
+	podSpec := field(field(spec, "template"), "spec")
+	podSpec["serviceAccountName"] = serviceAccountName
+
+	is := c.installSpec()
+	list, _ := is["deployments"].([]interface{})
+	is["deployments"] = append(list, map[string]interface{}{"name": name, "spec": spec})
+}
+
+func (c *clusterServiceVersion) addPermissions(key string, role map[string]interface{}) {
+	is := c.installSpec()
+	list, _ := is[key].([]interface{})
+	is[key] = append(list, map[string]interface{}{
+		"serviceAccountName": serviceAccountName,
+		"rules":              role["rules"],
+	})
+}
+
+// field returns the named map, creating it if necessary
+func field(obj map[string]interface{}, name string) map[string]interface{} {
+	if m, ok := obj[name].(map[string]interface{}); ok {
+		return m
+	}
+	m := make(map[string]interface{})
+	obj[name] = m
+	return m
+}
+
+func writeYAML(filename string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
@@ -0,0 +1,414 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// OpenAPI generates an OpenAPI document describing the Red Sky API from the Go sources of the API bindings.
+// The document is written as JSON and embedded into a Go source file so it can be served by redskyctl.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+func main() {
+	var (
+		source        = flag.String("source", ".", "directory containing the API binding sources")
+		outputPackage = flag.String("package", "v1alpha1", "output package name")
+		outputFile    = flag.String("output", "zz_generated.openapi.go", "output filename")
+		documentFile  = flag.String("document", "", "optional filename to also write the JSON document to")
+		headerFile    = flag.String("header", "", "optional header file to include at the top of the generated file")
+		version       = flag.String("version", "v1alpha1", "API version included in the document")
+	)
+
+	flag.Parse()
+
+	schemas, err := parseSchemas(*source)
+	if err != nil {
+		log.Fatal("failed to parse API sources: ", err)
+	}
+
+	doc, err := json.MarshalIndent(newDocument(*version, schemas), "", "  ")
+	if err != nil {
+		log.Fatal("failed to marshal OpenAPI document: ", err)
+	}
+	doc = append(doc, '\n')
+
+	if *documentFile != "" {
+		if err := ioutil.WriteFile(*documentFile, doc, 0644); err != nil {
+			log.Fatal("failed to write document file: ", err)
+		}
+	}
+
+	var outputBuffer bytes.Buffer
+	if *headerFile != "" {
+		h, err := ioutil.ReadFile(*headerFile)
+		if err != nil {
+			log.Fatal("failed to read header file")
+		}
+		outputBuffer.Write(h)
+	}
+	outputBuffer.WriteString("\n// Code generated by cmd/openapi, DO NOT EDIT.\n\n")
+	outputBuffer.WriteString(fmt.Sprintf("package %s\n\n", *outputPackage))
+	outputBuffer.WriteString("// OpenAPIDocument is the OpenAPI description of the Red Sky API\n")
+	if bytes.ContainsRune(doc, '`') {
+		outputBuffer.WriteString(fmt.Sprintf("const OpenAPIDocument = %q\n", doc))
+	} else {
+		outputBuffer.WriteString(fmt.Sprintf("const OpenAPIDocument = `%s`\n", doc))
+	}
+
+	if err := ioutil.WriteFile(*outputFile, outputBuffer.Bytes(), 0644); err != nil {
+		log.Fatal("failed to write output file")
+	}
+}
+
+// schema is a JSON schema object
+type schema map[string]interface{}
+
+// ref returns a reference to a named schema
+func ref(name string) schema {
+	return schema{"$ref": "#/components/schemas/" + name}
+}
+
+// operation describes a single API endpoint
+type operation struct {
+	path        string
+	method      string
+	id          string
+	summary     string
+	parameters  []schema
+	request     string
+	response    string
+	status      int
+	description string
+}
+
+var (
+	nameParameter        = schema{"name": "name", "in": "path", "required": true, "schema": schema{"type": "string"}}
+	numberParameter      = schema{"name": "number", "in": "path", "required": true, "schema": schema{"type": "integer", "format": "int64"}}
+	labelSelectorParam   = schema{"name": "labelSelector", "in": "query", "description": "Comma separated list of label value pairs to match on.", "schema": schema{"type": "string"}}
+	offsetParameter      = schema{"name": "offset", "in": "query", "schema": schema{"type": "integer"}}
+	limitParameter       = schema{"name": "limit", "in": "query", "schema": schema{"type": "integer"}}
+	trialStatusParameter = schema{"name": "status", "in": "query", "description": "Comma separated list of statuses to fetch.", "schema": schema{"type": "string"}}
+	idempotencyKeyParam  = schema{"name": "Idempotency-Key", "in": "header", "description": "Identifies retries of the same report, a conflicting retry is not an error.", "schema": schema{"type": "string"}}
+)
+
+// operations are the endpoints of the API, the experiment and trial endpoints are normally discovered using links
+var operations = []operation{
+	{path: "/experiments/", method: "options", id: "options", summary: "Describe the server", status: 204},
+	{path: "/experiments/", method: "get", id: "getAllExperiments", summary: "List experiments", parameters: []schema{offsetParameter, limitParameter, labelSelectorParam}, response: "ExperimentList", status: 200},
+	{path: "/experiments/{name}", method: "get", id: "getExperiment", summary: "Get an experiment", parameters: []schema{nameParameter}, response: "Experiment", status: 200},
+	{path: "/experiments/{name}", method: "put", id: "createExperiment", summary: "Create or update an experiment", parameters: []schema{nameParameter}, request: "Experiment", response: "Experiment", status: 200},
+	{path: "/experiments/{name}", method: "patch", id: "updateExperiment", summary: "Change the optimization settings of an experiment", parameters: []schema{nameParameter}, request: "ExperimentPatch", response: "Experiment", status: 200},
+	{path: "/experiments/{name}", method: "delete", id: "deleteExperiment", summary: "Delete an experiment", parameters: []schema{nameParameter}, status: 204},
+	{path: "/experiments/{name}/labels", method: "post", id: "labelExperiment", summary: "Update experiment labels", parameters: []schema{nameParameter}, request: "ExperimentLabels", status: 204},
+	{path: "/experiments/{name}/nextTrial", method: "post", id: "nextTrial", summary: "Obtain the next trial suggestion", parameters: []schema{nameParameter}, response: "TrialAssignments", status: 200},
+	{path: "/experiments/{name}/trialReports", method: "post", id: "reportTrials", summary: "Record multiple completed trials", parameters: []schema{nameParameter}, request: "TrialReports", status: 201},
+	{path: "/experiments/{name}/trials/", method: "get", id: "getAllTrials", summary: "List trials", parameters: []schema{nameParameter, trialStatusParameter, labelSelectorParam}, response: "TrialList", status: 200},
+	{path: "/experiments/{name}/trials/", method: "post", id: "createTrial", summary: "Create a trial with explicit assignments", parameters: []schema{nameParameter}, request: "TrialAssignments", status: 201},
+	{path: "/experiments/{name}/trials/{number}", method: "post", id: "reportTrial", summary: "Report trial observations", parameters: []schema{nameParameter, numberParameter, idempotencyKeyParam}, request: "TrialValues", status: 201},
+	{path: "/experiments/{name}/trials/{number}", method: "delete", id: "abandonRunningTrial", summary: "Abandon a running trial", parameters: []schema{nameParameter, numberParameter}, status: 204},
+	{path: "/experiments/{name}/trials/{number}/labels", method: "post", id: "labelTrial", summary: "Update trial labels", parameters: []schema{nameParameter, numberParameter}, request: "TrialLabels", status: 204},
+}
+
+// newDocument returns the OpenAPI document
+func newDocument(version string, schemas map[string]schema) schema {
+	paths := make(map[string]schema)
+	for _, op := range operations {
+		o := schema{
+			"operationId": op.id,
+			"summary":     op.summary,
+			"responses": schema{
+				"default": schema{
+					"description": "Error",
+					"content":     schema{"application/json": schema{"schema": ref("Error")}},
+				},
+			},
+		}
+		if len(op.parameters) > 0 {
+			o["parameters"] = op.parameters
+		}
+		if op.request != "" {
+			o["requestBody"] = schema{
+				"required": true,
+				"content":  schema{"application/json": schema{"schema": ref(op.request)}},
+			}
+		}
+		r := schema{"description": statusDescription(op.status)}
+		if op.response != "" {
+			r["content"] = schema{"application/json": schema{"schema": ref(op.response)}}
+		}
+		o["responses"].(schema)[strconv.Itoa(op.status)] = r
+
+		if paths[op.path] == nil {
+			paths[op.path] = schema{}
+		}
+		paths[op.path][op.method] = o
+	}
+
+	return schema{
+		"openapi": "3.0.3",
+		"info": schema{
+			"title":   "Red Sky API",
+			"version": version,
+		},
+		"paths":      paths,
+		"components": schema{"schemas": schemas},
+	}
+}
+
+// statusDescription returns a description for the supplied status code
+func statusDescription(status int) string {
+	switch status {
+	case 200:
+		return "OK"
+	case 201:
+		return "Created"
+	case 204:
+		return "No Content"
+	}
+	return ""
+}
+
+// parseSchemas reads the Go sources and produces a schema for every type that can be exchanged with the API
+func parseSchemas(dir string) (map[string]schema, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go") && !strings.HasPrefix(fi.Name(), "zz_generated")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &schemaParser{types: make(map[string]*ast.TypeSpec), docs: make(map[string]string), enums: make(map[string][]string)}
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			p.collect(f)
+		}
+	}
+
+	schemas := make(map[string]schema)
+	for name, ts := range p.types {
+		if s := p.typeSchema(ts); s != nil {
+			schemas[name] = s
+		}
+	}
+	return schemas, nil
+}
+
+// fieldSchemas overrides the schema of struct fields whose wire format is customized, keyed by "Type.jsonName"
+var fieldSchemas = map[string]schema{
+	"Assignment.value": {"oneOf": []schema{{"type": "number"}, {"type": "string"}}},
+}
+
+// schemaParser holds the declarations found in the API sources
+type schemaParser struct {
+	types map[string]*ast.TypeSpec
+	docs  map[string]string
+	enums map[string][]string
+}
+
+// collect records the exported type and constant declarations of a file
+func (p *schemaParser) collect(f *ast.File) {
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if !s.Name.IsExported() {
+					continue
+				}
+				p.types[s.Name.Name] = s
+				if s.Doc != nil {
+					p.docs[s.Name.Name] = description(s.Doc)
+				} else if gd.Doc != nil {
+					p.docs[s.Name.Name] = description(gd.Doc)
+				}
+			case *ast.ValueSpec:
+				typ, ok := s.Type.(*ast.Ident)
+				if gd.Tok != token.CONST || !ok {
+					continue
+				}
+				for _, v := range s.Values {
+					if lit, ok := v.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						if value, err := strconv.Unquote(lit.Value); err == nil {
+							p.enums[typ.Name] = append(p.enums[typ.Name], value)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// typeSchema returns the schema for a declared type or nil if the type is not part of the API
+func (p *schemaParser) typeSchema(ts *ast.TypeSpec) schema {
+	var s schema
+	switch t := ts.Type.(type) {
+	case *ast.StructType:
+		properties, required := p.properties(ts.Name.Name, t)
+		if len(properties) == 0 {
+			return nil
+		}
+		s = schema{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			sort.Strings(required)
+			s["required"] = required
+		}
+	case *ast.Ident:
+		if len(p.enums[ts.Name.Name]) == 0 {
+			return nil
+		}
+		s = p.exprSchema(t)
+		s["enum"] = p.enums[ts.Name.Name]
+	case *ast.MapType:
+		s = p.exprSchema(t)
+	default:
+		return nil
+	}
+
+	if d := p.docs[ts.Name.Name]; d != "" {
+		s["description"] = d
+	}
+	return s
+}
+
+// properties returns the JSON properties of a struct, including those promoted from embedded structs
+func (p *schemaParser) properties(typeName string, st *ast.StructType) (map[string]schema, []string) {
+	properties := make(map[string]schema)
+	var required []string
+	for _, f := range st.Fields.List {
+		name, opts := "", ""
+		if f.Tag != nil {
+			tag, _ := strconv.Unquote(f.Tag.Value)
+			name, opts = splitTag(reflect.StructTag(tag).Get("json"))
+		}
+		if name == "-" {
+			continue
+		}
+
+		// Embedded structs without a JSON name have their fields promoted
+		if len(f.Names) == 0 && name == "" {
+			if id, ok := f.Type.(*ast.Ident); ok {
+				if ts, ok := p.types[id.Name]; ok {
+					if est, ok := ts.Type.(*ast.StructType); ok {
+						pp, rr := p.properties(id.Name, est)
+						for k, v := range pp {
+							properties[k] = v
+						}
+						required = append(required, rr...)
+					}
+				}
+			}
+			continue
+		}
+
+		// Fields without a JSON tag are not part of the wire format
+		if name == "" || len(f.Names) == 0 || !f.Names[0].IsExported() {
+			continue
+		}
+
+		s := p.exprSchema(f.Type)
+		if fs, ok := fieldSchemas[typeName+"."+name]; ok {
+			s = schema{}
+			for k, v := range fs {
+				s[k] = v
+			}
+		}
+		if f.Doc != nil {
+			if _, isRef := s["$ref"]; isRef {
+				s = schema{"allOf": []schema{s}}
+			}
+			s["description"] = description(f.Doc)
+		}
+		properties[name] = s
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	return properties, required
+}
+
+// exprSchema returns the schema for a type expression
+func (p *schemaParser) exprSchema(expr ast.Expr) schema {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return schema{"type": "string"}
+		case "bool":
+			return schema{"type": "boolean"}
+		case "int", "int32":
+			return schema{"type": "integer", "format": "int32"}
+		case "int64":
+			return schema{"type": "integer", "format": "int64"}
+		case "float32":
+			return schema{"type": "number", "format": "float"}
+		case "float64":
+			return schema{"type": "number", "format": "double"}
+		}
+		if ts, ok := p.types[t.Name]; ok {
+			if id, ok := ts.Type.(*ast.Ident); ok && len(p.enums[t.Name]) == 0 {
+				return p.exprSchema(id)
+			}
+			return ref(t.Name)
+		}
+	case *ast.StarExpr:
+		return p.exprSchema(t.X)
+	case *ast.ArrayType:
+		return schema{"type": "array", "items": p.exprSchema(t.Elt)}
+	case *ast.MapType:
+		return schema{"type": "object", "additionalProperties": p.exprSchema(t.Value)}
+	case *ast.SelectorExpr:
+		switch fmt.Sprintf("%s.%s", t.X, t.Sel.Name) {
+		case "json.Number":
+			return schema{"type": "number"}
+		case "time.Time":
+			return schema{"type": "string", "format": "date-time"}
+		case "time.Duration":
+			return schema{"type": "integer", "format": "int64"}
+		}
+	}
+	return schema{}
+}
+
+// splitTag returns the name and options of a struct tag value
+func splitTag(tag string) (string, string) {
+	if i := strings.Index(tag, ","); i >= 0 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
+
+// description returns the text of a comment group as a single line
+func description(cg *ast.CommentGroup) string {
+	return strings.Join(strings.Fields(cg.Text()), " ")
+}
@@ -20,6 +20,15 @@ templatizeDeployment() {
         sed 's/VERSION/"{{ .Chart.AppVersion }}"/g' | \
         sed 's/IMG:TAG/"{{ .Values.redskyImage }}:{{ .Values.redskyTag }}"/g' | \
         sed 's/PULL_POLICY/{{ .Values.redskyImagePullPolicy }}/g' | \
+        sed 's/value: WATCH_NAMESPACE/value: {{ .Values.watchNamespace | quote }}/g' | \
+        sed 's/^\( *\)imagePullPolicy: .*$/&\n\1resources:\n\1  {{- toYaml .Values.resources | nindent 10 }}/g' | \
+        sed 's/name: redsky-manager$/name: {{ .Values.remoteServer.existingSecret | default (printf "%s-manager" .Release.Name) | quote }}/g' | \
+        sed 's/WEBHOOK_CERT_PROVISIONER/{{ if .Values.webhook.existingSecret }}none{{ else if .Values.webhook.certManager.enabled }}cert-manager{{ else if .Values.webhook.selfSigned }}self-signed{{ else }}none{{ end }}/g' | \
+        sed 's/- \(--webhook-.*\)$/- "\1"/g' | \
+        sed 's/WEBHOOK_SERVICE_NAME/{{ .Release.Name }}-webhook-service/g' | \
+        sed 's/^\( *\)secret:$/\1{{- if or .Values.webhook.certManager.enabled .Values.webhook.existingSecret }}\n&/g' | \
+        sed 's/^\( *\)  secretName: WEBHOOK_SECRET_NAME$/&\n\1{{- else }}\n\1emptyDir: {}\n\1{{- end }}/g' | \
+        sed 's/WEBHOOK_SECRET_NAME/{{ .Values.webhook.existingSecret | default (printf "%s-webhook-server-cert" .Release.Name) | quote }}/g' | \
         sed 's/name: redsky-\(.*\)$/name: "{{ .Release.Name }}-\1"/g'
 }
 
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/controller"
+	"github.com/redskyops/redskyops-controller/internal/events"
+	"github.com/redskyops/redskyops-controller/internal/meta"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// EventReconciler publishes trial and experiment lifecycle events. The event types published for each object are
+// recorded in an annotation so events are retried (by returning an error) until they are published and are not
+// published again when the manager restarts.
+type EventReconciler struct {
+	client.Client
+	Log     logr.Logger
+	Emitter *events.Emitter
+}
+
+// +kubebuilder:rbac:groups=redskyops.dev,resources=experiments;trials,verbs=get;list;watch;update
+
+// Reconcile publishes the lifecycle events of a trial
+func (r *EventReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+
+	t := &redskyv1beta1.Trial{}
+	if err := r.Get(ctx, req.NamespacedName, t); err != nil {
+		return ctrl.Result{}, controller.IgnoreNotFound(err)
+	}
+
+	published, observed := events.Published(t)
+	pending := events.TrialEvents(t, published)
+
+	// Trials which finished (or started deleting) before they were ever observed are recorded without publishing
+	// anything, otherwise every existing trial would be published the first time the manager starts
+	if !observed && (trial.IsFinished(t) || !t.GetDeletionTimestamp().IsZero()) {
+		return r.recordPublished(ctx, t, published, pending)
+	}
+
+	for _, e := range pending {
+		if err := r.emit(e); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		if e.Type == events.TrialCompleted {
+			if err := r.emitBestTrial(ctx, t); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	if observed && len(pending) == 0 {
+		return ctrl.Result{}, nil
+	}
+	return r.recordPublished(ctx, t, published, pending)
+}
+
+// reconcileExperiment publishes the lifecycle events of an experiment
+func (r *EventReconciler) reconcileExperiment(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+
+	exp := &redskyv1beta1.Experiment{}
+	if err := r.Get(ctx, req.NamespacedName, exp); err != nil {
+		return ctrl.Result{}, controller.IgnoreNotFound(err)
+	}
+
+	published, observed := events.Published(exp)
+	pending := events.ExperimentEvents(exp, published)
+
+	// Experiments which were already converged or completed before they were ever observed are just recorded
+	if !observed {
+		return r.recordPublished(ctx, exp, published, pending)
+	}
+
+	for _, e := range pending {
+		if err := r.emit(e); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if len(pending) == 0 {
+		return ctrl.Result{}, nil
+	}
+	return r.recordPublished(ctx, exp, published, pending)
+}
+
+func (r *EventReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("event-experiment").
+		For(&redskyv1beta1.Experiment{}).
+		Complete(reconcile.Func(r.reconcileExperiment)); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("event").
+		For(&redskyv1beta1.Trial{}).
+		Complete(r)
+}
+
+// publishedObject is an experiment or trial
+type publishedObject interface {
+	metav1.Object
+	runtime.Object
+}
+
+// recordPublished updates the published events annotation of an object
+func (r *EventReconciler) recordPublished(ctx context.Context, obj publishedObject, published map[events.Type]bool, pending []*events.Event) (ctrl.Result, error) {
+	for _, e := range pending {
+		published[e.Type] = true
+	}
+	events.SetPublished(obj, published)
+
+	result, err := controller.RequeueConflict(r.Update(ctx, obj))
+	return *result, err
+}
+
+// emitBestTrial checks a completed trial against the rest of the experiment to see if it is a new best
+func (r *EventReconciler) emitBestTrial(ctx context.Context, t *redskyv1beta1.Trial) error {
+	exp := &redskyv1beta1.Experiment{}
+	if err := r.Get(ctx, t.ExperimentNamespacedName(), exp); err != nil {
+		return controller.IgnoreNotFound(err)
+	}
+
+	trialList := &redskyv1beta1.TrialList{}
+	if err := r.listTrials(ctx, trialList, exp.TrialSelector()); err != nil {
+		return err
+	}
+
+	if best := events.BestTrialEvent(exp, t, trialList); best != nil {
+		return r.emit(best)
+	}
+	return nil
+}
+
+// emit publishes a single event, events rejected by the publisher are logged and dropped instead of being retried
+func (r *EventReconciler) emit(e *events.Event) error {
+	err := r.Emitter.Emit(e)
+	if events.IsPermanent(err) {
+		r.Log.Error(err, "Dropping rejected event", "type", e.Type, "namespace", e.Namespace, "experiment", e.Experiment, "trial", e.Trial)
+		return nil
+	}
+	return err
+}
+
+// listTrials retrieves the list of trial objects matching the specified selector
+func (r *EventReconciler) listTrials(ctx context.Context, trialList *redskyv1beta1.TrialList, selector *metav1.LabelSelector) error {
+	matchingSelector, err := meta.MatchingSelector(selector)
+	if err != nil {
+		return err
+	}
+	return r.List(ctx, trialList, matchingSelector)
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/redskyops/redskyops-controller/internal/trial"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -38,9 +39,14 @@ import (
 type ExperimentReconciler struct {
 	client.Client
 	Log logr.Logger
+	// Backoff controls how failed reconciles are retried
+	Backoff controller.Backoff
+	// Recorder is used to report experiment convergence, may be nil
+	Recorder record.EventRecorder
 }
 
 // +kubebuilder:rbac:groups=redskyops.dev,resources=experiments,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=redskyops.dev,resources=trials,verbs=list;watch;update;delete
 
 func (r *ExperimentReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -76,7 +82,7 @@ func (r *ExperimentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Named("experiment").
 		For(&redskyv1beta1.Experiment{}).
 		Watches(&source.Kind{Type: &redskyv1beta1.Trial{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(trialToExperimentRequest)}).
-		Complete(r)
+		Complete(r.Backoff.Reconciler(r, r.Log))
 }
 
 // trialToExperimentRequest extracts the reconcile request for an experiment of a trial
@@ -98,8 +104,18 @@ func (r *ExperimentReconciler) updateStatus(ctx context.Context, exp *redskyv1be
 		dirty = meta.RemoveFinalizer(exp, experiment.HasTrialFinalizer) || dirty
 	}
 
+	// Check if a running experiment has stopped improving
+	now := metav1.Now()
+	converged := experiment.UpdateConvergedCondition(exp, trialList, &now)
+	dirty = converged || dirty
+
 	// Update the experiment status
 	dirty = experiment.UpdateStatus(exp, trialList) || dirty
+	dirty = experiment.UpdateForecast(exp, trialList) || dirty
+	dirty = experiment.UpdateResourceHours(exp, trialList) || dirty
+
+	// Check if a completed experiment would benefit from expanded bounds
+	dirty = experiment.UpdateBoundsCondition(exp, trialList, &now) || dirty
 
 	// Only send an update if something actually changed
 	if dirty {
@@ -107,6 +123,12 @@ func (r *ExperimentReconciler) updateStatus(ctx context.Context, exp *redskyv1be
 			return controller.RequeueConflict(err)
 		}
 	}
+
+	// Record an event once the update with the new condition succeeds
+	if converged && r.Recorder != nil && experiment.CheckCondition(&exp.Status, redskyv1beta1.ExperimentConverged, corev1.ConditionTrue) {
+		c := experiment.GetCondition(&exp.Status, redskyv1beta1.ExperimentConverged)
+		r.Recorder.Event(exp, corev1.EventTypeNormal, c.Reason, c.Message)
+	}
 	return nil, nil
 }
 
@@ -126,9 +148,13 @@ func (r *ExperimentReconciler) updateTrialStatus(ctx context.Context, trialList
 
 		// Update the trial status
 		dirty = trial.UpdateStatus(t) || dirty
+		dirty = trial.Compact(t) || dirty
 
 		// Only send an update if something actually changed
 		if dirty {
+			if size := trial.Size(t); size > trial.WarningSize {
+				r.Log.Info("Trial is nearing the object size limit", "trial", t.Namespace+"/"+t.Name, "size", size, "limit", trial.MaxSize)
+			}
 			if err := r.Update(ctx, t); err != nil {
 				return controller.RequeueConflict(err)
 			}
@@ -0,0 +1,454 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/controller"
+	"github.com/redskyops/redskyops-controller/internal/experiment"
+	"github.com/redskyops/redskyops-controller/internal/metric"
+	"github.com/redskyops/redskyops-controller/internal/schedule"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultExperimentsHistoryLimit is the number of completed experiments to keep when a limit is not specified
+const defaultExperimentsHistoryLimit = 3
+
+// ExperimentScheduleReconciler reconciles an ExperimentSchedule object
+type ExperimentScheduleReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=redskyops.dev,resources=experimentschedules,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=redskyops.dev,resources=experiments,verbs=list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups="",resources=services,verbs=list
+
+func (r *ExperimentScheduleReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+	log := r.Log.WithValues("experimentschedule", req.NamespacedName)
+	now := time.Now()
+
+	sched := &redskyv1beta1.ExperimentSchedule{}
+	if err := r.Get(ctx, req.NamespacedName, sched); err != nil {
+		return ctrl.Result{}, controller.IgnoreNotFound(err)
+	}
+
+	expList := &redskyv1beta1.ExperimentList{}
+	if err := r.listExperiments(ctx, sched, expList); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if result, err := r.updateStatus(ctx, sched, expList); result != nil {
+		return *result, err
+	}
+
+	if result, err := r.cleanupExperiments(ctx, sched, expList); result != nil {
+		return *result, err
+	}
+
+	if result, err := r.runSchedule(ctx, log, sched, expList, now); result != nil {
+		return *result, err
+	}
+
+	if result, err := r.detectDrift(ctx, log, sched, expList, now); result != nil {
+		return *result, err
+	}
+
+	return r.requeue(sched, now), nil
+}
+
+func (r *ExperimentScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("experiment-schedule").
+		For(&redskyv1beta1.ExperimentSchedule{}).
+		Owns(&redskyv1beta1.Experiment{}).
+		Complete(r)
+}
+
+// listExperiments finds the experiments created by the supplied schedule, the list is sorted by creation time
+func (r *ExperimentScheduleReconciler) listExperiments(ctx context.Context, sched *redskyv1beta1.ExperimentSchedule, expList *redskyv1beta1.ExperimentList) error {
+	if err := r.List(ctx, expList, client.InNamespace(sched.Namespace), client.MatchingLabels{redskyv1beta1.LabelExperimentSchedule: sched.Name}); err != nil {
+		return err
+	}
+
+	items := expList.Items[:0]
+	for i := range expList.Items {
+		if metav1.IsControlledBy(&expList.Items[i], sched) {
+			items = append(items, expList.Items[i])
+		}
+	}
+	expList.Items = items
+
+	sort.Slice(expList.Items, func(i, j int) bool {
+		return expList.Items[i].CreationTimestamp.Before(&expList.Items[j].CreationTimestamp)
+	})
+	return nil
+}
+
+// updateStatus records the currently active experiments
+func (r *ExperimentScheduleReconciler) updateStatus(ctx context.Context, sched *redskyv1beta1.ExperimentSchedule, expList *redskyv1beta1.ExperimentList) (*ctrl.Result, error) {
+	var active []corev1.ObjectReference
+	for i := range expList.Items {
+		if !isExperimentFinished(&expList.Items[i]) {
+			active = append(active, experimentReference(&expList.Items[i]))
+		}
+	}
+
+	if !equalReferences(sched.Status.Active, active) {
+		sched.Status.Active = active
+		if err := r.Update(ctx, sched); err != nil {
+			return controller.RequeueConflict(err)
+		}
+	}
+	return nil, nil
+}
+
+// cleanupExperiments deletes the oldest completed experiments beyond the history limit
+func (r *ExperimentScheduleReconciler) cleanupExperiments(ctx context.Context, sched *redskyv1beta1.ExperimentSchedule, expList *redskyv1beta1.ExperimentList) (*ctrl.Result, error) {
+	limit := defaultExperimentsHistoryLimit
+	if sched.Spec.ExperimentsHistoryLimit != nil {
+		limit = int(*sched.Spec.ExperimentsHistoryLimit)
+	}
+
+	var finished []*redskyv1beta1.Experiment
+	for i := range expList.Items {
+		if isExperimentFinished(&expList.Items[i]) && expList.Items[i].DeletionTimestamp.IsZero() {
+			finished = append(finished, &expList.Items[i])
+		}
+	}
+
+	for i := 0; i < len(finished)-limit; i++ {
+		if err := r.Delete(ctx, finished[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); controller.IgnoreNotFound(err) != nil {
+			return &ctrl.Result{}, err
+		}
+	}
+	return nil, nil
+}
+
+// runSchedule creates a new experiment if a scheduled run is due
+func (r *ExperimentScheduleReconciler) runSchedule(ctx context.Context, log logr.Logger, sched *redskyv1beta1.ExperimentSchedule, expList *redskyv1beta1.ExperimentList, now time.Time) (*ctrl.Result, error) {
+	if sched.Spec.Suspend != nil && *sched.Spec.Suspend {
+		return nil, nil
+	}
+
+	// Without a schedule, only the initial run is started
+	if sched.Spec.Schedule == "" {
+		if sched.Status.LastScheduleTime != nil || len(expList.Items) > 0 {
+			return nil, nil
+		}
+		return r.createExperiment(ctx, log, sched, expList, now, sched.Spec.WarmStart)
+	}
+
+	s, err := schedule.Parse(sched.Spec.Schedule)
+	if err != nil {
+		// Do not requeue an invalid schedule, it will be reconciled again when it changes
+		log.Error(err, "Invalid schedule", "schedule", sched.Spec.Schedule)
+		return &ctrl.Result{}, nil
+	}
+
+	// Determine the most recent scheduled run that has not been started
+	after := sched.CreationTimestamp.Time
+	if sched.Status.LastScheduleTime != nil {
+		after = sched.Status.LastScheduleTime.Time
+	}
+	scheduled := s.Last(after, now)
+	if scheduled.IsZero() {
+		return nil, nil
+	}
+
+	// Apply the concurrency policy
+	active := activeExperiments(expList)
+	switch sched.Spec.ConcurrencyPolicy {
+	case redskyv1beta1.AllowConcurrent:
+	case redskyv1beta1.ReplaceConcurrent:
+		for _, exp := range active {
+			if err := r.Delete(ctx, exp, client.PropagationPolicy(metav1.DeletePropagationBackground)); controller.IgnoreNotFound(err) != nil {
+				return &ctrl.Result{}, err
+			}
+		}
+	default:
+		if len(active) > 0 {
+			log.Info("Skipping scheduled run, previous experiment is still active", "scheduledTime", scheduled)
+			return nil, nil
+		}
+	}
+
+	return r.createExperiment(ctx, log, sched, expList, scheduled, sched.Spec.WarmStart)
+}
+
+// detectDrift monitors the objective metric once all experiments have completed and starts a new run when the
+// metric degrades beyond the threshold for the configured period
+func (r *ExperimentScheduleReconciler) detectDrift(ctx context.Context, log logr.Logger, sched *redskyv1beta1.ExperimentSchedule, expList *redskyv1beta1.ExperimentList, now time.Time) (*ctrl.Result, error) {
+	dd := sched.Spec.DriftDetection
+	m := driftMetric(sched)
+	if m == nil {
+		if dd != nil {
+			log.Info("Drift detection disabled, no metric to monitor", "schedule", sched.Name)
+		}
+		return nil, nil
+	}
+	if len(expList.Items) == 0 {
+		return nil, nil
+	}
+
+	// Reset the baseline while experiments are running
+	if len(activeExperiments(expList)) > 0 {
+		if sched.Status.Drift != nil {
+			sched.Status.Drift = nil
+			if err := r.Update(ctx, sched); err != nil {
+				return controller.RequeueConflict(err)
+			}
+		}
+		return nil, nil
+	}
+
+	if sched.Status.Drift == nil {
+		sched.Status.Drift = &redskyv1beta1.DriftStatus{}
+	}
+	drift := sched.Status.Drift
+	if drift.LastProbeTime != nil && drift.LastProbeTime.Add(driftInterval(dd)).After(now) {
+		return nil, nil
+	}
+	drift.LastProbeTime = &metav1.Time{Time: now}
+
+	// Evaluate the metric over the most recent interval as if it were a trial run
+	latest := &expList.Items[len(expList.Items)-1]
+	t := &redskyv1beta1.Trial{}
+	t.Name = latest.Name
+	t.Namespace = sched.Namespace
+	t.Status.StartTime = &metav1.Time{Time: now.Add(-driftInterval(dd))}
+	t.Status.CompletionTime = &metav1.Time{Time: now}
+	value, err := r.captureDriftMetric(ctx, m, t)
+	if err != nil {
+		if merr, ok := err.(*metric.CaptureError); ok && merr.RetryAfter > 0 {
+			return &ctrl.Result{RequeueAfter: merr.RetryAfter}, nil
+		}
+		log.Error(err, "Drift detection metric collection failed", "metric", m.Name)
+	} else if observeDrift(drift, dd, m.Minimize, value, now) {
+		log.Info("Performance degraded, starting a new experiment", "metric", m.Name, "baseline", drift.Baseline, "value", drift.LastValue)
+		sched.Status.Drift = nil
+		return r.createExperiment(ctx, log, sched, expList, now, dd.WarmStart == nil || *dd.WarmStart)
+	}
+
+	if err := r.Update(ctx, sched); err != nil {
+		return controller.RequeueConflict(err)
+	}
+	return nil, nil
+}
+
+// observeDrift records a newly observed drift detection metric value; returns true only if the metric has been degraded
+// for the configured period and a new run should be started
+func observeDrift(drift *redskyv1beta1.DriftStatus, dd *redskyv1beta1.DriftDetection, minimize bool, value float64, now time.Time) bool {
+	drift.LastValue = strconv.FormatFloat(value, 'f', -1, 64)
+	if drift.Baseline == "" {
+		drift.Baseline = drift.LastValue
+	}
+
+	threshold := 10.0
+	if dd.Threshold != nil {
+		threshold = float64(*dd.Threshold)
+	}
+	period := time.Hour
+	if dd.Period != nil {
+		period = dd.Period.Duration
+	}
+
+	baseline, _ := strconv.ParseFloat(drift.Baseline, 64)
+	switch {
+	case !isDegraded(baseline, value, threshold, minimize):
+		drift.DegradedSince = nil
+	case drift.DegradedSince == nil:
+		drift.DegradedSince = &metav1.Time{Time: now}
+	case now.Sub(drift.DegradedSince.Time) >= period:
+		return true
+	}
+	return false
+}
+
+// captureDriftMetric captures the current value of the drift detection metric
+func (r *ExperimentScheduleReconciler) captureDriftMetric(ctx context.Context, m *redskyv1beta1.Metric, t *redskyv1beta1.Trial) (float64, error) {
+	target, err := metricTarget(ctx, r, t.Namespace, m)
+	if err != nil {
+		return 0, err
+	}
+	value, _, err := metric.CaptureMetric(m, t, target)
+	return value, err
+}
+
+// requeue returns the result for waiting until the next scheduled run or drift detection probe
+func (r *ExperimentScheduleReconciler) requeue(sched *redskyv1beta1.ExperimentSchedule, now time.Time) ctrl.Result {
+	var next time.Time
+	if sched.Spec.Suspend == nil || !*sched.Spec.Suspend {
+		if s, err := schedule.Parse(sched.Spec.Schedule); err == nil && sched.Spec.Schedule != "" {
+			next = s.Next(now)
+		}
+	}
+	if d := sched.Status.Drift; d != nil && d.LastProbeTime != nil && sched.Spec.DriftDetection != nil {
+		if probe := d.LastProbeTime.Add(driftInterval(sched.Spec.DriftDetection)); next.IsZero() || probe.Before(next) {
+			next = probe
+		}
+	}
+
+	if next.IsZero() {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: next.Sub(now)}
+}
+
+// createExperiment creates a new experiment run and records it in the schedule status
+func (r *ExperimentScheduleReconciler) createExperiment(ctx context.Context, log logr.Logger, sched *redskyv1beta1.ExperimentSchedule, expList *redskyv1beta1.ExperimentList, scheduled time.Time, warmStart bool) (*ctrl.Result, error) {
+	exp := r.newExperiment(sched, expList, scheduled, warmStart)
+	if err := ctrl.SetControllerReference(sched, exp, r.Scheme); err != nil {
+		return &ctrl.Result{}, err
+	}
+	if err := r.Create(ctx, exp); err != nil && !apierrs.IsAlreadyExists(err) {
+		return &ctrl.Result{}, err
+	}
+	log.Info("Created scheduled experiment", "experiment", exp.Name, "scheduledTime", scheduled)
+
+	sched.Status.LastScheduleTime = &metav1.Time{Time: scheduled}
+	sched.Status.Active = append(sched.Status.Active, experimentReference(exp))
+	if err := r.Update(ctx, sched); err != nil {
+		return controller.RequeueConflict(err)
+	}
+	return &ctrl.Result{}, nil
+}
+
+// newExperiment creates a new experiment from the schedule template
+func (r *ExperimentScheduleReconciler) newExperiment(sched *redskyv1beta1.ExperimentSchedule, expList *redskyv1beta1.ExperimentList, scheduled time.Time, warmStart bool) *redskyv1beta1.Experiment {
+	exp := &redskyv1beta1.Experiment{}
+	sched.Spec.ExperimentTemplate.ObjectMeta.DeepCopyInto(&exp.ObjectMeta)
+	sched.Spec.ExperimentTemplate.Spec.DeepCopyInto(&exp.Spec)
+
+	// The name is deterministic so the same scheduled run is never created twice
+	exp.Name = fmt.Sprintf("%s-%d", sched.Name, scheduled.Unix()/60)
+	exp.Namespace = sched.Namespace
+	if exp.Labels == nil {
+		exp.Labels = make(map[string]string, 1)
+	}
+	exp.Labels[redskyv1beta1.LabelExperimentSchedule] = sched.Name
+
+	// Seed the optimizer using the most recent experiment that made it to the server
+	if warmStart {
+		for i := len(expList.Items) - 1; i >= 0; i-- {
+			if u := expList.Items[i].Annotations[redskyv1beta1.AnnotationExperimentURL]; u != "" {
+				if exp.Annotations == nil {
+					exp.Annotations = make(map[string]string, 1)
+				}
+				exp.Annotations[redskyv1beta1.AnnotationWarmStartFrom] = u
+				break
+			}
+		}
+	}
+
+	return exp
+}
+
+// isExperimentFinished checks to see if an experiment is no longer running trials
+func isExperimentFinished(exp *redskyv1beta1.Experiment) bool {
+	return exp.Status.Phase == experiment.PhaseCompleted || !exp.DeletionTimestamp.IsZero()
+}
+
+// driftMetric returns the metric monitored by drift detection, nil if drift detection is not enabled or if there is
+// no suitable metric to monitor
+func driftMetric(sched *redskyv1beta1.ExperimentSchedule) *redskyv1beta1.Metric {
+	dd := sched.Spec.DriftDetection
+	if dd == nil {
+		return nil
+	}
+	if dd.Metric != nil {
+		return dd.Metric
+	}
+
+	// Local metrics (e.g. the trial duration) are computed from the trial itself and have no meaningful value when
+	// evaluated over the drift detection interval
+	metrics := sched.Spec.ExperimentTemplate.Spec.Metrics
+	for i := range metrics {
+		if metrics[i].Type != redskyv1beta1.MetricLocal && metrics[i].Type != "" {
+			return &metrics[i]
+		}
+	}
+	return nil
+}
+
+// driftInterval returns how often drift detection metrics are evaluated
+func driftInterval(dd *redskyv1beta1.DriftDetection) time.Duration {
+	if dd.Interval != nil {
+		return dd.Interval.Duration
+	}
+	return 5 * time.Minute
+}
+
+// isDegraded checks to see if a value is worse than the baseline by more than the threshold percentage
+func isDegraded(baseline, value, threshold float64, minimize bool) bool {
+	delta := math.Abs(baseline) * threshold / 100
+	if minimize {
+		return value > baseline+delta
+	}
+	return value < baseline-delta
+}
+
+// activeExperiments returns the experiments which have not finished
+func activeExperiments(expList *redskyv1beta1.ExperimentList) []*redskyv1beta1.Experiment {
+	var active []*redskyv1beta1.Experiment
+	for i := range expList.Items {
+		if !isExperimentFinished(&expList.Items[i]) {
+			active = append(active, &expList.Items[i])
+		}
+	}
+	return active
+}
+
+// experimentReference returns a reference to the supplied experiment
+func experimentReference(exp *redskyv1beta1.Experiment) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: redskyv1beta1.GroupVersion.String(),
+		Kind:       "Experiment",
+		Namespace:  exp.Namespace,
+		Name:       exp.Name,
+		UID:        exp.UID,
+	}
+}
+
+// equalReferences compares two lists of object references
+func equalReferences(a, b []corev1.ObjectReference) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestIsDegraded(t *testing.T) {
+	cases := []struct {
+		desc      string
+		baseline  float64
+		value     float64
+		threshold float64
+		minimize  bool
+		degraded  bool
+	}{
+		{desc: "minimize improved", baseline: 100, value: 80, threshold: 10, minimize: true},
+		{desc: "minimize within threshold", baseline: 100, value: 110, threshold: 10, minimize: true},
+		{desc: "minimize degraded", baseline: 100, value: 111, threshold: 10, minimize: true, degraded: true},
+		{desc: "maximize improved", baseline: 100, value: 120, threshold: 10},
+		{desc: "maximize within threshold", baseline: 100, value: 90, threshold: 10},
+		{desc: "maximize degraded", baseline: 100, value: 89, threshold: 10, degraded: true},
+		{desc: "negative baseline", baseline: -100, value: -85, threshold: 10, minimize: true, degraded: true},
+		{desc: "zero threshold", baseline: 100, value: 100.5, minimize: true, degraded: true},
+		{desc: "larger threshold", baseline: 100, value: 140, threshold: 50, minimize: true},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.degraded, isDegraded(c.baseline, c.value, c.threshold, c.minimize))
+		})
+	}
+}
+
+func TestObserveDrift(t *testing.T) {
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	dd := &redskyv1beta1.DriftDetection{Period: &metav1.Duration{Duration: 30 * time.Minute}}
+	drift := &redskyv1beta1.DriftStatus{}
+
+	// The first value becomes the baseline
+	assert.False(t, observeDrift(drift, dd, true, 100, start))
+	assert.Equal(t, "100", drift.Baseline)
+	assert.Nil(t, drift.DegradedSince)
+
+	// Degradation is recorded but the period has not elapsed
+	assert.False(t, observeDrift(drift, dd, true, 150, start.Add(5*time.Minute)))
+	assert.Equal(t, "150", drift.LastValue)
+	if assert.NotNil(t, drift.DegradedSince) {
+		assert.Equal(t, start.Add(5*time.Minute), drift.DegradedSince.Time)
+	}
+	assert.False(t, observeDrift(drift, dd, true, 150, start.Add(30*time.Minute)))
+
+	// Recovering clears the degradation
+	assert.False(t, observeDrift(drift, dd, true, 105, start.Add(40*time.Minute)))
+	assert.Nil(t, drift.DegradedSince)
+	assert.Equal(t, "100", drift.Baseline)
+
+	// Sustained degradation for the full period starts a new run
+	assert.False(t, observeDrift(drift, dd, true, 150, start.Add(45*time.Minute)))
+	assert.False(t, observeDrift(drift, dd, true, 150, start.Add(70*time.Minute)))
+	assert.True(t, observeDrift(drift, dd, true, 150, start.Add(75*time.Minute)))
+}
+
+func TestDriftMetric(t *testing.T) {
+	duration := redskyv1beta1.Metric{Name: "duration", Minimize: true, Query: "{{duration .StartTime .CompletionTime}}"}
+	latency := redskyv1beta1.Metric{Name: "latency", Minimize: true, Type: redskyv1beta1.MetricPrometheus}
+	cost := redskyv1beta1.Metric{Name: "cost", Minimize: true, Type: redskyv1beta1.MetricPrometheus}
+	throughput := redskyv1beta1.Metric{Name: "throughput", Type: redskyv1beta1.MetricJSONPath}
+
+	cases := []struct {
+		desc     string
+		dd       *redskyv1beta1.DriftDetection
+		metrics  []redskyv1beta1.Metric
+		expected string
+	}{
+		{
+			desc:    "disabled",
+			metrics: []redskyv1beta1.Metric{latency, cost},
+		},
+		{
+			desc:     "first metric",
+			dd:       &redskyv1beta1.DriftDetection{},
+			metrics:  []redskyv1beta1.Metric{latency, cost},
+			expected: "latency",
+		},
+		{
+			desc:     "skip duration",
+			dd:       &redskyv1beta1.DriftDetection{},
+			metrics:  []redskyv1beta1.Metric{duration, cost, latency},
+			expected: "cost",
+		},
+		{
+			desc:     "skip explicit local",
+			dd:       &redskyv1beta1.DriftDetection{},
+			metrics:  []redskyv1beta1.Metric{{Name: "local", Type: redskyv1beta1.MetricLocal}, latency},
+			expected: "latency",
+		},
+		{
+			desc:    "only local",
+			dd:      &redskyv1beta1.DriftDetection{},
+			metrics: []redskyv1beta1.Metric{duration},
+		},
+		{
+			desc: "no metrics",
+			dd:   &redskyv1beta1.DriftDetection{},
+		},
+		{
+			desc:     "explicit",
+			dd:       &redskyv1beta1.DriftDetection{Metric: &throughput},
+			metrics:  []redskyv1beta1.Metric{latency, cost},
+			expected: "throughput",
+		},
+		{
+			desc:     "explicit local",
+			dd:       &redskyv1beta1.DriftDetection{Metric: &duration},
+			metrics:  []redskyv1beta1.Metric{latency},
+			expected: "duration",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			sched := &redskyv1beta1.ExperimentSchedule{}
+			sched.Spec.DriftDetection = c.dd
+			sched.Spec.ExperimentTemplate.Spec.Metrics = c.metrics
+			m := driftMetric(sched)
+			if c.expected == "" {
+				assert.Nil(t, m)
+			} else if assert.NotNil(t, m) {
+				assert.Equal(t, c.expected, m.Name)
+			}
+		})
+	}
+}
+
+func TestDetectDrift_NoMetric(t *testing.T) {
+	sched := &redskyv1beta1.ExperimentSchedule{}
+	sched.Spec.DriftDetection = &redskyv1beta1.DriftDetection{}
+	sched.Spec.ExperimentTemplate.Spec.Metrics = []redskyv1beta1.Metric{
+		{Name: "duration", Minimize: true, Query: "{{duration .StartTime .CompletionTime}}"},
+	}
+	expList := &redskyv1beta1.ExperimentList{Items: []redskyv1beta1.Experiment{{}}}
+
+	r := &ExperimentScheduleReconciler{}
+	result, err := r.detectDrift(context.TODO(), ctrl.Log, sched, expList, time.Now())
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Nil(t, sched.Status.Drift)
+}
@@ -20,12 +20,15 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/go-logr/logr"
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	"github.com/redskyops/redskyops-controller/internal/controller"
+	"github.com/redskyops/redskyops-controller/internal/experiment"
 	"github.com/redskyops/redskyops-controller/internal/meta"
 	"github.com/redskyops/redskyops-controller/internal/metric"
+	"github.com/redskyops/redskyops-controller/internal/template"
 	"github.com/redskyops/redskyops-controller/internal/trial"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,17 +37,31 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const (
+	// metricsDegradedThreshold is how long metric backend failures must persist before new trials are stopped
+	metricsDegradedThreshold = 2 * time.Minute
+	// metricsDegradedRetry is how long to wait before trying a failed metric backend again
+	metricsDegradedRetry = 15 * time.Second
+	// metricsDegradedTimeout is how long the experiment can be degraded before metric backend failures start counting
+	// against the remaining attempts, eventually failing the trial
+	metricsDegradedTimeout = 4 * metricsDegradedThreshold
+)
+
 // MetricReconciler reconciles the metrics on a Trial object
 type MetricReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+	// Backoff controls how failed reconciles are retried
+	Backoff controller.Backoff
 }
 
-// +kubebuilder:rbac:groups=redskyops.dev,resources=experiments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=redskyops.dev,resources=experiments,verbs=get;list;watch;update
 // +kubebuilder:rbac:groups=redskyops.dev,resources=trials,verbs=get;list;watch;update
 // +kubebuilder:rbac:groups="",resources=pods,verbs=list
 // +kubebuilder:rbac:groups="",resources=services,verbs=list
+// +kubebuilder:rbac:groups="",resources=events,verbs=list
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=list
 
 func (r *MetricReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
@@ -70,7 +87,7 @@ func (r *MetricReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("metric").
 		For(&redskyv1beta1.Trial{}).
-		Complete(r)
+		Complete(r.Backoff.Reconciler(r, r.Log))
 }
 
 func (r *MetricReconciler) ignoreTrial(t *redskyv1beta1.Trial) bool {
@@ -107,27 +124,27 @@ func (r *MetricReconciler) ignoreTrial(t *redskyv1beta1.Trial) bool {
 }
 
 func (r *MetricReconciler) evaluateMetrics(ctx context.Context, t *redskyv1beta1.Trial, probeTime *metav1.Time) (*ctrl.Result, error) {
-	// TODO This check precludes manual additions of Values
-	if len(t.Spec.Values) > 0 {
-		return nil, nil
-	}
-
 	// Get the experiment
 	exp := &redskyv1beta1.Experiment{}
 	if err := r.Get(ctx, t.ExperimentNamespacedName(), exp); err != nil {
 		return &ctrl.Result{}, err
 	}
 
-	// Evaluate the metrics
+	// Evaluate the metrics, preserving existing values (e.g. manual additions or progress made before a restart)
+	var added bool
 	for _, m := range exp.Spec.Metrics {
+		if hasValue(t, m.Name) {
+			continue
+		}
 		t.Spec.Values = append(t.Spec.Values, redskyv1beta1.Value{
 			Name:              m.Name,
 			AttemptsRemaining: 3,
 		})
+		added = true
 	}
 
 	// Update the status to indicate that we will be collecting metrics
-	if len(t.Spec.Values) > 0 {
+	if added {
 		trial.ApplyCondition(&t.Status, redskyv1beta1.TrialObserved, corev1.ConditionUnknown, "", "", probeTime)
 		err := r.Update(ctx, t)
 		return controller.RequeueConflict(err)
@@ -155,26 +172,61 @@ func (r *MetricReconciler) collectMetrics(ctx context.Context, t *redskyv1beta1.
 			continue
 		}
 
+		// Derived metrics must wait for the metrics they are derived from
+		if metrics[v.Name].Type == redskyv1beta1.MetricCEL && collectingMetrics(t, metrics) {
+			continue
+		}
+
+		// Honor a previously recorded retry time, this may have been recorded before the manager restarted
+		mc := metricCollection(t, v.Name)
+		if mc.NextAttemptTime != nil && probeTime.Before(mc.NextAttemptTime) {
+			return &ctrl.Result{RequeueAfter: mc.NextAttemptTime.Sub(probeTime.Time)}, nil
+		}
+		mc.LastAttemptTime = probeTime
+		mc.NextAttemptTime = nil
+
 		// Capture the metric
 		var captureError error
-		if target, err := r.target(ctx, t.Namespace, metrics[v.Name]); err != nil {
+		var captured bool
+		if target, err := metricTarget(ctx, r, t.Namespace, metrics[v.Name]); err != nil {
 			captureError = err
 		} else if value, stddev, err := metric.CaptureMetric(metrics[v.Name], t, target); err != nil {
 			if merr, ok := err.(*metric.CaptureError); ok && merr.RetryAfter > 0 {
 				// Do not count retries against the remaining attempts
+				if err := r.recordRetry(ctx, t, mc, merr, merr.RetryAfter, probeTime); err != nil {
+					return controller.RequeueConflict(err)
+				}
 				return &ctrl.Result{RequeueAfter: merr.RetryAfter}, nil
 			}
+			if merr, ok := err.(*metric.CaptureError); ok && merr.Unavailable && !metricsDegradedExpired(exp, probeTime) {
+				// Do not fail the trial because the backend is failing, apply backpressure to the experiment instead
+				log.Info("Metric backend unavailable", "address", merr.Address, "message", merr.Message)
+				if err := r.recordRetry(ctx, t, mc, merr, metricsDegradedRetry, probeTime); err != nil {
+					return controller.RequeueConflict(err)
+				}
+				return r.metricsDegraded(ctx, exp, merr, probeTime)
+			}
+			captureError = err
+		} else if ok, err := metric.CheckGuard(metrics[v.Name], t, value, stddev); err != nil {
 			captureError = err
 		} else {
+			captured = true
+			mc.Message = ""
 			v.AttemptsRemaining = 0
 			v.Value = strconv.FormatFloat(value, 'f', -1, 64)
 			if stddev != 0 {
 				v.Error = strconv.FormatFloat(stddev, 'f', -1, 64)
 			}
+			if !ok {
+				msg := fmt.Sprintf("Metric %s violated guard: %s", v.Name, metrics[v.Name].Guard)
+				trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, "GuardFailed", msg, probeTime)
+			}
 		}
 
 		// Handle any errors the occurred while collecting the value
 		if captureError != nil && v.AttemptsRemaining > 0 {
+			mc.Attempts++
+			mc.Message = captureError.Error()
 			v.AttemptsRemaining = v.AttemptsRemaining - 1
 			if v.AttemptsRemaining == 0 {
 				trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, "MetricFailed", captureError.Error(), probeTime)
@@ -187,17 +239,128 @@ func (r *MetricReconciler) collectMetrics(ctx context.Context, t *redskyv1beta1.
 
 		// We have started collecting metrics (success or fail), transition into a false status
 		trial.ApplyCondition(&t.Status, redskyv1beta1.TrialObserved, corev1.ConditionFalse, "", "", probeTime)
-		err := r.Update(ctx, t)
-		return controller.RequeueConflict(err)
+		if err := r.Update(ctx, t); err != nil {
+			return controller.RequeueConflict(err)
+		}
+
+		// A successful capture means the metric backend is working
+		if captured {
+			return r.metricsRecovered(ctx, exp, probeTime)
+		}
+		return &ctrl.Result{}, nil
 	}
 
-	// We made it through all of the metrics without needing additional changes
+	// We made it through all of the metrics without needing additional changes, account for the trial resources
+	if err := r.accountResources(ctx, t, exp); err != nil {
+		return &ctrl.Result{}, err
+	}
 	trial.ApplyCondition(&t.Status, redskyv1beta1.TrialObserved, corev1.ConditionTrue, "", "", probeTime)
 	err := r.Update(ctx, t)
 	return controller.RequeueConflict(err)
 }
 
-func (r *MetricReconciler) target(ctx context.Context, namespace string, m *redskyv1beta1.Metric) (runtime.Object, error) {
+// accountResources records the resource hours consumed by the trial workloads when the experiment has a cost model
+func (r *MetricReconciler) accountResources(ctx context.Context, t *redskyv1beta1.Trial, exp *redskyv1beta1.Experiment) error {
+	if exp.Spec.CostModel == nil {
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	if sel, err := meta.MatchingSelector(exp.Spec.CostModel.Selector); err != nil {
+		return err
+	} else if err := r.List(ctx, pods, client.InNamespace(t.Namespace), sel); err != nil {
+		return err
+	}
+
+	// An invalid cost model should not prevent the trial from finishing
+	if err := trial.UpdateResourceHours(t, pods, exp.Spec.CostModel); err != nil {
+		r.Log.Error(err, "Resource accounting failed", "trial", fmt.Sprintf("%s/%s", t.Namespace, t.Name))
+	}
+	return nil
+}
+
+// recordRetry records when collection of a metric should be attempted again so the delay is honored across restarts
+func (r *MetricReconciler) recordRetry(ctx context.Context, t *redskyv1beta1.Trial, mc *redskyv1beta1.MetricCollection, merr *metric.CaptureError, retryAfter time.Duration, probeTime *metav1.Time) error {
+	nextAttemptTime := metav1.NewTime(probeTime.Add(retryAfter))
+	mc.NextAttemptTime = &nextAttemptTime
+	mc.Message = merr.Error()
+	return r.Update(ctx, t)
+}
+
+// metricsDegraded records a metric backend failure on the experiment; if failures persist the experiment is marked as
+// degraded and new trials will not be started until the backend recovers
+func (r *MetricReconciler) metricsDegraded(ctx context.Context, exp *redskyv1beta1.Experiment, merr *metric.CaptureError, probeTime *metav1.Time) (*ctrl.Result, error) {
+	status := corev1.ConditionUnknown
+	if c := experiment.GetCondition(&exp.Status, redskyv1beta1.ExperimentMetricsDegraded); c != nil {
+		switch c.Status {
+		case corev1.ConditionTrue:
+			status = corev1.ConditionTrue
+		case corev1.ConditionUnknown:
+			if probeTime.Sub(c.LastTransitionTime.Time) >= metricsDegradedThreshold {
+				status = corev1.ConditionTrue
+			}
+		}
+	}
+
+	experiment.ApplyCondition(&exp.Status, redskyv1beta1.ExperimentMetricsDegraded, status, "MetricBackendUnavailable", merr.Error(), probeTime)
+	if err := r.Update(ctx, exp); err != nil {
+		return controller.RequeueConflict(err)
+	}
+	return &ctrl.Result{RequeueAfter: metricsDegradedRetry}, nil
+}
+
+// metricsDegradedExpired checks to see if the experiment has been degraded for too long to keep retrying the metric
+// backend without using the remaining attempts
+func metricsDegradedExpired(exp *redskyv1beta1.Experiment, probeTime *metav1.Time) bool {
+	c := experiment.GetCondition(&exp.Status, redskyv1beta1.ExperimentMetricsDegraded)
+	return c != nil && c.Status == corev1.ConditionTrue && probeTime.Sub(c.LastTransitionTime.Time) >= metricsDegradedTimeout
+}
+
+// metricsRecovered clears the degraded condition from the experiment
+func (r *MetricReconciler) metricsRecovered(ctx context.Context, exp *redskyv1beta1.Experiment, probeTime *metav1.Time) (*ctrl.Result, error) {
+	if c := experiment.GetCondition(&exp.Status, redskyv1beta1.ExperimentMetricsDegraded); c == nil || c.Status == corev1.ConditionFalse {
+		return &ctrl.Result{}, nil
+	}
+
+	experiment.ApplyCondition(&exp.Status, redskyv1beta1.ExperimentMetricsDegraded, corev1.ConditionFalse, "", "", probeTime)
+	err := r.Update(ctx, exp)
+	return controller.RequeueConflict(err)
+}
+
+// hasValue checks to see if the trial already has a value for the named metric
+func hasValue(t *redskyv1beta1.Trial, name string) bool {
+	for i := range t.Spec.Values {
+		if t.Spec.Values[i].Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// metricCollection returns the collection progress for the named metric, adding it to the trial status if necessary
+func metricCollection(t *redskyv1beta1.Trial, name string) *redskyv1beta1.MetricCollection {
+	for i := range t.Status.MetricCollection {
+		if t.Status.MetricCollection[i].Name == name {
+			return &t.Status.MetricCollection[i]
+		}
+	}
+	t.Status.MetricCollection = append(t.Status.MetricCollection, redskyv1beta1.MetricCollection{Name: name})
+	return &t.Status.MetricCollection[len(t.Status.MetricCollection)-1]
+}
+
+// collectingMetrics checks to see if any non-derived metric values are still being collected
+func collectingMetrics(t *redskyv1beta1.Trial, metrics map[string]*redskyv1beta1.Metric) bool {
+	for i := range t.Spec.Values {
+		v := &t.Spec.Values[i]
+		if v.AttemptsRemaining > 0 && metrics[v.Name].Type != redskyv1beta1.MetricCEL {
+			return true
+		}
+	}
+	return false
+}
+
+// metricTarget returns the object used to collect the supplied metric
+func metricTarget(ctx context.Context, r client.Reader, namespace string, m *redskyv1beta1.Metric) (runtime.Object, error) {
 	switch m.Type {
 	case redskyv1beta1.MetricPods:
 		// Use the selector to get a list of pods
@@ -208,6 +371,26 @@ func (r *MetricReconciler) target(ctx context.Context, namespace string, m *reds
 			return nil, err
 		}
 		return target, nil
+	case redskyv1beta1.MetricHPA:
+		// Use the selector to get a list of autoscalers and include the events involving them
+		target := &template.ScalingTarget{}
+		if sel, err := meta.MatchingSelector(m.Selector); err != nil {
+			return nil, err
+		} else if err := r.List(ctx, &target.HorizontalPodAutoscalers, client.InNamespace(namespace), sel); err != nil {
+			return nil, err
+		}
+		events := &corev1.EventList{}
+		if err := r.List(ctx, events, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		for _, e := range events.Items {
+			for _, hpa := range target.HorizontalPodAutoscalers.Items {
+				if e.InvolvedObject.Kind == "HorizontalPodAutoscaler" && e.InvolvedObject.UID == hpa.UID {
+					target.Events.Items = append(target.Events.Items, e)
+				}
+			}
+		}
+		return target, nil
 	case redskyv1beta1.MetricPrometheus, redskyv1beta1.MetricJSONPath:
 		// Both Prometheus and JSONPath target a service
 		target := &corev1.ServiceList{}
@@ -0,0 +1,299 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/experiment"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestMetricReconciler(t *testing.T) {
+	ns := testNamespace(t)
+	ctx := context.TODO()
+
+	r := &MetricReconciler{
+		Client: k8sClient,
+		Log:    ctrl.Log.WithName("test").WithName("Metric"),
+		Scheme: testScheme,
+	}
+
+	exp := &redskyv1beta1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Name: "metric", Namespace: ns},
+		Spec: redskyv1beta1.ExperimentSpec{
+			Parameters: []redskyv1beta1.Parameter{{Name: "x", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(10)}},
+			Metrics:    []redskyv1beta1.Metric{{Name: "duration", Query: "{{duration .StartTime .CompletionTime}}"}},
+		},
+	}
+	require.NoError(t, k8sClient.Create(ctx, exp))
+
+	tr := &redskyv1beta1.Trial{}
+	experiment.PopulateTrialFromTemplate(exp, tr)
+	tr.Namespace = ns
+	tr.Spec.Assignments = []redskyv1beta1.Assignment{{Name: "x", Value: redskyv1beta1.NewAssignmentValue(5)}}
+	require.NoError(t, k8sClient.Create(ctx, tr))
+	key := types.NamespacedName{Namespace: ns, Name: tr.Name}
+
+	// Simulate a finished trial run
+	start := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	completion := metav1.NewTime(start.Add(10 * time.Second))
+	tr.Status.StartTime = &start
+	tr.Status.CompletionTime = &completion
+	require.NoError(t, k8sClient.Update(ctx, tr))
+
+	// The metric is collected once the trial is observed
+	require.NoError(t, reconcileUntil(r, key, func() (bool, error) {
+		err := k8sClient.Get(ctx, key, tr)
+		return trial.CheckCondition(&tr.Status, redskyv1beta1.TrialObserved, corev1.ConditionTrue), err
+	}))
+	require.Len(t, tr.Spec.Values, 1)
+	assert.Equal(t, "10", tr.Spec.Values[0].Value)
+}
+
+func TestMetricReconciler_Restart(t *testing.T) {
+	ns := testNamespace(t)
+	ctx := context.TODO()
+
+	exp := &redskyv1beta1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Name: "metric-restart", Namespace: ns},
+		Spec: redskyv1beta1.ExperimentSpec{
+			Parameters: []redskyv1beta1.Parameter{{Name: "x", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(10)}},
+			Metrics: []redskyv1beta1.Metric{
+				{Name: "one", Query: "1"},
+				{Name: "duration", Query: "{{duration .StartTime .CompletionTime}}"},
+			},
+		},
+	}
+	require.NoError(t, k8sClient.Create(ctx, exp))
+
+	start := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	completion := metav1.NewTime(start.Add(10 * time.Second))
+	nextAttemptTime := metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+
+	cases := []struct {
+		desc             string
+		values           []redskyv1beta1.Value
+		metricCollection []redskyv1beta1.MetricCollection
+		expectedValues   []string
+		expectedRequeue  bool
+	}{
+		{
+			desc: "partially collected",
+			values: []redskyv1beta1.Value{
+				{Name: "one", Value: "1"},
+				{Name: "duration", AttemptsRemaining: 2},
+			},
+			metricCollection: []redskyv1beta1.MetricCollection{
+				{Name: "duration", Attempts: 1, Message: "connection refused"},
+			},
+			expectedValues: []string{"1", "10"},
+		},
+		{
+			desc: "manually added value",
+			values: []redskyv1beta1.Value{
+				{Name: "one", Value: "2"},
+			},
+			expectedValues: []string{"2", "10"},
+		},
+		{
+			desc: "pending retry",
+			values: []redskyv1beta1.Value{
+				{Name: "one", AttemptsRemaining: 3},
+				{Name: "duration", AttemptsRemaining: 3},
+			},
+			metricCollection: []redskyv1beta1.MetricCollection{
+				{Name: "one", NextAttemptTime: &nextAttemptTime},
+			},
+			expectedValues:  []string{"", ""},
+			expectedRequeue: true,
+		},
+	}
+	for i, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			// Every case uses a new reconciler to simulate a manager restart
+			r := &MetricReconciler{
+				Client: k8sClient,
+				Log:    ctrl.Log.WithName("test").WithName("Metric"),
+				Scheme: testScheme,
+			}
+
+			tr := &redskyv1beta1.Trial{}
+			experiment.PopulateTrialFromTemplate(exp, tr)
+			tr.Name = fmt.Sprintf("restart-%d", i)
+			tr.Namespace = ns
+			tr.Spec.Assignments = []redskyv1beta1.Assignment{{Name: "x", Value: redskyv1beta1.NewAssignmentValue(5)}}
+			tr.Spec.Values = c.values
+			require.NoError(t, k8sClient.Create(ctx, tr))
+			key := types.NamespacedName{Namespace: ns, Name: tr.Name}
+
+			tr.Status.StartTime = &start
+			tr.Status.CompletionTime = &completion
+			tr.Status.MetricCollection = c.metricCollection
+			trial.ApplyCondition(&tr.Status, redskyv1beta1.TrialObserved, corev1.ConditionFalse, "", "", nil)
+			require.NoError(t, k8sClient.Update(ctx, tr))
+
+			if c.expectedRequeue {
+				result, err := r.Reconcile(ctrl.Request{NamespacedName: key})
+				require.NoError(t, err)
+				assert.True(t, result.RequeueAfter > 0)
+			} else {
+				require.NoError(t, reconcileUntil(r, key, func() (bool, error) {
+					err := k8sClient.Get(ctx, key, tr)
+					return trial.CheckCondition(&tr.Status, redskyv1beta1.TrialObserved, corev1.ConditionTrue), err
+				}))
+			}
+
+			require.NoError(t, k8sClient.Get(ctx, key, tr))
+			var actualValues []string
+			for _, v := range tr.Spec.Values {
+				actualValues = append(actualValues, v.Value)
+			}
+			assert.Equal(t, c.expectedValues, actualValues)
+		})
+	}
+}
+
+func TestMetricReconciler_MetricsDegraded(t *testing.T) {
+	ns := testNamespace(t)
+	ctx := context.TODO()
+
+	// The Prometheus server fails until it is marked as available
+	var available int32
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if atomic.LoadInt32(&available) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		switch req.URL.Path {
+		case "/api/v1/targets":
+			_, _ = fmt.Fprint(w, `{"status":"success","data":{"activeTargets":[],"droppedTargets":[]}}`)
+		default:
+			_, _ = fmt.Fprint(w, `{"status":"success","data":{"resultType":"scalar","result":[1595471900.283,"10"]}}`)
+		}
+	}))
+	defer prometheus.Close()
+
+	r := &MetricReconciler{
+		Client: k8sClient,
+		Log:    ctrl.Log.WithName("test").WithName("Metric"),
+		Scheme: testScheme,
+	}
+
+	exp := &redskyv1beta1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Name: "metric-degraded", Namespace: ns},
+		Spec: redskyv1beta1.ExperimentSpec{
+			Parameters: []redskyv1beta1.Parameter{{Name: "x", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(10)}},
+			Metrics: []redskyv1beta1.Metric{
+				{Name: "latency", Type: redskyv1beta1.MetricPrometheus, URL: prometheus.URL, Query: "scalar(latency)"},
+			},
+		},
+	}
+	require.NoError(t, k8sClient.Create(ctx, exp))
+	expKey := types.NamespacedName{Namespace: ns, Name: exp.Name}
+
+	tr := &redskyv1beta1.Trial{}
+	experiment.PopulateTrialFromTemplate(exp, tr)
+	tr.Namespace = ns
+	tr.Spec.Assignments = []redskyv1beta1.Assignment{{Name: "x", Value: redskyv1beta1.NewAssignmentValue(5)}}
+	require.NoError(t, k8sClient.Create(ctx, tr))
+	key := types.NamespacedName{Namespace: ns, Name: tr.Name}
+
+	start := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+	completion := metav1.NewTime(start.Add(10 * time.Second))
+	tr.Status.StartTime = &start
+	tr.Status.CompletionTime = &completion
+	require.NoError(t, k8sClient.Update(ctx, tr))
+
+	// A failing backend marks the experiment as degraded without using any of the remaining attempts
+	require.NoError(t, reconcileUntil(r, key, func() (bool, error) {
+		err := k8sClient.Get(ctx, expKey, exp)
+		return experiment.GetCondition(&exp.Status, redskyv1beta1.ExperimentMetricsDegraded) != nil, err
+	}))
+	assert.True(t, experiment.CheckCondition(&exp.Status, redskyv1beta1.ExperimentMetricsDegraded, corev1.ConditionUnknown))
+	require.NoError(t, k8sClient.Get(ctx, key, tr))
+	require.Len(t, tr.Spec.Values, 1)
+	assert.Equal(t, 3, tr.Spec.Values[0].AttemptsRemaining)
+	assert.False(t, trial.CheckCondition(&tr.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue))
+
+	// Failures which persist past the threshold mark the experiment as degraded
+	c := experiment.GetCondition(&exp.Status, redskyv1beta1.ExperimentMetricsDegraded)
+	c.LastTransitionTime = metav1.NewTime(c.LastTransitionTime.Add(-2 * metricsDegradedThreshold))
+	require.NoError(t, k8sClient.Update(ctx, exp))
+	tr.Status.MetricCollection = nil
+	require.NoError(t, k8sClient.Update(ctx, tr))
+	require.NoError(t, reconcileUntil(r, key, func() (bool, error) {
+		err := k8sClient.Get(ctx, expKey, exp)
+		return experiment.CheckCondition(&exp.Status, redskyv1beta1.ExperimentMetricsDegraded, corev1.ConditionTrue), err
+	}))
+
+	// Once the backend recovers the metric is collected and the condition is cleared
+	atomic.StoreInt32(&available, 1)
+	require.NoError(t, k8sClient.Get(ctx, key, tr))
+	tr.Status.MetricCollection = nil
+	require.NoError(t, k8sClient.Update(ctx, tr))
+	require.NoError(t, reconcileUntil(r, key, func() (bool, error) {
+		err := k8sClient.Get(ctx, key, tr)
+		return trial.CheckCondition(&tr.Status, redskyv1beta1.TrialObserved, corev1.ConditionTrue), err
+	}))
+	assert.Equal(t, "10", tr.Spec.Values[0].Value)
+	require.NoError(t, k8sClient.Get(ctx, expKey, exp))
+	assert.True(t, experiment.CheckCondition(&exp.Status, redskyv1beta1.ExperimentMetricsDegraded, corev1.ConditionFalse))
+}
+
+func TestMetricsDegradedExpired(t *testing.T) {
+	now := metav1.Now()
+	degraded := func(status corev1.ConditionStatus, d time.Duration) *redskyv1beta1.Experiment {
+		exp := &redskyv1beta1.Experiment{}
+		exp.Status.Conditions = []redskyv1beta1.ExperimentCondition{{
+			Type:               redskyv1beta1.ExperimentMetricsDegraded,
+			Status:             status,
+			LastTransitionTime: metav1.NewTime(now.Add(-d)),
+		}}
+		return exp
+	}
+
+	cases := []struct {
+		desc     string
+		exp      *redskyv1beta1.Experiment
+		expected bool
+	}{
+		{desc: "healthy", exp: &redskyv1beta1.Experiment{}},
+		{desc: "failing", exp: degraded(corev1.ConditionUnknown, 2*metricsDegradedTimeout)},
+		{desc: "recovered", exp: degraded(corev1.ConditionFalse, 2*metricsDegradedTimeout)},
+		{desc: "degraded", exp: degraded(corev1.ConditionTrue, metricsDegradedTimeout-time.Second)},
+		{desc: "expired", exp: degraded(corev1.ConditionTrue, metricsDegradedTimeout), expected: true},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.expected, metricsDegradedExpired(c.exp, &now))
+		})
+	}
+}
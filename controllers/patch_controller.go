@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
@@ -29,12 +30,14 @@ import (
 	"github.com/redskyops/redskyops-controller/internal/trial"
 	"github.com/redskyops/redskyops-controller/internal/validation"
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // PatchReconciler reconciles the patches on a Trial object
@@ -42,10 +45,18 @@ type PatchReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+	// Backoff controls how failed reconciles are retried
+	Backoff controller.Backoff
+
+	// Keep the raw API reader for reading the config maps holding compacted patches and listing nodes, we do not have
+	// watch permission on either and the caching reader would hang waiting for an informer that cannot sync
+	apiReader client.Reader
 }
 
-// +kubebuilder:rbac:groups=redskyops.dev,resources=experiments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=redskyops.dev,resources=experiments,verbs=get;list;watch;update
 // +kubebuilder:rbac:groups=redskyops.dev,resources=trials,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;create;update
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=list
 
 // Reconcile inspects a trial to see if patches need to be applied. The "trial patched" status condition
 // is used to control what actions need to be taken. If the status is "unknown" then the experiment is fetched
@@ -61,6 +72,10 @@ func (r *PatchReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, controller.IgnoreNotFound(err)
 	}
 
+	if result, err := r.waitForQueue(ctx, t); result != nil {
+		return *result, err
+	}
+
 	if result, err := r.evaluatePatchOperations(ctx, t, &now); result != nil {
 		return *result, err
 	}
@@ -74,10 +89,11 @@ func (r *PatchReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 
 // SetupWithManager registers a new patch reconciler with the supplied manager
 func (r *PatchReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.apiReader = mgr.GetAPIReader()
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("patch").
 		For(&redskyv1beta1.Trial{}).
-		Complete(r)
+		Complete(r.Backoff.Reconciler(r, r.Log))
 }
 
 // ignoreTrial determines which trial objects can be ignored by this reconciler
@@ -112,6 +128,32 @@ func (r *PatchReconciler) ignoreTrial(t *redskyv1beta1.Trial) bool {
 	return false
 }
 
+// waitForQueue defers the start of a trial while pending trials of the same experiment have a higher priority
+func (r *PatchReconciler) waitForQueue(ctx context.Context, t *redskyv1beta1.Trial) (*ctrl.Result, error) {
+	// Only wait if we have not started evaluating patches
+	if !trial.CheckCondition(&t.Status, redskyv1beta1.TrialPatched, corev1.ConditionUnknown) {
+		return nil, nil
+	}
+
+	expName := t.ExperimentNamespacedName()
+	trialList := &redskyv1beta1.TrialList{}
+	if err := r.List(ctx, trialList, client.MatchingLabels{redskyv1beta1.LabelExperiment: expName.Name}); err != nil {
+		return &ctrl.Result{}, err
+	}
+
+	priority := trial.Priority(t)
+	for i := range trialList.Items {
+		pt := &trialList.Items[i]
+		if pt.UID == t.UID || pt.ExperimentNamespacedName() != expName || !trial.IsPending(pt) {
+			continue
+		}
+		if trial.Priority(pt) > priority {
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+	}
+	return nil, nil
+}
+
 // evaluatePatchOperations will render the patch templates from the experiment using the trial assignments to create "patch operations" on the trial
 func (r *PatchReconciler) evaluatePatchOperations(ctx context.Context, t *redskyv1beta1.Trial, probeTime *metav1.Time) (*ctrl.Result, error) {
 	// Only evaluate patches if the "patched" status is "unknown"
@@ -125,15 +167,36 @@ func (r *PatchReconciler) evaluatePatchOperations(ctx context.Context, t *redsky
 		return &ctrl.Result{}, err
 	}
 
-	// Make sure the assignments are valid
+	// Make sure the assignments are valid, invalid assignments (e.g. ones that violate a constraint) will never succeed
 	if err := validation.CheckAssignments(t, exp); err != nil {
-		return &ctrl.Result{}, err
+		trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, "InvalidAssignments", err.Error(), probeTime)
+		err := r.Update(ctx, t)
+		return controller.RequeueConflict(err)
+	}
+
+	// Resolve percentage parameters against the state of the cluster before the first trial
+	if err := r.resolveReferences(ctx, t, exp); err != nil {
+		return controller.RequeueConflict(err)
+	}
+
+	// Map parameters with a catalog to the nearest catalog entry
+	trial.ApplyCatalogs(exp, t)
+
+	// Allow the experiment to adjust the assignments before they are used
+	if result, err := r.transformAssignments(ctx, t, exp, probeTime); result != nil {
+		return result, err
 	}
 
 	// Readiness checks from patches should always be applied first
 	readinessChecks := t.Status.ReadinessChecks
 	t.Status.ReadinessChecks = nil
 
+	// Nodes are used to verify the topology keys of rendered patches, if they are not available the check is skipped
+	nodes := &corev1.NodeList{}
+	if err := r.apiReader.List(ctx, nodes); err != nil {
+		r.Log.Info("Unable to list nodes", "trial", t.Namespace+"/"+t.Name, "message", err.Error())
+	}
+
 	// Evaluate the patches
 	te := template.New()
 	for i := range exp.Spec.Patches {
@@ -145,6 +208,13 @@ func (r *PatchReconciler) evaluatePatchOperations(ctx context.Context, t *redsky
 			return &ctrl.Result{}, err
 		}
 
+		// Make sure the rendered patch can be scheduled, unschedulable assignments will never succeed
+		if err := validation.CheckScheduling(data, nodes.Items); err != nil {
+			trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, "Unschedulable", err.Error(), probeTime)
+			err := r.Update(ctx, t)
+			return controller.RequeueConflict(err)
+		}
+
 		// Add a patch operation if necessary
 		if po, err := r.createPatchOperation(t, p, ref, data); err != nil {
 			return &ctrl.Result{}, err
@@ -169,6 +239,87 @@ func (r *PatchReconciler) evaluatePatchOperations(ctx context.Context, t *redsky
 	return controller.RequeueConflict(err)
 }
 
+// resolveReferences records the concrete values of the percentage parameter assignments on the trial; the referenced
+// values are pinned on the experiment the first time they are read so trials do not compound earlier patches
+func (r *PatchReconciler) resolveReferences(ctx context.Context, t *redskyv1beta1.Trial, exp *redskyv1beta1.Experiment) error {
+	baseline, err := trial.ReferenceBaseline(exp)
+	if err != nil {
+		return err
+	}
+
+	pinned := false
+	t.Status.ResolvedAssignments = nil
+	for i := range exp.Spec.Parameters {
+		p := &exp.Spec.Parameters[i]
+		if p.Reference == nil || len(p.Values) > 0 {
+			continue
+		}
+
+		var percent int64
+		for j := range t.Spec.Assignments {
+			if t.Spec.Assignments[j].Name == p.Name {
+				percent, _ = t.Spec.Assignments[j].Int64Value()
+			}
+		}
+
+		value, ok := baseline[p.Name]
+		if !ok {
+			u := &unstructured.Unstructured{}
+			u.SetGroupVersionKind(p.Reference.TargetRef.GroupVersionKind())
+			key := types.NamespacedName{Name: p.Reference.TargetRef.Name, Namespace: p.Reference.TargetRef.Namespace}
+			if key.Namespace == "" {
+				key.Namespace = t.Namespace
+			}
+			if err := r.Get(ctx, key, u); err != nil {
+				return err
+			}
+
+			if value, err = trial.ReferencedValue(p.Reference, u); err != nil {
+				return fmt.Errorf("unable to resolve reference for parameter %s: %w", p.Name, err)
+			}
+			baseline[p.Name] = value
+			pinned = true
+		}
+
+		v, err := trial.ResolveReference(value, percent)
+		if err != nil {
+			return fmt.Errorf("unable to resolve reference for parameter %s: %w", p.Name, err)
+		}
+		t.Status.ResolvedAssignments = append(t.Status.ResolvedAssignments, redskyv1beta1.Assignment{Name: p.Name, Value: v})
+	}
+
+	// Only update the experiment when a new value was read
+	if !pinned {
+		return nil
+	}
+	if err := trial.SetReferenceBaseline(exp, baseline); err != nil {
+		return err
+	}
+	return r.Update(ctx, exp)
+}
+
+// transformAssignments calls the assignment webhook of the experiment, if there is one
+func (r *PatchReconciler) transformAssignments(ctx context.Context, t *redskyv1beta1.Trial, exp *redskyv1beta1.Experiment, probeTime *metav1.Time) (*ctrl.Result, error) {
+	hook := exp.Spec.AssignmentWebhook
+	if hook == nil {
+		return nil, nil
+	}
+
+	err := trial.CallAssignmentWebhook(ctx, hook, t)
+	if err == nil {
+		return nil, nil
+	}
+
+	if hook.FailurePolicy == redskyv1beta1.IgnoreWebhook {
+		r.Log.Info("Ignoring assignment webhook failure", "trial", t.Namespace+"/"+t.Name, "message", err.Error())
+		return nil, nil
+	}
+
+	trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, "AssignmentWebhookFailed", err.Error(), probeTime)
+	err = r.Update(ctx, t)
+	return controller.RequeueConflict(err)
+}
+
 // applyPatches will actually patch the objects from the patch operations
 func (r *PatchReconciler) applyPatches(ctx context.Context, t *redskyv1beta1.Trial, probeTime *metav1.Time) (*ctrl.Result, error) {
 	// Only apply patches if the "patched" status is "false"
@@ -189,7 +340,11 @@ func (r *PatchReconciler) applyPatches(ctx context.Context, t *redskyv1beta1.Tri
 		u.SetName(p.TargetRef.Name)
 		u.SetNamespace(p.TargetRef.Namespace)
 		u.SetGroupVersionKind(p.TargetRef.GroupVersionKind())
-		if err := r.Patch(ctx, u, client.RawPatch(p.PatchType, p.Data)); err != nil {
+		data, err := trial.PatchData(ctx, r.apiReader, t, p)
+		if err == nil {
+			err = r.Patch(ctx, u, client.RawPatch(p.PatchType, data))
+		}
+		if err != nil {
 			p.AttemptsRemaining = p.AttemptsRemaining - 1
 			if p.AttemptsRemaining == 0 {
 				// There are no remaining patch attempts remaining, fail the trial
@@ -200,16 +355,95 @@ func (r *PatchReconciler) applyPatches(ctx context.Context, t *redskyv1beta1.Tri
 		}
 
 		// Update the patch operation status
-		err := r.Update(ctx, t)
+		err = r.Update(ctx, t)
 		return controller.RequeueConflict(err)
 	}
 
+	// Record the patched objects so the trial can be reproduced later, a missing snapshot does not fail the trial
+	if err := r.snapshot(ctx, t); err != nil {
+		r.Log.Info("Unable to record trial snapshot", "trial", t.Namespace+"/"+t.Name, "message", err.Error())
+	}
+
+	// Move large patches out of the trial, if that fails the patch data just stays on the trial
+	if err := r.compactPatches(ctx, t); err != nil {
+		r.Log.Info("Unable to compact trial patches", "trial", t.Namespace+"/"+t.Name, "message", err.Error())
+	}
+
 	// We made it through all of the patches without needing additional changes
 	trial.ApplyCondition(&t.Status, redskyv1beta1.TrialPatched, corev1.ConditionTrue, "", "", probeTime)
 	err := r.Update(ctx, t)
 	return controller.RequeueConflict(err)
 }
 
+// snapshot stores the current state of the patch targets in a config map owned by the trial
+func (r *PatchReconciler) snapshot(ctx context.Context, t *redskyv1beta1.Trial) error {
+	var objs []*unstructured.Unstructured
+	seen := make(map[corev1.ObjectReference]bool, len(t.Status.PatchOperations))
+	for i := range t.Status.PatchOperations {
+		ref := t.Status.PatchOperations[i].TargetRef
+		if seen[ref] || trial.IsTrialJobReference(t, &ref) {
+			continue
+		}
+		seen[ref] = true
+
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(ref.GroupVersionKind())
+		if err := r.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, u); err != nil {
+			return err
+		}
+		objs = append(objs, u)
+	}
+	if len(objs) == 0 {
+		return nil
+	}
+
+	cm, err := trial.NewSnapshot(t, objs)
+	if err != nil {
+		return err
+	}
+	if err := controllerutil.SetControllerReference(t, cm, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, cm); err != nil && !apierrs.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// compactPatches stores the data of large applied patches in a config map owned by the trial
+func (r *PatchReconciler) compactPatches(ctx context.Context, t *redskyv1beta1.Trial) error {
+	compacted := t.DeepCopy()
+	cm := trial.NewPatchArtifacts(compacted)
+	if cm == nil {
+		return nil
+	}
+	if err := controllerutil.SetControllerReference(t, cm, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.Create(ctx, cm); apierrs.IsAlreadyExists(err) {
+		// Merge into the config map left by a previous compaction (or an update that failed with a conflict)
+		existing := &corev1.ConfigMap{}
+		if err := r.apiReader.Get(ctx, client.ObjectKey{Namespace: cm.Namespace, Name: cm.Name}, existing); err != nil {
+			return err
+		}
+		if existing.Data == nil {
+			existing.Data = make(map[string]string, len(cm.Data))
+		}
+		for k, v := range cm.Data {
+			existing.Data[k] = v
+		}
+		if err := r.Update(ctx, existing); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	// Only drop the inline data once it is safely stored in the config map
+	t.Status.PatchOperations = compacted.Status.PatchOperations
+	return nil
+}
+
 // renderTemplate determines the patch target and renders the patch template
 func (r *PatchReconciler) renderTemplate(te *template.Engine, t *redskyv1beta1.Trial, p *redskyv1beta1.PatchTemplate) (*corev1.ObjectReference, []byte, error) {
 	// Render the actual patch data
@@ -273,6 +507,22 @@ func (r *PatchReconciler) createPatchOperation(t *redskyv1beta1.Trial, p *redsky
 		return nil, fmt.Errorf("unknown patch type: %s", p.Type)
 	}
 
+	// StatefulSet patches cannot change persistent volumes and may be restricted to a subset of the pods
+	if trial.IsStatefulSetReference(&po.TargetRef) {
+		if err := trial.CheckStatefulSetPatch(po.PatchType, po.Data); err != nil {
+			return nil, err
+		}
+		if p.Partition != nil {
+			data, err := trial.PartitionStatefulSetPatch(po.PatchType, po.Data, *p.Partition)
+			if err != nil {
+				return nil, err
+			}
+			po.Data = data
+		}
+	} else if p.Partition != nil {
+		return nil, fmt.Errorf("patch partition is only supported for stateful sets")
+	}
+
 	// If the patch is for the trial job itself, it cannot be applied (since the job won't exist until well after patches are applied)
 	if trial.IsTrialJobReference(t, &po.TargetRef) {
 		po.AttemptsRemaining = 0
@@ -302,7 +552,12 @@ func (r *PatchReconciler) createReadinessCheck(t *redskyv1beta1.Trial, p *redsky
 
 	// Add configured and default readiness conditions
 	for i := range p.ReadinessGates {
-		rc.ConditionTypes = append(rc.ConditionTypes, p.ReadinessGates[i].ConditionType)
+		if p.ReadinessGates[i].ConditionType != "" {
+			rc.ConditionTypes = append(rc.ConditionTypes, p.ReadinessGates[i].ConditionType)
+		}
+		if p.ReadinessGates[i].JSONPath != "" || p.ReadinessGates[i].CEL != "" {
+			rc.Expressions = append(rc.Expressions, p.ReadinessGates[i].ReadinessExpression)
+		}
 	}
 
 	// Check for a "legacy" patch that has no explicit (not even empty) readiness gates and apply settings consistent
@@ -312,8 +567,8 @@ func (r *PatchReconciler) createReadinessCheck(t *redskyv1beta1.Trial, p *redsky
 		rc.InitialDelaySeconds = 1
 	}
 
-	// If there are no conditions to check, we do not need to add a readiness check
-	if len(rc.ConditionTypes) == 0 {
+	// If there are no conditions or expressions to check, we do not need to add a readiness check
+	if len(rc.ConditionTypes) == 0 && len(rc.Expressions) == 0 {
 		return nil, nil
 	}
 	return rc, nil
@@ -0,0 +1,144 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/experiment"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestPatchReconciler(t *testing.T) {
+	ns := testNamespace(t)
+	ctx := context.TODO()
+
+	r := &PatchReconciler{
+		Client:    k8sClient,
+		Log:       ctrl.Log.WithName("test").WithName("Patch"),
+		Scheme:    testScheme,
+		apiReader: k8sClient,
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: ns},
+		Data:       map[string]string{"x": "1"},
+	}
+	require.NoError(t, k8sClient.Create(ctx, cm))
+
+	exp := &redskyv1beta1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Name: "patch", Namespace: ns},
+		Spec: redskyv1beta1.ExperimentSpec{
+			Parameters: []redskyv1beta1.Parameter{{Name: "x", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(10)}},
+			Metrics:    []redskyv1beta1.Metric{{Name: "m"}},
+			Patches: []redskyv1beta1.PatchTemplate{
+				{
+					Patch:     `{"data":{"x":"{{ .Values.x }}"}}`,
+					TargetRef: &corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "app", Namespace: ns},
+				},
+			},
+		},
+	}
+	require.NoError(t, k8sClient.Create(ctx, exp))
+
+	tr := &redskyv1beta1.Trial{}
+	experiment.PopulateTrialFromTemplate(exp, tr)
+	tr.Namespace = ns
+	tr.Spec.Assignments = []redskyv1beta1.Assignment{{Name: "x", Value: redskyv1beta1.NewAssignmentValue(5)}}
+	require.NoError(t, k8sClient.Create(ctx, tr))
+	key := types.NamespacedName{Namespace: ns, Name: tr.Name}
+
+	// The patch is rendered and applied to the config map
+	require.NoError(t, reconcileUntil(r, key, func() (bool, error) {
+		err := k8sClient.Get(ctx, key, tr)
+		return trial.CheckCondition(&tr.Status, redskyv1beta1.TrialPatched, corev1.ConditionTrue), err
+	}))
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: ns, Name: cm.Name}, cm))
+	assert.Equal(t, "5", cm.Data["x"])
+}
+
+func TestPatchReconciler_References(t *testing.T) {
+	ns := testNamespace(t)
+	ctx := context.TODO()
+
+	r := &PatchReconciler{
+		Client:    k8sClient,
+		Log:       ctrl.Log.WithName("test").WithName("Patch"),
+		Scheme:    testScheme,
+		apiReader: k8sClient,
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: ns},
+		Data:       map[string]string{"memory": "1Gi"},
+	}
+	require.NoError(t, k8sClient.Create(ctx, cm))
+
+	exp := &redskyv1beta1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Name: "reference", Namespace: ns},
+		Spec: redskyv1beta1.ExperimentSpec{
+			Parameters: []redskyv1beta1.Parameter{
+				{
+					Name: "memory",
+					Min:  redskyv1beta1.NewParameterBound(25),
+					Max:  redskyv1beta1.NewParameterBound(200),
+					Reference: &redskyv1beta1.ParameterReference{
+						TargetRef: corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "app"},
+						FieldPath: "{.data.memory}",
+					},
+				},
+			},
+			Metrics: []redskyv1beta1.Metric{{Name: "m"}},
+			Patches: []redskyv1beta1.PatchTemplate{
+				{
+					Patch:     `{"data":{"memory":"{{ .Values.memory }}"}}`,
+					TargetRef: &corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "app", Namespace: ns},
+				},
+			},
+		},
+	}
+	require.NoError(t, k8sClient.Create(ctx, exp))
+
+	// Each trial is relative to the original value, not the value left behind by the previous trial
+	for _, name := range []string{"reference-000", "reference-001"} {
+		tr := &redskyv1beta1.Trial{}
+		experiment.PopulateTrialFromTemplate(exp, tr)
+		tr.Name = name
+		tr.Namespace = ns
+		tr.Spec.Assignments = []redskyv1beta1.Assignment{{Name: "memory", Value: redskyv1beta1.NewAssignmentValue(50)}}
+		require.NoError(t, k8sClient.Create(ctx, tr))
+		key := types.NamespacedName{Namespace: ns, Name: tr.Name}
+
+		require.NoError(t, reconcileUntil(r, key, func() (bool, error) {
+			err := k8sClient.Get(ctx, key, tr)
+			return trial.CheckCondition(&tr.Status, redskyv1beta1.TrialPatched, corev1.ConditionTrue), err
+		}))
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: ns, Name: cm.Name}, cm))
+		assert.Equal(t, "512Mi", cm.Data["memory"])
+	}
+
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: ns, Name: exp.Name}, exp))
+	assert.JSONEq(t, `{"memory":"1Gi"}`, exp.Annotations[redskyv1beta1.AnnotationReferenceBaseline])
+}
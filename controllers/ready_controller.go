@@ -39,6 +39,8 @@ type ReadyReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+	// Backoff controls how failed reconciles are retried
+	Backoff controller.Backoff
 
 	// Keep the raw API reader for doing stabilization checks. In that case we only have patch/get permissions
 	// on the object and if we were to use the standard caching reader we would hang because cache itself also
@@ -77,7 +79,7 @@ func (r *ReadyReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("ready").
 		For(&redskyv1beta1.Trial{}).
-		Complete(r)
+		Complete(r.Backoff.Reconciler(r, r.Log))
 }
 
 // ignoreTrial determines which trial objects can be ignored by this reconciler
@@ -265,7 +267,7 @@ type readinessChecker struct {
 
 // newReadinessChecker returns a new checker for the supplied trial
 func newReadinessChecker(reader client.Reader, t *redskyv1beta1.Trial) *readinessChecker {
-	checker := ready.ReadinessChecker{Reader: reader}
+	checker := ready.ReadinessChecker{Reader: reader, ScaleUpTimeout: trial.ScaleUpTimeout(t)}
 	epoch := t.GetCreationTimestamp()
 	for i := range t.Status.Conditions {
 		if t.Status.Conditions[i].Type == redskyv1beta1.TrialPatched {
@@ -320,6 +322,10 @@ func (rc *readinessChecker) check(ctx context.Context, c *redskyv1beta1.Readines
 		if !ok || err != nil {
 			break
 		}
+		msg, ok, err = rc.checker.CheckExpressions(&ul.Items[i], c.Expressions)
+		if !ok || err != nil {
+			break
+		}
 	}
 
 	// If a check is missing it's kind, just mark it as completed
@@ -334,6 +340,16 @@ func (rc *readinessChecker) check(ctx context.Context, c *redskyv1beta1.Readines
 		return "", ok, err
 	}
 
+	// Waiting for new nodes does not count against the failure threshold
+	for i := range ul.Items {
+		if scalingUp, err := rc.checker.ScalingUp(ctx, &ul.Items[i]); err != nil {
+			return "", false, err
+		} else if scalingUp {
+			c.LastCheckTime = now
+			return "Waiting for nodes to be provisioned", false, nil
+		}
+	}
+
 	// Check if we exceeded the failure threshold
 	c.AttemptsRemaining--
 	if c.AttemptsRemaining <= 0 {
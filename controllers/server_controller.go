@@ -19,7 +19,9 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
@@ -38,9 +40,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 // ServerReconciler reconciles a experiment and trial objects with a remote server
@@ -49,6 +54,10 @@ type ServerReconciler struct {
 	Log            logr.Logger
 	Scheme         *runtime.Scheme
 	ExperimentsAPI experimentsv1alpha1.API
+	// ConcurrencyLimit restricts the number of active trials across all experiments
+	ConcurrencyLimit experiment.ConcurrencyLimit
+	// Backoff controls how failed reconciles are retried
+	Backoff controller.Backoff
 
 	trialCreation *rate.Limiter
 }
@@ -74,6 +83,13 @@ func (r *ServerReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		}
 	}
 
+	// Update the experiment on the server if the optimization configuration changed
+	if exp.GetAnnotations()[redskyv1beta1.AnnotationExperimentURL] != "" && exp.DeletionTimestamp.IsZero() && server.NeedsOptimizationUpdate(exp) {
+		if result, err := r.updateExperiment(ctx, log, exp); result != nil {
+			return *result, err
+		}
+	}
+
 	// Get the current list of trials
 	// NOTE: No need to use limits, the cache will just return the full list anyway
 	trialList := &redskyv1beta1.TrialList{}
@@ -96,18 +112,25 @@ func (r *ServerReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		// Trials that have the server finalizer may need to be reported
 		if meta.HasFinalizer(t, server.Finalizer) {
 			// TODO Combine report and abandon into one function
-			if trial.IsFinished(t) {
-				if result, err := r.reportTrial(ctx, tlog, t); result != nil {
+			if trial.IsAbandoned(t) {
+				if result, err := r.abandonTrial(ctx, tlog, t); result != nil {
 					return *result, err
 				}
-			} else if trial.IsAbandoned(t) {
-				if result, err := r.abandonTrial(ctx, tlog, t); result != nil {
+			} else if trial.IsFinished(t) {
+				if result, err := r.reportTrial(ctx, tlog, exp, t); result != nil {
 					return *result, err
 				}
 			} else {
 				trialHasFinalizer = true
 			}
 		}
+
+		// Labels on reported trials are synchronized so they can be used to mark trials on the server
+		if lbls := server.TrialLabels(t); lbls != nil && isReportedSuccess(t) {
+			if result, err := r.syncTrialLabels(ctx, tlog, t, lbls); result != nil {
+				return *result, err
+			}
+		}
 	}
 
 	// Create a new trial if necessary
@@ -115,6 +138,14 @@ func (r *ServerReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		if result, err := r.nextTrial(ctx, log, exp, trialList); result != nil {
 			return *result, err
 		}
+	} else {
+		// We do not need capacity so we cannot be waiting for it or yielding it
+		dirty := experiment.SetWaiting(exp, false)
+		dirty = experiment.SetPreemptedBy(exp, "") || dirty
+		if dirty {
+			result, err := controller.RequeueConflict(r.Update(ctx, exp))
+			return *result, err
+		}
 	}
 
 	// Unlink the experiment from the server (only when all trial finalizers are removed)
@@ -128,40 +159,39 @@ func (r *ServerReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	return ctrl.Result{}, nil
 }
 
-func (r *ServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	if r.ExperimentsAPI == nil {
-		ctx := context.Background()
-
-		// Create a new Red Sky API
-		cfg := &config.RedSkyConfig{}
-		if err := cfg.Load(); err != nil {
-			return err
+// NewExperimentsAPI creates a new Red Sky API using the supplied configuration, the Kubernetes configuration is used
+// to identify the cluster to the Red Sky API and the optional transport is used to make requests (unchanged responses
+// are served from a local cache); if the API rejects the configured credentials, nil is returned
+func NewExperimentsAPI(ctx context.Context, cfg *config.RedSkyConfig, kubeConfig *rest.Config, transport http.RoundTripper) (experimentsv1alpha1.API, error) {
+	// Compute the UA string comment using the Kube API server information
+	var comment string
+	if dc, err := discovery.NewDiscoveryClientForConfig(kubeConfig); err == nil {
+		if serverVersion, err := dc.ServerVersion(); err == nil && serverVersion.GitVersion != "" {
+			comment = fmt.Sprintf("Kubernetes %s", strings.TrimPrefix(serverVersion.GitVersion, "v"))
 		}
+	}
 
-		// Compute the UA string comment using the Kube API server information
-		var comment string
-		if dc, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig()); err == nil {
-			if serverVersion, err := dc.ServerVersion(); err == nil && serverVersion.GitVersion != "" {
-				comment = fmt.Sprintf("Kubernetes %s", strings.TrimPrefix(serverVersion.GitVersion, "v"))
-			}
-		}
+	// The controller frequently polls experiments and trials that have not changed, use conditional requests
+	transport = &redskyapi.CachingTransport{Base: transport}
 
-		c, err := redskyapi.NewClient(ctx, cfg, version.UserAgent("RedSkyController", comment, nil))
-		if err != nil {
-			return err
-		}
-		api := experimentsv1alpha1.NewAPI(c)
+	c, err := redskyapi.NewClient(ctx, cfg, version.UserAgent("RedSkyController", comment, transport))
+	if err != nil {
+		return nil, err
+	}
+	api := experimentsv1alpha1.NewAPI(redskyapi.WithInterceptors(c, controller.APIMetrics{}))
 
-		// An unauthorized error means we will never be able to connect without changing the credentials and restarting
-		if _, err := api.Options(ctx); experimentsv1alpha1.IsUnauthorized(err) {
-			r.Log.Info("Red Sky API is unavailable, skipping setup", "message", err.Error())
-			return nil
-		}
-		r.ExperimentsAPI = api
+	// An unauthorized error means we will never be able to connect without changing the credentials and restarting
+	if _, err := api.Options(ctx); experimentsv1alpha1.IsUnauthorized(err) {
+		return nil, nil
 	}
+	return api, nil
+}
 
-	// Enforce a one trial per-second creation limit (no burst! that is the whole point)
-	r.trialCreation = rate.NewLimiter(1, 1)
+// SetupWithManager registers a new server reconciler with the supplied manager, the experiments API must be set
+func (r *ServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.ExperimentsAPI == nil {
+		return fmt.Errorf("experiments API is required")
+	}
 
 	// To search for namespaces by name, we need to index them
 	_ = mgr.GetCache().IndexField(&corev1.Namespace{}, "metadata.name", func(obj runtime.Object) []string { return []string{obj.(*corev1.Namespace).Name} })
@@ -169,8 +199,9 @@ func (r *ServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("server").
 		For(&redskyv1beta1.Experiment{}).
+		Watches(&source.Kind{Type: &redskyv1beta1.Trial{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: handler.ToRequestsFunc(trialToExperimentRequest)}).
 		WithEventFilter(&createFilter{}).
-		Complete(r)
+		Complete(r.Backoff.Reconciler(r, r.Log))
 }
 
 // createFilter ignores the experiment create event to allow the experiment status to stabilize more naturally
@@ -193,6 +224,11 @@ func (r *ServerReconciler) listTrials(ctx context.Context, trialList *redskyv1be
 // createExperiment will create a new experiment on the server using the cluster state; any default values from the
 // server will be copied back into cluster along with the URLs needed for future interactions with server.
 func (r *ServerReconciler) createExperiment(ctx context.Context, log logr.Logger, exp *redskyv1beta1.Experiment) (*ctrl.Result, error) {
+	// Make sure the optimization configuration is valid before sending it to the server
+	if err := validation.CheckOptimization(exp); err != nil {
+		return &ctrl.Result{}, err
+	}
+
 	// Convert the cluster state into a server representation
 	n, e := server.FromCluster(exp)
 	ee, err := r.ExperimentsAPI.CreateExperiment(ctx, n, *e)
@@ -217,6 +253,27 @@ func (r *ServerReconciler) createExperiment(ctx context.Context, log logr.Logger
 	return nil, nil
 }
 
+// updateExperiment will send changes to the optimization configuration of the cluster experiment to the server
+func (r *ServerReconciler) updateExperiment(ctx context.Context, log logr.Logger, exp *redskyv1beta1.Experiment) (*ctrl.Result, error) {
+	// Make sure the optimization configuration is still valid before sending it to the server
+	if err := validation.CheckOptimization(exp); err != nil {
+		return &ctrl.Result{}, err
+	}
+
+	if _, err := r.ExperimentsAPI.UpdateExperiment(ctx, exp.GetAnnotations()[redskyv1beta1.AnnotationExperimentURL], server.OptimizationPatch(exp)); err != nil {
+		return &ctrl.Result{}, err
+	}
+
+	// Record the optimization configuration so we do not send it again
+	exp.GetAnnotations()[redskyv1beta1.AnnotationOptimizationChecksum] = server.OptimizationChecksum(exp)
+	if err := r.Update(ctx, exp); err != nil {
+		return controller.RequeueConflict(err)
+	}
+
+	log.Info("Updated remote experiment", "optimization", exp.Spec.Optimization)
+	return &ctrl.Result{}, nil
+}
+
 // unlinkExperiment will delete the experiment from the server using the URLs recorded in the cluster; the finalizer
 // added when the experiment was created on the server will also be removed
 func (r *ServerReconciler) unlinkExperiment(ctx context.Context, log logr.Logger, exp *redskyv1beta1.Experiment) (*ctrl.Result, error) {
@@ -244,7 +301,10 @@ func (r *ServerReconciler) unlinkExperiment(ctx context.Context, log logr.Logger
 // nextTrial will try to obtain a suggestion from the server and create the corresponding cluster state in the form of
 // a trial; if the cluster can not accommodate additional trials at the time of invocation, not action will be taken
 func (r *ServerReconciler) nextTrial(ctx context.Context, log logr.Logger, exp *redskyv1beta1.Experiment, trialList *redskyv1beta1.TrialList) (*ctrl.Result, error) {
-	// Enforce a rate limit on trial creation
+	// Enforce a one trial per-second creation limit (no burst! that is the whole point)
+	if r.trialCreation == nil {
+		r.trialCreation = rate.NewLimiter(1, 1)
+	}
 	if res := r.trialCreation.Reserve(); res.OK() {
 		if d := res.Delay(); d > 0 {
 			res.Cancel()
@@ -252,12 +312,54 @@ func (r *ServerReconciler) nextTrial(ctx context.Context, log logr.Logger, exp *
 		}
 	}
 
+	// Apply backpressure while the metric backends are failing
+	if experiment.CheckCondition(&exp.Status, redskyv1beta1.ExperimentMetricsDegraded, corev1.ConditionTrue) {
+		return nil, nil
+	}
+
+	// Yield to higher priority experiments which are waiting for capacity
+	expList := &redskyv1beta1.ExperimentList{}
+	if err := r.List(ctx, expList); err != nil {
+		return &ctrl.Result{}, err
+	}
+	preemptedBy := experiment.Preempting(exp, expList)
+	if experiment.SetPreemptedBy(exp, preemptedBy) {
+		if preemptedBy != "" {
+			log.Info("Yielding to higher priority experiment", "preemptedBy", preemptedBy)
+		}
+		err := r.Update(ctx, exp)
+		return controller.RequeueConflict(err)
+	}
+	if preemptedBy != "" {
+		return &ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
 	// Determine the namespace (if any) to use for the trial
 	namespace, err := experiment.NextTrialNamespace(ctx, r, exp, trialList)
 	if err != nil {
 		return &ctrl.Result{}, err
 	}
+	var limited bool
+	if namespace != "" {
+		// Enforce the global concurrency limits
+		allowed, err := r.checkConcurrency(ctx, namespace)
+		if err != nil {
+			return &ctrl.Result{}, err
+		}
+		if !allowed {
+			namespace = ""
+			limited = true
+		}
+	}
 	if namespace == "" {
+		if experiment.SetWaiting(exp, true) {
+			err := r.Update(ctx, exp)
+			return controller.RequeueConflict(err)
+		}
+		// Trials finishing in other experiments do not trigger a reconcile of this experiment
+		if limited {
+			return &ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
 		return nil, nil
 	}
 
@@ -275,7 +377,7 @@ func (r *ServerReconciler) nextTrial(ctx context.Context, log logr.Logger, exp *
 	t := &redskyv1beta1.Trial{}
 	experiment.PopulateTrialFromTemplate(exp, t)
 	t.Namespace = namespace
-	server.ToClusterTrial(t, &suggestion)
+	server.ToClusterTrial(t, &suggestion, exp.Spec.Parameters)
 
 	// Create the trial
 	if err := r.Create(ctx, t); err != nil {
@@ -286,21 +388,59 @@ func (r *ServerReconciler) nextTrial(ctx context.Context, log logr.Logger, exp *
 		return &ctrl.Result{}, err
 	}
 
-	log.Info("Created new trial", "reportTrialURL", t.GetAnnotations()[redskyv1beta1.AnnotationReportTrialURL], "assignments", t.Spec.Assignments)
+	log.Info("Created new trial", "reportTrialURL", t.GetAnnotations()[redskyv1beta1.AnnotationReportTrialURL], "assignments", trial.Assignments(t))
+
+	// We found capacity, stop waiting for it
+	if experiment.SetWaiting(exp, false) {
+		err := r.Update(ctx, exp)
+		return controller.RequeueConflict(err)
+	}
 	return nil, nil
 }
 
+// checkConcurrency checks to see if another trial can be started in the specified namespace without exceeding the
+// concurrency limits, the current usage is recorded in the concurrency gauges
+func (r *ServerReconciler) checkConcurrency(ctx context.Context, namespace string) (bool, error) {
+	allTrials := &redskyv1beta1.TrialList{}
+	if err := r.List(ctx, allTrials); err != nil {
+		return false, err
+	}
+
+	active := experiment.ActiveTrials(allTrials)
+	var total int
+	controller.NamespaceConcurrentTrials.Reset()
+	for ns, n := range active {
+		controller.NamespaceConcurrentTrials.WithLabelValues(ns).Set(float64(n))
+		total += n
+	}
+	controller.ConcurrentTrials.Set(float64(total))
+
+	return r.ConcurrencyLimit.Allow(active, namespace), nil
+}
+
 // reportTrial will report the values from a finished in cluster trial back to the server
-func (r *ServerReconciler) reportTrial(ctx context.Context, log logr.Logger, t *redskyv1beta1.Trial) (*ctrl.Result, error) {
+func (r *ServerReconciler) reportTrial(ctx context.Context, log logr.Logger, exp *redskyv1beta1.Experiment, t *redskyv1beta1.Trial) (*ctrl.Result, error) {
 	if !meta.RemoveFinalizer(t, server.Finalizer) {
 		return nil, nil
 	}
 
 	if reportTrialURL := t.GetAnnotations()[redskyv1beta1.AnnotationReportTrialURL]; reportTrialURL != "" {
 		trialValues := server.FromClusterTrial(t)
-		err := r.ExperimentsAPI.ReportTrial(ctx, reportTrialURL, *trialValues)
-		if controller.IgnoreReportError(err) != nil {
-			return &ctrl.Result{}, err
+
+		// The baseline for relative values must be stored in the cluster before anything is reported
+		if server.ApplyDataResidency(exp, trialValues) {
+			err := r.Update(ctx, exp)
+			return controller.RequeueConflict(err)
+		}
+
+		if !trial.CheckCondition(&t.Status, redskyv1beta1.TrialReported, corev1.ConditionTrue) {
+			// The idempotency key makes it safe to report again if the update below does not go through
+			err := r.ExperimentsAPI.ReportTrial(ctx, reportTrialURL, *trialValues)
+			if controller.IgnoreReportError(err) != nil {
+				return &ctrl.Result{}, err
+			}
+			now := metav1.Now()
+			trial.ApplyCondition(&t.Status, redskyv1beta1.TrialReported, corev1.ConditionTrue, "", "", &now)
 		}
 
 		// Shadow the logger reference with one that will produce more contextual details
@@ -323,6 +463,28 @@ func (r *ServerReconciler) reportTrial(ctx context.Context, log logr.Logger, t *
 	return nil, nil
 }
 
+// syncTrialLabels will send the changes to the trial labels to the server
+func (r *ServerReconciler) syncTrialLabels(ctx context.Context, log logr.Logger, t *redskyv1beta1.Trial, lbls *experimentsv1alpha1.TrialLabels) (*ctrl.Result, error) {
+	err := r.ExperimentsAPI.LabelTrial(ctx, t.GetAnnotations()[redskyv1beta1.AnnotationLabelTrialURL], *lbls)
+	if controller.IgnoreNotFound(err) != nil {
+		return &ctrl.Result{}, err
+	}
+
+	server.SetTrialLabelsSynced(t)
+	if err := r.Update(ctx, t); err != nil {
+		return controller.RequeueConflict(err)
+	}
+
+	log.Info("Synchronized trial labels", "labels", lbls.Labels)
+	return nil, nil
+}
+
+// isReportedSuccess checks to see if the trial was reported as completed, only completed trials can be labeled
+func isReportedSuccess(t *redskyv1beta1.Trial) bool {
+	return trial.CheckCondition(&t.Status, redskyv1beta1.TrialReported, corev1.ConditionTrue) &&
+		!trial.CheckCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue)
+}
+
 // abandonTrial will remove the finalizer and try to notify the server that the trial will not be reported
 func (r *ServerReconciler) abandonTrial(ctx context.Context, log logr.Logger, t *redskyv1beta1.Trial) (*ctrl.Result, error) {
 	if !meta.RemoveFinalizer(t, server.Finalizer) {
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/experiment"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestServerReconciler(t *testing.T) {
+	ns := testNamespace(t)
+	ctx := context.TODO()
+
+	api := fake.NewAPI(experimentsv1alpha1.TrialAssignments{
+		Assignments: []experimentsv1alpha1.Assignment{{ParameterName: "x", Value: "5"}},
+	})
+	r := &ServerReconciler{
+		Client:         k8sClient,
+		Log:            ctrl.Log.WithName("test").WithName("Server"),
+		Scheme:         testScheme,
+		ExperimentsAPI: api,
+	}
+
+	exp := &redskyv1beta1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Name: "server", Namespace: ns},
+		Spec: redskyv1beta1.ExperimentSpec{
+			Parameters: []redskyv1beta1.Parameter{{Name: "x", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(10)}},
+			Metrics:    []redskyv1beta1.Metric{{Name: "m"}},
+		},
+	}
+	require.NoError(t, k8sClient.Create(ctx, exp))
+	key := types.NamespacedName{Namespace: ns, Name: exp.Name}
+
+	// The experiment is created on the server and the suggestion becomes a trial
+	trialList := &redskyv1beta1.TrialList{}
+	require.NoError(t, reconcileUntil(r, key, func() (bool, error) {
+		err := k8sClient.List(ctx, trialList, client.InNamespace(ns))
+		return len(trialList.Items) > 0, err
+	}))
+	assert.Contains(t, api.Experiments, "server")
+	tr := &trialList.Items[0]
+	assert.Equal(t, []redskyv1beta1.Assignment{{Name: "x", Value: redskyv1beta1.NewAssignmentValue(5)}}, tr.Spec.Assignments)
+
+	// Finishing the trial reports the values back to the server
+	reportTrialURL := tr.Annotations[redskyv1beta1.AnnotationReportTrialURL]
+	require.NotEmpty(t, reportTrialURL)
+	tr.Spec.Values = []redskyv1beta1.Value{{Name: "m", Value: "42"}}
+	trial.ApplyCondition(&tr.Status, redskyv1beta1.TrialComplete, corev1.ConditionTrue, "", "", nil)
+	require.NoError(t, k8sClient.Update(ctx, tr))
+	require.NoError(t, reconcileUntil(r, key, func() (bool, error) {
+		_, ok := api.Report(reportTrialURL)
+		return ok, nil
+	}))
+	vls, _ := api.Report(reportTrialURL)
+	assert.Equal(t, []experimentsv1alpha1.Value{{MetricName: "m", Value: 42}}, vls.Values)
+
+	// Labeling the reported trial in the cluster labels it on the server
+	labelTrialURL := tr.Annotations[redskyv1beta1.AnnotationLabelTrialURL]
+	require.NotEmpty(t, labelTrialURL)
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: tr.Namespace, Name: tr.Name}, tr))
+	tr.Labels["best"] = "true"
+	require.NoError(t, k8sClient.Update(ctx, tr))
+	require.NoError(t, reconcileUntil(r, key, func() (bool, error) {
+		return api.Labels(labelTrialURL)["best"] == "true", nil
+	}))
+}
+
+func TestServerReconciler_ConcurrencyLimit(t *testing.T) {
+	ns := testNamespace(t)
+	ctx := context.TODO()
+
+	api := fake.NewAPI(experimentsv1alpha1.TrialAssignments{
+		Assignments: []experimentsv1alpha1.Assignment{{ParameterName: "x", Value: "5"}},
+	})
+
+	// Every step uses a new reconciler so the trial creation rate limit does not apply
+	newReconciler := func() *ServerReconciler {
+		return &ServerReconciler{
+			Client:           k8sClient,
+			Log:              ctrl.Log.WithName("test").WithName("Server"),
+			Scheme:           testScheme,
+			ExperimentsAPI:   api,
+			ConcurrencyLimit: experiment.ConcurrencyLimit{MaxTrialsPerNamespace: 1},
+		}
+	}
+
+	var keys []types.NamespacedName
+	for _, name := range []string{"limited-a", "limited-b"} {
+		exp := &redskyv1beta1.Experiment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			Spec: redskyv1beta1.ExperimentSpec{
+				Parameters: []redskyv1beta1.Parameter{{Name: "x", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(10)}},
+				Metrics:    []redskyv1beta1.Metric{{Name: "m"}},
+			},
+		}
+		require.NoError(t, k8sClient.Create(ctx, exp))
+		keys = append(keys, types.NamespacedName{Namespace: ns, Name: name})
+	}
+
+	// The first experiment uses the only trial allowed in the namespace
+	trialList := &redskyv1beta1.TrialList{}
+	require.NoError(t, reconcileUntil(newReconciler(), keys[0], func() (bool, error) {
+		err := k8sClient.List(ctx, trialList, client.InNamespace(ns))
+		return len(trialList.Items) > 0, err
+	}))
+
+	// The second experiment waits for capacity and checks the limit again later
+	exp := &redskyv1beta1.Experiment{}
+	require.NoError(t, reconcileUntil(newReconciler(), keys[1], func() (bool, error) {
+		err := k8sClient.Get(ctx, keys[1], exp)
+		return exp.Status.Waiting, err
+	}))
+	result, err := newReconciler().Reconcile(ctrl.Request{NamespacedName: keys[1]})
+	require.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 0)
+
+	// Finishing the trial of the first experiment allows the second experiment to create a trial
+	tr := &trialList.Items[0]
+	tr.Spec.Values = []redskyv1beta1.Value{{Name: "m", Value: "42"}}
+	trial.ApplyCondition(&tr.Status, redskyv1beta1.TrialComplete, corev1.ConditionTrue, "", "", nil)
+	require.NoError(t, k8sClient.Update(ctx, tr))
+	require.NoError(t, reconcileUntil(newReconciler(), keys[1], func() (bool, error) {
+		err := k8sClient.Get(ctx, keys[1], exp)
+		return !exp.Status.Waiting, err
+	}))
+}
@@ -41,6 +41,8 @@ type SetupReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+	// Backoff controls how failed reconciles are retried
+	Backoff controller.Backoff
 }
 
 // +kubebuilder:rbac:groups=redskyops.dev,resources=trials,verbs=get;list;watch;update
@@ -85,7 +87,7 @@ func (r *SetupReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Named("setup").
 		For(&redskyv1beta1.Trial{}).
 		Owns(&batchv1.Job{}).
-		Complete(r)
+		Complete(r.Backoff.Reconciler(r, r.Log))
 }
 
 // inspectSetupJobs will look for the setup jobs and update the trial status accordingly
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	redskyv1alpha1 "github.com/redskyops/redskyops-controller/api/v1alpha1"
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// These tests use a real API server provided by envtest, see https://book.kubebuilder.io/reference/envtest.html for
+// installing the required binaries; tests are skipped if the binaries are not available
+
+var (
+	testScheme = runtime.NewScheme()
+	k8sClient  client.Client
+)
+
+func TestMain(m *testing.M) {
+	_ = clientgoscheme.AddToScheme(testScheme)
+	_ = redskyv1alpha1.AddToScheme(testScheme)
+	_ = redskyv1beta1.AddToScheme(testScheme)
+
+	if !hasEnvtestAssets() {
+		os.Exit(m.Run())
+	}
+
+	ctrl.SetLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(os.Stderr)))
+	testEnv := &envtest.Environment{CRDDirectoryPaths: []string{filepath.Join("..", "config", "crd", "bases")}}
+	cfg, err := testEnv.Start()
+	if err != nil {
+		panic(err)
+	}
+	k8sClient, err = client.New(cfg, client.Options{Scheme: testScheme})
+	if err != nil {
+		_ = testEnv.Stop()
+		panic(err)
+	}
+
+	code := m.Run()
+	_ = testEnv.Stop()
+	os.Exit(code)
+}
+
+// hasEnvtestAssets checks for the control plane binaries used by envtest
+func hasEnvtestAssets() bool {
+	dir := os.Getenv("KUBEBUILDER_ASSETS")
+	if dir == "" {
+		dir = "/usr/local/kubebuilder/bin"
+	}
+	_, err := os.Stat(filepath.Join(dir, "kube-apiserver"))
+	return err == nil
+}
+
+// testNamespace returns a new namespace for a single test, the test is skipped if envtest is not available
+func testNamespace(t *testing.T) string {
+	if k8sClient == nil {
+		t.Skip("envtest binaries are not available")
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "test-"}}
+	if err := k8sClient.Create(context.TODO(), ns); err != nil {
+		t.Fatal(err)
+	}
+	return ns.Name
+}
+
+// reconcileUntil invokes the reconciler until the condition is satisfied or the maximum number of attempts is reached
+func reconcileUntil(r interface {
+	Reconcile(ctrl.Request) (ctrl.Result, error)
+}, key types.NamespacedName, done func() (bool, error)) error {
+	for i := 0; i < 20; i++ {
+		if _, err := r.Reconcile(ctrl.Request{NamespacedName: key}); err != nil {
+			return err
+		}
+		if ok, err := done(); err != nil || ok {
+			return err
+		}
+	}
+	return fmt.Errorf("reconcile of %s did not complete", key)
+}
@@ -18,53 +18,91 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	"github.com/redskyops/redskyops-controller/internal/controller"
 	"github.com/redskyops/redskyops-controller/internal/meta"
+	"github.com/redskyops/redskyops-controller/internal/ready"
 	"github.com/redskyops/redskyops-controller/internal/trial"
 	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// sidecarPollInterval is how often to check for trial run jobs blocked by service mesh sidecars
+const sidecarPollInterval = 5 * time.Second
+
+// warmupPollInterval is how often to check for the end of the trial run warm-up
+const warmupPollInterval = 5 * time.Second
+
+// scaleUpPollInterval is how often to check for trial run pods waiting for the cluster autoscaler
+const scaleUpPollInterval = 10 * time.Second
+
 // TrialJobReconciler reconciles a Trial's job
 type TrialJobReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
+	// Backoff controls how failed reconciles are retried
+	Backoff controller.Backoff
+	// Pods is used to read trial run logs when the completion policy includes log markers
+	Pods corev1client.PodsGetter
 }
 
 // +kubebuilder:rbac:groups=redskyops.dev,resources=trials,verbs=get;list;watch;update
-// +kubebuilder:rbac:groups=batch;extensions,resources=jobs,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=batch;extensions,resources=jobs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;update
 // +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
 
 func (r *TrialJobReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	ctx := context.Background()
 	now := metav1.Now()
 
 	t := &redskyv1beta1.Trial{}
-	if err := r.Get(ctx, req.NamespacedName, t); err != nil || r.ignoreTrial(t) {
+	if err := r.Get(ctx, req.NamespacedName, t); err != nil {
 		return ctrl.Result{}, controller.IgnoreNotFound(err)
 	}
 
+	// Resume the CronJob suspended for the trial run, this must happen before the finished trial is ignored
+	if result, err := r.resumeCronJob(ctx, t); result != nil {
+		return *result, err
+	}
+
+	if r.ignoreTrial(t) {
+		return ctrl.Result{}, nil
+	}
+
 	// List the trial jobs (there should only ever be 0 or 1 matching jobs)
 	jobList := &batchv1.JobList{}
 	if err := r.listJobs(ctx, jobList, t.Namespace, t.GetJobSelector()); err != nil {
 		return ctrl.Result{}, err
 	}
 
+	// Stop the trial if it was aborted
+	if result, err := r.abortTrial(ctx, t, jobList, &now); result != nil {
+		return *result, err
+	}
+
 	// Update trial status based on existing job state
 	if result, err := r.updateStatus(ctx, t, jobList, &now); result != nil {
 		return *result, err
 	}
 
+	// Fail the trial if the job was deleted after it started, it will be abandoned instead of reported
+	if result, err := r.jobDeleted(ctx, t, jobList, &now); result != nil {
+		return *result, err
+	}
+
 	// Create a new job if necessary
 	if len(jobList.Items) == 0 {
 		// Insert a "sleep" between "ready" and the trial job
@@ -93,7 +131,7 @@ func (r *TrialJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Named("trial-job").
 		For(&redskyv1beta1.Trial{}).
 		Owns(&batchv1.Job{}).
-		Complete(r)
+		Complete(r.Backoff.Reconciler(r, r.Log))
 }
 
 func (r *TrialJobReconciler) ignoreTrial(t *redskyv1beta1.Trial) bool {
@@ -107,6 +145,11 @@ func (r *TrialJobReconciler) ignoreTrial(t *redskyv1beta1.Trial) bool {
 		return true
 	}
 
+	// Do not ignore aborted trials, they need to be stopped
+	if t.Spec.Abort {
+		return false
+	}
+
 	// Ignore trials that are not ready yet
 	if !trial.CheckCondition(&t.Status, redskyv1beta1.TrialReady, corev1.ConditionTrue) {
 		return true
@@ -125,28 +168,176 @@ func (r *TrialJobReconciler) ignoreTrial(t *redskyv1beta1.Trial) bool {
 func (r *TrialJobReconciler) updateStatus(ctx context.Context, t *redskyv1beta1.Trial, jobList *batchv1.JobList, probeTime *metav1.Time) (*ctrl.Result, error) {
 	for i := range jobList.Items {
 		if update, requeue := r.applyJobStatus(ctx, t, &jobList.Items[i], probeTime); update {
-			err := r.Update(ctx, t)
-			return controller.RequeueConflict(err)
+			if err := r.Update(ctx, t); err != nil {
+				return controller.RequeueConflict(err)
+			}
+			return r.shutdownSidecars(ctx, t, &jobList.Items[i])
 		} else if requeue {
 			// We are watching jobs, not pods; we may need to poll the pod state before it is consistent
 			return &ctrl.Result{Requeue: true}, nil
 		}
 	}
 
+	// The end of the warm-up does not change the job status, poll until the measured trial run starts
+	if t.Spec.Warmup != nil && t.Status.WarmupStartTime != nil && t.Status.StartTime == nil {
+		return &ctrl.Result{RequeueAfter: warmupPollInterval}, nil
+	}
+
+	// Pods waiting for new nodes do not change the job status, poll so the scale up timeout is enforced
+	if timeout := trial.ScaleUpTimeout(t); timeout > 0 {
+		for i := range jobList.Items {
+			podList, err := r.listPods(ctx, &jobList.Items[i])
+			if err != nil {
+				return &ctrl.Result{}, err
+			}
+			for j := range podList.Items {
+				if ready.AwaitingScaleUp(&podList.Items[j], timeout, probeTime.Time) {
+					return &ctrl.Result{RequeueAfter: scaleUpPollInterval}, nil
+				}
+			}
+		}
+	}
+
+	// Sidecars keep the job active after the trial run exits, the job status will not change so we need to poll
+	if trial.SidecarPolicy(t) == trial.SidecarPolicyShutdown {
+		for i := range jobList.Items {
+			if !isJobFinished(&jobList.Items[i]) {
+				return &ctrl.Result{RequeueAfter: sidecarPollInterval}, nil
+			}
+		}
+	}
+
 	return nil, nil
 }
 
+// shutdownSidecars will delete a trial run job that is only being kept alive by service mesh sidecars
+func (r *TrialJobReconciler) shutdownSidecars(ctx context.Context, t *redskyv1beta1.Trial, job *batchv1.Job) (*ctrl.Result, error) {
+	if trial.SidecarPolicy(t) != trial.SidecarPolicyShutdown || t.Status.CompletionTime == nil || isJobFinished(job) {
+		return &ctrl.Result{}, nil
+	}
+
+	podList, err := r.listPods(ctx, job)
+	if err != nil {
+		return &ctrl.Result{}, err
+	}
+
+	for i := range podList.Items {
+		if trial.SidecarsBlocking(&podList.Items[i]) {
+			// The trial times have already been recorded, deleting the job will terminate the sidecars
+			err := r.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+			return &ctrl.Result{}, controller.IgnoreNotFound(err)
+		}
+	}
+
+	return &ctrl.Result{}, nil
+}
+
+// jobDeleted marks the trial as failed if the trial run job disappeared before the trial run completed
+func (r *TrialJobReconciler) jobDeleted(ctx context.Context, t *redskyv1beta1.Trial, jobList *batchv1.JobList, probeTime *metav1.Time) (*ctrl.Result, error) {
+	if len(jobList.Items) > 0 || (t.Status.StartTime == nil && t.Status.WarmupStartTime == nil) || t.Status.CompletionTime != nil {
+		return nil, nil
+	}
+
+	trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, trial.ReasonJobDeleted, "Trial run job was deleted before the trial completed", probeTime)
+	err := r.Update(ctx, t)
+	return controller.RequeueConflict(err)
+}
+
+// abortTrial will delete the trial run jobs and mark the trial as failed if an abort was requested
+func (r *TrialJobReconciler) abortTrial(ctx context.Context, t *redskyv1beta1.Trial, jobList *batchv1.JobList, probeTime *metav1.Time) (*ctrl.Result, error) {
+	if !t.Spec.Abort {
+		return nil, nil
+	}
+
+	for i := range jobList.Items {
+		if err := r.Delete(ctx, &jobList.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); controller.IgnoreNotFound(err) != nil {
+			return &ctrl.Result{}, err
+		}
+	}
+
+	trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, "Aborted", "Trial was aborted", probeTime)
+	err := r.Update(ctx, t)
+	return controller.RequeueConflict(err)
+}
+
 // createJob will create a new trial run job
 func (r *TrialJobReconciler) createJob(ctx context.Context, t *redskyv1beta1.Trial) (*ctrl.Result, error) {
-	job := trial.NewJob(t)
+	// Use the job template from the referenced CronJob, effectively triggering an immediate run of the CronJob
+	if ref := t.Spec.JobTemplateRef; ref != nil {
+		if ref.Kind != "CronJob" {
+			return &ctrl.Result{}, fmt.Errorf("unsupported job template reference kind: %s", ref.Kind)
+		}
+
+		cj := &batchv1beta1.CronJob{}
+		ns := ref.Namespace
+		if ns == "" {
+			ns = t.Namespace
+		}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: ns, Name: ref.Name}, cj); err != nil {
+			return &ctrl.Result{}, err
+		}
+
+		// Keep the CronJob from starting its own jobs while the trial is running
+		if cj.Spec.Suspend == nil || !*cj.Spec.Suspend {
+			suspend := true
+			cj.Spec.Suspend = &suspend
+			if cj.Annotations == nil {
+				cj.Annotations = make(map[string]string, 1)
+			}
+			cj.Annotations[redskyv1beta1.AnnotationSuspendedBy] = t.Name
+			if err := r.Update(ctx, cj); err != nil {
+				return controller.RequeueConflict(err)
+			}
+		}
+
+		t = t.DeepCopy()
+		t.Spec.JobTemplate = cj.Spec.JobTemplate.DeepCopy()
+	}
+
+	job, err := trial.NewJob(t)
+	if err != nil {
+		return &ctrl.Result{}, err
+	}
 	if err := controllerutil.SetControllerReference(t, job, r.Scheme); err != nil {
 		return &ctrl.Result{}, err
 	}
 
-	err := r.Create(ctx, job)
+	err = r.Create(ctx, job)
 	return &ctrl.Result{}, err
 }
 
+// resumeCronJob will resume the CronJob referenced by the trial job template if it was suspended by the trial and the
+// trial run is over
+func (r *TrialJobReconciler) resumeCronJob(ctx context.Context, t *redskyv1beta1.Trial) (*ctrl.Result, error) {
+	ref := t.Spec.JobTemplateRef
+	if ref == nil || ref.Kind != "CronJob" {
+		return nil, nil
+	}
+	if t.Status.CompletionTime == nil && !trial.IsFinished(t) && t.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+
+	cj := &batchv1beta1.CronJob{}
+	ns := ref.Namespace
+	if ns == "" {
+		ns = t.Namespace
+	}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: ns, Name: ref.Name}, cj); controller.IgnoreNotFound(err) != nil {
+		return &ctrl.Result{}, err
+	}
+	if cj.Annotations[redskyv1beta1.AnnotationSuspendedBy] != t.Name {
+		return nil, nil
+	}
+
+	suspend := false
+	cj.Spec.Suspend = &suspend
+	delete(cj.Annotations, redskyv1beta1.AnnotationSuspendedBy)
+	if err := r.Update(ctx, cj); err != nil {
+		return controller.RequeueConflict(err)
+	}
+	return nil, nil
+}
+
 // listJobs will return all of the jobs for the trial
 func (r *TrialJobReconciler) listJobs(ctx context.Context, jobList *batchv1.JobList, namespace string, selector *metav1.LabelSelector) error {
 	matchingSelector, err := meta.MatchingSelector(selector)
@@ -171,35 +362,65 @@ func (r *TrialJobReconciler) listJobs(ctx context.Context, jobList *batchv1.JobL
 }
 
 func (r *TrialJobReconciler) applyJobStatus(ctx context.Context, t *redskyv1beta1.Trial, job *batchv1.Job, time *metav1.Time) (bool, bool) {
-	var dirty bool
+	var dirty, checkPolicy bool
 
 	// Get the interval of the container execution in the job pods
 	startedAt := job.Status.StartTime
 	finishedAt := job.Status.CompletionTime
-	if matchingSelector, err := meta.MatchingSelector(job.Spec.Selector); err == nil {
-		podList := &corev1.PodList{}
-		if err := r.List(ctx, podList, client.InNamespace(job.Namespace), matchingSelector); err == nil {
-			// Look for pod failures (edge case where job controller doesn't update status properly, e.g. initContainer failure or unschedulable)
-			for i := range podList.Items {
-				s := &podList.Items[i].Status
-				if s.Phase == corev1.PodFailed {
-					trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, s.Reason, "", time)
+	podList, err := r.listPods(ctx, job)
+	if err == nil {
+		// Look for pod failures (edge case where job controller doesn't update status properly, e.g. initContainer failure or unschedulable)
+		for i := range podList.Items {
+			s := &podList.Items[i].Status
+			if s.Phase == corev1.PodFailed {
+				trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, s.Reason, "", time)
+				dirty = true
+			}
+			// With sidecars still running, the pod will not fail even if the trial run did
+			if trial.SidecarPolicy(t) == trial.SidecarPolicyShutdown && trial.SidecarsBlocking(&podList.Items[i]) {
+				if cs := trial.SidecarExitFailure(&podList.Items[i]); cs != nil {
+					trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, cs.State.Terminated.Reason, cs.State.Terminated.Message, time)
 					dirty = true
 				}
-				// TODO We should consolidate this with `internal/ready/podFailed`
-				for _, c := range s.Conditions {
-					if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
-						trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, c.Reason, c.Message, time)
-						dirty = true
+			}
+			// TODO We should consolidate this with `internal/ready/podFailed`
+			for _, c := range s.Conditions {
+				if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+					// Give the autoscaler a chance to provision a node, the container times exclude the wait
+					if ready.AwaitingScaleUp(&podList.Items[i], trial.ScaleUpTimeout(t), time.Time) {
+						continue
 					}
+					trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, c.Reason, c.Message, time)
+					dirty = true
 				}
 			}
+		}
 
-			// Check if the job has a start/completion time, but it is not yet reflected in the pod state we are seeing
-			startedAt, finishedAt = containerTime(podList)
-			if (startedAt == nil && job.Status.StartTime != nil) || (finishedAt == nil && job.Status.CompletionTime != nil) {
-				return dirty, true
-			}
+		// Check if the job has a start/completion time, but it is not yet reflected in the pod state we are seeing
+		startedAt, finishedAt = containerTime(podList)
+		if (startedAt == nil && job.Status.StartTime != nil) || (finishedAt == nil && job.Status.CompletionTime != nil) {
+			return dirty, true
+		}
+
+		// Evaluate the completion policy the first time we see the trial run finish
+		checkPolicy = finishedAt != nil && t.Status.CompletionTime == nil
+	}
+
+	// Record the start of the warm-up, the measured trial run starts once the warm-up is over
+	if t.Spec.Warmup != nil {
+		if warmupStartTime, updated := latestTime(t.Status.WarmupStartTime, startedAt, nil); updated {
+			t.Status.WarmupStartTime = warmupStartTime
+			dirty = true
+		}
+		// If the trial run just finished, the completion time has not been recorded yet
+		warmupLimit := time
+		if finishedAt != nil {
+			warmupLimit = finishedAt
+		}
+		startedAt = trial.WarmupEnd(t, podList, warmupLimit)
+		if startedAt == nil && t.Status.StartTime == nil && finishedAt != nil {
+			trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, trial.ReasonWarmupIncomplete, "Trial run finished before the warm-up was over", time)
+			dirty = true
 		}
 	}
 
@@ -223,12 +444,88 @@ func (r *TrialJobReconciler) applyJobStatus(ctx context.Context, t *redskyv1beta
 		}
 	}
 
+	// Apply the completion policy last so the failure reason takes precedence over the generic job failure
+	if checkPolicy && r.checkCompletionPolicy(ctx, t, podList, time) {
+		dirty = true
+	}
+
 	return dirty, false
 }
 
+// checkCompletionPolicy applies the trial completion policy to the finished trial run pods
+func (r *TrialJobReconciler) checkCompletionPolicy(ctx context.Context, t *redskyv1beta1.Trial, pods *corev1.PodList, time *metav1.Time) bool {
+	policy := t.Spec.CompletionPolicy
+	if policy == nil {
+		return false
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if f := trial.CheckExitCodes(policy, pod); f != nil {
+			trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, f.Reason, f.Message, time)
+			return true
+		}
+
+		// Only inspect the logs once the trial run in the pod has actually finished
+		if !trial.HasLogMarkers(policy) || r.Pods == nil || (pod.Status.Phase == corev1.PodRunning && !trial.SidecarsBlocking(pod)) {
+			continue
+		}
+
+		reason, message, err := r.checkLogMarkers(ctx, policy, pod)
+		if err != nil {
+			r.Log.Error(err, "Unable to check trial run logs", "namespace", pod.Namespace, "name", pod.Name)
+			reason, message = "LogsUnavailable", err.Error()
+		}
+		if reason != "" {
+			trial.ApplyCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue, reason, message, time)
+			return true
+		}
+	}
+	return false
+}
+
+// checkLogMarkers searches the logs of a trial run pod for the markers of the completion policy
+func (r *TrialJobReconciler) checkLogMarkers(ctx context.Context, policy *redskyv1beta1.TrialCompletionPolicy, pod *corev1.Pod) (string, string, error) {
+	opts := &corev1.PodLogOptions{Container: trial.LogContainer(policy, pod)}
+	logs, err := r.Pods.Pods(pod.Namespace).GetLogs(pod.Name, opts).Context(ctx).Stream()
+	if err != nil {
+		return "", "", err
+	}
+	defer logs.Close()
+
+	return trial.CheckLogMarkers(policy, logs)
+}
+
+// listPods returns the pods belonging to the supplied job
+func (r *TrialJobReconciler) listPods(ctx context.Context, job *batchv1.Job) (*corev1.PodList, error) {
+	matchingSelector, err := meta.MatchingSelector(job.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	podList := &corev1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(job.Namespace), matchingSelector); err != nil {
+		return nil, err
+	}
+	return podList, nil
+}
+
+// isJobFinished checks to see if the job has completed or failed
+func isJobFinished(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
 func containerTime(pods *corev1.PodList) (startedAt *metav1.Time, finishedAt *metav1.Time) {
 	for i := range pods.Items {
 		for j := range pods.Items[i].Status.ContainerStatuses {
+			// Service mesh sidecars outlive the trial run, do not let them skew the interval
+			if trial.IsSidecarContainer(pods.Items[i].Status.ContainerStatuses[j].Name) {
+				continue
+			}
 			s := &pods.Items[i].Status.ContainerStatuses[j].State
 			if s.Running != nil {
 				startedAt, _ = earliestTime(startedAt, &s.Running.StartedAt)
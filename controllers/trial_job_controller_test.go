@@ -0,0 +1,132 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/controller"
+	"github.com/redskyops/redskyops-controller/internal/experiment"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// newReadyTrial returns a new trial which is ready to run using the referenced job template
+func newReadyTrial(ns, name string, ref *corev1.ObjectReference) *redskyv1beta1.Trial {
+	exp := &redskyv1beta1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Name: "cron", Namespace: ns},
+		Spec: redskyv1beta1.ExperimentSpec{
+			Parameters: []redskyv1beta1.Parameter{{Name: "x", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(10)}},
+			Metrics:    []redskyv1beta1.Metric{{Name: "m"}},
+		},
+	}
+
+	tr := &redskyv1beta1.Trial{}
+	experiment.PopulateTrialFromTemplate(exp, tr)
+	tr.Name = name
+	tr.Namespace = ns
+	tr.Spec.JobTemplateRef = ref
+	tr.Spec.Assignments = []redskyv1beta1.Assignment{{Name: "x", Value: redskyv1beta1.NewAssignmentValue(5)}}
+	trial.ApplyCondition(&tr.Status, redskyv1beta1.TrialReady, corev1.ConditionTrue, "", "", nil)
+	return tr
+}
+
+func TestTrialJobReconciler_JobTemplateRef(t *testing.T) {
+	ns := testNamespace(t)
+	ctx := context.TODO()
+
+	r := &TrialJobReconciler{
+		Client: k8sClient,
+		Log:    ctrl.Log.WithName("test").WithName("TrialJob"),
+		Scheme: testScheme,
+	}
+
+	cj := &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: ns},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule: "*/5 * * * *",
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers:    []corev1.Container{{Name: "app", Image: "busybox"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, k8sClient.Create(ctx, cj))
+	cjKey := types.NamespacedName{Namespace: ns, Name: cj.Name}
+
+	tr := newReadyTrial(ns, "cron-001", &corev1.ObjectReference{Kind: "CronJob", Name: cj.Name})
+	require.NoError(t, k8sClient.Create(ctx, tr))
+	key := types.NamespacedName{Namespace: ns, Name: tr.Name}
+
+	// The trial run job is created from the CronJob template and the CronJob is suspended while the trial runs
+	job := &batchv1.Job{}
+	require.NoError(t, reconcileUntil(r, key, func() (bool, error) {
+		err := k8sClient.Get(ctx, key, job)
+		return err == nil, controller.IgnoreNotFound(err)
+	}))
+	if assert.Len(t, job.Spec.Template.Spec.Containers, 1) {
+		assert.Equal(t, "busybox", job.Spec.Template.Spec.Containers[0].Image)
+	}
+	require.NoError(t, k8sClient.Get(ctx, cjKey, cj))
+	if assert.NotNil(t, cj.Spec.Suspend) {
+		assert.True(t, *cj.Spec.Suspend)
+	}
+	assert.Equal(t, tr.Name, cj.Annotations[redskyv1beta1.AnnotationSuspendedBy])
+
+	// The CronJob is resumed once the trial run is over
+	require.NoError(t, k8sClient.Get(ctx, key, tr))
+	tr.Spec.Abort = true
+	require.NoError(t, k8sClient.Update(ctx, tr))
+	require.NoError(t, reconcileUntil(r, key, func() (bool, error) {
+		err := k8sClient.Get(ctx, cjKey, cj)
+		return cj.Spec.Suspend != nil && !*cj.Spec.Suspend, err
+	}))
+	assert.NotContains(t, cj.Annotations, redskyv1beta1.AnnotationSuspendedBy)
+}
+
+func TestTrialJobReconciler_JobTemplateRefUnsupported(t *testing.T) {
+	ns := testNamespace(t)
+	ctx := context.TODO()
+
+	r := &TrialJobReconciler{
+		Client: k8sClient,
+		Log:    ctrl.Log.WithName("test").WithName("TrialJob"),
+		Scheme: testScheme,
+	}
+
+	tr := newReadyTrial(ns, "deployment-001", &corev1.ObjectReference{Kind: "Deployment", Name: "app"})
+	require.NoError(t, k8sClient.Create(ctx, tr))
+	key := types.NamespacedName{Namespace: ns, Name: tr.Name}
+
+	_, err := r.Reconcile(ctrl.Request{NamespacedName: key})
+	assert.EqualError(t, err, "unsupported job template reference kind: Deployment")
+}
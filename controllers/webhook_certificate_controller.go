@@ -0,0 +1,118 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/redskyops/redskyops-controller/internal/controller"
+	"github.com/redskyops/redskyops-controller/internal/webhook"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// WebhookCertificateReconciler rotates self-signed webhook certificates and injects the CA into webhook configurations
+type WebhookCertificateReconciler struct {
+	client.Client
+	Log        logr.Logger
+	Scheme     *runtime.Scheme
+	SelfSigned *webhook.SelfSigned
+}
+
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations;validatingwebhookconfigurations,verbs=get;list;watch;update
+
+func (r *WebhookCertificateReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	ctx := context.Background()
+
+	// Make sure the certificates are current
+	next, err := r.SelfSigned.Ensure(time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	caBundle, err := r.SelfSigned.CABundle()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	result := ctrl.Result{RequeueAfter: time.Until(next)}
+
+	// The request name may correspond to either type of webhook configuration
+	mwc := &admissionregistrationv1beta1.MutatingWebhookConfiguration{}
+	if err := r.Get(ctx, req.NamespacedName, mwc); controller.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, err
+	} else if err == nil && !r.ignoreConfiguration(mwc) {
+		var dirty bool
+		for i := range mwc.Webhooks {
+			dirty = r.inject(&mwc.Webhooks[i].ClientConfig, caBundle) || dirty
+		}
+		if dirty {
+			if err := r.Update(ctx, mwc); err != nil {
+				conflict, err := controller.RequeueConflict(err)
+				return *conflict, err
+			}
+		}
+	}
+
+	vwc := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{}
+	if err := r.Get(ctx, req.NamespacedName, vwc); controller.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, err
+	} else if err == nil && !r.ignoreConfiguration(vwc) {
+		var dirty bool
+		for i := range vwc.Webhooks {
+			dirty = r.inject(&vwc.Webhooks[i].ClientConfig, caBundle) || dirty
+		}
+		if dirty {
+			if err := r.Update(ctx, vwc); err != nil {
+				conflict, err := controller.RequeueConflict(err)
+				return *conflict, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (r *WebhookCertificateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("webhook-certificate").
+		For(&admissionregistrationv1beta1.MutatingWebhookConfiguration{}).
+		Watches(&source.Kind{Type: &admissionregistrationv1beta1.ValidatingWebhookConfiguration{}}, &handler.EnqueueRequestForObject{}).
+		Complete(r)
+}
+
+func (r *WebhookCertificateReconciler) ignoreConfiguration(obj interface{ GetLabels() map[string]string }) bool {
+	// Only consider our own webhook configurations
+	return obj.GetLabels()["app.kubernetes.io/name"] != "redskyops"
+}
+
+// inject sets the CA bundle on client configurations which reference the webhook service
+func (r *WebhookCertificateReconciler) inject(cc *admissionregistrationv1beta1.WebhookClientConfig, caBundle []byte) bool {
+	if cc.Service == nil || cc.Service.Name != r.SelfSigned.ServiceName || cc.Service.Namespace != r.SelfSigned.Namespace {
+		return false
+	}
+	if bytes.Equal(cc.CABundle, caBundle) {
+		return false
+	}
+	cc.CABundle = caBundle
+	return true
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package e2e is a framework for running end-to-end tests of Red Sky Ops against a disposable kind cluster. The
+// framework is intended to be reused by anyone integrating with the controller, e.g. to verify custom setup tasks.
+package e2e
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	redskyv1alpha1 "github.com/redskyops/redskyops-controller/api/v1alpha1"
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/experiment"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Cluster is a kind cluster with Red Sky Ops installed
+type Cluster struct {
+	// Name is the name of the kind cluster
+	Name string
+	// KubeConfig is the path to the kubeconfig file for the cluster, defaults to a temporary file
+	KubeConfig string
+	// Kind is the path to the kind binary
+	Kind string
+	// Redskyctl is the path to the redskyctl binary used to install the controller
+	Redskyctl string
+	// Images are local Docker images to load into the cluster before installing the controller
+	Images []string
+	// KeepCluster prevents the cluster from being deleted, useful for debugging failures
+	KeepCluster bool
+
+	client client.Client
+}
+
+// NewCluster returns a new cluster description using the binaries found on the path
+func NewCluster(name string) *Cluster {
+	return &Cluster{
+		Name:      name,
+		Kind:      "kind",
+		Redskyctl: "redskyctl",
+	}
+}
+
+// Create starts a new kind cluster and loads the configured images
+func (c *Cluster) Create(ctx context.Context) error {
+	if c.KubeConfig == "" {
+		dir, err := ioutil.TempDir("", "redsky-e2e")
+		if err != nil {
+			return err
+		}
+		c.KubeConfig = filepath.Join(dir, "kubeconfig")
+	}
+
+	if err := c.run(ctx, nil, c.Kind, "create", "cluster", "--name", c.Name, "--kubeconfig", c.KubeConfig, "--wait", "5m"); err != nil {
+		return err
+	}
+	for _, img := range c.Images {
+		if err := c.run(ctx, nil, c.Kind, "load", "docker-image", img, "--name", c.Name); err != nil {
+			return err
+		}
+	}
+	return c.connect()
+}
+
+// Delete removes the kind cluster
+func (c *Cluster) Delete(ctx context.Context) error {
+	if c.KeepCluster {
+		return nil
+	}
+	return c.run(ctx, nil, c.Kind, "delete", "cluster", "--name", c.Name)
+}
+
+// Install runs `redskyctl init` against the cluster and waits for the controller to become available
+func (c *Cluster) Install(ctx context.Context) error {
+	return c.run(ctx, nil, c.Redskyctl, "init", "--wait")
+}
+
+// Client returns a client for the cluster
+func (c *Cluster) Client() client.Client {
+	return c.client
+}
+
+// CreateExperiment creates the supplied experiment
+func (c *Cluster) CreateExperiment(ctx context.Context, exp *redskyv1beta1.Experiment) error {
+	return c.client.Create(ctx, exp)
+}
+
+// CreateTrial creates a new trial with the supplied assignments using the experiment's trial template
+func (c *Cluster) CreateTrial(ctx context.Context, exp *redskyv1beta1.Experiment, assignments map[string]int64) (*redskyv1beta1.Trial, error) {
+	t := &redskyv1beta1.Trial{}
+	experiment.PopulateTrialFromTemplate(exp, t)
+	for _, p := range exp.Spec.Parameters {
+		v, ok := assignments[p.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing assignment for parameter %q", p.Name)
+		}
+		t.Spec.Assignments = append(t.Spec.Assignments, redskyv1beta1.Assignment{Name: p.Name, Value: redskyv1beta1.NewAssignmentValue(v)})
+	}
+	if err := c.client.Create(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// WaitForTrial polls the trial until the specified condition is true or the timeout expires; the trial is updated
+// with the final observed state
+func (c *Cluster) WaitForTrial(ctx context.Context, t *redskyv1beta1.Trial, conditionType redskyv1beta1.TrialConditionType, timeout time.Duration) error {
+	key := client.ObjectKey{Namespace: t.Namespace, Name: t.Name}
+	return wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		if err := c.client.Get(ctx, key, t); err != nil {
+			return false, err
+		}
+		if conditionType != redskyv1beta1.TrialFailed && trial.CheckCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue) {
+			return false, fmt.Errorf("trial %s failed", t.Name)
+		}
+		return trial.CheckCondition(&t.Status, conditionType, corev1.ConditionTrue), nil
+	})
+}
+
+// connect creates a new client using the cluster kubeconfig
+func (c *Cluster) connect() error {
+	cfg, err := clientcmd.BuildConfigFromFlags("", c.KubeConfig)
+	if err != nil {
+		return err
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return err
+	}
+	if err := redskyv1alpha1.AddToScheme(scheme); err != nil {
+		return err
+	}
+	if err := redskyv1beta1.AddToScheme(scheme); err != nil {
+		return err
+	}
+
+	c.client, err = client.New(cfg, client.Options{Scheme: scheme})
+	return err
+}
+
+// run executes a command against the cluster, failures include the combined output of the command
+func (c *Cluster) run(ctx context.Context, stdin []byte, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), "KUBECONFIG="+c.KubeConfig)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s %v failed: %w\n%s", name, args, err, out)
+	}
+	return nil
+}
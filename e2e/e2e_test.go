@@ -0,0 +1,76 @@
+// +build e2e
+
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cluster is shared by all of the tests in this package
+var cluster *Cluster
+
+func TestMain(m *testing.M) {
+	cluster = NewCluster("redsky-e2e")
+	if redskyctl := os.Getenv("REDSKYCTL"); redskyctl != "" {
+		cluster.Redskyctl = redskyctl
+	}
+	for _, env := range []string{"IMG", "SETUPTOOLS_IMG"} {
+		if img := os.Getenv(env); img != "" {
+			cluster.Images = append(cluster.Images, img)
+		}
+	}
+	cluster.KeepCluster = os.Getenv("E2E_KEEP_CLUSTER") != ""
+
+	ctx := context.Background()
+	if err := cluster.Create(ctx); err != nil {
+		_ = cluster.Delete(ctx)
+		panic(err)
+	}
+	if err := cluster.Install(ctx); err != nil {
+		_ = cluster.Delete(ctx)
+		panic(err)
+	}
+
+	code := m.Run()
+	_ = cluster.Delete(ctx)
+	os.Exit(code)
+}
+
+func TestSimulationExperiment(t *testing.T) {
+	ctx := context.Background()
+
+	exp := SimulationExperiment("simulation", "default")
+	require.NoError(t, cluster.CreateExperiment(ctx, exp))
+
+	trial, err := cluster.CreateTrial(ctx, exp, map[string]int64{"x": 5, "y": 2})
+	require.NoError(t, err)
+	require.NoError(t, cluster.WaitForTrial(ctx, trial, redskyv1beta1.TrialComplete, 5*time.Minute))
+
+	// The trial job sleeps, so the duration should be recorded
+	require.Len(t, trial.Spec.Values, 1)
+	assert.Equal(t, "duration", trial.Spec.Values[0].Name)
+	assert.NotEmpty(t, trial.Spec.Values[0].Value)
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SimulationExperiment returns an experiment which does not modify any application, trials just run the default trial
+// job (which sleeps) and report how long they took
+func SimulationExperiment(name, namespace string) *redskyv1beta1.Experiment {
+	return &redskyv1beta1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: redskyv1beta1.ExperimentSpec{
+			Parameters: []redskyv1beta1.Parameter{
+				{Name: "x", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(10)},
+				{Name: "y", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(10)},
+			},
+			Metrics: []redskyv1beta1.Metric{
+				{Name: "duration", Minimize: true, Query: "{{duration .StartTime .CompletionTime}}"},
+			},
+		},
+	}
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+)
+
+const (
+	// BoundsEdgeFraction is the fraction of a parameter's range considered to be "at" one of its bounds
+	BoundsEdgeFraction = 0.05
+	// BoundsExpansionFraction is the fraction of a parameter's range a limiting bound is expanded by
+	BoundsExpansionFraction = 0.5
+)
+
+// Dimension is a numeric parameter whose bounds may be expanded
+type Dimension struct {
+	// Name is the name of the parameter
+	Name string
+	// Min is the current lower bound
+	Min float64
+	// Max is the current upper bound
+	Max float64
+	// Integer indicates the bounds must be whole numbers
+	Integer bool
+}
+
+// Objective is a metric being optimized
+type Objective struct {
+	// Name is the name of the metric
+	Name string
+	// Minimize indicates lower values are better
+	Minimize bool
+}
+
+// Observation is the outcome of a single completed trial
+type Observation struct {
+	// Assignments are the numeric parameter assignments of the trial
+	Assignments map[string]float64
+	// Values are the metric values of the trial
+	Values map[string]float64
+}
+
+// BoundsSuggestion is a proposed expansion of a parameter's bounds for a follow-up experiment
+type BoundsSuggestion struct {
+	// Parameter is the name of the parameter
+	Parameter string `json:"parameter"`
+	// Min is the current lower bound
+	Min float64 `json:"min"`
+	// Max is the current upper bound
+	Max float64 `json:"max"`
+	// SuggestedMin is the proposed lower bound
+	SuggestedMin float64 `json:"suggestedMin"`
+	// SuggestedMax is the proposed upper bound
+	SuggestedMax float64 `json:"suggestedMax"`
+	// AtLowerBound is the number of best trials whose assignment was at the lower bound
+	AtLowerBound int `json:"atLowerBound,omitempty"`
+	// AtUpperBound is the number of best trials whose assignment was at the upper bound
+	AtUpperBound int `json:"atUpperBound,omitempty"`
+	// BestTrials is the number of best trials considered
+	BestTrials int `json:"bestTrials"`
+}
+
+// String returns a short human readable description of the suggestion
+func (s *BoundsSuggestion) String() string {
+	var edges []string
+	if s.SuggestedMin != s.Min {
+		edges = append(edges, "lower")
+	}
+	if s.SuggestedMax != s.Max {
+		edges = append(edges, "upper")
+	}
+	return fmt.Sprintf("%s: best trials at %s bound, consider [%s, %s]", s.Parameter, strings.Join(edges, " and "),
+		formatBound(s.SuggestedMin), formatBound(s.SuggestedMax))
+}
+
+// SuggestBounds identifies parameters whose best observations cluster at one of the bounds and proposes expanded bounds
+func SuggestBounds(dimensions []Dimension, objectives []Objective, observations []Observation) []BoundsSuggestion {
+	best := BestObservations(objectives, observations)
+	if len(best) == 0 {
+		return nil
+	}
+
+	var suggestions []BoundsSuggestion
+	for _, d := range dimensions {
+		width := d.Max - d.Min
+		if width <= 0 {
+			continue
+		}
+
+		s := BoundsSuggestion{Parameter: d.Name, Min: d.Min, Max: d.Max, SuggestedMin: d.Min, SuggestedMax: d.Max, BestTrials: len(best)}
+		edge := width * BoundsEdgeFraction
+		for _, o := range best {
+			v, ok := o.Assignments[d.Name]
+			if !ok {
+				continue
+			}
+			if v-d.Min <= edge {
+				s.AtLowerBound++
+			}
+			if d.Max-v <= edge {
+				s.AtUpperBound++
+			}
+		}
+
+		// A majority of the best observations must be at the bound
+		if s.AtLowerBound*2 > len(best) {
+			s.SuggestedMin = d.Min - width*BoundsExpansionFraction
+			if d.Min >= 0 && s.SuggestedMin < 0 {
+				s.SuggestedMin = 0
+			}
+			if d.Integer {
+				s.SuggestedMin = math.Floor(s.SuggestedMin)
+			}
+		}
+		if s.AtUpperBound*2 > len(best) {
+			s.SuggestedMax = d.Max + width*BoundsExpansionFraction
+			if d.Integer {
+				s.SuggestedMax = math.Ceil(s.SuggestedMax)
+			}
+		}
+
+		if s.SuggestedMin != s.Min || s.SuggestedMax != s.Max {
+			suggestions = append(suggestions, s)
+		}
+	}
+	return suggestions
+}
+
+// BestObservations returns the best observations: the top decile (at least one) of a single objective or the
+// non-dominated observations of multiple objectives
+func BestObservations(objectives []Objective, observations []Observation) []Observation {
+	// Only consider observations that have a value for every objective
+	var candidates []Observation
+	for _, o := range observations {
+		complete := true
+		for _, obj := range objectives {
+			if _, ok := o.Values[obj.Name]; !ok {
+				complete = false
+			}
+		}
+		if complete {
+			candidates = append(candidates, o)
+		}
+	}
+	if len(objectives) == 0 || len(candidates) == 0 {
+		return nil
+	}
+
+	score := func(o Observation, obj Objective) float64 {
+		if obj.Minimize {
+			return o.Values[obj.Name]
+		}
+		return -o.Values[obj.Name]
+	}
+
+	if len(objectives) == 1 {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return score(candidates[i], objectives[0]) < score(candidates[j], objectives[0])
+		})
+		n := len(candidates) / 10
+		if n < 1 {
+			n = 1
+		}
+		return candidates[:n]
+	}
+
+	var front []Observation
+	for i := range candidates {
+		dominated := false
+		for j := range candidates {
+			if i != j && Dominates(objectives, candidates[j].Values, candidates[i].Values) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front = append(front, candidates[i])
+		}
+	}
+	return front
+}
+
+// Dominates checks to see if the values of a are at least as good as b for every objective and strictly better
+// for at least one
+func Dominates(objectives []Objective, a, b map[string]float64) bool {
+	better := false
+	for _, obj := range objectives {
+		va, vb := a[obj.Name], b[obj.Name]
+		if !obj.Minimize {
+			va, vb = -va, -vb
+		}
+		if va > vb {
+			return false
+		} else if va < vb {
+			better = true
+		}
+	}
+	return better
+}
+
+// ExperimentObservations extracts the numeric dimensions, objectives and completed observations from an experiment
+func ExperimentObservations(exp *experimentsv1alpha1.Experiment, trials []experimentsv1alpha1.TrialItem) ([]Dimension, []Objective, []Observation) {
+	var dimensions []Dimension
+	for _, p := range exp.Parameters {
+		if p.Type == experimentsv1alpha1.ParameterTypeCategorical {
+			continue
+		}
+		min, minErr := p.Bounds.Min.Float64()
+		max, maxErr := p.Bounds.Max.Float64()
+		if minErr != nil || maxErr != nil {
+			continue
+		}
+		dimensions = append(dimensions, Dimension{Name: p.Name, Min: min, Max: max, Integer: p.Type == experimentsv1alpha1.ParameterTypeInteger})
+	}
+
+	objectives := make([]Objective, 0, len(exp.Metrics))
+	for _, m := range exp.Metrics {
+		objectives = append(objectives, Objective{Name: m.Name, Minimize: m.Minimize})
+	}
+
+	var observations []Observation
+	for i := range trials {
+		if trials[i].Status != experimentsv1alpha1.TrialCompleted {
+			continue
+		}
+		o := Observation{Assignments: make(map[string]float64), Values: make(map[string]float64)}
+		for _, a := range trials[i].Assignments {
+			if f, err := a.Value.Float64(); err == nil {
+				o.Assignments[a.ParameterName] = f
+			}
+		}
+		for _, v := range trials[i].Values {
+			o.Values[v.MetricName] = v.Value
+		}
+		observations = append(observations, o)
+	}
+
+	return dimensions, objectives, observations
+}
+
+// formatBound formats a bound without unnecessary precision
+func formatBound(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggestBounds(t *testing.T) {
+	obs := func(cpu, mem, cost float64) Observation {
+		return Observation{
+			Assignments: map[string]float64{"cpu": cpu, "memory": mem},
+			Values:      map[string]float64{"cost": cost},
+		}
+	}
+	dimensions := []Dimension{
+		{Name: "cpu", Min: 100, Max: 1000, Integer: true},
+		{Name: "memory", Min: 0.5, Max: 2},
+	}
+	objectives := []Objective{{Name: "cost", Minimize: true}}
+
+	cases := []struct {
+		desc         string
+		observations []Observation
+		expected     []BoundsSuggestion
+	}{
+		{
+			desc: "no observations",
+		},
+		{
+			desc:         "interior",
+			observations: []Observation{obs(500, 1, 1), obs(100, 2, 5)},
+		},
+		{
+			desc:         "lower bound",
+			observations: []Observation{obs(110, 1, 1), obs(900, 1.5, 5)},
+			expected: []BoundsSuggestion{
+				{Parameter: "cpu", Min: 100, Max: 1000, SuggestedMin: 0, SuggestedMax: 1000, AtLowerBound: 1, BestTrials: 1},
+			},
+		},
+		{
+			desc:         "upper bound",
+			observations: []Observation{obs(500, 2, 1), obs(900, 1, 5)},
+			expected: []BoundsSuggestion{
+				{Parameter: "memory", Min: 0.5, Max: 2, SuggestedMin: 0.5, SuggestedMax: 2.75, AtUpperBound: 1, BestTrials: 1},
+			},
+		},
+		{
+			desc:         "missing values",
+			observations: []Observation{{Assignments: map[string]float64{"cpu": 100}}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.expected, SuggestBounds(dimensions, objectives, c.observations))
+		})
+	}
+}
+
+func TestBestObservations(t *testing.T) {
+	obs := func(n, a, b float64) Observation {
+		return Observation{
+			Assignments: map[string]float64{"n": n},
+			Values:      map[string]float64{"a": a, "b": b},
+		}
+	}
+	observations := []Observation{obs(1, 1, 5), obs(2, 2, 2), obs(3, 5, 1), obs(4, 3, 3)}
+
+	cases := []struct {
+		desc       string
+		objectives []Objective
+		expected   []float64
+	}{
+		{
+			desc:       "minimize",
+			objectives: []Objective{{Name: "a", Minimize: true}},
+			expected:   []float64{1},
+		},
+		{
+			desc:       "maximize",
+			objectives: []Objective{{Name: "a"}},
+			expected:   []float64{3},
+		},
+		{
+			desc:       "pareto",
+			objectives: []Objective{{Name: "a", Minimize: true}, {Name: "b", Minimize: true}},
+			expected:   []float64{1, 2, 3},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			var actual []float64
+			for _, o := range BestObservations(c.objectives, observations) {
+				actual = append(actual, o.Assignments["n"])
+			}
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
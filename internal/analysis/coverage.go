@@ -0,0 +1,168 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"encoding/json"
+	"math"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+)
+
+// DefaultCoverageBins is the default number of histogram bins used to summarize a numeric parameter
+const DefaultCoverageBins = 10
+
+// Coverage describes the parameter space of an experiment and how much of it was explored by the trials
+type Coverage struct {
+	// Experiment is the name of the experiment
+	Experiment string `json:"experiment"`
+	// Trials is the number of trials considered
+	Trials int `json:"trials"`
+	// Parameters is the coverage of each parameter
+	Parameters []ParameterCoverage `json:"parameters"`
+}
+
+// ParameterCoverage describes the domain of a single parameter and how much of it was explored by the trials
+type ParameterCoverage struct {
+	// Name is the name of the parameter
+	Name string `json:"name"`
+	// Type is the type of the parameter
+	Type experimentsv1alpha1.ParameterType `json:"type"`
+	// Min is the lower bound of a numeric parameter
+	Min float64 `json:"min,omitempty"`
+	// Max is the upper bound of a numeric parameter
+	Max float64 `json:"max,omitempty"`
+	// Values are the allowed values of a categorical parameter
+	Values []string `json:"values,omitempty"`
+	// Sensitive indicates the observed values have been omitted
+	Sensitive bool `json:"sensitive,omitempty"`
+	// ObservedMin is the smallest value assigned to a numeric parameter
+	ObservedMin *float64 `json:"observedMin,omitempty"`
+	// ObservedMax is the largest value assigned to a numeric parameter
+	ObservedMax *float64 `json:"observedMax,omitempty"`
+	// Histogram is the number of assignments in each equal width bin of a numeric parameter's domain
+	Histogram []int `json:"histogram,omitempty"`
+	// Counts is the number of assignments of each categorical value
+	Counts map[string]int `json:"counts,omitempty"`
+	// Coverage is the fraction of the domain that was explored: the observed range relative to the bounds for numeric
+	// parameters or the fraction of values assigned at least once for categorical parameters
+	Coverage float64 `json:"coverage"`
+}
+
+// ExperimentCoverage computes the parameter coverage of the supplied trials
+func ExperimentCoverage(exp *experimentsv1alpha1.Experiment, trials []experimentsv1alpha1.TrialItem, bins int) *Coverage {
+	if bins <= 0 {
+		bins = DefaultCoverageBins
+	}
+
+	c := &Coverage{Experiment: exp.DisplayName, Trials: len(trials)}
+	for i := range exp.Parameters {
+		p := &exp.Parameters[i]
+		if p.Type == experimentsv1alpha1.ParameterTypeCategorical {
+			c.Parameters = append(c.Parameters, categoricalCoverage(p, trials))
+		} else {
+			c.Parameters = append(c.Parameters, numericCoverage(p, trials, bins))
+		}
+	}
+	return c
+}
+
+func numericCoverage(p *experimentsv1alpha1.Parameter, trials []experimentsv1alpha1.TrialItem, bins int) ParameterCoverage {
+	pc := ParameterCoverage{Name: p.Name, Type: p.Type, Sensitive: p.Sensitive, Histogram: make([]int, bins)}
+	pc.Min, _ = p.Bounds.Min.Float64()
+	pc.Max, _ = p.Bounds.Max.Float64()
+	width := pc.Max - pc.Min
+
+	var observedMin, observedMax float64
+	var observed bool
+	for _, v := range assignedValues(p.Name, trials) {
+		f, err := v.Float64()
+		if err != nil {
+			continue
+		}
+		if !observed || f < observedMin {
+			observedMin = f
+		}
+		if !observed || f > observedMax {
+			observedMax = f
+		}
+		observed = true
+
+		b := 0
+		if width > 0 {
+			b = int(math.Floor((f - pc.Min) / width * float64(bins)))
+		}
+		if b < 0 {
+			b = 0
+		} else if b >= bins {
+			b = bins - 1
+		}
+		pc.Histogram[b]++
+	}
+
+	if observed {
+		if width > 0 {
+			pc.Coverage = (observedMax - observedMin) / width
+		} else {
+			pc.Coverage = 1
+		}
+		if !p.Sensitive {
+			pc.ObservedMin, pc.ObservedMax = &observedMin, &observedMax
+		}
+	}
+	if p.Sensitive {
+		pc.Histogram = nil
+	}
+	return pc
+}
+
+func categoricalCoverage(p *experimentsv1alpha1.Parameter, trials []experimentsv1alpha1.TrialItem) ParameterCoverage {
+	pc := ParameterCoverage{Name: p.Name, Type: p.Type, Sensitive: p.Sensitive, Values: p.Values}
+	counts := make(map[string]int, len(p.Values))
+	for _, v := range assignedValues(p.Name, trials) {
+		counts[v.String()]++
+	}
+
+	var explored int
+	for _, v := range p.Values {
+		if counts[v] > 0 {
+			explored++
+		}
+	}
+	if len(p.Values) > 0 {
+		pc.Coverage = float64(explored) / float64(len(p.Values))
+	}
+	if !p.Sensitive {
+		pc.Counts = counts
+	} else {
+		pc.Values = nil
+	}
+	return pc
+}
+
+// assignedValues returns all of the values assigned to the named parameter
+func assignedValues(name string, trials []experimentsv1alpha1.TrialItem) []json.Number {
+	var values []json.Number
+	for i := range trials {
+		for _, a := range trials[i].Assignments {
+			if a.ParameterName == name {
+				values = append(values, a.Value)
+			}
+		}
+	}
+	return values
+}
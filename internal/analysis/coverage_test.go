@@ -0,0 +1,93 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"encoding/json"
+	"testing"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExperimentCoverage(t *testing.T) {
+	trial := func(a, b string) experimentsv1alpha1.TrialItem {
+		return experimentsv1alpha1.TrialItem{TrialAssignments: experimentsv1alpha1.TrialAssignments{
+			Assignments: []experimentsv1alpha1.Assignment{
+				{ParameterName: "a", Value: json.Number(a)},
+				{ParameterName: "b", Value: json.Number(b)},
+			},
+		}}
+	}
+	f := func(v float64) *float64 { return &v }
+
+	cases := []struct {
+		desc     string
+		params   []experimentsv1alpha1.Parameter
+		trials   []experimentsv1alpha1.TrialItem
+		expected []ParameterCoverage
+	}{
+		{
+			desc: "numeric",
+			params: []experimentsv1alpha1.Parameter{
+				{Name: "a", Type: experimentsv1alpha1.ParameterTypeInteger, Bounds: experimentsv1alpha1.Bounds{Min: "0", Max: "100"}},
+			},
+			trials: []experimentsv1alpha1.TrialItem{trial("10", "x"), trial("30", "y"), trial("100", "x")},
+			expected: []ParameterCoverage{
+				{Name: "a", Type: experimentsv1alpha1.ParameterTypeInteger, Max: 100, ObservedMin: f(10), ObservedMax: f(100), Histogram: []int{0, 1, 0, 1, 0, 0, 0, 0, 0, 1}, Coverage: 0.9},
+			},
+		},
+		{
+			desc: "categorical",
+			params: []experimentsv1alpha1.Parameter{
+				{Name: "b", Type: experimentsv1alpha1.ParameterTypeCategorical, Values: []string{"x", "y", "z", "w"}},
+			},
+			trials: []experimentsv1alpha1.TrialItem{trial("10", "x"), trial("30", "y"), trial("100", "x")},
+			expected: []ParameterCoverage{
+				{Name: "b", Type: experimentsv1alpha1.ParameterTypeCategorical, Values: []string{"x", "y", "z", "w"}, Counts: map[string]int{"x": 2, "y": 1}, Coverage: 0.5},
+			},
+		},
+		{
+			desc: "sensitive",
+			params: []experimentsv1alpha1.Parameter{
+				{Name: "a", Type: experimentsv1alpha1.ParameterTypeInteger, Bounds: experimentsv1alpha1.Bounds{Min: "0", Max: "100"}, Sensitive: true},
+			},
+			trials: []experimentsv1alpha1.TrialItem{trial("25", "x"), trial("75", "y")},
+			expected: []ParameterCoverage{
+				{Name: "a", Type: experimentsv1alpha1.ParameterTypeInteger, Max: 100, Sensitive: true, Coverage: 0.5},
+			},
+		},
+		{
+			desc: "unexplored",
+			params: []experimentsv1alpha1.Parameter{
+				{Name: "c", Type: experimentsv1alpha1.ParameterTypeDouble, Bounds: experimentsv1alpha1.Bounds{Min: "0.5", Max: "1.5"}},
+			},
+			trials: []experimentsv1alpha1.TrialItem{trial("10", "x")},
+			expected: []ParameterCoverage{
+				{Name: "c", Type: experimentsv1alpha1.ParameterTypeDouble, Min: 0.5, Max: 1.5, Histogram: make([]int, DefaultCoverageBins)},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			exp := &experimentsv1alpha1.Experiment{Parameters: c.params}
+			cov := ExperimentCoverage(exp, c.trials, 0)
+			assert.Equal(t, len(c.trials), cov.Trials)
+			assert.Equal(t, c.expected, cov.Parameters)
+		})
+	}
+}
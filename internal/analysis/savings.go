@@ -0,0 +1,101 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"fmt"
+	"math"
+)
+
+// Delta is the change in a metric between a baseline configuration and a candidate configuration
+type Delta struct {
+	// Metric is the name of the metric
+	Metric string `json:"metric"`
+	// Baseline is the mean value of the baseline measurements
+	Baseline float64 `json:"baseline"`
+	// Candidate is the mean value of the candidate measurements
+	Candidate float64 `json:"candidate"`
+	// Difference is the candidate value less the baseline value
+	Difference float64 `json:"difference"`
+	// Relative is the difference as a fraction of the baseline value, zero when the baseline value is zero
+	Relative float64 `json:"relative"`
+	// Lower is the lower bound of the confidence interval of the difference, if it could be computed
+	Lower *float64 `json:"lower,omitempty"`
+	// Upper is the upper bound of the confidence interval of the difference, if it could be computed
+	Upper *float64 `json:"upper,omitempty"`
+}
+
+// CompareMeans computes the difference between the means of the baseline and candidate measurements; a confidence
+// interval is included when both sets contain at least two measurements (e.g. from replicated trials)
+func CompareMeans(metric string, baseline, candidate []float64, confidence float64) (*Delta, error) {
+	if len(baseline) == 0 || len(candidate) == 0 {
+		return nil, fmt.Errorf("measurements of metric %s are required", metric)
+	}
+
+	d := &Delta{Metric: metric, Baseline: mean(baseline), Candidate: mean(candidate)}
+	d.Difference = d.Candidate - d.Baseline
+	if d.Baseline != 0 {
+		d.Relative = d.Difference / math.Abs(d.Baseline)
+	}
+
+	if len(baseline) > 1 && len(candidate) > 1 {
+		lower, upper, err := DifferenceInterval(baseline, candidate, confidence)
+		if err != nil {
+			return nil, err
+		}
+		d.Lower, d.Upper = &lower, &upper
+	}
+	return d, nil
+}
+
+// DifferenceInterval returns the Welch confidence interval of the difference between the means of b and a
+func DifferenceInterval(a, b []float64, confidence float64) (float64, float64, error) {
+	if len(a) < 2 || len(b) < 2 {
+		return 0, 0, fmt.Errorf("at least two measurements of each configuration are required, found %d and %d", len(a), len(b))
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return 0, 0, fmt.Errorf("confidence level must be between 0 and 1: %g", confidence)
+	}
+
+	diff := mean(b) - mean(a)
+	na, nb := float64(len(a)), float64(len(b))
+	va, vb := variance(a)/na, variance(b)/nb
+	if va+vb == 0 {
+		return diff, diff, nil
+	}
+
+	df := (va + vb) * (va + vb) / (va*va/(na-1) + vb*vb/(nb-1))
+	margin := studentQuantile(1-confidence, df) * math.Sqrt(va+vb)
+	return diff - margin, diff + margin, nil
+}
+
+// studentQuantile returns the critical value of the Student's t distribution for a two-sided tail probability
+func studentQuantile(p, df float64) float64 {
+	lo, hi := 0.0, 1.0
+	for studentTwoSided(hi, df) > p && hi < 1e6 {
+		lo, hi = hi, hi*2
+	}
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if studentTwoSided(mid, df) > p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
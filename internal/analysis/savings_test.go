@@ -0,0 +1,96 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareMeans(t *testing.T) {
+	cases := []struct {
+		desc       string
+		baseline   []float64
+		candidate  []float64
+		difference float64
+		relative   float64
+		lower      float64
+		upper      float64
+		interval   bool
+		err        bool
+	}{
+		{
+			desc:       "single measurements",
+			baseline:   []float64{10},
+			candidate:  []float64{8},
+			difference: -2,
+			relative:   -0.2,
+		},
+		{
+			desc:       "replicates",
+			baseline:   []float64{10, 12, 11},
+			candidate:  []float64{8, 9, 7},
+			difference: -3,
+			relative:   -0.2727,
+			lower:      -5.2670,
+			upper:      -0.7330,
+			interval:   true,
+		},
+		{
+			desc:       "identical replicates",
+			baseline:   []float64{5, 5},
+			candidate:  []float64{4, 4},
+			difference: -1,
+			relative:   -0.2,
+			lower:      -1,
+			upper:      -1,
+			interval:   true,
+		},
+		{
+			desc:      "missing",
+			baseline:  []float64{10},
+			candidate: nil,
+			err:       true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			d, err := CompareMeans("m", c.baseline, c.candidate, 0.95)
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.InDelta(t, c.difference, d.Difference, 0.0001)
+				assert.InDelta(t, c.relative, d.Relative, 0.0001)
+				if c.interval && assert.NotNil(t, d.Lower) && assert.NotNil(t, d.Upper) {
+					assert.InDelta(t, c.lower, *d.Lower, 0.0001)
+					assert.InDelta(t, c.upper, *d.Upper, 0.0001)
+				} else {
+					assert.Nil(t, d.Lower)
+					assert.Nil(t, d.Upper)
+				}
+			}
+		})
+	}
+}
+
+func TestStudentQuantile(t *testing.T) {
+	assert.InDelta(t, 2.2281, studentQuantile(0.05, 10), 0.0001)
+	assert.InDelta(t, 1.9600, studentQuantile(0.05, 1e6), 0.0001)
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package analysis contains helpers for interpreting experiment results.
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Test identifies a statistical test
+type Test string
+
+const (
+	// WelchTTest is Welch's unequal variances t-test
+	WelchTTest Test = "t-test"
+	// MannWhitneyU is the non-parametric Mann-Whitney U test
+	MannWhitneyU Test = "mann-whitney"
+)
+
+// Significance is the result of comparing two sets of measurements
+type Significance struct {
+	// Test is the statistical test that was performed
+	Test Test `json:"test"`
+	// Statistic is the value of the test statistic
+	Statistic float64 `json:"statistic"`
+	// PValue is the two-sided probability of observing the difference if there was no actual difference
+	PValue float64 `json:"pValue"`
+	// Alpha is the significance level the p-value is compared against
+	Alpha float64 `json:"alpha"`
+	// Significant is true if the p-value is below the significance level
+	Significant bool `json:"significant"`
+	// MeanA is the mean of the first set of measurements
+	MeanA float64 `json:"meanA"`
+	// MeanB is the mean of the second set of measurements
+	MeanB float64 `json:"meanB"`
+}
+
+// Compare runs the requested test on two sets of measurements
+func Compare(test Test, a, b []float64, alpha float64) (*Significance, error) {
+	if len(a) < 2 || len(b) < 2 {
+		return nil, fmt.Errorf("at least two measurements of each trial are required, found %d and %d", len(a), len(b))
+	}
+	if alpha <= 0 || alpha >= 1 {
+		return nil, fmt.Errorf("significance level must be between 0 and 1: %g", alpha)
+	}
+
+	s := &Significance{Test: test, Alpha: alpha, MeanA: mean(a), MeanB: mean(b)}
+	switch test {
+	case WelchTTest, "":
+		s.Test = WelchTTest
+		s.Statistic, s.PValue = welch(a, b)
+	case MannWhitneyU:
+		s.Statistic, s.PValue = mannWhitney(a, b)
+	default:
+		return nil, fmt.Errorf("unknown test: %s", test)
+	}
+	s.Significant = s.PValue < alpha
+	return s, nil
+}
+
+// welch returns the t statistic and p-value of Welch's t-test
+func welch(a, b []float64) (float64, float64) {
+	na, nb := float64(len(a)), float64(len(b))
+	va, vb := variance(a)/na, variance(b)/nb
+	if va+vb == 0 {
+		if mean(a) == mean(b) {
+			return 0, 1
+		}
+		return math.Inf(1), 0
+	}
+
+	t := (mean(a) - mean(b)) / math.Sqrt(va+vb)
+	df := (va + vb) * (va + vb) / (va*va/(na-1) + vb*vb/(nb-1))
+	return t, studentTwoSided(t, df)
+}
+
+// mannWhitney returns the U statistic and the p-value using the tie corrected normal approximation
+func mannWhitney(a, b []float64) (float64, float64) {
+	type obs struct {
+		value float64
+		first bool
+	}
+	all := make([]obs, 0, len(a)+len(b))
+	for _, v := range a {
+		all = append(all, obs{value: v, first: true})
+	}
+	for _, v := range b {
+		all = append(all, obs{value: v})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	// Assign average ranks to ties
+	var rankSum, tieCorrection float64
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].first {
+				rankSum += rank
+			}
+		}
+		t := float64(j - i)
+		tieCorrection += t*t*t - t
+		i = j
+	}
+
+	na, nb := float64(len(a)), float64(len(b))
+	n := na + nb
+	u := rankSum - na*(na+1)/2
+	mu := na * nb / 2
+	sigma := math.Sqrt(na * nb / 12 * ((n + 1) - tieCorrection/(n*(n-1))))
+	if sigma == 0 {
+		return u, 1
+	}
+	z := (math.Abs(u-mu) - 0.5) / sigma
+	if z < 0 {
+		z = 0
+	}
+	return u, math.Erfc(z / math.Sqrt2)
+}
+
+// studentTwoSided returns the two-sided tail probability of the Student's t distribution
+func studentTwoSided(t, df float64) float64 {
+	return regularizedIncompleteBeta(df/2, 0.5, df/(df+t*t))
+}
+
+// regularizedIncompleteBeta evaluates I_x(a, b) using a continued fraction
+func regularizedIncompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lbeta, _ := math.Lgamma(a + b)
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	front := math.Exp(lbeta - la - lb + a*math.Log(x) + b*math.Log(1-x))
+
+	// Use the symmetry relation for faster convergence
+	if x > (a+1)/(a+b+2) {
+		return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+	}
+	return front * betaContinuedFraction(a, b, x) / a
+}
+
+// betaContinuedFraction is the modified Lentz evaluation of the incomplete beta continued fraction
+func betaContinuedFraction(a, b, x float64) float64 {
+	const epsilon, tiny = 1e-14, 1e-300
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1.0; m <= 300; m++ {
+		// Even step
+		num := m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		// Odd step
+		num = -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
+
+func mean(x []float64) float64 {
+	var sum float64
+	for _, v := range x {
+		sum += v
+	}
+	return sum / float64(len(x))
+}
+
+func variance(x []float64) float64 {
+	m := mean(x)
+	var ss float64
+	for _, v := range x {
+		ss += (v - m) * (v - m)
+	}
+	return ss / float64(len(x)-1)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	a := []float64{19.8, 20.4, 19.6, 17.8, 18.5, 18.9, 18.3, 18.9, 19.5, 22.0}
+	b := []float64{28.2, 26.6, 20.1, 23.3, 25.2, 22.1, 17.7, 27.6, 20.6, 13.7, 23.2, 17.5, 20.6, 18.0, 23.9, 21.6, 24.3, 20.4, 23.9, 13.3}
+
+	cases := []struct {
+		desc        string
+		test        Test
+		a, b        []float64
+		statistic   float64
+		pValue      float64
+		significant bool
+		err         bool
+	}{
+		{
+			desc:        "Welch",
+			test:        WelchTTest,
+			a:           a,
+			b:           b,
+			statistic:   -2.2255,
+			pValue:      0.0355,
+			significant: true,
+		},
+		{
+			desc:        "MannWhitney",
+			test:        MannWhitneyU,
+			a:           []float64{1, 2, 3, 4, 5},
+			b:           []float64{6, 7, 8, 9, 10},
+			statistic:   0,
+			pValue:      0.0122,
+			significant: true,
+		},
+		{
+			desc:        "Identical",
+			test:        WelchTTest,
+			a:           []float64{1, 1, 1},
+			b:           []float64{1, 1, 1},
+			statistic:   0,
+			pValue:      1,
+			significant: false,
+		},
+		{
+			desc: "TooFewSamples",
+			test: WelchTTest,
+			a:    []float64{1},
+			b:    []float64{1, 2},
+			err:  true,
+		},
+		{
+			desc: "UnknownTest",
+			test: "z-test",
+			a:    a,
+			b:    b,
+			err:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			s, err := Compare(c.test, c.a, c.b, 0.05)
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.InDelta(t, c.statistic, s.Statistic, 0.001)
+				assert.InDelta(t, c.pValue, s.PValue, 0.001)
+				assert.Equal(t, c.significant, s.Significant)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"context"
+	"fmt"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+)
+
+// CompareTrials tests whether the difference in a metric between two trials is statistically significant. The
+// measurements of each trial are taken from all of the completed trials which share the same assignments.
+func CompareTrials(ctx context.Context, api experimentsv1alpha1.API, experimentName string, a, b int64, metric string, test Test, alpha float64) (*Significance, error) {
+	exp, err := api.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName(experimentName))
+	if err != nil {
+		return nil, err
+	}
+
+	if metric == "" {
+		if len(exp.Metrics) != 1 {
+			return nil, fmt.Errorf("a metric name is required for experiment %s", experimentName)
+		}
+		metric = exp.Metrics[0].Name
+	}
+
+	q := &experimentsv1alpha1.TrialListQuery{Status: []experimentsv1alpha1.TrialStatus{experimentsv1alpha1.TrialCompleted}}
+	tl, err := api.GetAllTrials(ctx, exp.TrialsURL, q)
+	if err != nil {
+		return nil, err
+	}
+
+	sa, err := Replicates(tl.Trials, a, metric)
+	if err != nil {
+		return nil, err
+	}
+	sb, err := Replicates(tl.Trials, b, metric)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(test, sa, sb, alpha)
+}
+
+// Replicates returns the metric values of every trial with the same assignments as the specified trial
+func Replicates(trials []experimentsv1alpha1.TrialItem, number int64, metric string) ([]float64, error) {
+	var target *experimentsv1alpha1.TrialItem
+	for i := range trials {
+		if trials[i].Number == number {
+			target = &trials[i]
+			break
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("unable to find completed trial %d", number)
+	}
+
+	var samples []float64
+	for i := range trials {
+		if !sameAssignments(target.Assignments, trials[i].Assignments) {
+			continue
+		}
+		for _, v := range trials[i].Values {
+			if v.MetricName == metric {
+				samples = append(samples, v.Value)
+			}
+		}
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("trial %d has no value for metric %s", number, metric)
+	}
+	return samples, nil
+}
+
+// sameAssignments checks if two sets of assignments are equivalent
+func sameAssignments(a, b []experimentsv1alpha1.Assignment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	values := make(map[string]string, len(a))
+	for _, v := range a {
+		values[v.ParameterName] = v.Value.String()
+	}
+	for _, v := range b {
+		if av, ok := values[v.ParameterName]; !ok || av != v.Value.String() {
+			return false
+		}
+	}
+	return true
+}
+
+// ParetoTrials returns the completed trials which are not dominated by any other completed trial across all of the
+// experiment's metrics, trials missing a value for any metric are not considered
+func ParetoTrials(exp *experimentsv1alpha1.Experiment, trials []experimentsv1alpha1.TrialItem) []experimentsv1alpha1.TrialItem {
+	objectives := make([]Objective, 0, len(exp.Metrics))
+	for _, m := range exp.Metrics {
+		objectives = append(objectives, Objective{Name: m.Name, Minimize: m.Minimize})
+	}
+	if len(objectives) == 0 {
+		return nil
+	}
+
+	// Collect the metric values of each eligible trial
+	var candidates []int
+	values := make(map[int]map[string]float64, len(trials))
+	for i := range trials {
+		if trials[i].Status != experimentsv1alpha1.TrialCompleted {
+			continue
+		}
+		v := make(map[string]float64, len(trials[i].Values))
+		for _, tv := range trials[i].Values {
+			v[tv.MetricName] = tv.Value
+		}
+		complete := true
+		for _, obj := range objectives {
+			if _, ok := v[obj.Name]; !ok {
+				complete = false
+			}
+		}
+		if complete {
+			candidates = append(candidates, i)
+			values[i] = v
+		}
+	}
+
+	var front []experimentsv1alpha1.TrialItem
+	for _, i := range candidates {
+		dominated := false
+		for _, j := range candidates {
+			if i != j && Dominates(objectives, values[j], values[i]) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front = append(front, trials[i])
+		}
+	}
+	return front
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analysis
+
+import (
+	"testing"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParetoTrials(t *testing.T) {
+	trial := func(n int64, status experimentsv1alpha1.TrialStatus, cost, duration float64) experimentsv1alpha1.TrialItem {
+		return experimentsv1alpha1.TrialItem{
+			Number: n,
+			Status: status,
+			TrialValues: experimentsv1alpha1.TrialValues{
+				Values: []experimentsv1alpha1.Value{
+					{MetricName: "cost", Value: cost},
+					{MetricName: "duration", Value: duration},
+				},
+			},
+		}
+	}
+	trials := []experimentsv1alpha1.TrialItem{
+		trial(1, experimentsv1alpha1.TrialCompleted, 1, 5),
+		trial(2, experimentsv1alpha1.TrialCompleted, 3, 3),
+		trial(3, experimentsv1alpha1.TrialCompleted, 5, 1),
+		trial(4, experimentsv1alpha1.TrialCompleted, 4, 4),
+		trial(5, experimentsv1alpha1.TrialFailed, 0, 0),
+		{Number: 6, Status: experimentsv1alpha1.TrialCompleted},
+	}
+
+	cases := []struct {
+		desc     string
+		metrics  []experimentsv1alpha1.Metric
+		expected []int64
+	}{
+		{
+			desc: "no metrics",
+		},
+		{
+			desc:     "single metric",
+			metrics:  []experimentsv1alpha1.Metric{{Name: "cost", Minimize: true}},
+			expected: []int64{1},
+		},
+		{
+			desc:     "minimize both",
+			metrics:  []experimentsv1alpha1.Metric{{Name: "cost", Minimize: true}, {Name: "duration", Minimize: true}},
+			expected: []int64{1, 2, 3},
+		},
+		{
+			desc:     "maximize one",
+			metrics:  []experimentsv1alpha1.Metric{{Name: "cost", Minimize: true}, {Name: "duration"}},
+			expected: []int64{1},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			exp := &experimentsv1alpha1.Experiment{Metrics: c.metrics}
+			var actual []int64
+			for _, ti := range ParetoTrials(exp, trials) {
+				actual = append(actual, ti.Number)
+			}
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit records the mutations made by the controller, both in the cluster and against the Red Sky API.
+package audit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// Event is a single audited mutation
+type Event struct {
+	// Time is when the mutation was performed
+	Time time.Time `json:"time"`
+	// Actor is the identity performing the mutation
+	Actor string `json:"actor,omitempty"`
+	// Action is the type of mutation, e.g. "create" or "patch" for cluster objects, the HTTP method for remote calls
+	Action string `json:"action"`
+	// Resource identifies the type of object being mutated, e.g. "apps/v1, Kind=Deployment"
+	Resource string `json:"resource,omitempty"`
+	// Namespace is the namespace of the mutated cluster object
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the mutated cluster object
+	Name string `json:"name,omitempty"`
+	// URL is the location of the remote API call
+	URL string `json:"url,omitempty"`
+	// StatusCode is the HTTP status code of the remote API call
+	StatusCode int `json:"statusCode,omitempty"`
+	// Error is the error message if the mutation failed
+	Error string `json:"error,omitempty"`
+}
+
+// Sink is a destination for audit events
+type Sink interface {
+	// Record persists a single audit event
+	Record(*Event) error
+}
+
+// NewSink returns a sink for the supplied target; an HTTP(S) URL is treated as a webhook and anything else as the
+// path to a file which events are appended to. The log is used to report events which cannot be delivered in the
+// background.
+func NewSink(target string, log logr.Logger) (Sink, error) {
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		return &WebhookSink{URL: target, Log: log}, nil
+	}
+
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &WriterSink{Writer: f}, nil
+}
+
+// WriterSink writes audit events as newline delimited JSON
+type WriterSink struct {
+	// Writer receives the encoded events
+	Writer io.Writer
+
+	mu sync.Mutex
+}
+
+// Record writes the event as a single line of JSON
+func (s *WriterSink) Record(e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.Writer.Write(append(b, '\n'))
+	return err
+}
+
+// DefaultWebhookQueueSize is the number of events which can be waiting to be posted to a webhook
+const DefaultWebhookQueueSize = 1024
+
+// WebhookSink posts each audit event as JSON to a remote endpoint. Events are queued and posted in the background so
+// a slow or unavailable webhook never blocks the mutation being audited; events are dropped when the queue is full.
+type WebhookSink struct {
+	// URL is the location events are posted to
+	URL string
+	// Client is used to post events, uses a client with a short timeout if nil
+	Client *http.Client
+	// QueueSize is the maximum number of events waiting to be posted, uses DefaultWebhookQueueSize if zero
+	QueueSize int
+	// Log is used to report events which could not be posted
+	Log logr.Logger
+
+	once    sync.Once
+	queue   chan []byte
+	dropped uint64
+}
+
+// Record queues the event to be posted to the webhook, an error is returned if the queue is full
+func (s *WebhookSink) Record(e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	s.once.Do(s.start)
+	select {
+	case s.queue <- b:
+		return nil
+	default:
+		return fmt.Errorf("audit webhook queue is full, %d events dropped", atomic.AddUint64(&s.dropped, 1))
+	}
+}
+
+// Dropped returns the number of events discarded because the queue was full
+func (s *WebhookSink) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// start creates the queue and begins posting events in the background
+func (s *WebhookSink) start() {
+	size := s.QueueSize
+	if size <= 0 {
+		size = DefaultWebhookQueueSize
+	}
+	s.queue = make(chan []byte, size)
+
+	go func() {
+		for b := range s.queue {
+			if err := s.post(b); err != nil && s.Log != nil {
+				s.Log.Error(err, "Failed to post audit event", "url", s.URL)
+			}
+		}
+	}()
+}
+
+// post sends a single encoded event to the webhook
+func (s *WebhookSink) post(b []byte) error {
+	c := s.Client
+	if c == nil {
+		c = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	resp, err := c.Post(s.URL, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("audit webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Auditor populates common event fields before recording events to a sink
+type Auditor struct {
+	// Sink is where events are recorded
+	Sink Sink
+	// Actor is the identity recorded for every event
+	Actor string
+	// Log is used to report events which could not be recorded
+	Log logr.Logger
+	// Now returns the current time, uses the system time if nil
+	Now func() time.Time
+}
+
+// Record fills in the time and actor of the event and records it to the sink; failures are logged instead of being
+// returned since the mutation has already occurred
+func (a *Auditor) Record(e *Event) {
+	if a == nil || a.Sink == nil {
+		return
+	}
+
+	if a.Now != nil {
+		e.Time = a.Now()
+	} else {
+		e.Time = time.Now()
+	}
+	e.Actor = a.Actor
+
+	if err := a.Sink.Record(e); err != nil && a.Log != nil {
+		a.Log.Error(err, "Failed to record audit event", "action", e.Action, "resource", e.Resource, "name", e.Name, "url", e.URL)
+	}
+}
+
+// ServiceAccountName returns the subject of a Kubernetes service account token, e.g.
+// "system:serviceaccount:redsky-system:default"; an empty string is returned if the token cannot be decoded
+func ServiceAccountName(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+
+	claims := struct {
+		Subject string `json:"sub"`
+	}{}
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return ""
+	}
+	return claims.Subject
+}
+
+// errorString returns the message of a possibly nil error
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
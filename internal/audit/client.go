@@ -0,0 +1,125 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"context"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// Client is a Kubernetes client that records every mutation it performs
+type Client struct {
+	client.Client
+	// Auditor records the mutations
+	Auditor *Auditor
+	// Scheme is used to determine the kind of typed objects
+	Scheme *runtime.Scheme
+}
+
+var _ client.Client = &Client{}
+
+// Create records the creation of an object
+func (c *Client) Create(ctx context.Context, obj runtime.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, opts...)
+	c.record("create", obj, err)
+	return err
+}
+
+// Update records the update of an object
+func (c *Client) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	err := c.Client.Update(ctx, obj, opts...)
+	c.record("update", obj, err)
+	return err
+}
+
+// Patch records the patch of an object
+func (c *Client) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	err := c.Client.Patch(ctx, obj, patch, opts...)
+	c.record("patch", obj, err)
+	return err
+}
+
+// Delete records the deletion of an object
+func (c *Client) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	c.record("delete", obj, err)
+	return err
+}
+
+// DeleteAllOf records the deletion of a collection of objects
+func (c *Client) DeleteAllOf(ctx context.Context, obj runtime.Object, opts ...client.DeleteAllOfOption) error {
+	err := c.Client.DeleteAllOf(ctx, obj, opts...)
+	c.record("deletecollection", obj, err)
+	return err
+}
+
+// Status returns a status writer that records status updates
+func (c *Client) Status() client.StatusWriter {
+	return &statusWriter{StatusWriter: c.Client.Status(), client: c}
+}
+
+// record creates an audit event for a mutation of the supplied object
+func (c *Client) record(action string, obj runtime.Object, err error) {
+	e := &Event{
+		Action:   action,
+		Resource: c.resource(obj),
+		Error:    errorString(err),
+	}
+	if acc, aerr := meta.Accessor(obj); aerr == nil {
+		e.Namespace = acc.GetNamespace()
+		e.Name = acc.GetName()
+	}
+	c.Auditor.Record(e)
+}
+
+// resource returns a description of the object type
+func (c *Client) resource(obj runtime.Object) string {
+	if gvk := obj.GetObjectKind().GroupVersionKind(); !gvk.Empty() {
+		return gvk.String()
+	}
+	if c.Scheme != nil {
+		if gvk, err := apiutil.GVKForObject(obj, c.Scheme); err == nil {
+			return gvk.String()
+		}
+	}
+	return reflect.TypeOf(obj).String()
+}
+
+// statusWriter records status updates
+type statusWriter struct {
+	client.StatusWriter
+	client *Client
+}
+
+// Update records the update of an object's status
+func (w *statusWriter) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	err := w.StatusWriter.Update(ctx, obj, opts...)
+	w.client.record("update/status", obj, err)
+	return err
+}
+
+// Patch records the patch of an object's status
+func (w *statusWriter) Patch(ctx context.Context, obj runtime.Object, patch client.Patch, opts ...client.PatchOption) error {
+	err := w.StatusWriter.Patch(ctx, obj, patch, opts...)
+	w.client.record("patch/status", obj, err)
+	return err
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"net/http"
+)
+
+// Transport is an HTTP transport that records every request which may modify remote state
+type Transport struct {
+	// Auditor records the requests
+	Auditor *Auditor
+	// Base is the transport used to make the request, uses the default transport if nil
+	Base http.RoundTripper
+}
+
+// RoundTrip delegates to the base transport and records the outcome of mutating requests
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base().RoundTrip(req)
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		// Read-only requests are not audited
+	default:
+		e := &Event{
+			Action: req.Method,
+			URL:    req.URL.String(),
+			Error:  errorString(err),
+		}
+		if resp != nil {
+			e.StatusCode = resp.StatusCode
+		}
+		t.Auditor.Record(e)
+	}
+
+	return resp, err
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
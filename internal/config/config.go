@@ -28,6 +28,7 @@ import (
 	"strings"
 
 	"github.com/lestrrat-go/jwx/jwk"
+	"github.com/redskyops/redskyops-controller/internal/hmacauth"
 	"github.com/redskyops/redskyops-controller/internal/oauth2/authorizationcode"
 	"github.com/redskyops/redskyops-controller/internal/oauth2/devicecode"
 	"github.com/redskyops/redskyops-controller/internal/oauth2/registration"
@@ -126,6 +127,14 @@ func (rsc *RedSkyConfig) Merge(data *Config) {
 	mergeControllers(&rsc.data, data.Controllers)
 	mergeContexts(&rsc.data, data.Contexts)
 	mergeString(&rsc.data.CurrentContext, data.CurrentContext)
+	mergeViews(&rsc.data, data.Views)
+}
+
+// Views returns the saved trial views
+func (rsc *RedSkyConfig) Views() []NamedView {
+	views := make([]NamedView, len(rsc.data.Views))
+	copy(views, rsc.data.Views)
+	return views
 }
 
 // Reader returns a configuration reader for accessing information from the configuration
@@ -344,6 +353,15 @@ func (rsc *RedSkyConfig) NewDeviceAuthorization() (*devicecode.Config, error) {
 
 // Authorize configures the supplied transport
 func (rsc *RedSkyConfig) Authorize(ctx context.Context, transport http.RoundTripper) (http.RoundTripper, error) {
+	// Shared secrets are used to sign requests directly
+	az, err := CurrentAuthorization(rsc.Reader())
+	if err != nil {
+		return nil, err
+	}
+	if az.Credential.HMACCredential != nil {
+		return &hmacauth.Transport{KeyID: az.Credential.KeyID, Secret: []byte(az.Credential.Secret), Base: transport}, nil
+	}
+
 	// Get the token source and use it to wrap the transport
 	src, err := rsc.tokenSource(ctx)
 	if err != nil {
@@ -382,6 +400,13 @@ func (rsc *RedSkyConfig) tokenSource(ctx context.Context) (oauth2.TokenSource, e
 		return cc.TokenSource(ctx), nil
 	}
 
+	if az.Credential.BearerTokenCredential != nil {
+		if az.Credential.BearerTokenFile != "" {
+			return &fileTokenSource{filename: az.Credential.BearerTokenFile}, nil
+		}
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: az.Credential.BearerToken, TokenType: "Bearer"}), nil
+	}
+
 	if az.Credential.TokenCredential != nil {
 		c := &oauth2.Config{
 			ClientID: rsc.clientID(&srv),
@@ -17,9 +17,15 @@ limitations under the License.
 package config
 
 import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/redskyops/redskyops-controller/internal/hmacauth"
 )
 
 const (
@@ -67,3 +73,69 @@ func TestRedSkyConfig_Endpoints(t *testing.T) {
 	g.Expect(ep.Resolve(expFooBar).String()).To(Equal("http://example.com/api/experiments/foo_bar?foo=bar"))
 	g.Expect(ep.Resolve(expFooBarTrials).String()).To(Equal("http://example.com/api/experiments/foo_bar/trials/?foo=bar"))
 }
+
+func TestRedSkyConfig_AuthorizeHMAC(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := &RedSkyConfig{}
+	cfg.Overrides.HMACCredential = HMACCredential{KeyID: "test", Secret: "s3cr3t"}
+	g.Expect(defaultLoader(cfg)).Should(Succeed())
+
+	rt, err := cfg.Authorize(context.TODO(), nil)
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(rt).To(BeAssignableToTypeOf(&hmacauth.Transport{}))
+
+	// Shared secrets must survive a round trip through the configuration file
+	data, err := json.Marshal(&Credential{HMACCredential: &cfg.Overrides.HMACCredential})
+	g.Expect(err).ShouldNot(HaveOccurred())
+	c := &Credential{}
+	g.Expect(json.Unmarshal(data, c)).Should(Succeed())
+	g.Expect(c.HMACCredential).To(Equal(&cfg.Overrides.HMACCredential))
+}
+
+func TestRedSkyConfig_AuthorizeBearerTokenFile(t *testing.T) {
+	g := NewWithT(t)
+
+	f, err := ioutil.TempFile("", "token")
+	g.Expect(err).ShouldNot(HaveOccurred())
+	defer os.Remove(f.Name())
+	g.Expect(ioutil.WriteFile(f.Name(), []byte("first\n"), 0600)).Should(Succeed())
+
+	cfg := &RedSkyConfig{}
+	cfg.Overrides.BearerTokenCredential = BearerTokenCredential{BearerTokenFile: f.Name()}
+	g.Expect(defaultLoader(cfg)).Should(Succeed())
+
+	src, err := cfg.tokenSource(context.TODO())
+	g.Expect(err).ShouldNot(HaveOccurred())
+	tok, err := src.Token()
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(tok.AccessToken).To(Equal("first"))
+
+	// Changes to the file must be picked up without reloading the configuration
+	g.Expect(ioutil.WriteFile(f.Name(), []byte("second\n"), 0600)).Should(Succeed())
+	g.Expect(os.Chtimes(f.Name(), time.Now(), time.Now().Add(time.Minute))).Should(Succeed())
+	tok, err = src.Token()
+	g.Expect(err).ShouldNot(HaveOccurred())
+	g.Expect(tok.AccessToken).To(Equal("second"))
+}
+
+func TestRedSkyConfig_SaveView(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := &RedSkyConfig{}
+	g.Expect(cfg.Update(SaveView("", &View{}))).ShouldNot(Succeed())
+
+	g.Expect(cfg.Update(SaveView("failed", &View{FieldSelector: "status=failed", SortBy: "memory"}))).Should(Succeed())
+	g.Expect(cfg.Update(SaveView("all", &View{All: true}))).Should(Succeed())
+	g.Expect(cfg.Views()).To(Equal([]NamedView{
+		{Name: "failed", View: View{FieldSelector: "status=failed", SortBy: "memory"}},
+		{Name: "all", View: View{All: true}},
+	}))
+
+	// Saving an existing view replaces it entirely
+	g.Expect(cfg.Update(SaveView("failed", &View{SortOrder: "desc"}))).Should(Succeed())
+	g.Expect(cfg.Views()).To(Equal([]NamedView{
+		{Name: "failed", View: View{SortOrder: "desc"}},
+		{Name: "all", View: View{All: true}},
+	}))
+}
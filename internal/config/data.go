@@ -45,6 +45,8 @@ type Config struct {
 	Contexts []NamedContext `json:"contexts,omitempty"`
 	// CurrentContext is the name of the default context
 	CurrentContext string `json:"current-context,omitempty"`
+	// Views is a named list of saved trial views
+	Views []NamedView `json:"views,omitempty"`
 }
 
 // Server contains information about how to communicate with a Red Sky API Server
@@ -115,6 +117,22 @@ type ClientCredential struct {
 	Scope string `json:"scope"`
 }
 
+// HMACCredential represents a shared secret used to sign requests
+type HMACCredential struct {
+	// KeyID identifies the shared secret to the server
+	KeyID string `json:"key_id,omitempty"`
+	// Secret is the shared secret used to sign requests
+	Secret string `json:"hmac_secret"`
+}
+
+// BearerTokenCredential represents a static bearer token
+type BearerTokenCredential struct {
+	// BearerToken is presented to the service being authenticated to
+	BearerToken string `json:"bearer_token,omitempty"`
+	// BearerTokenFile is the path to a file containing the bearer token, the file is re-read when it changes
+	BearerTokenFile string `json:"bearer_token_file,omitempty"`
+}
+
 // Cluster contains information about communicating with a Kubernetes cluster
 type Cluster struct {
 	// KubeConfig is the path to a kubeconfig file to use; leave blank to get the default file
@@ -159,6 +177,22 @@ type Context struct {
 	Cluster string `json:"cluster,omitempty"`
 }
 
+// View is a saved combination of filters, sort order and output format used to display trials
+type View struct {
+	// Selector is the label selector used to filter trials
+	Selector string `json:"selector,omitempty"`
+	// FieldSelector is the field selector used to filter trials
+	FieldSelector string `json:"field-selector,omitempty"`
+	// SortBy is the JSONPath expression, metric or parameter name used to sort trials
+	SortBy string `json:"sort-by,omitempty"`
+	// SortOrder is the sort order, one of "asc" or "desc"
+	SortOrder string `json:"sort-order,omitempty"`
+	// Output is the output format used to display trials, e.g. "custom-columns=..."
+	Output string `json:"output,omitempty"`
+	// All includes staged trials
+	All bool `json:"all,omitempty"`
+}
+
 // NamedServer associates a name to a server configuration
 type NamedServer struct {
 	// Name is the referencable name for the server
@@ -199,12 +233,24 @@ type NamedContext struct {
 	Context Context `json:"context"`
 }
 
+// NamedView associates a name to a view
+type NamedView struct {
+	// Name is the referencable name for the view
+	Name string `json:"name"`
+	// View is the view configuration
+	View View `json:"view"`
+}
+
 // Credential is use to represent a credential
 type Credential struct {
 	// TokenCredential is used to prove authorization using a token that has already been obtained
 	*TokenCredential
 	// ClientCredential is used to obtain a new token for authorization using the credential information
 	*ClientCredential
+	// HMACCredential is used to sign requests instead of obtaining a token
+	*HMACCredential
+	// BearerTokenCredential is used to prove authorization using a fixed token
+	*BearerTokenCredential
 }
 
 // UnmarshalJSON determines which type of credential is being used
@@ -226,6 +272,16 @@ func (c *Credential) UnmarshalJSON(data []byte) error {
 		if err := json.Unmarshal(data, c.ClientCredential); err != nil {
 			return nil
 		}
+	case m["bearer_token"] != "" || m["bearer_token_file"] != "":
+		c.BearerTokenCredential = &BearerTokenCredential{}
+		if err := json.Unmarshal(data, c.BearerTokenCredential); err != nil {
+			return nil
+		}
+	case m["hmac_secret"] != "":
+		c.HMACCredential = &HMACCredential{}
+		if err := json.Unmarshal(data, c.HMACCredential); err != nil {
+			return nil
+		}
 	default:
 		return fmt.Errorf("unknown credential")
 	}
@@ -259,6 +315,10 @@ func (c *Credential) MarshalJSON() ([]byte, error) {
 		}{TC: (*TC)(c.TokenCredential), AccessToken: accessToken, Expiry: expiry})
 	} else if c.ClientCredential != nil {
 		return json.Marshal(c.ClientCredential)
+	} else if c.HMACCredential != nil {
+		return json.Marshal(c.HMACCredential)
+	} else if c.BearerTokenCredential != nil {
+		return json.Marshal(c.BearerTokenCredential)
 	}
 	return []byte("{}"), nil
 }
@@ -24,6 +24,10 @@ func envLoader(cfg *RedSkyConfig) error {
 	defaultString(&cfg.Overrides.ServerIssuer, os.Getenv("REDSKY_SERVER_ISSUER"))
 	defaultString(&cfg.Overrides.Credential.ClientID, os.Getenv("REDSKY_AUTHORIZATION_CLIENT_ID"))
 	defaultString(&cfg.Overrides.Credential.ClientSecret, os.Getenv("REDSKY_AUTHORIZATION_CLIENT_SECRET"))
+	defaultString(&cfg.Overrides.HMACCredential.KeyID, os.Getenv("REDSKY_AUTHORIZATION_HMAC_KEY_ID"))
+	defaultString(&cfg.Overrides.HMACCredential.Secret, os.Getenv("REDSKY_AUTHORIZATION_HMAC_SECRET"))
+	defaultString(&cfg.Overrides.BearerTokenCredential.BearerToken, os.Getenv("REDSKY_AUTHORIZATION_BEARER_TOKEN"))
+	defaultString(&cfg.Overrides.BearerTokenCredential.BearerTokenFile, os.Getenv("REDSKY_AUTHORIZATION_BEARER_TOKEN_FILE"))
 	return nil
 }
 
@@ -48,6 +52,14 @@ func EnvironmentMapping(r Reader, includeController bool) (map[string][]byte, er
 		env["REDSKY_AUTHORIZATION_CLIENT_ID"] = []byte(az.Credential.ClientID)
 		env["REDSKY_AUTHORIZATION_CLIENT_SECRET"] = []byte(az.Credential.ClientSecret)
 	}
+	if az.Credential.HMACCredential != nil {
+		env["REDSKY_AUTHORIZATION_HMAC_KEY_ID"] = []byte(az.Credential.KeyID)
+		env["REDSKY_AUTHORIZATION_HMAC_SECRET"] = []byte(az.Credential.Secret)
+	}
+	if az.Credential.BearerTokenCredential != nil {
+		env["REDSKY_AUTHORIZATION_BEARER_TOKEN"] = []byte(az.Credential.BearerToken)
+		env["REDSKY_AUTHORIZATION_BEARER_TOKEN_FILE"] = []byte(az.Credential.BearerTokenFile)
+	}
 
 	// Optionally record environment variables from the controller configuration
 	if includeController {
@@ -44,14 +44,32 @@ func mergeAuthorization(a1, a2 *Authorization) {
 	// Do not merge credentials, just shallow copy them wholesale if they are present
 	if a2.Credential.TokenCredential != nil && a2.Credential.AccessToken != "" {
 		a1.Credential.ClientCredential = nil
+		a1.Credential.HMACCredential = nil
+		a1.Credential.BearerTokenCredential = nil
 		a1.Credential.TokenCredential = new(TokenCredential)
 		*a1.Credential.TokenCredential = *a2.Credential.TokenCredential
 	}
 	if a2.Credential.ClientCredential != nil && a2.Credential.ClientID != "" {
 		a1.Credential.TokenCredential = nil
+		a1.Credential.HMACCredential = nil
+		a1.Credential.BearerTokenCredential = nil
 		a1.Credential.ClientCredential = new(ClientCredential)
 		*a1.Credential.ClientCredential = *a2.Credential.ClientCredential
 	}
+	if a2.Credential.HMACCredential != nil && a2.Credential.Secret != "" {
+		a1.Credential.TokenCredential = nil
+		a1.Credential.ClientCredential = nil
+		a1.Credential.BearerTokenCredential = nil
+		a1.Credential.HMACCredential = new(HMACCredential)
+		*a1.Credential.HMACCredential = *a2.Credential.HMACCredential
+	}
+	if a2.Credential.BearerTokenCredential != nil && (a2.Credential.BearerToken != "" || a2.Credential.BearerTokenFile != "") {
+		a1.Credential.TokenCredential = nil
+		a1.Credential.ClientCredential = nil
+		a1.Credential.HMACCredential = nil
+		a1.Credential.BearerTokenCredential = new(BearerTokenCredential)
+		*a1.Credential.BearerTokenCredential = *a2.Credential.BearerTokenCredential
+	}
 }
 
 func mergeCluster(c1, c2 *Cluster) {
@@ -153,6 +171,17 @@ func mergeControllers(data *Config, controllers []NamedController) {
 	}
 }
 
+func mergeViews(data *Config, views []NamedView) {
+	// Views are not merged, they are replaced wholesale
+	for i := range views {
+		if v := findView(data.Views, views[i].Name); v != nil {
+			*v = views[i].View
+		} else {
+			data.Views = append(data.Views, views[i])
+		}
+	}
+}
+
 func mergeContexts(data *Config, contexts []NamedContext) {
 	idx := make(map[string]*Context, len(contexts))
 	for i := range contexts {
@@ -215,3 +244,12 @@ func findContext(l []NamedContext, name string) *Context {
 	}
 	return nil
 }
+
+func findView(l []NamedView, name string) *View {
+	for i := range l {
+		if l[i].Name == name {
+			return &l[i].View
+		}
+	}
+	return nil
+}
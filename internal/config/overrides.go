@@ -28,6 +28,10 @@ type Overrides struct {
 	ServerIssuer string
 	// Credential overrides the current authorization
 	Credential ClientCredential
+	// HMACCredential overrides the current authorization with a shared secret used to sign requests
+	HMACCredential HMACCredential
+	// BearerTokenCredential overrides the current authorization with a static bearer token
+	BearerTokenCredential BearerTokenCredential
 	// KubeConfig overrides the current cluster's kubeconfig file
 	KubeConfig string
 	// Namespace overrides the current cluster's default namespace
@@ -80,6 +84,16 @@ func (o *overrideReader) Authorization(name string) (Authorization, error) {
 		return Authorization{Credential: Credential{ClientCredential: &cc}}, nil
 	}
 
+	if o.overrides.HMACCredential.Secret != "" {
+		hc := o.overrides.HMACCredential
+		return Authorization{Credential: Credential{HMACCredential: &hc}}, nil
+	}
+
+	if o.overrides.BearerTokenCredential.BearerToken != "" || o.overrides.BearerTokenCredential.BearerTokenFile != "" {
+		bc := o.overrides.BearerTokenCredential
+		return Authorization{Credential: Credential{BearerTokenCredential: &bc}}, nil
+	}
+
 	return o.delegate.Authorization(name)
 }
 
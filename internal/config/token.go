@@ -0,0 +1,67 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// ServiceAccountTokenFile is the location of the Kubernetes service account token in a pod
+const ServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// fileTokenSource is a token source that reads a bearer token from a file, re-reading the file when it changes
+type fileTokenSource struct {
+	filename string
+
+	mu      sync.Mutex
+	token   *oauth2.Token
+	modTime time.Time
+}
+
+// Token returns the current contents of the token file
+func (s *fileTokenSource) Token() (*oauth2.Token, error) {
+	fi, err := os.Stat(s.filename)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != nil && fi.ModTime().Equal(s.modTime) {
+		return s.token, nil
+	}
+
+	data, err := ioutil.ReadFile(s.filename)
+	if err != nil {
+		return nil, err
+	}
+	accessToken := strings.TrimSpace(string(data))
+	if accessToken == "" {
+		return nil, fmt.Errorf("empty bearer token file: %s", s.filename)
+	}
+
+	s.token = &oauth2.Token{AccessToken: accessToken, TokenType: "Bearer"}
+	s.modTime = fi.ModTime()
+	return s.token, nil
+}
@@ -48,6 +48,8 @@ func SaveToken(name string, t *oauth2.Token) Change {
 		}
 
 		az.Credential.ClientCredential = nil
+		az.Credential.HMACCredential = nil
+		az.Credential.BearerTokenCredential = nil
 		az.Credential.TokenCredential = &TokenCredential{
 			AccessToken:  t.AccessToken,
 			TokenType:    t.TokenType,
@@ -73,6 +75,18 @@ func SaveClientRegistration(name string, info *registration.ClientInformationRes
 	}
 }
 
+// SaveView is a configuration change that persists the supplied trial view. If the view exists, it is overwritten;
+// otherwise a new named view is created.
+func SaveView(name string, v *View) Change {
+	return func(cfg *Config) error {
+		if name == "" {
+			return fmt.Errorf("view name must not be empty")
+		}
+		mergeViews(cfg, []NamedView{{Name: name, View: *v}})
+		return nil
+	}
+}
+
 // ApplyCurrentContext is a configuration change that updates the values of a context and sets that context as the
 // current context. If the context exists, non-empty values will overwrite; otherwise a new named context is created.
 func ApplyCurrentContext(contextName, serverName, authorizationName, clusterName string) Change {
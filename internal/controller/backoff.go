@@ -0,0 +1,93 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Backoff controls how aggressively failed reconciles are retried, zero values use the controller-runtime defaults
+type Backoff struct {
+	// BaseDelay is the delay before the first retry of a failed reconcile
+	BaseDelay time.Duration
+	// MaxDelay is the maximum delay between retries of a failed reconcile
+	MaxDelay time.Duration
+	// QPS is the overall rate at which retries are allowed
+	QPS float64
+	// Burst is the bucket size for the overall rate of retries
+	Burst int
+}
+
+// RateLimiter returns a work queue rate limiter for the backoff
+func (b Backoff) RateLimiter() workqueue.RateLimiter {
+	baseDelay, maxDelay, qps, burst := 5*time.Millisecond, 1000*time.Second, 10.0, 100
+	if b.BaseDelay > 0 {
+		baseDelay = b.BaseDelay
+	}
+	if b.MaxDelay > 0 {
+		maxDelay = b.MaxDelay
+	}
+	if b.QPS > 0 {
+		qps = b.QPS
+	}
+	if b.Burst > 0 {
+		burst = b.Burst
+	}
+
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+}
+
+// Reconciler wraps the supplied reconciler so that retries are scheduled using the backoff; if the backoff is not
+// configured the reconciler is returned unchanged
+func (b Backoff) Reconciler(r reconcile.Reconciler, log logr.Logger) reconcile.Reconciler {
+	if b == (Backoff{}) {
+		return r
+	}
+	return &backoffReconciler{Reconciler: r, Log: log, limiter: b.RateLimiter()}
+}
+
+// backoffReconciler delays retries according to a rate limiter instead of the work queue defaults
+type backoffReconciler struct {
+	reconcile.Reconciler
+	Log     logr.Logger
+	limiter workqueue.RateLimiter
+}
+
+// Reconcile invokes the wrapped reconciler and converts retries into delayed requeues
+func (r *backoffReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	result, err := r.Reconciler.Reconcile(req)
+	switch {
+	case err != nil:
+		// Returning the error would cause the work queue to apply the default backoff
+		r.Log.Error(err, "Reconciler error", "request", req)
+		return ctrl.Result{RequeueAfter: r.limiter.When(req)}, nil
+	case result.Requeue && result.RequeueAfter <= 0:
+		return ctrl.Result{RequeueAfter: r.limiter.When(req)}, nil
+	default:
+		r.limiter.Forget(req)
+		return result, nil
+	}
+}
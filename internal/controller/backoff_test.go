@@ -0,0 +1,62 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type stubReconciler struct {
+	err error
+}
+
+func (r *stubReconciler) Reconcile(ctrl.Request) (ctrl.Result, error) {
+	return ctrl.Result{}, r.err
+}
+
+func TestBackoff_Reconciler(t *testing.T) {
+	stub := &stubReconciler{}
+	assert.Equal(t, reconcile.Reconciler(stub), Backoff{}.Reconciler(stub, ctrl.Log))
+
+	r := Backoff{BaseDelay: time.Second, MaxDelay: 4 * time.Second}.Reconciler(stub, ctrl.Log)
+	req := ctrl.Request{}
+	req.Name = "test"
+
+	stub.err = fmt.Errorf("failed")
+	for _, after := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		result, err := r.Reconcile(req)
+		assert.NoError(t, err)
+		assert.Equal(t, after, result.RequeueAfter)
+	}
+
+	// Success resets the backoff
+	stub.err = nil
+	result, err := r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+
+	stub.err = fmt.Errorf("failed")
+	result, err = r.Reconcile(req)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Second, result.RequeueAfter)
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CoalescedError indicates that an update was not written, the object should be reconciled again after the delay
+type CoalescedError struct {
+	// After is the delay before the update will be written
+	After time.Duration
+}
+
+func (e *CoalescedError) Error() string {
+	return fmt.Sprintf("update coalesced, retry after %s", e.After)
+}
+
+// CoalescingClient is a Kubernetes client that holds back trial updates which only advance probe times; changes to
+// anything else are always written immediately
+type CoalescingClient struct {
+	client.Client
+	// Interval is the minimum time between writes of a trial when only probe times change
+	Interval time.Duration
+	// Now returns the current time, defaults to `time.Now`
+	Now func() time.Time
+
+	mu     sync.Mutex
+	writes map[types.UID]trialWrite
+}
+
+var _ client.Client = &CoalescingClient{}
+
+// trialWrite records the state of the last trial update
+type trialWrite struct {
+	resourceVersion string
+	fingerprint     [sha256.Size]byte
+	time            time.Time
+}
+
+// Update writes the object unless it is a trial whose only changes since the last write are probe times
+func (c *CoalescingClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	t, ok := obj.(*redskyv1beta1.Trial)
+	if !ok {
+		return c.Client.Update(ctx, obj, opts...)
+	}
+
+	now := c.now()
+	if c.Interval > 0 {
+		c.mu.Lock()
+		w, ok := c.writes[t.UID]
+		c.mu.Unlock()
+		if ok && w.resourceVersion == t.ResourceVersion && w.fingerprint == fingerprint(t) {
+			if after := w.time.Add(c.Interval).Sub(now); after > 0 {
+				TrialUpdates.WithLabelValues("coalesced").Inc()
+				return &CoalescedError{After: after}
+			}
+		}
+	}
+
+	if err := c.Client.Update(ctx, obj, opts...); err != nil {
+		return err
+	}
+	TrialUpdates.WithLabelValues("written").Inc()
+	if c.Interval <= 0 {
+		return nil
+	}
+
+	// Remember what was actually persisted, the cached copy of the trial will match it
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.writes == nil {
+		c.writes = make(map[types.UID]trialWrite)
+	}
+	for uid, w := range c.writes {
+		if !now.Before(w.time.Add(c.Interval)) {
+			delete(c.writes, uid)
+		}
+	}
+	c.writes[t.UID] = trialWrite{resourceVersion: t.ResourceVersion, fingerprint: fingerprint(t), time: now}
+	return nil
+}
+
+// Delete forgets about previous writes of the object
+func (c *CoalescingClient) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOption) error {
+	if t, ok := obj.(*redskyv1beta1.Trial); ok {
+		c.mu.Lock()
+		delete(c.writes, t.UID)
+		c.mu.Unlock()
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *CoalescingClient) now() time.Time {
+	if c.Now != nil {
+		return c.Now()
+	}
+	return time.Now()
+}
+
+// fingerprint returns a hash of the trial that ignores the resource version and probe times
+func fingerprint(t *redskyv1beta1.Trial) [sha256.Size]byte {
+	t = t.DeepCopy()
+	t.ResourceVersion = ""
+	for i := range t.Status.Conditions {
+		t.Status.Conditions[i].LastProbeTime = metav1.Time{}
+	}
+	for i := range t.Status.ReadinessChecks {
+		t.Status.ReadinessChecks[i].LastCheckTime = nil
+	}
+	data, _ := json.Marshal(t)
+	return sha256.Sum256(data)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// countingClient counts updates and assigns a new resource version for each one
+type countingClient struct {
+	client.Client
+	updates int
+}
+
+func (c *countingClient) Update(ctx context.Context, obj runtime.Object, opts ...client.UpdateOption) error {
+	c.updates++
+	obj.(metav1.Object).SetResourceVersion(strconv.Itoa(c.updates))
+	return nil
+}
+
+func TestCoalescingClient_Update(t *testing.T) {
+	now := time.Now()
+	cc := &countingClient{}
+	c := &CoalescingClient{Client: cc, Interval: 10 * time.Second, Now: func() time.Time { return now }}
+	ctx := context.TODO()
+
+	tt := &redskyv1beta1.Trial{}
+	tt.UID = "test"
+	probe := func() {
+		for i := range tt.Status.Conditions {
+			tt.Status.Conditions[i].LastProbeTime = metav1.NewTime(now)
+		}
+	}
+
+	// The first write always goes through
+	tt.Status.Conditions = []redskyv1beta1.TrialCondition{{Type: redskyv1beta1.TrialReady, Status: corev1.ConditionFalse}}
+	assert.NoError(t, c.Update(ctx, tt))
+	assert.Equal(t, 1, cc.updates)
+
+	// Probe time changes are held back
+	now = now.Add(time.Second)
+	probe()
+	err := c.Update(ctx, tt)
+	assert.Equal(t, &CoalescedError{After: 9 * time.Second}, err)
+	assert.Equal(t, 1, cc.updates)
+
+	// Other changes are written immediately
+	tt.Status.Conditions[0].Reason = "Waiting"
+	assert.NoError(t, c.Update(ctx, tt))
+	assert.Equal(t, 2, cc.updates)
+
+	// Probe time changes are written once the interval passes
+	now = now.Add(10 * time.Second)
+	probe()
+	assert.NoError(t, c.Update(ctx, tt))
+	assert.Equal(t, 3, cc.updates)
+
+	// Changes made by someone else are written immediately
+	now = now.Add(time.Second)
+	probe()
+	tt.ResourceVersion = "other"
+	assert.NoError(t, c.Update(ctx, tt))
+	assert.Equal(t, 4, cc.updates)
+
+	// Non-trial objects are never held back
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, c.Update(ctx, cm))
+	assert.NoError(t, c.Update(ctx, cm))
+	assert.Equal(t, 6, cc.updates)
+}
+
+func TestRequeueConflict_Coalesced(t *testing.T) {
+	result, err := RequeueConflict(&CoalescedError{After: 5 * time.Second})
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, result.RequeueAfter)
+}
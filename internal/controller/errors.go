@@ -26,10 +26,8 @@ func IgnoreNotFound(err error) error {
 	if apierrs.IsNotFound(err) {
 		return nil
 	}
-	if rserr, ok := err.(*redskyapi.Error); ok {
-		if rserr.Type == redskyapi.ErrExperimentNotFound || rserr.Type == redskyapi.ErrTrialNotFound {
-			return nil
-		}
+	if redskyapi.IsErrorType(err, redskyapi.ErrExperimentNotFound) || redskyapi.IsErrorType(err, redskyapi.ErrTrialNotFound) {
+		return nil
 	}
 	return err
 }
@@ -47,10 +45,8 @@ func IgnoreReportError(err error) error {
 	if IgnoreNotFound(err) == nil {
 		return nil
 	}
-	if rserr, ok := err.(*redskyapi.Error); ok {
-		if rserr.Type == redskyapi.ErrTrialAlreadyReported {
-			return nil
-		}
+	if redskyapi.IsErrorType(err, redskyapi.ErrTrialAlreadyReported) {
+		return nil
 	}
 	return err
 }
@@ -60,6 +60,13 @@ func TestIgnoreNotFound(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			desc: "wrapped redskyapi error experiment not found",
+			in: fmt.Errorf("unable to get experiment: %w", &redskyapi.Error{
+				Type: redskyapi.ErrExperimentNotFound,
+			}),
+			expectedErr: nil,
+		},
 		{
 			desc:        "other error",
 			in:          fmt.Errorf("111"),
@@ -17,7 +17,12 @@ limitations under the License.
 package controller
 
 import (
+	"net/http"
+	"strconv"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redskyops/redskyops-controller/redskyapi"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
@@ -29,6 +34,20 @@ var (
 		Help: "Total number of reconciliation conflict errors per controller",
 	}, []string{"controller"})
 
+	// TrialUpdates is a Prometheus counter metric which holds the total number of
+	// trial updates, either written to the API server or coalesced with a later write
+	TrialUpdates = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "redsky_trial_updates_total",
+		Help: "Total number of trial updates by result (written or coalesced)",
+	}, []string{"result"})
+
+	// FeatureEnabled is a Prometheus gauge metric which records if a feature gate
+	// is enabled (1) or disabled (0)
+	FeatureEnabled = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "redsky_feature_enabled",
+		Help: "Whether a feature gate is enabled (1) or disabled (0)",
+	}, []string{"name"})
+
 	// TODO Experiment is an unbounded label, that might be problematic
 
 	// ExperimentTrials is a Prometheus gauge metric which holds the total number
@@ -44,12 +63,63 @@ var (
 		Name: "redsky_experiment_active_trials_total",
 		Help: "Total number of active trials present for an experiment",
 	}, []string{"experiment"})
+
+	// ConcurrentTrials is a Prometheus gauge metric which holds the number of
+	// active trials across all experiments
+	ConcurrentTrials = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "redsky_concurrent_trials",
+		Help: "Number of active trials across all experiments",
+	})
+
+	// APIRequests is a Prometheus counter metric which holds the total number of
+	// requests made to the remote Red Sky API by method, endpoint and status code
+	APIRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "redsky_api_requests_total",
+		Help: "Total number of Red Sky API requests by method, endpoint and status code (or \"error\")",
+	}, []string{"method", "endpoint", "code"})
+
+	// APIRequestDuration is a Prometheus histogram metric which holds the latency
+	// of requests made to the remote Red Sky API by method and endpoint
+	APIRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "redsky_api_request_duration_seconds",
+		Help: "Latency of Red Sky API requests by method and endpoint",
+	}, []string{"method", "endpoint"})
+
+	// NamespaceConcurrentTrials is a Prometheus gauge metric which holds the number
+	// of active trials across all experiments in a namespace
+	NamespaceConcurrentTrials = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "redsky_namespace_concurrent_trials",
+		Help: "Number of active trials across all experiments in a namespace",
+	}, []string{"namespace"})
 )
 
 func init() {
 	metrics.Registry.MustRegister(
 		ReconcileConflictErrors,
+		TrialUpdates,
+		FeatureEnabled,
 		ExperimentTrials,
 		ExperimentActiveTrials,
+		ConcurrentTrials,
+		NamespaceConcurrentTrials,
+		APIRequests,
+		APIRequestDuration,
 	)
 }
+
+// APIMetrics is a Red Sky API client interceptor which records the request count and latency metrics
+type APIMetrics struct{}
+
+// OnRequest does nothing, requests are only recorded once they complete
+func (APIMetrics) OnRequest(*http.Request) {}
+
+// OnResponse records the outcome and latency of the request
+func (APIMetrics) OnResponse(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	endpoint := redskyapi.Endpoint(req)
+	code := "error"
+	if err == nil && resp != nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	APIRequests.WithLabelValues(req.Method, endpoint, code).Inc()
+	APIRequestDuration.WithLabelValues(req.Method, endpoint).Observe(elapsed.Seconds())
+}
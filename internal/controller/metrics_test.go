@@ -0,0 +1,119 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func TestAPIMetrics(t *testing.T) {
+	cases := []struct {
+		desc     string
+		method   string
+		path     string
+		resp     *http.Response
+		err      error
+		endpoint string
+		code     string
+	}{
+		{
+			desc:     "experiment",
+			method:   http.MethodGet,
+			path:     "/v1/experiments/my-experiment",
+			resp:     &http.Response{StatusCode: http.StatusOK},
+			endpoint: "/v1/experiments/*",
+			code:     "200",
+		},
+		{
+			desc:     "trial",
+			method:   http.MethodPost,
+			path:     "/v1/experiments/my-experiment/trials/42",
+			resp:     &http.Response{StatusCode: http.StatusNoContent},
+			endpoint: "/v1/experiments/*/trials/*",
+			code:     "204",
+		},
+		{
+			desc:     "other trial",
+			method:   http.MethodPost,
+			path:     "/v1/experiments/other-experiment/trials/7",
+			resp:     &http.Response{StatusCode: http.StatusNoContent},
+			endpoint: "/v1/experiments/*/trials/*",
+			code:     "204",
+		},
+		{
+			desc:     "next trial unavailable",
+			method:   http.MethodGet,
+			path:     "/v1/experiments/my-experiment/nextTrial",
+			resp:     &http.Response{StatusCode: http.StatusServiceUnavailable},
+			endpoint: "/v1/experiments/*/nextTrial",
+			code:     "503",
+		},
+		{
+			desc:     "error",
+			method:   http.MethodGet,
+			path:     "/v1/experiments/",
+			err:      fmt.Errorf("connection refused"),
+			endpoint: "/v1/experiments/",
+			code:     "error",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			req, err := http.NewRequest(c.method, "http://example.com"+c.path, nil)
+			require.NoError(t, err)
+
+			requests := APIRequests.WithLabelValues(c.method, c.endpoint, c.code)
+			count := testutil.ToFloat64(requests)
+			samples := sampleCount(t, c.method, c.endpoint)
+
+			APIMetrics{}.OnRequest(req)
+			APIMetrics{}.OnResponse(req, c.resp, c.err, 250*time.Millisecond)
+
+			assert.Equal(t, count+1, testutil.ToFloat64(requests))
+			assert.Equal(t, samples+1, sampleCount(t, c.method, c.endpoint))
+		})
+	}
+}
+
+// sampleCount returns the number of request latency observations recorded for the method and endpoint
+func sampleCount(t *testing.T, method, endpoint string) uint64 {
+	mfs, err := metrics.Registry.Gather()
+	require.NoError(t, err)
+	for _, mf := range mfs {
+		if mf.GetName() != "redsky_api_request_duration_seconds" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["method"] == method && labels["endpoint"] == endpoint {
+				return m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
@@ -33,7 +33,7 @@ import (
 // RequeueIfUnavailable will return a new result and the supplied error, adjusted for trial unavailable errors
 func RequeueIfUnavailable(err error) (*ctrl.Result, error) {
 	result := &ctrl.Result{}
-	if rse, ok := err.(*redskyapi.Error); ok && rse.Type == redskyapi.ErrTrialUnavailable {
+	if rse, ok := redskyapi.AsError(err); ok && rse.Type == redskyapi.ErrTrialUnavailable {
 		result.RequeueAfter = rse.RetryAfter
 		err = nil
 	}
@@ -52,6 +52,9 @@ func requeueConflictForFrameStack(err error, fs frameStack) (*ctrl.Result, error
 		ReconcileConflictErrors.WithLabelValues(controllerName).Inc()
 		result.Requeue = true
 		err = nil
+	} else if ce, ok := err.(*CoalescedError); ok {
+		result.RequeueAfter = ce.After
+		err = nil
 	}
 	return result, err
 }
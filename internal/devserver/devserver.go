@@ -0,0 +1,583 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package devserver is an in-memory implementation of the Red Sky experiments API intended for local development. The
+// server suggests random assignments and does not persist any state.
+package devserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+)
+
+const (
+	endpointExperiment = "/experiments/"
+
+	relationLabels    = "https://carbonrelay.com/rel/labels"
+	relationTrials    = "https://carbonrelay.com/rel/trials"
+	relationNextTrial = "https://carbonrelay.com/rel/next-trial"
+)
+
+// Server is an HTTP handler for the experiments API
+type Server struct {
+	// Name is reported in the "Server" header of each response
+	Name string
+	// Rand is the source of random assignments
+	Rand *rand.Rand
+
+	mu          sync.Mutex
+	experiments map[string]*experiment
+}
+
+// experiment is the server state of a single experiment
+type experiment struct {
+	experimentsv1alpha1.Experiment
+	lastModified time.Time
+	trials       []*experimentsv1alpha1.TrialItem
+}
+
+// NewServer returns a new empty server
+func NewServer() *Server {
+	return &Server{
+		Name:        "RedSkyDevServer",
+		Rand:        rand.New(rand.NewSource(time.Now().UnixNano())),
+		experiments: make(map[string]*experiment),
+	}
+}
+
+// ServeHTTP dispatches API requests
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Server", s.Name)
+	if !strings.HasPrefix(r.URL.Path, endpointExperiment) {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	// Path segments are: {name}/trials/{number}/labels
+	p := strings.Split(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, endpointExperiment), "/"), "/")
+	switch {
+	case p[0] == "" && r.Method == http.MethodOptions:
+		w.WriteHeader(http.StatusNoContent)
+	case p[0] == "" && r.Method == http.MethodGet:
+		s.listExperiments(w, r)
+	case len(p) == 1 && r.Method == http.MethodGet:
+		s.getExperiment(w, r, p[0])
+	case len(p) == 1 && r.Method == http.MethodPut:
+		s.putExperiment(w, r, p[0])
+	case len(p) == 1 && r.Method == http.MethodPatch:
+		s.patchExperiment(w, r, p[0])
+	case len(p) == 1 && r.Method == http.MethodDelete:
+		s.deleteExperiment(w, p[0])
+	case len(p) == 2 && p[1] == "labels" && r.Method == http.MethodPost:
+		s.labelExperiment(w, r, p[0])
+	case len(p) == 2 && p[1] == "nextTrial" && r.Method == http.MethodPost:
+		s.nextTrial(w, r, p[0])
+	case len(p) == 2 && p[1] == "trialReports" && r.Method == http.MethodPost:
+		s.reportTrials(w, r, p[0])
+	case len(p) == 2 && p[1] == "trials" && r.Method == http.MethodGet:
+		s.listTrials(w, r, p[0])
+	case len(p) == 2 && p[1] == "trials" && r.Method == http.MethodPost:
+		s.createTrial(w, r, p[0])
+	case len(p) == 3 && p[1] == "trials" && r.Method == http.MethodPost:
+		s.reportTrial(w, r, p[0], p[2])
+	case len(p) == 3 && p[1] == "trials" && r.Method == http.MethodDelete:
+		s.abandonTrial(w, p[0], p[2])
+	case len(p) == 4 && p[1] == "trials" && p[3] == "labels" && r.Method == http.MethodPost:
+		s.labelTrial(w, r, p[0], p[2])
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) listExperiments(w http.ResponseWriter, r *http.Request) {
+	selector := parseSelector(r.URL.Query().Get("labelSelector"))
+
+	names := make([]string, 0, len(s.experiments))
+	for name := range s.experiments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lst := experimentsv1alpha1.ExperimentList{}
+	for _, name := range names {
+		exp := s.experiments[name]
+		if !matches(exp.Labels, selector) {
+			continue
+		}
+		lst.Experiments = append(lst.Experiments, experimentsv1alpha1.ExperimentItem{
+			Experiment: exp.Experiment,
+			Metadata:   experimentsv1alpha1.Metadata{"Link": experimentLinks(r, name)},
+		})
+	}
+	writeJSON(w, http.StatusOK, lst)
+}
+
+func (s *Server) getExperiment(w http.ResponseWriter, r *http.Request, name string) {
+	exp, ok := s.experiments[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("experiment %q not found", name))
+		return
+	}
+	writeExperiment(w, r, http.StatusOK, name, exp)
+}
+
+func (s *Server) patchExperiment(w http.ResponseWriter, r *http.Request, name string) {
+	exp, ok := s.experiments[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("experiment %q not found", name))
+		return
+	}
+	in := experimentsv1alpha1.ExperimentPatch{}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	exp.Optimization = experimentsv1alpha1.MergeOptimization(exp.Optimization, in.Optimization)
+	writeExperiment(w, r, http.StatusOK, name, exp)
+}
+
+func (s *Server) putExperiment(w http.ResponseWriter, r *http.Request, name string) {
+	in := experimentsv1alpha1.Experiment{}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err := validateExperiment(&in); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	status := http.StatusOK
+	exp, ok := s.experiments[name]
+	if !ok {
+		status = http.StatusCreated
+		exp = &experiment{}
+		s.experiments[name] = exp
+	}
+	in.Observations = exp.Observations
+	exp.Experiment = in
+	exp.lastModified = time.Now()
+	writeExperiment(w, r, status, name, exp)
+}
+
+func (s *Server) deleteExperiment(w http.ResponseWriter, name string) {
+	if _, ok := s.experiments[name]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("experiment %q not found", name))
+		return
+	}
+	delete(s.experiments, name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) labelExperiment(w http.ResponseWriter, r *http.Request, name string) {
+	exp, ok := s.experiments[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("experiment %q not found", name))
+		return
+	}
+	lbl := experimentsv1alpha1.ExperimentLabels{}
+	if err := json.NewDecoder(r.Body).Decode(&lbl); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	exp.Labels = mergeLabels(exp.Labels, lbl.Labels)
+	exp.lastModified = time.Now()
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) nextTrial(w http.ResponseWriter, r *http.Request, name string) {
+	exp, ok := s.experiments[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("experiment %q not found", name))
+		return
+	}
+
+	// Prefer trials which were explicitly created over random suggestions
+	var t *experimentsv1alpha1.TrialItem
+	for _, st := range exp.trials {
+		if st.Status == experimentsv1alpha1.TrialStaged {
+			t = st
+			break
+		}
+	}
+	if t == nil {
+		t = exp.addTrial(experimentsv1alpha1.TrialStaged, s.suggest(exp))
+	}
+	t.Status = experimentsv1alpha1.TrialActive
+
+	w.Header().Set("Location", trialURL(r, name, t.Number))
+	w.Header().Add("Link", fmt.Sprintf(`<%s/labels>; rel="%s"`, trialURL(r, name, t.Number), relationLabels))
+	writeJSON(w, http.StatusOK, experimentsv1alpha1.TrialAssignments{Assignments: t.Assignments})
+}
+
+func (s *Server) listTrials(w http.ResponseWriter, r *http.Request, name string) {
+	exp, ok := s.experiments[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("experiment %q not found", name))
+		return
+	}
+
+	statuses := make(map[experimentsv1alpha1.TrialStatus]bool)
+	for _, st := range strings.Split(r.URL.Query().Get("status"), ",") {
+		if st != "" {
+			statuses[experimentsv1alpha1.TrialStatus(st)] = true
+		}
+	}
+	selector := parseSelector(r.URL.Query().Get("labelSelector"))
+
+	lst := experimentsv1alpha1.TrialList{Trials: []experimentsv1alpha1.TrialItem{}}
+	for _, t := range exp.trials {
+		if len(statuses) > 0 && !statuses[t.Status] {
+			continue
+		}
+		if !matches(t.Labels, selector) {
+			continue
+		}
+		item := *t
+		item.Metadata = experimentsv1alpha1.Metadata{
+			"Location": []string{trialURL(r, name, t.Number)},
+			"Link":     []string{fmt.Sprintf(`<%s/labels>; rel="%s"`, trialURL(r, name, t.Number), relationLabels)},
+		}
+		lst.Trials = append(lst.Trials, item)
+	}
+	writeJSON(w, http.StatusOK, lst)
+}
+
+func (s *Server) createTrial(w http.ResponseWriter, r *http.Request, name string) {
+	exp, ok := s.experiments[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("experiment %q not found", name))
+		return
+	}
+	asm := experimentsv1alpha1.TrialAssignments{}
+	if err := json.NewDecoder(r.Body).Decode(&asm); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err := validateAssignments(&exp.Experiment, asm.Assignments); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	t := exp.addTrial(experimentsv1alpha1.TrialStaged, asm.Assignments)
+	w.Header().Set("Location", trialURL(r, name, t.Number))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) reportTrial(w http.ResponseWriter, r *http.Request, name, number string) {
+	exp, t := s.trial(name, number)
+	if t == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("trial %s not found", number))
+		return
+	}
+	key := r.Header.Get(experimentsv1alpha1.HeaderIdempotencyKey)
+	if t.Status != experimentsv1alpha1.TrialActive {
+		if key != "" && key == t.IdempotencyKey {
+			// Replay of a report that was already accepted
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		writeError(w, http.StatusConflict, fmt.Sprintf("trial %s already reported", number))
+		return
+	}
+	vls := experimentsv1alpha1.TrialValues{IdempotencyKey: key}
+	if err := json.NewDecoder(r.Body).Decode(&vls); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err := validateValues(&exp.Experiment, &vls); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	t.TrialValues = vls
+	t.Status = experimentsv1alpha1.TrialCompleted
+	if vls.Failed {
+		t.Status = experimentsv1alpha1.TrialFailed
+	}
+	exp.Observations++
+	exp.lastModified = time.Now()
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) reportTrials(w http.ResponseWriter, r *http.Request, name string) {
+	exp, ok := s.experiments[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("experiment %q not found", name))
+		return
+	}
+	batch := experimentsv1alpha1.TrialReports{}
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	// Validate the entire batch before recording anything
+	for i := range batch.Trials {
+		if err := validateAssignments(&exp.Experiment, batch.Trials[i].Assignments); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("trial %d: %s", i, err.Error()))
+			return
+		}
+		if err := validateValues(&exp.Experiment, &batch.Trials[i].TrialValues); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("trial %d: %s", i, err.Error()))
+			return
+		}
+	}
+
+	for i := range batch.Trials {
+		rpt := &batch.Trials[i]
+		status := experimentsv1alpha1.TrialCompleted
+		if rpt.Failed {
+			status = experimentsv1alpha1.TrialFailed
+		}
+		t := exp.addTrial(status, rpt.Assignments)
+		t.TrialValues = rpt.TrialValues
+		t.Labels = rpt.Labels
+		exp.Observations++
+	}
+	exp.lastModified = time.Now()
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) abandonTrial(w http.ResponseWriter, name, number string) {
+	_, t := s.trial(name, number)
+	if t == nil || t.Status != experimentsv1alpha1.TrialActive {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("trial %s not found", number))
+		return
+	}
+	t.Status = experimentsv1alpha1.TrialAbandoned
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) labelTrial(w http.ResponseWriter, r *http.Request, name, number string) {
+	_, t := s.trial(name, number)
+	if t == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("trial %s not found", number))
+		return
+	}
+	lbl := experimentsv1alpha1.TrialLabels{}
+	if err := json.NewDecoder(r.Body).Decode(&lbl); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	t.Labels = mergeLabels(t.Labels, lbl.Labels)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// trial returns the experiment and trial for the supplied path segments
+func (s *Server) trial(name, number string) (*experiment, *experimentsv1alpha1.TrialItem) {
+	exp, ok := s.experiments[name]
+	if !ok {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || n < 1 || n > int64(len(exp.trials)) {
+		return exp, nil
+	}
+	return exp, exp.trials[n-1]
+}
+
+// suggest produces random assignments for each parameter
+func (s *Server) suggest(exp *experiment) []experimentsv1alpha1.Assignment {
+	asm := make([]experimentsv1alpha1.Assignment, 0, len(exp.Parameters))
+	for _, p := range exp.Parameters {
+		var v json.Number
+		switch p.Type {
+		case experimentsv1alpha1.ParameterTypeInteger:
+			min, _ := p.Bounds.Min.Int64()
+			max, _ := p.Bounds.Max.Int64()
+			v = json.Number(strconv.FormatInt(min+s.Rand.Int63n(max-min+1), 10))
+		case experimentsv1alpha1.ParameterTypeCategorical:
+			v = json.Number(p.Values[s.Rand.Intn(len(p.Values))])
+		default:
+			min, _ := p.Bounds.Min.Float64()
+			max, _ := p.Bounds.Max.Float64()
+			v = json.Number(strconv.FormatFloat(min+s.Rand.Float64()*(max-min), 'f', -1, 64))
+		}
+		asm = append(asm, experimentsv1alpha1.Assignment{ParameterName: p.Name, Value: v})
+	}
+	return asm
+}
+
+// addTrial appends a new trial to the experiment
+func (e *experiment) addTrial(status experimentsv1alpha1.TrialStatus, asm []experimentsv1alpha1.Assignment) *experimentsv1alpha1.TrialItem {
+	t := &experimentsv1alpha1.TrialItem{Status: status, Number: int64(len(e.trials) + 1)}
+	t.Assignments = asm
+	e.trials = append(e.trials, t)
+	return t
+}
+
+func validateExperiment(exp *experimentsv1alpha1.Experiment) error {
+	if len(exp.Parameters) == 0 {
+		return fmt.Errorf("experiment must have at least one parameter")
+	}
+	if len(exp.Metrics) == 0 {
+		return fmt.Errorf("experiment must have at least one metric")
+	}
+	for _, p := range exp.Parameters {
+		if p.Type == experimentsv1alpha1.ParameterTypeCategorical {
+			if len(p.Values) == 0 {
+				return fmt.Errorf("categorical parameter %q must have at least one value", p.Name)
+			}
+			continue
+		}
+		min, minErr := p.Bounds.Min.Float64()
+		max, maxErr := p.Bounds.Max.Float64()
+		if minErr != nil || maxErr != nil || min > max {
+			return fmt.Errorf("invalid bounds for parameter %q", p.Name)
+		}
+	}
+	return nil
+}
+
+func validateAssignments(exp *experimentsv1alpha1.Experiment, asm []experimentsv1alpha1.Assignment) error {
+	values := make(map[string]json.Number, len(asm))
+	for _, a := range asm {
+		values[a.ParameterName] = a.Value
+	}
+	for _, p := range exp.Parameters {
+		v, ok := values[p.Name]
+		if !ok {
+			return fmt.Errorf("missing assignment for parameter %q", p.Name)
+		}
+		if p.Type == experimentsv1alpha1.ParameterTypeCategorical {
+			if !contains(p.Values, v.String()) {
+				return fmt.Errorf("assignment for parameter %q is not one of the allowed values", p.Name)
+			}
+			continue
+		}
+		f, err := v.Float64()
+		min, _ := p.Bounds.Min.Float64()
+		max, _ := p.Bounds.Max.Float64()
+		if err != nil || f < min || f > max {
+			return fmt.Errorf("assignment for parameter %q is out of bounds", p.Name)
+		}
+	}
+	return nil
+}
+
+func validateValues(exp *experimentsv1alpha1.Experiment, vls *experimentsv1alpha1.TrialValues) error {
+	if vls.Failed {
+		return nil
+	}
+	values := make(map[string]bool, len(vls.Values))
+	for _, v := range vls.Values {
+		values[v.MetricName] = true
+	}
+	for _, m := range exp.Metrics {
+		if !values[m.Name] {
+			return fmt.Errorf("missing value for metric %q", m.Name)
+		}
+	}
+	return nil
+}
+
+// parseSelector parses a comma separated list of "key=value" pairs
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func parseSelector(s string) map[string]string {
+	selector := make(map[string]string)
+	for _, kv := range strings.Split(s, ",") {
+		if p := strings.SplitN(kv, "=", 2); len(p) == 2 {
+			selector[p[0]] = p[1]
+		}
+	}
+	return selector
+}
+
+func matches(labels, selector map[string]string) bool {
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeLabels adds new labels to existing labels, labels with empty values are removed
+func mergeLabels(labels, newLabels map[string]string) map[string]string {
+	if labels == nil {
+		labels = make(map[string]string, len(newLabels))
+	}
+	for k, v := range newLabels {
+		if v == "" {
+			delete(labels, k)
+		} else {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + endpointExperiment
+}
+
+func trialURL(r *http.Request, name string, number int64) string {
+	return fmt.Sprintf("%s%s/trials/%d", baseURL(r), name, number)
+}
+
+func experimentLinks(r *http.Request, name string) []string {
+	self := baseURL(r) + name
+	return []string{
+		fmt.Sprintf(`<%s>; rel="self"`, self),
+		fmt.Sprintf(`<%s/trials/>; rel="%s"`, self, relationTrials),
+		fmt.Sprintf(`<%s/nextTrial>; rel="%s"`, self, relationNextTrial),
+		fmt.Sprintf(`<%s/labels>; rel="%s"`, self, relationLabels),
+	}
+}
+
+func writeExperiment(w http.ResponseWriter, r *http.Request, status int, name string, exp *experiment) {
+	for _, l := range experimentLinks(r, name) {
+		w.Header().Add("Link", l)
+	}
+	w.Header().Set("Last-Modified", exp.lastModified.UTC().Format(http.TimeFormat))
+	writeJSON(w, status, exp.Experiment)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, &experimentsv1alpha1.Error{Message: message})
+}
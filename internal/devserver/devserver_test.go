@@ -0,0 +1,152 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package devserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/redskyops/redskyops-controller/internal/config"
+	"github.com/redskyops/redskyops-controller/redskyapi"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig points the API client at a test server
+type testConfig struct{ url string }
+
+func (c *testConfig) Endpoints() (config.Endpoints, error) {
+	u, err := url.Parse(c.url + endpointExperiment)
+	if err != nil {
+		return nil, err
+	}
+	return config.Endpoints{endpointExperiment: u}, nil
+}
+
+func (c *testConfig) Authorize(ctx context.Context, transport http.RoundTripper) (http.RoundTripper, error) {
+	return transport, nil
+}
+
+func TestServer(t *testing.T) {
+	srv := httptest.NewServer(NewServer())
+	defer srv.Close()
+
+	ctx := context.TODO()
+	c, err := redskyapi.NewClient(ctx, &testConfig{url: srv.URL}, nil)
+	require.NoError(t, err)
+	api := experimentsv1alpha1.NewAPI(c)
+
+	// Create an experiment
+	exp, err := api.CreateExperiment(ctx, experimentsv1alpha1.NewExperimentName("test"), experimentsv1alpha1.Experiment{
+		Metrics: []experimentsv1alpha1.Metric{{Name: "duration", Minimize: true}},
+		Parameters: []experimentsv1alpha1.Parameter{
+			{Name: "replicas", Type: experimentsv1alpha1.ParameterTypeInteger, Bounds: experimentsv1alpha1.Bounds{Min: "1", Max: "5"}},
+			{Name: "ratio", Type: experimentsv1alpha1.ParameterTypeDouble, Bounds: experimentsv1alpha1.Bounds{Min: "0.1", Max: "0.9"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "test", exp.Name())
+	require.NotEmpty(t, exp.NextTrialURL)
+	require.NotEmpty(t, exp.TrialsURL)
+
+	// Optimization settings can be changed
+	exp, err = api.UpdateExperiment(ctx, exp.SelfURL, experimentsv1alpha1.ExperimentPatch{
+		Optimization: []experimentsv1alpha1.Optimization{{Name: experimentsv1alpha1.OptimizationParallelTrials, Value: "2"}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, exp.Optimization, experimentsv1alpha1.Optimization{Name: experimentsv1alpha1.OptimizationParallelTrials, Value: "2"})
+	require.NotEmpty(t, exp.NextTrialURL)
+
+	// Invalid experiments are rejected
+	_, err = api.CreateExperiment(ctx, experimentsv1alpha1.NewExperimentName("invalid"), experimentsv1alpha1.Experiment{})
+	if assert.Error(t, err) {
+		assert.Equal(t, experimentsv1alpha1.ErrExperimentInvalid, err.(*experimentsv1alpha1.Error).Type)
+	}
+
+	// Suggestions must be in bounds
+	asm, err := api.NextTrial(ctx, exp.NextTrialURL)
+	require.NoError(t, err)
+	require.Len(t, asm.Assignments, 2)
+	replicas, err := asm.Assignments[0].Value.Int64()
+	require.NoError(t, err)
+	assert.True(t, replicas >= 1 && replicas <= 5)
+	ratio, err := asm.Assignments[1].Value.Float64()
+	require.NoError(t, err)
+	assert.True(t, ratio >= 0.1 && ratio <= 0.9)
+
+	// Report the trial, reporting twice is a conflict unless it is a retry with the same idempotency key
+	vls := experimentsv1alpha1.TrialValues{Values: []experimentsv1alpha1.Value{{MetricName: "duration", Value: 42}}, IdempotencyKey: "test-1"}
+	require.NoError(t, api.ReportTrial(ctx, asm.SelfURL, vls))
+	require.NoError(t, api.ReportTrial(ctx, asm.SelfURL, vls))
+	err = api.ReportTrial(ctx, asm.SelfURL, experimentsv1alpha1.TrialValues{Values: vls.Values})
+	if assert.Error(t, err) {
+		assert.Equal(t, experimentsv1alpha1.ErrTrialAlreadyReported, err.(*experimentsv1alpha1.Error).Type)
+	}
+
+	// Explicitly created trials are suggested first
+	created := []experimentsv1alpha1.Assignment{{ParameterName: "replicas", Value: "3"}, {ParameterName: "ratio", Value: json.Number("0.5")}}
+	_, err = api.CreateTrial(ctx, exp.TrialsURL, experimentsv1alpha1.TrialAssignments{Assignments: created})
+	require.NoError(t, err)
+	asm, err = api.NextTrial(ctx, exp.NextTrialURL)
+	require.NoError(t, err)
+	assert.Equal(t, created, asm.Assignments)
+	require.NoError(t, api.AbandonRunningTrial(ctx, asm.SelfURL))
+
+	// List the trials
+	lst, err := api.GetAllTrials(ctx, exp.TrialsURL, &experimentsv1alpha1.TrialListQuery{Status: []experimentsv1alpha1.TrialStatus{experimentsv1alpha1.TrialCompleted}})
+	require.NoError(t, err)
+	require.Len(t, lst.Trials, 1)
+	assert.Equal(t, int64(1), lst.Trials[0].Number)
+	assert.Equal(t, vls.Values, lst.Trials[0].Values)
+
+	// Iterate over the trials
+	var numbers []int64
+	err = experimentsv1alpha1.ForEachTrial(ctx, api, exp.TrialsURL, nil, func(t *experimentsv1alpha1.TrialItem) error {
+		numbers = append(numbers, t.Number)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, numbers, 2)
+
+	exp, err = api.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName("test"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), exp.Observations)
+
+	// Report a batch of completed trials, an invalid trial rejects the entire batch
+	rpts := []experimentsv1alpha1.TrialReport{
+		{Assignments: created, TrialValues: experimentsv1alpha1.TrialValues{Values: vls.Values}},
+		{Assignments: created, TrialValues: experimentsv1alpha1.TrialValues{Failed: true}, Labels: map[string]string{"source": "import"}},
+	}
+	err = api.ReportTrials(ctx, exp.SelfURL, append(rpts, experimentsv1alpha1.TrialReport{Assignments: created}))
+	if assert.Error(t, err) {
+		assert.Equal(t, experimentsv1alpha1.ErrTrialInvalid, err.(*experimentsv1alpha1.Error).Type)
+	}
+	require.NoError(t, api.ReportTrials(ctx, exp.SelfURL, rpts))
+	exp, err = api.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName("test"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), exp.Observations)
+
+	// Delete the experiment
+	require.NoError(t, api.DeleteExperiment(ctx, exp.SelfURL))
+	_, err = api.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName("test"))
+	assert.Error(t, err)
+}
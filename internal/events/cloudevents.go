@@ -0,0 +1,122 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+)
+
+// CloudEventsContentType is the media type of a structured mode CloudEvent
+const CloudEventsContentType = "application/cloudevents+json"
+
+// cloudEvent is the structured mode JSON encoding of a version 1.0 CloudEvent
+type cloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            *Event    `json:"data"`
+}
+
+// CloudEventsPublisher posts events to an HTTP sink (e.g. a Knative broker or an Argo Events webhook source) as
+// structured mode CloudEvents; requests are signed when the client transport signs them (see the hmacauth package)
+type CloudEventsPublisher struct {
+	// URL is the location events are posted to
+	URL string
+	// Client is used to post events, uses a client with a short timeout if nil
+	Client *http.Client
+}
+
+// Publish posts the event using the topic as the CloudEvent type; the event identifier is derived from the event
+// contents so sinks can discard duplicate deliveries. There are no retries here, failed events are retried when the
+// object is reconciled again; events rejected by the sink produce an error for which IsPermanent returns true.
+func (p *CloudEventsPublisher) Publish(topic string, e *Event) error {
+	b, err := json.Marshal(&cloudEvent{
+		SpecVersion:     "1.0",
+		ID:              cloudEventID(topic, e),
+		Source:          path.Join("/apis/redskyops.dev/v1beta1/namespaces", e.Namespace, "experiments", e.Experiment),
+		Type:            topic,
+		Subject:         e.Trial,
+		Time:            e.Time,
+		DataContentType: "application/json",
+		Data:            e,
+	})
+	if err != nil {
+		return err
+	}
+
+	c := p.Client
+	if c == nil {
+		c = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	return p.post(c, b)
+}
+
+func (p *CloudEventsPublisher) post(c *http.Client, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", CloudEventsContentType)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	switch {
+	case resp.StatusCode/100 == 2:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode/100 == 5:
+		return fmt.Errorf("cloudevents sink returned status %d", resp.StatusCode)
+	default:
+		return &permanentError{StatusCode: resp.StatusCode}
+	}
+}
+
+// permanentError is a sink response which will not succeed if retried
+type permanentError struct {
+	StatusCode int
+}
+
+func (e *permanentError) Error() string {
+	return fmt.Sprintf("cloudevents sink rejected event with status %d", e.StatusCode)
+}
+
+// IsPermanent checks to see if an error returned while publishing an event will not succeed if retried
+func IsPermanent(err error) bool {
+	_, ok := err.(*permanentError)
+	return ok
+}
+
+// cloudEventID returns a stable identifier for the event
+func cloudEventID(topic string, e *Event) string {
+	h := sha256.Sum256([]byte(path.Join(topic, e.Namespace, e.Experiment, e.Trial)))
+	return hex.EncodeToString(h[:16])
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package events publishes trial and experiment lifecycle events to a message bus so other systems can react to
+// optimization progress without polling the cluster.
+package events
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Type identifies the kind of lifecycle event, it is also used as the suffix of the topic the event is published to
+type Type string
+
+const (
+	// TrialCreated is published when a new trial is created
+	TrialCreated Type = "trial.created"
+	// TrialCompleted is published when a trial finishes successfully
+	TrialCompleted Type = "trial.completed"
+	// TrialFailed is published when a trial fails
+	TrialFailed Type = "trial.failed"
+	// NewBest is published when a completed trial is not dominated by any other completed trial of the experiment
+	NewBest Type = "trial.best"
+	// ExperimentCompleted is published when an experiment exhausts its trial budget
+	ExperimentCompleted Type = "experiment.completed"
+	// ExperimentConverged is published when the recent trials of an experiment stop improving the metric values
+	ExperimentConverged Type = "experiment.converged"
+)
+
+// Event is a single lifecycle event
+type Event struct {
+	// Type is the kind of event
+	Type Type `json:"type"`
+	// Time is when the event was published
+	Time time.Time `json:"time"`
+	// Namespace is the namespace of the experiment
+	Namespace string `json:"namespace"`
+	// Experiment is the name of the experiment
+	Experiment string `json:"experiment"`
+	// Trial is the name of the trial, empty for experiment events
+	Trial string `json:"trial,omitempty"`
+	// Assignments are the parameter values of the trial, sensitive values are redacted
+	Assignments map[string]string `json:"assignments,omitempty"`
+	// Values are the metric values of the trial
+	Values map[string]string `json:"values,omitempty"`
+	// Reason is the machine readable reason a trial failed
+	Reason string `json:"reason,omitempty"`
+	// Message is the human readable explanation of a trial failure
+	Message string `json:"message,omitempty"`
+}
+
+// Key returns the message key used for partitioning, events for the same experiment share a key so ordering is
+// preserved by consumers
+func (e *Event) Key() string {
+	return e.Namespace + "/" + e.Experiment
+}
+
+// Publisher is a destination for lifecycle events
+type Publisher interface {
+	// Publish sends a single event to the named topic (or subject)
+	Publish(topic string, e *Event) error
+}
+
+// NewPublisher returns a publisher for the supplied target; a "nats://" URL publishes to a NATS server, a
+// "cloudevents+http(s)://" URL posts CloudEvents to an HTTP sink and an HTTP(S) URL publishes through a Kafka REST proxy
+func NewPublisher(target string) (Publisher, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "nats":
+		p := &NATSPublisher{Address: u.Host}
+		if u.User != nil {
+			p.User = u.User.Username()
+			p.Password, _ = u.User.Password()
+		}
+		return p, nil
+	case "cloudevents+http", "cloudevents+https":
+		return &CloudEventsPublisher{URL: strings.TrimPrefix(target, "cloudevents+")}, nil
+	case "http", "https":
+		return &KafkaRESTPublisher{URL: strings.TrimSuffix(target, "/")}, nil
+	default:
+		return nil, fmt.Errorf("unsupported event publisher %q, expected a nats://, cloudevents+http(s):// or http(s):// URL", target)
+	}
+}
+
+// Emitter populates common event fields before publishing events
+type Emitter struct {
+	// Publisher is where events are sent
+	Publisher Publisher
+	// TopicPrefix is prepended to the event type to produce the topic name, e.g. "redskyops."
+	TopicPrefix string
+	// Now returns the current time, uses the system time if nil
+	Now func() time.Time
+}
+
+// Emit fills in the time of the event and publishes it
+func (m *Emitter) Emit(e *Event) error {
+	if m == nil || m.Publisher == nil {
+		return nil
+	}
+
+	if m.Now != nil {
+		e.Time = m.Now()
+	} else {
+		e.Time = time.Now()
+	}
+
+	return m.Publisher.Publish(m.TopicPrefix+string(e.Type), e)
+}
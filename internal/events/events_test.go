@@ -0,0 +1,277 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/experiment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTrialEvents(t *testing.T) {
+	pending := &redskyv1beta1.Trial{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "test-001",
+			Labels:      map[string]string{redskyv1beta1.LabelExperiment: "test"},
+			Annotations: map[string]string{redskyv1beta1.AnnotationSensitiveParameters: "secret"},
+		},
+		Spec: redskyv1beta1.TrialSpec{
+			Assignments: []redskyv1beta1.Assignment{
+				{Name: "cpu", Value: redskyv1beta1.NewAssignmentValue(100)},
+				{Name: "secret", Value: redskyv1beta1.NewAssignmentValue(7)},
+			},
+		},
+	}
+	running := withCondition(pending, redskyv1beta1.TrialPatched)
+	completed := withCondition(running, redskyv1beta1.TrialComplete)
+	failed := withCondition(running, redskyv1beta1.TrialFailed)
+
+	cases := []struct {
+		desc      string
+		trial     *redskyv1beta1.Trial
+		published []Type
+		expected  []Type
+	}{
+		{desc: "created", trial: pending, expected: []Type{TrialCreated}},
+		{desc: "progress", trial: running, published: []Type{TrialCreated}},
+		{desc: "completed", trial: completed, published: []Type{TrialCreated}, expected: []Type{TrialCompleted}},
+		{desc: "failed", trial: failed, published: []Type{TrialCreated}, expected: []Type{TrialFailed}},
+		{desc: "missed creation", trial: completed, expected: []Type{TrialCreated, TrialCompleted}},
+		{desc: "already finished", trial: completed, published: []Type{TrialCreated, TrialCompleted}},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			published := make(map[Type]bool)
+			for _, p := range c.published {
+				published[p] = true
+			}
+
+			var actual []Type
+			for _, e := range TrialEvents(c.trial, published) {
+				assert.Equal(t, "test", e.Experiment)
+				assert.Equal(t, map[string]string{"cpu": "100", "secret": "***"}, e.Assignments)
+				actual = append(actual, e.Type)
+			}
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestExperimentEvents(t *testing.T) {
+	running := &redskyv1beta1.Experiment{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"}}
+	running.Status.Phase = experiment.PhaseRunning
+	converged := running.DeepCopy()
+	experiment.ApplyCondition(&converged.Status, redskyv1beta1.ExperimentConverged, corev1.ConditionTrue, "NoImprovement", "done", nil)
+	completed := running.DeepCopy()
+	completed.Status.Phase = experiment.PhaseCompleted
+
+	assert.Empty(t, ExperimentEvents(running, map[Type]bool{}))
+	assert.Empty(t, ExperimentEvents(completed, map[Type]bool{ExperimentCompleted: true}))
+	assert.Empty(t, ExperimentEvents(converged, map[Type]bool{ExperimentConverged: true}))
+	assert.Equal(t, []*Event{{Type: ExperimentCompleted, Namespace: "default", Experiment: "test"}}, ExperimentEvents(completed, map[Type]bool{}))
+	assert.Equal(t, []*Event{{Type: ExperimentConverged, Namespace: "default", Experiment: "test", Reason: "NoImprovement", Message: "done"}}, ExperimentEvents(converged, map[Type]bool{}))
+}
+
+func TestPublished(t *testing.T) {
+	exp := &redskyv1beta1.Experiment{}
+
+	published, observed := Published(exp)
+	assert.False(t, observed)
+	assert.Empty(t, published)
+
+	SetPublished(exp, published)
+	published, observed = Published(exp)
+	assert.True(t, observed)
+	assert.Empty(t, published)
+
+	SetPublished(exp, map[Type]bool{ExperimentCompleted: true, ExperimentConverged: true})
+	assert.Equal(t, "experiment.completed,experiment.converged", exp.Annotations[redskyv1beta1.AnnotationPublishedEvents])
+	published, observed = Published(exp)
+	assert.True(t, observed)
+	assert.Equal(t, map[Type]bool{ExperimentCompleted: true, ExperimentConverged: true}, published)
+}
+
+func TestBestTrialEvent(t *testing.T) {
+	exp := &redskyv1beta1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test"},
+		Spec: redskyv1beta1.ExperimentSpec{
+			Metrics: []redskyv1beta1.Metric{{Name: "cost", Minimize: true}, {Name: "throughput"}},
+		},
+	}
+	trialList := &redskyv1beta1.TrialList{Items: []redskyv1beta1.Trial{
+		*completedTrial("test-000", "10", "100"),
+		*completedTrial("test-001", "20", "300"),
+	}}
+
+	cases := []struct {
+		desc  string
+		trial *redskyv1beta1.Trial
+		best  bool
+	}{
+		{desc: "dominates all", trial: completedTrial("test-002", "5", "400"), best: true},
+		{desc: "pareto optimal", trial: completedTrial("test-002", "15", "200"), best: true},
+		{desc: "dominated", trial: completedTrial("test-002", "25", "250")},
+		{desc: "missing value", trial: completedTrial("test-002", "5", "")},
+		{desc: "stale self", trial: completedTrial("test-000", "11", "100"), best: true},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			e := BestTrialEvent(exp, c.trial, trialList)
+			if !c.best {
+				assert.Nil(t, e)
+				return
+			}
+			require.NotNil(t, e)
+			assert.Equal(t, NewBest, e.Type)
+			assert.Equal(t, c.trial.Name, e.Trial)
+		})
+	}
+}
+
+func TestNATSPublisher(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	published := make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		_, _ = fmt.Fprint(conn, "INFO {}\r\n")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch {
+			case strings.HasPrefix(line, "PING"):
+				_, _ = fmt.Fprint(conn, "PONG\r\n")
+			case strings.HasPrefix(line, "PUB "):
+				payload, _ := r.ReadString('\n')
+				published <- line + payload
+			}
+		}
+	}()
+
+	p := &NATSPublisher{Address: l.Addr().String()}
+	defer p.Close()
+	require.NoError(t, p.Publish("redskyops.trial.created", &Event{Type: TrialCreated, Experiment: "test"}))
+
+	msg := <-published
+	assert.True(t, strings.HasPrefix(msg, "PUB redskyops.trial.created "))
+	assert.Contains(t, msg, `"experiment":"test"`)
+}
+
+func TestKafkaRESTPublisher(t *testing.T) {
+	var path, contentType string
+	var body kafkaRecords
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		b, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &body)
+		_, _ = fmt.Fprint(w, `{"offsets":[{"partition":0,"offset":1}]}`)
+	}))
+	defer srv.Close()
+
+	p := &KafkaRESTPublisher{URL: srv.URL}
+	require.NoError(t, p.Publish("redskyops.trial.completed", &Event{Type: TrialCompleted, Namespace: "default", Experiment: "test"}))
+	assert.Equal(t, "/topics/redskyops.trial.completed", path)
+	assert.Equal(t, "application/vnd.kafka.json.v2+json", contentType)
+	require.Len(t, body.Records, 1)
+	assert.Equal(t, "default/test", body.Records[0].Key)
+}
+
+func withCondition(t *redskyv1beta1.Trial, conditionType redskyv1beta1.TrialConditionType) *redskyv1beta1.Trial {
+	t = t.DeepCopy()
+	t.Status.Conditions = append(t.Status.Conditions, redskyv1beta1.TrialCondition{Type: conditionType, Status: corev1.ConditionTrue})
+	return t
+}
+
+func completedTrial(name, cost, throughput string) *redskyv1beta1.Trial {
+	t := &redskyv1beta1.Trial{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+	t.Spec.Values = append(t.Spec.Values, redskyv1beta1.Value{Name: "cost", Value: cost})
+	if throughput != "" {
+		t.Spec.Values = append(t.Spec.Values, redskyv1beta1.Value{Name: "throughput", Value: throughput})
+	}
+	return withCondition(t, redskyv1beta1.TrialComplete)
+}
+
+func TestCloudEventsPublisher(t *testing.T) {
+	attempts := 0
+	statuses := []int{http.StatusServiceUnavailable}
+	var contentType string
+	var ce map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if len(statuses) > 0 {
+			w.WriteHeader(statuses[0])
+			statuses = statuses[1:]
+			return
+		}
+		contentType = r.Header.Get("Content-Type")
+		b, _ := ioutil.ReadAll(r.Body)
+		_ = json.Unmarshal(b, &ce)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	p, err := NewPublisher("cloudevents+" + srv.URL)
+	require.NoError(t, err)
+
+	// Server errors are returned to be retried later
+	err = p.Publish("redskyops.trial.failed", &Event{Type: TrialFailed, Namespace: "default", Experiment: "test", Trial: "test-001"})
+	if assert.Error(t, err) {
+		assert.False(t, IsPermanent(err))
+	}
+	assert.Equal(t, 1, attempts)
+
+	require.NoError(t, p.Publish("redskyops.trial.failed", &Event{Type: TrialFailed, Namespace: "default", Experiment: "test", Trial: "test-001"}))
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, CloudEventsContentType, contentType)
+	assert.Equal(t, "1.0", ce["specversion"])
+	assert.Equal(t, "redskyops.trial.failed", ce["type"])
+	assert.Equal(t, "/apis/redskyops.dev/v1beta1/namespaces/default/experiments/test", ce["source"])
+	assert.Equal(t, "test-001", ce["subject"])
+	assert.NotEmpty(t, ce["id"])
+
+	// Client errors are permanent
+	attempts = 0
+	statuses = []int{http.StatusBadRequest, http.StatusAccepted}
+	err = p.Publish("redskyops.trial.failed", &Event{Type: TrialFailed})
+	if assert.Error(t, err) {
+		assert.True(t, IsPermanent(err))
+	}
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// KafkaRESTPublisher publishes events to Kafka through a REST proxy implementing the Confluent v2 API
+type KafkaRESTPublisher struct {
+	// URL is the base location of the REST proxy
+	URL string
+	// Client is used to post events, uses a client with a short timeout if nil
+	Client *http.Client
+}
+
+// kafkaRecords is the body of a v2 produce request
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key"`
+	Value *Event `json:"value"`
+}
+
+// kafkaOffsets is the body of a v2 produce response
+type kafkaOffsets struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// Publish produces the event to the topic keyed by experiment
+func (p *KafkaRESTPublisher) Publish(topic string, e *Event) error {
+	b, err := json.Marshal(&kafkaRecords{Records: []kafkaRecord{{Key: e.Key(), Value: e}}})
+	if err != nil {
+		return err
+	}
+
+	c := p.Client
+	if c == nil {
+		c = &http.Client{Timeout: 5 * time.Second}
+	}
+
+	resp, err := c.Post(p.URL+"/topics/"+url.PathEscape(topic), "application/vnd.kafka.json.v2+json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("kafka REST proxy returned status %d", resp.StatusCode)
+	}
+
+	// Individual records can fail even when the request succeeds
+	offsets := &kafkaOffsets{}
+	if err := json.NewDecoder(resp.Body).Decode(offsets); err == nil {
+		for _, o := range offsets.Offsets {
+			if o.ErrorCode != nil || o.Error != "" {
+				return fmt.Errorf("kafka REST proxy failed to produce record: %s", o.Error)
+			}
+		}
+	}
+	return nil
+}
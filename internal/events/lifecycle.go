@@ -0,0 +1,184 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"strconv"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/analysis"
+	"github.com/redskyops/redskyops-controller/internal/experiment"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Published returns the event types already published for an object, false if the object has never been observed
+func Published(obj metav1.Object) (map[Type]bool, bool) {
+	value, ok := obj.GetAnnotations()[redskyv1beta1.AnnotationPublishedEvents]
+	published := make(map[Type]bool)
+	for _, t := range strings.Split(value, ",") {
+		if t != "" {
+			published[Type(t)] = true
+		}
+	}
+	return published, ok
+}
+
+// SetPublished records the event types published for an object
+func SetPublished(obj metav1.Object, published map[Type]bool) {
+	var types []string
+	for _, t := range []Type{TrialCreated, TrialCompleted, TrialFailed, ExperimentCompleted, ExperimentConverged} {
+		if published[t] {
+			types = append(types, string(t))
+		}
+	}
+
+	a := obj.GetAnnotations()
+	if a == nil {
+		a = make(map[string]string)
+	}
+	a[redskyv1beta1.AnnotationPublishedEvents] = strings.Join(types, ",")
+	obj.SetAnnotations(a)
+}
+
+// TrialEvents returns the events for the current state of a trial which have not already been published
+func TrialEvents(t *redskyv1beta1.Trial, published map[Type]bool) []*Event {
+	var events []*Event
+	if !published[TrialCreated] {
+		events = append(events, newTrialEvent(TrialCreated, t))
+	}
+
+	switch {
+	case published[TrialCompleted] || published[TrialFailed]:
+		// The trial can only finish once
+
+	case trial.CheckCondition(&t.Status, redskyv1beta1.TrialComplete, corev1.ConditionTrue):
+		events = append(events, newTrialEvent(TrialCompleted, t))
+
+	case trial.CheckCondition(&t.Status, redskyv1beta1.TrialFailed, corev1.ConditionTrue):
+		e := newTrialEvent(TrialFailed, t)
+		for _, c := range t.Status.Conditions {
+			if c.Type == redskyv1beta1.TrialFailed {
+				e.Reason, e.Message = c.Reason, c.Message
+			}
+		}
+		events = append(events, e)
+	}
+
+	return events
+}
+
+// ExperimentEvents returns the events for the current state of an experiment which have not already been published
+func ExperimentEvents(exp *redskyv1beta1.Experiment, published map[Type]bool) []*Event {
+	var events []*Event
+	if !published[ExperimentConverged] && experiment.CheckCondition(&exp.Status, redskyv1beta1.ExperimentConverged, corev1.ConditionTrue) {
+		c := experiment.GetCondition(&exp.Status, redskyv1beta1.ExperimentConverged)
+		events = append(events, &Event{Type: ExperimentConverged, Namespace: exp.Namespace, Experiment: exp.Name, Reason: c.Reason, Message: c.Message})
+	}
+	if !published[ExperimentCompleted] && exp.Status.Phase == experiment.PhaseCompleted {
+		events = append(events, &Event{Type: ExperimentCompleted, Namespace: exp.Namespace, Experiment: exp.Name})
+	}
+	return events
+}
+
+// BestTrialEvent returns the event for a completed trial which is not dominated by any other completed trial in the
+// list; with a single metric this is simply the best trial so far. The trial itself is skipped in the list since it
+// may be stale. Note that only trials which have not yet been cleaned up are considered.
+func BestTrialEvent(exp *redskyv1beta1.Experiment, t *redskyv1beta1.Trial, trialList *redskyv1beta1.TrialList) *Event {
+	if len(exp.Spec.Metrics) == 0 || !trial.CheckCondition(&t.Status, redskyv1beta1.TrialComplete, corev1.ConditionTrue) {
+		return nil
+	}
+
+	values, ok := metricValues(exp, t)
+	if !ok {
+		return nil
+	}
+
+	objectives := make([]analysis.Objective, 0, len(exp.Spec.Metrics))
+	for _, m := range exp.Spec.Metrics {
+		objectives = append(objectives, analysis.Objective{Name: m.Name, Minimize: m.Minimize})
+	}
+
+	for i := range trialList.Items {
+		o := &trialList.Items[i]
+		if o.Namespace == t.Namespace && o.Name == t.Name {
+			continue
+		}
+		if !trial.CheckCondition(&o.Status, redskyv1beta1.TrialComplete, corev1.ConditionTrue) {
+			continue
+		}
+		if other, ok := metricValues(exp, o); ok && analysis.Dominates(objectives, other, values) {
+			return nil
+		}
+	}
+
+	return newTrialEvent(NewBest, t)
+}
+
+// newTrialEvent returns an event describing the trial
+func newTrialEvent(eventType Type, t *redskyv1beta1.Trial) *Event {
+	e := &Event{
+		Type:       eventType,
+		Namespace:  t.Namespace,
+		Experiment: t.ExperimentNamespacedName().Name,
+		Trial:      t.Name,
+	}
+
+	if len(t.Spec.Assignments) > 0 {
+		e.Assignments = make(map[string]string, len(t.Spec.Assignments))
+		for _, a := range t.Spec.Assignments {
+			if trial.IsSensitive(t, a.Name) {
+				e.Assignments[a.Name] = trial.Redacted
+			} else {
+				e.Assignments[a.Name] = a.Value.String()
+			}
+		}
+	}
+
+	if len(t.Spec.Values) > 0 {
+		e.Values = make(map[string]string, len(t.Spec.Values))
+		for _, v := range t.Spec.Values {
+			e.Values[v.Name] = v.Value
+		}
+	}
+
+	return e
+}
+
+// metricValues returns the parsed values of the experiment metrics, false if any value is missing
+func metricValues(exp *redskyv1beta1.Experiment, t *redskyv1beta1.Trial) (map[string]float64, bool) {
+	values := make(map[string]float64, len(exp.Spec.Metrics))
+	for _, m := range exp.Spec.Metrics {
+		found := false
+		for _, v := range t.Spec.Values {
+			if v.Name != m.Name {
+				continue
+			}
+			f, err := strconv.ParseFloat(v.Value, 64)
+			if err != nil {
+				return nil, false
+			}
+			values[m.Name], found = f, true
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return values, true
+}
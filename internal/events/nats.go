@@ -0,0 +1,160 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATSPublisher publishes events to a NATS server using the core text protocol; each publish is followed by a PING
+// so the server acknowledges the message before the call returns
+type NATSPublisher struct {
+	// Address is the host and port of the NATS server
+	Address string
+	// User is the optional user name sent when connecting
+	User string
+	// Password is the optional password sent when connecting
+	Password string
+	// Timeout bounds connecting and each publish, defaults to 5 seconds if zero
+	Timeout time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// Publish sends the event to the subject, the connection is re-established once if the existing one has gone stale
+func (p *NATSPublisher) Publish(subject string, e *Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// A connection which has been idle may have been closed by the server, retry with a new connection
+	if err := p.publish(subject, b); err != nil {
+		p.close()
+		return p.publish(subject, b)
+	}
+	return nil
+}
+
+// Close terminates the connection to the server
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.close()
+}
+
+func (p *NATSPublisher) publish(subject string, payload []byte) error {
+	if err := p.connect(); err != nil {
+		return err
+	}
+
+	if err := p.conn.SetDeadline(time.Now().Add(p.timeout())); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(p.conn, "PUB %s %d\r\n%s\r\nPING\r\n", subject, len(payload), payload); err != nil {
+		return err
+	}
+	return p.waitForPong()
+}
+
+func (p *NATSPublisher) connect() error {
+	if p.conn != nil {
+		return nil
+	}
+
+	conn, err := net.DialTimeout("tcp", p.Address, p.timeout())
+	if err != nil {
+		return err
+	}
+	p.conn, p.r = conn, bufio.NewReader(conn)
+
+	// The server always starts by sending its INFO
+	if err := p.conn.SetDeadline(time.Now().Add(p.timeout())); err != nil {
+		return err
+	}
+	line, err := p.r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected NATS greeting: %s", strings.TrimSpace(line))
+	}
+
+	opts, err := json.Marshal(map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "redskyops-controller",
+		"lang":     "go",
+		"user":     p.User,
+		"pass":     p.Password,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(p.conn, "CONNECT %s\r\nPING\r\n", opts); err != nil {
+		return err
+	}
+	return p.waitForPong()
+}
+
+// waitForPong reads server operations until the acknowledgement of our PING, answering any server PINGs
+func (p *NATSPublisher) waitForPong() error {
+	for {
+		line, err := p.r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := fmt.Fprint(p.conn, "PONG\r\n"); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("NATS error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+func (p *NATSPublisher) close() error {
+	if p.conn == nil {
+		return nil
+	}
+	err := p.conn.Close()
+	p.conn, p.r = nil, nil
+	return err
+}
+
+func (p *NATSPublisher) timeout() time.Duration {
+	if p.Timeout > 0 {
+		return p.Timeout
+	}
+	return 5 * time.Second
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiment
+
+import (
+	"strconv"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/analysis"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SuggestBounds analyzes the completed trials in the cluster to determine if the bounds of any parameters should be
+// expanded; note that only trials which have not yet been cleaned up are considered
+func SuggestBounds(exp *redskyv1beta1.Experiment, trialList *redskyv1beta1.TrialList) []analysis.BoundsSuggestion {
+	var dimensions []analysis.Dimension
+	for _, p := range exp.Spec.Parameters {
+		if len(p.Values) > 0 {
+			continue
+		}
+		dimensions = append(dimensions, analysis.Dimension{Name: p.Name, Min: float64(p.Min.Int64Value()), Max: float64(p.Max.Int64Value()), Integer: true})
+	}
+
+	objectives := make([]analysis.Objective, 0, len(exp.Spec.Metrics))
+	for _, m := range exp.Spec.Metrics {
+		objectives = append(objectives, analysis.Objective{Name: m.Name, Minimize: m.Minimize})
+	}
+
+	var observations []analysis.Observation
+	for i := range trialList.Items {
+		t := &trialList.Items[i]
+		if !trial.CheckCondition(&t.Status, redskyv1beta1.TrialComplete, corev1.ConditionTrue) {
+			continue
+		}
+		o := analysis.Observation{Assignments: make(map[string]float64), Values: make(map[string]float64)}
+		for _, a := range t.Spec.Assignments {
+			if v, ok := a.Int64Value(); ok {
+				o.Assignments[a.Name] = float64(v)
+			}
+		}
+		for _, v := range t.Spec.Values {
+			if f, err := strconv.ParseFloat(v.Value, 64); err == nil {
+				o.Values[v.Name] = f
+			}
+		}
+		observations = append(observations, o)
+	}
+
+	return analysis.SuggestBounds(dimensions, objectives, observations)
+}
+
+// UpdateBoundsCondition records bounds suggestions on a completed experiment, the analysis is only performed once;
+// returns true only if changes were necessary
+func UpdateBoundsCondition(exp *redskyv1beta1.Experiment, trialList *redskyv1beta1.TrialList, probeTime *metav1.Time) bool {
+	if exp.Status.Phase != PhaseCompleted || GetCondition(&exp.Status, redskyv1beta1.ExperimentBoundsLimited) != nil {
+		return false
+	}
+
+	suggestions := SuggestBounds(exp, trialList)
+	if len(suggestions) == 0 {
+		ApplyCondition(&exp.Status, redskyv1beta1.ExperimentBoundsLimited, corev1.ConditionFalse, "", "", probeTime)
+		return true
+	}
+
+	msgs := make([]string, 0, len(suggestions))
+	for i := range suggestions {
+		msgs = append(msgs, suggestions[i].String())
+	}
+	ApplyCondition(&exp.Status, redskyv1beta1.ExperimentBoundsLimited, corev1.ConditionTrue, "BestTrialsAtBounds", strings.Join(msgs, "; "), probeTime)
+	return true
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiment
+
+import (
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+)
+
+// ConcurrencyLimit restricts the number of trials that can be active at the same time across all experiments
+type ConcurrencyLimit struct {
+	// MaxTrials is the maximum number of active trials, zero for no limit
+	MaxTrials int
+	// MaxTrialsPerNamespace is the maximum number of active trials in a single namespace, zero for no limit
+	MaxTrialsPerNamespace int
+}
+
+// ActiveTrials returns the number of active trials in each namespace
+func ActiveTrials(trialList *redskyv1beta1.TrialList) map[string]int {
+	active := make(map[string]int)
+	for i := range trialList.Items {
+		t := &trialList.Items[i]
+		if trial.IsActive(t) && !trial.IsAbandoned(t) {
+			active[t.Namespace]++
+		}
+	}
+	return active
+}
+
+// Allow checks to see if a new trial can be started in the specified namespace given the active trial counts
+func (l *ConcurrencyLimit) Allow(active map[string]int, namespace string) bool {
+	if l.MaxTrialsPerNamespace > 0 && active[namespace] >= l.MaxTrialsPerNamespace {
+		return false
+	}
+	if l.MaxTrials > 0 {
+		var total int
+		for _, n := range active {
+			total += n
+		}
+		if total >= l.MaxTrials {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiment
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Converged checks the recently completed trials of an experiment for progress; the experiment is converged when none
+// of the most recent trials improved upon the best value of any metric by more than the tolerance. Note that only
+// trials which have not yet been cleaned up are considered. The returned message describes the best values found.
+func Converged(exp *redskyv1beta1.Experiment, trialList *redskyv1beta1.TrialList) (bool, string) {
+	c := exp.Spec.Convergence
+	if c == nil || c.Trials <= 0 || len(exp.Spec.Metrics) == 0 {
+		return false, ""
+	}
+	tolerance := float64(c.Tolerance.MilliValue()) / 1000
+
+	// Collect the metric values of the completed trials in the order they completed
+	type observation struct {
+		time   metav1.Time
+		values []float64
+	}
+	var observations []observation
+	for i := range trialList.Items {
+		t := &trialList.Items[i]
+		if !trial.CheckCondition(&t.Status, redskyv1beta1.TrialComplete, corev1.ConditionTrue) {
+			continue
+		}
+		o := observation{time: t.CreationTimestamp}
+		if t.Status.CompletionTime != nil {
+			o.time = *t.Status.CompletionTime
+		}
+		if o.values = metricValues(exp, t); o.values != nil {
+			observations = append(observations, o)
+		}
+	}
+	sort.SliceStable(observations, func(i, j int) bool { return observations[i].time.Before(&observations[j].time) })
+
+	// There must be a history to compare the recent trials against
+	n := len(observations) - int(c.Trials)
+	if n <= 0 {
+		return false, ""
+	}
+
+	var msgs []string
+	for m, metric := range exp.Spec.Metrics {
+		best := observations[0].values[m]
+		for _, o := range observations[1:n] {
+			best = better(metric.Minimize, best, o.values[m])
+		}
+
+		for _, o := range observations[n:] {
+			if improvement(metric.Minimize, best, o.values[m]) > tolerance {
+				return false, ""
+			}
+			best = better(metric.Minimize, best, o.values[m])
+		}
+
+		msgs = append(msgs, fmt.Sprintf("%s=%s", metric.Name, strconv.FormatFloat(best, 'f', -1, 64)))
+	}
+
+	return true, fmt.Sprintf("No improvement over the last %d trials, best values: %s", c.Trials, strings.Join(msgs, ", "))
+}
+
+// UpdateConvergedCondition records the convergence of a running experiment, if requested the experiment is completed
+// once it converges (this should be checked before the status is updated so the phase reflects the completion);
+// returns true only if changes were necessary
+func UpdateConvergedCondition(exp *redskyv1beta1.Experiment, trialList *redskyv1beta1.TrialList, probeTime *metav1.Time) bool {
+	if exp.Spec.Convergence == nil || exp.Status.Phase == PhaseCompleted || !exp.GetDeletionTimestamp().IsZero() {
+		return false
+	}
+
+	converged, msg := Converged(exp, trialList)
+	if !converged {
+		if !CheckCondition(&exp.Status, redskyv1beta1.ExperimentConverged, corev1.ConditionTrue) {
+			return false
+		}
+		ApplyCondition(&exp.Status, redskyv1beta1.ExperimentConverged, corev1.ConditionFalse, "", "", probeTime)
+		return true
+	}
+
+	if CheckCondition(&exp.Status, redskyv1beta1.ExperimentConverged, corev1.ConditionTrue) {
+		return false
+	}
+	ApplyCondition(&exp.Status, redskyv1beta1.ExperimentConverged, corev1.ConditionTrue, "NoImprovement", msg, probeTime)
+
+	// Stop the experiment the same way an exhausted budget does
+	if exp.Spec.Convergence.Complete {
+		exp.SetReplicas(0)
+		delete(exp.GetAnnotations(), redskyv1beta1.AnnotationNextTrialURL)
+	}
+	return true
+}
+
+// metricValues returns the values of a trial in the order of the experiment metrics, nil is returned if any are missing
+func metricValues(exp *redskyv1beta1.Experiment, t *redskyv1beta1.Trial) []float64 {
+	values := make([]float64, len(exp.Spec.Metrics))
+	for i, m := range exp.Spec.Metrics {
+		found := false
+		for _, v := range t.Spec.Values {
+			if v.Name != m.Name {
+				continue
+			}
+			f, err := strconv.ParseFloat(v.Value, 64)
+			if err != nil {
+				return nil
+			}
+			values[i], found = f, true
+		}
+		if !found {
+			return nil
+		}
+	}
+	return values
+}
+
+// better returns the better of two metric values
+func better(minimize bool, a, b float64) float64 {
+	if minimize {
+		return math.Min(a, b)
+	}
+	return math.Max(a, b)
+}
+
+// improvement returns the relative improvement of a value over the best value, negative if the value is worse
+func improvement(minimize bool, best, value float64) float64 {
+	delta := value - best
+	if minimize {
+		delta = best - value
+	}
+	if best == 0 {
+		return delta
+	}
+	return delta / math.Abs(best)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiment
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConverged(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	exp := &redskyv1beta1.Experiment{}
+	exp.Spec.Metrics = []redskyv1beta1.Metric{{Name: "cost", Minimize: true}}
+
+	// Build a list of completed trials with the supplied costs, in order of completion
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	trials := func(costs ...string) *redskyv1beta1.TrialList {
+		l := &redskyv1beta1.TrialList{}
+		for i := len(costs) - 1; i >= 0; i-- {
+			completionTime := metav1.NewTime(start.Add(time.Duration(i) * time.Minute))
+			t := redskyv1beta1.Trial{}
+			t.Name = fmt.Sprintf("test-%03d", i)
+			t.Spec.Values = []redskyv1beta1.Value{{Name: "cost", Value: costs[i]}}
+			t.Status.CompletionTime = &completionTime
+			t.Status.Conditions = []redskyv1beta1.TrialCondition{{Type: redskyv1beta1.TrialComplete, Status: corev1.ConditionTrue}}
+			l.Items = append(l.Items, t)
+		}
+		return l
+	}
+
+	// Without criteria the experiment never converges
+	converged, _ := Converged(exp, trials("10", "10", "10", "10"))
+	g.Expect(converged).To(BeFalse())
+
+	exp.Spec.Convergence = &redskyv1beta1.ConvergenceCriteria{Trials: 2}
+
+	// There must be more trials then the window
+	converged, _ = Converged(exp, trials("10", "10"))
+	g.Expect(converged).To(BeFalse())
+
+	// The recent trials improved
+	converged, _ = Converged(exp, trials("10", "8", "9", "7"))
+	g.Expect(converged).To(BeFalse())
+
+	// The recent trials did not improve
+	converged, msg := Converged(exp, trials("10", "7", "9", "8"))
+	g.Expect(converged).To(BeTrue())
+	g.Expect(msg).To(Equal("No improvement over the last 2 trials, best values: cost=7"))
+
+	// Small improvements are within the tolerance
+	exp.Spec.Convergence.Tolerance = resource.MustParse("0.05")
+	converged, _ = Converged(exp, trials("10", "9", "8.9", "8.8"))
+	g.Expect(converged).To(BeTrue())
+	converged, _ = Converged(exp, trials("10", "9", "8.9", "8"))
+	g.Expect(converged).To(BeFalse())
+}
+
+func TestUpdateConvergedCondition(t *testing.T) {
+	g := NewGomegaWithT(t)
+	now := metav1.Now()
+	replicas := int32(2)
+
+	exp := &redskyv1beta1.Experiment{}
+	exp.Annotations = map[string]string{}
+	exp.Spec.Replicas = &replicas
+	exp.Spec.Metrics = []redskyv1beta1.Metric{{Name: "throughput"}}
+	exp.Spec.Convergence = &redskyv1beta1.ConvergenceCriteria{Trials: 1, Complete: true}
+
+	trialList := &redskyv1beta1.TrialList{}
+	for _, v := range []string{"100", "90"} {
+		t := redskyv1beta1.Trial{}
+		t.Spec.Values = []redskyv1beta1.Value{{Name: "throughput", Value: v}}
+		t.Status.Conditions = []redskyv1beta1.TrialCondition{{Type: redskyv1beta1.TrialComplete, Status: corev1.ConditionTrue}}
+		trialList.Items = append(trialList.Items, t)
+	}
+
+	// Convergence completes the experiment
+	g.Expect(UpdateConvergedCondition(exp, trialList, &now)).To(BeTrue())
+	g.Expect(CheckCondition(&exp.Status, redskyv1beta1.ExperimentConverged, corev1.ConditionTrue)).To(BeTrue())
+	g.Expect(exp.Replicas()).To(Equal(int32(0)))
+	g.Expect(summarize(exp, 0, len(trialList.Items))).To(Equal(PhaseCompleted))
+
+	// No further changes once converged
+	g.Expect(UpdateConvergedCondition(exp, trialList, &now)).To(BeFalse())
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiment
+
+import (
+	"strconv"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// UpdateResourceHours totals the resource hours and cost recorded on the trials of an experiment with a cost model;
+// returns true if the status changed. Note that only trials which have not yet been deleted are included.
+func UpdateResourceHours(exp *redskyv1beta1.Experiment, trialList *redskyv1beta1.TrialList) bool {
+	if exp.Spec.CostModel == nil {
+		return false
+	}
+
+	var resourceHours corev1.ResourceList
+	var cost string
+	var totalCost float64
+	for i := range trialList.Items {
+		t := &trialList.Items[i]
+		for name, q := range t.Status.ResourceHours {
+			if resourceHours == nil {
+				resourceHours = corev1.ResourceList{}
+			}
+			total := resourceHours[name]
+			total.Add(q)
+			resourceHours[name] = total
+		}
+		if c, err := strconv.ParseFloat(t.Status.Cost, 64); err == nil {
+			totalCost += c
+			cost = trial.FormatCost(totalCost)
+		}
+	}
+
+	if resourceListEqual(exp.Status.ResourceHours, resourceHours) && exp.Status.Cost == cost {
+		return false
+	}
+	exp.Status.ResourceHours = resourceHours
+	exp.Status.Cost = cost
+	return true
+}
+
+func resourceListEqual(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, qa := range a {
+		if qb, ok := b[name]; !ok || qa.Cmp(qb) != 0 {
+			return false
+		}
+	}
+	return true
+}
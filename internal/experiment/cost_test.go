@@ -0,0 +1,54 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiment
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestUpdateResourceHours(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	exp := &redskyv1beta1.Experiment{}
+	trialList := &redskyv1beta1.TrialList{}
+	for _, cost := range []string{"1.25", "2.5", ""} {
+		t := redskyv1beta1.Trial{}
+		t.Status.Cost = cost
+		if cost != "" {
+			t.Status.ResourceHours = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")}
+		}
+		trialList.Items = append(trialList.Items, t)
+	}
+
+	// Without a cost model nothing is accounted for
+	g.Expect(UpdateResourceHours(exp, trialList)).To(BeFalse())
+	g.Expect(exp.Status.ResourceHours).To(BeNil())
+
+	// The trial resource hours and costs are totaled
+	exp.Spec.CostModel = &redskyv1beta1.CostModel{}
+	g.Expect(UpdateResourceHours(exp, trialList)).To(BeTrue())
+	g.Expect(exp.Status.ResourceHours.Cpu().String()).To(Equal("1"))
+	g.Expect(exp.Status.Cost).To(Equal("3.75"))
+
+	// Nothing changes the second time
+	g.Expect(UpdateResourceHours(exp, trialList)).To(BeFalse())
+}
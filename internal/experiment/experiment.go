@@ -20,6 +20,8 @@ import (
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	"github.com/redskyops/redskyops-controller/internal/controller"
 	"github.com/redskyops/redskyops-controller/internal/trial"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 )
 
 const (
@@ -70,6 +72,10 @@ func UpdateStatus(exp *redskyv1beta1.Experiment, trialList *redskyv1beta1.TrialL
 		exp.Status.ActiveTrials = activeTrials
 		dirty = true
 	}
+	if queue := Queue(trialList); !equality.Semantic.DeepEqual(exp.Status.Queue, queue) {
+		exp.Status.Queue = queue
+		dirty = true
+	}
 
 	// If we made a change, record this in the metric gauges
 	if dirty {
@@ -95,6 +101,9 @@ func summarize(exp *redskyv1beta1.Experiment, activeTrials int32, totalTrials in
 		if remote && exp.Annotations[redskyv1beta1.AnnotationNextTrialURL] == "" {
 			return PhaseCompleted
 		}
+		if exp.Spec.Convergence != nil && exp.Spec.Convergence.Complete && CheckCondition(&exp.Status, redskyv1beta1.ExperimentConverged, corev1.ConditionTrue) {
+			return PhaseCompleted
+		}
 		return PhasePaused
 	}
 
@@ -18,9 +18,11 @@ package experiment
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -62,6 +64,68 @@ func TestSummarize(t *testing.T) {
 	g.Expect(summarize(exp, 0, 1)).To(Equal(PhaseIdle))
 }
 
+func TestQueue(t *testing.T) {
+	g := NewGomegaWithT(t)
+	now := metav1.Now()
+	earlier := metav1.NewTime(now.Add(-time.Minute))
+
+	trialList := &redskyv1beta1.TrialList{Items: []redskyv1beta1.Trial{
+		{ObjectMeta: metav1.ObjectMeta{Name: "running", CreationTimestamp: earlier}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "second", CreationTimestamp: now}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "first", CreationTimestamp: earlier}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "urgent", CreationTimestamp: now, Annotations: map[string]string{redskyv1beta1.AnnotationTrialPriority: "10"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "failed", CreationTimestamp: earlier}},
+	}}
+	trialList.Items[0].Status.Conditions = []redskyv1beta1.TrialCondition{{Type: redskyv1beta1.TrialPatched, Status: corev1.ConditionTrue}}
+	trialList.Items[4].Status.Conditions = []redskyv1beta1.TrialCondition{{Type: redskyv1beta1.TrialFailed, Status: corev1.ConditionTrue}}
+
+	var names []string
+	for _, qt := range Queue(trialList) {
+		names = append(names, qt.Name)
+	}
+	g.Expect(names).To(Equal([]string{"urgent", "first", "second"}))
+}
+
+func TestPreempting(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	exp := &redskyv1beta1.Experiment{ObjectMeta: metav1.ObjectMeta{Name: "low", Namespace: "default", UID: "1"}}
+	exp.Spec.Priority = 1
+	expList := &redskyv1beta1.ExperimentList{Items: []redskyv1beta1.Experiment{*exp}}
+
+	// Nothing else is competing for capacity
+	g.Expect(Preempting(exp, expList)).To(BeEmpty())
+
+	// Higher priority experiments only preempt when they are waiting
+	high := redskyv1beta1.Experiment{ObjectMeta: metav1.ObjectMeta{Name: "high", Namespace: "default", UID: "2"}}
+	high.Spec.Priority = 10
+	expList.Items = append(expList.Items, high)
+	g.Expect(Preempting(exp, expList)).To(BeEmpty())
+	expList.Items[1].Status.Waiting = true
+	g.Expect(Preempting(exp, expList)).To(Equal("default/high"))
+
+	// Waiting experiments of equal priority do not preempt
+	exp.Spec.Priority = 10
+	g.Expect(Preempting(exp, expList)).To(BeEmpty())
+
+	// Preemptions are only counted on change
+	g.Expect(SetPreemptedBy(exp, "default/high")).To(BeTrue())
+	g.Expect(SetPreemptedBy(exp, "default/high")).To(BeFalse())
+	g.Expect(SetPreemptedBy(exp, "")).To(BeTrue())
+	g.Expect(exp.Status.Preemptions).To(Equal(int32(1)))
+}
+
+func TestConcurrencyLimit_Allow(t *testing.T) {
+	g := NewGomegaWithT(t)
+	active := map[string]int{"a": 2, "b": 1}
+
+	g.Expect((&ConcurrencyLimit{}).Allow(active, "a")).To(BeTrue())
+	g.Expect((&ConcurrencyLimit{MaxTrials: 3}).Allow(active, "c")).To(BeFalse())
+	g.Expect((&ConcurrencyLimit{MaxTrials: 4}).Allow(active, "c")).To(BeTrue())
+	g.Expect((&ConcurrencyLimit{MaxTrialsPerNamespace: 2}).Allow(active, "a")).To(BeFalse())
+	g.Expect((&ConcurrencyLimit{MaxTrialsPerNamespace: 2}).Allow(active, "b")).To(BeTrue())
+}
+
 // Explicitly sets the state of the fields consider when computing the phase
 func setupExperiment(exp *redskyv1beta1.Experiment, replicas *int32, experimentURL, nextTrialURL string, deletionTimestamp *metav1.Time) {
 	exp.Spec.Replicas = replicas
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiment
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	redskyapi "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// forecastWindow is the number of recently finished trials used to compute the average trial duration
+const forecastWindow = 10
+
+// Forecast computes the rolling average duration of the most recently finished trials and, if the experiment has a
+// trial budget, the projected time the budget will be exhausted. The projection is anchored to the completion time of
+// the most recent trial so that it only changes as trials finish. Note that only trials which have not yet been
+// cleaned up are considered, the remaining budget may be overestimated once finished trials are deleted.
+func Forecast(exp *redskyv1beta1.Experiment, trialList *redskyv1beta1.TrialList) (*metav1.Duration, *metav1.Time) {
+	// Collect the creation and completion times of the finished trials
+	type run struct{ created, completed time.Time }
+	var runs []run
+	for i := range trialList.Items {
+		t := &trialList.Items[i]
+		if !trial.IsFinished(t) || t.Status.CompletionTime == nil || t.Status.CompletionTime.Before(&t.CreationTimestamp) {
+			continue
+		}
+		runs = append(runs, run{created: t.CreationTimestamp.Time, completed: t.Status.CompletionTime.Time})
+	}
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].completed.Before(runs[j].completed) })
+
+	// Average the duration of the most recent trials
+	recent := runs
+	if len(recent) > forecastWindow {
+		recent = recent[len(recent)-forecastWindow:]
+	}
+	var total time.Duration
+	for _, r := range recent {
+		total += r.completed.Sub(r.created)
+	}
+	avg := (total / time.Duration(len(recent))).Round(time.Second)
+	average := &metav1.Duration{Duration: avg}
+
+	// Project the remaining trials assuming they run concurrently in batches of the replica count
+	budget := experimentBudget(exp)
+	remaining := budget - int64(len(runs))
+	if budget <= 0 || remaining <= 0 {
+		return average, nil
+	}
+	replicas := int64(exp.Replicas())
+	if replicas <= 0 {
+		return average, nil
+	}
+	batches := (remaining + replicas - 1) / replicas
+	eta := metav1.NewTime(runs[len(runs)-1].completed.Add(time.Duration(batches) * avg))
+	return average, &eta
+}
+
+// UpdateForecast records the average trial duration and estimated completion time of a running experiment; returns
+// true only if changes were necessary
+func UpdateForecast(exp *redskyv1beta1.Experiment, trialList *redskyv1beta1.TrialList) bool {
+	average, eta := Forecast(exp, trialList)
+	if exp.Status.Phase == PhaseCompleted || !exp.GetDeletionTimestamp().IsZero() {
+		eta = nil
+	}
+
+	var dirty bool
+	if !durationEqual(exp.Status.AverageTrialDuration, average) {
+		exp.Status.AverageTrialDuration = average
+		dirty = true
+	}
+	if !timeEqual(exp.Status.EstimatedCompletionTime, eta) {
+		exp.Status.EstimatedCompletionTime = eta
+		dirty = true
+	}
+	return dirty
+}
+
+// experimentBudget returns the total number of trials the experiment is expected to run, zero if there is no budget
+func experimentBudget(exp *redskyv1beta1.Experiment) int64 {
+	for _, o := range exp.Spec.Optimization {
+		if o.Name == redskyapi.OptimizationExperimentBudget {
+			if b, err := strconv.ParseInt(o.Value, 10, 64); err == nil {
+				return b
+			}
+		}
+	}
+	return 0
+}
+
+func durationEqual(a, b *metav1.Duration) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Duration == b.Duration
+}
+
+func timeEqual(a, b *metav1.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(b)
+}
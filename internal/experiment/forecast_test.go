@@ -0,0 +1,75 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiment
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestForecast(t *testing.T) {
+	g := NewGomegaWithT(t)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	replicas := int32(2)
+
+	exp := &redskyv1beta1.Experiment{}
+	exp.Spec.Replicas = &replicas
+
+	// Two finished trials taking 10 and 20 minutes, and one still running
+	trialList := &redskyv1beta1.TrialList{}
+	for i, d := range []time.Duration{10 * time.Minute, 20 * time.Minute, 0} {
+		t := redskyv1beta1.Trial{}
+		t.CreationTimestamp = metav1.NewTime(start.Add(time.Duration(i) * time.Hour))
+		if d > 0 {
+			completionTime := metav1.NewTime(t.CreationTimestamp.Add(d))
+			t.Status.CompletionTime = &completionTime
+			t.Status.Conditions = []redskyv1beta1.TrialCondition{{Type: redskyv1beta1.TrialComplete, Status: corev1.ConditionTrue}}
+		}
+		trialList.Items = append(trialList.Items, t)
+	}
+
+	// Without a budget there is only an average
+	average, eta := Forecast(exp, trialList)
+	g.Expect(average).To(Equal(&metav1.Duration{Duration: 15 * time.Minute}))
+	g.Expect(eta).To(BeNil())
+
+	// Seven remaining trials run in four batches after the last completion
+	exp.Spec.Optimization = []redskyv1beta1.Optimization{{Name: "experimentBudget", Value: "9"}}
+	average, eta = Forecast(exp, trialList)
+	g.Expect(average.Duration).To(Equal(15 * time.Minute))
+	g.Expect(eta.Time).To(Equal(start.Add(time.Hour + 20*time.Minute + 60*time.Minute)))
+
+	// The status is only changed once
+	g.Expect(UpdateForecast(exp, trialList)).To(BeTrue())
+	g.Expect(UpdateForecast(exp, trialList)).To(BeFalse())
+
+	// Completed experiments do not have an estimated completion time
+	exp.Status.Phase = PhaseCompleted
+	g.Expect(UpdateForecast(exp, trialList)).To(BeTrue())
+	g.Expect(exp.Status.EstimatedCompletionTime).To(BeNil())
+	g.Expect(exp.Status.AverageTrialDuration).NotTo(BeNil())
+
+	// Nothing to forecast without finished trials
+	average, eta = Forecast(exp, &redskyv1beta1.TrialList{})
+	g.Expect(average).To(BeNil())
+	g.Expect(eta).To(BeNil())
+}
@@ -20,11 +20,13 @@ import (
 	"context"
 
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/featuregate"
 	"github.com/redskyops/redskyops-controller/internal/trial"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -75,7 +77,7 @@ func NextTrialNamespace(ctx context.Context, c client.Client, exp *redskyv1beta1
 	}
 
 	// If we could not find a namespace, we may be able to create it
-	if exp.Spec.NamespaceTemplate != nil {
+	if exp.Spec.NamespaceTemplate != nil && featuregate.Enabled(featuregate.NamespacePerTrial) {
 		return createNamespaceFromTemplate(ctx, c, exp)
 	}
 
@@ -141,6 +143,30 @@ func createNamespaceFromTemplate(ctx context.Context, c client.Client, exp *reds
 	return n.Name, nil
 }
 
+// SetupObjects returns the service account, role and bindings needed to run the setup tasks of an experiment in its
+// own namespace; experiments which select or create trial namespaces do not need any additional objects
+func SetupObjects(exp *redskyv1beta1.Experiment) []runtime.Object {
+	if len(exp.Spec.TrialTemplate.Spec.SetupTasks) == 0 {
+		return nil
+	}
+	if exp.Spec.NamespaceSelector != nil || exp.Spec.NamespaceTemplate != nil {
+		return nil
+	}
+
+	ts := createTrialNamespace(exp, exp.Namespace)
+	var objs []runtime.Object
+	if ts.ServiceAccount != nil {
+		objs = append(objs, ts.ServiceAccount)
+	}
+	if ts.Role != nil {
+		objs = append(objs, ts.Role)
+	}
+	for i := range ts.RoleBindings {
+		objs = append(objs, &ts.RoleBindings[i])
+	}
+	return objs
+}
+
 // trialNamespace represents the supporting resources for a trial namespace
 type trialNamespace struct {
 	ServiceAccount *corev1.ServiceAccount
@@ -0,0 +1,68 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiment
+
+import (
+	"sort"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Preempting returns the name of a higher priority experiment that is waiting for capacity, the supplied experiment
+// should not start new trials while such an experiment exists; returns an empty string if there is nothing to yield to
+func Preempting(exp *redskyv1beta1.Experiment, expList *redskyv1beta1.ExperimentList) string {
+	var waiting []*redskyv1beta1.Experiment
+	for i := range expList.Items {
+		e := &expList.Items[i]
+		if e.UID == exp.UID || e.Spec.Priority <= exp.Spec.Priority {
+			continue
+		}
+		if !e.Status.Waiting || e.Replicas() == 0 || !e.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		waiting = append(waiting, e)
+	}
+	if len(waiting) == 0 {
+		return ""
+	}
+
+	// Report the highest priority experiment we are yielding to
+	sort.Slice(waiting, func(i, j int) bool { return waiting[i].Spec.Priority > waiting[j].Spec.Priority })
+	return types.NamespacedName{Namespace: waiting[0].Namespace, Name: waiting[0].Name}.String()
+}
+
+// SetPreemptedBy records the experiment being yielded to, returns true only if the status was changed
+func SetPreemptedBy(exp *redskyv1beta1.Experiment, preemptedBy string) bool {
+	if exp.Status.PreemptedBy == preemptedBy {
+		return false
+	}
+	if preemptedBy != "" {
+		exp.Status.Preemptions++
+	}
+	exp.Status.PreemptedBy = preemptedBy
+	return true
+}
+
+// SetWaiting records if the experiment is waiting for capacity, returns true only if the status was changed
+func SetWaiting(exp *redskyv1beta1.Experiment, waiting bool) bool {
+	if exp.Status.Waiting == waiting {
+		return false
+	}
+	exp.Status.Waiting = waiting
+	return true
+}
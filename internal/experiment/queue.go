@@ -0,0 +1,46 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiment
+
+import (
+	"sort"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+)
+
+// Queue returns the pending trials from the supplied list in the order they should be started
+func Queue(trialList *redskyv1beta1.TrialList) []redskyv1beta1.QueuedTrial {
+	var pending []*redskyv1beta1.Trial
+	for i := range trialList.Items {
+		if trial.IsPending(&trialList.Items[i]) {
+			pending = append(pending, &trialList.Items[i])
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return trial.Precedes(pending[i], pending[j]) })
+
+	var queue []redskyv1beta1.QueuedTrial
+	for _, t := range pending {
+		queue = append(queue, redskyv1beta1.QueuedTrial{
+			Name:        t.Name,
+			Namespace:   t.Namespace,
+			Priority:    trial.Priority(t),
+			Assignments: append([]redskyv1beta1.Assignment(nil), t.Spec.Assignments...),
+		})
+	}
+	return queue
+}
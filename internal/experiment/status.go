@@ -0,0 +1,74 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiment
+
+import (
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ApplyCondition updates the status of an existing condition or adds it if it does not exist
+func ApplyCondition(status *redskyv1beta1.ExperimentStatus, conditionType redskyv1beta1.ExperimentConditionType, conditionStatus corev1.ConditionStatus, reason, message string, time *metav1.Time) {
+	// Make sure we have a time
+	if time == nil {
+		now := metav1.Now()
+		time = &now
+	}
+
+	// Update an existing condition
+	if c := GetCondition(status, conditionType); c != nil {
+		if c.Status != conditionStatus {
+			c.Status = conditionStatus
+			c.LastTransitionTime = *time
+		}
+		c.LastProbeTime = *time
+		c.Reason = reason
+		c.Message = message
+		return
+	}
+
+	// Condition does not exist
+	status.Conditions = append(status.Conditions, redskyv1beta1.ExperimentCondition{
+		Type:               conditionType,
+		Status:             conditionStatus,
+		Reason:             reason,
+		Message:            message,
+		LastProbeTime:      *time,
+		LastTransitionTime: *time,
+	})
+}
+
+// GetCondition returns the condition of the specified type, or nil if the condition does not exist
+func GetCondition(status *redskyv1beta1.ExperimentStatus, conditionType redskyv1beta1.ExperimentConditionType) *redskyv1beta1.ExperimentCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == conditionType {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// CheckCondition checks to see if a condition has a specific status
+func CheckCondition(status *redskyv1beta1.ExperimentStatus, conditionType redskyv1beta1.ExperimentConditionType, conditionStatus corev1.ConditionStatus) bool {
+	if c := GetCondition(status, conditionType); c != nil {
+		return c.Status == conditionStatus
+	}
+
+	// If the condition we are looking for *is* unknown, then we did "find" it
+	return conditionStatus == corev1.ConditionUnknown
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package experiment
 
 import (
+	"strings"
+
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -50,6 +52,36 @@ func PopulateTrialFromTemplate(exp *redskyv1beta1.Experiment, t *redskyv1beta1.T
 		Namespace: exp.Namespace,
 	}
 
+	// Record which parameters have values that should not be displayed
+	var sensitive []string
+	for i := range exp.Spec.Parameters {
+		if exp.Spec.Parameters[i].Sensitive {
+			sensitive = append(sensitive, exp.Spec.Parameters[i].Name)
+		}
+	}
+	if len(sensitive) > 0 {
+		t.Annotations[redskyv1beta1.AnnotationSensitiveParameters] = strings.Join(sensitive, ",")
+	}
+
+	// Record how long pods can wait for new nodes
+	if d, ok := exp.GetAnnotations()[redskyv1beta1.AnnotationScaleUpTimeout]; ok {
+		if _, ok := t.Annotations[redskyv1beta1.AnnotationScaleUpTimeout]; !ok {
+			t.Annotations[redskyv1beta1.AnnotationScaleUpTimeout] = d
+		}
+	}
+
+	// Record how service mesh sidecars should be handled by the trial run job
+	if p, ok := exp.GetAnnotations()[redskyv1beta1.AnnotationSidecarPolicy]; ok {
+		if _, ok := t.Annotations[redskyv1beta1.AnnotationSidecarPolicy]; !ok {
+			t.Annotations[redskyv1beta1.AnnotationSidecarPolicy] = p
+		}
+	}
+
+	// The pod template overlay applies to every job created for the experiment
+	if t.Spec.PodTemplateOverlay == "" {
+		t.Spec.PodTemplateOverlay = exp.Spec.PodTemplateOverlay
+	}
+
 	// Default trial name is the experiment name with a random suffix
 	if t.Name == "" && t.GenerateName == "" {
 		t.GenerateName = exp.Name + "-"
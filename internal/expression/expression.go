@@ -0,0 +1,111 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package expression evaluates Common Expression Language (CEL) expressions. A single evaluator is shared for
+// readiness checks, metric guards and derived metrics so users only need to learn one expression syntax.
+package expression
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+)
+
+// EvaluationError is an error produced while evaluating a valid expression, for example when the expression references
+// a key which is not present in the supplied variables
+type EvaluationError struct {
+	err error
+}
+
+// Error returns the message of the underlying evaluation error
+func (e *EvaluationError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying evaluation error
+func (e *EvaluationError) Unwrap() error {
+	return e.err
+}
+
+// IsEvaluationError checks to see if the error (or any error it wraps) occurred while evaluating an otherwise valid
+// expression; other errors indicate that the expression itself is invalid
+func IsEvaluationError(err error) bool {
+	var eerr *EvaluationError
+	return errors.As(err, &eerr)
+}
+
+// Evaluate compiles and evaluates the expression, the keys of the supplied variables are declared as dynamically
+// typed identifiers available to the expression
+func Evaluate(expr string, vars map[string]interface{}) (interface{}, error) {
+	opts := make([]cel.EnvOption, 0, len(vars))
+	for name := range vars {
+		opts = append(opts, cel.Declarations(decls.NewVar(name, decls.Dyn)))
+	}
+
+	env, err := cel.NewEnv(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ast, iss := env.Compile(expr)
+	if iss != nil && iss.Err() != nil {
+		return nil, iss.Err()
+	}
+
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, err
+	}
+
+	out, _, err := prg.Eval(vars)
+	if err != nil {
+		return nil, &EvaluationError{err: err}
+	}
+	return out.Value(), nil
+}
+
+// EvaluateBool evaluates an expression which must produce a boolean result
+func EvaluateBool(expr string, vars map[string]interface{}) (bool, error) {
+	v, err := Evaluate(expr, vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression must evaluate to a bool, got %T", v)
+	}
+	return b, nil
+}
+
+// EvaluateFloat evaluates an expression which must produce a numeric result
+func EvaluateFloat(expr string, vars map[string]interface{}) (float64, error) {
+	v, err := Evaluate(expr, vars)
+	if err != nil {
+		return 0, err
+	}
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int64:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	default:
+		return 0, fmt.Errorf("expression must evaluate to a number, got %T", v)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expression
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateBool(t *testing.T) {
+	self := map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase":         "Running",
+			"readyReplicas": int64(3),
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Available", "status": "True"},
+			},
+		},
+		"spec": map[string]interface{}{"replicas": int64(3)},
+	}
+
+	cases := []struct {
+		desc     string
+		expr     string
+		expected bool
+		err      bool
+	}{
+		{
+			desc:     "string comparison",
+			expr:     `self.status.phase == "Running"`,
+			expected: true,
+		},
+		{
+			desc:     "field comparison",
+			expr:     `self.status.readyReplicas >= self.spec.replicas`,
+			expected: true,
+		},
+		{
+			desc:     "macro",
+			expr:     `self.status.conditions.exists(c, c.type == "Available" && c.status == "True")`,
+			expected: true,
+		},
+		{
+			desc:     "missing field",
+			expr:     `has(self.status.observedGeneration)`,
+			expected: false,
+		},
+		{
+			desc: "not a bool",
+			expr: `self.status.phase`,
+			err:  true,
+		},
+		{
+			desc: "syntax error",
+			expr: `self.status.phase ==`,
+			err:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			actual, err := EvaluateBool(c.expr, map[string]interface{}{"self": self})
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEvaluateFloat(t *testing.T) {
+	metrics := map[string]interface{}{
+		"cost":     float64(12.5),
+		"requests": float64(500),
+	}
+
+	cases := []struct {
+		desc     string
+		expr     string
+		expected float64
+		err      bool
+	}{
+		{
+			desc:     "ratio",
+			expr:     `metrics.cost / metrics.requests`,
+			expected: 0.025,
+		},
+		{
+			desc:     "integer",
+			expr:     `1 + 2`,
+			expected: 3,
+		},
+		{
+			desc: "unknown metric",
+			expr: `metrics.latency * 2.0`,
+			err:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			actual, err := EvaluateFloat(c.expr, map[string]interface{}{"metrics": metrics})
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.InDelta(t, c.expected, actual, 1e-9)
+			}
+		})
+	}
+}
+
+func TestIsEvaluationError(t *testing.T) {
+	vars := map[string]interface{}{"self": map[string]interface{}{}}
+
+	_, err := EvaluateBool(`self.status.phase == "Running"`, vars)
+	assert.True(t, IsEvaluationError(err), "missing key")
+
+	_, err = EvaluateBool(`self.status.phase ==`, vars)
+	assert.False(t, IsEvaluationError(err), "syntax error")
+
+	_, err = EvaluateBool(`other.status.phase == "Running"`, vars)
+	assert.False(t, IsEvaluationError(err), "undeclared reference")
+
+	assert.False(t, IsEvaluationError(nil))
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package featuregate allows experimental controller features to be enabled or disabled selectively.
+package featuregate
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Feature is the name of a feature that can be toggled
+type Feature string
+
+// Stage indicates the maturity of a feature
+type Stage string
+
+const (
+	// Alpha features are disabled by default and may change or be removed without notice
+	Alpha Stage = "ALPHA"
+	// Beta features are enabled by default but can still be disabled
+	Beta Stage = "BETA"
+)
+
+const (
+	// NamespacePerTrial allows experiments to create a new namespace for trials using a namespace template
+	NamespacePerTrial Feature = "NamespacePerTrial"
+	// Webhooks allows the controller to provision its own webhook certificates
+	Webhooks Feature = "Webhooks"
+)
+
+// Spec describes a feature
+type Spec struct {
+	// Default is the enablement of the feature when it is not explicitly configured
+	Default bool
+	// Stage is the maturity of the feature
+	Stage Stage
+}
+
+// knownFeatures is the registry of all features that can be toggled
+var knownFeatures = map[Feature]Spec{
+	NamespacePerTrial: {Default: true, Stage: Beta},
+	Webhooks:          {Default: true, Stage: Beta},
+}
+
+// Default is the feature gate used by the controller
+var Default = &Gate{}
+
+// Enabled checks if a feature is enabled on the default feature gate
+func Enabled(f Feature) bool {
+	return Default.Enabled(f)
+}
+
+// Gate tracks the enablement of the known features, the zero value uses the defaults for every feature
+type Gate struct {
+	mu      sync.RWMutex
+	enabled map[Feature]bool
+}
+
+// Set parses a comma-delimited list of "Feature=bool" pairs, for use as a flag value
+func (g *Gate) Set(value string) error {
+	enabled := make(map[Feature]bool)
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		kv := strings.SplitN(s, "=", 2)
+		f := Feature(strings.TrimSpace(kv[0]))
+		if _, ok := knownFeatures[f]; !ok {
+			return fmt.Errorf("unrecognized feature gate: %s", f)
+		}
+		if len(kv) != 2 {
+			return fmt.Errorf("missing bool value for %s", f)
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return fmt.Errorf("invalid value of %s=%s, err: %v", f, kv[1], err)
+		}
+		enabled[f] = b
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.enabled == nil {
+		g.enabled = make(map[Feature]bool, len(enabled))
+	}
+	for f, b := range enabled {
+		g.enabled[f] = b
+	}
+	return nil
+}
+
+// String returns the explicitly configured features as a comma-delimited list of "Feature=bool" pairs
+func (g *Gate) String() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	pairs := make([]string, 0, len(g.enabled))
+	for f, b := range g.enabled {
+		pairs = append(pairs, fmt.Sprintf("%s=%t", f, b))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Enabled checks if a feature is enabled
+func (g *Gate) Enabled(f Feature) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if b, ok := g.enabled[f]; ok {
+		return b
+	}
+	return knownFeatures[f].Default
+}
+
+// Status returns the enablement of every known feature
+func (g *Gate) Status() map[Feature]bool {
+	status := make(map[Feature]bool, len(knownFeatures))
+	for f := range knownFeatures {
+		status[f] = g.Enabled(f)
+	}
+	return status
+}
+
+// Usage returns a description of the known features suitable for flag help
+func Usage() string {
+	lines := make([]string, 0, len(knownFeatures))
+	for f, spec := range knownFeatures {
+		lines = append(lines, fmt.Sprintf("%s=true|false (%s - default=%t)", f, spec.Stage, spec.Default))
+	}
+	sort.Strings(lines)
+	return "A set of key=value pairs that describe feature gates for experimental features. Options are:\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package featuregate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGate_Set(t *testing.T) {
+	cases := []struct {
+		desc     string
+		value    string
+		expected map[Feature]bool
+		err      string
+	}{
+		{
+			desc:  "defaults",
+			value: "",
+			expected: map[Feature]bool{
+				NamespacePerTrial: true,
+				Webhooks:          true,
+			},
+		},
+		{
+			desc:  "disable",
+			value: "Webhooks=false",
+			expected: map[Feature]bool{
+				NamespacePerTrial: true,
+				Webhooks:          false,
+			},
+		},
+		{
+			desc:  "whitespace",
+			value: " NamespacePerTrial = false , Webhooks=true ",
+			expected: map[Feature]bool{
+				NamespacePerTrial: false,
+				Webhooks:          true,
+			},
+		},
+		{
+			desc:  "unknown",
+			value: "Bogus=true",
+			err:   "unrecognized feature gate: Bogus",
+		},
+		{
+			desc:  "missing value",
+			value: "Webhooks",
+			err:   "missing bool value for Webhooks",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			g := &Gate{}
+			err := g.Set(c.value)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, g.Status())
+			}
+		})
+	}
+}
+
+func TestGate_String(t *testing.T) {
+	g := &Gate{}
+	assert.Equal(t, "", g.String())
+	assert.NoError(t, g.Set("Webhooks=false,NamespacePerTrial=true"))
+	assert.Equal(t, "NamespacePerTrial=true,Webhooks=false", g.String())
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hmacauth signs HTTP requests using a shared secret as an alternative to OAuth 2.0 bearer tokens.
+package hmacauth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// Algorithm is the authorization scheme used for signed requests
+	Algorithm = "HMAC-SHA256"
+	// DateHeader is the header containing the time at which the request was signed
+	DateHeader = "X-Redsky-Date"
+	// ContentHashHeader is the header containing the hex encoded SHA-256 hash of the request body
+	ContentHashHeader = "X-Redsky-Content-Sha256"
+	// dateFormat is the format of the signing time
+	dateFormat = "20060102T150405Z"
+)
+
+// Transport is an HTTP transport that signs each request
+type Transport struct {
+	// KeyID identifies the shared secret to the server
+	KeyID string
+	// Secret is the shared secret used to sign requests
+	Secret []byte
+	// Base is the transport used to make the signed request, uses the default transport if nil
+	Base http.RoundTripper
+	// Now returns the current time, uses the system time if nil
+	Now func() time.Time
+}
+
+// RoundTrip signs a copy of the request before passing it to the base transport
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	now := time.Now
+	if t.Now != nil {
+		now = t.Now
+	}
+
+	// The request must not be modified, clone it before signing
+	req2 := req.Clone(req.Context())
+	if err := Sign(req2, t.KeyID, t.Secret, now()); err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+	return t.base().RoundTrip(req2)
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// Sign adds the signature headers to the supplied request, the body of the request is buffered to compute the content hash
+func Sign(req *http.Request, keyID string, secret []byte, now time.Time) error {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	contentHash := sha256.Sum256(body)
+	req.Header.Set(DateHeader, now.UTC().Format(dateFormat))
+	req.Header.Set(ContentHashHeader, hex.EncodeToString(contentHash[:]))
+
+	signature := Signature(req, secret)
+	req.Header.Set("Authorization", fmt.Sprintf("%s KeyId=%s, Signature=%s", Algorithm, keyID, signature))
+	return nil
+}
+
+// Signature computes the hex encoded signature of a request which already has the date and content hash headers
+func Signature(req *http.Request, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(stringToSign(req)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// stringToSign returns the canonical representation of the request that is signed
+func stringToSign(req *http.Request) string {
+	return strings.Join([]string{
+		req.Method,
+		req.URL.Host,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		req.Header.Get(DateHeader),
+		req.Header.Get(ContentHashHeader),
+	}, "\n")
+}
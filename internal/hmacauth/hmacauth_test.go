@@ -0,0 +1,76 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hmacauth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	secret := []byte("s3cr3t")
+	now := time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		desc   string
+		method string
+		body   string
+	}{
+		{
+			desc:   "get",
+			method: http.MethodGet,
+		},
+		{
+			desc:   "post",
+			method: http.MethodPost,
+			body:   `{"name":"test"}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.Equal(t, c.body, string(body))
+				assert.Equal(t, "20200401T120000Z", r.Header.Get(DateHeader))
+
+				// The server sees the path only, restore the host to verify the signature
+				r.URL.Host = r.Host
+				expected := Algorithm + " KeyId=test, Signature=" + Signature(r, secret)
+				assert.Equal(t, expected, r.Header.Get("Authorization"))
+			}))
+			defer srv.Close()
+
+			client := &http.Client{Transport: &Transport{KeyID: "test", Secret: secret, Now: func() time.Time { return now }}}
+			req, err := http.NewRequest(c.method, srv.URL+"/experiments/?limit=10", strings.NewReader(c.body))
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+
+			// The original request must not be modified
+			assert.Empty(t, req.Header.Get("Authorization"))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metric
+
+import (
+	"strconv"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/expression"
+)
+
+func captureCELMetric(m *redskyv1beta1.Metric, t *redskyv1beta1.Trial) (float64, float64, error) {
+	vars := map[string]interface{}{
+		"metrics": collectedValues(t),
+		"values":  assignments(t),
+	}
+
+	value, err := expression.EvaluateFloat(m.Query, vars)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var stddev float64
+	if m.ErrorQuery != "" {
+		if stddev, err = expression.EvaluateFloat(m.ErrorQuery, vars); err != nil {
+			return 0, 0, err
+		}
+	}
+	return value, stddev, nil
+}
+
+// CheckGuard evaluates the guard expression of the supplied metric against a collected value, metrics without a guard
+// always pass
+func CheckGuard(m *redskyv1beta1.Metric, t *redskyv1beta1.Trial, value, stddev float64) (bool, error) {
+	if m.Guard == "" {
+		return true, nil
+	}
+
+	return expression.EvaluateBool(m.Guard, map[string]interface{}{
+		"value":   value,
+		"error":   stddev,
+		"metrics": collectedValues(t),
+		"values":  assignments(t),
+	})
+}
+
+// collectedValues returns the metric values which have already been collected for the trial
+func collectedValues(t *redskyv1beta1.Trial) map[string]interface{} {
+	values := make(map[string]interface{}, len(t.Spec.Values))
+	for _, v := range t.Spec.Values {
+		if v.AttemptsRemaining > 0 || v.Value == "" {
+			continue
+		}
+		if fv, err := strconv.ParseFloat(v.Value, 64); err == nil {
+			values[v.Name] = fv
+		}
+	}
+	return values
+}
+
+// assignments returns the parameter assignments of the trial
+func assignments(t *redskyv1beta1.Trial) map[string]interface{} {
+	values := make(map[string]interface{}, len(t.Spec.Assignments))
+	// Resolved values of percentage parameters replace the assigned percentages
+	for _, asm := range [][]redskyv1beta1.Assignment{t.Spec.Assignments, t.Status.ResolvedAssignments} {
+		for _, a := range asm {
+			if v, ok := a.Int64Value(); ok {
+				values[a.Name] = v
+			} else {
+				values[a.Name] = a.Value.StrVal
+			}
+		}
+	}
+	return values
+}
@@ -61,13 +61,16 @@ func captureOneJSONPathMetric(url, name, query string) (float64, float64, error)
 	req.Header.Set("Accept", "application/json")
 	resp, err := httpClient.Do(req.WithContext(context.TODO()))
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, unavailableError(url, query, time.Time{}, err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
 	// Check the response status
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return 0, 0, &CaptureError{Message: fmt.Sprintf("metric endpoint returned %s", resp.Status), Address: url, Query: query, Unavailable: true}
+	}
 	if resp.StatusCode != http.StatusOK {
 		// TODO Should we not ignore this?
 		return 0, 0, nil
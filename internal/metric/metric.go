@@ -18,10 +18,12 @@ package metric
 
 import (
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"time"
 
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	"github.com/redskyops/redskyops-controller/internal/template"
 	corev1 "k8s.io/api/core/v1"
@@ -40,6 +42,8 @@ type CaptureError struct {
 	CompletionTime time.Time
 	// The minimum amount of time until the metric is expected to be available
 	RetryAfter time.Duration
+	// Indicates the metric backend itself is failing (e.g. server errors or timeouts), as opposed to the query
+	Unavailable bool
 }
 
 func (e *CaptureError) Error() string {
@@ -59,21 +63,39 @@ func CaptureMetric(metric *redskyv1beta1.Metric, trial *redskyv1beta1.Trial, tar
 
 	// Capture the value based on the metric type
 	switch metric.Type {
-	case redskyv1beta1.MetricLocal, redskyv1beta1.MetricPods, "":
+	case redskyv1beta1.MetricLocal, redskyv1beta1.MetricPods, redskyv1beta1.MetricHPA, "":
 		// Just parse the query as a float
 		value, err := strconv.ParseFloat(metric.Query, 64)
 		return value, 0, err
 	case redskyv1beta1.MetricPrometheus:
 		return capturePrometheusMetric(metric, target, trial.Status.CompletionTime.Time)
 	case redskyv1beta1.MetricDatadog:
-		return captureDatadogMetric(metric.Scheme, metric.Query, trial.Status.StartTime.Time, trial.Status.CompletionTime.Time)
+		startTime, completionTime := template.MeasurementWindow(metric, trial)
+		return captureDatadogMetric(metric.Scheme, metric.Query, startTime, completionTime)
 	case redskyv1beta1.MetricJSONPath:
 		return captureJSONPathMetric(metric, target)
+	case redskyv1beta1.MetricCEL:
+		return captureCELMetric(metric, trial)
 	default:
 		return 0, 0, fmt.Errorf("unknown metric type: %s", metric.Type)
 	}
 }
 
+// unavailableError wraps errors which indicate the metric backend could not be reached or is failing
+func unavailableError(address, query string, completionTime time.Time, err error) error {
+	var unavailable bool
+	switch e := err.(type) {
+	case *promv1.Error:
+		unavailable = e.Type == promv1.ErrServer || e.Type == promv1.ErrTimeout
+	case net.Error:
+		unavailable = true
+	}
+	if !unavailable {
+		return err
+	}
+	return &CaptureError{Message: err.Error(), Address: address, Query: query, CompletionTime: completionTime, Unavailable: true}
+}
+
 func toURL(target runtime.Object, m *redskyv1beta1.Metric) ([]string, error) {
 	// Allow a specified URL to take precedence over a selector
 	if m.URL != "" {
@@ -27,6 +27,7 @@ import (
 	"testing"
 	"time"
 
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -146,6 +147,15 @@ func TestCaptureMetric(t *testing.T) {
 			},
 			expected: 5,
 		},
+		{
+			desc: "derived cel",
+			metric: &redskyv1beta1.Metric{
+				Name:  "testMetric",
+				Query: "double(size(metrics)) + 10.0 / 2.0",
+				Type:  redskyv1beta1.MetricCEL,
+			},
+			expected: 5,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -179,3 +189,52 @@ func promHttpTestServer() *httptest.Server {
 		return
 	}))
 }
+
+func TestUnavailableError(t *testing.T) {
+	cases := []struct {
+		desc        string
+		err         error
+		unavailable bool
+	}{
+		{
+			desc:        "server error",
+			err:         &promv1.Error{Type: promv1.ErrServer, Msg: "server error: 503"},
+			unavailable: true,
+		},
+		{
+			desc:        "timeout",
+			err:         &promv1.Error{Type: promv1.ErrTimeout, Msg: "timeout"},
+			unavailable: true,
+		},
+		{
+			desc: "bad query",
+			err:  &promv1.Error{Type: promv1.ErrBadData, Msg: "parse error"},
+		},
+		{
+			desc: "client error",
+			err:  &promv1.Error{Type: promv1.ErrClient, Msg: "client error: 404"},
+		},
+		{
+			desc:        "network error",
+			err:         &net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")},
+			unavailable: true,
+		},
+		{
+			desc: "other error",
+			err:  fmt.Errorf("unexpected"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			err := unavailableError("http://prometheus:9090", "up", time.Now(), c.err)
+			merr, ok := err.(*CaptureError)
+			if c.unavailable {
+				require.True(t, ok)
+				assert.True(t, merr.Unavailable)
+				assert.Equal(t, c.err.Error(), merr.Message)
+			} else {
+				assert.Equal(t, c.err, err)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net/http"
 	"strings"
 	"time"
 
@@ -51,7 +52,8 @@ func capturePrometheusMetric(m *redskyv1beta1.Metric, target runtime.Object, com
 func captureOnePrometheusMetric(address, query, errorQuery string, completionTime time.Time) (float64, float64, error) {
 	// Get the Prometheus client based on the metric URL
 	// TODO Cache these by URL
-	c, err := prom.NewClient(prom.Config{Address: address})
+	// NOTE: Use the default transport so the process wide TLS policy is honored
+	c, err := prom.NewClient(prom.Config{Address: address, RoundTripper: http.DefaultTransport})
 	if err != nil {
 		return 0, 0, err
 	}
@@ -60,7 +62,7 @@ func captureOnePrometheusMetric(address, query, errorQuery string, completionTim
 	// Make sure Prometheus is ready
 	targets, err := promAPI.Targets(context.TODO())
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, unavailableError(address, query, completionTime, err)
 	}
 
 	for _, target := range targets.Active {
@@ -77,7 +79,7 @@ func captureOnePrometheusMetric(address, query, errorQuery string, completionTim
 	// Execute query
 	v, _, err := promAPI.Query(context.TODO(), query, completionTime)
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, unavailableError(address, query, completionTime, err)
 	}
 
 	// Only accept scalar results
@@ -19,15 +19,21 @@ package ready
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/expression"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/scale/scheme/extensionsv1beta1"
+	"k8s.io/client-go/util/jsonpath"
 	"k8s.io/kubectl/pkg/polymorphichelpers"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -52,6 +58,8 @@ const (
 type ReadinessChecker struct {
 	// Reader is used to fetch information about objects related to the object whose conditions are being checked
 	Reader client.Reader
+	// ScaleUpTimeout is the amount of time unschedulable pods are given for new nodes to be provisioned
+	ScaleUpTimeout time.Duration
 }
 
 // ReadinessError is an error that occurs while testing for readiness, it indicates a "hard failure" and is not just
@@ -117,6 +125,50 @@ func (r *ReadinessChecker) CheckConditions(ctx context.Context, obj *unstructure
 	return "", true, nil
 }
 
+// CheckExpressions evaluates readiness expressions against the specified object, all of the expressions must produce
+// their expected value for the object to be considered ready. Expressions which fail to evaluate (for example, because
+// the object does not have a status yet) are not ready, only expressions which cannot be compiled are an error.
+func (r *ReadinessChecker) CheckExpressions(obj *unstructured.Unstructured, expressions []redskyv1beta1.ReadinessExpression) (string, bool, error) {
+	for i := range expressions {
+		e := &expressions[i]
+		if e.CEL != "" {
+			ok, err := expression.EvaluateBool(e.CEL, map[string]interface{}{"self": obj.UnstructuredContent()})
+			if expression.IsEvaluationError(err) {
+				// The object may not be fully populated yet (e.g. "no such key: status"), only invalid expressions fail
+				return fmt.Sprintf("Waiting for %s: %v", e.CEL, err), false, nil
+			}
+			if err != nil {
+				return "", false, &ReadinessError{error: "invalid readiness expression", Reason: "InvalidExpression", Message: err.Error()}
+			}
+			if !ok {
+				return fmt.Sprintf("Waiting for %s", e.CEL), false, nil
+			}
+		}
+		if e.JSONPath == "" {
+			continue
+		}
+
+		jp := jsonpath.New("readiness").AllowMissingKeys(true)
+		if err := jp.Parse(e.JSONPath); err != nil {
+			return "", false, &ReadinessError{error: "invalid readiness expression", Reason: "InvalidExpression", Message: err.Error()}
+		}
+		var buf strings.Builder
+		if err := jp.Execute(&buf, obj.UnstructuredContent()); err != nil {
+			return "", false, &ReadinessError{error: "invalid readiness expression", Reason: "InvalidExpression", Message: err.Error()}
+		}
+
+		result := strings.TrimSpace(buf.String())
+		if e.Value != "" {
+			if result != e.Value {
+				return fmt.Sprintf("Waiting for %s to be %q (currently %q)", e.JSONPath, e.Value, result), false, nil
+			}
+		} else if result == "" || strings.EqualFold(result, "false") {
+			return fmt.Sprintf("Waiting for %s", e.JSONPath), false, nil
+		}
+	}
+	return "", true, nil
+}
+
 // alwaysTrue does not actually check any status and just returns true
 func (r *ReadinessChecker) alwaysTrue(obj *unstructured.Unstructured) (string, corev1.ConditionStatus, error) {
 	_ = obj.GroupVersionKind() // Just to be consistent with everyone else
@@ -156,6 +208,16 @@ func (r *ReadinessChecker) unstructuredConditionStatus(obj *unstructured.Unstruc
 
 // appReady performs a rollout status check and falls back to a pod ready check
 func (r *ReadinessChecker) appReady(ctx context.Context, obj *unstructured.Unstructured) (string, corev1.ConditionStatus, error) {
+	// Stateful sets are checked pod-by-pod to account for partitions and the "OnDelete" strategy
+	if obj.GroupVersionKind().GroupKind() == appsv1.SchemeGroupVersion.WithKind("StatefulSet").GroupKind() {
+		return r.statefulSetReady(ctx, obj)
+	}
+
+	// Horizontal pod autoscalers do not roll out, instead they are checked for a settled replica count
+	if obj.GroupVersionKind().GroupKind() == autoscalingv1.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler").GroupKind() {
+		return r.horizontalPodAutoscalerReady(obj)
+	}
+
 	// Get the kubectl status viewer for the object, if no status viewer is available, fall back to pod ready
 	sv, err := polymorphichelpers.StatusViewerFor(obj.GetObjectKind().GroupVersionKind().GroupKind())
 	if err != nil {
@@ -193,6 +255,90 @@ func (r *ReadinessChecker) rolloutStatus(obj *unstructured.Unstructured) (string
 	return msg, corev1.ConditionFalse, err
 }
 
+// statefulSetReady checks that every pod at or above the update partition is running the current revision and is ready
+func (r *ReadinessChecker) statefulSetReady(ctx context.Context, obj *unstructured.Unstructured) (string, corev1.ConditionStatus, error) {
+	sts := &appsv1.StatefulSet{}
+	if err := scheme.Scheme.Convert(obj, sts, nil); err != nil {
+		return "", corev1.ConditionFalse, fmt.Errorf("failed to convert %T to %T: %v", obj, sts, err)
+	}
+
+	if sts.Status.ObservedGeneration == 0 || sts.Generation > sts.Status.ObservedGeneration {
+		return "Waiting for statefulset spec update to be observed...", corev1.ConditionFalse, nil
+	}
+
+	// Only pods with an ordinal at or above the partition are expected to be updated
+	var partition int64
+	if ru := sts.Spec.UpdateStrategy.RollingUpdate; ru != nil && ru.Partition != nil {
+		partition = int64(*ru.Partition)
+	}
+
+	list, err := r.listPods(ctx, obj)
+	if err != nil {
+		return "", corev1.ConditionFalse, err
+	}
+
+	var updated int64
+	for i := range list.Items {
+		p := &list.Items[i]
+		ordinal, err := strconv.ParseInt(p.Name[strings.LastIndex(p.Name, "-")+1:], 10, 64)
+		if err != nil || ordinal < partition {
+			continue
+		}
+
+		if sts.Status.UpdateRevision != "" && p.Labels[appsv1.StatefulSetRevisionLabel] != sts.Status.UpdateRevision {
+			return fmt.Sprintf("Waiting for pod %s to be updated...", p.Name), corev1.ConditionFalse, nil
+		}
+		var ready bool
+		for _, c := range p.Status.Conditions {
+			if c.Type == corev1.PodReady {
+				ready = c.Status == corev1.ConditionTrue
+			}
+		}
+		if !ready {
+			return fmt.Sprintf("Waiting for pod %s to be ready...", p.Name), corev1.ConditionFalse, nil
+		}
+		updated++
+	}
+
+	replicas := int64(1)
+	if sts.Spec.Replicas != nil {
+		replicas = int64(*sts.Spec.Replicas)
+	}
+	if expected := replicas - partition; updated < expected {
+		return fmt.Sprintf("Waiting for %d pods to be updated...", expected-updated), corev1.ConditionFalse, nil
+	}
+
+	if partition > 0 {
+		return fmt.Sprintf("partitioned roll out complete: %d new pods have been updated...", updated), corev1.ConditionTrue, nil
+	}
+	return fmt.Sprintf("statefulset rolling update complete %d pods at revision %s...", updated, sts.Status.UpdateRevision), corev1.ConditionTrue, nil
+}
+
+// horizontalPodAutoscalerReady checks that the autoscaler has observed the current spec and that the number of replicas
+// is within the configured bounds and matches the desired number of replicas; only fields common to all API versions
+// are considered
+func (r *ReadinessChecker) horizontalPodAutoscalerReady(obj *unstructured.Unstructured) (string, corev1.ConditionStatus, error) {
+	if observed, ok, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration"); ok && observed < obj.GetGeneration() {
+		return "Waiting for horizontal pod autoscaler spec update to be observed...", corev1.ConditionFalse, nil
+	}
+
+	minReplicas, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "minReplicas")
+	if !ok {
+		minReplicas = 1
+	}
+	maxReplicas, _, _ := unstructured.NestedInt64(obj.Object, "spec", "maxReplicas")
+	current, _, _ := unstructured.NestedInt64(obj.Object, "status", "currentReplicas")
+	desired, _, _ := unstructured.NestedInt64(obj.Object, "status", "desiredReplicas")
+
+	if current < minReplicas || (maxReplicas > 0 && current > maxReplicas) {
+		return fmt.Sprintf("Waiting for %d replicas to be within %d-%d...", current, minReplicas, maxReplicas), corev1.ConditionFalse, nil
+	}
+	if current != desired {
+		return fmt.Sprintf("Waiting for scaling from %d to %d replicas...", current, desired), corev1.ConditionFalse, nil
+	}
+	return fmt.Sprintf("horizontal pod autoscaler settled at %d replicas...", current), corev1.ConditionTrue, nil
+}
+
 // podReady attempts to locate the pods associated with the specified object and
 func (r *ReadinessChecker) podReady(ctx context.Context, obj *unstructured.Unstructured) (string, corev1.ConditionStatus, error) {
 	// Get the list of pods for the object
@@ -231,9 +377,11 @@ func (r *ReadinessChecker) podFailed(ctx context.Context, obj *unstructured.Unst
 	for i := range list.Items {
 		p := &list.Items[i]
 		for _, c := range p.Status.Conditions {
-			// Check for unschedulable pods
+			// Check for unschedulable pods, unless an autoscaler may still be provisioning nodes
 			if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
-				return &ReadinessError{error: "pod unschedulable", Reason: c.Reason, Message: c.Message}
+				if !AwaitingScaleUp(p, r.ScaleUpTimeout, time.Now()) {
+					return &ReadinessError{error: "pod unschedulable", Reason: c.Reason, Message: c.Message}
+				}
 			}
 
 			// Check the container status
@@ -262,6 +410,37 @@ func (r *ReadinessChecker) podFailed(ctx context.Context, obj *unstructured.Unst
 	return nil
 }
 
+// AwaitingScaleUp checks to see if the pod is unschedulable for less than the scale up timeout, i.e. it may still be
+// scheduled once an autoscaler provisions a new node
+func AwaitingScaleUp(pod *corev1.Pod, timeout time.Duration, now time.Time) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+			return now.Sub(c.LastTransitionTime.Time) < timeout
+		}
+	}
+	return false
+}
+
+// ScalingUp checks to see if any of the pods for the supplied object are waiting for new nodes
+func (r *ReadinessChecker) ScalingUp(ctx context.Context, obj *unstructured.Unstructured) (bool, error) {
+	if r.ScaleUpTimeout <= 0 {
+		return false, nil
+	}
+
+	list, err := r.listPods(ctx, obj)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	for i := range list.Items {
+		if AwaitingScaleUp(&list.Items[i], r.ScaleUpTimeout, now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // listPods returns the pods "owned" by the supplied unstructured object
 func (r *ReadinessChecker) listPods(ctx context.Context, obj *unstructured.Unstructured) (*corev1.PodList, error) {
 	// Get the pod selector
@@ -19,9 +19,12 @@ package ready
 import (
 	"context"
 	"testing"
+	"time"
 
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	"github.com/stretchr/testify/assert"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -68,6 +71,112 @@ func TestReadinessChecker_CheckConditions(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc:           "statefulset-partition",
+			conditionTypes: []string{ConditionTypeAppReady},
+			ready:          true,
+
+			objs: []runtime.Object{
+				&appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 2},
+					Spec: appsv1.StatefulSetSpec{
+						Replicas: &[]int32{2}[0],
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"test": "test"},
+						},
+						UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+							Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+							RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: &[]int32{1}[0]},
+						},
+					},
+					Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdateRevision: "test-2"},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-0", Labels: map[string]string{"test": "test", appsv1.StatefulSetRevisionLabel: "test-1"}},
+					Status: corev1.PodStatus{
+						Conditions: []corev1.PodCondition{{
+							Type:   corev1.PodReady,
+							Status: corev1.ConditionTrue,
+						}},
+					},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-1", Labels: map[string]string{"test": "test", appsv1.StatefulSetRevisionLabel: "test-2"}},
+					Status: corev1.PodStatus{
+						Conditions: []corev1.PodCondition{{
+							Type:   corev1.PodReady,
+							Status: corev1.ConditionTrue,
+						}},
+					},
+				},
+			},
+		},
+		{
+			desc:           "statefulset-on-delete",
+			conditionTypes: []string{ConditionTypeAppReady},
+			msg:            "Waiting for pod test-0 to be updated...",
+
+			objs: []runtime.Object{
+				&appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 2},
+					Spec: appsv1.StatefulSetSpec{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"test": "test"},
+						},
+						UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+					},
+					Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdateRevision: "test-2"},
+				},
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-0", Labels: map[string]string{"test": "test", appsv1.StatefulSetRevisionLabel: "test-1"}},
+					Status: corev1.PodStatus{
+						Conditions: []corev1.PodCondition{{
+							Type:   corev1.PodReady,
+							Status: corev1.ConditionTrue,
+						}},
+					},
+				},
+			},
+		},
+		{
+			desc:           "hpa-settled",
+			conditionTypes: []string{ConditionTypeAppReady},
+			ready:          true,
+
+			objs: []runtime.Object{
+				&autoscalingv1.HorizontalPodAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 2},
+					Spec:       autoscalingv1.HorizontalPodAutoscalerSpec{MinReplicas: &[]int32{3}[0], MaxReplicas: 5},
+					Status:     autoscalingv1.HorizontalPodAutoscalerStatus{ObservedGeneration: &[]int64{2}[0], CurrentReplicas: 3, DesiredReplicas: 3},
+				},
+			},
+		},
+		{
+			desc:           "hpa-scaling",
+			conditionTypes: []string{ConditionTypeAppReady},
+			msg:            "Waiting for scaling from 2 to 3 replicas...",
+
+			objs: []runtime.Object{
+				&autoscalingv1.HorizontalPodAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 2},
+					Spec:       autoscalingv1.HorizontalPodAutoscalerSpec{MaxReplicas: 5},
+					Status:     autoscalingv1.HorizontalPodAutoscalerStatus{ObservedGeneration: &[]int64{2}[0], CurrentReplicas: 2, DesiredReplicas: 3},
+				},
+			},
+		},
+		{
+			desc:           "hpa-below-minimum",
+			conditionTypes: []string{ConditionTypeAppReady},
+			msg:            "Waiting for 1 replicas to be within 2-5...",
+
+			objs: []runtime.Object{
+				&autoscalingv1.HorizontalPodAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{Name: "test", Generation: 1},
+					Spec:       autoscalingv1.HorizontalPodAutoscalerSpec{MinReplicas: &[]int32{2}[0], MaxReplicas: 5},
+					Status:     autoscalingv1.HorizontalPodAutoscalerStatus{CurrentReplicas: 1, DesiredReplicas: 2},
+				},
+			},
+		},
 		{
 			desc:           "unschedulable",
 			conditionTypes: []string{ConditionTypePodReady},
@@ -131,3 +240,148 @@ func TestReadinessChecker_CheckConditions(t *testing.T) {
 		})
 	}
 }
+
+func TestReadinessChecker_CheckExpressions(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Database",
+		"status": map[string]interface{}{
+			"phase":     "Running",
+			"available": true,
+		},
+	}}
+
+	cases := []struct {
+		desc        string
+		expressions []redskyv1beta1.ReadinessExpression
+		msg         string
+		ready       bool
+		err         bool
+	}{
+		{
+			desc:        "value",
+			expressions: []redskyv1beta1.ReadinessExpression{{JSONPath: "{.status.phase}", Value: "Running"}},
+			ready:       true,
+		},
+		{
+			desc:        "truthy",
+			expressions: []redskyv1beta1.ReadinessExpression{{JSONPath: "{.status.available}"}},
+			ready:       true,
+		},
+		{
+			desc:        "mismatch",
+			expressions: []redskyv1beta1.ReadinessExpression{{JSONPath: "{.status.phase}", Value: "Ready"}},
+			msg:         `Waiting for {.status.phase} to be "Ready" (currently "Running")`,
+		},
+		{
+			desc:        "missing",
+			expressions: []redskyv1beta1.ReadinessExpression{{JSONPath: "{.status.replicas}"}},
+			msg:         "Waiting for {.status.replicas}",
+		},
+		{
+			desc:        "invalid",
+			expressions: []redskyv1beta1.ReadinessExpression{{JSONPath: "{.status.phase"}},
+			err:         true,
+		},
+		{
+			desc:        "cel",
+			expressions: []redskyv1beta1.ReadinessExpression{{CEL: `self.status.phase == "Running" && self.status.available`}},
+			ready:       true,
+		},
+		{
+			desc:        "cel not ready",
+			expressions: []redskyv1beta1.ReadinessExpression{{CEL: `self.status.phase == "Ready"`}},
+			msg:         `Waiting for self.status.phase == "Ready"`,
+		},
+		{
+			desc:        "cel missing key",
+			expressions: []redskyv1beta1.ReadinessExpression{{CEL: `self.status.replicas > 0`}},
+			msg:         `Waiting for self.status.replicas > 0: no such key: replicas`,
+		},
+		{
+			desc:        "cel invalid",
+			expressions: []redskyv1beta1.ReadinessExpression{{CEL: `self.status.phase ==`}},
+			err:         true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			rc := &ReadinessChecker{}
+			msg, ready, err := rc.CheckExpressions(obj, c.expressions)
+			assert.Equal(t, c.ready, ready)
+			assert.Equal(t, c.msg, msg)
+			if c.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReadinessChecker_CheckExpressionsNoStatus(t *testing.T) {
+	// A newly created object does not have a status until its controller has observed it
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Database",
+	}}
+
+	rc := &ReadinessChecker{}
+	msg, ready, err := rc.CheckExpressions(obj, []redskyv1beta1.ReadinessExpression{{CEL: `self.status.phase == "Running"`}})
+	assert.NoError(t, err)
+	assert.False(t, ready)
+	assert.Equal(t, `Waiting for self.status.phase == "Running": no such key: status`, msg)
+
+	msg, ready, err = rc.CheckExpressions(obj, []redskyv1beta1.ReadinessExpression{{CEL: `has(self.status) && self.status.phase == "Running"`}})
+	assert.NoError(t, err)
+	assert.False(t, ready)
+	assert.Equal(t, `Waiting for has(self.status) && self.status.phase == "Running"`, msg)
+}
+
+func TestAwaitingScaleUp(t *testing.T) {
+	now := time.Now()
+	unschedulable := func(d time.Duration) corev1.PodCondition {
+		return corev1.PodCondition{
+			Type:               corev1.PodScheduled,
+			Status:             corev1.ConditionFalse,
+			Reason:             corev1.PodReasonUnschedulable,
+			LastTransitionTime: metav1.NewTime(now.Add(-d)),
+		}
+	}
+
+	cases := []struct {
+		desc       string
+		conditions []corev1.PodCondition
+		timeout    time.Duration
+		expected   bool
+	}{
+		{
+			desc:    "scheduled",
+			timeout: time.Minute,
+			conditions: []corev1.PodCondition{
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue},
+			},
+		},
+		{
+			desc:       "no timeout",
+			conditions: []corev1.PodCondition{unschedulable(time.Second)},
+		},
+		{
+			desc:       "within timeout",
+			timeout:    time.Minute,
+			conditions: []corev1.PodCondition{unschedulable(time.Second)},
+			expected:   true,
+		},
+		{
+			desc:       "timeout exceeded",
+			timeout:    time.Minute,
+			conditions: []corev1.PodCondition{unschedulable(2 * time.Minute)},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			pod := &corev1.Pod{Status: corev1.PodStatus{Conditions: c.conditions}}
+			assert.Equal(t, c.expected, AwaitingScaleUp(pod, c.timeout, now))
+		})
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule implements the standard five field cron schedule format.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron schedule
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar track wildcards, when both are restricted a time matches if either matches
+	domStar, dowStar bool
+}
+
+// field describes the valid range of a schedule field
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minutes    = field{name: "minute", min: 0, max: 59}
+	hours      = field{name: "hour", min: 0, max: 23}
+	daysOfMon  = field{name: "day of month", min: 1, max: 31}
+	months     = field{name: "month", min: 1, max: 12, names: map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}}
+	daysOfWeek = field{name: "day of week", min: 0, max: 7, names: map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}}
+)
+
+// descriptors are the supported shortcuts for common schedules
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a standard cron schedule, e.g. "0 3 * * 1" or "@weekly"
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = d
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected exactly 5 fields, found %d: %s", len(fields), spec)
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.minute, err = minutes.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hour, err = hours.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.dom, err = daysOfMon.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.month, err = months.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.dow, err = daysOfWeek.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	// Sunday may be either 0 or 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// Next returns the first activation time strictly after the supplied time, a zero time is returned if the schedule
+// can never be satisfied (e.g. February 30th)
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+
+	// Give up after five years, the longest gap for a satisfiable schedule is a leap day
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// Last returns the most recent activation time after the supplied time which is not after the current time, a zero
+// time is returned if there are no activations in that range
+func (s *Schedule) Last(after, now time.Time) time.Time {
+	var last time.Time
+	for t := s.Next(after); !t.IsZero() && !t.After(now); t = s.Next(t) {
+		last = t
+	}
+	return last
+}
+
+// matchDay checks both the day of month and day of week fields
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// parse converts a comma separated list of ranges into a bit set
+func (f *field) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, r := range strings.Split(expr, ",") {
+		b, err := f.parseRange(r)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	return bits, nil
+}
+
+// parseRange converts a single "*", "N", "N-M" expression (with an optional "/STEP") into a bit set
+func (f *field) parseRange(expr string) (uint64, error) {
+	rangeAndStep := strings.SplitN(expr, "/", 2)
+	lowAndHigh := strings.SplitN(rangeAndStep[0], "-", 2)
+
+	var low, high int
+	var err error
+	if lowAndHigh[0] == "*" {
+		low, high = f.min, f.max
+	} else {
+		if low, err = f.value(lowAndHigh[0]); err != nil {
+			return 0, err
+		}
+		high = low
+		if len(lowAndHigh) > 1 {
+			if high, err = f.value(lowAndHigh[1]); err != nil {
+				return 0, err
+			}
+		} else if len(rangeAndStep) > 1 {
+			high = f.max
+		}
+	}
+
+	step := 1
+	if len(rangeAndStep) > 1 {
+		if step, err = strconv.Atoi(rangeAndStep[1]); err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid %s step: %s", f.name, expr)
+		}
+	}
+
+	if low < f.min || high > f.max || low > high {
+		return 0, fmt.Errorf("%s out of range [%d, %d]: %s", f.name, f.min, f.max, expr)
+	}
+
+	var bits uint64
+	for i := low; i <= high; i += step {
+		bits |= 1 << uint(i)
+	}
+	return bits, nil
+}
+
+// value converts a single number or name into an integer
+func (f *field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", f.name, s)
+	}
+	return v, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	from := time.Date(2020, time.June, 10, 14, 30, 15, 0, time.UTC) // Wednesday
+
+	cases := []struct {
+		spec string
+		next time.Time
+	}{
+		{spec: "* * * * *", next: time.Date(2020, time.June, 10, 14, 31, 0, 0, time.UTC)},
+		{spec: "@hourly", next: time.Date(2020, time.June, 10, 15, 0, 0, 0, time.UTC)},
+		{spec: "@daily", next: time.Date(2020, time.June, 11, 0, 0, 0, 0, time.UTC)},
+		{spec: "@weekly", next: time.Date(2020, time.June, 14, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 3 * * mon", next: time.Date(2020, time.June, 15, 3, 0, 0, 0, time.UTC)},
+		{spec: "0 3 * * 7", next: time.Date(2020, time.June, 14, 3, 0, 0, 0, time.UTC)},
+		{spec: "*/20 * * * *", next: time.Date(2020, time.June, 10, 14, 40, 0, 0, time.UTC)},
+		{spec: "15 9-17/4 * * *", next: time.Date(2020, time.June, 10, 17, 15, 0, 0, time.UTC)},
+		{spec: "0 0 1,15 * *", next: time.Date(2020, time.June, 15, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 feb *", next: time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 13 * fri", next: time.Date(2020, time.June, 12, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 30 feb *", next: time.Time{}},
+	}
+	for _, c := range cases {
+		t.Run(c.spec, func(t *testing.T) {
+			s, err := Parse(c.spec)
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.next, s.Next(from))
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		spec string
+		err  bool
+	}{
+		{spec: "0 0 * *", err: true},
+		{spec: "60 * * * *", err: true},
+		{spec: "* 24 * * *", err: true},
+		{spec: "* * 0 * *", err: true},
+		{spec: "* * * 13 *", err: true},
+		{spec: "* * * * 8", err: true},
+		{spec: "*/0 * * * *", err: true},
+		{spec: "5-1 * * * *", err: true},
+		{spec: "x * * * *", err: true},
+		{spec: "0-59/15 0,12 1-7 jan-dec sun-sat"},
+	}
+	for _, c := range cases {
+		t.Run(c.spec, func(t *testing.T) {
+			_, err := Parse(c.spec)
+			if c.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSchedule_Last(t *testing.T) {
+	s, err := Parse("0 * * * *")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	after := time.Date(2020, time.June, 10, 14, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Time{}, s.Last(after, after.Add(15*time.Minute)))
+	assert.Equal(t, time.Date(2020, time.June, 10, 15, 0, 0, 0, time.UTC), s.Last(after, after.Add(30*time.Minute)))
+	assert.Equal(t, time.Date(2020, time.June, 10, 17, 0, 0, 0, time.UTC), s.Last(after, after.Add(3*time.Hour)))
+}
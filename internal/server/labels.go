@@ -0,0 +1,96 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"sort"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	redskyapi "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+)
+
+// TrialLabels returns the label changes needed to synchronize the trial labels with the server or nil if the server is
+// already up-to-date; only unqualified label names (e.g. "best" or "baseline") are synchronized
+func TrialLabels(t *redskyv1beta1.Trial) *redskyapi.TrialLabels {
+	if t.GetAnnotations()[redskyv1beta1.AnnotationLabelTrialURL] == "" {
+		return nil
+	}
+
+	current := syncableLabels(t)
+	synced := syncedLabels(t)
+
+	lbls := make(map[string]string)
+	for k, v := range current {
+		if sv, ok := synced[k]; !ok || sv != v {
+			lbls[k] = v
+		}
+	}
+	for k := range synced {
+		if _, ok := current[k]; !ok {
+			// An empty value removes the label on the server
+			lbls[k] = ""
+		}
+	}
+
+	if len(lbls) == 0 {
+		return nil
+	}
+	return &redskyapi.TrialLabels{Labels: lbls}
+}
+
+// SetTrialLabelsSynced records the current trial labels as being synchronized with the server
+func SetTrialLabelsSynced(t *redskyv1beta1.Trial) {
+	current := syncableLabels(t)
+	if len(current) == 0 {
+		delete(t.GetAnnotations(), redskyv1beta1.AnnotationSyncedLabels)
+		return
+	}
+
+	pairs := make([]string, 0, len(current))
+	for k, v := range current {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	if t.Annotations == nil {
+		t.Annotations = make(map[string]string, 1)
+	}
+	t.Annotations[redskyv1beta1.AnnotationSyncedLabels] = strings.Join(pairs, ",")
+}
+
+// syncableLabels returns the trial labels which should be present on the server
+func syncableLabels(t *redskyv1beta1.Trial) map[string]string {
+	lbls := make(map[string]string)
+	for k, v := range t.GetLabels() {
+		if !strings.Contains(k, "/") {
+			lbls[k] = v
+		}
+	}
+	return lbls
+}
+
+// syncedLabels returns the trial labels last sent to the server
+func syncedLabels(t *redskyv1beta1.Trial) map[string]string {
+	lbls := make(map[string]string)
+	for _, pair := range strings.Split(t.GetAnnotations()[redskyv1beta1.AnnotationSyncedLabels], ",") {
+		if p := strings.SplitN(pair, "=", 2); len(p) == 2 {
+			lbls[p[0]] = p[1]
+		}
+	}
+	return lbls
+}
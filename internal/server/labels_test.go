@@ -0,0 +1,48 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	redskyapi "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrialLabels(t *testing.T) {
+	tr := &redskyv1beta1.Trial{}
+	tr.Labels = map[string]string{"best": "true", redskyv1beta1.LabelExperiment: "test"}
+
+	// Trials without a label URL cannot be synchronized
+	assert.Nil(t, TrialLabels(tr))
+
+	tr.Annotations = map[string]string{redskyv1beta1.AnnotationLabelTrialURL: "http://example.com/experiments/test/trials/1/labels"}
+	assert.Equal(t, &redskyapi.TrialLabels{Labels: map[string]string{"best": "true"}}, TrialLabels(tr))
+
+	SetTrialLabelsSynced(tr)
+	assert.Equal(t, "best=true", tr.Annotations[redskyv1beta1.AnnotationSyncedLabels])
+	assert.Nil(t, TrialLabels(tr))
+
+	// Removed labels are sent with an empty value
+	delete(tr.Labels, "best")
+	tr.Labels["baseline"] = "true"
+	assert.Equal(t, &redskyapi.TrialLabels{Labels: map[string]string{"best": "", "baseline": "true"}}, TrialLabels(tr))
+
+	SetTrialLabelsSynced(tr)
+	assert.Equal(t, "baseline=true", tr.Annotations[redskyv1beta1.AnnotationSyncedLabels])
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	redskyapi "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+)
+
+// OptimizationChecksum returns a checksum of the optimization configuration of an experiment
+func OptimizationChecksum(exp *redskyv1beta1.Experiment) string {
+	opt := make([]string, 0, len(exp.Spec.Optimization))
+	for _, o := range exp.Spec.Optimization {
+		opt = append(opt, o.Name+"="+o.Value)
+	}
+	sort.Strings(opt)
+
+	h := sha256.New()
+	for _, o := range opt {
+		_, _ = h.Write([]byte(o))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// NeedsOptimizationUpdate checks to see if the optimization configuration of an experiment has changed since it was
+// last sent to the server; experiments without a recorded checksum are assumed to be up-to-date
+func NeedsOptimizationUpdate(exp *redskyv1beta1.Experiment) bool {
+	checksum, ok := exp.GetAnnotations()[redskyv1beta1.AnnotationOptimizationChecksum]
+	return ok && checksum != OptimizationChecksum(exp)
+}
+
+// OptimizationPatch returns the patch used to update the optimization configuration of the experiment on the server
+func OptimizationPatch(exp *redskyv1beta1.Experiment) redskyapi.ExperimentPatch {
+	p := redskyapi.ExperimentPatch{}
+	for _, o := range exp.Spec.Optimization {
+		// Warm starts only apply when the experiment is created
+		if o.Name == OptimizationWarmStartFrom {
+			continue
+		}
+		p.Optimization = append(p.Optimization, redskyapi.Optimization{Name: o.Name, Value: o.Value})
+	}
+	return p
+}
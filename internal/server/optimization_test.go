@@ -0,0 +1,50 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	redskyapi "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNeedsOptimizationUpdate(t *testing.T) {
+	exp := &redskyv1beta1.Experiment{}
+	exp.Spec.Optimization = []redskyv1beta1.Optimization{
+		{Name: redskyapi.OptimizationExperimentBudget, Value: "40"},
+		{Name: OptimizationWarmStartFrom, Value: "https://example.com/experiments/previous"},
+	}
+
+	// Experiments without a checksum were never synchronized
+	assert.False(t, NeedsOptimizationUpdate(exp))
+
+	ToCluster(exp, &redskyapi.Experiment{Optimization: []redskyapi.Optimization{
+		{Name: redskyapi.OptimizationExperimentBudget, Value: "40"},
+		{Name: OptimizationWarmStartFrom, Value: "https://example.com/experiments/previous"},
+	}})
+	assert.False(t, NeedsOptimizationUpdate(exp))
+
+	exp.Spec.Optimization[0].Value = "80"
+	exp.Spec.Optimization = append(exp.Spec.Optimization, redskyv1beta1.Optimization{Name: redskyapi.OptimizationParallelTrials, Value: "2"})
+	assert.True(t, NeedsOptimizationUpdate(exp))
+	assert.Equal(t, redskyapi.ExperimentPatch{Optimization: []redskyapi.Optimization{
+		{Name: redskyapi.OptimizationExperimentBudget, Value: "80"},
+		{Name: redskyapi.OptimizationParallelTrials, Value: "2"},
+	}}, OptimizationPatch(exp))
+}
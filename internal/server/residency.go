@@ -0,0 +1,93 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	redskyapi "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+)
+
+const (
+	// DataResidencyRelative reports metric values relative to the in-cluster baseline
+	DataResidencyRelative = "relative"
+)
+
+// ApplyDataResidency adjusts the trial values according to the data residency mode of the experiment, returns true if
+// the experiment baseline was changed and the experiment needs to be updated before the values can be reported
+func ApplyDataResidency(exp *redskyv1beta1.Experiment, values *redskyapi.TrialValues) bool {
+	if exp.GetAnnotations()[redskyv1beta1.AnnotationDataResidency] != DataResidencyRelative {
+		return false
+	}
+
+	// The first non-zero value of each metric becomes the baseline
+	baseline := parseBaseline(exp.GetAnnotations()[redskyv1beta1.AnnotationMetricBaseline])
+	var dirty bool
+	for _, v := range values.Values {
+		if _, ok := baseline[v.MetricName]; !ok && v.Value != 0 {
+			baseline[v.MetricName] = v.Value
+			dirty = true
+		}
+	}
+	if dirty {
+		exp.GetAnnotations()[redskyv1beta1.AnnotationMetricBaseline] = formatBaseline(baseline)
+		return true
+	}
+
+	// Only values relative to the baseline leave the cluster
+	for i := range values.Values {
+		b := math.Abs(baseline[values.Values[i].MetricName])
+		if b == 0 {
+			// Without a baseline the value must be zero
+			values.Values[i].Value = 0
+			values.Values[i].Error = 0
+			continue
+		}
+		values.Values[i].Value /= b
+		values.Values[i].Error /= b
+	}
+	return false
+}
+
+// parseBaseline parses the metric baseline annotation value
+func parseBaseline(s string) map[string]float64 {
+	baseline := make(map[string]float64)
+	for _, p := range strings.Split(s, ",") {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseFloat(kv[1], 64); err == nil {
+			baseline[strings.TrimSpace(kv[0])] = v
+		}
+	}
+	return baseline
+}
+
+// formatBaseline produces a metric baseline annotation value
+func formatBaseline(baseline map[string]float64) string {
+	pairs := make([]string, 0, len(baseline))
+	for k, v := range baseline {
+		pairs = append(pairs, k+"="+strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	redskyapi "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyDataResidency(t *testing.T) {
+	cases := []struct {
+		desc        string
+		annotations map[string]string
+		values      []redskyapi.Value
+		dirty       bool
+		baseline    string
+		expected    []redskyapi.Value
+	}{
+		{
+			desc:     "raw",
+			values:   []redskyapi.Value{{MetricName: "one", Value: 200, Error: 10}},
+			expected: []redskyapi.Value{{MetricName: "one", Value: 200, Error: 10}},
+		},
+		{
+			desc: "initial baseline",
+			annotations: map[string]string{
+				redskyv1beta1.AnnotationDataResidency: DataResidencyRelative,
+			},
+			values:   []redskyapi.Value{{MetricName: "one", Value: 200}, {MetricName: "two", Value: -0.5}},
+			dirty:    true,
+			baseline: "one=200,two=-0.5",
+			expected: []redskyapi.Value{{MetricName: "one", Value: 200}, {MetricName: "two", Value: -0.5}},
+		},
+		{
+			desc: "relative",
+			annotations: map[string]string{
+				redskyv1beta1.AnnotationDataResidency:  DataResidencyRelative,
+				redskyv1beta1.AnnotationMetricBaseline: "one=200,two=-0.5",
+			},
+			values:   []redskyapi.Value{{MetricName: "one", Value: 100, Error: 10}, {MetricName: "two", Value: -1}},
+			baseline: "one=200,two=-0.5",
+			expected: []redskyapi.Value{{MetricName: "one", Value: 0.5, Error: 0.05}, {MetricName: "two", Value: -2}},
+		},
+		{
+			desc: "zero without baseline",
+			annotations: map[string]string{
+				redskyv1beta1.AnnotationDataResidency: DataResidencyRelative,
+			},
+			values:   []redskyapi.Value{{MetricName: "one", Value: 0, Error: 1}},
+			expected: []redskyapi.Value{{MetricName: "one", Value: 0, Error: 0}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			exp := &redskyv1beta1.Experiment{ObjectMeta: metav1.ObjectMeta{Annotations: c.annotations}}
+			values := &redskyapi.TrialValues{Values: c.values}
+			assert.Equal(t, c.dirty, ApplyDataResidency(exp, values))
+			assert.Equal(t, c.baseline, exp.GetAnnotations()[redskyv1beta1.AnnotationMetricBaseline])
+			assert.Equal(t, c.expected, values.Values)
+		})
+	}
+}
@@ -17,10 +17,13 @@ limitations under the License.
 package server
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"path"
 	"strconv"
+	"strings"
 
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	"github.com/redskyops/redskyops-controller/internal/trial"
@@ -32,6 +35,9 @@ import (
 const (
 	// Finalizer is used to ensure synchronization with the server
 	Finalizer = "serverFinalizer.redskyops.dev"
+
+	// OptimizationWarmStartFrom is the optimization configuration used to seed an experiment with previous results
+	OptimizationWarmStartFrom = "warmStartFrom"
 )
 
 // TODO Split this into trial.go and experiment.go ?
@@ -44,15 +50,49 @@ func FromCluster(in *redskyv1beta1.Experiment) (redskyapi.ExperimentName, *redsk
 	out.ExperimentMeta.NextTrialURL = in.Annotations[redskyv1beta1.AnnotationNextTrialURL]
 
 	out.Optimization = nil
+	warmStartFrom := in.Annotations[redskyv1beta1.AnnotationWarmStartFrom]
 	for _, o := range in.Spec.Optimization {
 		out.Optimization = append(out.Optimization, redskyapi.Optimization{
 			Name:  o.Name,
 			Value: o.Value,
 		})
+		if o.Name == OptimizationWarmStartFrom {
+			warmStartFrom = ""
+		}
+	}
+	if warmStartFrom != "" {
+		out.Optimization = append(out.Optimization, redskyapi.Optimization{
+			Name:  OptimizationWarmStartFrom,
+			Value: warmStartFrom,
+		})
+	}
+
+	// Sensitive parameter names can optionally be hidden from the server
+	hashed := make(map[string]string)
+	parameterName := func(name string) string {
+		if h, ok := hashed[name]; ok {
+			return h
+		}
+		return name
 	}
 
 	out.Parameters = nil
 	for _, p := range in.Spec.Parameters {
+		if p.Sensitive && in.Annotations[redskyv1beta1.AnnotationHashSensitiveParameters] == "true" {
+			hashed[p.Name] = HashParameterName(p.Name)
+		}
+
+		// Categorical parameters are described by their values instead of bounds
+		if len(p.Values) > 0 {
+			out.Parameters = append(out.Parameters, redskyapi.Parameter{
+				Type:      redskyapi.ParameterTypeCategorical,
+				Name:      parameterName(p.Name),
+				Values:    p.Values,
+				Sensitive: p.Sensitive,
+			})
+			continue
+		}
+
 		// This is a special case to omit parameters client side
 		if p.Min == p.Max {
 			continue
@@ -60,11 +100,12 @@ func FromCluster(in *redskyv1beta1.Experiment) (redskyapi.ExperimentName, *redsk
 
 		out.Parameters = append(out.Parameters, redskyapi.Parameter{
 			Type: redskyapi.ParameterTypeInteger,
-			Name: p.Name,
+			Name: parameterName(p.Name),
 			Bounds: redskyapi.Bounds{
-				Min: json.Number(strconv.FormatInt(p.Min, 10)),
-				Max: json.Number(strconv.FormatInt(p.Max, 10)),
+				Min: json.Number(strconv.FormatInt(p.Min.Int64Value(), 10)),
+				Max: json.Number(strconv.FormatInt(p.Max.Int64Value(), 10)),
 			},
+			Sensitive: p.Sensitive,
 		})
 	}
 
@@ -76,8 +117,8 @@ func FromCluster(in *redskyv1beta1.Experiment) (redskyapi.ExperimentName, *redsk
 				Name:           c.Name,
 				ConstraintType: redskyapi.ConstraintOrder,
 				OrderConstraint: redskyapi.OrderConstraint{
-					LowerParameter: c.Order.LowerParameter,
-					UpperParameter: c.Order.UpperParameter,
+					LowerParameter: parameterName(c.Order.LowerParameter),
+					UpperParameter: parameterName(c.Order.UpperParameter),
 				},
 			})
 		case c.Sum != nil:
@@ -92,7 +133,7 @@ func FromCluster(in *redskyv1beta1.Experiment) (redskyapi.ExperimentName, *redsk
 				}
 
 				sc.Parameters = append(sc.Parameters, redskyapi.SumConstraintParameter{
-					Name:   p.Name,
+					Name:   parameterName(p.Name),
 					Weight: float64(p.Weight.MilliValue()) / 1000,
 				})
 			}
@@ -117,6 +158,12 @@ func FromCluster(in *redskyv1beta1.Experiment) (redskyapi.ExperimentName, *redsk
 	return n, out
 }
 
+// HashParameterName returns an opaque name used in place of a sensitive parameter name on the server
+func HashParameterName(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return "p" + hex.EncodeToString(sum[:])[:16]
+}
+
 // ToCluster converts API state to cluster state
 func ToCluster(exp *redskyv1beta1.Experiment, ee *redskyapi.Experiment) {
 	if exp.GetAnnotations() == nil {
@@ -133,13 +180,17 @@ func ToCluster(exp *redskyv1beta1.Experiment, ee *redskyapi.Experiment) {
 			Value: ee.Optimization[i].Value,
 		})
 	}
+	exp.GetAnnotations()[redskyv1beta1.AnnotationOptimizationChecksum] = OptimizationChecksum(exp)
 
 	controllerutil.AddFinalizer(exp, Finalizer)
 }
 
-// ToClusterTrial converts API state to cluster state
-func ToClusterTrial(t *redskyv1beta1.Trial, suggestion *redskyapi.TrialAssignments) {
+// ToClusterTrial converts API state to cluster state, the experiment parameters determine the type of each assignment
+func ToClusterTrial(t *redskyv1beta1.Trial, suggestion *redskyapi.TrialAssignments, parameters []redskyv1beta1.Parameter) {
 	t.GetAnnotations()[redskyv1beta1.AnnotationReportTrialURL] = suggestion.SelfURL
+	if suggestion.LabelsURL != "" {
+		t.GetAnnotations()[redskyv1beta1.AnnotationLabelTrialURL] = suggestion.LabelsURL
+	}
 
 	// Try to make the cluster trial names match what is on the server
 	if t.Name == "" && t.GenerateName != "" && suggestion.SelfURL != "" {
@@ -151,13 +202,24 @@ func ToClusterTrial(t *redskyv1beta1.Trial, suggestion *redskyapi.TrialAssignmen
 		}
 	}
 
+	// Map hashed parameter names back to the names used in the cluster
+	names := make(map[string]string)
+	for _, n := range strings.Split(t.GetAnnotations()[redskyv1beta1.AnnotationSensitiveParameters], ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			names[HashParameterName(n)] = n
+		}
+	}
+
 	for _, a := range suggestion.Assignments {
-		if v, err := a.Value.Int64(); err == nil {
-			t.Spec.Assignments = append(t.Spec.Assignments, redskyv1beta1.Assignment{
-				Name:  a.ParameterName,
-				Value: v,
-			})
+		name := a.ParameterName
+		if n, ok := names[name]; ok {
+			name = n
 		}
+
+		t.Spec.Assignments = append(t.Spec.Assignments, redskyv1beta1.Assignment{
+			Name:  name,
+			Value: redskyv1beta1.ParseAssignmentValue(parameters, name, a.Value.String()),
+		})
 	}
 
 	trial.UpdateStatus(t)
@@ -167,12 +229,14 @@ func ToClusterTrial(t *redskyv1beta1.Trial, suggestion *redskyapi.TrialAssignmen
 
 // FromClusterTrial converts cluster state to API state
 func FromClusterTrial(in *redskyv1beta1.Trial) *redskyapi.TrialValues {
-	out := &redskyapi.TrialValues{}
+	out := &redskyapi.TrialValues{IdempotencyKey: string(in.UID)}
 
 	// Check to see if the trial failed
 	for _, c := range in.Status.Conditions {
 		if c.Type == redskyv1beta1.TrialFailed && c.Status == corev1.ConditionTrue {
 			out.Failed = true
+			out.FailureReason = c.Reason
+			out.FailureMessage = c.Message
 		}
 	}
 
@@ -198,7 +262,7 @@ func FromClusterTrial(in *redskyv1beta1.Trial) *redskyapi.TrialValues {
 
 // StopExperiment updates the experiment in the event that it should be paused or halted
 func StopExperiment(exp *redskyv1beta1.Experiment, err error) bool {
-	if rse, ok := err.(*redskyapi.Error); ok && rse.Type == redskyapi.ErrExperimentStopped {
+	if redskyapi.IsErrorType(err, redskyapi.ErrExperimentStopped) {
 		exp.SetReplicas(0)
 		delete(exp.GetAnnotations(), redskyv1beta1.AnnotationNextTrialURL)
 		return true
@@ -79,15 +79,36 @@ func TestFromCluster(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "warm start",
+			in: &redskyv1beta1.Experiment{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						redskyv1beta1.AnnotationWarmStartFrom: "previous_111",
+					},
+				},
+				Spec: redskyv1beta1.ExperimentSpec{
+					Optimization: []redskyv1beta1.Optimization{
+						{Name: "one", Value: "111"},
+					},
+				},
+			},
+			out: &redskyapi.Experiment{
+				Optimization: []redskyapi.Optimization{
+					{Name: "one", Value: "111"},
+					{Name: OptimizationWarmStartFrom, Value: "previous_111"},
+				},
+			},
+		},
 		{
 			desc: "parameters",
 			in: &redskyv1beta1.Experiment{
 				Spec: redskyv1beta1.ExperimentSpec{
 					Parameters: []redskyv1beta1.Parameter{
-						{Name: "one", Min: 111, Max: 222},
-						{Name: "two", Min: 1111, Max: 2222},
-						{Name: "three", Min: 11111, Max: 22222},
-						{Name: "test_case", Min: 1, Max: 1},
+						{Name: "one", Min: redskyv1beta1.NewParameterBound(111), Max: redskyv1beta1.NewParameterBound(222)},
+						{Name: "two", Min: redskyv1beta1.NewParameterBound(1111), Max: redskyv1beta1.NewParameterBound(2222)},
+						{Name: "three", Min: redskyv1beta1.NewParameterBound(11111), Max: redskyv1beta1.NewParameterBound(22222)},
+						{Name: "test_case", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(1)},
 					},
 				},
 			},
@@ -214,6 +235,81 @@ func TestFromCluster(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "hashed sensitive parameters",
+			in: &redskyv1beta1.Experiment{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						redskyv1beta1.AnnotationHashSensitiveParameters: "true",
+					},
+				},
+				Spec: redskyv1beta1.ExperimentSpec{
+					Parameters: []redskyv1beta1.Parameter{
+						{Name: "one", Min: redskyv1beta1.NewParameterBound(0), Max: redskyv1beta1.NewParameterBound(1)},
+						{Name: "two", Min: redskyv1beta1.NewParameterBound(0), Max: redskyv1beta1.NewParameterBound(1), Sensitive: true},
+					},
+					Constraints: []redskyv1beta1.Constraint{
+						{
+							Name: "one-two",
+							Order: &redskyv1beta1.OrderConstraint{
+								LowerParameter: "one",
+								UpperParameter: "two",
+							},
+						},
+					},
+				},
+			},
+			out: &redskyapi.Experiment{
+				Parameters: []redskyapi.Parameter{
+					{
+						Type: redskyapi.ParameterTypeInteger,
+						Name: "one",
+						Bounds: redskyapi.Bounds{
+							Min: "0",
+							Max: "1",
+						},
+					},
+					{
+						Type: redskyapi.ParameterTypeInteger,
+						Name: HashParameterName("two"),
+						Bounds: redskyapi.Bounds{
+							Min: "0",
+							Max: "1",
+						},
+						Sensitive: true,
+					},
+				},
+				Constraints: []redskyapi.Constraint{
+					{
+						Name:           "one-two",
+						ConstraintType: redskyapi.ConstraintOrder,
+						OrderConstraint: redskyapi.OrderConstraint{
+							LowerParameter: "one",
+							UpperParameter: HashParameterName("two"),
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "categorical parameters",
+			in: &redskyv1beta1.Experiment{
+				Spec: redskyv1beta1.ExperimentSpec{
+					Parameters: []redskyv1beta1.Parameter{
+						{Name: "one", Values: []string{"a", "b", "c"}},
+					},
+				},
+			},
+			out: &redskyapi.Experiment{
+				Parameters: []redskyapi.Parameter{
+					{
+						Type:   redskyapi.ParameterTypeCategorical,
+						Name:   "one",
+						Values: []string{"a", "b", "c"},
+					},
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.desc, func(t *testing.T) {
@@ -252,8 +348,9 @@ func TestToCluster(t *testing.T) {
 			expOut: &redskyv1beta1.Experiment{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						redskyv1beta1.AnnotationExperimentURL: "self_111",
-						redskyv1beta1.AnnotationNextTrialURL:  "next_trial_111",
+						redskyv1beta1.AnnotationExperimentURL:        "self_111",
+						redskyv1beta1.AnnotationNextTrialURL:         "next_trial_111",
+						redskyv1beta1.AnnotationOptimizationChecksum: "5cdb3086244e5ec2",
 					},
 					Finalizers: []string{
 						Finalizer,
@@ -282,6 +379,7 @@ func TestToClusterTrial(t *testing.T) {
 		desc       string
 		trial      *redskyv1beta1.Trial
 		suggestion *redskyapi.TrialAssignments
+		parameters []redskyv1beta1.Parameter
 		trialOut   *redskyv1beta1.Trial
 	}{
 		{
@@ -319,9 +417,9 @@ func TestToClusterTrial(t *testing.T) {
 				},
 				Spec: redskyv1beta1.TrialSpec{
 					Assignments: []redskyv1beta1.Assignment{
-						{Name: "one", Value: 111},
-						{Name: "two", Value: 222},
-						{Name: "three", Value: 333},
+						{Name: "one", Value: redskyv1beta1.NewAssignmentValue(111)},
+						{Name: "two", Value: redskyv1beta1.NewAssignmentValue(222)},
+						{Name: "three", Value: redskyv1beta1.NewAssignmentValue(333)},
 					},
 				},
 			},
@@ -361,9 +459,100 @@ func TestToClusterTrial(t *testing.T) {
 				},
 				Spec: redskyv1beta1.TrialSpec{
 					Assignments: []redskyv1beta1.Assignment{
-						{Name: "one", Value: 111},
-						{Name: "two", Value: 222},
-						{Name: "three", Value: 333},
+						{Name: "one", Value: redskyv1beta1.NewAssignmentValue(111)},
+						{Name: "two", Value: redskyv1beta1.NewAssignmentValue(222)},
+						{Name: "three", Value: redskyv1beta1.NewAssignmentValue(333)},
+					},
+				},
+			},
+		},
+		{
+			desc: "hashed sensitive parameters",
+			trial: &redskyv1beta1.Trial{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "name",
+					Annotations: map[string]string{
+						redskyv1beta1.AnnotationSensitiveParameters: "two",
+					},
+				},
+			},
+			suggestion: &redskyapi.TrialAssignments{
+				TrialMeta: redskyapi.TrialMeta{
+					SelfURL: "some/path/1",
+				},
+				Assignments: []redskyapi.Assignment{
+					{ParameterName: "one", Value: json.Number("111")},
+					{ParameterName: HashParameterName("two"), Value: json.Number("222")},
+				},
+			},
+			trialOut: &redskyv1beta1.Trial{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "name",
+					Annotations: map[string]string{
+						redskyv1beta1.AnnotationSensitiveParameters: "two",
+						redskyv1beta1.AnnotationReportTrialURL:      "some/path/1",
+					},
+					Finalizers: []string{
+						Finalizer,
+					},
+				},
+				Status: redskyv1beta1.TrialStatus{
+					Phase:       "Created",
+					Assignments: "one=111, two=***",
+				},
+				Spec: redskyv1beta1.TrialSpec{
+					Assignments: []redskyv1beta1.Assignment{
+						{Name: "one", Value: redskyv1beta1.NewAssignmentValue(111)},
+						{Name: "two", Value: redskyv1beta1.NewAssignmentValue(222)},
+					},
+				},
+			},
+		},
+		{
+			desc: "categorical assignments",
+			trial: &redskyv1beta1.Trial{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "name",
+					Annotations: map[string]string{},
+				},
+			},
+			suggestion: &redskyapi.TrialAssignments{
+				TrialMeta: redskyapi.TrialMeta{
+					SelfURL: "some/path/1",
+				},
+				Assignments: []redskyapi.Assignment{
+					{ParameterName: "one", Value: json.Number("111")},
+					{ParameterName: "two", Value: json.Number("b")},
+					{ParameterName: "three", Value: json.Number("100")},
+					{ParameterName: "four", Value: json.Number("1099511627776")},
+				},
+			},
+			parameters: []redskyv1beta1.Parameter{
+				{Name: "one", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(1000)},
+				{Name: "two", Values: []string{"a", "b"}},
+				{Name: "three", Values: []string{"100", "200"}},
+				{Name: "four", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(1 << 41)},
+			},
+			trialOut: &redskyv1beta1.Trial{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "name",
+					Annotations: map[string]string{
+						redskyv1beta1.AnnotationReportTrialURL: "some/path/1",
+					},
+					Finalizers: []string{
+						Finalizer,
+					},
+				},
+				Status: redskyv1beta1.TrialStatus{
+					Phase:       "Created",
+					Assignments: "one=111, two=b, three=100, four=1099511627776",
+				},
+				Spec: redskyv1beta1.TrialSpec{
+					Assignments: []redskyv1beta1.Assignment{
+						{Name: "one", Value: redskyv1beta1.NewAssignmentValue(111)},
+						{Name: "two", Value: redskyv1beta1.NewCategoricalAssignmentValue("b")},
+						{Name: "three", Value: redskyv1beta1.NewCategoricalAssignmentValue("100")},
+						{Name: "four", Value: redskyv1beta1.NewAssignmentValue(1099511627776)},
 					},
 				},
 			},
@@ -371,7 +560,7 @@ func TestToClusterTrial(t *testing.T) {
 	}
 	for _, c := range cases {
 		t.Run(c.desc, func(t *testing.T) {
-			ToClusterTrial(c.trial, c.suggestion)
+			ToClusterTrial(c.trial, c.suggestion, c.parameters)
 			assert.Equal(t, c.trialOut, c.trial)
 		})
 	}
@@ -416,6 +605,21 @@ func TestFromClusterTrial(t *testing.T) {
 				Failed: true,
 			},
 		},
+		{
+			desc: "aborted",
+			in: &redskyv1beta1.Trial{
+				Status: redskyv1beta1.TrialStatus{
+					Conditions: []redskyv1beta1.TrialCondition{
+						{Type: redskyv1beta1.TrialFailed, Status: corev1.ConditionTrue, Reason: "Aborted", Message: "Trial was aborted"},
+					},
+				},
+			},
+			expectedOut: &redskyapi.TrialValues{
+				Failed:         true,
+				FailureReason:  "Aborted",
+				FailureMessage: "Trial was aborted",
+			},
+		},
 		{
 			desc: "conditions not failed",
 			in: &redskyv1beta1.Trial{
@@ -440,6 +644,17 @@ func TestFromClusterTrial(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "idempotency key",
+			in: &redskyv1beta1.Trial{
+				ObjectMeta: metav1.ObjectMeta{
+					UID: "1b9a6d4e-2f1c-4c53-9a56-0e2d8b4f7c11",
+				},
+			},
+			expectedOut: &redskyapi.TrialValues{
+				IdempotencyKey: "1b9a6d4e-2f1c-4c53-9a56-0e2d8b4f7c11",
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.desc, func(t *testing.T) {
@@ -197,6 +197,11 @@ func NewJob(t *redskyv1beta1.Trial, mode string) (*batchv1.Job, error) {
 		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, *v)
 	}
 
+	// Apply the pod template overlay from the experiment
+	if err := trial.ApplyPodTemplateOverlay(t, &job.Spec.Template); err != nil {
+		return nil, err
+	}
+
 	return job, nil
 }
 
@@ -17,7 +17,10 @@ limitations under the License.
 package template
 
 import (
+	"crypto/sha256"
+	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"text/template"
@@ -25,6 +28,9 @@ import (
 
 	"github.com/Masterminds/sprig"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // FuncMap returns the functions used for template evaluation
@@ -34,9 +40,13 @@ func FuncMap() template.FuncMap {
 	delete(f, "expandenv")
 
 	extra := template.FuncMap{
+		"configHash":       configHash,
+		"disruptionBudget": disruptionBudget,
 		"duration":         duration,
+		"eventCount":       eventCount,
 		"percent":          percent,
 		"resourceRequests": resourceRequests,
+		"topologySpread":   topologySpread,
 	}
 
 	for k, v := range extra {
@@ -46,6 +56,45 @@ func FuncMap() template.FuncMap {
 	return f
 }
 
+// configHash returns a short, stable hash of a value (e.g. the trial assignments); used as a pod template annotation
+// it forces workloads to restart (and reload their configuration) whenever the hashed value changes
+func configHash(value interface{}) (string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(b))[:16], nil
+}
+
+// disruptionBudget returns the JSON representation of pod disruption budget spec fields using either "minAvailable" or
+// "maxUnavailable" (the other field is cleared), the value is an integer or a percentage string (e.g. "50%")
+func disruptionBudget(field string, value interface{}) (string, error) {
+	var v intstr.IntOrString
+	switch tv := value.(type) {
+	case int:
+		return disruptionBudget(field, int64(tv))
+	case int64:
+		// Budgets are 32-bit integers, do not silently truncate larger values
+		if tv < math.MinInt32 || tv > math.MaxInt32 {
+			return "", fmt.Errorf("disruption budget value out of range: %d", tv)
+		}
+		v = intstr.FromInt(int(tv))
+	case string:
+		v = intstr.Parse(tv)
+	default:
+		return "", fmt.Errorf("invalid disruption budget value: %v", value)
+	}
+
+	spec := map[string]interface{}{"minAvailable": nil, "maxUnavailable": nil}
+	if _, ok := spec[field]; !ok {
+		return "", fmt.Errorf("invalid disruption budget field %q, expected minAvailable or maxUnavailable", field)
+	}
+	spec[field] = v
+
+	b, err := json.Marshal(spec)
+	return string(b), err
+}
+
 // duration returns a floating point number representing the number of seconds between two times
 func duration(start, completion time.Time) float64 {
 	if start.Before(completion) {
@@ -54,11 +103,43 @@ func duration(start, completion time.Time) float64 {
 	return 0
 }
 
+// eventCount returns the total number of occurrences of a list of (possibly aggregated) events
+func eventCount(events []corev1.Event) int64 {
+	var count int64
+	for i := range events {
+		if events[i].Count > 1 {
+			count += int64(events[i].Count)
+		} else {
+			count++
+		}
+	}
+	return count
+}
+
 // percent returns a percentage of an integer value using an integer (0-100) percentage
 func percent(value int64, percent int64) string {
 	return fmt.Sprintf("%d", int64(float64(value)*(float64(percent)/100.0)))
 }
 
+// topologySpread returns the JSON representation of a list containing a single topology spread constraint for the pods
+// matching a comma separated list of "key=value" labels
+func topologySpread(maxSkew int64, topologyKey, whenUnsatisfiable, matchLabels string) (string, error) {
+	ls, err := labels.ConvertSelectorToLabelsMap(matchLabels)
+	if err != nil {
+		return "", err
+	}
+
+	tsc := []corev1.TopologySpreadConstraint{{
+		MaxSkew:           int32(maxSkew),
+		TopologyKey:       topologyKey,
+		WhenUnsatisfiable: corev1.UnsatisfiableConstraintAction(whenUnsatisfiable),
+		LabelSelector:     &metav1.LabelSelector{MatchLabels: ls},
+	}}
+
+	b, err := json.Marshal(tsc)
+	return string(b), err
+}
+
 // resourceRequests uses a map of resource types to weights to calculate a weighted sum of the resource requests
 func resourceRequests(pods corev1.PodList, weights string) (float64, error) {
 	var totalResources float64
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package template
+
+import (
+	"time"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ReasonSuccessfulRescale is the reason of the events recorded each time a horizontal pod autoscaler changes the number
+// of replicas of its target
+const ReasonSuccessfulRescale = "SuccessfulRescale"
+
+// ScalingTarget is the target of "hpa" metrics
+type ScalingTarget struct {
+	metav1.TypeMeta `json:",inline"`
+	// HorizontalPodAutoscalers is the list of autoscalers matched by the metric selector
+	HorizontalPodAutoscalers autoscalingv1.HorizontalPodAutoscalerList `json:"horizontalPodAutoscalers"`
+	// Events is the list of events involving the matched autoscalers
+	Events corev1.EventList `json:"events"`
+}
+
+// DeepCopyObject returns a copy of the scaling target
+func (in *ScalingTarget) DeepCopyObject() runtime.Object {
+	out := &ScalingTarget{TypeMeta: in.TypeMeta}
+	in.HorizontalPodAutoscalers.DeepCopyInto(&out.HorizontalPodAutoscalers)
+	in.Events.DeepCopyInto(&out.Events)
+	return out
+}
+
+// scalingEvents returns the rescale events last observed between the start and completion times
+func scalingEvents(events []corev1.Event, start, completion time.Time) []corev1.Event {
+	var result []corev1.Event
+	for i := range events {
+		e := &events[i]
+		if e.Reason != ReasonSuccessfulRescale {
+			continue
+		}
+
+		ts := e.LastTimestamp.Time
+		if ts.IsZero() {
+			ts = e.EventTime.Time
+		}
+		if ts.IsZero() {
+			ts = e.FirstTimestamp.Time
+		}
+		if ts.Before(start) || (!completion.IsZero() && ts.After(completion)) {
+			continue
+		}
+
+		result = append(result, *e)
+	}
+	return result
+}
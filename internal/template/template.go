@@ -24,6 +24,7 @@ import (
 	"time"
 
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -34,24 +35,28 @@ import (
 type PatchData struct {
 	// Trial metadata
 	Trial metav1.ObjectMeta
-	// Trial assignments
-	Values map[string]int64
+	// Trial assignments, integers for numeric parameters and strings for categorical parameters
+	Values map[string]interface{}
 }
 
 // MetricData represents a trial during metric evaluation
 type MetricData struct {
 	// Trial metadata
 	Trial metav1.ObjectMeta
-	// The time at which the trial run started (possibly adjusted)
+	// The time at which the measurement started (possibly adjusted by the metric warmup or window)
 	StartTime time.Time
 	// The time at which the trial run completed
 	CompletionTime time.Time
-	// The duration of the trial run expressed as a Prometheus range value
+	// The duration of the measurement expressed as a Prometheus range value
 	Range string
-	// Trial assignments
-	Values map[string]int64
+	// Trial assignments, integers for numeric parameters and strings for categorical parameters
+	Values map[string]interface{}
 	// List of pods from the trial namespace (only available for "pods" type metrics)
 	Pods *corev1.PodList
+	// List of horizontal pod autoscalers from the trial namespace (only available for "hpa" type metrics)
+	HorizontalPodAutoscalers *autoscalingv1.HorizontalPodAutoscalerList
+	// Rescale events of the horizontal pod autoscalers during the measurement (only available for "hpa" type metrics)
+	ScalingEvents []corev1.Event
 }
 
 func newPatchData(t *redskyv1beta1.Trial) *PatchData {
@@ -59,34 +64,43 @@ func newPatchData(t *redskyv1beta1.Trial) *PatchData {
 
 	t.ObjectMeta.DeepCopyInto(&d.Trial)
 
-	d.Values = make(map[string]int64, len(t.Spec.Assignments))
-	for _, a := range t.Spec.Assignments {
-		d.Values[a.Name] = a.Value
-	}
+	d.Values = assignmentValues(t)
 
 	return d
 }
 
-func newMetricData(t *redskyv1beta1.Trial, target runtime.Object) *MetricData {
+// assignmentValues returns the trial assignments keyed by parameter name
+func assignmentValues(t *redskyv1beta1.Trial) map[string]interface{} {
+	values := make(map[string]interface{}, len(t.Spec.Assignments))
+	// Resolved values of percentage parameters replace the assigned percentages
+	for _, asm := range [][]redskyv1beta1.Assignment{t.Spec.Assignments, t.Status.ResolvedAssignments} {
+		for _, a := range asm {
+			if v, ok := a.Int64Value(); ok {
+				values[a.Name] = v
+			} else {
+				values[a.Name] = a.Value.StrVal
+			}
+		}
+	}
+	return values
+}
+
+func newMetricData(m *redskyv1beta1.Metric, t *redskyv1beta1.Trial, target runtime.Object) *MetricData {
 	d := &MetricData{}
 
 	t.ObjectMeta.DeepCopyInto(&d.Trial)
 
-	d.Values = make(map[string]int64, len(t.Spec.Assignments))
-	for _, a := range t.Spec.Assignments {
-		d.Values[a.Name] = a.Value
-	}
+	d.Values = assignmentValues(t)
 
 	if pods, ok := target.(*corev1.PodList); ok {
 		d.Pods = pods
 	}
 
-	if t.Status.StartTime != nil {
-		d.StartTime = t.Status.StartTime.Time
-	}
+	d.StartTime, d.CompletionTime = MeasurementWindow(m, t)
 
-	if t.Status.CompletionTime != nil {
-		d.CompletionTime = t.Status.CompletionTime.Time
+	if st, ok := target.(*ScalingTarget); ok {
+		d.HorizontalPodAutoscalers = &st.HorizontalPodAutoscalers
+		d.ScalingEvents = scalingEvents(st.Events.Items, d.StartTime, d.CompletionTime)
 	}
 
 	d.Range = fmt.Sprintf("%.0fs", math.Max(d.CompletionTime.Sub(d.StartTime).Seconds(), 0))
@@ -94,6 +108,33 @@ func newMetricData(t *redskyv1beta1.Trial, target runtime.Object) *MetricData {
 	return d
 }
 
+// MeasurementWindow returns the interval of the trial run a metric should be measured over, the start of the trial
+// run is moved forward to skip the metric warmup and to respect the metric window
+func MeasurementWindow(m *redskyv1beta1.Metric, t *redskyv1beta1.Trial) (time.Time, time.Time) {
+	var start, end time.Time
+	if t.Status.StartTime != nil {
+		start = t.Status.StartTime.Time
+	}
+	if t.Status.CompletionTime != nil {
+		end = t.Status.CompletionTime.Time
+	}
+
+	if m.Warmup != nil {
+		start = start.Add(m.Warmup.Duration)
+	}
+	if m.Window != nil && !end.IsZero() {
+		if ws := end.Add(-m.Window.Duration); ws.After(start) {
+			start = ws
+		}
+	}
+
+	// Never start the measurement after the trial run completed
+	if !end.IsZero() && start.After(end) {
+		start = end
+	}
+	return start, end
+}
+
 // Engine is used to render Go text templates
 type Engine struct {
 	FuncMap template.FuncMap
@@ -133,7 +174,7 @@ func (e *Engine) RenderHelmValue(helmValue *redskyv1beta1.HelmValue, trial *reds
 
 // RenderMetricQueries returns the metric query and the metric error query
 func (e *Engine) RenderMetricQueries(metric *redskyv1beta1.Metric, trial *redskyv1beta1.Trial, target runtime.Object) (string, string, error) {
-	data := newMetricData(trial, target)
+	data := newMetricData(metric, trial, target)
 	b1, err := e.render(metric.Name, metric.Query, data)
 	if err != nil {
 		return "", "", err
@@ -23,6 +23,7 @@ import (
 
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	"github.com/stretchr/testify/assert"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -62,6 +63,65 @@ func TestEngine(t *testing.T) {
 			},
 			expected: `{"metadata":{"labels":{"app":"testApp"}}}`,
 		},
+		{
+			desc: "patch assignments",
+			trial: &redskyv1beta1.Trial{
+				Spec: redskyv1beta1.TrialSpec{
+					Assignments: []redskyv1beta1.Assignment{
+						{Name: "replicas", Value: redskyv1beta1.NewAssignmentValue(3)},
+						{Name: "mode", Value: redskyv1beta1.NewCategoricalAssignmentValue("fast")},
+					},
+				},
+			},
+			input: &redskyv1beta1.PatchTemplate{
+				Patch: "spec:\n  replicas: {{ .Values.replicas }}\n  mode: {{ .Values.mode }}\n",
+			},
+			expected: `{"spec":{"mode":"fast","replicas":3}}`,
+		},
+		{
+			desc: "patch config hash",
+			trial: &redskyv1beta1.Trial{
+				Spec: redskyv1beta1.TrialSpec{
+					Assignments: []redskyv1beta1.Assignment{
+						{Name: "workers", Value: redskyv1beta1.NewAssignmentValue(4)},
+						{Name: "mode", Value: redskyv1beta1.NewCategoricalAssignmentValue("fast")},
+					},
+				},
+			},
+			input: &redskyv1beta1.PatchTemplate{
+				Patch: "metadata:\n  annotations:\n    hash: \"{{ configHash .Values }}\"\n",
+			},
+			expected: `{"metadata":{"annotations":{"hash":"025cf36ebbf9a32e"}}}`,
+		},
+		{
+			desc: "patch topology spread",
+			trial: &redskyv1beta1.Trial{
+				Spec: redskyv1beta1.TrialSpec{
+					Assignments: []redskyv1beta1.Assignment{
+						{Name: "skew", Value: redskyv1beta1.NewAssignmentValue(2)},
+						{Name: "when", Value: redskyv1beta1.NewCategoricalAssignmentValue("ScheduleAnyway")},
+					},
+				},
+			},
+			input: &redskyv1beta1.PatchTemplate{
+				Patch: "spec:\n  topologySpreadConstraints: {{ topologySpread .Values.skew \"zone\" .Values.when \"app=web\" }}\n",
+			},
+			expected: `{"spec":{"topologySpreadConstraints":[{"labelSelector":{"matchLabels":{"app":"web"}},"maxSkew":2,"topologyKey":"zone","whenUnsatisfiable":"ScheduleAnyway"}]}}`,
+		},
+		{
+			desc: "patch disruption budget",
+			trial: &redskyv1beta1.Trial{
+				Spec: redskyv1beta1.TrialSpec{
+					Assignments: []redskyv1beta1.Assignment{
+						{Name: "budget", Value: redskyv1beta1.NewCategoricalAssignmentValue("50%")},
+					},
+				},
+			},
+			input: &redskyv1beta1.PatchTemplate{
+				Patch: "spec: {{ disruptionBudget \"minAvailable\" .Values.budget }}\n",
+			},
+			expected: `{"spec":{"maxUnavailable":null,"minAvailable":"50%"}}`,
+		},
 		{
 			desc: "default helm",
 			trial: &redskyv1beta1.Trial{
@@ -146,6 +206,52 @@ func TestEngine(t *testing.T) {
 			},
 			expected: "25010",
 		},
+		{
+			desc: "hpa metric (scaling events)",
+			trial: &redskyv1beta1.Trial{
+				Status: redskyv1beta1.TrialStatus{
+					StartTime:      &now,
+					CompletionTime: &later,
+				},
+			},
+			input: &redskyv1beta1.Metric{
+				Name:  "testMetric",
+				Query: "{{eventCount .ScalingEvents}}",
+				Type:  redskyv1beta1.MetricHPA,
+			},
+			obj: &ScalingTarget{
+				Events: corev1.EventList{
+					Items: []corev1.Event{
+						{Reason: ReasonSuccessfulRescale, Count: 2, LastTimestamp: metav1.NewTime(now.Add(time.Second))},
+						{Reason: ReasonSuccessfulRescale, Count: 1, LastTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+						{Reason: "FailedGetResourceMetric", Count: 1, LastTimestamp: metav1.NewTime(now.Add(time.Second))},
+					},
+				},
+			},
+			expected: "2",
+		},
+		{
+			desc: "hpa metric (replicas)",
+			trial: &redskyv1beta1.Trial{
+				Status: redskyv1beta1.TrialStatus{
+					StartTime:      &now,
+					CompletionTime: &later,
+				},
+			},
+			input: &redskyv1beta1.Metric{
+				Name:  "testMetric",
+				Query: "{{(index .HorizontalPodAutoscalers.Items 0).Status.CurrentReplicas}}",
+				Type:  redskyv1beta1.MetricHPA,
+			},
+			obj: &ScalingTarget{
+				HorizontalPodAutoscalers: autoscalingv1.HorizontalPodAutoscalerList{
+					Items: []autoscalingv1.HorizontalPodAutoscaler{
+						{Status: autoscalingv1.HorizontalPodAutoscalerStatus{CurrentReplicas: 3}},
+					},
+				},
+			},
+			expected: "3",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -170,3 +276,108 @@ func TestEngine(t *testing.T) {
 		})
 	}
 }
+
+func TestMeasurementWindow(t *testing.T) {
+	start := metav1.NewTime(time.Date(2020, time.June, 1, 12, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(30 * time.Minute))
+	trial := &redskyv1beta1.Trial{
+		Status: redskyv1beta1.TrialStatus{
+			StartTime:      &start,
+			CompletionTime: &end,
+		},
+	}
+
+	testCases := []struct {
+		desc          string
+		warmup        time.Duration
+		window        time.Duration
+		expectedStart time.Time
+	}{
+		{
+			desc:          "full duration",
+			expectedStart: start.Time,
+		},
+		{
+			desc:          "warmup",
+			warmup:        5 * time.Minute,
+			expectedStart: start.Add(5 * time.Minute),
+		},
+		{
+			desc:          "window",
+			window:        10 * time.Minute,
+			expectedStart: end.Add(-10 * time.Minute),
+		},
+		{
+			desc:          "window longer than warmup allows",
+			warmup:        25 * time.Minute,
+			window:        10 * time.Minute,
+			expectedStart: start.Add(25 * time.Minute),
+		},
+		{
+			desc:          "warmup exceeds run",
+			warmup:        time.Hour,
+			expectedStart: end.Time,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			m := &redskyv1beta1.Metric{}
+			if tc.warmup > 0 {
+				m.Warmup = &metav1.Duration{Duration: tc.warmup}
+			}
+			if tc.window > 0 {
+				m.Window = &metav1.Duration{Duration: tc.window}
+			}
+			s, e := MeasurementWindow(m, trial)
+			assert.Equal(t, tc.expectedStart, s)
+			assert.Equal(t, end.Time, e)
+		})
+	}
+}
+
+func TestDisruptionBudget(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		field    string
+		value    interface{}
+		expected string
+		err      string
+	}{
+		{
+			desc:     "integer",
+			field:    "minAvailable",
+			value:    2,
+			expected: `{"maxUnavailable":null,"minAvailable":2}`,
+		},
+		{
+			desc:     "percent",
+			field:    "maxUnavailable",
+			value:    "25%",
+			expected: `{"maxUnavailable":"25%","minAvailable":null}`,
+		},
+		{
+			desc:  "64-bit assignment",
+			field: "minAvailable",
+			value: int64(1 << 40),
+			err:   "disruption budget value out of range: 1099511627776",
+		},
+		{
+			desc:  "unknown field",
+			field: "replicas",
+			value: 1,
+			err:   `invalid disruption budget field "replicas", expected minAvailable or maxUnavailable`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			actual, err := disruptionBudget(tc.field, tc.value)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlspolicy
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// fipsOnly is set when the binary was built against a FIPS validated crypto module
+var fipsOnly bool
+
+// Policy describes the restrictions placed on outbound TLS connections
+type Policy struct {
+	// MinVersion is the minimum TLS version to negotiate, e.g. "1.2"
+	MinVersion string
+	// CipherSuites is the list of allowed cipher suite names, an empty list allows the Go defaults
+	CipherSuites []string
+	// FIPS restricts connections to FIPS approved versions and cipher suites
+	FIPS bool
+}
+
+// FIPSOnly returns true if the current binary was built in FIPS only mode
+func FIPSOnly() bool {
+	return fipsOnly
+}
+
+// TLSConfig returns a new TLS configuration which enforces this policy
+func (p *Policy) TLSConfig() (*tls.Config, error) {
+	c := &tls.Config{}
+
+	if p.MinVersion != "" {
+		v, err := parseVersion(p.MinVersion)
+		if err != nil {
+			return nil, err
+		}
+		c.MinVersion = v
+	}
+
+	for _, name := range p.CipherSuites {
+		id, err := parseCipherSuite(name)
+		if err != nil {
+			return nil, err
+		}
+		c.CipherSuites = append(c.CipherSuites, id)
+	}
+
+	if p.FIPS || fipsOnly {
+		if c.MinVersion < tls.VersionTLS12 {
+			c.MinVersion = tls.VersionTLS12
+		}
+		if !fipsOnly {
+			// The TLS 1.3 cipher suites are not configurable, without a FIPS validated crypto module the only way to
+			// exclude the non-approved suites is to never negotiate TLS 1.3
+			if c.MinVersion > tls.VersionTLS12 {
+				return nil, fmt.Errorf("TLS 1.3 requires a FIPS only build")
+			}
+			c.MaxVersion = tls.VersionTLS12
+		}
+		if len(c.CipherSuites) == 0 {
+			c.CipherSuites = fipsCipherSuites
+		}
+		for _, id := range c.CipherSuites {
+			if !isFIPSCipherSuite(id) {
+				return nil, fmt.Errorf("cipher suite is not FIPS approved: %s", tls.CipherSuiteName(id))
+			}
+		}
+		c.CurvePreferences = []tls.CurveID{tls.CurveP256, tls.CurveP384}
+	}
+
+	return c, nil
+}
+
+// Apply configures the default HTTP transport to use this policy; since all outbound connections (Red Sky API,
+// Prometheus, JSONPath, etc.) are eventually made using the default transport, this must be invoked before any
+// clients are created.
+func (p *Policy) Apply() error {
+	c, err := p.TLSConfig()
+	if err != nil {
+		return err
+	}
+
+	t, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("unable to apply TLS policy to default transport")
+	}
+	t.TLSClientConfig = c
+	return nil
+}
+
+// SplitCipherSuites returns the cipher suite names from a comma-separated list, ignoring surrounding whitespace and
+// empty entries
+func SplitCipherSuites(s string) []string {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func parseVersion(s string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToUpper(s), "TLS") {
+	case "1.0", "10":
+		return tls.VersionTLS10, nil
+	case "1.1", "11":
+		return tls.VersionTLS11, nil
+	case "1.2", "12":
+		return tls.VersionTLS12, nil
+	case "1.3", "13":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unknown TLS version: %s", s)
+}
+
+func parseCipherSuite(s string) (uint16, error) {
+	for _, cs := range tls.CipherSuites() {
+		if cs.Name == s {
+			return cs.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown or insecure cipher suite: %s", s)
+}
+
+// fipsCipherSuites are the TLS 1.2 cipher suites approved for use in FIPS mode
+var fipsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+func isFIPSCipherSuite(id uint16) bool {
+	for _, fid := range fipsCipherSuites {
+		if id == fid {
+			return true
+		}
+	}
+	return false
+}
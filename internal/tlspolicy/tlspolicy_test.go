@@ -0,0 +1,131 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlspolicy
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy_TLSConfig(t *testing.T) {
+	cases := []struct {
+		desc     string
+		policy   Policy
+		expected *tls.Config
+		err      string
+	}{
+		{
+			desc:     "default",
+			expected: &tls.Config{},
+		},
+		{
+			desc:     "minVersion",
+			policy:   Policy{MinVersion: "1.2"},
+			expected: &tls.Config{MinVersion: tls.VersionTLS12},
+		},
+		{
+			desc:   "cipherSuites",
+			policy: Policy{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}},
+			expected: &tls.Config{
+				CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256},
+			},
+		},
+		{
+			desc:   "fips",
+			policy: Policy{FIPS: true},
+			expected: &tls.Config{
+				MinVersion:       tls.VersionTLS12,
+				MaxVersion:       tls.VersionTLS12,
+				CipherSuites:     fipsCipherSuites,
+				CurvePreferences: []tls.CurveID{tls.CurveP256, tls.CurveP384},
+			},
+		},
+		{
+			desc:   "fipsCipherSuite",
+			policy: Policy{FIPS: true, CipherSuites: []string{"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA"}},
+			err:    "cipher suite is not FIPS approved: TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA",
+		},
+		{
+			desc:   "fipsMinVersion",
+			policy: Policy{FIPS: true, MinVersion: "1.3"},
+			err:    "TLS 1.3 requires a FIPS only build",
+		},
+		{
+			desc:   "unknownVersion",
+			policy: Policy{MinVersion: "2.0"},
+			err:    "unknown TLS version: 2.0",
+		},
+		{
+			desc:   "unknownCipherSuite",
+			policy: Policy{CipherSuites: []string{"foo"}},
+			err:    "unknown or insecure cipher suite: foo",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			actual, err := c.policy.TLSConfig()
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSplitCipherSuites(t *testing.T) {
+	cases := []struct {
+		desc     string
+		list     string
+		expected []string
+	}{
+		{
+			desc: "empty",
+		},
+		{
+			desc:     "single",
+			list:     "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256",
+			expected: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		},
+		{
+			desc:     "multiple",
+			list:     "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+			expected: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+		},
+		{
+			desc:     "whitespace",
+			list:     " TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256 ,\tTLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384\n",
+			expected: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"},
+		},
+		{
+			desc:     "empty entries",
+			list:     ",TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,, ,",
+			expected: []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		},
+		{
+			desc: "only separators",
+			list: " , ,",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.expected, SplitCipherSuites(c.list))
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"math"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+)
+
+// NearestCatalogEntry returns the catalog entry whose capacity is closest to the supplied value, ties are resolved
+// using the entry with the smaller capacity
+func NearestCatalogEntry(catalog []redskyv1beta1.CatalogEntry, v int64) *redskyv1beta1.CatalogEntry {
+	var nearest *redskyv1beta1.CatalogEntry
+	var nearestCapacity, nearestDistance float64
+	for i := range catalog {
+		c := float64(catalog[i].Capacity.MilliValue()) / 1000
+		d := math.Abs(c - float64(v))
+		if nearest == nil || d < nearestDistance || (d == nearestDistance && c < nearestCapacity) {
+			nearest, nearestCapacity, nearestDistance = &catalog[i], c, d
+		}
+	}
+	return nearest
+}
+
+// ApplyCatalogs records the catalog entry selected for each assignment to a parameter with a catalog
+func ApplyCatalogs(exp *redskyv1beta1.Experiment, t *redskyv1beta1.Trial) {
+	for i := range exp.Spec.Parameters {
+		p := &exp.Spec.Parameters[i]
+		if len(p.Catalog) == 0 {
+			continue
+		}
+		for j := range t.Spec.Assignments {
+			if t.Spec.Assignments[j].Name != p.Name {
+				continue
+			}
+			if v, ok := t.Spec.Assignments[j].Int64Value(); ok {
+				if e := NearestCatalogEntry(p.Catalog, v); e != nil {
+					setResolvedAssignment(t, redskyv1beta1.Assignment{Name: p.Name, Value: redskyv1beta1.NewCategoricalAssignmentValue(e.Name)})
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestApplyCatalogs(t *testing.T) {
+	catalog := []redskyv1beta1.CatalogEntry{
+		{Name: "m5.large", Capacity: resource.MustParse("8")},
+		{Name: "m5.xlarge", Capacity: resource.MustParse("16")},
+		{Name: "m5.2xlarge", Capacity: resource.MustParse("32")},
+	}
+
+	cases := []struct {
+		desc     string
+		value    int64
+		expected string
+	}{
+		{desc: "exact", value: 16, expected: "m5.xlarge"},
+		{desc: "below", value: 1, expected: "m5.large"},
+		{desc: "above", value: 100, expected: "m5.2xlarge"},
+		{desc: "nearest", value: 26, expected: "m5.2xlarge"},
+		{desc: "tie", value: 12, expected: "m5.large"},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			exp := &redskyv1beta1.Experiment{}
+			exp.Spec.Parameters = []redskyv1beta1.Parameter{{Name: "capacity", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(100), Catalog: catalog}, {Name: "replicas"}}
+			tt := &redskyv1beta1.Trial{}
+			tt.Spec.Assignments = []redskyv1beta1.Assignment{
+				{Name: "capacity", Value: redskyv1beta1.NewAssignmentValue(c.value)},
+				{Name: "replicas", Value: redskyv1beta1.NewAssignmentValue(2)},
+			}
+
+			ApplyCatalogs(exp, tt)
+			assert.Equal(t, []redskyv1beta1.Assignment{{Name: "capacity", Value: redskyv1beta1.NewCategoricalAssignmentValue(c.expected)}}, tt.Status.ResolvedAssignments)
+		})
+	}
+}
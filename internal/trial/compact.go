@@ -0,0 +1,131 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/meta"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// MaxSize is the approximate size limit of a trial, the default etcd request size limit
+	MaxSize = 1536 * 1024
+	// WarningSize is the size at which a trial is considered to be nearing the limit
+	WarningSize = MaxSize * 3 / 4
+
+	// maxMessageLength is the length at which status messages are truncated
+	maxMessageLength = 2048
+	// maxInlinePatchLength is the length at which applied patch data is moved out of the trial
+	maxInlinePatchLength = 4096
+	// truncatedSuffix is appended to truncated status messages
+	truncatedSuffix = "... (truncated)"
+)
+
+// Size returns the approximate number of bytes required to store the trial
+func Size(t *redskyv1beta1.Trial) int {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// Compact truncates long status messages on the trial; returns true only if changes were necessary
+func Compact(t *redskyv1beta1.Trial) bool {
+	var dirty bool
+	for i := range t.Status.Conditions {
+		dirty = truncate(&t.Status.Conditions[i].Message) || dirty
+	}
+	for i := range t.Status.MetricCollection {
+		dirty = truncate(&t.Status.MetricCollection[i].Message) || dirty
+	}
+	return dirty
+}
+
+// NewPatchArtifacts returns a config map holding the data of large applied patches, the patch operations are updated to
+// reference the config map instead of holding the data inline; returns nil if there is nothing to compact
+func NewPatchArtifacts(t *redskyv1beta1.Trial) *corev1.ConfigMap {
+	cm := &corev1.ConfigMap{}
+	cm.Name = t.Name + "-patches"
+	cm.Namespace = t.Namespace
+	meta.AddLabel(cm, redskyv1beta1.LabelExperiment, t.ExperimentNamespacedName().Name)
+	meta.AddLabel(cm, redskyv1beta1.LabelTrial, t.Name)
+	meta.AddLabel(cm, redskyv1beta1.LabelTrialRole, "patches")
+
+	for i := range t.Status.PatchOperations {
+		po := &t.Status.PatchOperations[i]
+
+		// Patches against the trial job are needed to create the job, unapplied patches are still needed
+		if po.AttemptsRemaining > 0 || len(po.Data) <= maxInlinePatchLength || IsTrialJobReference(t, &po.TargetRef) {
+			continue
+		}
+
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		key := fmt.Sprintf("patch-%d", i)
+		cm.Data[key] = string(po.Data)
+		po.Data = nil
+		po.DataRef = &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: cm.Name},
+			Key:                  key,
+		}
+	}
+
+	if len(cm.Data) == 0 {
+		return nil
+	}
+	return cm
+}
+
+// PatchData returns the raw data of the patch operation, reading it from the referenced config map if the patch has
+// been compacted out of the trial; the reader should not be cached since config maps are not watched
+func PatchData(ctx context.Context, r client.Reader, t *redskyv1beta1.Trial, po *redskyv1beta1.PatchOperation) ([]byte, error) {
+	if po.DataRef == nil {
+		return po.Data, nil
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: t.Namespace, Name: po.DataRef.Name}, cm); err != nil {
+		return nil, err
+	}
+	data, ok := cm.Data[po.DataRef.Key]
+	if !ok {
+		return nil, fmt.Errorf("missing patch data %q in config map %q", po.DataRef.Key, po.DataRef.Name)
+	}
+	return []byte(data), nil
+}
+
+// truncate shortens the message in place; returns true only if the message was changed
+func truncate(msg *string) bool {
+	if len(*msg) <= maxMessageLength {
+		return false
+	}
+	n := maxMessageLength - len(truncatedSuffix)
+	for n > 0 && !utf8.RuneStart((*msg)[n]) {
+		n--
+	}
+	*msg = (*msg)[:n] + truncatedSuffix
+	return true
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCompact(t *testing.T) {
+	cases := []struct {
+		desc    string
+		message string
+		dirty   bool
+	}{
+		{
+			desc:    "short",
+			message: "failed",
+		},
+		{
+			desc:    "long",
+			message: strings.Repeat("x", maxMessageLength+1),
+			dirty:   true,
+		},
+		{
+			desc:    "multibyte",
+			message: strings.Repeat("é", maxMessageLength),
+			dirty:   true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			tt := &redskyv1beta1.Trial{
+				Status: redskyv1beta1.TrialStatus{
+					Conditions:       []redskyv1beta1.TrialCondition{{Message: c.message}},
+					MetricCollection: []redskyv1beta1.MetricCollection{{Message: c.message}},
+				},
+			}
+			assert.Equal(t, c.dirty, Compact(tt))
+			for _, msg := range []string{tt.Status.Conditions[0].Message, tt.Status.MetricCollection[0].Message} {
+				assert.True(t, len(msg) <= maxMessageLength)
+				assert.Equal(t, c.dirty, strings.HasSuffix(msg, truncatedSuffix))
+				assert.True(t, utf8.ValidString(msg))
+			}
+		})
+	}
+}
+
+func TestNewPatchArtifacts(t *testing.T) {
+	large := []byte(strings.Repeat("x", maxInlinePatchLength+1))
+	tt := &redskyv1beta1.Trial{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-001", Namespace: "default"},
+		Status: redskyv1beta1.TrialStatus{
+			PatchOperations: []redskyv1beta1.PatchOperation{
+				{TargetRef: corev1.ObjectReference{Kind: "Deployment", Name: "small"}, Data: []byte("{}")},
+				{TargetRef: corev1.ObjectReference{Kind: "Deployment", Name: "pending"}, Data: large, AttemptsRemaining: 3},
+				{TargetRef: corev1.ObjectReference{Kind: "Job", Name: "test-001"}, Data: large},
+				{TargetRef: corev1.ObjectReference{Kind: "Deployment", Name: "large"}, Data: large},
+			},
+		},
+	}
+
+	cm := NewPatchArtifacts(tt)
+	if assert.NotNil(t, cm) {
+		assert.Equal(t, "test-001-patches", cm.Name)
+		assert.Equal(t, map[string]string{"patch-3": string(large)}, cm.Data)
+	}
+	assert.Equal(t, []byte("{}"), tt.Status.PatchOperations[0].Data)
+	assert.Equal(t, large, tt.Status.PatchOperations[1].Data)
+	assert.Equal(t, large, tt.Status.PatchOperations[2].Data)
+	assert.Nil(t, tt.Status.PatchOperations[3].Data)
+	assert.Equal(t, &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "test-001-patches"},
+		Key:                  "patch-3",
+	}, tt.Status.PatchOperations[3].DataRef)
+
+	assert.Nil(t, NewPatchArtifacts(tt))
+}
+
+func TestPatchData(t *testing.T) {
+	large := []byte(strings.Repeat("x", maxInlinePatchLength+1))
+	tt := &redskyv1beta1.Trial{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-001", Namespace: "default"},
+		Status: redskyv1beta1.TrialStatus{
+			PatchOperations: []redskyv1beta1.PatchOperation{
+				{TargetRef: corev1.ObjectReference{Kind: "Deployment", Name: "small"}, Data: []byte("{}")},
+				{TargetRef: corev1.ObjectReference{Kind: "Deployment", Name: "large"}, Data: large},
+			},
+		},
+	}
+	cm := NewPatchArtifacts(tt)
+	if !assert.NotNil(t, cm) {
+		return
+	}
+
+	ctx := context.TODO()
+	r := fake.NewFakeClient(cm)
+	for i := range tt.Status.PatchOperations {
+		data, err := PatchData(ctx, r, tt, &tt.Status.PatchOperations[i])
+		if assert.NoError(t, err) {
+			assert.Equal(t, [][]byte{[]byte("{}"), large}[i], data)
+		}
+	}
+
+	tt.Status.PatchOperations[1].DataRef.Key = "patch-9"
+	_, err := PatchData(ctx, r, tt, &tt.Status.PatchOperations[1])
+	assert.Error(t, err)
+
+	_, err = PatchData(ctx, fake.NewFakeClient(), tt, &tt.Status.PatchOperations[1])
+	assert.Error(t, err)
+}
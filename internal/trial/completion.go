@@ -0,0 +1,98 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CheckExitCodes returns the failure mapped to the exit code of a terminated trial run container in the pod
+func CheckExitCodes(policy *redskyv1beta1.TrialCompletionPolicy, pod *corev1.Pod) *redskyv1beta1.ExitCodeFailure {
+	if policy == nil {
+		return nil
+	}
+
+	for i := range pod.Status.ContainerStatuses {
+		cs := &pod.Status.ContainerStatuses[i]
+		if cs.State.Terminated == nil || IsSidecarContainer(cs.Name) {
+			continue
+		}
+		for j := range policy.ExitCodes {
+			if policy.ExitCodes[j].ExitCode == cs.State.Terminated.ExitCode {
+				f := policy.ExitCodes[j]
+				if f.Message == "" {
+					f.Message = fmt.Sprintf("Container %s exited with code %d", cs.Name, f.ExitCode)
+				}
+				return &f
+			}
+		}
+	}
+	return nil
+}
+
+// HasLogMarkers checks to see if the policy requires the trial run logs to be inspected
+func HasLogMarkers(policy *redskyv1beta1.TrialCompletionPolicy) bool {
+	return policy != nil && (policy.SuccessMarker != "" || policy.FailureMarker != "")
+}
+
+// LogContainer returns the name of the container in the pod whose logs should be searched for markers
+func LogContainer(policy *redskyv1beta1.TrialCompletionPolicy, pod *corev1.Pod) string {
+	if policy != nil && policy.Container != "" {
+		return policy.Container
+	}
+	for i := range pod.Spec.Containers {
+		if !IsSidecarContainer(pod.Spec.Containers[i].Name) {
+			return pod.Spec.Containers[i].Name
+		}
+	}
+	return ""
+}
+
+// CheckLogMarkers searches the trial run logs for the success and failure markers of the policy, returning the
+// reason and message of the failure or empty strings if the logs indicate success
+func CheckLogMarkers(policy *redskyv1beta1.TrialCompletionPolicy, logs io.Reader) (string, string, error) {
+	if !HasLogMarkers(policy) {
+		return "", "", nil
+	}
+
+	var success bool
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if policy.FailureMarker != "" && strings.Contains(line, policy.FailureMarker) {
+			return "FailureMarker", strings.TrimSpace(line), nil
+		}
+		if policy.SuccessMarker != "" && strings.Contains(line, policy.SuccessMarker) {
+			success = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", "", err
+	}
+
+	if policy.SuccessMarker != "" && !success {
+		return "MissingSuccessMarker", fmt.Sprintf("Trial run logs did not contain %q", policy.SuccessMarker), nil
+	}
+	return "", "", nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"strings"
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCheckExitCodes(t *testing.T) {
+	policy := &redskyv1beta1.TrialCompletionPolicy{
+		ExitCodes: []redskyv1beta1.ExitCodeFailure{
+			{ExitCode: 3, Reason: "SLOViolation", Message: "Latency objective was not met"},
+			{ExitCode: 4, Reason: "HarnessError"},
+		},
+	}
+	exited := func(code int32) *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "default", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: code}}},
+		}}}
+	}
+
+	cases := []struct {
+		desc     string
+		policy   *redskyv1beta1.TrialCompletionPolicy
+		pod      *corev1.Pod
+		expected *redskyv1beta1.ExitCodeFailure
+	}{
+		{
+			desc: "no policy",
+			pod:  exited(3),
+		},
+		{
+			desc:   "unmapped",
+			policy: policy,
+			pod:    exited(0),
+		},
+		{
+			desc:     "mapped",
+			policy:   policy,
+			pod:      exited(3),
+			expected: &redskyv1beta1.ExitCodeFailure{ExitCode: 3, Reason: "SLOViolation", Message: "Latency objective was not met"},
+		},
+		{
+			desc:     "default message",
+			policy:   policy,
+			pod:      exited(4),
+			expected: &redskyv1beta1.ExitCodeFailure{ExitCode: 4, Reason: "HarnessError", Message: "Container default exited with code 4"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.expected, CheckExitCodes(c.policy, c.pod))
+		})
+	}
+}
+
+func TestCheckLogMarkers(t *testing.T) {
+	cases := []struct {
+		desc    string
+		policy  *redskyv1beta1.TrialCompletionPolicy
+		logs    string
+		reason  string
+		message string
+	}{
+		{
+			desc: "no markers",
+			logs: "FAIL\n",
+		},
+		{
+			desc:   "success",
+			policy: &redskyv1beta1.TrialCompletionPolicy{SuccessMarker: "PASS", FailureMarker: "FAIL"},
+			logs:   "starting\nall checks PASS\n",
+		},
+		{
+			desc:    "failure",
+			policy:  &redskyv1beta1.TrialCompletionPolicy{SuccessMarker: "PASS", FailureMarker: "FAIL"},
+			logs:    "starting\n  FAIL: error rate too high  \nPASS\n",
+			reason:  "FailureMarker",
+			message: "FAIL: error rate too high",
+		},
+		{
+			desc:    "missing success",
+			policy:  &redskyv1beta1.TrialCompletionPolicy{SuccessMarker: "PASS"},
+			logs:    "starting\n",
+			reason:  "MissingSuccessMarker",
+			message: `Trial run logs did not contain "PASS"`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			reason, message, err := CheckLogMarkers(c.policy, strings.NewReader(c.logs))
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.reason, reason)
+				assert.Equal(t, c.message, message)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// UpdateResourceHours records the resource hours consumed by the trial workload pods and their cost according to the
+// supplied cost model; the workload is considered to consume its requested resources from the creation of the trial
+// (when the patches are applied) until the trial run completes
+func UpdateResourceHours(t *redskyv1beta1.Trial, pods *corev1.PodList, model *redskyv1beta1.CostModel) error {
+	if t.Status.CompletionTime == nil {
+		return nil
+	}
+
+	hours := t.Status.CompletionTime.Sub(t.CreationTimestamp.Time).Hours()
+	if hours < 0 {
+		hours = 0
+	}
+
+	resourceHours := ResourceHours(pods, hours)
+	var cost string
+	if model.Weights != "" {
+		c, err := Cost(resourceHours, model.Weights)
+		if err != nil {
+			return err
+		}
+		cost = FormatCost(c)
+	}
+
+	t.Status.ResourceHours = resourceHours
+	t.Status.Cost = cost
+	return nil
+}
+
+// ResourceHours returns the sum of the container resource requests of the supplied pods multiplied by a number of hours
+func ResourceHours(pods *corev1.PodList, hours float64) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for i := range pods.Items {
+		for _, c := range pods.Items[i].Spec.Containers {
+			for name, q := range c.Resources.Requests {
+				total := requests[name]
+				total.Add(q)
+				requests[name] = total
+			}
+		}
+	}
+
+	resourceHours := make(corev1.ResourceList, len(requests))
+	for name, q := range requests {
+		resourceHours[name] = *resource.NewMilliQuantity(int64(math.Round(float64(q.MilliValue())*hours)), q.Format)
+	}
+	return resourceHours
+}
+
+// Cost returns the weighted sum of the resource hours, the weights use the same "name=weight" syntax and units (i.e.
+// the weight is applied to the milli-value of the resource) as the `resourceRequests` metric function
+func Cost(resourceHours corev1.ResourceList, weights string) (float64, error) {
+	var cost float64
+	for _, entry := range strings.Split(weights, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		p := strings.SplitN(entry, "=", 2)
+		if len(p) != 2 {
+			return 0, fmt.Errorf("invalid cost model weight %q", entry)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(p[1]), 64)
+		if err != nil {
+			return 0, fmt.Errorf("unable to parse cost model weight for %s", p[0])
+		}
+
+		if q, ok := resourceHours[corev1.ResourceName(strings.TrimSpace(p[0]))]; ok {
+			cost += weight * float64(q.MilliValue())
+		}
+	}
+	return cost, nil
+}
+
+// FormatCost returns the string representation of a cost, rounded to avoid floating point noise
+func FormatCost(cost float64) string {
+	return strconv.FormatFloat(math.Round(cost*1e6)/1e6, 'f', -1, 64)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"testing"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestUpdateResourceHours(t *testing.T) {
+	start := metav1.NewTime(time.Date(2020, time.June, 1, 12, 0, 0, 0, time.UTC))
+	completion := metav1.NewTime(start.Add(90 * time.Minute))
+	container := func(cpu, memory string) corev1.Container {
+		return corev1.Container{Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}}}
+	}
+	pods := &corev1.PodList{Items: []corev1.Pod{
+		{Spec: corev1.PodSpec{Containers: []corev1.Container{container("500m", "512Mi"), container("250m", "256Mi")}}},
+		{Spec: corev1.PodSpec{Containers: []corev1.Container{container("250m", "256Mi")}}},
+	}}
+
+	cases := []struct {
+		desc       string
+		completion *metav1.Time
+		weights    string
+		cpu        string
+		memory     string
+		cost       string
+		err        bool
+	}{
+		{
+			desc: "running",
+		},
+		{
+			desc:       "no weights",
+			completion: &completion,
+			cpu:        "1500m",
+			memory:     "1536Mi",
+		},
+		{
+			desc:       "weights",
+			completion: &completion,
+			weights:    "cpu=0.01, memory=0.000000000001",
+			cpu:        "1500m",
+			memory:     "1536Mi",
+			cost:       "16.610613",
+		},
+		{
+			desc:       "invalid weights",
+			completion: &completion,
+			weights:    "cpu",
+			err:        true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			tt := &redskyv1beta1.Trial{}
+			tt.CreationTimestamp = start
+			tt.Status.CompletionTime = c.completion
+
+			err := UpdateResourceHours(tt, pods, &redskyv1beta1.CostModel{Weights: c.weights})
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				if c.cpu == "" {
+					assert.Nil(t, tt.Status.ResourceHours)
+				} else {
+					assert.Equal(t, c.cpu, tt.Status.ResourceHours.Cpu().String())
+					assert.Equal(t, c.memory, tt.Status.ResourceHours.Memory().String())
+				}
+				assert.Equal(t, c.cost, tt.Status.Cost)
+			}
+		})
+	}
+}
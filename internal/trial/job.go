@@ -19,6 +19,7 @@ package trial
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
@@ -28,10 +29,11 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/yaml"
 )
 
 // NewJob returns a new trial run job from the template on the trial
-func NewJob(t *redskyv1beta1.Trial) *batchv1.Job {
+func NewJob(t *redskyv1beta1.Trial) (*batchv1.Job, error) {
 	job := &batchv1.Job{}
 
 	// Start with the job template
@@ -77,10 +79,53 @@ func NewJob(t *redskyv1beta1.Trial) *batchv1.Job {
 		addDefaultContainer(t, job)
 	}
 
+	// Allow the trial run containers to signal the end of the warm-up
+	AddWarmupProbe(t, &job.Spec.Template)
+
+	// Opt out of service mesh sidecar injection if requested
+	if SidecarPolicy(t) == SidecarPolicyDisableInjection {
+		disableSidecarInjection(&job.Spec.Template)
+	}
+
+	// Apply the pod template overlay from the experiment
+	if err := ApplyPodTemplateOverlay(t, &job.Spec.Template); err != nil {
+		return nil, err
+	}
+
 	// Check to see if there is patch for the (as of yet, non-existent) trial job
 	job = patchSelf(t, job)
 
-	return job
+	return job, nil
+}
+
+// ApplyPodTemplateOverlay applies the strategic merge overlay from the trial to the supplied pod template
+func ApplyPodTemplateOverlay(t *redskyv1beta1.Trial, template *corev1.PodTemplateSpec) error {
+	if strings.TrimSpace(t.Spec.PodTemplateOverlay) == "" {
+		return nil
+	}
+
+	overlay, err := yaml.ToJSON([]byte(t.Spec.PodTemplateOverlay))
+	if err != nil {
+		return fmt.Errorf("invalid pod template overlay: %w", err)
+	}
+
+	original, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+
+	patched, err := strategicpatch.StrategicMergePatch(original, overlay, &corev1.PodTemplateSpec{})
+	if err != nil {
+		return fmt.Errorf("unable to apply pod template overlay: %w", err)
+	}
+
+	result := &corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(patched, result); err != nil {
+		return err
+	}
+
+	*template = *result
+	return nil
 }
 
 func addDefaultContainer(t *redskyv1beta1.Trial, job *batchv1.Job) {
@@ -92,6 +137,9 @@ func addDefaultContainer(t *redskyv1beta1.Trial, job *batchv1.Job) {
 	if t.Spec.StartTimeOffset != nil {
 		s = &metav1.Duration{Duration: s.Duration + t.Spec.StartTimeOffset.Duration}
 	}
+	if t.Spec.Warmup != nil && t.Spec.Warmup.Duration != nil {
+		s = &metav1.Duration{Duration: s.Duration + t.Spec.Warmup.Duration.Duration}
+	}
 
 	// Add a busybox container that just runs sleep
 	job.Spec.Template.Spec.Containers = []corev1.Container{
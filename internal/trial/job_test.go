@@ -0,0 +1,95 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestApplyPodTemplateOverlay(t *testing.T) {
+	cases := []struct {
+		desc     string
+		overlay  string
+		template corev1.PodTemplateSpec
+		expected corev1.PodTemplateSpec
+		err      bool
+	}{
+		{
+			desc: "empty",
+			template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever},
+			},
+			expected: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever},
+			},
+		},
+		{
+			desc:    "metadata",
+			overlay: "metadata:\n  labels:\n    cost-center: perf\n  annotations:\n    sidecar.istio.io/inject: \"false\"\n",
+			template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+				Spec:       corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever},
+			},
+			expected: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"app": "test", "cost-center": "perf"},
+					Annotations: map[string]string{"sidecar.istio.io/inject": "false"},
+				},
+				Spec: corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever},
+			},
+		},
+		{
+			desc:    "image pull secrets",
+			overlay: `{"spec":{"imagePullSecrets":[{"name":"registry"}]}}`,
+			template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "main", Image: "busybox"}},
+				},
+			},
+			expected: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers:       []corev1.Container{{Name: "main", Image: "busybox"}},
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry"}},
+				},
+			},
+		},
+		{
+			desc:    "invalid",
+			overlay: "spec: [",
+			err:     true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			tr := &redskyv1beta1.Trial{}
+			tr.Spec.PodTemplateOverlay = c.overlay
+			err := ApplyPodTemplateOverlay(tr, &c.template)
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, c.template)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"strconv"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IsPending checks to see if the specified trial has been suggested but has not yet started
+func IsPending(t *redskyv1beta1.Trial) bool {
+	if IsFinished(t) || !t.GetDeletionTimestamp().IsZero() {
+		return false
+	}
+	return !CheckCondition(&t.Status, redskyv1beta1.TrialPatched, corev1.ConditionTrue)
+}
+
+// Priority returns the priority of the specified trial, trials without a (valid) priority annotation have a priority of zero
+func Priority(t *redskyv1beta1.Trial) int32 {
+	p, err := strconv.ParseInt(t.GetAnnotations()[redskyv1beta1.AnnotationTrialPriority], 10, 32)
+	if err != nil {
+		return 0
+	}
+	return int32(p)
+}
+
+// Precedes checks to see if the first trial should be started before the second, higher priority trials go first and
+// trials of equal priority are started in the order they were created
+func Precedes(t1, t2 *redskyv1beta1.Trial) bool {
+	if p1, p2 := Priority(t1), Priority(t2); p1 != p2 {
+		return p1 > p2
+	}
+	if !t1.CreationTimestamp.Equal(&t2.CreationTimestamp) {
+		return t1.CreationTimestamp.Before(&t2.CreationTimestamp)
+	}
+	return t1.Name < t2.Name
+}
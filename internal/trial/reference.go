@@ -0,0 +1,142 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// ReferenceBaseline returns the referenced values pinned on the experiment, keyed by parameter name; each value is
+// either an int64 or a quantity string
+func ReferenceBaseline(exp *redskyv1beta1.Experiment) (map[string]interface{}, error) {
+	baseline := make(map[string]interface{})
+	data, ok := exp.GetAnnotations()[redskyv1beta1.AnnotationReferenceBaseline]
+	if !ok {
+		return baseline, nil
+	}
+
+	d := json.NewDecoder(bytes.NewReader([]byte(data)))
+	d.UseNumber()
+	if err := d.Decode(&baseline); err != nil {
+		return nil, fmt.Errorf("invalid reference baseline: %w", err)
+	}
+	for k, v := range baseline {
+		switch vv := v.(type) {
+		case json.Number:
+			i, err := vv.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("invalid reference baseline for parameter %s: %w", k, err)
+			}
+			baseline[k] = i
+		case string:
+		default:
+			return nil, fmt.Errorf("invalid reference baseline for parameter %s", k)
+		}
+	}
+	return baseline, nil
+}
+
+// SetReferenceBaseline pins the referenced values on the experiment
+func SetReferenceBaseline(exp *redskyv1beta1.Experiment, baseline map[string]interface{}) error {
+	data, err := json.Marshal(baseline)
+	if err != nil {
+		return err
+	}
+
+	a := exp.GetAnnotations()
+	if a == nil {
+		a = make(map[string]string)
+	}
+	a[redskyv1beta1.AnnotationReferenceBaseline] = string(data)
+	exp.SetAnnotations(a)
+	return nil
+}
+
+// ReferencedValue returns the value referenced on the supplied object; numeric fields (e.g. replicas) produce an int64
+// and string fields produce a quantity string
+func ReferencedValue(ref *redskyv1beta1.ParameterReference, obj *unstructured.Unstructured) (interface{}, error) {
+	jp := jsonpath.New("reference")
+	if err := jp.Parse(ref.FieldPath); err != nil {
+		return nil, err
+	}
+	results, err := jp.FindResults(obj.UnstructuredContent())
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != 1 || len(results[0]) != 1 {
+		return nil, fmt.Errorf("referenced value %s must match exactly one field", ref.FieldPath)
+	}
+
+	switch v := results[0][0].Interface().(type) {
+	case int64:
+		return v, nil
+	case float64:
+		return int64(math.Round(v)), nil
+	case string:
+		if _, err := resource.ParseQuantity(v); err != nil {
+			return nil, fmt.Errorf("referenced value %s is not a quantity: %w", ref.FieldPath, err)
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("referenced value %s is not a number or quantity", ref.FieldPath)
+	}
+}
+
+// ResolveReference returns the concrete value of a percentage parameter assignment relative to a referenced value;
+// integer values remain integers and quantities retain the format of the referenced value
+func ResolveReference(value interface{}, percent int64) (redskyv1beta1.AssignmentValue, error) {
+	var s string
+	switch v := value.(type) {
+	case int64:
+		return redskyv1beta1.NewAssignmentValue(percentOf(v, percent)), nil
+	case string:
+		s = v
+	default:
+		return redskyv1beta1.AssignmentValue{}, fmt.Errorf("referenced value %v is not a number or quantity", value)
+	}
+
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return redskyv1beta1.AssignmentValue{}, fmt.Errorf("referenced value %s is not a quantity: %w", s, err)
+	}
+
+	// Avoid introducing fractional values (which cannot be expressed using binary suffixes) for whole quantities
+	var r *resource.Quantity
+	if q.MilliValue()%1000 == 0 {
+		r = resource.NewQuantity(percentOf(q.Value(), percent), q.Format)
+	} else {
+		r = resource.NewMilliQuantity(percentOf(q.MilliValue(), percent), q.Format)
+	}
+	return redskyv1beta1.NewCategoricalAssignmentValue(r.String()), nil
+}
+
+// percentOf returns the percentage of a value rounded to the nearest integer
+func percentOf(v, percent int64) int64 {
+	p := v * percent
+	if p < 0 {
+		return (p - 50) / 100
+	}
+	return (p + 50) / 100
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestResolveReference(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(4),
+			"weight":   float64(2.6),
+			"resources": map[string]interface{}{
+				"memory": "1Gi",
+				"cpu":    "500m",
+				"count":  "3",
+				"bad":    "lots",
+			},
+		},
+	}}
+
+	cases := []struct {
+		desc      string
+		fieldPath string
+		percent   int64
+		expected  redskyv1beta1.AssignmentValue
+		err       bool
+	}{
+		{
+			desc:      "integer",
+			fieldPath: "{.spec.replicas}",
+			percent:   150,
+			expected:  redskyv1beta1.NewAssignmentValue(6),
+		},
+		{
+			desc:      "integer rounded",
+			fieldPath: "{.spec.replicas}",
+			percent:   55,
+			expected:  redskyv1beta1.NewAssignmentValue(2),
+		},
+		{
+			desc:      "float",
+			fieldPath: "{.spec.weight}",
+			percent:   200,
+			expected:  redskyv1beta1.NewAssignmentValue(6),
+		},
+		{
+			desc:      "binary quantity",
+			fieldPath: "{.spec.resources.memory}",
+			percent:   50,
+			expected:  redskyv1beta1.NewCategoricalAssignmentValue("512Mi"),
+		},
+		{
+			desc:      "milli quantity",
+			fieldPath: "{.spec.resources.cpu}",
+			percent:   150,
+			expected:  redskyv1beta1.NewCategoricalAssignmentValue("750m"),
+		},
+		{
+			desc:      "whole quantity",
+			fieldPath: "{.spec.resources.count}",
+			percent:   200,
+			expected:  redskyv1beta1.NewCategoricalAssignmentValue("6"),
+		},
+		{
+			desc:      "missing",
+			fieldPath: "{.spec.missing}",
+			percent:   100,
+			err:       true,
+		},
+		{
+			desc:      "not a quantity",
+			fieldPath: "{.spec.resources.bad}",
+			percent:   100,
+			err:       true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			ref := &redskyv1beta1.ParameterReference{FieldPath: c.fieldPath}
+			value, err := ReferencedValue(ref, obj)
+			var actual redskyv1beta1.AssignmentValue
+			if err == nil {
+				actual, err = ResolveReference(value, c.percent)
+			}
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestReferenceBaseline(t *testing.T) {
+	cases := []struct {
+		desc       string
+		annotation string
+		expected   map[string]interface{}
+		err        bool
+	}{
+		{
+			desc:     "missing",
+			expected: map[string]interface{}{},
+		},
+		{
+			desc:       "values",
+			annotation: `{"replicas":4,"memory":"1Gi","big":1099511627776}`,
+			expected:   map[string]interface{}{"replicas": int64(4), "memory": "1Gi", "big": int64(1099511627776)},
+		},
+		{
+			desc:       "fractional",
+			annotation: `{"replicas":4.5}`,
+			err:        true,
+		},
+		{
+			desc:       "invalid",
+			annotation: `[]`,
+			err:        true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			exp := &redskyv1beta1.Experiment{}
+			if c.annotation != "" {
+				exp.SetAnnotations(map[string]string{redskyv1beta1.AnnotationReferenceBaseline: c.annotation})
+			}
+			actual, err := ReferenceBaseline(exp)
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, actual)
+			}
+
+			// Round trip the baseline through the annotation
+			if assert.NoError(t, SetReferenceBaseline(exp, actual)) {
+				roundTrip, err := ReferenceBaseline(exp)
+				if assert.NoError(t, err) {
+					assert.Equal(t, c.expected, roundTrip)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SidecarPolicyDisableInjection prevents service mesh sidecars from being injected into trial run job pods
+	SidecarPolicyDisableInjection = "disable-injection"
+	// SidecarPolicyShutdown stops the trial run job once all of the non-sidecar containers have exited
+	SidecarPolicyShutdown = "shutdown"
+)
+
+// sidecarContainers are the names of the proxy containers injected by the supported service meshes
+var sidecarContainers = map[string]bool{
+	"istio-proxy":   true,
+	"linkerd-proxy": true,
+}
+
+// sidecarInjectionAnnotations are the pod annotations used to opt out of the supported service meshes
+var sidecarInjectionAnnotations = map[string]string{
+	"sidecar.istio.io/inject": "false",
+	"linkerd.io/inject":       "disabled",
+}
+
+// SidecarPolicy returns the service mesh sidecar policy of the trial
+func SidecarPolicy(t *redskyv1beta1.Trial) string {
+	return t.GetAnnotations()[redskyv1beta1.AnnotationSidecarPolicy]
+}
+
+// IsSidecarContainer checks to see if the named container is a service mesh sidecar
+func IsSidecarContainer(name string) bool {
+	return sidecarContainers[name]
+}
+
+// SidecarsBlocking checks to see if all of the non-sidecar containers in the pod have exited while at least one
+// sidecar is still running, preventing the pod (and therefore the job) from ever completing
+func SidecarsBlocking(pod *corev1.Pod) bool {
+	var sidecarRunning bool
+	for i := range pod.Status.ContainerStatuses {
+		cs := &pod.Status.ContainerStatuses[i]
+		if IsSidecarContainer(cs.Name) {
+			sidecarRunning = sidecarRunning || cs.State.Running != nil
+		} else if cs.State.Terminated == nil {
+			return false
+		}
+	}
+	return sidecarRunning
+}
+
+// SidecarExitFailure returns the first non-sidecar container in the pod that exited with a non-zero exit code
+func SidecarExitFailure(pod *corev1.Pod) *corev1.ContainerStatus {
+	for i := range pod.Status.ContainerStatuses {
+		cs := &pod.Status.ContainerStatuses[i]
+		if !IsSidecarContainer(cs.Name) && cs.State.Terminated != nil && cs.State.Terminated.ExitCode != 0 {
+			return cs
+		}
+	}
+	return nil
+}
+
+// disableSidecarInjection annotates a pod template so service meshes do not inject their sidecars
+func disableSidecarInjection(template *corev1.PodTemplateSpec) {
+	if template.Annotations == nil {
+		template.Annotations = make(map[string]string, len(sidecarInjectionAnnotations))
+	}
+	for k, v := range sidecarInjectionAnnotations {
+		if _, ok := template.Annotations[k]; !ok {
+			template.Annotations[k] = v
+		}
+	}
+}
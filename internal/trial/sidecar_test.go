@@ -0,0 +1,98 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSidecarsBlocking(t *testing.T) {
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	succeeded := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}
+	failed := corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}}
+
+	cases := []struct {
+		desc     string
+		statuses []corev1.ContainerStatus
+		blocking bool
+		failure  string
+	}{
+		{
+			desc: "no sidecar",
+			statuses: []corev1.ContainerStatus{
+				{Name: "default", State: succeeded},
+			},
+		},
+		{
+			desc: "main running",
+			statuses: []corev1.ContainerStatus{
+				{Name: "default", State: running},
+				{Name: "istio-proxy", State: running},
+			},
+		},
+		{
+			desc: "istio blocking",
+			statuses: []corev1.ContainerStatus{
+				{Name: "default", State: succeeded},
+				{Name: "istio-proxy", State: running},
+			},
+			blocking: true,
+		},
+		{
+			desc: "linkerd blocking failure",
+			statuses: []corev1.ContainerStatus{
+				{Name: "default", State: failed},
+				{Name: "linkerd-proxy", State: running},
+			},
+			blocking: true,
+			failure:  "default",
+		},
+		{
+			desc: "sidecar terminated",
+			statuses: []corev1.ContainerStatus{
+				{Name: "default", State: succeeded},
+				{Name: "istio-proxy", State: succeeded},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: c.statuses}}
+			assert.Equal(t, c.blocking, SidecarsBlocking(pod))
+			if cs := SidecarExitFailure(pod); c.failure != "" {
+				if assert.NotNil(t, cs) {
+					assert.Equal(t, c.failure, cs.Name)
+				}
+			} else {
+				assert.Nil(t, cs)
+			}
+		})
+	}
+}
+
+func TestDisableSidecarInjection(t *testing.T) {
+	template := &corev1.PodTemplateSpec{}
+	template.Annotations = map[string]string{"sidecar.istio.io/inject": "true"}
+	disableSidecarInjection(template)
+	assert.Equal(t, map[string]string{
+		"sidecar.istio.io/inject": "true",
+		"linkerd.io/inject":       "disabled",
+	}, template.Annotations)
+}
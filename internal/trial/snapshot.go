@@ -0,0 +1,86 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"bytes"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/meta"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// SnapshotKey is the config map key containing the snapshot manifests
+const SnapshotKey = "manifests.yaml"
+
+// NewSnapshot returns a config map holding the sanitized manifests of the trial's target objects
+func NewSnapshot(t *redskyv1beta1.Trial, objs []*unstructured.Unstructured) (*corev1.ConfigMap, error) {
+	var buf bytes.Buffer
+	for _, obj := range objs {
+		data, err := yaml.Marshal(Sanitize(obj).Object)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString("---\n")
+		buf.Write(data)
+	}
+
+	cm := &corev1.ConfigMap{}
+	cm.Name = t.Name + "-snapshot"
+	cm.Namespace = t.Namespace
+	meta.AddLabel(cm, redskyv1beta1.LabelExperiment, t.ExperimentNamespacedName().Name)
+	meta.AddLabel(cm, redskyv1beta1.LabelTrial, t.Name)
+	meta.AddLabel(cm, redskyv1beta1.LabelTrialRole, "snapshot")
+	cm.Data = map[string]string{SnapshotKey: buf.String()}
+	return cm, nil
+}
+
+// Sanitize returns a copy of the object with the cluster assigned state removed so it can be re-applied, the contents
+// of secrets are also removed
+func Sanitize(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	u := obj.DeepCopy()
+	delete(u.Object, "status")
+
+	unstructured.RemoveNestedField(u.Object, "metadata", "uid")
+	unstructured.RemoveNestedField(u.Object, "metadata", "resourceVersion")
+	unstructured.RemoveNestedField(u.Object, "metadata", "generation")
+	unstructured.RemoveNestedField(u.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(u.Object, "metadata", "selfLink")
+	unstructured.RemoveNestedField(u.Object, "metadata", "managedFields")
+	unstructured.RemoveNestedField(u.Object, "metadata", "ownerReferences")
+	unstructured.RemoveNestedField(u.Object, "metadata", "annotations", "kubectl.kubernetes.io/last-applied-configuration")
+	unstructured.RemoveNestedField(u.Object, "metadata", "annotations", "deployment.kubernetes.io/revision")
+	if a, ok, _ := unstructured.NestedMap(u.Object, "metadata", "annotations"); ok && len(a) == 0 {
+		unstructured.RemoveNestedField(u.Object, "metadata", "annotations")
+	}
+
+	// Cluster IPs are allocated by the cluster and cannot be re-used
+	if u.GetKind() == "Service" && u.GetAPIVersion() == "v1" {
+		unstructured.RemoveNestedField(u.Object, "spec", "clusterIP")
+		unstructured.RemoveNestedField(u.Object, "spec", "clusterIPs")
+	}
+
+	// Secret values must never be copied into a config map, only the metadata of the secret is recorded
+	if u.GetKind() == "Secret" && u.GetAPIVersion() == "v1" {
+		unstructured.RemoveNestedField(u.Object, "data")
+		unstructured.RemoveNestedField(u.Object, "stringData")
+	}
+
+	return u
+}
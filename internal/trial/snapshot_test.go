@@ -0,0 +1,129 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSanitize(t *testing.T) {
+	cases := []struct {
+		desc     string
+		obj      map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			desc: "deployment",
+			obj: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]interface{}{
+					"name":              "test",
+					"uid":               "1234",
+					"resourceVersion":   "1",
+					"creationTimestamp": "2020-01-01T00:00:00Z",
+					"annotations": map[string]interface{}{
+						"deployment.kubernetes.io/revision": "2",
+					},
+				},
+				"spec":   map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{"readyReplicas": int64(2)},
+			},
+			expected: map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]interface{}{"name": "test"},
+				"spec":       map[string]interface{}{"replicas": int64(2)},
+			},
+		},
+		{
+			desc: "service",
+			obj: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata": map[string]interface{}{
+					"name": "test",
+					"annotations": map[string]interface{}{
+						"example.com/keep": "true",
+					},
+				},
+				"spec": map[string]interface{}{"clusterIP": "10.0.0.1", "type": "ClusterIP"},
+			},
+			expected: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata": map[string]interface{}{
+					"name": "test",
+					"annotations": map[string]interface{}{
+						"example.com/keep": "true",
+					},
+				},
+				"spec": map[string]interface{}{"type": "ClusterIP"},
+			},
+		},
+		{
+			desc: "secret",
+			obj: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata": map[string]interface{}{
+					"name": "test",
+					"annotations": map[string]interface{}{
+						"kubectl.kubernetes.io/last-applied-configuration": `{"stringData":{"password":"hunter2"}}`,
+					},
+				},
+				"type":       "Opaque",
+				"data":       map[string]interface{}{"password": "aHVudGVyMg=="},
+				"stringData": map[string]interface{}{"password": "hunter2"},
+			},
+			expected: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Secret",
+				"metadata":   map[string]interface{}{"name": "test"},
+				"type":       "Opaque",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: c.obj}
+			assert.Equal(t, c.expected, Sanitize(obj).Object)
+		})
+	}
+}
+
+func TestNewSnapshot_Secret(t *testing.T) {
+	tr := &redskyv1beta1.Trial{}
+	tr.Name = "test-001"
+	tr.Namespace = "default"
+	secret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "credentials"},
+		"data":       map[string]interface{}{"password": "aHVudGVyMg=="},
+	}}
+
+	cm, err := NewSnapshot(tr, []*unstructured.Unstructured{secret})
+	if assert.NoError(t, err) {
+		assert.Contains(t, cm.Data[SnapshotKey], "name: credentials")
+		assert.NotContains(t, cm.Data[SnapshotKey], "aHVudGVyMg==")
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// IsStatefulSetReference checks to see if the supplied reference points to a StatefulSet
+func IsStatefulSetReference(ref *corev1.ObjectReference) bool {
+	return ref.Kind == "StatefulSet" && ref.GroupVersionKind().Group == "apps"
+}
+
+// CheckStatefulSetPatch ensures a patch does not modify the volume claim templates of a StatefulSet, changes to the
+// persistent volume claims cannot be applied to existing pods and are rejected by the API server
+func CheckStatefulSetPatch(patchType types.PatchType, data []byte) error {
+	switch patchType {
+	case types.JSONPatchType:
+		var ops []struct {
+			Path string `json:"path"`
+		}
+		if err := json.Unmarshal(data, &ops); err != nil {
+			return err
+		}
+		for _, op := range ops {
+			if op.Path == "/spec/volumeClaimTemplates" || strings.HasPrefix(op.Path, "/spec/volumeClaimTemplates/") {
+				return fmt.Errorf("stateful set patch must not modify volume claim templates")
+			}
+		}
+	default:
+		var obj struct {
+			Spec map[string]json.RawMessage `json:"spec"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		if _, ok := obj.Spec["volumeClaimTemplates"]; ok {
+			return fmt.Errorf("stateful set patch must not modify volume claim templates")
+		}
+	}
+	return nil
+}
+
+// PartitionStatefulSetPatch adds a partitioned rolling update strategy to a StatefulSet patch
+func PartitionStatefulSetPatch(patchType types.PatchType, data []byte, partition int32) ([]byte, error) {
+	strategy := map[string]interface{}{
+		"type":          "RollingUpdate",
+		"rollingUpdate": map[string]interface{}{"partition": partition},
+	}
+
+	switch patchType {
+	case types.JSONPatchType:
+		var ops []interface{}
+		if err := json.Unmarshal(data, &ops); err != nil {
+			return nil, err
+		}
+		ops = append(ops, map[string]interface{}{"op": "add", "path": "/spec/updateStrategy", "value": strategy})
+		return json.Marshal(ops)
+	default:
+		obj := make(map[string]interface{})
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return nil, err
+		}
+		spec, ok := obj["spec"].(map[string]interface{})
+		if !ok {
+			spec = make(map[string]interface{})
+			obj["spec"] = spec
+		}
+		spec["updateStrategy"] = strategy
+		return json.Marshal(obj)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestCheckStatefulSetPatch(t *testing.T) {
+	cases := []struct {
+		desc      string
+		patchType types.PatchType
+		data      string
+		err       bool
+	}{
+		{
+			desc:      "strategic",
+			patchType: types.StrategicMergePatchType,
+			data:      `{"spec":{"replicas":3}}`,
+		},
+		{
+			desc:      "strategic volume claims",
+			patchType: types.StrategicMergePatchType,
+			data:      `{"spec":{"volumeClaimTemplates":[]}}`,
+			err:       true,
+		},
+		{
+			desc:      "json volume claims",
+			patchType: types.JSONPatchType,
+			data:      `[{"op":"replace","path":"/spec/volumeClaimTemplates/0/spec/resources/requests/storage","value":"1Gi"}]`,
+			err:       true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			err := CheckStatefulSetPatch(c.patchType, []byte(c.data))
+			if c.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestPartitionStatefulSetPatch(t *testing.T) {
+	data, err := PartitionStatefulSetPatch(types.StrategicMergePatchType, []byte(`{"spec":{"replicas":3}}`), 2)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"spec":{"replicas":3,"updateStrategy":{"type":"RollingUpdate","rollingUpdate":{"partition":2}}}}`, string(data))
+	}
+
+	data, err = PartitionStatefulSetPatch(types.JSONPatchType, []byte(`[]`), 1)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `[{"op":"add","path":"/spec/updateStrategy","value":{"type":"RollingUpdate","rollingUpdate":{"partition":1}}}]`, string(data))
+	}
+}
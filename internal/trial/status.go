@@ -37,6 +37,7 @@ const (
 	patched      = "Patched"
 	patching     = "Patching"
 	running      = "Running"
+	warmingUp    = "Warming Up"
 	stabilized   = "Stabilized"
 	waiting      = "Waiting"
 	captured     = "Captured"
@@ -54,13 +55,14 @@ var (
 		redskyv1beta1.TrialObserved,
 		redskyv1beta1.TrialComplete,
 		redskyv1beta1.TrialFailed,
+		redskyv1beta1.TrialReported,
 	}
 )
 
 // UpdateStatus will make sure the trial status matches the current state of the trial; returns true only if changes were necessary
 func UpdateStatus(t *redskyv1beta1.Trial) bool {
 	phase := summarize(t)
-	assignments := assignments(t)
+	assignments := Assignments(t)
 	values := values(t)
 
 	var dirty bool
@@ -137,6 +139,8 @@ func summarize(t *redskyv1beta1.Trial) string {
 			case corev1.ConditionTrue:
 				if t.Status.StartTime != nil {
 					phase = running
+				} else if t.Status.WarmupStartTime != nil {
+					phase = warmingUp
 				} else {
 					phase = stabilized
 				}
@@ -172,10 +176,21 @@ func summarize(t *redskyv1beta1.Trial) string {
 	return phase
 }
 
-func assignments(t *redskyv1beta1.Trial) string {
+// Assignments returns a summary of the trial assignments with sensitive values redacted, resolved values are
+// included in parenthesis
+func Assignments(t *redskyv1beta1.Trial) string {
 	assignments := make([]string, len(t.Spec.Assignments))
 	for i := range t.Spec.Assignments {
-		assignments[i] = fmt.Sprintf("%s=%d", t.Spec.Assignments[i].Name, t.Spec.Assignments[i].Value)
+		if IsSensitive(t, t.Spec.Assignments[i].Name) {
+			assignments[i] = fmt.Sprintf("%s=%s", t.Spec.Assignments[i].Name, Redacted)
+			continue
+		}
+		assignments[i] = fmt.Sprintf("%s=%s", t.Spec.Assignments[i].Name, t.Spec.Assignments[i].Value.String())
+		for _, a := range t.Status.ResolvedAssignments {
+			if a.Name == t.Spec.Assignments[i].Name {
+				assignments[i] += fmt.Sprintf(" (%s)", a.Value.String())
+			}
+		}
 	}
 	return strings.Join(assignments, ", ")
 }
@@ -17,7 +17,6 @@ limitations under the License.
 package trial
 
 import (
-	"fmt"
 	"strings"
 	"time"
 
@@ -26,6 +25,10 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ReasonJobDeleted is the failure reason used when the trial run job is deleted before the trial finishes; since the
+// assignments were never evaluated the trial is abandoned instead of being reported as a failure
+const ReasonJobDeleted = "JobDeleted"
+
 // IsFinished checks to see if the specified trial is finished
 func IsFinished(t *redskyv1beta1.Trial) bool {
 	for _, c := range t.Status.Conditions {
@@ -38,9 +41,18 @@ func IsFinished(t *redskyv1beta1.Trial) bool {
 	return false
 }
 
-// IsAbandoned checks to see if the specified trial is abandoned
+// IsAbandoned checks to see if the specified trial is abandoned, either because it was deleted before it finished or
+// because the trial run job was lost
 func IsAbandoned(t *redskyv1beta1.Trial) bool {
-	return !IsFinished(t) && !t.GetDeletionTimestamp().IsZero()
+	if !IsFinished(t) {
+		return !t.GetDeletionTimestamp().IsZero()
+	}
+	for _, c := range t.Status.Conditions {
+		if c.Type == redskyv1beta1.TrialFailed && c.Status == corev1.ConditionTrue && c.Reason == ReasonJobDeleted {
+			return true
+		}
+	}
+	return false
 }
 
 // IsActive checks to see if the specified trial and any setup delete tasks are NOT finished
@@ -95,11 +107,33 @@ func IsTrialJobReference(t *redskyv1beta1.Trial, ref *corev1.ObjectReference) bo
 func AppendAssignmentEnv(t *redskyv1beta1.Trial, env []corev1.EnvVar) []corev1.EnvVar {
 	for _, a := range t.Spec.Assignments {
 		name := strings.ReplaceAll(strings.ToUpper(a.Name), ".", "_")
-		env = append(env, corev1.EnvVar{Name: name, Value: fmt.Sprintf("%d", a.Value)})
+		env = append(env, corev1.EnvVar{Name: name, Value: a.Value.String()})
 	}
 	return env
 }
 
+// Redacted is the value displayed in place of a sensitive parameter assignment
+const Redacted = "***"
+
+// IsSensitive checks to see if the named parameter was marked as sensitive on the trial
+func IsSensitive(t *redskyv1beta1.Trial, name string) bool {
+	for _, n := range strings.Split(t.GetAnnotations()[redskyv1beta1.AnnotationSensitiveParameters], ",") {
+		if strings.TrimSpace(n) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ScaleUpTimeout returns the amount of time unschedulable pods may wait for new nodes, zero if pods may not wait
+func ScaleUpTimeout(t *redskyv1beta1.Trial) time.Duration {
+	d, err := time.ParseDuration(t.GetAnnotations()[redskyv1beta1.AnnotationScaleUpTimeout])
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
 // NeedsCleanup checks to see if a trial's TTL has expired
 func NeedsCleanup(t *redskyv1beta1.Trial) bool {
 	// Already deleted or still active, no cleanup necessary
@@ -0,0 +1,74 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsAbandoned(t *testing.T) {
+	now := metav1.Now()
+	cases := []struct {
+		desc       string
+		deleted    bool
+		conditions []redskyv1beta1.TrialCondition
+		abandoned  bool
+	}{
+		{
+			desc: "running",
+		},
+		{
+			desc:      "deleted",
+			deleted:   true,
+			abandoned: true,
+		},
+		{
+			desc:    "deleted complete",
+			deleted: true,
+			conditions: []redskyv1beta1.TrialCondition{
+				{Type: redskyv1beta1.TrialComplete, Status: corev1.ConditionTrue},
+			},
+		},
+		{
+			desc: "failed",
+			conditions: []redskyv1beta1.TrialCondition{
+				{Type: redskyv1beta1.TrialFailed, Status: corev1.ConditionTrue, Reason: "Aborted"},
+			},
+		},
+		{
+			desc: "job deleted",
+			conditions: []redskyv1beta1.TrialCondition{
+				{Type: redskyv1beta1.TrialFailed, Status: corev1.ConditionTrue, Reason: ReasonJobDeleted},
+			},
+			abandoned: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			tt := &redskyv1beta1.Trial{Status: redskyv1beta1.TrialStatus{Conditions: c.conditions}}
+			if c.deleted {
+				tt.DeletionTimestamp = &now
+			}
+			assert.Equal(t, c.abandoned, IsAbandoned(tt))
+		})
+	}
+}
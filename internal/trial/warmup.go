@@ -0,0 +1,94 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReasonWarmupIncomplete is the reason a trial fails when the trial run finishes before the warm-up is over
+const ReasonWarmupIncomplete = "WarmupIncomplete"
+
+// AddWarmupProbe adds the warm-up readiness probe to the trial run containers that do not already have one
+func AddWarmupProbe(t *redskyv1beta1.Trial, template *corev1.PodTemplateSpec) {
+	if t.Spec.Warmup == nil || t.Spec.Warmup.ReadinessProbe == nil {
+		return
+	}
+
+	for i := range template.Spec.Containers {
+		if template.Spec.Containers[i].ReadinessProbe == nil {
+			template.Spec.Containers[i].ReadinessProbe = t.Spec.Warmup.ReadinessProbe.DeepCopy()
+		}
+	}
+}
+
+// WarmupEnd returns the time the warm-up of the trial run ended, nil if the trial run is still warming up (or finished
+// before the warm-up was over); the warm-up cannot end after the trial completion time, if it is known
+func WarmupEnd(t *redskyv1beta1.Trial, pods *corev1.PodList, now *metav1.Time) *metav1.Time {
+	w := t.Spec.Warmup
+	if w == nil || t.Status.WarmupStartTime == nil {
+		return nil
+	}
+
+	limit := now
+	if t.Status.CompletionTime != nil && t.Status.CompletionTime.Before(now) {
+		limit = t.Status.CompletionTime
+	}
+
+	end := t.Status.WarmupStartTime.DeepCopy()
+	if w.Duration != nil {
+		end = &metav1.Time{Time: end.Add(w.Duration.Duration)}
+		if limit.Before(end) {
+			return nil
+		}
+	}
+
+	if w.ReadinessProbe != nil {
+		if pods == nil || len(pods.Items) == 0 {
+			return nil
+		}
+
+		// The warm-up is over once the last of the pods became ready
+		for i := range pods.Items {
+			readyAt := podReadyTime(&pods.Items[i])
+			if readyAt == nil {
+				return nil
+			}
+			if end.Before(readyAt) {
+				end = readyAt.DeepCopy()
+			}
+		}
+		if limit.Before(end) {
+			return nil
+		}
+	}
+
+	return end
+}
+
+// podReadyTime returns the time the pod became ready, nil if the pod is not ready
+func podReadyTime(pod *corev1.Pod) *metav1.Time {
+	for i := range pod.Status.Conditions {
+		c := &pod.Status.Conditions[i]
+		if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+			return &c.LastTransitionTime
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"testing"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWarmupEnd(t *testing.T) {
+	start := metav1.NewTime(time.Date(2020, time.June, 1, 12, 0, 0, 0, time.UTC))
+	at := func(d time.Duration) *metav1.Time { return &metav1.Time{Time: start.Add(d)} }
+	pod := func(readyAt *metav1.Time) corev1.Pod {
+		p := corev1.Pod{}
+		if readyAt != nil {
+			p.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: *readyAt}}
+		}
+		return p
+	}
+
+	cases := []struct {
+		desc       string
+		warmup     *redskyv1beta1.TrialWarmup
+		pods       []corev1.Pod
+		completion *metav1.Time
+		now        *metav1.Time
+		expected   *metav1.Time
+	}{
+		{
+			desc: "no warmup",
+			now:  at(time.Minute),
+		},
+		{
+			desc:     "duration elapsed",
+			warmup:   &redskyv1beta1.TrialWarmup{Duration: &metav1.Duration{Duration: time.Minute}},
+			now:      at(2 * time.Minute),
+			expected: at(time.Minute),
+		},
+		{
+			desc:   "duration not elapsed",
+			warmup: &redskyv1beta1.TrialWarmup{Duration: &metav1.Duration{Duration: time.Minute}},
+			now:    at(30 * time.Second),
+		},
+		{
+			desc:     "pods ready",
+			warmup:   &redskyv1beta1.TrialWarmup{ReadinessProbe: &corev1.Probe{}},
+			pods:     []corev1.Pod{pod(at(10 * time.Second)), pod(at(20 * time.Second))},
+			now:      at(time.Minute),
+			expected: at(20 * time.Second),
+		},
+		{
+			desc:   "pods not ready",
+			warmup: &redskyv1beta1.TrialWarmup{ReadinessProbe: &corev1.Probe{}},
+			pods:   []corev1.Pod{pod(at(10 * time.Second)), pod(nil)},
+			now:    at(time.Minute),
+		},
+		{
+			desc:     "duration and pods ready",
+			warmup:   &redskyv1beta1.TrialWarmup{Duration: &metav1.Duration{Duration: 30 * time.Second}, ReadinessProbe: &corev1.Probe{}},
+			pods:     []corev1.Pod{pod(at(10 * time.Second))},
+			now:      at(time.Minute),
+			expected: at(30 * time.Second),
+		},
+		{
+			desc:       "completed during duration",
+			warmup:     &redskyv1beta1.TrialWarmup{Duration: &metav1.Duration{Duration: time.Minute}},
+			completion: at(30 * time.Second),
+			now:        at(2 * time.Minute),
+		},
+		{
+			desc:       "completed after duration",
+			warmup:     &redskyv1beta1.TrialWarmup{Duration: &metav1.Duration{Duration: time.Minute}},
+			completion: at(90 * time.Second),
+			now:        at(2 * time.Minute),
+			expected:   at(time.Minute),
+		},
+		{
+			desc:       "pods ready after completion",
+			warmup:     &redskyv1beta1.TrialWarmup{ReadinessProbe: &corev1.Probe{}},
+			pods:       []corev1.Pod{pod(at(40 * time.Second))},
+			completion: at(30 * time.Second),
+			now:        at(time.Minute),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			tr := &redskyv1beta1.Trial{
+				Spec:   redskyv1beta1.TrialSpec{Warmup: c.warmup},
+				Status: redskyv1beta1.TrialStatus{WarmupStartTime: &start, CompletionTime: c.completion},
+			}
+			actual := WarmupEnd(tr, &corev1.PodList{Items: c.pods}, c.now)
+			if c.expected == nil {
+				assert.Nil(t, actual)
+			} else if assert.NotNil(t, actual) {
+				assert.True(t, c.expected.Equal(actual), "expected %s, got %s", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAddWarmupProbe(t *testing.T) {
+	probe := &corev1.Probe{PeriodSeconds: 5}
+	existing := &corev1.Probe{PeriodSeconds: 1}
+	tr := &redskyv1beta1.Trial{Spec: redskyv1beta1.TrialSpec{Warmup: &redskyv1beta1.TrialWarmup{ReadinessProbe: probe}}}
+	template := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "a"}, {Name: "b", ReadinessProbe: existing}},
+		},
+	}
+
+	AddWarmupProbe(tr, template)
+	assert.Equal(t, probe, template.Spec.Containers[0].ReadinessProbe)
+	assert.Equal(t, existing, template.Spec.Containers[1].ReadinessProbe)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+)
+
+// DefaultWebhookTimeout is the amount of time to wait for an assignment webhook when no timeout is specified
+const DefaultWebhookTimeout = 10 * time.Second
+
+// AssignmentWebhookRequest is the body sent to an assignment webhook
+type AssignmentWebhookRequest struct {
+	// Namespace of the trial
+	Namespace string `json:"namespace"`
+	// Experiment is the name of the experiment
+	Experiment string `json:"experiment"`
+	// Trial is the name of the trial
+	Trial string `json:"trial"`
+	// Assignments are the values that would be used to render the patches
+	Assignments []redskyv1beta1.Assignment `json:"assignments"`
+}
+
+// AssignmentWebhookResponse is the body returned from an assignment webhook
+type AssignmentWebhookResponse struct {
+	// Assignments are the values to use instead, assignments which are omitted are not changed
+	Assignments []redskyv1beta1.Assignment `json:"assignments"`
+}
+
+// EffectiveAssignments returns the trial assignments with any resolved values substituted
+func EffectiveAssignments(t *redskyv1beta1.Trial) []redskyv1beta1.Assignment {
+	asm := make([]redskyv1beta1.Assignment, len(t.Spec.Assignments))
+	for i := range t.Spec.Assignments {
+		asm[i] = t.Spec.Assignments[i]
+		for _, a := range t.Status.ResolvedAssignments {
+			if a.Name == asm[i].Name {
+				asm[i].Value = a.Value
+			}
+		}
+	}
+	return asm
+}
+
+// CallAssignmentWebhook sends the effective assignments of the trial to the webhook, the values returned by the
+// webhook are recorded as resolved assignments on the trial
+func CallAssignmentWebhook(ctx context.Context, hook *redskyv1beta1.AssignmentWebhook, t *redskyv1beta1.Trial) error {
+	timeout := DefaultWebhookTimeout
+	if hook.TimeoutSeconds != nil {
+		timeout = time.Duration(*hook.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := json.Marshal(&AssignmentWebhookRequest{
+		Namespace:   t.Namespace,
+		Experiment:  t.ExperimentNamespacedName().Name,
+		Trial:       t.Name,
+		Assignments: EffectiveAssignments(t),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("assignment webhook returned %s", resp.Status)
+	}
+
+	result := &AssignmentWebhookResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("invalid assignment webhook response: %w", err)
+	}
+
+	// Only parameters of the trial can be changed
+	for _, a := range result.Assignments {
+		if _, ok := t.GetAssignment(a.Name); !ok {
+			return fmt.Errorf("assignment webhook returned unknown parameter %s", a.Name)
+		}
+	}
+
+	for _, a := range result.Assignments {
+		setResolvedAssignment(t, a)
+	}
+	return nil
+}
+
+// setResolvedAssignment adds or replaces a resolved assignment
+func setResolvedAssignment(t *redskyv1beta1.Trial, a redskyv1beta1.Assignment) {
+	for i := range t.Status.ResolvedAssignments {
+		if t.Status.ResolvedAssignments[i].Name == a.Name {
+			t.Status.ResolvedAssignments[i].Value = a.Value
+			return
+		}
+	}
+	t.Status.ResolvedAssignments = append(t.Status.ResolvedAssignments, a)
+}
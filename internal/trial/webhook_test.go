@@ -0,0 +1,97 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallAssignmentWebhook(t *testing.T) {
+	// The webhook rounds memory up to the next multiple of 256
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &AssignmentWebhookRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp := &AssignmentWebhookResponse{}
+		for _, a := range req.Assignments {
+			if a.Name == "memory" {
+				v, _ := a.Int64Value()
+				resp.Assignments = append(resp.Assignments, redskyv1beta1.Assignment{Name: a.Name, Value: redskyv1beta1.NewAssignmentValue((v + 255) / 256 * 256)})
+			}
+			if a.Name == "unknown" {
+				resp.Assignments = append(resp.Assignments, redskyv1beta1.Assignment{Name: "other", Value: a.Value})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	cases := []struct {
+		desc        string
+		url         string
+		assignments []redskyv1beta1.Assignment
+		expected    []redskyv1beta1.Assignment
+		err         bool
+	}{
+		{
+			desc: "transformed",
+			url:  srv.URL,
+			assignments: []redskyv1beta1.Assignment{
+				{Name: "memory", Value: redskyv1beta1.NewAssignmentValue(300)},
+				{Name: "cpu", Value: redskyv1beta1.NewAssignmentValue(100)},
+			},
+			expected: []redskyv1beta1.Assignment{
+				{Name: "memory", Value: redskyv1beta1.NewAssignmentValue(512)},
+			},
+		},
+		{
+			desc: "unknown parameter",
+			url:  srv.URL,
+			assignments: []redskyv1beta1.Assignment{
+				{Name: "unknown", Value: redskyv1beta1.NewAssignmentValue(1)},
+			},
+			err: true,
+		},
+		{
+			desc: "invalid URL",
+			url:  "://",
+			err:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			tt := &redskyv1beta1.Trial{Spec: redskyv1beta1.TrialSpec{Assignments: c.assignments}}
+			err := CallAssignmentWebhook(context.TODO(), &redskyv1beta1.AssignmentWebhook{URL: c.url}, tt)
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, tt.Status.ResolvedAssignments)
+		})
+	}
+}
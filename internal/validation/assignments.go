@@ -16,7 +16,13 @@ limitations under the License.
 
 package validation
 
-import redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+import (
+	"fmt"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
 
 // AssignmentError is raised when trial assignments do not match the experiment parameter definitions
 type AssignmentError struct {
@@ -28,12 +34,32 @@ type AssignmentError struct {
 	OutOfBounds []string
 	// Parameter names for which multiple assignments exist
 	Duplicated []string
+	// Constraint names (or positions, for unnamed constraints) which the assignments do not satisfy
+	Unsatisfied []string
 }
 
 // Error returns a message describing the nature of the problems with the assignments
 func (e *AssignmentError) Error() string {
-	// TODO Improve this error message
-	return "invalid assignments"
+	var problems []string
+	if len(e.Unassigned) > 0 {
+		problems = append(problems, "unassigned: "+strings.Join(e.Unassigned, ", "))
+	}
+	if len(e.Undefined) > 0 {
+		problems = append(problems, "undefined: "+strings.Join(e.Undefined, ", "))
+	}
+	if len(e.OutOfBounds) > 0 {
+		problems = append(problems, "out of bounds: "+strings.Join(e.OutOfBounds, ", "))
+	}
+	if len(e.Duplicated) > 0 {
+		problems = append(problems, "duplicated: "+strings.Join(e.Duplicated, ", "))
+	}
+	if len(e.Unsatisfied) > 0 {
+		problems = append(problems, "unsatisfied constraints: "+strings.Join(e.Unsatisfied, ", "))
+	}
+	if len(problems) == 0 {
+		return "invalid assignments"
+	}
+	return "invalid assignments (" + strings.Join(problems, "; ") + ")"
 }
 
 // CheckAssignments ensures the trial assignments match the definitions on the experiment
@@ -41,10 +67,11 @@ func CheckAssignments(t *redskyv1beta1.Trial, exp *redskyv1beta1.Experiment) err
 	err := &AssignmentError{}
 
 	// Index the assignments, checking for duplicates
-	assignments := make(map[string]int64, len(t.Spec.Assignments))
-	for _, a := range t.Spec.Assignments {
+	assignments := make(map[string]*redskyv1beta1.Assignment, len(t.Spec.Assignments))
+	for i := range t.Spec.Assignments {
+		a := &t.Spec.Assignments[i]
 		if _, ok := assignments[a.Name]; !ok {
-			assignments[a.Name] = a.Value
+			assignments[a.Name] = a
 		} else {
 			err.Duplicated = append(err.Duplicated, a.Name)
 		}
@@ -53,7 +80,7 @@ func CheckAssignments(t *redskyv1beta1.Trial, exp *redskyv1beta1.Experiment) err
 	// Verify against the parameter specifications
 	for _, p := range exp.Spec.Parameters {
 		if a, ok := assignments[p.Name]; ok {
-			if a < p.Min || a > p.Max {
+			if !inBounds(&p, a) {
 				err.OutOfBounds = append(err.OutOfBounds, p.Name)
 			}
 			delete(assignments, p.Name)
@@ -65,9 +92,79 @@ func CheckAssignments(t *redskyv1beta1.Trial, exp *redskyv1beta1.Experiment) err
 		err.Undefined = append(err.Undefined, n)
 	}
 
+	// Verify against the constraints
+	for i := range exp.Spec.Constraints {
+		c := &exp.Spec.Constraints[i]
+		if !satisfied(c, t) {
+			name := c.Name
+			if name == "" {
+				name = fmt.Sprintf("constraint %d", i)
+			}
+			err.Unsatisfied = append(err.Unsatisfied, name)
+		}
+	}
+
 	// If there were no problems found, return nil
-	if len(err.Unassigned) == 0 && len(err.Undefined) == 0 && len(err.OutOfBounds) == 0 && len(err.Duplicated) == 0 {
+	if len(err.Unassigned) == 0 && len(err.Undefined) == 0 && len(err.OutOfBounds) == 0 && len(err.Duplicated) == 0 && len(err.Unsatisfied) == 0 {
 		return nil
 	}
 	return err
 }
+
+// satisfied checks that the numeric assignments of a trial satisfy a constraint, constraints on parameters without a
+// numeric assignment are ignored since those assignments are already invalid
+func satisfied(c *redskyv1beta1.Constraint, t *redskyv1beta1.Trial) bool {
+	values := make(map[string]int64, len(t.Spec.Assignments))
+	for i := range t.Spec.Assignments {
+		if v, ok := t.Spec.Assignments[i].Int64Value(); ok {
+			values[t.Spec.Assignments[i].Name] = v
+		}
+	}
+
+	switch {
+	case c.Order != nil:
+		lower, lok := values[c.Order.LowerParameter]
+		upper, uok := values[c.Order.UpperParameter]
+		return !lok || !uok || lower <= upper
+
+	case c.Sum != nil:
+		var sum float64
+		for _, p := range c.Sum.Parameters {
+			v, ok := values[p.Name]
+			if !ok {
+				return true
+			}
+			sum += float64(p.Weight.MilliValue()) / 1000 * float64(v)
+		}
+		bound := float64(c.Sum.Bound.MilliValue()) / 1000
+		if c.Sum.IsUpperBound {
+			return sum <= bound
+		}
+		return sum >= bound
+	}
+
+	return true
+}
+
+// inBounds checks that an assignment is within the domain of the parameter
+func inBounds(p *redskyv1beta1.Parameter, a *redskyv1beta1.Assignment) bool {
+	// Categorical parameters must be assigned one of the allowed values
+	if len(p.Values) > 0 {
+		if a.Value.Type != intstr.String {
+			return false
+		}
+		for _, v := range p.Values {
+			if v == a.Value.StrVal {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Numeric parameters must be assigned an integer in the inclusive range
+	v, ok := a.Int64Value()
+	if !ok {
+		return false
+	}
+	return v >= p.Min.Int64Value() && v <= p.Max.Int64Value()
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestCheckAssignments(t *testing.T) {
+	parameters := []redskyv1beta1.Parameter{
+		{Name: "a", Min: redskyv1beta1.NewParameterBound(0), Max: redskyv1beta1.NewParameterBound(100)},
+		{Name: "b", Min: redskyv1beta1.NewParameterBound(0), Max: redskyv1beta1.NewParameterBound(100)},
+		{Name: "c", Values: []string{"x", "y"}},
+	}
+	order := redskyv1beta1.Constraint{
+		Name:  "a-before-b",
+		Order: &redskyv1beta1.OrderConstraint{LowerParameter: "a", UpperParameter: "b"},
+	}
+	sum := func(bound string, upper bool, weights ...string) redskyv1beta1.Constraint {
+		c := redskyv1beta1.Constraint{Sum: &redskyv1beta1.SumConstraint{Bound: resource.MustParse(bound), IsUpperBound: upper}}
+		for i, w := range weights {
+			c.Sum.Parameters = append(c.Sum.Parameters, redskyv1beta1.SumConstraintParameter{Name: string(rune('a' + i)), Weight: resource.MustParse(w)})
+		}
+		return c
+	}
+
+	cases := []struct {
+		desc        string
+		a, b        int64
+		c           string
+		constraints []redskyv1beta1.Constraint
+		expected    *AssignmentError
+	}{
+		{
+			desc: "valid",
+			a:    10,
+			b:    20,
+			c:    "x",
+		},
+		{
+			desc: "bounds are inclusive",
+			a:    0,
+			b:    100,
+			c:    "y",
+		},
+		{
+			desc:     "out of bounds",
+			a:        -1,
+			b:        101,
+			c:        "z",
+			expected: &AssignmentError{OutOfBounds: []string{"a", "b", "c"}},
+		},
+		{
+			desc:        "order",
+			a:           10,
+			b:           20,
+			c:           "x",
+			constraints: []redskyv1beta1.Constraint{order},
+		},
+		{
+			desc:        "order equal",
+			a:           20,
+			b:           20,
+			c:           "x",
+			constraints: []redskyv1beta1.Constraint{order},
+		},
+		{
+			desc:        "order unsatisfied",
+			a:           21,
+			b:           20,
+			c:           "x",
+			constraints: []redskyv1beta1.Constraint{order},
+			expected:    &AssignmentError{Unsatisfied: []string{"a-before-b"}},
+		},
+		{
+			desc:        "upper bound",
+			a:           40,
+			b:           60,
+			c:           "x",
+			constraints: []redskyv1beta1.Constraint{sum("100", true, "1", "1")},
+		},
+		{
+			desc:        "upper bound exceeded",
+			a:           41,
+			b:           60,
+			c:           "x",
+			constraints: []redskyv1beta1.Constraint{sum("100", true, "1", "1")},
+			expected:    &AssignmentError{Unsatisfied: []string{"constraint 0"}},
+		},
+		{
+			desc:        "lower bound",
+			a:           40,
+			b:           60,
+			c:           "x",
+			constraints: []redskyv1beta1.Constraint{sum("100", false, "1", "1")},
+		},
+		{
+			desc:        "lower bound not reached",
+			a:           39,
+			b:           60,
+			c:           "x",
+			constraints: []redskyv1beta1.Constraint{sum("100", false, "1", "1")},
+			expected:    &AssignmentError{Unsatisfied: []string{"constraint 0"}},
+		},
+		{
+			desc:        "weighted",
+			a:           50,
+			b:           26,
+			c:           "x",
+			constraints: []redskyv1beta1.Constraint{sum("50", true, "500m", "1")},
+			expected:    &AssignmentError{Unsatisfied: []string{"constraint 0"}},
+		},
+		{
+			desc:        "weighted equal",
+			a:           50,
+			b:           25,
+			c:           "x",
+			constraints: []redskyv1beta1.Constraint{sum("50", true, "500m", "1000m")},
+		},
+		{
+			desc:        "negative weight",
+			a:           80,
+			b:           20,
+			c:           "x",
+			constraints: []redskyv1beta1.Constraint{sum("60", true, "1", "-1")},
+		},
+		{
+			desc:        "multiple unsatisfied",
+			a:           90,
+			b:           20,
+			c:           "x",
+			constraints: []redskyv1beta1.Constraint{order, sum("100", true, "1", "1")},
+			expected:    &AssignmentError{Unsatisfied: []string{"a-before-b", "constraint 1"}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			exp := &redskyv1beta1.Experiment{}
+			exp.Spec.Parameters = parameters
+			exp.Spec.Constraints = c.constraints
+
+			tr := &redskyv1beta1.Trial{}
+			tr.Spec.Assignments = []redskyv1beta1.Assignment{
+				{Name: "a", Value: redskyv1beta1.NewAssignmentValue(c.a)},
+				{Name: "b", Value: redskyv1beta1.NewAssignmentValue(c.b)},
+				{Name: "c", Value: redskyv1beta1.NewCategoricalAssignmentValue(c.c)},
+			}
+
+			err := CheckAssignments(tr, exp)
+			if c.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, c.expected, err)
+			}
+		})
+	}
+}
+
+func TestCheckAssignments_Names(t *testing.T) {
+	exp := &redskyv1beta1.Experiment{}
+	exp.Spec.Parameters = []redskyv1beta1.Parameter{
+		{Name: "a", Min: redskyv1beta1.NewParameterBound(0), Max: redskyv1beta1.NewParameterBound(10)},
+		{Name: "b", Min: redskyv1beta1.NewParameterBound(0), Max: redskyv1beta1.NewParameterBound(10)},
+	}
+
+	tr := &redskyv1beta1.Trial{}
+	tr.Spec.Assignments = []redskyv1beta1.Assignment{
+		{Name: "a", Value: redskyv1beta1.NewAssignmentValue(1)},
+		{Name: "a", Value: redskyv1beta1.NewAssignmentValue(2)},
+		{Name: "z", Value: redskyv1beta1.NewAssignmentValue(3)},
+	}
+
+	err := CheckAssignments(tr, exp)
+	assert.Equal(t, &AssignmentError{Unassigned: []string{"b"}, Undefined: []string{"z"}, Duplicated: []string{"a"}}, err)
+	assert.EqualError(t, err, "invalid assignments (unassigned: b; undefined: z; duplicated: a)")
+}
@@ -18,6 +18,7 @@ package validation
 
 import (
 	"fmt"
+	"strconv"
 
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	redskyapi "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
@@ -55,5 +56,38 @@ func CheckDefinition(exp *redskyv1beta1.Experiment, ee *redskyapi.Experiment) er
 		return fmt.Errorf("server and cluster have incompatible metric definitions")
 	}
 
+	// If the experiment is seeded, the server must honor the seed for the results to be reproducible
+	for _, o := range exp.Spec.Optimization {
+		if o.Name != redskyapi.OptimizationSeed {
+			continue
+		}
+		var seed string
+		for _, eo := range ee.Optimization {
+			if eo.Name == redskyapi.OptimizationSeed {
+				seed = eo.Value
+			}
+		}
+		if seed != o.Value {
+			return fmt.Errorf("server did not accept the optimization seed")
+		}
+	}
+
+	return nil
+}
+
+// CheckOptimization ensures the well known optimization configuration values are valid
+func CheckOptimization(exp *redskyv1beta1.Experiment) error {
+	for _, o := range exp.Spec.Optimization {
+		switch o.Name {
+		case redskyapi.OptimizationSeed:
+			if _, err := strconv.ParseInt(o.Value, 10, 64); err != nil {
+				return fmt.Errorf("invalid optimization seed: %s", o.Value)
+			}
+		case redskyapi.OptimizationExperimentBudget, redskyapi.OptimizationParallelTrials:
+			if v, err := strconv.ParseInt(o.Value, 10, 64); err != nil || v < 1 {
+				return fmt.Errorf("invalid optimization %s: %s", o.Name, o.Value)
+			}
+		}
+	}
 	return nil
 }
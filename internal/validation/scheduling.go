@@ -0,0 +1,115 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// schedulingPatch holds the scheduling related fields of a rendered patch
+type schedulingPatch struct {
+	Spec struct {
+		// Pod disruption budget fields
+		MinAvailable   *intstr.IntOrString `json:"minAvailable"`
+		MaxUnavailable *intstr.IntOrString `json:"maxUnavailable"`
+		// Pod topology spread constraints
+		TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints"`
+		// Pod template topology spread constraints
+		Template struct {
+			Spec struct {
+				TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints"`
+			} `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+}
+
+// CheckScheduling ensures the pod disruption budget and topology spread constraint fields of rendered patch data can be
+// satisfied, the nodes are used to verify topology keys and that check is skipped if there are no nodes
+func CheckScheduling(data []byte, nodes []corev1.Node) error {
+	p := &schedulingPatch{}
+	if err := json.Unmarshal(data, p); err != nil {
+		// Not an object (e.g. a JSON patch), there is nothing to check
+		return nil
+	}
+
+	if err := checkDisruptionBudget(p.Spec.MinAvailable, p.Spec.MaxUnavailable); err != nil {
+		return err
+	}
+
+	tsc := append(p.Spec.TopologySpreadConstraints, p.Spec.Template.Spec.TopologySpreadConstraints...)
+	for i := range tsc {
+		if err := checkTopologySpread(&tsc[i], nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDisruptionBudget verifies the pod disruption budget allows for at least one voluntary disruption
+func checkDisruptionBudget(minAvailable, maxUnavailable *intstr.IntOrString) error {
+	if minAvailable != nil && maxUnavailable != nil {
+		return fmt.Errorf("pod disruption budget cannot specify both minAvailable and maxUnavailable")
+	}
+	if maxUnavailable != nil {
+		if v, err := intstr.GetValueFromIntOrPercent(maxUnavailable, 100, true); err != nil {
+			return fmt.Errorf("invalid pod disruption budget maxUnavailable: %w", err)
+		} else if v < 1 {
+			return fmt.Errorf("pod disruption budget maxUnavailable of %s does not allow any disruptions", maxUnavailable.String())
+		}
+	}
+	if minAvailable != nil && minAvailable.Type == intstr.String {
+		if v, err := intstr.GetValueFromIntOrPercent(minAvailable, 100, true); err != nil {
+			return fmt.Errorf("invalid pod disruption budget minAvailable: %w", err)
+		} else if v >= 100 {
+			return fmt.Errorf("pod disruption budget minAvailable of %s does not allow any disruptions", minAvailable.String())
+		}
+	}
+	return nil
+}
+
+// checkTopologySpread verifies the topology spread constraint is valid and, if it is required, that the topology key
+// is present on at least one node
+func checkTopologySpread(tsc *corev1.TopologySpreadConstraint, nodes []corev1.Node) error {
+	if tsc.MaxSkew < 1 {
+		return fmt.Errorf("topology spread maxSkew must be at least 1, was %d", tsc.MaxSkew)
+	}
+	if tsc.TopologyKey == "" {
+		return fmt.Errorf("topology spread constraint is missing a topologyKey")
+	}
+
+	switch tsc.WhenUnsatisfiable {
+	case corev1.ScheduleAnyway:
+		return nil
+	case corev1.DoNotSchedule:
+	default:
+		return fmt.Errorf("invalid topology spread whenUnsatisfiable %q, expected %s or %s", tsc.WhenUnsatisfiable, corev1.DoNotSchedule, corev1.ScheduleAnyway)
+	}
+
+	if len(nodes) == 0 {
+		return nil
+	}
+	for i := range nodes {
+		if _, ok := nodes[i].Labels[tsc.TopologyKey]; ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("no nodes have the topology key %q, pods would never be scheduled", tsc.TopologyKey)
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// CertProvisionerNone indicates webhook certificates are not managed by the controller
+	CertProvisionerNone = "none"
+	// CertProvisionerCertManager indicates webhook certificates are issued by cert-manager and mounted into the controller
+	CertProvisionerCertManager = "cert-manager"
+	// CertProvisionerSelfSigned indicates webhook certificates are generated and rotated by the controller itself
+	CertProvisionerSelfSigned = "self-signed"
+
+	// CAFile is the name of the CA certificate file in the certificate directory
+	CAFile = "ca.crt"
+	// CertFile is the name of the serving certificate file in the certificate directory
+	CertFile = "tls.crt"
+	// KeyFile is the name of the serving key file in the certificate directory
+	KeyFile = "tls.key"
+)
+
+// SelfSigned describes the self-signed certificates used to serve webhooks
+type SelfSigned struct {
+	// CertDir is the directory the webhook server reads certificates from
+	CertDir string
+	// ServiceName is the name of the service fronting the webhook server
+	ServiceName string
+	// Namespace is the namespace of the service fronting the webhook server
+	Namespace string
+	// Validity is how long generated certificates are valid for
+	Validity time.Duration
+	// RotateBefore is how long before expiration certificates are regenerated
+	RotateBefore time.Duration
+}
+
+// Ensure generates new certificates if they do not exist or are about to expire, the time at which the certificates
+// should be checked again is returned
+func (s *SelfSigned) Ensure(now time.Time) (time.Time, error) {
+	if notAfter, err := s.notAfter(); err == nil && now.Before(notAfter.Add(-s.RotateBefore)) {
+		return notAfter.Add(-s.RotateBefore), nil
+	}
+
+	notAfter := now.Add(s.Validity)
+	if err := s.generate(now, notAfter); err != nil {
+		return now, err
+	}
+	return notAfter.Add(-s.RotateBefore), nil
+}
+
+// CABundle returns the PEM encoded CA certificate used to sign the serving certificate
+func (s *SelfSigned) CABundle() ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(s.CertDir, CAFile))
+}
+
+// notAfter returns the expiration time of the current serving certificate
+func (s *SelfSigned) notAfter() (time.Time, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.CertDir, CertFile))
+	if err != nil {
+		return time.Time{}, err
+	}
+	b, _ := pem.Decode(data)
+	if b == nil {
+		return time.Time{}, fmt.Errorf("invalid certificate: %s", CertFile)
+	}
+	cert, err := x509.ParseCertificate(b.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return cert.NotAfter, nil
+}
+
+// generate creates a new CA and serving certificate
+func (s *SelfSigned) generate(notBefore, notAfter time.Time) error {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(notBefore.UnixNano()),
+		Subject:               pkix.Name{CommonName: "redskyops-webhook-ca"},
+		NotBefore:             notBefore.Add(-time.Hour),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		return err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	host := fmt.Sprintf("%s.%s.svc", s.ServiceName, s.Namespace)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(notBefore.UnixNano() + 1),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{s.ServiceName, fmt.Sprintf("%s.%s", s.ServiceName, s.Namespace), host, host + ".cluster.local"},
+		NotBefore:    notBefore.Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, key.Public(), caKey)
+	if err != nil {
+		return err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	// Write the key before the certificate so the webhook server never sees a mismatched pair for long
+	if err := os.MkdirAll(s.CertDir, 0700); err != nil {
+		return err
+	}
+	if err := writePEM(filepath.Join(s.CertDir, CAFile), "CERTIFICATE", caDER); err != nil {
+		return err
+	}
+	if err := writePEM(filepath.Join(s.CertDir, KeyFile), "EC PRIVATE KEY", keyDER); err != nil {
+		return err
+	}
+	return writePEM(filepath.Join(s.CertDir, CertFile), "CERTIFICATE", der)
+}
+
+// writePEM atomically writes a single PEM block to the named file
+func writePEM(filename, blockType string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := pem.Encode(f, &pem.Block{Type: blockType, Bytes: data}); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filename)
+}
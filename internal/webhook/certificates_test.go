@@ -0,0 +1,77 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfSigned_Ensure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webhook")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := &SelfSigned{
+		CertDir:      dir,
+		ServiceName:  "redsky-webhook-service",
+		Namespace:    "redsky-system",
+		Validity:     24 * time.Hour,
+		RotateBefore: time.Hour,
+	}
+	now := time.Now()
+
+	// Initial generation
+	next, err := s.Ensure(now)
+	require.NoError(t, err)
+	assert.WithinDuration(t, now.Add(23*time.Hour), next, time.Second)
+
+	cert, err := tls.LoadX509KeyPair(filepath.Join(dir, CertFile), filepath.Join(dir, KeyFile))
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+
+	caBundle, err := s.CABundle()
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	require.True(t, roots.AppendCertsFromPEM(caBundle))
+	_, err = leaf.Verify(x509.VerifyOptions{DNSName: "redsky-webhook-service.redsky-system.svc", Roots: roots})
+	assert.NoError(t, err)
+
+	// Current certificates are not regenerated
+	again, err := s.Ensure(now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.WithinDuration(t, next, again, time.Second)
+	same, err := s.CABundle()
+	require.NoError(t, err)
+	assert.Equal(t, caBundle, same)
+
+	// Expiring certificates are regenerated
+	_, err = s.Ensure(now.Add(23*time.Hour + time.Minute))
+	require.NoError(t, err)
+	rotated, err := s.CABundle()
+	require.NoError(t, err)
+	assert.NotEqual(t, caBundle, rotated)
+}
@@ -17,18 +17,31 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"strings"
+	"time"
 
 	redskyv1alpha1 "github.com/redskyops/redskyops-controller/api/v1alpha1"
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	"github.com/redskyops/redskyops-controller/controllers"
+	"github.com/redskyops/redskyops-controller/internal/audit"
 	"github.com/redskyops/redskyops-controller/internal/config"
 	"github.com/redskyops/redskyops-controller/internal/controller"
+	"github.com/redskyops/redskyops-controller/internal/events"
+	"github.com/redskyops/redskyops-controller/internal/experiment"
+	"github.com/redskyops/redskyops-controller/internal/featuregate"
+	"github.com/redskyops/redskyops-controller/internal/hmacauth"
+	"github.com/redskyops/redskyops-controller/internal/tlspolicy"
 	"github.com/redskyops/redskyops-controller/internal/version"
+	"github.com/redskyops/redskyops-controller/internal/webhook"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -54,83 +67,284 @@ func main() {
 
 	var metricsAddr string
 	var enableLeaderElection bool
+	var namespace string
+	var webhookPort int
+	var webhookCertProvisioner string
+	selfSigned := &webhook.SelfSigned{Validity: 365 * 24 * time.Hour, RotateBefore: 30 * 24 * time.Hour}
+	var tlsPolicy tlspolicy.Policy
+	var tlsCipherSuites string
+	var concurrencyLimit experiment.ConcurrencyLimit
+	var hmacCredential config.HMACCredential
+	var hmacSecretFile string
+	var bearerTokenCredential config.BearerTokenCredential
+	var serviceAccountToken bool
+	var auditLog string
+	var auditActor string
+	var eventPublisher string
+	var eventTopicPrefix string
+	var eventHMACKeyID string
+	var eventHMACSecretFile string
+	var trialUpdateInterval time.Duration
+	var experimentBackoff, trialBackoff controller.Backoff
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&namespace, "namespace", os.Getenv("WATCH_NAMESPACE"), "Restrict the manager to a single namespace, defaults to all namespaces.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook server binds to.")
+	flag.StringVar(&selfSigned.CertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs", "The directory containing the webhook server certificates.")
+	flag.StringVar(&webhookCertProvisioner, "webhook-cert-provisioner", webhook.CertProvisionerNone,
+		"How webhook certificates are provisioned; one of: none|cert-manager|self-signed.")
+	flag.StringVar(&selfSigned.ServiceName, "webhook-service-name", "redsky-webhook-service", "The name of the webhook service, used for self-signed certificates.")
+	flag.StringVar(&tlsPolicy.MinVersion, "tls-min-version", "", "Minimum TLS version for outbound connections, e.g. 1.2.")
+	flag.StringVar(&tlsCipherSuites, "tls-cipher-suites", "", "Comma-separated list of cipher suites allowed for outbound connections.")
+	flag.BoolVar(&tlsPolicy.FIPS, "tls-fips", false, "Restrict outbound connections to FIPS approved TLS versions and cipher suites.")
+	flag.IntVar(&concurrencyLimit.MaxTrials, "max-concurrent-trials", 0, "The maximum number of active trials across all experiments, 0 for no limit.")
+	flag.IntVar(&concurrencyLimit.MaxTrialsPerNamespace, "max-concurrent-trials-per-namespace", 0, "The maximum number of active trials in a namespace across all experiments, 0 for no limit.")
+	flag.StringVar(&hmacCredential.KeyID, "hmac-key-id", "", "The key identifier used to sign requests to the Red Sky API.")
+	flag.StringVar(&hmacSecretFile, "hmac-secret-file", "", "The file containing the shared secret used to sign requests to the Red Sky API.")
+	flag.StringVar(&bearerTokenCredential.BearerTokenFile, "bearer-token-file", "", "The file containing a bearer token used to authorize requests to the Red Sky API, re-read when it changes.")
+	flag.BoolVar(&serviceAccountToken, "service-account-token", false, "Use the pod's service account token to authorize requests to the Red Sky API.")
+	flag.StringVar(&auditLog, "audit-log", "", "The file or webhook URL which receives a record of every mutation, disabled if empty.")
+	flag.StringVar(&auditActor, "audit-actor", "", "The identity recorded in the audit log, defaults to the service account name.")
+	flag.StringVar(&eventPublisher, "event-publisher", "", "The NATS (nats://), CloudEvents sink (cloudevents+http(s)://) or Kafka REST proxy (http(s)://) URL which receives trial and experiment lifecycle events, disabled if empty.")
+	flag.StringVar(&eventTopicPrefix, "event-topic-prefix", "redskyops.", "The prefix of the topic (or subject) each lifecycle event type is published to.")
+	flag.StringVar(&eventHMACKeyID, "event-hmac-key-id", "", "The key identifier used to sign requests to a CloudEvents sink.")
+	flag.StringVar(&eventHMACSecretFile, "event-hmac-secret-file", "", "The file containing the shared secret used to sign requests to a CloudEvents sink, unsigned if empty.")
+	flag.DurationVar(&trialUpdateInterval, "trial-update-interval", 0, "The minimum time between trial updates that only change probe times, 0 to write every update.")
+	// Feature gates default to the environment so they are also visible to `/manager version`
+	if err := featuregate.Default.Set(os.Getenv("FEATURE_GATES")); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "invalid FEATURE_GATES:", err.Error())
+		os.Exit(1)
+	}
+	flag.Var(featuregate.Default, "feature-gates", featuregate.Usage())
+	addBackoffFlags(&experimentBackoff, "experiment")
+	addBackoffFlags(&trialBackoff, "trial")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(func(o *zap.Options) {
 		o.Development = false
 	}))
 
+	tlsPolicy.CipherSuites = tlspolicy.SplitCipherSuites(tlsCipherSuites)
+	if err := tlsPolicy.Apply(); err != nil {
+		setupLog.Error(err, "unable to apply TLS policy")
+		os.Exit(1)
+	}
+
+	if hmacSecretFile != "" {
+		secret, err := ioutil.ReadFile(hmacSecretFile)
+		if err != nil {
+			setupLog.Error(err, "unable to read HMAC secret")
+			os.Exit(1)
+		}
+		hmacCredential.Secret = strings.TrimSpace(string(secret))
+	}
+
+	if serviceAccountToken {
+		bearerTokenCredential.BearerTokenFile = config.ServiceAccountTokenFile
+	}
+
 	v := version.GetInfo()
-	setupLog.Info("Red Sky Ops Controller", "version", v.String(), "gitCommit", v.GitCommit)
+	setupLog.Info("Red Sky Ops Controller", "version", v.String(), "gitCommit", v.GitCommit, "fipsOnly", tlspolicy.FIPSOnly(), "featureGates", featuregate.Default.String())
+	for f, enabled := range featuregate.Default.Status() {
+		if enabled {
+			controller.FeatureEnabled.WithLabelValues(string(f)).Set(1)
+		} else {
+			controller.FeatureEnabled.WithLabelValues(string(f)).Set(0)
+		}
+	}
+
+	// Webhook certificates are only provisioned by the controller if the feature is enabled
+	if !featuregate.Enabled(featuregate.Webhooks) && webhookCertProvisioner == webhook.CertProvisionerSelfSigned {
+		setupLog.Info("Webhooks feature is disabled, skipping self-signed certificates")
+		webhookCertProvisioner = webhook.CertProvisionerNone
+	}
+
+	// Self-signed certificates must exist before the webhook server starts
+	selfSigned.Namespace = os.Getenv("POD_NAMESPACE")
+	if selfSigned.Namespace == "" {
+		selfSigned.Namespace = "redsky-system"
+	}
+	switch webhookCertProvisioner {
+	case webhook.CertProvisionerNone, webhook.CertProvisionerCertManager:
+		// Certificates are either unused or mounted into the certificate directory
+	case webhook.CertProvisionerSelfSigned:
+		if _, err := selfSigned.Ensure(time.Now()); err != nil {
+			setupLog.Error(err, "unable to generate webhook certificates")
+			os.Exit(1)
+		}
+	default:
+		setupLog.Error(fmt.Errorf("unknown webhook certificate provisioner: %s", webhookCertProvisioner), "invalid arguments")
+		os.Exit(1)
+	}
 
 	mgr, err := ctrl.NewManager(controller.WithConversion(ctrl.GetConfigOrDie(), scheme), ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
 		LeaderElection:     enableLeaderElection,
+		Namespace:          namespace,
+		Port:               webhookPort,
+		CertDir:            selfSigned.CertDir,
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	// Optionally record every in-cluster and remote mutation
+	kubeClient := mgr.GetClient()
+	var apiTransport http.RoundTripper
+	if auditLog != "" {
+		sink, err := audit.NewSink(auditLog, ctrl.Log.WithName("audit"))
+		if err != nil {
+			setupLog.Error(err, "unable to create audit log")
+			os.Exit(1)
+		}
+		if auditActor == "" {
+			auditActor = audit.ServiceAccountName(mgr.GetConfig().BearerToken)
+		}
+		auditor := &audit.Auditor{Sink: sink, Actor: auditActor, Log: ctrl.Log.WithName("audit")}
+		kubeClient = &audit.Client{Client: kubeClient, Auditor: auditor, Scheme: mgr.GetScheme()}
+		apiTransport = &audit.Transport{Auditor: auditor}
+	}
+
+	// The controller-runtime client cannot read pod logs, use a typed client for that
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		setupLog.Error(err, "unable to create clientset")
+		os.Exit(1)
+	}
+
+	// Coalesce frequent trial updates to reduce the write load on the API server
+	kubeClient = &controller.CoalescingClient{Client: kubeClient, Interval: trialUpdateInterval}
+
 	if err = (&controllers.ExperimentReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Experiment"),
+		Client:   kubeClient,
+		Log:      ctrl.Log.WithName("controllers").WithName("Experiment"),
+		Backoff:  experimentBackoff,
+		Recorder: mgr.GetEventRecorderFor("experiment-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Experiment")
 		os.Exit(1)
 	}
-	if err = (&controllers.ServerReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Server"),
+	if err = (&controllers.ExperimentScheduleReconciler{
+		Client: kubeClient,
+		Log:    ctrl.Log.WithName("controllers").WithName("ExperimentSchedule"),
 		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ExperimentSchedule")
+		os.Exit(1)
+	}
+	// Optionally publish lifecycle events so other systems do not need to poll
+	if eventPublisher != "" {
+		publisher, err := events.NewPublisher(eventPublisher)
+		if err != nil {
+			setupLog.Error(err, "unable to create event publisher")
+			os.Exit(1)
+		}
+		if ce, ok := publisher.(*events.CloudEventsPublisher); ok && eventHMACSecretFile != "" {
+			secret, err := ioutil.ReadFile(eventHMACSecretFile)
+			if err != nil {
+				setupLog.Error(err, "unable to read event HMAC secret")
+				os.Exit(1)
+			}
+			ce.Client = &http.Client{
+				Timeout:   5 * time.Second,
+				Transport: &hmacauth.Transport{KeyID: eventHMACKeyID, Secret: []byte(strings.TrimSpace(string(secret)))},
+			}
+		}
+		if err = (&controllers.EventReconciler{
+			Client:  kubeClient,
+			Log:     ctrl.Log.WithName("controllers").WithName("Event"),
+			Emitter: &events.Emitter{Publisher: publisher, TopicPrefix: eventTopicPrefix},
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Event")
+			os.Exit(1)
+		}
+	}
+	// Only reconcile with the Red Sky API if we are authorized to use it
+	cfg := &config.RedSkyConfig{}
+	cfg.Overrides.HMACCredential = hmacCredential
+	cfg.Overrides.BearerTokenCredential = bearerTokenCredential
+	if err := cfg.Load(); err != nil {
+		setupLog.Error(err, "unable to load Red Sky configuration")
+		os.Exit(1)
+	}
+	experimentsAPI, err := controllers.NewExperimentsAPI(context.Background(), cfg, mgr.GetConfig(), apiTransport)
+	if err != nil {
+		setupLog.Error(err, "unable to create Red Sky API")
+		os.Exit(1)
+	}
+	if experimentsAPI == nil {
+		setupLog.Info("Red Sky API is unavailable, skipping setup")
+	} else if err = (&controllers.ServerReconciler{
+		Client:           kubeClient,
+		Log:              ctrl.Log.WithName("controllers").WithName("Server"),
+		Scheme:           mgr.GetScheme(),
+		ExperimentsAPI:   experimentsAPI,
+		ConcurrencyLimit: concurrencyLimit,
+		Backoff:          experimentBackoff,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Server")
 		os.Exit(1)
 	}
 	if err = (&controllers.SetupReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Setup"),
-		Scheme: mgr.GetScheme(),
+		Client:  kubeClient,
+		Log:     ctrl.Log.WithName("controllers").WithName("Setup"),
+		Scheme:  mgr.GetScheme(),
+		Backoff: trialBackoff,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Setup")
 		os.Exit(1)
 	}
 	if err = (&controllers.PatchReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Patch"),
-		Scheme: mgr.GetScheme(),
+		Client:  kubeClient,
+		Log:     ctrl.Log.WithName("controllers").WithName("Patch"),
+		Scheme:  mgr.GetScheme(),
+		Backoff: trialBackoff,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Patch")
 		os.Exit(1)
 	}
 	if err = (&controllers.ReadyReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Ready"),
-		Scheme: mgr.GetScheme(),
+		Client:  kubeClient,
+		Log:     ctrl.Log.WithName("controllers").WithName("Ready"),
+		Scheme:  mgr.GetScheme(),
+		Backoff: trialBackoff,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Ready")
 		os.Exit(1)
 	}
 	if err = (&controllers.TrialJobReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Trial"),
-		Scheme: mgr.GetScheme(),
+		Client:  kubeClient,
+		Log:     ctrl.Log.WithName("controllers").WithName("Trial"),
+		Scheme:  mgr.GetScheme(),
+		Backoff: trialBackoff,
+		Pods:    clientset.CoreV1(),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Trial")
 		os.Exit(1)
 	}
 	if err = (&controllers.MetricReconciler{
-		Client: mgr.GetClient(),
-		Log:    ctrl.Log.WithName("controllers").WithName("Metric"),
-		Scheme: mgr.GetScheme(),
+		Client:  kubeClient,
+		Log:     ctrl.Log.WithName("controllers").WithName("Metric"),
+		Scheme:  mgr.GetScheme(),
+		Backoff: trialBackoff,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Metric")
 		os.Exit(1)
 	}
+	if webhookCertProvisioner == webhook.CertProvisionerSelfSigned {
+		if err = (&controllers.WebhookCertificateReconciler{
+			Client:     kubeClient,
+			Log:        ctrl.Log.WithName("controllers").WithName("WebhookCertificate"),
+			Scheme:     mgr.GetScheme(),
+			SelfSigned: selfSigned,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "WebhookCertificate")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	setupLog.Info("starting manager")
@@ -140,11 +354,25 @@ func main() {
 	}
 }
 
+// addBackoffFlags registers the flags used to tune how a group of controllers retry failed reconciles
+func addBackoffFlags(b *controller.Backoff, name string) {
+	flag.DurationVar(&b.BaseDelay, name+"-backoff-base-delay", 0, fmt.Sprintf("The delay before the first retry of a failed %s reconcile, 0 for the default.", name))
+	flag.DurationVar(&b.MaxDelay, name+"-backoff-max-delay", 0, fmt.Sprintf("The maximum delay between retries of a failed %s reconcile, 0 for the default.", name))
+	flag.Float64Var(&b.QPS, name+"-backoff-qps", 0, fmt.Sprintf("The overall rate of %s reconcile retries, 0 for the default.", name))
+	flag.IntVar(&b.Burst, name+"-backoff-burst", 0, fmt.Sprintf("The bucket size for the overall rate of %s reconcile retries, 0 for the default.", name))
+}
+
 // handleDebugArgs will make the process dump and exit if the first arg is either "version" or "config"
 func handleDebugArgs() {
 	if len(os.Args) > 1 {
 		if os.Args[1] == "version" {
-			if output, err := json.Marshal(version.GetInfo()); err != nil {
+			// Feature gates can only be reported from the environment since the flags are not available
+			_ = featuregate.Default.Set(os.Getenv("FEATURE_GATES"))
+			info := struct {
+				*version.Info
+				FeatureGates map[featuregate.Feature]bool `json:"featureGates"`
+			}{Info: version.GetInfo(), FeatureGates: featuregate.Default.Status()}
+			if output, err := json.Marshal(info); err != nil {
 				os.Exit(1)
 			} else {
 				fmt.Println(string(output))
@@ -0,0 +1,180 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redskyapi
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultCacheSize is the default maximum number of responses retained by a caching transport
+const DefaultCacheSize = 256
+
+// CachingTransport is a round tripper that retains successful GET responses (e.g. the experiment and trial lists) which
+// carry an "ETag" or "Last-Modified" validator; subsequent requests for the same URL (with the same "Authorization" and
+// "Accept" headers) are made conditional so the server can respond with "304 Not Modified" instead of re-sending an
+// unchanged payload
+type CachingTransport struct {
+	// Base is the transport used to make the request, uses the default transport if nil
+	Base http.RoundTripper
+	// MaxEntries is the maximum number of responses to retain, defaults to DefaultCacheSize
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// varyHeaders are the request headers which must match for a retained response to be used
+var varyHeaders = []string{"Authorization", "Accept"}
+
+// cacheEntry is a single retained response
+type cacheEntry struct {
+	vary       []string
+	header     http.Header
+	body       []byte
+	storedTime time.Time
+}
+
+// matches checks to see if the entry was retained for a request with the same vary headers
+func (e *cacheEntry) matches(vary []string) bool {
+	for i := range vary {
+		if e.vary[i] != vary[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// requestVary returns the values of the vary headers from the request
+func requestVary(req *http.Request) []string {
+	vary := make([]string, len(varyHeaders))
+	for i, h := range varyHeaders {
+		vary[i] = req.Header.Get(h)
+	}
+	return vary
+}
+
+// RoundTrip delegates to the base transport, making GET requests conditional when a previous response was retained
+func (t *CachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.URL.String()
+
+	// Any other method invalidates the retained response for the URL
+	if req.Method != http.MethodGet {
+		t.remove(key)
+		return t.base().RoundTrip(req)
+	}
+
+	// Only make the request conditional if the caller did not already do so, the retained response must have been
+	// requested using the same credentials and representation
+	vary := requestVary(req)
+	entry := t.get(key)
+	if entry != nil && entry.matches(vary) && req.Header.Get("If-None-Match") == "" && req.Header.Get("If-Modified-Since") == "" {
+		req = req.Clone(req.Context())
+		if etag := entry.header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if lastModified := entry.header.Get("Last-Modified"); lastModified != "" {
+			req.Header.Set("If-Modified-Since", lastModified)
+		}
+	} else {
+		entry = nil
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry != nil:
+		// Replay the retained response, preferring any updated headers from the server
+		_ = resp.Body.Close()
+		header := entry.header.Clone()
+		for k, v := range resp.Header {
+			header[k] = v
+		}
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		resp.Header = header
+		resp.ContentLength = int64(len(entry.body))
+		resp.Body = ioutil.NopCloser(bytes.NewReader(entry.body))
+		return resp, nil
+
+	case resp.StatusCode == http.StatusOK && (resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""):
+		// Retain the response so the next request can be conditional
+		body, err := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		t.put(key, &cacheEntry{vary: vary, header: resp.Header.Clone(), body: body, storedTime: time.Now()})
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		return resp, nil
+
+	default:
+		t.remove(key)
+		return resp, nil
+	}
+}
+
+func (t *CachingTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *CachingTransport) get(key string) *cacheEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.entries[key]
+}
+
+func (t *CachingTransport) put(key string, entry *cacheEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.entries == nil {
+		t.entries = make(map[string]*cacheEntry)
+	}
+
+	// Evict the oldest response to make room
+	maxEntries := t.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheSize
+	}
+	if _, ok := t.entries[key]; !ok && len(t.entries) >= maxEntries {
+		var oldest string
+		for k, e := range t.entries {
+			if oldest == "" || e.storedTime.Before(t.entries[oldest].storedTime) {
+				oldest = k
+			}
+		}
+		delete(t.entries, oldest)
+	}
+
+	t.entries[key] = entry
+}
+
+func (t *CachingTransport) remove(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.entries, key)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redskyapi
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cacheServer is a test server which serves a body with a validator and records the conditional request headers
+type cacheServer struct {
+	*httptest.Server
+	body         string
+	etag         string
+	lastModified string
+	conditions   []string
+}
+
+func newCacheServer() *cacheServer {
+	s := &cacheServer{body: "v1", etag: `"1"`}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.conditions = append(s.conditions, r.Header.Get("If-None-Match")+r.Header.Get("If-Modified-Since"))
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if s.etag != "" {
+			w.Header().Set("ETag", s.etag)
+		}
+		if s.lastModified != "" {
+			w.Header().Set("Last-Modified", s.lastModified)
+		}
+		if (s.etag != "" && r.Header.Get("If-None-Match") == s.etag) ||
+			(s.lastModified != "" && r.Header.Get("If-Modified-Since") == s.lastModified) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		_, _ = w.Write([]byte(s.body))
+	}))
+	return s
+}
+
+// cacheGet performs a GET request with the supplied header name/value pairs, returning the status code and body
+func cacheGet(t *testing.T, c *http.Client, u string, header ...string) (int, string) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	require.NoError(t, err)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(b)
+}
+
+func TestCachingTransport_ETag(t *testing.T) {
+	srv := newCacheServer()
+	defer srv.Close()
+	c := &http.Client{Transport: &CachingTransport{}}
+
+	code, body := cacheGet(t, c, srv.URL+"/experiments/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "v1", body)
+
+	// The second request is revalidated and the retained body is replayed
+	code, body = cacheGet(t, c, srv.URL+"/experiments/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "v1", body)
+
+	// A changed resource is fetched again
+	srv.body, srv.etag = "v2", `"2"`
+	code, body = cacheGet(t, c, srv.URL+"/experiments/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "v2", body)
+
+	assert.Equal(t, []string{"", `"1"`, `"1"`}, srv.conditions)
+}
+
+func TestCachingTransport_LastModified(t *testing.T) {
+	srv := newCacheServer()
+	defer srv.Close()
+	srv.etag, srv.lastModified = "", "Mon, 01 Jun 2020 12:00:00 GMT"
+	c := &http.Client{Transport: &CachingTransport{}}
+
+	cacheGet(t, c, srv.URL+"/experiments/")
+	code, body := cacheGet(t, c, srv.URL+"/experiments/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "v1", body)
+	assert.Equal(t, []string{"", srv.lastModified}, srv.conditions)
+}
+
+func TestCachingTransport_Vary(t *testing.T) {
+	srv := newCacheServer()
+	defer srv.Close()
+	c := &http.Client{Transport: &CachingTransport{}}
+
+	cacheGet(t, c, srv.URL+"/experiments/", "Authorization", "Bearer a")
+
+	// A response retained for other credentials or another representation is not used
+	cacheGet(t, c, srv.URL+"/experiments/", "Authorization", "Bearer b")
+	cacheGet(t, c, srv.URL+"/experiments/", "Authorization", "Bearer b", "Accept", "text/plain")
+	cacheGet(t, c, srv.URL+"/experiments/", "Authorization", "Bearer b", "Accept", "text/plain")
+	assert.Equal(t, []string{"", "", "", `"1"`}, srv.conditions)
+}
+
+func TestCachingTransport_Invalidate(t *testing.T) {
+	srv := newCacheServer()
+	defer srv.Close()
+	c := &http.Client{Transport: &CachingTransport{}}
+
+	cacheGet(t, c, srv.URL+"/experiments/test")
+	resp, err := c.Post(srv.URL+"/experiments/test", "application/json", strings.NewReader("{}"))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	cacheGet(t, c, srv.URL+"/experiments/test")
+	assert.Equal(t, []string{"", "", ""}, srv.conditions)
+}
+
+func TestCachingTransport_Eviction(t *testing.T) {
+	srv := newCacheServer()
+	defer srv.Close()
+	c := &http.Client{Transport: &CachingTransport{MaxEntries: 1}}
+
+	cacheGet(t, c, srv.URL+"/a")
+	cacheGet(t, c, srv.URL+"/b")
+	cacheGet(t, c, srv.URL+"/a")
+	cacheGet(t, c, srv.URL+"/a")
+	assert.Equal(t, []string{"", "", "", `"1"`}, srv.conditions)
+}
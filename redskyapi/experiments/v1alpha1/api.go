@@ -19,14 +19,18 @@ package v1alpha1
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
+	"strings"
 	"time"
 
 	"golang.org/x/oauth2"
 )
 
+// Path is relative to redskyapi/experiments/v1alpha1
+//go:generate go run ../../../cmd/openapi/main.go --header ../../../hack/boilerplate.go.txt --package v1alpha1 --document ../../../docs/api/openapi.json
+
 const (
 	endpointExperiment = "/experiments/"
 
@@ -37,6 +41,9 @@ const (
 	relationLabels    = "https://carbonrelay.com/rel/labels"
 	relationTrials    = "https://carbonrelay.com/rel/trials"
 	relationNextTrial = "https://carbonrelay.com/rel/next-trial"
+
+	// HeaderIdempotencyKey is the request header used to identify retries of the same request
+	HeaderIdempotencyKey = "Idempotency-Key"
 )
 
 // Meta is used to collect resource metadata from the response
@@ -70,6 +77,8 @@ func (m *Metadata) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// ErrorType identifies the API specific error condition, error types are themselves errors so they can be used as the
+// target of errors.Is
 type ErrorType string
 
 const (
@@ -86,33 +95,84 @@ const (
 	ErrUnexpected             ErrorType = "unexpected"
 )
 
+func (t ErrorType) Error() string {
+	return strings.ReplaceAll(string(t), "-", " ")
+}
+
+// FieldViolation describes a single invalid field of a rejected request
+type FieldViolation struct {
+	// The path to the invalid field, e.g. "parameters[0].bounds"
+	Field string `json:"field"`
+	// A description of why the field is invalid
+	Description string `json:"description"`
+}
+
 // Error represents the API specific error messages and may be used in response to HTTP status codes
 type Error struct {
-	Type       ErrorType     `json:"-"`
-	Message    string        `json:"error"`
-	RetryAfter time.Duration `json:"-"`
-	Location   string        `json:"-"`
+	Type            ErrorType        `json:"-"`
+	Message         string           `json:"error"`
+	FieldViolations []FieldViolation `json:"fieldViolations,omitempty"`
+	StatusCode      int              `json:"-"`
+	RetryAfter      time.Duration    `json:"-"`
+	Location        string           `json:"-"`
 }
 
 func (e *Error) Error() string {
 	return e.Message
 }
 
+// Is allows an error to be compared to either an error type or another error with the same type
+func (e *Error) Is(target error) bool {
+	switch t := target.(type) {
+	case ErrorType:
+		return e.Type == t
+	case *Error:
+		return e.Type == t.Type
+	}
+	return false
+}
+
+// UnmarshalJSON accepts either an "error" or "message" field for the error message
+func (e *Error) UnmarshalJSON(b []byte) error {
+	type errorBody Error
+	raw := &struct {
+		*errorBody
+		AltMessage string `json:"message"`
+	}{errorBody: (*errorBody)(e)}
+	if err := json.Unmarshal(b, raw); err != nil {
+		return err
+	}
+	if e.Message == "" {
+		e.Message = raw.AltMessage
+	}
+	return nil
+}
+
+// IsErrorType checks to see if the error (or any error it wraps) is an API error of the specified type
+func IsErrorType(err error, t ErrorType) bool {
+	return errors.Is(err, t)
+}
+
+// AsError finds the first API error in the error chain
+func AsError(err error) (*Error, bool) {
+	var rserr *Error
+	if errors.As(err, &rserr) {
+		return rserr, true
+	}
+	return nil, false
+}
+
 // IsUnauthorized check to see if the error is an "unauthorized" error
 func IsUnauthorized(err error) bool {
 	// OAuth errors (e.g. fetching tokens) will come out of `Do` and will be wrapped in url.Error
-	if uerr, ok := err.(*url.Error); ok {
-		err = uerr.Unwrap()
-	}
-	if rerr, ok := err.(*oauth2.RetrieveError); ok {
+	var rerr *oauth2.RetrieveError
+	if errors.As(err, &rerr) {
 		if rerr.Response.StatusCode == http.StatusUnauthorized {
 			return true
 		}
 	}
-	if rserr, ok := err.(*Error); ok {
-		if rserr.Type == ErrUnauthorized {
-			return true
-		}
+	if IsErrorType(err, ErrUnauthorized) {
+		return true
 	}
 	// TODO This is a hack to work around the way we generate errors during JWT validation
 	if err != nil && err.Error() == "no Bearer token" {
@@ -137,12 +197,17 @@ type API interface {
 	GetExperimentByName(context.Context, ExperimentName) (Experiment, error)
 	GetExperiment(context.Context, string) (Experiment, error)
 	CreateExperiment(context.Context, ExperimentName, Experiment) (Experiment, error)
+	UpdateExperiment(context.Context, string, ExperimentPatch) (Experiment, error)
 	DeleteExperiment(context.Context, string) error
 	GetAllTrials(context.Context, string, *TrialListQuery) (TrialList, error)
+	GetAllTrialsByPage(context.Context, string) (TrialList, error)
 	CreateTrial(context.Context, string, TrialAssignments) (string, error) // TODO Should this return TrialAssignments?
 	NextTrial(context.Context, string) (TrialAssignments, error)
 	ReportTrial(context.Context, string, TrialValues) error
+	ReportTrials(context.Context, string, []TrialReport) error
 	AbandonRunningTrial(context.Context, string) error
 	LabelExperiment(context.Context, string, ExperimentLabels) error
 	LabelTrial(context.Context, string, TrialLabels) error
+	AddTrialLabels(context.Context, string, map[string]string) error
+	RemoveTrialLabels(context.Context, string, ...string) error
 }
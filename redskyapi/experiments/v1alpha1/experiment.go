@@ -47,6 +47,16 @@ type Optimization struct {
 	Value string `json:"value"`
 }
 
+const (
+	// OptimizationSeed is the name of the optimization configuration used to seed the random number generator of the
+	// optimizer; experiments created with the same seed will produce the same burn-in trials
+	OptimizationSeed = "seed"
+	// OptimizationExperimentBudget is the name of the optimization configuration for the total number of trials
+	OptimizationExperimentBudget = "experimentBudget"
+	// OptimizationParallelTrials is the name of the optimization configuration for the number of concurrent trials
+	OptimizationParallelTrials = "parallelTrials"
+)
+
 type Metric struct {
 	// The name of the metric.
 	Name string `json:"name"`
@@ -96,8 +106,9 @@ type Constraint struct {
 type ParameterType string
 
 const (
-	ParameterTypeInteger ParameterType = "int"
-	ParameterTypeDouble  ParameterType = "double"
+	ParameterTypeInteger     ParameterType = "int"
+	ParameterTypeDouble      ParameterType = "double"
+	ParameterTypeCategorical ParameterType = "categorical"
 )
 
 type Bounds struct {
@@ -115,6 +126,10 @@ type Parameter struct {
 	Type ParameterType `json:"type"`
 	// The domain of the parameter.
 	Bounds Bounds `json:"bounds"`
+	// The discrete values of a categorical parameter.
+	Values []string `json:"values,omitempty"`
+	// Flag indicating the values of the parameter should not be displayed.
+	Sensitive bool `json:"sensitive,omitempty"`
 }
 
 type ExperimentMeta struct {
@@ -238,6 +253,32 @@ type ExperimentList struct {
 	Experiments []ExperimentItem `json:"experiments,omitempty"`
 }
 
+// ExperimentPatch is used to change the settings of an existing experiment
+type ExperimentPatch struct {
+	// Changes to how the optimizer will generate trials, optimization parameters not included are left unchanged.
+	Optimization []Optimization `json:"optimization,omitempty"`
+}
+
+// MergeOptimization returns a copy of the optimization configuration with the changes applied, changes replace values
+// with the same name and new values are appended
+func MergeOptimization(opt []Optimization, changes []Optimization) []Optimization {
+	merged := append([]Optimization(nil), opt...)
+	for _, c := range changes {
+		found := false
+		for i := range merged {
+			if merged[i].Name == c.Name {
+				merged[i].Value = c.Value
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, c)
+		}
+	}
+	return merged
+}
+
 type ExperimentLabels struct {
 	// New labels for this experiment.
 	Labels map[string]string `json:"labels"`
@@ -0,0 +1,254 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake provides an in-memory implementation of the experiments API for testing.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+)
+
+const baseURL = "http://fake.redskyops.dev/experiments/"
+
+var _ experimentsv1alpha1.API = &API{}
+
+// API is a fake experiments API; suggestions are served in order and reports are recorded for inspection
+type API struct {
+	mu sync.Mutex
+
+	// Experiments are the experiments created through this API, keyed by name
+	Experiments map[string]experimentsv1alpha1.Experiment
+	// Suggestions are the assignments returned from subsequent calls to NextTrial
+	Suggestions []experimentsv1alpha1.TrialAssignments
+	// Reports are the values reported for each trial, keyed by the trial URL
+	Reports map[string]experimentsv1alpha1.TrialValues
+	// Abandoned are the URLs of the trials which were abandoned
+	Abandoned []string
+	// TrialLabels are the labels applied to each trial, keyed by the trial labels URL
+	TrialLabels map[string]map[string]string
+
+	trials int
+}
+
+// NewAPI returns a new fake API which will serve the supplied suggestions
+func NewAPI(suggestions ...experimentsv1alpha1.TrialAssignments) *API {
+	return &API{
+		Experiments: make(map[string]experimentsv1alpha1.Experiment),
+		Suggestions: suggestions,
+		Reports:     make(map[string]experimentsv1alpha1.TrialValues),
+		TrialLabels: make(map[string]map[string]string),
+	}
+}
+
+// Report returns the values reported for the specified trial URL
+func (f *API) Report(u string) (experimentsv1alpha1.TrialValues, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	vls, ok := f.Reports[u]
+	return vls, ok
+}
+
+func (f *API) Options(context.Context) (experimentsv1alpha1.ServerMeta, error) {
+	return experimentsv1alpha1.ServerMeta{Server: "Fake"}, nil
+}
+
+func (f *API) GetAllExperiments(ctx context.Context, q *experimentsv1alpha1.ExperimentListQuery) (experimentsv1alpha1.ExperimentList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	lst := experimentsv1alpha1.ExperimentList{}
+	for _, exp := range f.Experiments {
+		lst.Experiments = append(lst.Experiments, experimentsv1alpha1.ExperimentItem{Experiment: exp})
+	}
+	return lst, nil
+}
+
+func (f *API) GetAllExperimentsByPage(ctx context.Context, u string) (experimentsv1alpha1.ExperimentList, error) {
+	return f.GetAllExperiments(ctx, nil)
+}
+
+func (f *API) GetExperimentByName(ctx context.Context, n experimentsv1alpha1.ExperimentName) (experimentsv1alpha1.Experiment, error) {
+	return f.GetExperiment(ctx, baseURL+n.Name())
+}
+
+func (f *API) GetExperiment(ctx context.Context, u string) (experimentsv1alpha1.Experiment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if exp, ok := f.Experiments[strings.TrimPrefix(u, baseURL)]; ok {
+		return exp, nil
+	}
+	return experimentsv1alpha1.Experiment{}, &experimentsv1alpha1.Error{Type: experimentsv1alpha1.ErrExperimentNotFound, Message: "experiment not found"}
+}
+
+func (f *API) CreateExperiment(ctx context.Context, n experimentsv1alpha1.ExperimentName, exp experimentsv1alpha1.Experiment) (experimentsv1alpha1.Experiment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	exp.SelfURL = baseURL + n.Name()
+	exp.TrialsURL = exp.SelfURL + "/trials/"
+	exp.NextTrialURL = exp.SelfURL + "/nextTrial"
+	exp.LabelsURL = exp.SelfURL + "/labels"
+	f.Experiments[n.Name()] = exp
+	return exp, nil
+}
+
+func (f *API) UpdateExperiment(ctx context.Context, u string, p experimentsv1alpha1.ExperimentPatch) (experimentsv1alpha1.Experiment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	exp, ok := f.Experiments[strings.TrimPrefix(u, baseURL)]
+	if !ok {
+		return experimentsv1alpha1.Experiment{}, &experimentsv1alpha1.Error{Type: experimentsv1alpha1.ErrExperimentNotFound, Message: "experiment not found"}
+	}
+	exp.Optimization = experimentsv1alpha1.MergeOptimization(exp.Optimization, p.Optimization)
+	f.Experiments[strings.TrimPrefix(u, baseURL)] = exp
+	return exp, nil
+}
+
+func (f *API) DeleteExperiment(ctx context.Context, u string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.Experiments, strings.TrimPrefix(u, baseURL))
+	return nil
+}
+
+func (f *API) GetAllTrials(ctx context.Context, u string, q *experimentsv1alpha1.TrialListQuery) (experimentsv1alpha1.TrialList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	lst := experimentsv1alpha1.TrialList{}
+	for ru, vls := range f.Reports {
+		if strings.HasPrefix(ru, u) {
+			item := experimentsv1alpha1.TrialItem{TrialValues: vls, Status: experimentsv1alpha1.TrialCompleted}
+			item.SelfURL = ru
+			if q.Matches(&item) {
+				lst.Trials = append(lst.Trials, item)
+			}
+		}
+	}
+	return lst, nil
+}
+
+func (f *API) GetAllTrialsByPage(ctx context.Context, u string) (experimentsv1alpha1.TrialList, error) {
+	return f.GetAllTrials(ctx, u, nil)
+}
+
+func (f *API) CreateTrial(ctx context.Context, u string, asm experimentsv1alpha1.TrialAssignments) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Suggestions = append(f.Suggestions, asm)
+	return "", nil
+}
+
+func (f *API) NextTrial(ctx context.Context, u string) (experimentsv1alpha1.TrialAssignments, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.Suggestions) == 0 {
+		return experimentsv1alpha1.TrialAssignments{}, &experimentsv1alpha1.Error{Type: experimentsv1alpha1.ErrTrialUnavailable, Message: "no suggestions available"}
+	}
+
+	asm := f.Suggestions[0]
+	f.Suggestions = f.Suggestions[1:]
+	f.trials++
+	asm.SelfURL = fmt.Sprintf("%s/trials/%d", strings.TrimSuffix(u, "/nextTrial"), f.trials)
+	asm.LabelsURL = asm.SelfURL + "/labels"
+	return asm, nil
+}
+
+func (f *API) ReportTrial(ctx context.Context, u string, vls experimentsv1alpha1.TrialValues) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r, ok := f.Reports[u]; ok {
+		if vls.IdempotencyKey != "" && r.IdempotencyKey == vls.IdempotencyKey {
+			return nil
+		}
+		return &experimentsv1alpha1.Error{Type: experimentsv1alpha1.ErrTrialAlreadyReported, Message: "trial already reported"}
+	}
+	f.Reports[u] = vls
+	return nil
+}
+
+func (f *API) ReportTrials(ctx context.Context, u string, rpts []experimentsv1alpha1.TrialReport) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range rpts {
+		f.trials++
+		tu := fmt.Sprintf("%s/trials/%d", strings.TrimSuffix(u, "/"), f.trials)
+		f.Reports[tu] = r.TrialValues
+		if len(r.Labels) > 0 {
+			f.TrialLabels[tu+"/labels"] = r.Labels
+		}
+	}
+	return nil
+}
+
+func (f *API) AbandonRunningTrial(ctx context.Context, u string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Abandoned = append(f.Abandoned, u)
+	return nil
+}
+
+func (f *API) LabelExperiment(context.Context, string, experimentsv1alpha1.ExperimentLabels) error {
+	return nil
+}
+
+func (f *API) LabelTrial(ctx context.Context, u string, lbl experimentsv1alpha1.TrialLabels) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	labels := f.TrialLabels[u]
+	if labels == nil {
+		labels = make(map[string]string, len(lbl.Labels))
+		f.TrialLabels[u] = labels
+	}
+	for k, v := range lbl.Labels {
+		if v == "" {
+			delete(labels, k)
+		} else {
+			labels[k] = v
+		}
+	}
+	return nil
+}
+
+func (f *API) AddTrialLabels(ctx context.Context, u string, labels map[string]string) error {
+	for k, v := range labels {
+		if v == "" {
+			return fmt.Errorf("label value for %q must not be empty", k)
+		}
+	}
+	return f.LabelTrial(ctx, u, experimentsv1alpha1.TrialLabels{Labels: labels})
+}
+
+func (f *API) RemoveTrialLabels(ctx context.Context, u string, keys ...string) error {
+	lbl := experimentsv1alpha1.TrialLabels{Labels: make(map[string]string, len(keys))}
+	for _, k := range keys {
+		lbl.Labels[k] = ""
+	}
+	return f.LabelTrial(ctx, u, lbl)
+}
+
+// Labels returns the labels applied to the specified trial labels URL
+func (f *API) Labels(u string) map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	labels := make(map[string]string, len(f.TrialLabels[u]))
+	for k, v := range f.TrialLabels[u] {
+		labels[k] = v
+	}
+	return labels
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"mime"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -32,11 +33,18 @@ import (
 
 // NewAPI returns a new API implementation for the specified client
 func NewAPI(c redskyapi.Client) API {
-	return &httpAPI{client: c}
+	return NewAPIWithRetryPolicy(c, DefaultRetryPolicy)
+}
+
+// NewAPIWithRetryPolicy returns a new API implementation for the specified client which retries failed requests
+// according to the supplied policy
+func NewAPIWithRetryPolicy(c redskyapi.Client, p RetryPolicy) API {
+	return &httpAPI{client: c, retry: p}
 }
 
 type httpAPI struct {
 	client redskyapi.Client
+	retry  RetryPolicy
 }
 
 func (h *httpAPI) Options(ctx context.Context) (ServerMeta, error) {
@@ -52,7 +60,7 @@ func (h *httpAPI) Options(ctx context.Context) (ServerMeta, error) {
 	// TODO This isn't working because of backend configuration issues
 	// req.URL.Opaque = "*"
 
-	resp, body, err := h.client.Do(ctx, req)
+	resp, body, err := h.do(ctx, req)
 	if err != nil {
 		return sm, err
 	}
@@ -83,7 +91,7 @@ func (h *httpAPI) GetAllExperimentsByPage(ctx context.Context, u string) (Experi
 		return lst, err
 	}
 
-	resp, body, err := h.client.Do(ctx, req)
+	resp, body, err := h.do(ctx, req)
 	if err != nil {
 		return lst, err
 	}
@@ -106,7 +114,7 @@ func (h *httpAPI) GetExperimentByName(ctx context.Context, n ExperimentName) (Ex
 	exp, err := h.GetExperiment(ctx, u)
 
 	// Improve the "not found" error message using the name
-	if eerr, ok := err.(*Error); ok && eerr.Type == ErrExperimentNotFound {
+	if eerr, ok := AsError(err); ok && eerr.Type == ErrExperimentNotFound {
 		eerr.Message = fmt.Sprintf(`experiment "%s" not found`, n.Name())
 	}
 
@@ -121,7 +129,7 @@ func (h *httpAPI) GetExperiment(ctx context.Context, u string) (Experiment, erro
 		return e, err
 	}
 
-	resp, body, err := h.client.Do(ctx, req)
+	resp, body, err := h.do(ctx, req)
 	if err != nil {
 		return e, err
 	}
@@ -147,7 +155,7 @@ func (h *httpAPI) CreateExperiment(ctx context.Context, n ExperimentName, exp Ex
 		return e, err
 	}
 
-	resp, body, err := h.client.Do(ctx, req)
+	resp, body, err := h.do(ctx, req)
 	if err != nil {
 		return e, err
 	}
@@ -168,13 +176,40 @@ func (h *httpAPI) CreateExperiment(ctx context.Context, n ExperimentName, exp Ex
 	}
 }
 
+func (h *httpAPI) UpdateExperiment(ctx context.Context, u string, p ExperimentPatch) (Experiment, error) {
+	e := Experiment{}
+
+	req, err := httpNewJSONRequest(http.MethodPatch, u, p)
+	if err != nil {
+		return e, err
+	}
+
+	resp, body, err := h.do(ctx, req)
+	if err != nil {
+		return e, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		metaUnmarshal(resp.Header, &e.ExperimentMeta)
+		err = json.Unmarshal(body, &e)
+		return e, err
+	case http.StatusNotFound:
+		return e, newError(ErrExperimentNotFound, resp, body)
+	case http.StatusUnprocessableEntity:
+		return e, newError(ErrExperimentInvalid, resp, body)
+	default:
+		return e, newError(ErrUnexpected, resp, body)
+	}
+}
+
 func (h *httpAPI) DeleteExperiment(ctx context.Context, u string) error {
 	req, err := http.NewRequest(http.MethodDelete, u, nil)
 	if err != nil {
 		return err
 	}
 
-	resp, body, err := h.client.Do(ctx, req)
+	resp, body, err := h.do(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -190,29 +225,39 @@ func (h *httpAPI) DeleteExperiment(ctx context.Context, u string) error {
 }
 
 func (h *httpAPI) GetAllTrials(ctx context.Context, u string, q *TrialListQuery) (TrialList, error) {
-	lst := TrialList{}
-
 	rawQuery := q.Encode()
 	if rawQuery != "" {
 		if uu, err := url.Parse(u); err == nil {
-			// TODO Should we be merging the query in this case?
-			uu.RawQuery = rawQuery
+			// Merge the filter into any query already present on the trials URL
+			qq := uu.Query()
+			fq, _ := url.ParseQuery(rawQuery)
+			for k, v := range fq {
+				qq[k] = v
+			}
+			uu.RawQuery = qq.Encode()
 			u = uu.String()
 		}
 	}
 
+	return h.GetAllTrialsByPage(ctx, u)
+}
+
+func (h *httpAPI) GetAllTrialsByPage(ctx context.Context, u string) (TrialList, error) {
+	lst := TrialList{}
+
 	req, err := http.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
 		return lst, err
 	}
 
-	resp, body, err := h.client.Do(ctx, req)
+	resp, body, err := h.do(ctx, req)
 	if err != nil {
 		return lst, err
 	}
 
 	switch resp.StatusCode {
 	case http.StatusOK:
+		metaUnmarshal(resp.Header, &lst.TrialListMeta)
 		err = json.Unmarshal(body, &lst)
 		for i := range lst.Trials {
 			metaUnmarshal(http.Header(lst.Trials[i].Metadata), &lst.Trials[i].TrialAssignments.TrialMeta)
@@ -231,7 +276,7 @@ func (h *httpAPI) CreateTrial(ctx context.Context, u string, asm TrialAssignment
 		return ta.SelfURL, err
 	}
 
-	resp, body, err := h.client.Do(ctx, req)
+	resp, body, err := h.do(ctx, req)
 	if err != nil {
 		return ta.SelfURL, err
 	}
@@ -257,6 +302,7 @@ func (h *httpAPI) NextTrial(ctx context.Context, u string) (TrialAssignments, er
 		return asm, err
 	}
 
+	// Do not retry, an unavailable trial is returned with the server requested delay so the caller can requeue
 	resp, body, err := h.client.Do(ctx, req)
 	if err != nil {
 		return asm, err
@@ -285,8 +331,11 @@ func (h *httpAPI) ReportTrial(ctx context.Context, u string, vls TrialValues) er
 	if err != nil {
 		return err
 	}
+	if vls.IdempotencyKey != "" {
+		req.Header.Set(HeaderIdempotencyKey, vls.IdempotencyKey)
+	}
 
-	resp, body, err := h.client.Do(ctx, req)
+	resp, body, err := h.do(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -297,6 +346,10 @@ func (h *httpAPI) ReportTrial(ctx context.Context, u string, vls TrialValues) er
 	case http.StatusNotFound:
 		return newError(ErrTrialNotFound, resp, body)
 	case http.StatusConflict:
+		// With an idempotency key, a conflict means an earlier attempt was already accepted
+		if vls.IdempotencyKey != "" {
+			return nil
+		}
 		return newError(ErrTrialAlreadyReported, resp, body)
 	case http.StatusUnprocessableEntity:
 		return newError(ErrTrialInvalid, resp, body)
@@ -305,13 +358,51 @@ func (h *httpAPI) ReportTrial(ctx context.Context, u string, vls TrialValues) er
 	}
 }
 
+// ReportTrials records multiple completed trials for the experiment at the supplied URL in a single request
+func (h *httpAPI) ReportTrials(ctx context.Context, u string, rpts []TrialReport) error {
+	if len(rpts) == 0 {
+		return nil
+	}
+
+	batch := TrialReports{Trials: make([]TrialReport, len(rpts))}
+	for i := range rpts {
+		batch.Trials[i] = rpts[i]
+		if batch.Trials[i].Failed {
+			batch.Trials[i].Values = nil
+		}
+	}
+
+	req, err := httpNewJSONRequest(http.MethodPost, strings.TrimSuffix(u, "/")+"/trialReports", batch)
+	if err != nil {
+		return err
+	}
+
+	resp, body, err := h.do(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return nil
+	case http.StatusNotFound:
+		return newError(ErrExperimentNotFound, resp, body)
+	case http.StatusConflict:
+		return newError(ErrExperimentStopped, resp, body)
+	case http.StatusUnprocessableEntity:
+		return newError(ErrTrialInvalid, resp, body)
+	default:
+		return newError(ErrUnexpected, resp, body)
+	}
+}
+
 func (h *httpAPI) AbandonRunningTrial(ctx context.Context, u string) error {
 	req, err := http.NewRequest(http.MethodDelete, u, nil)
 	if err != nil {
 		return err
 	}
 
-	resp, body, err := h.client.Do(ctx, req)
+	resp, body, err := h.do(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -332,7 +423,7 @@ func (h *httpAPI) LabelExperiment(ctx context.Context, u string, lbl ExperimentL
 		return err
 	}
 
-	resp, body, err := h.client.Do(ctx, req)
+	resp, body, err := h.do(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -355,7 +446,7 @@ func (h *httpAPI) LabelTrial(ctx context.Context, u string, lbl TrialLabels) err
 		return err
 	}
 
-	resp, body, err := h.client.Do(ctx, req)
+	resp, body, err := h.do(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -372,6 +463,28 @@ func (h *httpAPI) LabelTrial(ctx context.Context, u string, lbl TrialLabels) err
 	}
 }
 
+// AddTrialLabels adds (or overwrites) the supplied labels on the trial at the supplied labels URL
+func (h *httpAPI) AddTrialLabels(ctx context.Context, u string, labels map[string]string) error {
+	lbl := TrialLabels{Labels: make(map[string]string, len(labels))}
+	for k, v := range labels {
+		if v == "" {
+			return fmt.Errorf("label value for %q must not be empty", k)
+		}
+		lbl.Labels[k] = v
+	}
+	return h.LabelTrial(ctx, u, lbl)
+}
+
+// RemoveTrialLabels removes the labels with the supplied keys from the trial at the supplied labels URL
+func (h *httpAPI) RemoveTrialLabels(ctx context.Context, u string, keys ...string) error {
+	// An empty label value removes the label
+	lbl := TrialLabels{Labels: make(map[string]string, len(keys))}
+	for _, k := range keys {
+		lbl.Labels[k] = ""
+	}
+	return h.LabelTrial(ctx, u, lbl)
+}
+
 // httpNewJSONRequest returns a new HTTP request with a JSON payload
 func httpNewJSONRequest(method, u string, body interface{}) (*http.Request, error) {
 	b, err := json.Marshal(body)
@@ -390,11 +503,10 @@ func httpNewJSONRequest(method, u string, body interface{}) (*http.Request, erro
 
 // newError returns a new error with an API specific error condition, it also captures the details of the response
 func newError(t ErrorType, resp *http.Response, body []byte) error {
-	err := &Error{Type: t}
+	err := &Error{Type: t, StatusCode: resp.StatusCode}
 
-	// Unmarshal the response body into the error to get the server supplied error message
-	// TODO We should be comparing compatible media types here (e.g. charset)
-	if resp.Header.Get("Content-Type") == "application/json" {
+	// Unmarshal the response body into the error to get the server supplied error message and field violations
+	if mt, _, merr := mime.ParseMediaType(resp.Header.Get("Content-Type")); merr == nil && (mt == "application/json" || strings.HasSuffix(mt, "+json")) {
 		_ = json.Unmarshal(body, err)
 	}
 
@@ -403,8 +515,8 @@ func newError(t ErrorType, resp *http.Response, body []byte) error {
 		err.Location = resp.Request.URL.String()
 	}
 
-	// Capture the Retry-After header for "service unavailable"
-	if resp.StatusCode == http.StatusServiceUnavailable {
+	// Capture the Retry-After header for "service unavailable" or "too many requests"
+	if resp.StatusCode == http.StatusServiceUnavailable || resp.StatusCode == http.StatusTooManyRequests {
 		if ra, raerr := strconv.Atoi(resp.Header.Get("Retry-After")); raerr == nil {
 			if ra < 1 {
 				ra = 5
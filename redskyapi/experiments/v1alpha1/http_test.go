@@ -0,0 +1,89 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClient is an API client for a test server
+type testClient struct {
+	srv *httptest.Server
+}
+
+func (c *testClient) URL(ep string) *url.URL {
+	u, _ := url.Parse(c.srv.URL + ep)
+	return u
+}
+
+func (c *testClient) Do(ctx context.Context, req *http.Request) (*http.Response, []byte, error) {
+	resp, err := c.srv.Client().Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	return resp, body, err
+}
+
+func TestHTTPAPI_TrialLabels(t *testing.T) {
+	var received []TrialLabels
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/experiments/test/trials/1/labels", r.URL.Path)
+		lbl := TrialLabels{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&lbl))
+		received = append(received, lbl)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	ctx := context.TODO()
+	api := NewAPI(&testClient{srv: srv})
+	u := srv.URL + "/experiments/test/trials/1/labels"
+
+	require.NoError(t, api.AddTrialLabels(ctx, u, map[string]string{"best": "true"}))
+	require.NoError(t, api.RemoveTrialLabels(ctx, u, "investigate", "baseline"))
+	assert.Equal(t, []TrialLabels{
+		{Labels: map[string]string{"best": "true"}},
+		{Labels: map[string]string{"investigate": "", "baseline": ""}},
+	}, received)
+
+	// Empty values would remove the label instead of adding it
+	assert.Error(t, api.AddTrialLabels(ctx, u, map[string]string{"best": ""}))
+	assert.Len(t, received, 2)
+}
+
+func TestHTTPAPI_TrialLabelsNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	api := NewAPI(&testClient{srv: srv})
+	err := api.RemoveTrialLabels(context.TODO(), srv.URL+"/experiments/test/trials/1/labels", "best")
+	assert.True(t, IsErrorType(err, ErrTrialNotFound))
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+)
+
+// TrialIterator iterates over the trials of an experiment one page at a time, following the "next" links returned
+// by the server so the entire list does not need to be held in memory
+type TrialIterator struct {
+	api  API
+	ctx  context.Context
+	u    string
+	q    *TrialListQuery
+	next string
+	page []TrialItem
+	pos  int
+	err  error
+	more bool
+}
+
+// NewTrialIterator returns an iterator over the trials at the supplied URL matching the optional query
+func NewTrialIterator(ctx context.Context, api API, u string, q *TrialListQuery) *TrialIterator {
+	return &TrialIterator{api: api, ctx: ctx, u: u, q: q, pos: -1, more: true}
+}
+
+// Next advances the iterator to the next trial, returning false when there are no more trials or an error occurs
+func (it *TrialIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.pos+1 >= len(it.page) {
+		if !it.more {
+			return false
+		}
+
+		var lst TrialList
+		if it.next == "" {
+			lst, it.err = it.api.GetAllTrials(it.ctx, it.u, it.q)
+		} else {
+			lst, it.err = it.api.GetAllTrialsByPage(it.ctx, it.next)
+		}
+		if it.err != nil {
+			return false
+		}
+
+		it.page, it.pos, it.next = lst.Trials, -1, lst.Next
+		it.more = it.next != ""
+	}
+
+	it.pos++
+	return true
+}
+
+// Trial returns the current trial, it is only valid until the next call to Next
+func (it *TrialIterator) Trial() *TrialItem {
+	if it.pos < 0 || it.pos >= len(it.page) {
+		return nil
+	}
+	return &it.page[it.pos]
+}
+
+// Err returns the error, if any, encountered during iteration
+func (it *TrialIterator) Err() error {
+	return it.err
+}
+
+// ForEachTrial invokes the supplied function for each trial at the supplied URL matching the optional query, iteration
+// stops on the first error
+func ForEachTrial(ctx context.Context, api API, u string, q *TrialListQuery, f func(*TrialItem) error) error {
+	it := NewTrialIterator(ctx, api, u, q)
+	for it.Next() {
+		if err := f(it.Trial()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newPagedTrialServer returns a test server listing the supplied trial numbers using pages of the specified size
+// linked with "next" relations, the paths of the requested pages are recorded
+func newPagedTrialServer(numbers []int64, size int, requested *[]string) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requested = append(*requested, r.URL.RequestURI())
+
+		var page int
+		_, _ = fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+		start, end := page*size, (page+1)*size
+		if end < len(numbers) {
+			w.Header().Add("Link", fmt.Sprintf(`<%s/experiments/test/trials/?page=%d>; rel="next"`, srv.URL, page+1))
+		} else {
+			end = len(numbers)
+		}
+
+		lst := TrialList{}
+		for _, n := range numbers[start:end] {
+			lst.Trials = append(lst.Trials, TrialItem{Number: n})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&lst)
+	}))
+	return srv
+}
+
+func TestForEachTrial(t *testing.T) {
+	var requested []string
+	srv := newPagedTrialServer([]int64{1, 2, 3, 4, 5}, 2, &requested)
+	defer srv.Close()
+
+	api := NewAPI(&testClient{srv: srv})
+	q := &TrialListQuery{Status: []TrialStatus{TrialCompleted}}
+
+	var numbers []int64
+	err := ForEachTrial(context.TODO(), api, srv.URL+"/experiments/test/trials/", q, func(t *TrialItem) error {
+		numbers = append(numbers, t.Number)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, numbers)
+	assert.Equal(t, []string{
+		"/experiments/test/trials/?status=completed",
+		"/experiments/test/trials/?page=1",
+		"/experiments/test/trials/?page=2",
+	}, requested)
+}
+
+func TestForEachTrial_StopOnError(t *testing.T) {
+	var requested []string
+	srv := newPagedTrialServer([]int64{1, 2, 3, 4, 5}, 2, &requested)
+	defer srv.Close()
+
+	api := NewAPI(&testClient{srv: srv})
+	stop := errors.New("stop")
+
+	var numbers []int64
+	err := ForEachTrial(context.TODO(), api, srv.URL+"/experiments/test/trials/", nil, func(t *TrialItem) error {
+		numbers = append(numbers, t.Number)
+		if t.Number == 3 {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []int64{1, 2, 3}, numbers)
+	assert.Len(t, requested, 2, "the last page must not be requested")
+}
+
+func TestTrialIterator_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	it := NewTrialIterator(context.TODO(), NewAPI(&testClient{srv: srv}), srv.URL+"/experiments/test/trials/", nil)
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Trial())
+	assert.True(t, IsErrorType(it.Err(), ErrUnexpected))
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how requests to the API server are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a single request, values less than 2 disable retries
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, the delay doubles for each subsequent retry
+	BaseDelay time.Duration
+	// MaxDelay is the longest delay between attempts, requests are not retried if the server asks for a longer delay
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy used when one is not explicitly specified
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// delay returns a jittered delay to use before the specified retry (starting at 1)
+func (p *RetryPolicy) delay(retry int) time.Duration {
+	d := p.MaxDelay
+	if retry < 32 {
+		if bd := p.BaseDelay << uint(retry-1); bd > 0 && bd < d {
+			d = bd
+		}
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	// Wait at least half of the computed delay
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// do performs the request, retrying it according to the retry policy
+func (h *httpAPI) do(ctx context.Context, req *http.Request) (*http.Response, []byte, error) {
+	var done <-chan struct{}
+	if ctx != nil {
+		done = ctx.Done()
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, body, err := h.client.Do(ctx, req)
+		if attempt >= h.retry.MaxAttempts || !retryable(req, resp, err) {
+			return resp, body, err
+		}
+
+		// Honor the server requested delay as long as it is reasonable
+		d := h.retry.delay(attempt)
+		if resp != nil {
+			if ra, ok := retryAfter(resp.Header); ok {
+				if ra > h.retry.MaxDelay {
+					return resp, body, err
+				}
+				d = ra
+			}
+		}
+
+		// Rewind the request body for the next attempt
+		if req.GetBody != nil {
+			b, berr := req.GetBody()
+			if berr != nil {
+				return resp, body, err
+			}
+			req.Body = b
+		}
+
+		t := time.NewTimer(d)
+		select {
+		case <-done:
+			t.Stop()
+			return resp, body, err
+		case <-t.C:
+		}
+	}
+}
+
+// retryable checks to see if a request should be attempted again given the outcome of the previous attempt
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	// We cannot retry if we cannot send the same body again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+
+		// The request may have been processed, only retry if it is safe to do so
+		return idempotent(req) && transient(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		// The server did not process the request
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		// A proxy may have forwarded the request before failing
+		return idempotent(req)
+	}
+	return false
+}
+
+// idempotent checks to see if a request can be safely repeated
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(HeaderIdempotencyKey) != ""
+}
+
+// transient checks to see if an error is a temporary networking failure
+func transient(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var nerr net.Error
+	if errors.As(err, &nerr) {
+		return nerr.Timeout() || nerr.Temporary()
+	}
+	return false
+}
+
+// retryAfter returns the delay requested by the server using the "Retry-After" header
+func retryAfter(header http.Header) (time.Duration, bool) {
+	ra := header.Get("Retry-After")
+	if ra == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(ra); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(ra); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryable(t *testing.T) {
+	get := func() *http.Request { r, _ := http.NewRequest(http.MethodGet, "http://example.com", nil); return r }
+	post := func() *http.Request {
+		r, _ := http.NewRequest(http.MethodPost, "http://example.com", strings.NewReader("{}"))
+		return r
+	}
+	idempotentPost := func() *http.Request { r := post(); r.Header.Set(HeaderIdempotencyKey, "1"); return r }
+	unbufferedPost := func() *http.Request { r := post(); r.GetBody = nil; return r }
+	status := func(code int) *http.Response { return &http.Response{StatusCode: code} }
+
+	cases := []struct {
+		desc     string
+		req      *http.Request
+		resp     *http.Response
+		err      error
+		expected bool
+	}{
+		{desc: "ok", req: get(), resp: status(http.StatusOK)},
+		{desc: "not found", req: get(), resp: status(http.StatusNotFound)},
+		{desc: "internal error", req: get(), resp: status(http.StatusInternalServerError)},
+		{desc: "too many requests", req: post(), resp: status(http.StatusTooManyRequests), expected: true},
+		{desc: "unavailable", req: post(), resp: status(http.StatusServiceUnavailable), expected: true},
+		{desc: "bad gateway get", req: get(), resp: status(http.StatusBadGateway), expected: true},
+		{desc: "bad gateway post", req: post(), resp: status(http.StatusBadGateway)},
+		{desc: "gateway timeout idempotent post", req: idempotentPost(), resp: status(http.StatusGatewayTimeout), expected: true},
+		{desc: "unbuffered body", req: unbufferedPost(), resp: status(http.StatusServiceUnavailable)},
+		{desc: "connection reset get", req: get(), err: syscall.ECONNRESET, expected: true},
+		{desc: "connection reset post", req: post(), err: syscall.ECONNRESET},
+		{desc: "unexpected eof idempotent post", req: idempotentPost(), err: io.ErrUnexpectedEOF, expected: true},
+		{desc: "canceled", req: get(), err: context.Canceled},
+		{desc: "deadline exceeded", req: get(), err: context.DeadlineExceeded},
+		{desc: "other error", req: get(), err: errors.New("test")},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.expected, retryable(c.req, c.resp, c.err))
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := []struct {
+		desc     string
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{desc: "missing"},
+		{desc: "seconds", value: "3", expected: 3 * time.Second, ok: true},
+		{desc: "zero", value: "0", ok: true},
+		{desc: "negative", value: "-1"},
+		{desc: "past date", value: "Mon, 01 Jan 2001 00:00:00 GMT", ok: true},
+		{desc: "invalid", value: "soon"},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			h := http.Header{}
+			if c.value != "" {
+				h.Set("Retry-After", c.value)
+			}
+			actual, ok := retryAfter(h)
+			assert.Equal(t, c.expected, actual)
+			assert.Equal(t, c.ok, ok)
+		})
+	}
+
+	// Future dates are relative to the current time
+	h := http.Header{}
+	h.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	actual, ok := retryAfter(h)
+	assert.True(t, ok)
+	assert.InDelta(t, float64(time.Hour), float64(actual), float64(2*time.Second))
+}
+
+func TestHTTPAPI_Do(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second}
+
+	cases := []struct {
+		desc       string
+		method     string
+		statuses   []int
+		retryAfter string
+		expected   int
+		attempts   int
+	}{
+		{
+			desc:     "success",
+			method:   http.MethodGet,
+			statuses: []int{http.StatusOK},
+			expected: http.StatusOK,
+			attempts: 1,
+		},
+		{
+			desc:     "recovers",
+			method:   http.MethodGet,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			expected: http.StatusOK,
+			attempts: 3,
+		},
+		{
+			desc:     "max attempts",
+			method:   http.MethodGet,
+			statuses: []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			expected: http.StatusServiceUnavailable,
+			attempts: 3,
+		},
+		{
+			desc:     "not idempotent",
+			method:   http.MethodPost,
+			statuses: []int{http.StatusBadGateway, http.StatusOK},
+			expected: http.StatusBadGateway,
+			attempts: 1,
+		},
+		{
+			desc:       "retry after too long",
+			method:     http.MethodGet,
+			statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter: "60",
+			expected:   http.StatusTooManyRequests,
+			attempts:   1,
+		},
+		{
+			desc:       "retry after",
+			method:     http.MethodPost,
+			statuses:   []int{http.StatusTooManyRequests, http.StatusOK},
+			retryAfter: "0",
+			expected:   http.StatusOK,
+			attempts:   2,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			var attempts int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, "{}", string(b), "request body must be resent")
+				if c.retryAfter != "" {
+					w.Header().Set("Retry-After", c.retryAfter)
+				}
+				w.WriteHeader(c.statuses[attempts])
+				attempts++
+			}))
+			defer srv.Close()
+
+			h := &httpAPI{client: &testClient{srv: srv}, retry: policy}
+			req, err := http.NewRequest(c.method, srv.URL, strings.NewReader("{}"))
+			require.NoError(t, err)
+			resp, _, err := h.do(context.TODO(), req)
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, resp.StatusCode)
+			}
+			assert.Equal(t, c.attempts, attempts)
+		})
+	}
+}
+
+func TestHTTPAPI_NextTrialUnavailable(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	api := NewAPI(&testClient{srv: srv})
+	_, err := api.NextTrial(context.TODO(), srv.URL+"/experiments/test/nextTrial")
+	assert.Equal(t, 1, attempts, "unavailable trials must not be retried")
+	if rserr, ok := AsError(err); assert.True(t, ok) {
+		assert.Equal(t, ErrTrialUnavailable, rserr.Type)
+		assert.Equal(t, 7*time.Second, rserr.RetryAfter)
+	}
+}
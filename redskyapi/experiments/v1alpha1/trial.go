@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 )
@@ -50,6 +51,44 @@ type Assignment struct {
 	Value json.Number `json:"value"`
 }
 
+// MarshalJSON encodes the assignment, writing non-numeric (categorical) values as JSON strings
+func (a Assignment) MarshalJSON() ([]byte, error) {
+	type assignment Assignment
+	if _, err := a.Value.Float64(); err == nil {
+		return json.Marshal(assignment(a))
+	}
+	return json.Marshal(struct {
+		ParameterName string `json:"parameterName"`
+		Value         string `json:"value"`
+	}{ParameterName: a.ParameterName, Value: a.Value.String()})
+}
+
+// UnmarshalJSON decodes the assignment, accepting either a JSON number or a JSON string value
+func (a *Assignment) UnmarshalJSON(b []byte) error {
+	aa := &struct {
+		ParameterName string          `json:"parameterName"`
+		Value         json.RawMessage `json:"value"`
+	}{}
+	if err := json.Unmarshal(b, aa); err != nil {
+		return err
+	}
+
+	a.ParameterName = aa.ParameterName
+	a.Value = ""
+	if len(aa.Value) == 0 || string(aa.Value) == "null" {
+		return nil
+	}
+	if aa.Value[0] == '"' {
+		var s string
+		if err := json.Unmarshal(aa.Value, &s); err != nil {
+			return err
+		}
+		a.Value = json.Number(s)
+		return nil
+	}
+	return json.Unmarshal(aa.Value, &a.Value)
+}
+
 type TrialAssignments struct {
 	TrialMeta
 
@@ -71,6 +110,29 @@ type TrialValues struct {
 	Values []Value `json:"values,omitempty"`
 	// Indicator that the trial failed, Values is ignored when true.
 	Failed bool `json:"failed,omitempty"`
+	// A machine readable reason the trial failed, e.g. "Aborted".
+	FailureReason string `json:"failureReason,omitempty"`
+	// A human readable description of why the trial failed.
+	FailureMessage string `json:"failureMessage,omitempty"`
+
+	// IdempotencyKey is sent as a header to allow a report to be safely retried, it should uniquely identify the
+	// source of the trial values (e.g. the UID of the cluster trial).
+	IdempotencyKey string `json:"-"`
+}
+
+// TrialReport is a completed trial which was run outside of the optimizer (e.g. historical benchmark data).
+type TrialReport struct {
+	// The list of parameter names and their assigned values.
+	Assignments []Assignment `json:"assignments"`
+	TrialValues
+	// Labels to apply to the trial.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// TrialReports is a batch of completed trials.
+type TrialReports struct {
+	// The completed trials.
+	Trials []TrialReport `json:"trials"`
 }
 
 type TrialStatus string
@@ -102,6 +164,7 @@ type TrialItem struct {
 	Experiment *Experiment `json:"-"`
 }
 
+// TrialListQuery is used to filter the trials returned from the server
 type TrialListQuery struct {
 	// Comma separated list of statuses to fetch.
 	Status []TrialStatus
@@ -109,6 +172,7 @@ type TrialListQuery struct {
 	LabelSelector map[string]string
 }
 
+// Encode returns the query parameters for the filter
 func (p *TrialListQuery) Encode() string {
 	if p == nil {
 		return ""
@@ -126,12 +190,57 @@ func (p *TrialListQuery) Encode() string {
 		for k, v := range p.LabelSelector {
 			ls = append(ls, fmt.Sprintf("%s=%s", k, v))
 		}
+		sort.Strings(ls)
 		q.Add("labelSelector", strings.Join(ls, ","))
 	}
 	return q.Encode()
 }
 
+// Matches checks to see if a trial satisfies the filter, this can be used to filter trials returned by servers which
+// do not support the query parameters
+func (p *TrialListQuery) Matches(t *TrialItem) bool {
+	if p == nil {
+		return true
+	}
+	if len(p.Status) > 0 {
+		found := false
+		for i := range p.Status {
+			if t.Status == p.Status[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for k, v := range p.LabelSelector {
+		if lv, ok := t.Labels[k]; !ok || lv != v {
+			return false
+		}
+	}
+	return true
+}
+
+type TrialListMeta struct {
+	Next string `json:"-"`
+	Prev string `json:"-"`
+}
+
+func (m *TrialListMeta) SetLocation(string)        {}
+func (m *TrialListMeta) SetLastModified(time.Time) {}
+func (m *TrialListMeta) SetLink(rel, link string) {
+	switch strings.ToLower(rel) {
+	case relationNext:
+		m.Next = link
+	case relationPrev, relationPrevious:
+		m.Prev = link
+	}
+}
+
 type TrialList struct {
+	TrialListMeta
+
 	// The list of trials.
 	Trials []TrialItem `json:"trials"`
 
@@ -0,0 +1,1300 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by cmd/openapi, DO NOT EDIT.
+
+package v1alpha1
+
+// OpenAPIDocument is the OpenAPI description of the Red Sky API
+const OpenAPIDocument = `{
+  "components": {
+    "schemas": {
+      "Assignment": {
+        "properties": {
+          "parameterName": {
+            "description": "The name of the parameter in the experiment the assignment corresponds to.",
+            "type": "string"
+          },
+          "value": {
+            "description": "The assigned value of the parameter.",
+            "oneOf": [
+              {
+                "type": "number"
+              },
+              {
+                "type": "string"
+              }
+            ]
+          }
+        },
+        "required": [
+          "parameterName",
+          "value"
+        ],
+        "type": "object"
+      },
+      "Bounds": {
+        "properties": {
+          "max": {
+            "description": "The maximum value for a numeric parameter.",
+            "type": "number"
+          },
+          "min": {
+            "description": "The minimum value for a numeric parameter.",
+            "type": "number"
+          }
+        },
+        "required": [
+          "max",
+          "min"
+        ],
+        "type": "object"
+      },
+      "Constraint": {
+        "properties": {
+          "bound": {
+            "description": "Bound for inequality constraint.",
+            "format": "double",
+            "type": "number"
+          },
+          "constraintType": {
+            "$ref": "#/components/schemas/ConstraintType"
+          },
+          "isUpperBound": {
+            "description": "Flag indicating if bound is upper or lower bound.",
+            "type": "boolean"
+          },
+          "lowerParameter": {
+            "description": "Name of lower parameter.",
+            "type": "string"
+          },
+          "name": {
+            "description": "Optional name for constraint.",
+            "type": "string"
+          },
+          "parameters": {
+            "description": "Parameters and weights for constraint.",
+            "items": {
+              "$ref": "#/components/schemas/SumConstraintParameter"
+            },
+            "type": "array"
+          },
+          "upperParameter": {
+            "description": "Name of upper parameter.",
+            "type": "string"
+          }
+        },
+        "required": [
+          "bound",
+          "constraintType",
+          "lowerParameter",
+          "parameters",
+          "upperParameter"
+        ],
+        "type": "object"
+      },
+      "ConstraintType": {
+        "enum": [
+          "sum",
+          "order"
+        ],
+        "type": "string"
+      },
+      "Error": {
+        "description": "Error represents the API specific error messages and may be used in response to HTTP status codes",
+        "properties": {
+          "error": {
+            "type": "string"
+          },
+          "fieldViolations": {
+            "items": {
+              "$ref": "#/components/schemas/FieldViolation"
+            },
+            "type": "array"
+          }
+        },
+        "required": [
+          "error"
+        ],
+        "type": "object"
+      },
+      "ErrorType": {
+        "description": "ErrorType identifies the API specific error condition, error types are themselves errors so they can be used as the target of errors.Is",
+        "enum": [
+          "experiment-name-invalid",
+          "experiment-name-conflict",
+          "experiment-invalid",
+          "experiment-not-found",
+          "experiment-stopped",
+          "trial-invalid",
+          "trial-unavailable",
+          "trial-not-found",
+          "trial-already-reported",
+          "unauthorized",
+          "unexpected"
+        ],
+        "type": "string"
+      },
+      "Experiment": {
+        "description": "Experiment combines the search space, outcomes and optimization configuration",
+        "properties": {
+          "constraints": {
+            "description": "Constraints for the experiment.",
+            "items": {
+              "$ref": "#/components/schemas/Constraint"
+            },
+            "type": "array"
+          },
+          "displayName": {
+            "description": "The display name of the experiment. Do not use for generating URLs!",
+            "type": "string"
+          },
+          "labels": {
+            "additionalProperties": {
+              "type": "string"
+            },
+            "description": "Labels for this experiment.",
+            "type": "object"
+          },
+          "metrics": {
+            "description": "The metrics been optimized in the experiment.",
+            "items": {
+              "$ref": "#/components/schemas/Metric"
+            },
+            "type": "array"
+          },
+          "observations": {
+            "description": "The number of observations made for this experiment.",
+            "format": "int64",
+            "type": "integer"
+          },
+          "optimization": {
+            "description": "Controls how the optimizer will generate trials.",
+            "items": {
+              "$ref": "#/components/schemas/Optimization"
+            },
+            "type": "array"
+          },
+          "parameters": {
+            "description": "The search space of the experiment.",
+            "items": {
+              "$ref": "#/components/schemas/Parameter"
+            },
+            "type": "array"
+          }
+        },
+        "required": [
+          "metrics",
+          "parameters"
+        ],
+        "type": "object"
+      },
+      "ExperimentItem": {
+        "properties": {
+          "_metadata": {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/Metadata"
+              }
+            ],
+            "description": "The metadata for an individual experiment."
+          },
+          "constraints": {
+            "description": "Constraints for the experiment.",
+            "items": {
+              "$ref": "#/components/schemas/Constraint"
+            },
+            "type": "array"
+          },
+          "displayName": {
+            "description": "The display name of the experiment. Do not use for generating URLs!",
+            "type": "string"
+          },
+          "labels": {
+            "additionalProperties": {
+              "type": "string"
+            },
+            "description": "Labels for this experiment.",
+            "type": "object"
+          },
+          "metrics": {
+            "description": "The metrics been optimized in the experiment.",
+            "items": {
+              "$ref": "#/components/schemas/Metric"
+            },
+            "type": "array"
+          },
+          "observations": {
+            "description": "The number of observations made for this experiment.",
+            "format": "int64",
+            "type": "integer"
+          },
+          "optimization": {
+            "description": "Controls how the optimizer will generate trials.",
+            "items": {
+              "$ref": "#/components/schemas/Optimization"
+            },
+            "type": "array"
+          },
+          "parameters": {
+            "description": "The search space of the experiment.",
+            "items": {
+              "$ref": "#/components/schemas/Parameter"
+            },
+            "type": "array"
+          }
+        },
+        "required": [
+          "metrics",
+          "parameters"
+        ],
+        "type": "object"
+      },
+      "ExperimentLabels": {
+        "properties": {
+          "labels": {
+            "additionalProperties": {
+              "type": "string"
+            },
+            "description": "New labels for this experiment.",
+            "type": "object"
+          }
+        },
+        "required": [
+          "labels"
+        ],
+        "type": "object"
+      },
+      "ExperimentList": {
+        "properties": {
+          "experiments": {
+            "description": "The list of experiments.",
+            "items": {
+              "$ref": "#/components/schemas/ExperimentItem"
+            },
+            "type": "array"
+          }
+        },
+        "type": "object"
+      },
+      "ExperimentPatch": {
+        "description": "ExperimentPatch is used to change the settings of an existing experiment",
+        "properties": {
+          "optimization": {
+            "description": "Changes to how the optimizer will generate trials, optimization parameters not included are left unchanged.",
+            "items": {
+              "$ref": "#/components/schemas/Optimization"
+            },
+            "type": "array"
+          }
+        },
+        "type": "object"
+      },
+      "FieldViolation": {
+        "description": "FieldViolation describes a single invalid field of a rejected request",
+        "properties": {
+          "description": {
+            "description": "A description of why the field is invalid",
+            "type": "string"
+          },
+          "field": {
+            "description": "The path to the invalid field, e.g. \"parameters[0].bounds\"",
+            "type": "string"
+          }
+        },
+        "required": [
+          "description",
+          "field"
+        ],
+        "type": "object"
+      },
+      "Metadata": {
+        "additionalProperties": {
+          "items": {
+            "type": "string"
+          },
+          "type": "array"
+        },
+        "description": "Metadata is used to hold single or multi-value metadata from list responses",
+        "type": "object"
+      },
+      "Metric": {
+        "properties": {
+          "minimize": {
+            "description": "The flag indicating this metric should be minimized.",
+            "type": "boolean"
+          },
+          "name": {
+            "description": "The name of the metric.",
+            "type": "string"
+          }
+        },
+        "required": [
+          "name"
+        ],
+        "type": "object"
+      },
+      "Optimization": {
+        "properties": {
+          "name": {
+            "description": "The name of the optimization parameter.",
+            "type": "string"
+          },
+          "value": {
+            "description": "The value of the optimization parameter.",
+            "type": "string"
+          }
+        },
+        "required": [
+          "name",
+          "value"
+        ],
+        "type": "object"
+      },
+      "OrderConstraint": {
+        "properties": {
+          "lowerParameter": {
+            "description": "Name of lower parameter.",
+            "type": "string"
+          },
+          "upperParameter": {
+            "description": "Name of upper parameter.",
+            "type": "string"
+          }
+        },
+        "required": [
+          "lowerParameter",
+          "upperParameter"
+        ],
+        "type": "object"
+      },
+      "Parameter": {
+        "description": "Parameter is a variable that is going to be tuned in an experiment",
+        "properties": {
+          "bounds": {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/Bounds"
+              }
+            ],
+            "description": "The domain of the parameter."
+          },
+          "name": {
+            "description": "The name of the parameter.",
+            "type": "string"
+          },
+          "sensitive": {
+            "description": "Flag indicating the values of the parameter should not be displayed.",
+            "type": "boolean"
+          },
+          "type": {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/ParameterType"
+              }
+            ],
+            "description": "The type of the parameter."
+          },
+          "values": {
+            "description": "The discrete values of a categorical parameter.",
+            "items": {
+              "type": "string"
+            },
+            "type": "array"
+          }
+        },
+        "required": [
+          "bounds",
+          "name",
+          "type"
+        ],
+        "type": "object"
+      },
+      "ParameterType": {
+        "enum": [
+          "int",
+          "double",
+          "categorical"
+        ],
+        "type": "string"
+      },
+      "SumConstraint": {
+        "properties": {
+          "bound": {
+            "description": "Bound for inequality constraint.",
+            "format": "double",
+            "type": "number"
+          },
+          "isUpperBound": {
+            "description": "Flag indicating if bound is upper or lower bound.",
+            "type": "boolean"
+          },
+          "parameters": {
+            "description": "Parameters and weights for constraint.",
+            "items": {
+              "$ref": "#/components/schemas/SumConstraintParameter"
+            },
+            "type": "array"
+          }
+        },
+        "required": [
+          "bound",
+          "parameters"
+        ],
+        "type": "object"
+      },
+      "SumConstraintParameter": {
+        "properties": {
+          "name": {
+            "description": "Name of parameter to be used in constraint.",
+            "type": "string"
+          },
+          "weight": {
+            "description": "Weight for parameter in constraint.",
+            "format": "double",
+            "type": "number"
+          }
+        },
+        "required": [
+          "name",
+          "weight"
+        ],
+        "type": "object"
+      },
+      "TrialAssignments": {
+        "properties": {
+          "assignments": {
+            "description": "The list of parameter names and their assigned values.",
+            "items": {
+              "$ref": "#/components/schemas/Assignment"
+            },
+            "type": "array"
+          }
+        },
+        "required": [
+          "assignments"
+        ],
+        "type": "object"
+      },
+      "TrialItem": {
+        "properties": {
+          "_metadata": {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/Metadata"
+              }
+            ],
+            "description": "The metadata for an individual trial."
+          },
+          "assignments": {
+            "description": "The list of parameter names and their assigned values.",
+            "items": {
+              "$ref": "#/components/schemas/Assignment"
+            },
+            "type": "array"
+          },
+          "failed": {
+            "description": "Indicator that the trial failed, Values is ignored when true.",
+            "type": "boolean"
+          },
+          "failureMessage": {
+            "description": "A human readable description of why the trial failed.",
+            "type": "string"
+          },
+          "failureReason": {
+            "description": "A machine readable reason the trial failed, e.g. \"Aborted\".",
+            "type": "string"
+          },
+          "labels": {
+            "additionalProperties": {
+              "type": "string"
+            },
+            "description": "Labels for this trial.",
+            "type": "object"
+          },
+          "number": {
+            "description": "Ordinal number indicating when during an experiment the trail was generated.",
+            "format": "int64",
+            "type": "integer"
+          },
+          "status": {
+            "allOf": [
+              {
+                "$ref": "#/components/schemas/TrialStatus"
+              }
+            ],
+            "description": "The current trial status."
+          },
+          "values": {
+            "description": "The observed values.",
+            "items": {
+              "$ref": "#/components/schemas/Value"
+            },
+            "type": "array"
+          }
+        },
+        "required": [
+          "assignments",
+          "number",
+          "status"
+        ],
+        "type": "object"
+      },
+      "TrialLabels": {
+        "properties": {
+          "labels": {
+            "additionalProperties": {
+              "type": "string"
+            },
+            "description": "New labels for this trial.",
+            "type": "object"
+          }
+        },
+        "required": [
+          "labels"
+        ],
+        "type": "object"
+      },
+      "TrialList": {
+        "properties": {
+          "trials": {
+            "description": "The list of trials.",
+            "items": {
+              "$ref": "#/components/schemas/TrialItem"
+            },
+            "type": "array"
+          }
+        },
+        "required": [
+          "trials"
+        ],
+        "type": "object"
+      },
+      "TrialReport": {
+        "description": "TrialReport is a completed trial which was run outside of the optimizer (e.g. historical benchmark data).",
+        "properties": {
+          "assignments": {
+            "description": "The list of parameter names and their assigned values.",
+            "items": {
+              "$ref": "#/components/schemas/Assignment"
+            },
+            "type": "array"
+          },
+          "failed": {
+            "description": "Indicator that the trial failed, Values is ignored when true.",
+            "type": "boolean"
+          },
+          "failureMessage": {
+            "description": "A human readable description of why the trial failed.",
+            "type": "string"
+          },
+          "failureReason": {
+            "description": "A machine readable reason the trial failed, e.g. \"Aborted\".",
+            "type": "string"
+          },
+          "labels": {
+            "additionalProperties": {
+              "type": "string"
+            },
+            "description": "Labels to apply to the trial.",
+            "type": "object"
+          },
+          "values": {
+            "description": "The observed values.",
+            "items": {
+              "$ref": "#/components/schemas/Value"
+            },
+            "type": "array"
+          }
+        },
+        "required": [
+          "assignments"
+        ],
+        "type": "object"
+      },
+      "TrialReports": {
+        "description": "TrialReports is a batch of completed trials.",
+        "properties": {
+          "trials": {
+            "description": "The completed trials.",
+            "items": {
+              "$ref": "#/components/schemas/TrialReport"
+            },
+            "type": "array"
+          }
+        },
+        "required": [
+          "trials"
+        ],
+        "type": "object"
+      },
+      "TrialStatus": {
+        "enum": [
+          "staged",
+          "active",
+          "completed",
+          "failed",
+          "abandoned"
+        ],
+        "type": "string"
+      },
+      "TrialValues": {
+        "properties": {
+          "failed": {
+            "description": "Indicator that the trial failed, Values is ignored when true.",
+            "type": "boolean"
+          },
+          "failureMessage": {
+            "description": "A human readable description of why the trial failed.",
+            "type": "string"
+          },
+          "failureReason": {
+            "description": "A machine readable reason the trial failed, e.g. \"Aborted\".",
+            "type": "string"
+          },
+          "values": {
+            "description": "The observed values.",
+            "items": {
+              "$ref": "#/components/schemas/Value"
+            },
+            "type": "array"
+          }
+        },
+        "type": "object"
+      },
+      "Value": {
+        "properties": {
+          "error": {
+            "description": "The observed error of the metric.",
+            "format": "double",
+            "type": "number"
+          },
+          "metricName": {
+            "description": "The name of the metric in the experiment the value corresponds to.",
+            "type": "string"
+          },
+          "value": {
+            "description": "The observed value of the metric.",
+            "format": "double",
+            "type": "number"
+          }
+        },
+        "required": [
+          "metricName",
+          "value"
+        ],
+        "type": "object"
+      }
+    }
+  },
+  "info": {
+    "title": "Red Sky API",
+    "version": "v1alpha1"
+  },
+  "openapi": "3.0.3",
+  "paths": {
+    "/experiments/": {
+      "get": {
+        "operationId": "getAllExperiments",
+        "parameters": [
+          {
+            "in": "query",
+            "name": "offset",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "in": "query",
+            "name": "limit",
+            "schema": {
+              "type": "integer"
+            }
+          },
+          {
+            "description": "Comma separated list of label value pairs to match on.",
+            "in": "query",
+            "name": "labelSelector",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/ExperimentList"
+                }
+              }
+            },
+            "description": "OK"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "List experiments"
+      },
+      "options": {
+        "operationId": "options",
+        "responses": {
+          "204": {
+            "description": "No Content"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "Describe the server"
+      }
+    },
+    "/experiments/{name}": {
+      "delete": {
+        "operationId": "deleteExperiment",
+        "parameters": [
+          {
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "204": {
+            "description": "No Content"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "Delete an experiment"
+      },
+      "get": {
+        "operationId": "getExperiment",
+        "parameters": [
+          {
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Experiment"
+                }
+              }
+            },
+            "description": "OK"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "Get an experiment"
+      },
+      "patch": {
+        "operationId": "updateExperiment",
+        "parameters": [
+          {
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/ExperimentPatch"
+              }
+            }
+          },
+          "required": true
+        },
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Experiment"
+                }
+              }
+            },
+            "description": "OK"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "Change the optimization settings of an experiment"
+      },
+      "put": {
+        "operationId": "createExperiment",
+        "parameters": [
+          {
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/Experiment"
+              }
+            }
+          },
+          "required": true
+        },
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Experiment"
+                }
+              }
+            },
+            "description": "OK"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "Create or update an experiment"
+      }
+    },
+    "/experiments/{name}/labels": {
+      "post": {
+        "operationId": "labelExperiment",
+        "parameters": [
+          {
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/ExperimentLabels"
+              }
+            }
+          },
+          "required": true
+        },
+        "responses": {
+          "204": {
+            "description": "No Content"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "Update experiment labels"
+      }
+    },
+    "/experiments/{name}/nextTrial": {
+      "post": {
+        "operationId": "nextTrial",
+        "parameters": [
+          {
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/TrialAssignments"
+                }
+              }
+            },
+            "description": "OK"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "Obtain the next trial suggestion"
+      }
+    },
+    "/experiments/{name}/trialReports": {
+      "post": {
+        "operationId": "reportTrials",
+        "parameters": [
+          {
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/TrialReports"
+              }
+            }
+          },
+          "required": true
+        },
+        "responses": {
+          "201": {
+            "description": "Created"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "Record multiple completed trials"
+      }
+    },
+    "/experiments/{name}/trials/": {
+      "get": {
+        "operationId": "getAllTrials",
+        "parameters": [
+          {
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "Comma separated list of statuses to fetch.",
+            "in": "query",
+            "name": "status",
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "description": "Comma separated list of label value pairs to match on.",
+            "in": "query",
+            "name": "labelSelector",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "responses": {
+          "200": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/TrialList"
+                }
+              }
+            },
+            "description": "OK"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "List trials"
+      },
+      "post": {
+        "operationId": "createTrial",
+        "parameters": [
+          {
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/TrialAssignments"
+              }
+            }
+          },
+          "required": true
+        },
+        "responses": {
+          "201": {
+            "description": "Created"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "Create a trial with explicit assignments"
+      }
+    },
+    "/experiments/{name}/trials/{number}": {
+      "delete": {
+        "operationId": "abandonRunningTrial",
+        "parameters": [
+          {
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "in": "path",
+            "name": "number",
+            "required": true,
+            "schema": {
+              "format": "int64",
+              "type": "integer"
+            }
+          }
+        ],
+        "responses": {
+          "204": {
+            "description": "No Content"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "Abandon a running trial"
+      },
+      "post": {
+        "operationId": "reportTrial",
+        "parameters": [
+          {
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "in": "path",
+            "name": "number",
+            "required": true,
+            "schema": {
+              "format": "int64",
+              "type": "integer"
+            }
+          },
+          {
+            "description": "Identifies retries of the same report, a conflicting retry is not an error.",
+            "in": "header",
+            "name": "Idempotency-Key",
+            "schema": {
+              "type": "string"
+            }
+          }
+        ],
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/TrialValues"
+              }
+            }
+          },
+          "required": true
+        },
+        "responses": {
+          "201": {
+            "description": "Created"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "Report trial observations"
+      }
+    },
+    "/experiments/{name}/trials/{number}/labels": {
+      "post": {
+        "operationId": "labelTrial",
+        "parameters": [
+          {
+            "in": "path",
+            "name": "name",
+            "required": true,
+            "schema": {
+              "type": "string"
+            }
+          },
+          {
+            "in": "path",
+            "name": "number",
+            "required": true,
+            "schema": {
+              "format": "int64",
+              "type": "integer"
+            }
+          }
+        ],
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {
+                "$ref": "#/components/schemas/TrialLabels"
+              }
+            }
+          },
+          "required": true
+        },
+        "responses": {
+          "204": {
+            "description": "No Content"
+          },
+          "default": {
+            "content": {
+              "application/json": {
+                "schema": {
+                  "$ref": "#/components/schemas/Error"
+                }
+              }
+            },
+            "description": "Error"
+          }
+        },
+        "summary": "Update trial labels"
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,95 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redskyapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Interceptor observes the interactions of a client with the Red Sky API Server
+type Interceptor interface {
+	// OnRequest is invoked before the request is sent
+	OnRequest(req *http.Request)
+	// OnResponse is invoked once the request completes, the response is nil if the request failed
+	OnResponse(req *http.Request, resp *http.Response, err error, elapsed time.Duration)
+}
+
+// WithInterceptors returns a client which notifies the supplied interceptors of every request
+func WithInterceptors(c Client, interceptors ...Interceptor) Client {
+	if len(interceptors) == 0 {
+		return c
+	}
+	return &interceptingClient{Client: c, interceptors: interceptors}
+}
+
+type interceptingClient struct {
+	Client
+	interceptors []Interceptor
+}
+
+func (c *interceptingClient) Do(ctx context.Context, req *http.Request) (*http.Response, []byte, error) {
+	for _, i := range c.interceptors {
+		i.OnRequest(req)
+	}
+
+	start := time.Now()
+	resp, body, err := c.Client.Do(ctx, req)
+	elapsed := time.Since(start)
+
+	for _, i := range c.interceptors {
+		i.OnResponse(req, resp, err, elapsed)
+	}
+	return resp, body, err
+}
+
+// Endpoint returns a low cardinality description of the request path suitable for use as a metric label, the
+// individual experiment and trial identifiers are replaced with "*"
+func Endpoint(req *http.Request) string {
+	segments := strings.Split(req.URL.Path, "/")
+	for i := 1; i < len(segments); i++ {
+		if segments[i] != "" && (segments[i-1] == "experiments" || segments[i-1] == "trials") {
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// LoggingInterceptor writes a line describing each request and response, it is intended for debugging
+type LoggingInterceptor struct {
+	// Out is where the log is written
+	Out io.Writer
+}
+
+// OnRequest logs the request method and URL
+func (l *LoggingInterceptor) OnRequest(req *http.Request) {
+	_, _ = fmt.Fprintf(l.Out, "> %s %s\n", req.Method, req.URL)
+}
+
+// OnResponse logs the response status (or error) and how long the request took
+func (l *LoggingInterceptor) OnResponse(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	elapsed = elapsed.Round(time.Millisecond)
+	if err != nil {
+		_, _ = fmt.Fprintf(l.Out, "< %s %s failed after %s: %v\n", req.Method, req.URL, elapsed, err)
+		return
+	}
+	_, _ = fmt.Fprintf(l.Out, "< %s %s %s in %s\n", req.Method, req.URL, resp.Status, elapsed)
+}
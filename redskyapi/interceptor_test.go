@@ -0,0 +1,170 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redskyapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubClient is a client which returns a fixed response without making any requests
+type stubClient struct {
+	resp *http.Response
+	err  error
+	reqs []*http.Request
+}
+
+func (c *stubClient) URL(endpoint string) *url.URL {
+	return &url.URL{Scheme: "http", Host: "example.com", Path: endpoint}
+}
+
+func (c *stubClient) Do(_ context.Context, req *http.Request) (*http.Response, []byte, error) {
+	c.reqs = append(c.reqs, req)
+	return c.resp, nil, c.err
+}
+
+// recordingInterceptor records the callbacks it receives
+type recordingInterceptor struct {
+	name  string
+	calls *[]string
+}
+
+func (r *recordingInterceptor) OnRequest(req *http.Request) {
+	*r.calls = append(*r.calls, fmt.Sprintf("%s request %s", r.name, req.URL.Path))
+}
+
+func (r *recordingInterceptor) OnResponse(req *http.Request, resp *http.Response, err error, elapsed time.Duration) {
+	status := "<nil>"
+	if resp != nil {
+		status = resp.Status
+	}
+	*r.calls = append(*r.calls, fmt.Sprintf("%s response %s %s %v", r.name, req.URL.Path, status, err))
+}
+
+func TestEndpoint(t *testing.T) {
+	cases := []struct {
+		desc     string
+		path     string
+		expected string
+	}{
+		{
+			desc:     "root",
+			path:     "/",
+			expected: "/",
+		},
+		{
+			desc:     "experiment list",
+			path:     "/v1/experiments/",
+			expected: "/v1/experiments/",
+		},
+		{
+			desc:     "experiment",
+			path:     "/v1/experiments/my-experiment",
+			expected: "/v1/experiments/*",
+		},
+		{
+			desc:     "trial list",
+			path:     "/v1/experiments/my-experiment/trials/",
+			expected: "/v1/experiments/*/trials/",
+		},
+		{
+			desc:     "trial",
+			path:     "/v1/experiments/my-experiment/trials/42",
+			expected: "/v1/experiments/*/trials/*",
+		},
+		{
+			desc:     "trial sub-resource",
+			path:     "/v1/experiments/my-experiment/trials/42/labels",
+			expected: "/v1/experiments/*/trials/*/labels",
+		},
+		{
+			desc:     "next trial",
+			path:     "/v1/experiments/other-experiment/nextTrial",
+			expected: "/v1/experiments/*/nextTrial",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "http://example.com"+c.path, nil)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, Endpoint(req))
+		})
+	}
+}
+
+func TestWithInterceptors(t *testing.T) {
+	c := &stubClient{}
+	assert.Same(t, c, WithInterceptors(c))
+
+	var calls []string
+	c.resp = &http.Response{Status: "200 OK", StatusCode: http.StatusOK}
+	ic := WithInterceptors(c,
+		&recordingInterceptor{name: "first", calls: &calls},
+		&recordingInterceptor{name: "second", calls: &calls})
+	assert.Equal(t, c.URL("/v1/experiments/"), ic.URL("/v1/experiments/"))
+
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/v1/experiments/", nil)
+	require.NoError(t, err)
+	resp, _, err := ic.Do(context.TODO(), req)
+	require.NoError(t, err)
+	assert.Same(t, c.resp, resp)
+	assert.Equal(t, []*http.Request{req}, c.reqs)
+	assert.Equal(t, []string{
+		"first request /v1/experiments/",
+		"second request /v1/experiments/",
+		"first response /v1/experiments/ 200 OK <nil>",
+		"second response /v1/experiments/ 200 OK <nil>",
+	}, calls)
+}
+
+func TestWithInterceptors_Error(t *testing.T) {
+	var calls []string
+	c := &stubClient{err: fmt.Errorf("connection refused")}
+	ic := WithInterceptors(c, &recordingInterceptor{name: "only", calls: &calls})
+
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/v1/experiments/foo/trials/", nil)
+	require.NoError(t, err)
+	resp, _, err := ic.Do(context.TODO(), req)
+	assert.Nil(t, resp)
+	assert.EqualError(t, err, "connection refused")
+	assert.Equal(t, []string{
+		"only request /v1/experiments/foo/trials/",
+		"only response /v1/experiments/foo/trials/ <nil> connection refused",
+	}, calls)
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	var out bytes.Buffer
+	l := &LoggingInterceptor{Out: &out}
+	req, err := http.NewRequest(http.MethodGet, "http://example.com/v1/experiments/", nil)
+	require.NoError(t, err)
+
+	l.OnRequest(req)
+	l.OnResponse(req, &http.Response{Status: "200 OK"}, nil, 1500*time.Microsecond)
+	l.OnResponse(req, nil, fmt.Errorf("timeout"), 2*time.Second)
+	assert.Equal(t, "> GET http://example.com/v1/experiments/\n"+
+		"< GET http://example.com/v1/experiments/ 200 OK in 2ms\n"+
+		"< GET http://example.com/v1/experiments/ failed after 2s: timeout\n", out.String())
+}
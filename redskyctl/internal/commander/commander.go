@@ -22,10 +22,12 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"strings"
 
 	redskyv1alpha1 "github.com/redskyops/redskyops-controller/api/v1alpha1"
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	internalconfig "github.com/redskyops/redskyops-controller/internal/config"
+	"github.com/redskyops/redskyops-controller/internal/tlspolicy"
 	"github.com/redskyops/redskyops-controller/redskyapi"
 	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
@@ -50,6 +52,8 @@ type IOStreams struct {
 	Out io.Writer
 	// ErrOut is used to access the standard error output stream (or it's override)
 	ErrOut io.Writer
+	// Output controls how human-facing output is produced
+	Output OutputMode
 }
 
 // SetStreams updates the streams using the supplied command
@@ -57,6 +61,13 @@ func SetStreams(streams *IOStreams, cmd *cobra.Command) {
 	streams.Out = cmd.OutOrStdout()
 	streams.ErrOut = cmd.ErrOrStderr()
 	streams.In = cmd.InOrStdin()
+	streams.Output = outputMode(cmd)
+
+	// Progress and colors are only for interactive use, never for scripts
+	if !streams.Output.Quiet && !streams.Output.Porcelain && isTerminal(streams.ErrOut) && os.Getenv("TERM") != "dumb" {
+		streams.Output.Interactive = true
+		streams.Output.Color = os.Getenv("NO_COLOR") == ""
+	}
 }
 
 // StreamsPreRun is intended to be used as a pre-run function for commands when no other action is required
@@ -69,14 +80,23 @@ func StreamsPreRun(streams *IOStreams) func(cmd *cobra.Command, args []string) {
 // SetExperimentsAPI creates a new experiments API interface from the supplied configuration
 func SetExperimentsAPI(api *experimentsv1alpha1.API, cfg config.Config, cmd *cobra.Command) error {
 	ctx := cmd.Context()
+	if ctx == nil {
+		// Commands invoked for shell completion do not have a context
+		ctx = context.Background()
+	}
 
-	// Reuse the OAuth2 base transport for the API calls
-	t := oauth2.NewClient(ctx, nil).Transport
+	// Reuse the OAuth2 base transport for the API calls, repeated reads (e.g. when watching) are made conditional
+	t := &redskyapi.CachingTransport{Base: oauth2.NewClient(ctx, nil).Transport}
 	c, err := redskyapi.NewClient(ctx, cfg, t)
 	if err != nil {
 		return err
 	}
 
+	// Log the remote requests for debugging
+	if v, _ := cmd.Flags().GetInt("v"); v > 0 {
+		c = redskyapi.WithInterceptors(c, &redskyapi.LoggingInterceptor{Out: cmd.ErrOrStderr()})
+	}
+
 	*api = experimentsv1alpha1.NewAPI(c)
 	return nil
 }
@@ -85,7 +105,8 @@ func SetExperimentsAPI(api *experimentsv1alpha1.API, cfg config.Config, cmd *cob
 func SetPrinter(meta TableMeta, printer *ResourcePrinter, cmd *cobra.Command) {
 	pf := newPrintFlags(meta, cmd.Annotations)
 	pf.addFlags(cmd)
-	AddPreRunE(cmd, func(*cobra.Command, []string) error {
+	AddPreRunE(cmd, func(cmd *cobra.Command, _ []string) error {
+		pf.output = outputMode(cmd)
 		return pf.toPrinter(printer)
 	})
 }
@@ -98,7 +119,8 @@ func SetKubePrinter(printer *ResourcePrinter, cmd *cobra.Command) {
 	_ = redskyv1beta1.AddToScheme(kp.scheme)
 	pf := newPrintFlags(kp, cmd.Annotations)
 	pf.addFlags(cmd)
-	AddPreRunE(cmd, func(*cobra.Command, []string) error {
+	AddPreRunE(cmd, func(cmd *cobra.Command, _ []string) error {
+		pf.output = outputMode(cmd)
 		if err := pf.toPrinter(&kp.printer); err != nil {
 			return err
 		}
@@ -125,6 +147,25 @@ func ConfigGlobals(cfg *internalconfig.RedSkyConfig, cmd *cobra.Command) {
 	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error { return cfg.Load() }
 }
 
+// TLSGlobals sets up persistent globals for the outbound TLS policy
+func TLSGlobals(policy *tlspolicy.Policy, cmd *cobra.Command) {
+	// Make sure we get the root to make these globals
+	root := cmd.Root()
+
+	root.PersistentFlags().StringVar(&policy.MinVersion, "tls-min-version", policy.MinVersion, "Minimum TLS version for outbound connections, e.g. 1.2.")
+	root.PersistentFlags().StringSliceVar(&policy.CipherSuites, "tls-cipher-suites", policy.CipherSuites, "Comma-separated list of cipher suites allowed for outbound connections.")
+	root.PersistentFlags().BoolVar(&policy.FIPS, "tls-fips", policy.FIPS, "Restrict outbound connections to FIPS approved TLS versions and cipher suites.")
+
+	// Use an initializer instead of a persistent pre-run so commands which supply their own still get the policy
+	cobra.OnInitialize(func() {
+		policy.CipherSuites = tlspolicy.SplitCipherSuites(strings.Join(policy.CipherSuites, ","))
+		if err := policy.Apply(); err != nil {
+			root.PrintErr("Error: ", err.Error(), "\n")
+			os.Exit(1)
+		}
+	})
+}
+
 // WithContextE wraps a function that accepts a context in one that accepts a command and argument slice
 func WithContextE(runE func(context.Context) error) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, _ []string) error { return runE(cmd.Context()) }
@@ -182,7 +223,7 @@ func ExitOnError(cmd *cobra.Command) {
 			// Handle unauthorized errors by suggesting `login`
 			if experimentsv1alpha1.IsUnauthorized(err) {
 				msg := "unauthorized"
-				if _, ok := err.(*experimentsv1alpha1.Error); ok {
+				if _, ok := experimentsv1alpha1.AsError(err); ok {
 					msg = err.Error()
 				}
 				err = fmt.Errorf("%s, try running 'redskyctl login'", msg)
@@ -190,6 +231,13 @@ func ExitOnError(cmd *cobra.Command) {
 
 			// TODO With the exception of silence usage behavior and stdout vs. stderr, this is basically what Cobra already does with a RunE...
 			cmd.PrintErr("Error: ", err.Error(), "\n")
+
+			// Show the individual fields the server rejected so the problem can be corrected
+			if aerr, ok := experimentsv1alpha1.AsError(err); ok {
+				for _, v := range aerr.FieldViolations {
+					cmd.PrintErr("  ", v.Field, ": ", v.Description, "\n")
+				}
+			}
 			os.Exit(1)
 		}
 	}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commander
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// Message is a human-facing format string, the message itself is the English text and is used to look up translations
+type Message string
+
+const (
+	// MsgNoResources is displayed in place of an empty table
+	MsgNoResources Message = "No resources found."
+	// MsgAction is used to report an action taken on a named resource
+	MsgAction Message = "%s \"%s\" %s"
+	// MsgAborted is the action reported when a resource is aborted
+	MsgAborted Message = "aborted"
+	// MsgCreated is the action reported when a resource is created
+	MsgCreated Message = "created"
+	// MsgDeleted is the action reported when a resource is deleted
+	MsgDeleted Message = "deleted"
+	// MsgLabeled is the action reported when a resource is labeled
+	MsgLabeled Message = "labeled"
+)
+
+var (
+	catalogsMu sync.RWMutex
+	catalogs   = make(map[string]map[Message]string)
+)
+
+// RegisterCatalog adds the translations for a language, the language is the lower case ISO 639 code (e.g. "de")
+func RegisterCatalog(lang string, translations map[Message]string) {
+	catalogsMu.Lock()
+	defer catalogsMu.Unlock()
+	catalogs[lang] = translations
+}
+
+// OutputMode controls how human-facing output is produced
+type OutputMode struct {
+	// Quiet limits output to just the identifiers of the affected resources
+	Quiet bool
+	// Porcelain produces stable, parse-friendly output that does not depend on the terminal or the locale
+	Porcelain bool
+	// Lang is the language used to translate messages, English is used if empty or not available
+	Lang string
+	// Interactive indicates that progress can be displayed on the error stream
+	Interactive bool
+	// Color indicates that ANSI colors can be used on the error stream
+	Color bool
+}
+
+// OutputGlobals sets up persistent globals for controlling the output mode
+func OutputGlobals(cmd *cobra.Command) {
+	// Make sure we get the root to make these globals
+	root := cmd.Root()
+
+	root.PersistentFlags().BoolP("quiet", "q", false, "Only print the identifiers of resources.")
+	root.PersistentFlags().Bool("porcelain", false, "Produce stable, parse-friendly output regardless of the terminal or locale.")
+	root.PersistentFlags().Int("v", 0, "Debug log `level`, 1 or higher logs requests made to the remote server.")
+}
+
+// outputMode returns the output mode for the supplied command
+func outputMode(cmd *cobra.Command) OutputMode {
+	m := OutputMode{}
+	m.Quiet, _ = cmd.Flags().GetBool("quiet")
+	m.Porcelain, _ = cmd.Flags().GetBool("porcelain")
+	if !m.Porcelain {
+		m.Lang = language()
+	}
+	return m
+}
+
+// Sprintf formats a message, translating it if possible
+func (m OutputMode) Sprintf(msg Message, args ...interface{}) string {
+	return fmt.Sprintf(m.translate(msg), args...)
+}
+
+// PrintAction reports that an action was taken on a resource; quiet output only includes the name of the resource and
+// porcelain output is a tab delimited record of kind, name and action
+func (m OutputMode) PrintAction(w io.Writer, kind, name string, action Message) error {
+	var err error
+	switch {
+	case m.Quiet:
+		_, err = fmt.Fprintln(w, name)
+	case m.Porcelain:
+		_, err = fmt.Fprintf(w, "%s\t%s\t%s\n", kind, name, action)
+	default:
+		_, err = fmt.Fprintln(w, m.Sprintf(MsgAction, kind, name, m.translate(action)))
+	}
+	return err
+}
+
+// translate returns the format string to use for a message
+func (m OutputMode) translate(msg Message) string {
+	if m.Porcelain || m.Lang == "" {
+		return string(msg)
+	}
+
+	catalogsMu.RLock()
+	defer catalogsMu.RUnlock()
+	if s, ok := catalogs[m.Lang][msg]; ok {
+		return s
+	}
+	return string(msg)
+}
+
+// language returns the language of the current locale
+func language() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale := os.Getenv(env); locale != "" {
+			// Strip the territory, encoding and modifier, e.g. "de_DE.UTF-8@euro"
+			parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '_' || r == '.' || r == '@' || r == '-' })
+			if len(parts) == 0 {
+				return ""
+			}
+			lang := strings.ToLower(parts[0])
+			if lang == "c" || lang == "posix" {
+				return ""
+			}
+			return lang
+		}
+	}
+	return ""
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commander
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputMode_PrintAction(t *testing.T) {
+	RegisterCatalog("xx", map[Message]string{MsgAction: "%[3]s: %[1]s %[2]s", MsgDeleted: "gone"})
+
+	cases := []struct {
+		desc     string
+		output   OutputMode
+		expected string
+	}{
+		{
+			desc:     "default",
+			expected: "trial \"test-001\" deleted\n",
+		},
+		{
+			desc:     "quiet",
+			output:   OutputMode{Quiet: true},
+			expected: "test-001\n",
+		},
+		{
+			desc:     "porcelain",
+			output:   OutputMode{Porcelain: true, Lang: "xx"},
+			expected: "trial\ttest-001\tdeleted\n",
+		},
+		{
+			desc:     "translated",
+			output:   OutputMode{Lang: "xx"},
+			expected: "gone: trial test-001\n",
+		},
+		{
+			desc:     "missing catalog",
+			output:   OutputMode{Lang: "yy"},
+			expected: "trial \"test-001\" deleted\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NoError(t, c.output.PrintAction(&buf, "trial", "test-001", MsgDeleted))
+			assert.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+// testMeta is a table meta for string slices
+type testMeta struct{}
+
+func (testMeta) ExtractList(obj interface{}) ([]interface{}, error) {
+	var list []interface{}
+	for _, row := range obj.([][]string) {
+		list = append(list, row)
+	}
+	return list, nil
+}
+
+func (testMeta) Columns(interface{}, string, bool) []string { return []string{"name", "value"} }
+
+func (testMeta) ExtractValue(obj interface{}, column string) (string, error) {
+	if column == "name" {
+		return obj.([]string)[0], nil
+	}
+	return obj.([]string)[1], nil
+}
+
+func (testMeta) Header(_ string, column string) string { return column }
+
+func TestPrintFlags_OutputMode(t *testing.T) {
+	rows := [][]string{{"a", "1"}, {"bbbbbb", "2"}}
+
+	cases := []struct {
+		desc         string
+		outputFormat string
+		output       OutputMode
+		rows         [][]string
+		expected     string
+	}{
+		{
+			desc:     "default",
+			rows:     rows,
+			expected: "name     value   \na        1       \nbbbbbb   2       \n",
+		},
+		{
+			desc:     "default empty",
+			expected: "No resources found.\n",
+		},
+		{
+			desc:     "quiet",
+			output:   OutputMode{Quiet: true},
+			rows:     rows,
+			expected: "a\nbbbbbb\n",
+		},
+		{
+			desc:         "quiet csv",
+			outputFormat: "csv",
+			output:       OutputMode{Quiet: true},
+			rows:         rows,
+			expected:     "a\nbbbbbb\n",
+		},
+		{
+			desc:     "quiet empty",
+			output:   OutputMode{Quiet: true},
+			expected: "",
+		},
+		{
+			desc:     "porcelain",
+			output:   OutputMode{Porcelain: true},
+			rows:     rows,
+			expected: "name\tvalue\na\t1\nbbbbbb\t2\n",
+		},
+		{
+			desc:     "porcelain empty",
+			output:   OutputMode{Porcelain: true},
+			expected: "",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			pf := newPrintFlags(testMeta{}, map[string]string{PrinterOutputFormat: c.outputFormat})
+			pf.output = c.output
+
+			var p ResourcePrinter
+			var buf bytes.Buffer
+			if assert.NoError(t, pf.toPrinter(&p)) {
+				assert.NoError(t, p.PrintObj(c.rows, &buf))
+				assert.Equal(t, c.expected, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commander
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// OutputGroup is the API group used to identify the schema of structured output
+const OutputGroup = "redskyctl.redskyops.dev"
+
+// OutputVersions are the supported versions of the structured output schema, the first entry is the default
+var OutputVersions = []string{"v1alpha1", "none"}
+
+// checkOutputVersion ensures the requested output version is supported
+func checkOutputVersion(outputVersion string) error {
+	for _, v := range OutputVersions {
+		if strings.EqualFold(outputVersion, v) {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported output version %q, allowed versions are: %s", outputVersion, strings.Join(OutputVersions, ","))
+}
+
+// versionObject wraps the supplied object with the "kind" and "apiVersion" fields of the requested output version;
+// objects which already declare an API version (e.g. Kubernetes objects) are returned unchanged
+func versionObject(obj interface{}, outputVersion string) (interface{}, error) {
+	if outputVersion == "" || strings.EqualFold(outputVersion, "none") {
+		return obj, nil
+	}
+
+	kind := outputKind(obj)
+	if kind == "" {
+		return obj, nil
+	}
+
+	// Round trip through JSON so the wrapper fields appear alongside the object's own fields
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(b, &m); err != nil {
+		// Not a JSON object, leave it alone
+		return obj, nil
+	}
+	if _, ok := m["apiVersion"]; ok {
+		return obj, nil
+	}
+
+	m["apiVersion"] = OutputGroup + "/" + strings.ToLower(outputVersion)
+	m["kind"] = kind
+	return m, nil
+}
+
+// outputKind returns the kind of an object based on its type name, e.g. `ExperimentItem` is an `Experiment`
+func outputKind(obj interface{}) string {
+	t := reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || t.Name() == "" {
+		return ""
+	}
+	return strings.TrimSuffix(t.Name(), "Item")
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commander
+
+import (
+	"testing"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestVersionObject(t *testing.T) {
+	cases := []struct {
+		desc          string
+		obj           interface{}
+		outputVersion string
+		expected      interface{}
+	}{
+		{
+			desc:          "experiment item",
+			obj:           &experimentsv1alpha1.ExperimentItem{Experiment: experimentsv1alpha1.Experiment{DisplayName: "test"}},
+			outputVersion: "v1alpha1",
+			expected: map[string]interface{}{
+				"apiVersion":  "redskyctl.redskyops.dev/v1alpha1",
+				"kind":        "Experiment",
+				"displayName": "test",
+				"metrics":     nil,
+				"parameters":  nil,
+			},
+		},
+		{
+			desc:          "trial list",
+			obj:           &experimentsv1alpha1.TrialList{},
+			outputVersion: "v1alpha1",
+			expected: map[string]interface{}{
+				"apiVersion": "redskyctl.redskyops.dev/v1alpha1",
+				"kind":       "TrialList",
+				"trials":     nil,
+			},
+		},
+		{
+			desc:          "unversioned",
+			obj:           &experimentsv1alpha1.TrialList{},
+			outputVersion: "none",
+			expected:      &experimentsv1alpha1.TrialList{},
+		},
+		{
+			desc:          "kube object",
+			obj:           &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}},
+			outputVersion: "v1alpha1",
+			expected:      &unstructured.Unstructured{Object: map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			actual, err := versionObject(c.obj, c.outputVersion)
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCheckOutputVersion(t *testing.T) {
+	assert.NoError(t, checkOutputVersion("v1alpha1"))
+	assert.NoError(t, checkOutputVersion("none"))
+	assert.Error(t, checkOutputVersion("v2"))
+}
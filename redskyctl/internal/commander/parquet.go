@@ -0,0 +1,345 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commander
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// parquetPrinter generates Apache Parquet output, the column types are inferred from the extracted values so numbers
+// and timestamps are stored as typed columns; only uncompressed, plain encoded single row group files are produced
+type parquetPrinter struct {
+	// meta is used to extract information about the objects being formatted
+	meta TableMeta
+	// showLabels determines if a column should be included for each distinct label
+	showLabels bool
+}
+
+// PrintObj generates the Parquet file
+func (p *parquetPrinter) PrintObj(obj interface{}, w io.Writer) error {
+	rows, err := p.meta.ExtractList(obj)
+	if err != nil {
+		return err
+	}
+
+	columns := p.meta.Columns(obj, "parquet", p.showLabels)
+	pw := &parquetWriter{}
+	for x := range columns {
+		values := make([]string, len(rows))
+		for y := range rows {
+			if values[y], err = p.meta.ExtractValue(rows[y], columns[x]); err != nil {
+				return err
+			}
+		}
+		pw.addColumn(p.meta.Header("parquet", columns[x]), values)
+	}
+	pw.numRows = int64(len(rows))
+
+	_, err = w.Write(pw.bytes())
+	return err
+}
+
+// Parquet physical types, converted types and encodings (from the Parquet Thrift definitions)
+const (
+	parquetInt64     int32 = 2
+	parquetDouble    int32 = 5
+	parquetByteArray int32 = 6
+
+	parquetUTF8            int32 = 0
+	parquetTimestampMillis int32 = 9
+
+	parquetOptional int32 = 1
+
+	parquetPlain int32 = 0
+	parquetRLE   int32 = 3
+)
+
+// parquetColumn is a single optional column of the file, empty values are stored as nulls
+type parquetColumn struct {
+	name          string
+	physicalType  int32
+	convertedType int32
+	values        []string
+	offset        int64
+	size          int64
+}
+
+// parquetWriter accumulates columns to write a Parquet file
+type parquetWriter struct {
+	columns []*parquetColumn
+	numRows int64
+}
+
+// addColumn adds a column, the type is the narrowest type capable of representing all of the non-empty values
+func (pw *parquetWriter) addColumn(name string, values []string) {
+	c := &parquetColumn{name: name, values: values, physicalType: parquetInt64, convertedType: -1}
+	isInt, isFloat, isTime := true, true, true
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+			isInt = false
+		}
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			isFloat = false
+		}
+		if _, err := time.Parse(time.RFC3339, v); err != nil {
+			isTime = false
+		}
+	}
+
+	switch {
+	case isInt:
+	case isFloat:
+		c.physicalType = parquetDouble
+	case isTime:
+		c.convertedType = parquetTimestampMillis
+	default:
+		c.physicalType, c.convertedType = parquetByteArray, parquetUTF8
+	}
+	pw.columns = append(pw.columns, c)
+}
+
+// bytes returns the encoded file
+func (pw *parquetWriter) bytes() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("PAR1")
+	for _, c := range pw.columns {
+		page := c.page()
+		header := &thriftWriter{}
+		header.i32(1, 0) // DATA_PAGE
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(len(page)))
+		header.beginStruct(5)
+		header.i32(1, int32(len(c.values)))
+		header.i32(2, parquetPlain)
+		header.i32(3, parquetRLE)
+		header.i32(4, parquetRLE)
+		header.endStruct()
+		header.stop()
+
+		c.offset = int64(buf.Len())
+		buf.Write(header.Bytes())
+		buf.Write(page)
+		c.size = int64(buf.Len()) - c.offset
+	}
+
+	footer := pw.metadata()
+	buf.Write(footer)
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(footer)))
+	buf.WriteString("PAR1")
+	return buf.Bytes()
+}
+
+// metadata returns the encoded file metadata
+func (pw *parquetWriter) metadata() []byte {
+	var totalSize int64
+	for _, c := range pw.columns {
+		totalSize += c.size
+	}
+
+	t := &thriftWriter{}
+	t.i32(1, 1)
+	t.beginList(2, thriftStruct, len(pw.columns)+1)
+	t.reset()
+	t.binary(4, "schema")
+	t.i32(5, int32(len(pw.columns)))
+	t.stop()
+	for _, c := range pw.columns {
+		t.reset()
+		t.i32(1, c.physicalType)
+		t.i32(3, parquetOptional)
+		t.binary(4, c.name)
+		if c.convertedType >= 0 {
+			t.i32(6, c.convertedType)
+		}
+		t.stop()
+	}
+	t.endList()
+	t.i64(3, pw.numRows)
+	t.beginList(4, thriftStruct, 1)
+	t.reset()
+	t.beginList(1, thriftStruct, len(pw.columns))
+	for _, c := range pw.columns {
+		t.reset()
+		t.i64(2, c.offset)
+		t.beginStruct(3)
+		t.i32(1, c.physicalType)
+		t.beginList(2, thriftI32, 2)
+		t.varint(int64(parquetPlain))
+		t.varint(int64(parquetRLE))
+		t.endList()
+		t.beginList(3, thriftBinary, 1)
+		t.rawBinary(c.name)
+		t.endList()
+		t.i32(4, 0) // UNCOMPRESSED
+		t.i64(5, int64(len(c.values)))
+		t.i64(6, c.size)
+		t.i64(7, c.size)
+		t.i64(9, c.offset)
+		t.endStruct()
+		t.stop()
+	}
+	t.endList()
+	t.i64(2, totalSize)
+	t.i64(3, pw.numRows)
+	t.stop()
+	t.endList()
+	t.binary(6, "redskyctl")
+	t.stop()
+	return t.Bytes()
+}
+
+// page returns the data page contents: the definition levels followed by the plain encoded non-null values
+func (c *parquetColumn) page() []byte {
+	// Definition levels use a single bit-packed run of the RLE/bit-packing hybrid encoding
+	groups := (len(c.values) + 7) / 8
+	levels := make([]byte, 0, groups+binary.MaxVarintLen64)
+	levels = appendUvarint(levels, uint64(groups)<<1|1)
+	packed := make([]byte, groups)
+	for i, v := range c.values {
+		if v != "" {
+			packed[i/8] |= 1 << uint(i%8)
+		}
+	}
+	levels = append(levels, packed...)
+
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(len(levels)))
+	buf.Write(levels)
+	for _, v := range c.values {
+		if v == "" {
+			continue
+		}
+		switch {
+		case c.physicalType == parquetByteArray:
+			_ = binary.Write(&buf, binary.LittleEndian, uint32(len(v)))
+			buf.WriteString(v)
+		case c.physicalType == parquetDouble:
+			f, _ := strconv.ParseFloat(v, 64)
+			_ = binary.Write(&buf, binary.LittleEndian, math.Float64bits(f))
+		case c.convertedType == parquetTimestampMillis:
+			t, _ := time.Parse(time.RFC3339, v)
+			_ = binary.Write(&buf, binary.LittleEndian, t.UnixNano()/int64(time.Millisecond))
+		default:
+			i, _ := strconv.ParseInt(v, 10, 64)
+			_ = binary.Write(&buf, binary.LittleEndian, i)
+		}
+	}
+	return buf.Bytes()
+}
+
+// Thrift compact protocol types
+const (
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftBinary byte = 8
+	thriftList   byte = 9
+	thriftStruct byte = 12
+)
+
+// thriftWriter is a minimal implementation of the Thrift compact protocol sufficient for encoding Parquet metadata
+type thriftWriter struct {
+	bytes.Buffer
+	lastField []int16
+	field     int16
+}
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - t.field; delta > 0 && delta <= 15 {
+		t.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	t.field = id
+}
+
+func (t *thriftWriter) varint(v int64) {
+	// Zigzag encoding of a signed integer
+	t.Write(appendUvarint(nil, uint64((v<<1)^(v>>63))))
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(int64(v))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(v)
+}
+
+func (t *thriftWriter) binary(id int16, v string) {
+	t.fieldHeader(id, thriftBinary)
+	t.rawBinary(v)
+}
+
+func (t *thriftWriter) rawBinary(v string) {
+	t.Write(appendUvarint(nil, uint64(len(v))))
+	t.WriteString(v)
+}
+
+func (t *thriftWriter) beginStruct(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.lastField = append(t.lastField, t.field)
+	t.field = 0
+}
+
+func (t *thriftWriter) endStruct() {
+	t.stop()
+	t.field = t.lastField[len(t.lastField)-1]
+	t.lastField = t.lastField[:len(t.lastField)-1]
+}
+
+// beginList writes a list field header, struct elements must each start with reset and end with stop
+func (t *thriftWriter) beginList(id int16, elementType byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.WriteByte(byte(size)<<4 | elementType)
+	} else {
+		t.WriteByte(0xF0 | elementType)
+		t.Write(appendUvarint(nil, uint64(size)))
+	}
+	t.lastField = append(t.lastField, t.field)
+}
+
+func (t *thriftWriter) endList() {
+	t.field = t.lastField[len(t.lastField)-1]
+	t.lastField = t.lastField[:len(t.lastField)-1]
+}
+
+// reset starts a new struct within a list
+func (t *thriftWriter) reset() {
+	t.field = 0
+}
+
+// stop ends the current struct
+func (t *thriftWriter) stop() {
+	t.WriteByte(0)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commander
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParquetColumnTypes(t *testing.T) {
+	cases := []struct {
+		desc          string
+		values        []string
+		physicalType  int32
+		convertedType int32
+	}{
+		{desc: "int", values: []string{"1", "", "3"}, physicalType: parquetInt64, convertedType: -1},
+		{desc: "float", values: []string{"1", "2.5"}, physicalType: parquetDouble, convertedType: -1},
+		{desc: "time", values: []string{"2020-01-01T00:00:00Z"}, physicalType: parquetInt64, convertedType: parquetTimestampMillis},
+		{desc: "string", values: []string{"1", "a"}, physicalType: parquetByteArray, convertedType: parquetUTF8},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			pw := &parquetWriter{}
+			pw.addColumn("c", c.values)
+			if assert.Len(t, pw.columns, 1) {
+				assert.Equal(t, c.physicalType, pw.columns[0].physicalType)
+				assert.Equal(t, c.convertedType, pw.columns[0].convertedType)
+			}
+		})
+	}
+}
+
+func TestParquetFile(t *testing.T) {
+	pw := &parquetWriter{numRows: 2}
+	pw.addColumn("number", []string{"1", "2"})
+	pw.addColumn("status", []string{"completed", ""})
+	b := pw.bytes()
+
+	// The file must start and end with the magic number and the footer length must point at the metadata
+	if assert.True(t, len(b) > 12) {
+		assert.Equal(t, "PAR1", string(b[:4]))
+		assert.Equal(t, "PAR1", string(b[len(b)-4:]))
+		footer := int(binary.LittleEndian.Uint32(b[len(b)-8 : len(b)-4]))
+		assert.True(t, footer > 0 && footer < len(b)-12)
+	}
+
+	// Column chunks must be laid out sequentially after the magic number
+	offset := int64(4)
+	for _, c := range pw.columns {
+		assert.Equal(t, offset, c.offset)
+		offset += c.size
+	}
+}
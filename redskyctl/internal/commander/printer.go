@@ -34,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/util/jsonpath"
 	"sigs.k8s.io/yaml"
 )
 
@@ -50,6 +51,8 @@ const (
 	PrinterShowLabels = "showLabels"
 	// PrinterHideStatus is the configuration key for setting the initial hide status flag
 	PrinterHideStatus = "hideStatus"
+	// PrinterOutputVersion is the configuration key for setting the initial structured output schema version
+	PrinterOutputVersion = "outputVersion"
 )
 
 // ResourcePrinter formats an object to a byte stream
@@ -88,12 +91,21 @@ func (e NoPrinterError) Error() string {
 // requiresMeta returns true for the formats that require a TableMeta
 func requiresMeta(outputFormat string) bool {
 	switch outputFormat {
-	case "name", "wide", "csv", "":
+	case "name", "wide", "csv", "csv-wide", "parquet", "custom-columns", "":
 		return true
 	}
 	return false
 }
 
+// splitOutputFormat separates the name of an output format from the template argument of formats like "jsonpath"
+func splitOutputFormat(outputFormat string) (string, string) {
+	s := strings.SplitN(outputFormat, "=", 2)
+	if len(s) == 2 {
+		return strings.ToLower(s[0]), s[1]
+	}
+	return strings.ToLower(s[0]), ""
+}
+
 // printFlags are the options for creating a printer
 type printFlags struct {
 	// allowedFormats are the possible formats
@@ -108,6 +120,10 @@ type printFlags struct {
 	noHeader bool
 	// showLabels includes labels in supported formats
 	showLabels bool
+	// outputVersion is the schema version used for structured formats
+	outputVersion string
+	// output is the global output mode
+	output OutputMode
 }
 
 // printFlagsFieldSep checks for the field separator when parsing configuration values
@@ -132,14 +148,20 @@ func newPrintFlags(meta TableMeta, config map[string]string) *printFlags {
 	pf.noHeader, _ = strconv.ParseBool(config[PrinterNoHeader])
 	pf.showLabels, _ = strconv.ParseBool(config[PrinterShowLabels])
 
+	// Default to the current output schema version
+	pf.outputVersion = config[PrinterOutputVersion]
+	if pf.outputVersion == "" {
+		pf.outputVersion = OutputVersions[0]
+	}
+
 	// Compute the list of allowed printer formats
-	outputFormat := strings.ToLower(config[PrinterOutputFormat])
+	outputFormat, _ := splitOutputFormat(config[PrinterOutputFormat])
 	allowedFormats := strings.FieldsFunc(config[PrinterAllowedFormats], printFlagsFieldSep)
 	for i := range allowedFormats {
 		allowedFormats[i] = strings.ToLower(strings.TrimSpace(allowedFormats[i]))
 	}
 	if len(allowedFormats) == 0 {
-		allowedFormats = []string{"json", "yaml", "name", "wide", "csv", ""}
+		allowedFormats = []string{"json", "yaml", "name", "wide", "csv", "csv-wide", "parquet", "jsonpath", "custom-columns", ""}
 	}
 
 	for _, allowedFormat := range allowedFormats {
@@ -150,7 +172,7 @@ func newPrintFlags(meta TableMeta, config map[string]string) *printFlags {
 
 		// Only set the output format if it is allowed
 		if outputFormat == allowedFormat {
-			pf.outputFormat = allowedFormat
+			pf.outputFormat = config[PrinterOutputFormat]
 		}
 	}
 
@@ -178,48 +200,107 @@ func (f *printFlags) addFlags(cmd *cobra.Command) {
 			break
 		}
 	}
+
+	// The output version only applies to structured formats
+	for _, allowedFormat := range f.allowedFormats {
+		if allowedFormat == "json" || allowedFormat == "yaml" {
+			cmd.Flags().StringVar(&f.outputVersion, "output-version", f.outputVersion, fmt.Sprintf("Schema `version` for structured output. One of: %s", strings.Join(OutputVersions, "|")))
+			break
+		}
+	}
 }
 
 // toPrinter generates a new printer
 func (f *printFlags) toPrinter(printer *ResourcePrinter) error {
-	outputFormat := strings.ToLower(f.outputFormat)
+	outputFormat, arg := splitOutputFormat(f.outputFormat)
 	for _, allowedFormat := range f.allowedFormats {
 		if outputFormat == allowedFormat {
 			switch outputFormat {
+			case "jsonpath":
+				p, err := newJSONPathPrinter(arg, f.outputVersion)
+				if err != nil {
+					return err
+				}
+				*printer = p
+				return nil
+			case "custom-columns":
+				p, err := newCustomColumnsPrinter(f.meta, arg, !f.noHeader)
+				if err != nil {
+					return err
+				}
+				*printer = p
+				return nil
 			case "json", "yaml":
-				*printer = &marshalPrinter{outputFormat: outputFormat}
+				if err := checkOutputVersion(f.outputVersion); err != nil {
+					return err
+				}
+				*printer = &marshalPrinter{outputFormat: outputFormat, outputVersion: f.outputVersion}
 				return nil
-			case "wide", "name", "":
+			case "parquet":
+				*printer = &parquetPrinter{meta: f.meta, showLabels: f.showLabels}
+				return nil
+			case "wide", "name", "", "csv", "csv-wide":
+				// Quiet output is always just the names, regardless of the tabular format
+				if strings.HasPrefix(outputFormat, "csv") && !f.output.Quiet {
+					*printer = &csvPrinter{meta: f.meta, headers: !f.noHeader, showLabels: f.showLabels, outputFormat: outputFormat}
+					return nil
+				}
 				p := &tablePrinter{
 					meta:         f.meta,
 					columns:      f.columns,
 					headers:      !f.noHeader,
 					showLabels:   f.showLabels,
 					outputFormat: outputFormat,
+					output:       f.output,
 				}
-				if outputFormat == "name" {
+				if outputFormat == "name" || f.output.Quiet {
 					p.columns = []string{"name"}
 					p.headers = false
 				}
 				*printer = p
 				return nil
-			case "csv":
-				*printer = &csvPrinter{meta: f.meta, headers: !f.noHeader, showLabels: f.showLabels}
-				return nil
 			}
 		}
 	}
 	return NoPrinterError{OutputFormat: f.outputFormat, AllowedFormats: f.allowedFormats}
 }
 
+// WithoutHeaders returns a copy of the supplied printer that does not print a header row, this is useful when
+// appending additional rows to previously printed output; printers which do not produce headers are returned as-is
+func WithoutHeaders(printer ResourcePrinter) ResourcePrinter {
+	switch p := printer.(type) {
+	case *tablePrinter:
+		c := *p
+		c.headers = false
+		return &c
+	case *csvPrinter:
+		c := *p
+		c.headers = false
+		return &c
+	case *customColumnsPrinter:
+		c := *p
+		c.noHeader = true
+		return &c
+	default:
+		return printer
+	}
+}
+
 // marshalPrinter is a printer that generates output using some type of generic encoding (e.g. JSON)
 type marshalPrinter struct {
 	// outputFormat is the name of the marshaller to use, JSON will be used if it is unrecognized
 	outputFormat string
+	// outputVersion is the schema version used to wrap objects which are not already versioned
+	outputVersion string
 }
 
 // PrintObj will marshal the supplied object
 func (p *marshalPrinter) PrintObj(obj interface{}, w io.Writer) error {
+	obj, err := versionObject(obj, p.outputVersion)
+	if err != nil {
+		return err
+	}
+
 	// TODO It would be really nice if we could fix the field ordering for Unstructured objects
 	if strings.ToLower(p.outputFormat) == "yaml" {
 		output, err := yaml.Marshal(obj)
@@ -233,8 +314,133 @@ func (p *marshalPrinter) PrintObj(obj interface{}, w io.Writer) error {
 	enc := json.NewEncoder(w)
 	enc.SetEscapeHTML(false)
 	enc.SetIndent("", "    ")
-	err := enc.Encode(obj)
-	return err
+	return enc.Encode(obj)
+}
+
+// jsonPathPrinter is a printer that evaluates a JSONPath template against the generic JSON representation of an object
+type jsonPathPrinter struct {
+	// jsonPath is the parsed template
+	jsonPath *jsonpath.JSONPath
+	// outputVersion is the schema version used to wrap objects which are not already versioned
+	outputVersion string
+}
+
+// newJSONPathPrinter parses the supplied JSONPath template
+func newJSONPathPrinter(tmpl, outputVersion string) (*jsonPathPrinter, error) {
+	if tmpl == "" {
+		return nil, fmt.Errorf("template format specified but no template given")
+	}
+	jp := jsonpath.New("output")
+	if err := jp.Parse(tmpl); err != nil {
+		return nil, fmt.Errorf("error parsing jsonpath %s: %w", tmpl, err)
+	}
+	return &jsonPathPrinter{jsonPath: jp, outputVersion: outputVersion}, nil
+}
+
+// PrintObj evaluates the JSONPath template against the supplied object
+func (p *jsonPathPrinter) PrintObj(obj interface{}, w io.Writer) error {
+	obj, err := versionObject(obj, p.outputVersion)
+	if err != nil {
+		return err
+	}
+
+	data, err := genericJSON(obj)
+	if err != nil {
+		return err
+	}
+	return p.jsonPath.Execute(w, data)
+}
+
+// customColumnsPrinter is a printer that generates tabular output using JSONPath expressions for each column
+type customColumnsPrinter struct {
+	// meta is used to extract the rows of list objects
+	meta TableMeta
+	// headers are the column headers
+	headers []string
+	// columns are the parsed column expressions
+	columns []*jsonpath.JSONPath
+	// noHeader suppresses the header row
+	noHeader bool
+}
+
+// newCustomColumnsPrinter parses a column specification of the form "HEADER:JSONPATH,..."
+func newCustomColumnsPrinter(meta TableMeta, spec string, headers bool) (*customColumnsPrinter, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("custom-columns format specified but no custom columns given")
+	}
+	p := &customColumnsPrinter{meta: meta, noHeader: !headers}
+	for _, col := range strings.Split(spec, ",") {
+		parts := strings.SplitN(col, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("unexpected custom-columns spec: %s, expected <header>:<json-path-expr>", col)
+		}
+
+		// Allow the same relaxed syntax as kubectl, e.g. ".number" instead of "{.number}"
+		expr := parts[1]
+		if !strings.HasPrefix(expr, "{") {
+			expr = "{" + expr + "}"
+		}
+		jp := jsonpath.New(parts[0]).AllowMissingKeys(true)
+		if err := jp.Parse(expr); err != nil {
+			return nil, fmt.Errorf("error parsing jsonpath %s: %w", parts[1], err)
+		}
+
+		p.headers = append(p.headers, parts[0])
+		p.columns = append(p.columns, jp)
+	}
+	return p, nil
+}
+
+// PrintObj generates tabular data by evaluating the column expressions against each row
+func (p *customColumnsPrinter) PrintObj(obj interface{}, w io.Writer) error {
+	rows, err := p.meta.ExtractList(obj)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	if !p.noHeader {
+		if _, err := fmt.Fprintf(tw, "%s\t\n", strings.Join(p.headers, "\t")); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]string, len(p.columns))
+	for y := range rows {
+		data, err := genericJSON(rows[y])
+		if err != nil {
+			return err
+		}
+
+		for x := range p.columns {
+			var b strings.Builder
+			if err := p.columns[x].Execute(&b, data); err != nil {
+				return err
+			}
+			buf[x] = b.String()
+			if buf[x] == "" {
+				buf[x] = "<none>"
+			}
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t\n", strings.Join(buf, "\t")); err != nil {
+			return err
+		}
+	}
+
+	return tw.Flush()
+}
+
+// genericJSON round trips an object through JSON so it can be navigated using JSONPath expressions
+func genericJSON(obj interface{}) (interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
 // tablePrinter is a printer that generates tabular output
@@ -249,6 +455,8 @@ type tablePrinter struct {
 	showLabels bool
 	// outputFormat is the format this printer is generating (used to alter defaults)
 	outputFormat string
+	// output is the global output mode
+	output OutputMode
 }
 
 // PrintObj generates the tabular data
@@ -259,7 +467,11 @@ func (p *tablePrinter) PrintObj(obj interface{}, w io.Writer) error {
 		return err
 	}
 	if len(rows) == 0 {
-		_, err = fmt.Fprintln(w, "No resources found.")
+		// Scripts should not need to distinguish the empty message from an actual resource
+		if p.output.Quiet || p.output.Porcelain {
+			return nil
+		}
+		_, err = fmt.Fprintln(w, p.output.Sprintf(MsgNoResources))
 		return err
 	}
 
@@ -271,8 +483,11 @@ func (p *tablePrinter) PrintObj(obj interface{}, w io.Writer) error {
 	}
 
 	// Allocate a tab writer and a row buffer
-	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
 	buf := make([]string, len(columns))
+	if p.output.Porcelain {
+		return p.printPorcelain(w, rows, columns, buf)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
 
 	// Print headers
 	if p.headers {
@@ -300,6 +515,31 @@ func (p *tablePrinter) PrintObj(obj interface{}, w io.Writer) error {
 	return tw.Flush()
 }
 
+// printPorcelain generates tab delimited rows without any padding
+func (p *tablePrinter) printPorcelain(w io.Writer, rows []interface{}, columns []string, buf []string) error {
+	var err error
+	if p.headers {
+		for i := range columns {
+			buf[i] = p.meta.Header(p.outputFormat, columns[i])
+		}
+		if _, err = fmt.Fprintln(w, strings.Join(buf, "\t")); err != nil {
+			return err
+		}
+	}
+
+	for y := range rows {
+		for x := range columns {
+			if buf[x], err = p.meta.ExtractValue(rows[y], columns[x]); err != nil {
+				return err
+			}
+		}
+		if _, err = fmt.Fprintln(w, strings.Join(buf, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // printRow formats a single row
 func (p *tablePrinter) printRow(w io.Writer, row []string) error {
 	if len(row) == 1 {
@@ -320,6 +560,8 @@ type csvPrinter struct {
 	headers bool
 	// showLabels determines if a column should be included for each distinct label
 	showLabels bool
+	// outputFormat is the CSV variant being generated, e.g. "csv-wide" includes additional columns
+	outputFormat string
 }
 
 // PrintObj generates the CSV data
@@ -331,7 +573,11 @@ func (p *csvPrinter) PrintObj(obj interface{}, w io.Writer) error {
 	}
 
 	// Ensure we have a list of column names
-	columns := p.meta.Columns(obj, "csv", p.showLabels)
+	outputFormat := p.outputFormat
+	if outputFormat == "" {
+		outputFormat = "csv"
+	}
+	columns := p.meta.Columns(obj, outputFormat, p.showLabels)
 
 	// Allocate a CSV writer and a record buffer
 	cw := csv.NewWriter(w)
@@ -340,7 +586,7 @@ func (p *csvPrinter) PrintObj(obj interface{}, w io.Writer) error {
 	// Print headers
 	if p.headers {
 		for i := range columns {
-			buf[i] = p.meta.Header("csv", columns[i])
+			buf[i] = p.meta.Header(outputFormat, columns[i])
 		}
 		if err = cw.Write(buf); err != nil {
 			return err
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commander
+
+import (
+	"bytes"
+	"testing"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSplitOutputFormat(t *testing.T) {
+	cases := []struct {
+		outputFormat string
+		name         string
+		arg          string
+	}{
+		{outputFormat: "", name: ""},
+		{outputFormat: "JSON", name: "json"},
+		{outputFormat: "jsonpath={.Number}", name: "jsonpath", arg: "{.Number}"},
+		{outputFormat: "custom-columns=A:.a=b", name: "custom-columns", arg: "A:.a=b"},
+	}
+	for _, c := range cases {
+		t.Run(c.outputFormat, func(t *testing.T) {
+			name, arg := splitOutputFormat(c.outputFormat)
+			assert.Equal(t, c.name, name)
+			assert.Equal(t, c.arg, arg)
+		})
+	}
+}
+
+func TestJSONPathPrinter(t *testing.T) {
+	l := &experimentsv1alpha1.TrialList{
+		Trials: []experimentsv1alpha1.TrialItem{{Number: 1}, {Number: 2}},
+	}
+
+	p, err := newJSONPathPrinter("{.kind}: {.trials[*].number}", "v1alpha1")
+	if assert.NoError(t, err) {
+		var buf bytes.Buffer
+		if assert.NoError(t, p.PrintObj(l, &buf)) {
+			assert.Equal(t, "TrialList: 1 2", buf.String())
+		}
+	}
+
+	_, err = newJSONPathPrinter("", "v1alpha1")
+	assert.Error(t, err)
+}
+
+func TestCustomColumnsPrinter(t *testing.T) {
+	l := &corev1.ConfigMapList{
+		Items: []corev1.ConfigMap{
+			{ObjectMeta: metav1.ObjectMeta{Name: "a", Labels: map[string]string{"app": "test"}}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "b"}},
+		},
+	}
+
+	p, err := newCustomColumnsPrinter(&kubePrinter{}, "NAME:.metadata.name,APP:{.metadata.labels.app}", true)
+	if assert.NoError(t, err) {
+		var buf bytes.Buffer
+		if assert.NoError(t, p.PrintObj(l, &buf)) {
+			assert.Equal(t, "NAME   APP      \na      test     \nb      <none>   \n", buf.String())
+		}
+	}
+
+	_, err = newCustomColumnsPrinter(&kubePrinter{}, "NAME", true)
+	assert.Error(t, err)
+}
+
+func TestWithoutHeaders(t *testing.T) {
+	l := &corev1.ConfigMapList{
+		Items: []corev1.ConfigMap{{ObjectMeta: metav1.ObjectMeta{Name: "a"}}},
+	}
+
+	p, err := newCustomColumnsPrinter(&kubePrinter{}, "NAME:.metadata.name", true)
+	if assert.NoError(t, err) {
+		var buf bytes.Buffer
+		if assert.NoError(t, WithoutHeaders(p).PrintObj(l, &buf)) {
+			assert.Equal(t, "a   \n", buf.String())
+		}
+
+		// The original printer must not be modified
+		buf.Reset()
+		if assert.NoError(t, p.PrintObj(l, &buf)) {
+			assert.Equal(t, "NAME   \na      \n", buf.String())
+		}
+	}
+
+	m := &marshalPrinter{outputFormat: "json"}
+	assert.Same(t, m, WithoutHeaders(m))
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commander
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// progressInterval is how frequently the progress indicator is redrawn
+	progressInterval = 100 * time.Millisecond
+	// progressWidth is the number of characters used to draw the progress bar
+	progressWidth = 30
+
+	colorReset = "\x1b[0m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	clearLine  = "\r\x1b[K"
+)
+
+// spinnerFrames are the frames used to indicate progress when the total amount of work is unknown
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// Progress displays the progress of a long running operation, nothing is displayed unless the output mode is interactive
+type Progress struct {
+	w       io.Writer
+	output  OutputMode
+	message string
+	total   int
+
+	mu      sync.Mutex
+	current int
+	frame   int
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewProgress starts displaying progress on the error stream; if the total is not positive a spinner is displayed,
+// otherwise a progress bar is used
+func (s *IOStreams) NewProgress(message string, total int) *Progress {
+	p := &Progress{w: s.ErrOut, output: s.Output, message: message, total: total}
+	if !p.output.Interactive {
+		return p
+	}
+
+	p.stop = make(chan struct{})
+	p.stopped = make(chan struct{})
+	go func() {
+		defer close(p.stopped)
+		t := time.NewTicker(progressInterval)
+		defer t.Stop()
+		for {
+			p.render()
+			select {
+			case <-p.stop:
+				return
+			case <-t.C:
+			}
+		}
+	}()
+	return p
+}
+
+// Add records additional completed work
+func (p *Progress) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current += n
+}
+
+// Done stops displaying progress and reports the outcome of the operation
+func (p *Progress) Done(err error) {
+	if p.stop == nil {
+		return
+	}
+	close(p.stop)
+	<-p.stopped
+	p.stop = nil
+
+	status := p.output.color(colorGreen, "done")
+	if err != nil {
+		status = p.output.color(colorRed, "failed")
+	}
+	_, _ = fmt.Fprintf(p.w, "%s%s... %s\n", clearLine, p.message, status)
+}
+
+// render draws the current state of the progress indicator
+func (p *Progress) render() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.total <= 0 {
+		_, _ = fmt.Fprintf(p.w, "%s%s %s", clearLine, p.message, spinnerFrames[p.frame%len(spinnerFrames)])
+		p.frame++
+		return
+	}
+
+	current := p.current
+	if current > p.total {
+		current = p.total
+	}
+	filled := progressWidth * current / p.total
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressWidth-filled)
+	_, _ = fmt.Fprintf(p.w, "%s%s [%s] %d/%d", clearLine, p.message, bar, current, p.total)
+}
+
+// color wraps the text in the supplied color escape code if colors are enabled
+func (m OutputMode) color(code, text string) string {
+	if !m.Color {
+		return text
+	}
+	return code + text + colorReset
+}
+
+// isTerminal checks to see if the supplied writer is an interactive terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commander
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	cases := []struct {
+		desc     string
+		output   OutputMode
+		total    int
+		err      error
+		contains string
+		suffix   string
+	}{
+		{
+			desc: "not interactive",
+		},
+		{
+			desc:     "spinner",
+			output:   OutputMode{Interactive: true},
+			contains: "Testing |",
+			suffix:   "Testing... done\n",
+		},
+		{
+			desc:     "bar",
+			output:   OutputMode{Interactive: true},
+			total:    2,
+			contains: "/2",
+			suffix:   "Testing... done\n",
+		},
+		{
+			desc:     "color",
+			output:   OutputMode{Interactive: true, Color: true},
+			err:      fmt.Errorf("test"),
+			contains: "Testing |",
+			suffix:   "Testing... " + colorRed + "failed" + colorReset + "\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			s := &IOStreams{ErrOut: &buf, Output: c.output}
+			p := s.NewProgress("Testing", c.total)
+			p.Add(1)
+			p.Done(c.err)
+			if c.suffix == "" {
+				assert.Empty(t, buf.String())
+				return
+			}
+			assert.Contains(t, buf.String(), c.contains)
+			assert.True(t, strings.HasSuffix(buf.String(), c.suffix), "unexpected output: %q", buf.String())
+		})
+	}
+}
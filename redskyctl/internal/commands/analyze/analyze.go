@@ -0,0 +1,43 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyze
+
+import (
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Options are the common options for analyzing experiment results
+type Options struct {
+	// Config is the Red Sky Configuration
+	Config config.Config
+}
+
+// NewCommand creates a new command for analyzing experiment results
+func NewCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analyze",
+		Short: "Analyze experiment results",
+		Long:  "Analyze the results of an experiment",
+	}
+
+	cmd.AddCommand(NewSignificanceCommand(&SignificanceOptions{Config: o.Config}))
+	cmd.AddCommand(NewCoverageCommand(&CoverageOptions{Config: o.Config}))
+	cmd.AddCommand(NewBoundsCommand(&BoundsOptions{Config: o.Config}))
+
+	return cmd
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/analysis"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/generate"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BoundsOptions is the configuration for suggesting expanded parameter bounds
+type BoundsOptions struct {
+	// Config is the Red Sky Configuration
+	Config config.Config
+	// ExperimentsAPI is used to interact with the Red Sky Experiments API
+	ExperimentsAPI experimentsv1alpha1.API
+	// Printer is the resource printer used to render the cloned experiment
+	Printer commander.ResourcePrinter
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// ExperimentName is the name of the experiment to analyze
+	ExperimentName string
+	// Filename is the experiment manifest to clone with the expanded bounds
+	Filename string
+	// Name is the name of the cloned experiment
+	Name string
+}
+
+// NewBoundsCommand creates a new command for suggesting expanded parameter bounds
+func NewBoundsCommand(o *BoundsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bounds EXPERIMENT",
+		Short: "Suggest expanded parameter bounds",
+		Long: "Check if the best trials of an experiment are clustered at the bounds of any parameter and suggest " +
+			"expanded bounds for a follow-up experiment. If the experiment manifest is supplied, a copy with the " +
+			"expanded bounds is generated.",
+
+		Args: cobra.ExactArgs(1),
+
+		Annotations: map[string]string{
+			commander.PrinterAllowedFormats: "json,yaml",
+			commander.PrinterOutputFormat:   "yaml",
+			commander.PrinterHideStatus:     "true",
+		},
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.ExperimentName = args[0]
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.bounds),
+	}
+
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", o.Filename, "File that contains the experiment manifest to clone with the expanded bounds.")
+	cmd.Flags().StringVar(&o.Name, "name", o.Name, "Name of the cloned experiment, defaults to the original name with an \"-expanded\" suffix.")
+
+	_ = cmd.MarkFlagFilename("filename", "yml", "yaml")
+
+	commander.SetKubePrinter(&o.Printer, cmd)
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *BoundsOptions) bounds(ctx context.Context) error {
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName(o.ExperimentName))
+	if err != nil {
+		return err
+	}
+
+	q := &experimentsv1alpha1.TrialListQuery{Status: []experimentsv1alpha1.TrialStatus{experimentsv1alpha1.TrialCompleted}}
+	tl, err := o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, q)
+	if err != nil {
+		return err
+	}
+
+	suggestions := analysis.SuggestBounds(analysis.ExperimentObservations(&exp, tl.Trials))
+
+	// Without a manifest to clone, just report the suggestions
+	if o.Filename == "" {
+		return o.printSuggestions(o.Out, suggestions)
+	}
+
+	if len(suggestions) == 0 {
+		return fmt.Errorf("the best trials of experiment %s are not at the bounds of any parameter", o.ExperimentName)
+	}
+	clone, err := o.clone(suggestions)
+	if err != nil {
+		return err
+	}
+
+	// The summary goes to stderr so the cloned manifest can be redirected
+	if err := o.printSuggestions(o.ErrOut, suggestions); err != nil {
+		return err
+	}
+	return o.Printer.PrintObj(clone, o.Out)
+}
+
+// printSuggestions writes a summary of the suggested bounds
+func (o *BoundsOptions) printSuggestions(out io.Writer, suggestions []analysis.BoundsSuggestion) error {
+	if len(suggestions) == 0 {
+		_, err := fmt.Fprintf(out, "The best trials of experiment %s are not at the bounds of any parameter\n", o.ExperimentName)
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PARAMETER\tBOUNDS\tSUGGESTED\tAT MIN\tAT MAX")
+	for _, s := range suggestions {
+		_, _ = fmt.Fprintf(w, "%s\t[%g, %g]\t[%g, %g]\t%d/%d\t%d/%d\n", s.Parameter, s.Min, s.Max, s.SuggestedMin, s.SuggestedMax,
+			s.AtLowerBound, s.BestTrials, s.AtUpperBound, s.BestTrials)
+	}
+	return w.Flush()
+}
+
+// clone reads the experiment manifest and returns a copy with the suggested bounds applied
+func (o *BoundsOptions) clone(suggestions []analysis.BoundsSuggestion) (*redskyv1beta1.Experiment, error) {
+	experimentList := &redskyv1beta1.ExperimentList{}
+	if err := generate.ReadExperiments(o.Filename, o.In, experimentList); err != nil {
+		return nil, err
+	}
+	if len(experimentList.Items) != 1 {
+		return nil, fmt.Errorf("cloning requires a single experiment as input")
+	}
+	in := &experimentList.Items[0]
+
+	// Only keep the identifying metadata, the clone is a new experiment
+	out := &redskyv1beta1.Experiment{}
+	out.Name = o.Name
+	if out.Name == "" {
+		out.Name = in.Name + "-expanded"
+	}
+	out.Namespace = in.Namespace
+	out.Labels = in.Labels
+	for k, v := range in.Annotations {
+		switch k {
+		case redskyv1beta1.AnnotationExperimentURL,
+			redskyv1beta1.AnnotationNextTrialURL,
+			redskyv1beta1.AnnotationReportTrialURL,
+			redskyv1beta1.AnnotationOptimizationChecksum,
+			corev1.LastAppliedConfigAnnotation:
+		default:
+			metav1.SetMetaDataAnnotation(&out.ObjectMeta, k, v)
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+
+	for _, s := range suggestions {
+		for i := range out.Spec.Parameters {
+			p := &out.Spec.Parameters[i]
+			if p.Name == s.Parameter {
+				p.Min, p.Max = redskyv1beta1.NewParameterBound(int64(math.Floor(s.SuggestedMin))), redskyv1beta1.NewParameterBound(int64(math.Ceil(s.SuggestedMax)))
+			}
+		}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyze
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redskyops/redskyops-controller/internal/analysis"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// CoverageOptions is the configuration for describing the explored parameter space
+type CoverageOptions struct {
+	// Config is the Red Sky Configuration
+	Config config.Config
+	// ExperimentsAPI is used to interact with the Red Sky Experiments API
+	ExperimentsAPI experimentsv1alpha1.API
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// ExperimentName is the name of the experiment to describe
+	ExperimentName string
+	// Bins is the number of histogram bins used for numeric parameters
+	Bins int
+	// Completed restricts the coverage to completed trials
+	Completed bool
+}
+
+// NewCoverageCommand creates a new command for describing the explored parameter space
+func NewCoverageCommand(o *CoverageOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "coverage EXPERIMENT",
+		Short: "Describe parameter coverage",
+		Long: "Emit the parameter space of an experiment along with statistics describing how much of each parameter " +
+			"was explored by the trials, as JSON. Low coverage may indicate bounds the optimizer never approached.",
+
+		Args: cobra.ExactArgs(1),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.ExperimentName = args[0]
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.coverage),
+	}
+
+	cmd.Flags().IntVar(&o.Bins, "bins", analysis.DefaultCoverageBins, "Number of histogram `bins` for numeric parameters.")
+	cmd.Flags().BoolVar(&o.Completed, "completed", false, "Only consider completed trials.")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *CoverageOptions) coverage(ctx context.Context) error {
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName(o.ExperimentName))
+	if err != nil {
+		return err
+	}
+
+	q := &experimentsv1alpha1.TrialListQuery{}
+	if o.Completed {
+		q.Status = []experimentsv1alpha1.TrialStatus{experimentsv1alpha1.TrialCompleted}
+	}
+	tl, err := o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, q)
+	if err != nil {
+		return err
+	}
+
+	c := analysis.ExperimentCoverage(&exp, tl.Trials, o.Bins)
+	c.Experiment = o.ExperimentName
+
+	enc := json.NewEncoder(o.Out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
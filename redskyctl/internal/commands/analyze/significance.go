@@ -0,0 +1,111 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/redskyops/redskyops-controller/internal/analysis"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// SignificanceOptions is the configuration for comparing two trials
+type SignificanceOptions struct {
+	// Config is the Red Sky Configuration
+	Config config.Config
+	// ExperimentsAPI is used to interact with the Red Sky Experiments API
+	ExperimentsAPI experimentsv1alpha1.API
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// ExperimentName is the name of the experiment the trials belong to
+	ExperimentName string
+	// TrialA is the number of the first trial to compare
+	TrialA int64
+	// TrialB is the number of the second trial to compare
+	TrialB int64
+	// Metric is the name of the metric to compare, defaults to the only metric of the experiment
+	Metric string
+	// Test is the statistical test to perform
+	Test string
+	// Alpha is the significance level
+	Alpha float64
+}
+
+// NewSignificanceCommand creates a new command for comparing two trials
+func NewSignificanceCommand(o *SignificanceOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "significance EXPERIMENT TRIAL_A TRIAL_B",
+		Short: "Compare two trials",
+		Long: "Test if the difference in a metric between two trials is statistically significant. The measurements of " +
+			"each trial are taken from the completed trials with identical assignments, use `suggest` to replicate a trial.",
+
+		Args: cobra.ExactArgs(3),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			if err := o.Complete(args); err != nil {
+				return err
+			}
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.significance),
+	}
+
+	cmd.Flags().StringVar(&o.Metric, "metric", o.Metric, "Name of the `metric` to compare (defaults to the only metric).")
+	cmd.Flags().StringVar(&o.Test, "test", string(analysis.WelchTTest), "Statistical test to perform, one of: t-test|mann-whitney.")
+	cmd.Flags().Float64Var(&o.Alpha, "alpha", 0.05, "Significance level.")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+// Complete fills in the trial numbers from the arguments
+func (o *SignificanceOptions) Complete(args []string) error {
+	var err error
+	o.ExperimentName = args[0]
+	if o.TrialA, err = strconv.ParseInt(args[1], 10, 64); err != nil {
+		return fmt.Errorf("invalid trial number: %s", args[1])
+	}
+	if o.TrialB, err = strconv.ParseInt(args[2], 10, 64); err != nil {
+		return fmt.Errorf("invalid trial number: %s", args[2])
+	}
+	return nil
+}
+
+func (o *SignificanceOptions) significance(ctx context.Context) error {
+	s, err := analysis.CompareTrials(ctx, o.ExperimentsAPI, o.ExperimentName, o.TrialA, o.TrialB, o.Metric, analysis.Test(o.Test), o.Alpha)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(o.Out, "Test:        %s\n", s.Test)
+	_, _ = fmt.Fprintf(o.Out, "Means:       %g (%d), %g (%d)\n", s.MeanA, o.TrialA, s.MeanB, o.TrialB)
+	_, _ = fmt.Fprintf(o.Out, "Statistic:   %.4f\n", s.Statistic)
+	_, _ = fmt.Fprintf(o.Out, "P-value:     %.4f\n", s.PValue)
+	if s.Significant {
+		_, _ = fmt.Fprintf(o.Out, "The difference is significant at the %g level\n", s.Alpha)
+	} else {
+		_, _ = fmt.Fprintf(o.Out, "The difference is not significant at the %g level\n", s.Alpha)
+	}
+	return nil
+}
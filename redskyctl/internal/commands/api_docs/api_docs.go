@@ -0,0 +1,70 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api_docs
+
+import (
+	"fmt"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// Options is the configuration for printing the API documentation
+type Options struct {
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// OutputFormat is the format used to print the OpenAPI document
+	OutputFormat string
+}
+
+// NewCommand creates a new command for printing the API documentation
+func NewCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api-docs",
+		Short: "Print the Red Sky API specification",
+		Long:  "Print the OpenAPI specification of the Red Sky API",
+
+		PreRun: commander.StreamsPreRun(&o.IOStreams),
+		RunE:   func(cmd *cobra.Command, _ []string) error { return o.apiDocs() },
+	}
+
+	cmd.Flags().StringVarP(&o.OutputFormat, "output", "o", "json", "Output `format`. One of: json|yaml")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *Options) apiDocs() error {
+	doc := []byte(experimentsv1alpha1.OpenAPIDocument)
+
+	switch o.OutputFormat {
+	case "json", "":
+	case "yaml":
+		var err error
+		if doc, err = yaml.JSONToYAML(doc); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown output format: %s", o.OutputFormat)
+	}
+
+	_, err := o.Out.Write(doc)
+	return err
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"strings"
+	"time"
 
 	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	"github.com/redskyops/redskyops-controller/internal/template"
@@ -102,6 +103,7 @@ func checkExperiment(lint Linter, experiment *redskyv1beta1.Experiment) {
 	checkMetrics(lint.For("spec", "metrics"), experiment.Spec.Metrics)
 	checkPatches(lint.For("spec", "patches"), experiment.Spec.Patches)
 	checkTrialTemplate(lint.For("spec", "template"), &experiment.Spec.TrialTemplate)
+	checkScaling(lint.For("spec", "metrics"), experiment)
 
 	// TODO Some checks are higher level and need a combination of pieces: e.g. selector/template matching
 
@@ -210,6 +212,33 @@ func checkPatch(lint Linter, patch *redskyv1beta1.PatchTemplate) {
 
 }
 
+// scalingWindow is the default amount of time a horizontal pod autoscaler waits before scaling down
+const scalingWindow = 5 * time.Minute
+
+func checkScaling(lint Linter, experiment *redskyv1beta1.Experiment) {
+
+	var autoscaled bool
+	for i := range experiment.Spec.Patches {
+		if ref := experiment.Spec.Patches[i].TargetRef; ref != nil && ref.Kind == "HorizontalPodAutoscaler" {
+			autoscaled = true
+		}
+	}
+	if !autoscaled {
+		return
+	}
+
+	// Measurements shorter than the scale down stabilization window cannot observe the autoscaler settling
+	for i := range experiment.Spec.Metrics {
+		m := &experiment.Spec.Metrics[i]
+		if m.Window != nil && m.Window.Duration < scalingWindow {
+			lint.For(i).Warning().
+				WithDescription("Autoscaling experiments need measurements long enough to observe scaling behavior").
+				Failed("window", fmt.Errorf("%s is shorter than the %s autoscaler stabilization window", m.Window.Duration, scalingWindow))
+		}
+	}
+
+}
+
 func checkTrialTemplate(lint Linter, template *redskyv1beta1.TrialTemplateSpec) {
 	checkTrial(lint.For("spec"), &template.Spec)
 }
@@ -23,6 +23,7 @@ import (
 	"io"
 	"math/rand"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/redskyops/redskyops-controller/internal/config"
@@ -43,6 +44,7 @@ type ServerOptions struct {
 	Name           string
 	ParameterCount int
 	MetricCount    int
+	Seed           int64
 	AllowInvalid   bool
 	ReportFailure  bool
 	DryRun         bool
@@ -67,6 +69,7 @@ func NewServerCommand(o *ServerOptions) *cobra.Command {
 
 	cmd.Flags().IntVar(&o.ParameterCount, "parameters", o.ParameterCount, "Specify the number of experiment parameters to generate (1 - 20).")
 	cmd.Flags().IntVar(&o.MetricCount, "metrics", o.MetricCount, "Specify the number of experiment metrics to generate (1 or 2).")
+	cmd.Flags().Int64Var(&o.Seed, "seed", o.Seed, "Specify the optimizer random seed (0 to let the server decide).")
 	cmd.Flags().BoolVar(&o.AllowInvalid, "invalid", o.AllowInvalid, "Skip client side validity checks (server enforcement).")
 	cmd.Flags().BoolVar(&o.ReportFailure, "fail", o.ReportFailure, "Report an experiment failure instead of generated values.")
 	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun, "Generate experiment JSON to stdout.")
@@ -124,7 +127,7 @@ func (o *ServerOptions) checkServer() error {
 			n = getRandomName(i)
 			exp, err = o.ExperimentsAPI.CreateExperiment(context.TODO(), experimentsv1alpha1.NewExperimentName(n), *e)
 			if err != nil {
-				if aerr, ok := err.(*experimentsv1alpha1.Error); ok && aerr.Type == experimentsv1alpha1.ErrExperimentNameConflict {
+				if experimentsv1alpha1.IsErrorType(err, experimentsv1alpha1.ErrExperimentNameConflict) {
 					continue
 				}
 			}
@@ -145,14 +148,16 @@ func (o *ServerOptions) checkServer() error {
 
 	// Get the next trial assignments
 	var t experimentsv1alpha1.TrialAssignments
+	progress := o.NewProgress("Waiting for trial assignments", 0)
 	for i := 0; i < 5; i++ {
 		t, err = o.ExperimentsAPI.NextTrial(context.TODO(), exp.NextTrialURL)
-		if aerr, ok := err.(*experimentsv1alpha1.Error); ok && aerr.Type == experimentsv1alpha1.ErrTrialUnavailable {
+		if aerr, ok := experimentsv1alpha1.AsError(err); ok && aerr.Type == experimentsv1alpha1.ErrTrialUnavailable {
 			time.Sleep(aerr.RetryAfter)
 			continue
 		}
 		break
 	}
+	progress.Done(err)
 	if err != nil {
 		return err
 	}
@@ -192,7 +197,12 @@ func doDryRun(out io.Writer, name string, experiment *experimentsv1alpha1.Experi
 func generateExperiment(o *ServerOptions) *experimentsv1alpha1.Experiment {
 	e := &experimentsv1alpha1.Experiment{}
 
-	// TODO Optimization?
+	if o.Seed != 0 {
+		e.Optimization = append(e.Optimization, experimentsv1alpha1.Optimization{
+			Name:  experimentsv1alpha1.OptimizationSeed,
+			Value: strconv.FormatInt(o.Seed, 10),
+		})
+	}
 
 	used := make(map[string]bool, o.ParameterCount+o.MetricCount)
 
@@ -263,7 +273,20 @@ func checkServerExperiment(name string, original, created *experimentsv1alpha1.E
 		return fmt.Errorf("server did not return a trials link")
 	}
 
-	// TODO Optimization
+	for _, oo := range original.Optimization {
+		if oo.Name != experimentsv1alpha1.OptimizationSeed {
+			continue
+		}
+		var seed string
+		for _, o := range created.Optimization {
+			if o.Name == oo.Name {
+				seed = o.Value
+			}
+		}
+		if seed != oo.Value {
+			return fmt.Errorf("server returned a different seed: %s (expected %s)", seed, oo.Value)
+		}
+	}
 
 	if len(created.Parameters) != len(original.Parameters) {
 		return fmt.Errorf("server returned a different number of parameters: %d (expected %d)", len(created.Parameters), len(original.Parameters))
@@ -316,6 +339,14 @@ func checkTrialAssignments(exp *experimentsv1alpha1.Experiment, t *experimentsv1
 	}
 	for _, a := range t.Assignments {
 		if p, ok := params[a.ParameterName]; ok {
+			// Categorical assignments must be one of the parameter values
+			if p.Type == experimentsv1alpha1.ParameterTypeCategorical {
+				if !containsValue(p.Values, a.Value.String()) {
+					return fmt.Errorf("server returned unknown categorical assignment: %s = %s (expected one of %s)", a.ParameterName, a.Value, strings.Join(p.Values, ", "))
+				}
+				continue
+			}
+
 			// Check bounds using floating point arithmetic
 			v, err := a.Value.Float64()
 			if err != nil {
@@ -339,3 +370,13 @@ func checkTrialAssignments(exp *experimentsv1alpha1.Experiment, t *experimentsv1
 
 	return nil
 }
+
+// containsValue checks for the value in a list of categorical values
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/redskyops/redskyops-controller/internal/version"
+)
+
+// ReleasePublicKey is the base64 encoded (DER) public key used to sign release artifacts (cosign compatible)
+var ReleasePublicKey = ""
+
+const (
+	// ChecksumsAssetName is the name of the release asset containing the artifact checksums
+	ChecksumsAssetName = "checksums.txt"
+	// SignatureAssetSuffix is the suffix added to the name of a signed asset to get the name of the signature asset,
+	// it must not collide with the (empty) ".sig" assets produced by the notarization step
+	SignatureAssetSuffix = ".cosign.sig"
+)
+
+// VerifyBlob verifies the base64 encoded signature of the supplied data using the release public key
+func VerifyBlob(data, sig []byte) error {
+	if ReleasePublicKey == "" {
+		return fmt.Errorf("no release public key is available, signatures cannot be verified")
+	}
+
+	der, err := base64.StdEncoding.DecodeString(ReleasePublicKey)
+	if err != nil {
+		return err
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return err
+	}
+	key, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("unsupported release public key type %T", pub)
+	}
+
+	rawSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return err
+	}
+	var rs struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(rawSig, &rs); err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(data)
+	if !ecdsa.Verify(key, digest[:], rs.R, rs.S) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// VerifiedChecksums returns the checksums of the release artifacts, keyed by the artifact name, after verifying
+// the signature of the checksum file
+func (r *Release) VerifiedChecksums(ctx context.Context) (map[string]string, error) {
+	sum := r.AssetByName(ChecksumsAssetName)
+	sig := r.AssetByName(ChecksumsAssetName + SignatureAssetSuffix)
+	if sum == nil || sig == nil {
+		return nil, fmt.Errorf("release %s is not signed", r.TagName)
+	}
+
+	data, err := getBytes(ctx, sum.BrowserDownloadURL)
+	if err != nil {
+		return nil, err
+	}
+	sigData, err := getBytes(ctx, sig.BrowserDownloadURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := VerifyBlob(data, sigData); err != nil {
+		return nil, fmt.Errorf("unable to verify %s: %w", ChecksumsAssetName, err)
+	}
+
+	return parseChecksums(data), nil
+}
+
+// parseChecksums parses the output of `sha256sum`
+func parseChecksums(data []byte) map[string]string {
+	checksums := make(map[string]string)
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		f := strings.Fields(s.Text())
+		if len(f) == 2 {
+			checksums[strings.TrimPrefix(f[1], "*")] = f[0]
+		}
+	}
+	return checksums
+}
+
+func getBytes(ctx context.Context, url string) ([]byte, error) {
+	client := &http.Client{Transport: version.UserAgent("RedSkyOps", "", nil)}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &APIError{StatusCode: resp.StatusCode}
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyBlob(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+
+	data := []byte("0123456789abcdef  redskyctl-linux-amd64.tar.gz\n")
+	digest := sha256.Sum256(data)
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	rawSig, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	require.NoError(t, err)
+	sig := []byte(base64.StdEncoding.EncodeToString(rawSig))
+
+	defer func(pk string) { ReleasePublicKey = pk }(ReleasePublicKey)
+
+	ReleasePublicKey = ""
+	assert.EqualError(t, VerifyBlob(data, sig), "no release public key is available, signatures cannot be verified")
+
+	ReleasePublicKey = base64.StdEncoding.EncodeToString(der)
+	assert.NoError(t, VerifyBlob(data, sig))
+	assert.EqualError(t, VerifyBlob([]byte("tampered"), sig), "invalid signature")
+
+	assert.Equal(t, map[string]string{"redskyctl-linux-amd64.tar.gz": "0123456789abcdef"}, parseChecksums(data))
+}
@@ -57,7 +57,7 @@ func NewVersionCommand(o *VersionOptions) *cobra.Command {
 
 func (o *VersionOptions) checkVersion(ctx context.Context) error {
 	var releases ReleaseList
-	if err := getJSON(ctx, GitHubReleasesURL, &releases); err != nil {
+	if err := GetJSON(ctx, GitHubReleasesURL, &releases); err != nil {
 		return err
 	}
 
@@ -90,7 +90,8 @@ func (o *VersionOptions) checkVersion(ctx context.Context) error {
 	return nil
 }
 
-func getJSON(ctx context.Context, url string, obj interface{}) error {
+// GetJSON fetches the supplied URL and unmarshals the JSON response body
+func GetJSON(ctx context.Context, url string, obj interface{}) error {
 	client := &http.Client{Transport: version.UserAgent("RedSkyOps", "", nil)}
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -19,24 +19,39 @@ package commands
 import (
 	"os"
 	"strings"
+	"time"
 
 	"github.com/redskyops/redskyops-controller/internal/config"
+	"github.com/redskyops/redskyops-controller/internal/tlspolicy"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/analyze"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/api_docs"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/authorize_cluster"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/check"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/completion"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/configure"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/debug"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/dev"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/docs"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/experiments"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/export"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/generate"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/grant_permissions"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/importer"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/initialize"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/kustomize"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/login"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/logs"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/plot"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/query"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/queue"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/recipes"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/reset"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/results"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/revoke"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/update"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/version"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/views"
 	"github.com/spf13/cobra"
 )
 
@@ -55,28 +70,52 @@ func NewRedskyctlCommand() *cobra.Command {
 	cfg := &config.RedSkyConfig{}
 	commander.ConfigGlobals(cfg, rootCmd)
 
+	// Restrict outbound TLS connections
+	commander.TLSGlobals(&tlspolicy.Policy{}, rootCmd)
+
+	// Control the format of human-facing output
+	commander.OutputGlobals(rootCmd)
+
 	// Establish OAuth client identity
 	cfg.ClientIdentity = authorizationIdentity
 
 	// Add the sub-commands
+	rootCmd.AddCommand(analyze.NewCommand(&analyze.Options{Config: cfg}))
+	rootCmd.AddCommand(api_docs.NewCommand(&api_docs.Options{}))
 	rootCmd.AddCommand(authorize_cluster.NewCommand(&authorize_cluster.Options{GeneratorOptions: authorize_cluster.GeneratorOptions{Config: cfg}}))
 	rootCmd.AddCommand(check.NewCommand(&check.Options{Config: cfg}))
 	rootCmd.AddCommand(completion.NewCommand(&completion.Options{}))
 	rootCmd.AddCommand(configure.NewCommand(&configure.Options{Config: cfg}))
+	rootCmd.AddCommand(debug.NewCommand(&debug.Options{Config: cfg}))
+	rootCmd.AddCommand(dev.NewCommand(&dev.Options{}))
 	rootCmd.AddCommand(docs.NewCommand(&docs.Options{}))
+	rootCmd.AddCommand(experiments.NewAbortCommand(&experiments.AbortOptions{Options: experiments.Options{Config: cfg}}))
 	rootCmd.AddCommand(experiments.NewDeleteCommand(&experiments.DeleteOptions{Options: experiments.Options{Config: cfg}}))
-	rootCmd.AddCommand(experiments.NewGetCommand(&experiments.GetOptions{Options: experiments.Options{Config: cfg}, ChunkSize: 500}))
+	rootCmd.AddCommand(experiments.NewDescribeCommand(&experiments.DescribeOptions{Options: experiments.Options{Config: cfg}}))
+	rootCmd.AddCommand(experiments.NewGetCommand(&experiments.GetOptions{Options: experiments.Options{Config: cfg}, ChunkSize: 500, WatchInterval: 10 * time.Second}))
 	rootCmd.AddCommand(experiments.NewLabelCommand(&experiments.LabelOptions{Options: experiments.Options{Config: cfg}}))
+	rootCmd.AddCommand(experiments.NewStatusCommand(&experiments.StatusOptions{Options: experiments.Options{Config: cfg}}))
 	rootCmd.AddCommand(experiments.NewSuggestCommand(&experiments.SuggestOptions{Options: experiments.Options{Config: cfg}}))
+	rootCmd.AddCommand(export.NewBestCommand(&export.BestOptions{Options: export.Options{Config: cfg}}))
+	rootCmd.AddCommand(export.NewCommand(&export.Options{Config: cfg}))
+	rootCmd.AddCommand(export.NewReportCommand(&export.ReportOptions{Options: export.Options{Config: cfg}}))
 	rootCmd.AddCommand(generate.NewCommand(&generate.Options{Config: cfg}))
 	rootCmd.AddCommand(grant_permissions.NewCommand(&grant_permissions.Options{GeneratorOptions: grant_permissions.GeneratorOptions{Config: cfg}}))
+	rootCmd.AddCommand(importer.NewCommand(&importer.Options{Config: cfg}))
 	rootCmd.AddCommand(initialize.NewCommand(&initialize.Options{GeneratorOptions: initialize.GeneratorOptions{Config: cfg}, IncludeBootstrapRole: true}))
-	rootCmd.AddCommand(kustomize.NewCommand())
+	rootCmd.AddCommand(kustomize.NewCommand(&kustomize.Options{Config: cfg}))
 	rootCmd.AddCommand(login.NewCommand(&login.Options{Config: cfg}))
+	rootCmd.AddCommand(logs.NewCommand(&logs.Options{Config: cfg}))
+	rootCmd.AddCommand(plot.NewCommand(&plot.Options{Config: cfg}))
+	rootCmd.AddCommand(query.NewCommand(&query.Options{Config: cfg}))
+	rootCmd.AddCommand(queue.NewCommand(&queue.Options{Config: cfg}))
+	rootCmd.AddCommand(recipes.NewCommand(&recipes.Options{Config: cfg}))
 	rootCmd.AddCommand(reset.NewCommand(&reset.Options{Config: cfg}))
 	rootCmd.AddCommand(results.NewCommand(&results.Options{Config: cfg}))
 	rootCmd.AddCommand(revoke.NewCommand(&revoke.Options{Config: cfg}))
+	rootCmd.AddCommand(update.NewCommand(&update.Options{}))
 	rootCmd.AddCommand(version.NewCommand(&version.Options{Config: cfg}))
+	rootCmd.AddCommand(views.NewCommand(&views.Options{Config: cfg}))
 
 	// TODO Add 'backup' and 'restore' maintenance commands ('maint' subcommands?)
 	// TODO We need helpers for doing a "dry run" on patches to make configuration easier
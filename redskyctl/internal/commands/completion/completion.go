@@ -32,9 +32,14 @@ func NewCommand(o *Options) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "completion SHELL",
 		Short: "Output shell completion code",
-		Long:  "Output shell completion code which can be evaluated to provide interactive completion of commands.",
+		Long: "Output shell completion code which can be evaluated to provide interactive completion of commands. " +
+			"Experiment and trial names are completed using the remote server, falling back to the cluster.",
 
-		Example: `# Load the completion code for zsh into the current shell
+		Example: `# Load the completion code for bash into the current shell
+source <(redskyctl completion bash)
+# Load the completion code for fish into the current shell
+redskyctl completion fish | source
+# Load the completion code for zsh into the current shell
 source <(redskyctl completion zsh)
 # Set the completion code for zsh to autoload (assuming '$ZSH/completions' is part of 'fpath')
 redskyctl completion zsh > $ZSH/completions/_redskyctl`,
@@ -53,11 +58,11 @@ redskyctl completion zsh > $ZSH/completions/_redskyctl`,
 func (o *Options) completion(cmd *cobra.Command) error {
 	switch o.Shell {
 	case "bash":
-		return cmd.GenBashCompletion(cmd.OutOrStdout())
+		return cmd.Root().GenBashCompletion(cmd.OutOrStdout())
 	case "fish":
-		return cmd.GenFishCompletion(cmd.OutOrStdout(), true)
+		return cmd.Root().GenFishCompletion(cmd.OutOrStdout(), true)
 	case "zsh":
-		return cmd.GenZshCompletion(cmd.OutOrStdout())
+		return cmd.Root().GenZshCompletion(cmd.OutOrStdout())
 	default:
 		return fmt.Errorf("completion is not implemented for %s", o.Shell)
 	}
@@ -0,0 +1,352 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/config"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	"github.com/redskyops/redskyops-controller/internal/version"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// redactedConfigKeys are the configuration keys whose values are never included in a bundle
+var redactedConfigKeys = map[string]bool{
+	"access_token":              true,
+	"refresh_token":             true,
+	"bearer_token":              true,
+	"client_secret":             true,
+	"hmac_secret":               true,
+	"registration_access_token": true,
+}
+
+// CollectOptions is the configuration for collecting a diagnostic bundle
+type CollectOptions struct {
+	// Config is the Red Sky Configuration
+	Config *config.RedSkyConfig
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// Filename is the name of the bundle to create
+	Filename string
+	// Tail is the number of lines to collect from the end of the manager logs
+	Tail int64
+}
+
+// NewCollectCommand creates a new command for collecting a diagnostic bundle
+func NewCollectCommand(o *CollectOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "collect",
+		Short: "Collect diagnostic information",
+		Long:  "Collect the controller logs, resources, events, versions and configuration into a bundle for a bug report",
+
+		Args: cobra.NoArgs,
+
+		PreRun: commander.StreamsPreRun(&o.IOStreams),
+		RunE:   commander.WithContextE(o.collect),
+	}
+
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", "", "The `file` to write the bundle to, defaults to a timestamped name in the current directory.")
+	cmd.Flags().Int64Var(&o.Tail, "tail", 10000, "Number of `lines` to collect from the end of the manager logs.")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+// collector produces the contents of a single file in the bundle
+type collector struct {
+	name string
+	fn   func(context.Context) ([]byte, error)
+}
+
+func (o *CollectOptions) collect(ctx context.Context) error {
+	now := time.Now().UTC()
+	if o.Filename == "" {
+		o.Filename = fmt.Sprintf("redsky-debug-%s.tar.gz", now.Format("20060102T150405Z"))
+	}
+
+	f, err := os.Create(o.Filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	collectors := []collector{
+		{name: "version.json", fn: o.versions},
+		{name: "config.yaml", fn: o.redactedConfig},
+		{name: "manager.log", fn: o.managerLogs},
+		{name: "experiments.json", fn: o.resources("experiments.v1beta1.redskyops.dev")},
+		{name: "trials.json", fn: o.resources("trials.v1beta1.redskyops.dev")},
+		{name: "experimentschedules.json", fn: o.resources("experimentschedules.v1beta1.redskyops.dev")},
+		{name: "events.txt", fn: o.events},
+	}
+
+	// Failing to collect one file should not prevent the others from being collected
+	progress := o.NewProgress("Collecting diagnostic information", len(collectors))
+	var failures []string
+	for _, c := range collectors {
+		data, err := c.fn(ctx)
+		progress.Add(1)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", c.name, err))
+			continue
+		}
+		if err := writeFile(tw, c.name, now, data); err != nil {
+			progress.Done(err)
+			return err
+		}
+	}
+	if len(failures) > 0 {
+		if err := writeFile(tw, "errors.txt", now, []byte(strings.Join(failures, "\n")+"\n")); err != nil {
+			progress.Done(err)
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		progress.Done(err)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		progress.Done(err)
+		return err
+	}
+	progress.Done(nil)
+
+	return o.Output.PrintAction(o.Out, "bundle", o.Filename, commander.MsgCreated)
+}
+
+// versions returns the client, Kubernetes and controller versions
+func (o *CollectOptions) versions(ctx context.Context) ([]byte, error) {
+	v := map[string]interface{}{"redskyctl": version.GetInfo()}
+
+	if data, err := o.kubectl(ctx, "version", "--output", "json"); err == nil {
+		v["kubectl"] = json.RawMessage(data)
+	} else {
+		v["kubectl"] = err.Error()
+	}
+
+	if ns, err := o.Config.SystemNamespace(); err == nil {
+		if pod, err := o.kubectl(ctx, "--namespace", ns, "get", "pods", "--selector", "control-plane=controller-manager", "--output", "name"); err == nil && len(bytes.TrimSpace(pod)) > 0 {
+			podName := strings.Fields(string(pod))[0]
+			if data, err := o.kubectl(ctx, "--namespace", ns, "exec", "--container", "manager", podName, "/manager", "version"); err == nil {
+				v["controller"] = json.RawMessage(data)
+			} else {
+				v["controller"] = err.Error()
+			}
+		}
+	}
+
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// redactedConfig returns the effective configuration with all credentials removed
+func (o *CollectOptions) redactedConfig(context.Context) ([]byte, error) {
+	mini, err := config.Minify(o.Config.Reader())
+	if err != nil {
+		return nil, err
+	}
+
+	// Round trip through JSON so we can redact generically
+	data, err := json.Marshal(mini)
+	if err != nil {
+		return nil, err
+	}
+	var cfg interface{}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	redactConfig(cfg)
+	return yaml.Marshal(cfg)
+}
+
+// managerLogs returns the logs of the controller manager
+func (o *CollectOptions) managerLogs(ctx context.Context) ([]byte, error) {
+	ns, err := o.Config.SystemNamespace()
+	if err != nil {
+		return nil, err
+	}
+	return o.kubectl(ctx, "--namespace", ns, "logs", "--selector", "control-plane=controller-manager", "--all-containers", "--prefix", "--tail", fmt.Sprintf("%d", o.Tail))
+}
+
+// resources returns a function that collects the sanitized resources of the specified type from all namespaces
+func (o *CollectOptions) resources(resource string) func(context.Context) ([]byte, error) {
+	return func(ctx context.Context) ([]byte, error) {
+		data, err := o.kubectl(ctx, "get", resource, "--all-namespaces", "--output", "json")
+		if err != nil {
+			return nil, err
+		}
+		var list map[string]interface{}
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		if items, ok := list["items"].([]interface{}); ok {
+			for i := range items {
+				if obj, ok := items[i].(map[string]interface{}); ok {
+					sanitize(obj)
+				}
+			}
+		}
+		return json.MarshalIndent(list, "", "  ")
+	}
+}
+
+// events returns the recent events from the system namespace and for the Red Sky resources
+func (o *CollectOptions) events(ctx context.Context) ([]byte, error) {
+	var buf bytes.Buffer
+	if ns, err := o.Config.SystemNamespace(); err == nil {
+		data, err := o.kubectl(ctx, "--namespace", ns, "get", "events", "--sort-by", ".lastTimestamp")
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	for _, kind := range []string{"Experiment", "Trial"} {
+		data, err := o.kubectl(ctx, "get", "events", "--all-namespaces", "--field-selector", "involvedObject.kind="+kind, "--sort-by", ".lastTimestamp")
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+// kubectl runs kubectl and returns the output, the standard error output is included in the error
+func (o *CollectOptions) kubectl(ctx context.Context, args ...string) ([]byte, error) {
+	cmd, err := o.Config.Kubectl(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	data, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%v: %s", err, msg)
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// writeFile adds a file to the bundle
+func writeFile(tw *tar.Writer, name string, modTime time.Time, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: modTime}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// redactConfig recursively replaces the values of credential keys and controller environment variables
+func redactConfig(v interface{}) {
+	redactEnv(v)
+	redactCredentials(v)
+}
+
+// redactCredentials recursively replaces the values of credential keys
+func redactCredentials(v interface{}) {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k := range vv {
+			if redactedConfigKeys[k] {
+				vv[k] = trial.Redacted
+				continue
+			}
+			redactCredentials(vv[k])
+		}
+	case []interface{}:
+		for i := range vv {
+			redactCredentials(vv[i])
+		}
+	}
+}
+
+// sanitize removes potentially sensitive information from a resource
+func sanitize(obj map[string]interface{}) {
+	if md, ok := obj["metadata"].(map[string]interface{}); ok {
+		delete(md, "managedFields")
+		if annotations, ok := md["annotations"].(map[string]interface{}); ok {
+			delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+
+			// Redact the sensitive assignments of trials
+			if s, ok := annotations[redskyv1beta1.AnnotationSensitiveParameters].(string); ok {
+				redactAssignments(obj, strings.Split(s, ","))
+			}
+		}
+	}
+
+	redactEnv(obj)
+}
+
+// redactAssignments replaces the values of the named trial assignments
+func redactAssignments(obj map[string]interface{}, names []string) {
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	assignments, _ := spec["assignments"].([]interface{})
+	for i := range assignments {
+		a, ok := assignments[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, n := range names {
+			if a["name"] == strings.TrimSpace(n) {
+				a["value"] = trial.Redacted
+			}
+		}
+	}
+}
+
+// redactEnv recursively replaces the values of container environment variables
+func redactEnv(v interface{}) {
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k := range vv {
+			if env, ok := vv[k].([]interface{}); ok && k == "env" {
+				for i := range env {
+					if e, ok := env[i].(map[string]interface{}); ok {
+						if _, ok := e["value"]; ok {
+							e["value"] = trial.Redacted
+						}
+					}
+				}
+				continue
+			}
+			redactEnv(vv[k])
+		}
+	case []interface{}:
+		for i := range vv {
+			redactEnv(vv[i])
+		}
+	}
+}
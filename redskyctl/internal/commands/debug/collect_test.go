@@ -0,0 +1,117 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitize(t *testing.T) {
+	var obj map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"kind": "Trial",
+		"metadata": {
+			"name": "test",
+			"managedFields": [{"manager": "kubectl"}],
+			"annotations": {
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"redskyops.dev/sensitive-parameters": "password"
+			}
+		},
+		"spec": {
+			"assignments": [{"name": "password", "value": 42}, {"name": "replicas", "value": 3}],
+			"template": {"spec": {"template": {"spec": {"containers": [{"env": [
+				{"name": "TOKEN", "value": "secret"},
+				{"name": "POD", "valueFrom": {"fieldRef": {"fieldPath": "metadata.name"}}}
+			]}]}}}}
+		}
+	}`), &obj))
+
+	sanitize(obj)
+
+	actual, err := json.Marshal(obj)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"kind": "Trial",
+		"metadata": {
+			"name": "test",
+			"annotations": {
+				"redskyops.dev/sensitive-parameters": "password"
+			}
+		},
+		"spec": {
+			"assignments": [{"name": "password", "value": "***"}, {"name": "replicas", "value": 3}],
+			"template": {"spec": {"template": {"spec": {"containers": [{"env": [
+				{"name": "TOKEN", "value": "***"},
+				{"name": "POD", "valueFrom": {"fieldRef": {"fieldPath": "metadata.name"}}}
+			]}]}}}}
+		}
+	}`, string(actual))
+}
+
+func TestRedactConfig(t *testing.T) {
+	cases := []struct {
+		desc     string
+		cfg      string
+		expected string
+	}{
+		{
+			desc:     "credentials",
+			cfg:      `{"authorizations": [{"name": "default", "authorization": {"credential": {"access_token": "abc", "refresh_token": "def", "token_type": "Bearer"}}}]}`,
+			expected: `{"authorizations": [{"name": "default", "authorization": {"credential": {"access_token": "***", "refresh_token": "***", "token_type": "Bearer"}}}]}`,
+		},
+		{
+			desc:     "client credentials",
+			cfg:      `{"authorizations": [{"name": "default", "authorization": {"credential": {"client_id": "abc", "client_secret": "def"}}}]}`,
+			expected: `{"authorizations": [{"name": "default", "authorization": {"credential": {"client_id": "abc", "client_secret": "***"}}}]}`,
+		},
+		{
+			desc:     "server",
+			cfg:      `{"servers": [{"name": "default", "server": {"identifier": "https://api.example.com/"}}]}`,
+			expected: `{"servers": [{"name": "default", "server": {"identifier": "https://api.example.com/"}}]}`,
+		},
+		{
+			desc: "controller",
+			cfg: `{"controllers": [{"name": "default", "controller": {
+				"registration_client_uri": "https://auth.example.com/clients/abc",
+				"registration_access_token": "def",
+				"env": [{"name": "REDSKY_AUTHORIZATION_CLIENT_SECRET", "value": "ghi"}]
+			}}]}`,
+			expected: `{"controllers": [{"name": "default", "controller": {
+				"registration_client_uri": "https://auth.example.com/clients/abc",
+				"registration_access_token": "***",
+				"env": [{"name": "REDSKY_AUTHORIZATION_CLIENT_SECRET", "value": "***"}]
+			}}]}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			var cfg interface{}
+			require.NoError(t, json.Unmarshal([]byte(c.cfg), &cfg))
+
+			redactConfig(cfg)
+
+			actual, err := json.Marshal(cfg)
+			require.NoError(t, err)
+			assert.JSONEq(t, c.expected, string(actual))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dev
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/redskyops/redskyops-controller/internal/devserver"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+)
+
+// Options is the configuration for local development tools
+type Options struct {
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// ServerAddress is the address the development server listens on
+	ServerAddress string
+}
+
+// NewCommand creates a new command for local development tools
+func NewCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Local development tools",
+		Long:  "Tools for developing and demonstrating Red Sky Ops locally",
+	}
+
+	cmd.AddCommand(newServerCommand(o))
+
+	return cmd
+}
+
+func newServerCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "server",
+		Short: "Run an in-memory Red Sky API server",
+		Long: "Run an in-memory implementation of the Red Sky API which suggests random trials, all state is lost " +
+			"when the server stops",
+
+		PreRun: commander.StreamsPreRun(&o.IOStreams),
+		RunE:   commander.WithContextE(o.server),
+	}
+
+	cmd.Flags().StringVar(&o.ServerAddress, "address", ":8000", "Address to listen on.")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *Options) server(ctx context.Context) error {
+	server := commander.NewContextServer(ctx, devserver.NewServer(),
+		commander.WithServerOptions(o.configureServer),
+		commander.ShutdownOnInterrupt(func() { _, _ = fmt.Fprintln(o.Out) }),
+		commander.HandleStart(o.printUsage))
+
+	return server.ListenAndServe()
+}
+
+func (o *Options) configureServer(srv *http.Server) {
+	srv.Addr = o.ServerAddress
+	srv.ReadTimeout = 5 * time.Second
+	srv.WriteTimeout = 10 * time.Second
+}
+
+func (o *Options) printUsage(loc string) error {
+	_, _ = fmt.Fprintf(o.Out, "Serving the Red Sky API at %s\n", loc)
+	_, _ = fmt.Fprintf(o.Out, "To use this server, set the following environment variable (or use the cluster address when running the controller):\n\n")
+	_, _ = fmt.Fprintf(o.Out, "  export REDSKY_SERVER_IDENTIFIER=%s\n\n", loc)
+	return nil
+}
@@ -26,8 +26,6 @@ import (
 	"github.com/spf13/cobra/doc"
 )
 
-// TODO Add support for fetching Red Sky OpenAPI specification
-
 // Options is the configuration for generating documentation
 type Options struct {
 	// Directory is the output directory for generated documentation
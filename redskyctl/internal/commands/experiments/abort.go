@@ -0,0 +1,82 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+)
+
+// AbortOptions includes the configuration for aborting running trials
+type AbortOptions struct {
+	Options
+}
+
+// NewAbortCommand creates a new abort command
+func NewAbortCommand(o *AbortOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "abort (TYPE NAME | TYPE/NAME ...)",
+		Short: "Abort a running trial",
+		Long: "Abort running trials in the cluster. The trial run job is stopped, setup tasks are cleaned up and the " +
+			"trial is reported as failed so the experiment can continue.",
+
+		ValidArgsFunction: o.completeNames,
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			return o.setNames(args)
+		},
+		RunE: commander.WithContextE(o.abort),
+	}
+
+	_ = cmd.MarkZshCompPositionalArgumentWords(1, string(typeTrial))
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *AbortOptions) abort(ctx context.Context) error {
+	for _, n := range o.Names {
+		if n.Type != typeTrial {
+			return fmt.Errorf("cannot abort \"%s\"", n.Type)
+		}
+		if n.Name == "" || n.Number < 0 {
+			return fmt.Errorf("experiment name and trial number are required to abort a trial")
+		}
+
+		t, err := o.getTrial(ctx, n)
+		if err != nil {
+			return err
+		}
+
+		kubectlPatch, err := o.Config.Kubectl(ctx, "patch", "trials.v1beta1.redskyops.dev", t.Name,
+			"--namespace", t.Namespace, "--type", "merge", "--patch", `{"spec":{"abort":true}}`)
+		if err != nil {
+			return err
+		}
+		kubectlPatch.Stderr = o.ErrOut
+		if err := kubectlPatch.Run(); err != nil {
+			return err
+		}
+
+		_ = o.Output.PrintAction(o.Out, "trial", t.Name, commander.MsgAborted)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+)
+
+// completeNames provides dynamic shell completion of "TYPE NAME" and "TYPE/NAME" arguments
+func (o *Options) completeNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var t resourceType
+	var prefix string
+	if len(args) > 0 && !strings.Contains(args[0], "/") {
+		nt, err := normalizeType(args[0])
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		t = nt
+	} else if p := strings.SplitN(toComplete, "/", 2); len(p) == 2 {
+		nt, err := normalizeType(p[0])
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		t, prefix, toComplete = nt, p[0]+"/", p[1]
+	} else {
+		return validTypes(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := o.matchNames(cmd, t, toComplete)
+	for i := range names {
+		names[i] = prefix + names[i]
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeExperimentName provides dynamic shell completion of a single experiment name argument
+func (o *Options) completeExperimentName(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return o.matchNames(cmd, typeExperiment, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// matchNames returns the names of the requested type which start with the text being completed
+func (o *Options) matchNames(cmd *cobra.Command, t resourceType, toComplete string) []string {
+	// Completion runs without the normal pre-run, the API is only needed to look up names
+	ctx := context.Background()
+	if err := commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd); err != nil {
+		o.ExperimentsAPI = nil
+	}
+
+	return matchNames(t, toComplete, o.experimentNames(ctx), func(experimentName string) []string {
+		return o.trialNames(ctx, experimentName)
+	})
+}
+
+// matchNames filters experiment names by the text being completed; trial names are only included once the text being
+// completed identifies a single experiment
+func matchNames(t resourceType, toComplete string, experimentNames []string, trialNames func(string) []string) []string {
+	var matches []string
+	for _, n := range experimentNames {
+		if strings.HasPrefix(n, toComplete) {
+			matches = append(matches, n)
+		}
+	}
+	if t != typeTrial {
+		return matches
+	}
+
+	var experimentName string
+	if len(matches) == 1 {
+		experimentName = matches[0]
+	}
+	for _, n := range experimentNames {
+		if strings.HasPrefix(toComplete, n+"-") && len(n) > len(experimentName) {
+			experimentName = n
+		}
+	}
+	if experimentName == "" {
+		return matches
+	}
+
+	for _, n := range trialNames(experimentName) {
+		if strings.HasPrefix(n, toComplete) {
+			matches = append(matches, n)
+		}
+	}
+	return matches
+}
+
+// experimentNames returns the names of the experiments from the remote server, falling back to the cluster
+func (o *Options) experimentNames(ctx context.Context) []string {
+	var names []string
+	if o.ExperimentsAPI != nil {
+		if l, err := o.ExperimentsAPI.GetAllExperiments(ctx, nil); err == nil {
+			for {
+				for i := range l.Experiments {
+					names = append(names, l.Experiments[i].DisplayName)
+				}
+				if l.Next == "" {
+					break
+				}
+				if l, err = o.ExperimentsAPI.GetAllExperimentsByPage(ctx, l.Next); err != nil {
+					break
+				}
+			}
+			sort.Strings(names)
+			return names
+		}
+	}
+
+	el := &redskyv1beta1.ExperimentList{}
+	if err := o.kubectlCompletionJSON(ctx, el, "get", "experiments.v1beta1.redskyops.dev"); err != nil {
+		return nil
+	}
+	for i := range el.Items {
+		names = append(names, el.Items[i].Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// trialNames returns the names of the trials of an experiment from the remote server, falling back to the cluster
+func (o *Options) trialNames(ctx context.Context, experimentName string) []string {
+	var names []string
+	if o.ExperimentsAPI != nil {
+		if exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName(experimentName)); err == nil && exp.TrialsURL != "" {
+			if tl, err := o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, nil); err == nil {
+				for i := range tl.Trials {
+					names = append(names, fmt.Sprintf("%s-%03d", experimentName, tl.Trials[i].Number))
+				}
+				sort.Strings(names)
+				return names
+			}
+		}
+	}
+
+	tl := &redskyv1beta1.TrialList{}
+	if err := o.kubectlCompletionJSON(ctx, tl, "get", "trials.v1beta1.redskyops.dev", "--all-namespaces", "--selector", redskyv1beta1.LabelExperiment+"="+experimentName); err != nil {
+		return nil
+	}
+	for i := range tl.Items {
+		names = append(names, tl.Items[i].Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// kubectlCompletionJSON is like kubectlJSON except errors are not reported since they would corrupt the completions
+func (o *Options) kubectlCompletionJSON(ctx context.Context, obj interface{}, args ...string) error {
+	streams := o.IOStreams
+	defer func() { o.IOStreams = streams }()
+	o.ErrOut = nil
+	return o.kubectlJSON(ctx, obj, args...)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchNames(t *testing.T) {
+	experimentNames := []string{"my-exp", "my-exp-2", "other"}
+	trialNames := map[string][]string{
+		"my-exp":   {"my-exp-000", "my-exp-001"},
+		"my-exp-2": {"my-exp-2-000"},
+		"other":    {"other-000"},
+	}
+
+	cases := []struct {
+		desc         string
+		resourceType resourceType
+		toComplete   string
+		expected     []string
+	}{
+		{
+			desc:         "all experiments",
+			resourceType: typeExperiment,
+			expected:     []string{"my-exp", "my-exp-2", "other"},
+		},
+		{
+			desc:         "experiment prefix",
+			resourceType: typeExperiment,
+			toComplete:   "my",
+			expected:     []string{"my-exp", "my-exp-2"},
+		},
+		{
+			desc:         "ambiguous trial experiment",
+			resourceType: typeTrial,
+			toComplete:   "my",
+			expected:     []string{"my-exp", "my-exp-2"},
+		},
+		{
+			desc:         "single trial experiment",
+			resourceType: typeTrial,
+			toComplete:   "o",
+			expected:     []string{"other", "other-000"},
+		},
+		{
+			desc:         "trial prefix",
+			resourceType: typeTrial,
+			toComplete:   "my-exp-0",
+			expected:     []string{"my-exp-000", "my-exp-001"},
+		},
+		{
+			desc:         "longest experiment name",
+			resourceType: typeTrial,
+			toComplete:   "my-exp-2-",
+			expected:     []string{"my-exp-2-000"},
+		},
+		{
+			desc:         "no match",
+			resourceType: typeTrial,
+			toComplete:   "x",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			actual := matchNames(c.resourceType, c.toComplete, experimentNames, func(n string) []string { return trialNames[n] })
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
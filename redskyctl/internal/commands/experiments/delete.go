@@ -19,7 +19,10 @@ package experiments
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
 	"github.com/redskyops/redskyops-controller/internal/controller"
 	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
@@ -32,6 +35,8 @@ type DeleteOptions struct {
 
 	// IgnoreNotFound treats missing resources as successful deletes
 	IgnoreNotFound bool
+	// Cascade also deletes the namespaces, jobs and config maps created for the trials
+	Cascade bool
 }
 
 // NewDeleteCommand creates a new deletion command
@@ -39,7 +44,17 @@ func NewDeleteCommand(o *DeleteOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "delete (TYPE NAME | TYPE/NAME ...)",
 		Short: "Delete a Red Sky resource",
-		Long:  "Delete Red Sky resources from the remote server",
+		Long: "Delete Red Sky resources from the remote server and the cluster.\n\n" +
+			"Deleting a trial abandons it on the remote server if it is still running, completed trials remain part of " +
+			"the experiment results. Use --cascade to also delete the namespaces, jobs and config maps created for the " +
+			"trials.",
+		Example: `# Delete an experiment along with everything created for its trials
+redskyctl delete experiment my-experiment --cascade
+
+# Delete a single trial
+redskyctl delete trial my-experiment-012`,
+
+		ValidArgsFunction: o.completeNames,
 
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			commander.SetStreams(&o.IOStreams, cmd)
@@ -51,9 +66,12 @@ func NewDeleteCommand(o *DeleteOptions) *cobra.Command {
 		RunE: commander.WithContextE(o.delete),
 	}
 
+	cmd.Flags().BoolVar(&o.Cascade, "cascade", false, "Also delete the namespaces, jobs and config maps created for the trials.")
+	cmd.Flags().BoolVar(&o.IgnoreNotFound, "ignore-not-found", false, "Treat \"resource not found\" as a successful delete.")
+
 	_ = cmd.MarkZshCompPositionalArgumentWords(1, validTypes()...)
 
-	o.Printer = &verbPrinter{verb: "deleted"}
+	o.Printer = &verbPrinter{verb: commander.MsgDeleted, output: &o.Output}
 	commander.ExitOnError(cmd)
 	return cmd
 }
@@ -66,7 +84,14 @@ func (o *DeleteOptions) delete(ctx context.Context) error {
 
 		switch n.Type {
 		case typeExperiment:
-			if err := o.deleteExperiment(ctx, n.experimentName()); o.ignoreDeleteError(err) != nil {
+			if err := o.deleteExperiment(ctx, n); o.ignoreDeleteError(err) != nil {
+				return err
+			}
+		case typeTrial:
+			if n.Number < 0 {
+				return fmt.Errorf("experiment name and trial number are required to delete a trial")
+			}
+			if err := o.deleteTrial(ctx, n); o.ignoreDeleteError(err) != nil {
 				return err
 			}
 		default:
@@ -84,17 +109,176 @@ func (o *DeleteOptions) ignoreDeleteError(err error) error {
 	return err
 }
 
-// deleteExperiment deletes an individual experiment by name
+// deleteExperiment deletes an individual experiment by name from both the remote server and the cluster
 //noinspection GoNilness
-func (o *DeleteOptions) deleteExperiment(ctx context.Context, name experimentsv1alpha1.ExperimentName) error {
-	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, name)
-	if err != nil && exp.SelfURL == "" {
+func (o *DeleteOptions) deleteExperiment(ctx context.Context, n name) error {
+	// The experiment may exist only in the cluster, only report a missing experiment if it is missing from both
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, n.experimentName())
+	if err != nil && exp.SelfURL == "" && controller.IgnoreNotFound(err) != nil {
 		return err
 	}
+	remoteErr := err
+	if exp.SelfURL != "" {
+		remoteErr = o.ExperimentsAPI.DeleteExperiment(ctx, exp.SelfURL)
+		if controller.IgnoreNotFound(remoteErr) != nil {
+			return remoteErr
+		}
+	}
 
-	if err := o.ExperimentsAPI.DeleteExperiment(ctx, exp.SelfURL); err != nil {
+	// Find the cluster experiment (and its trials) before anything is deleted
+	expList := &redskyv1beta1.ExperimentList{}
+	if err := o.kubectlJSON(ctx, expList, "get", "experiments.v1beta1.redskyops.dev", "--field-selector", "metadata.name="+n.Name); err != nil {
 		return err
 	}
+	if len(expList.Items) == 0 {
+		if remoteErr != nil {
+			return remoteErr
+		}
+		return o.Output.PrintAction(o.Out, "experiment", n.Name, commander.MsgDeleted)
+	}
+	clusterExp := &expList.Items[0]
+
+	var trials []redskyv1beta1.Trial
+	if o.Cascade {
+		tl := &redskyv1beta1.TrialList{}
+		if err := o.kubectlJSON(ctx, tl, "get", "trials.v1beta1.redskyops.dev", "--all-namespaces", "--selector", redskyv1beta1.LabelExperiment+"="+n.Name); err != nil {
+			return err
+		}
+		trials = experimentTrials(clusterExp, tl)
+	}
 
-	return o.Printer.PrintObj(&exp, o.Out)
+	if _, err := o.kubectlDelete(ctx, "experiments.v1beta1.redskyops.dev", clusterExp.Name, "--namespace", clusterExp.Namespace); err != nil {
+		return err
+	}
+
+	if o.Cascade {
+		if err := o.deleteCreated(ctx, cascadeSelectors(clusterExp.Namespace, clusterExp.Name, trials)); err != nil {
+			return err
+		}
+	}
+
+	return o.Output.PrintAction(o.Out, "experiment", n.Name, commander.MsgDeleted)
+}
+
+// deleteTrial deletes an individual trial by name, running trials are abandoned on the remote server
+func (o *DeleteOptions) deleteTrial(ctx context.Context, n name) error {
+	trialName := fmt.Sprintf("%s-%03d", n.Name, n.Number)
+
+	// Abandon the trial if it is still running, the server does not allow completed trials to be removed
+	abandoned, err := o.abandonTrial(ctx, n)
+	if err != nil {
+		return err
+	}
+
+	t, err := o.getTrial(ctx, n)
+	if err != nil {
+		if abandoned {
+			return o.Output.PrintAction(o.Out, "trial", trialName, commander.MsgDeleted)
+		}
+		return err
+	}
+
+	if _, err := o.kubectlDelete(ctx, "trials.v1beta1.redskyops.dev", t.Name, "--namespace", t.Namespace); err != nil {
+		return err
+	}
+
+	if o.Cascade {
+		expName := t.ExperimentNamespacedName()
+		if err := o.deleteCreated(ctx, cascadeSelectors(expName.Namespace, expName.Name, []redskyv1beta1.Trial{*t})); err != nil {
+			return err
+		}
+	}
+
+	return o.Output.PrintAction(o.Out, "trial", t.Name, commander.MsgDeleted)
+}
+
+// abandonTrial abandons the remote trial if it has not yet finished, returns true if a trial was abandoned
+func (o *DeleteOptions) abandonTrial(ctx context.Context, n name) (bool, error) {
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, n.experimentName())
+	if err != nil || exp.TrialsURL == "" {
+		return false, controller.IgnoreNotFound(err)
+	}
+
+	q := &experimentsv1alpha1.TrialListQuery{Status: []experimentsv1alpha1.TrialStatus{experimentsv1alpha1.TrialStaged, experimentsv1alpha1.TrialActive}}
+	tl, err := o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, q)
+	if err != nil {
+		return false, err
+	}
+
+	for i := range tl.Trials {
+		if tl.Trials[i].Number == n.Number && tl.Trials[i].SelfURL != "" {
+			err := o.ExperimentsAPI.AbandonRunningTrial(ctx, tl.Trials[i].SelfURL)
+			return err == nil, controller.IgnoreNotFound(err)
+		}
+	}
+	return false, nil
+}
+
+// kubectlDelete deletes a cluster object, returns true if the object existed
+func (o *DeleteOptions) kubectlDelete(ctx context.Context, args ...string) (bool, error) {
+	cmd, err := o.Config.Kubectl(ctx, append([]string{"delete", "--ignore-not-found", "--output", "name"}, args...)...)
+	if err != nil {
+		return false, err
+	}
+	cmd.Stderr = o.ErrOut
+	out, err := cmd.Output()
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(out)) != "", nil
+}
+
+// deleteCreated deletes the objects created for trials using the supplied selectors
+func (o *DeleteOptions) deleteCreated(ctx context.Context, selectors [][]string) error {
+	for _, args := range selectors {
+		if _, err := o.kubectlDelete(ctx, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// experimentTrials returns the trials which belong to the supplied experiment, trials of experiments with the same
+// name in other namespaces are excluded
+func experimentTrials(exp *redskyv1beta1.Experiment, tl *redskyv1beta1.TrialList) []redskyv1beta1.Trial {
+	var trials []redskyv1beta1.Trial
+	for i := range tl.Items {
+		nn := tl.Items[i].ExperimentNamespacedName()
+		if nn.Name == exp.Name && nn.Namespace == exp.Namespace {
+			trials = append(trials, tl.Items[i])
+		}
+	}
+	return trials
+}
+
+// cascadeSelectors returns the kubectl arguments for deleting the objects created for the supplied trials of an
+// experiment; deletes are scoped to the namespaces the trials ran in
+func cascadeSelectors(experimentNamespace, experimentName string, trials []redskyv1beta1.Trial) [][]string {
+	// Group the trials by namespace
+	trialNames := make(map[string][]string)
+	var namespaces []string
+	for i := range trials {
+		ns := trials[i].Namespace
+		if _, ok := trialNames[ns]; !ok {
+			namespaces = append(namespaces, ns)
+		}
+		trialNames[ns] = append(trialNames[ns], trials[i].Name)
+	}
+	sort.Strings(namespaces)
+
+	var selectors [][]string
+	for _, ns := range namespaces {
+		labelSelector := fmt.Sprintf("%s=%s,%s in (%s)", redskyv1beta1.LabelExperiment, experimentName, redskyv1beta1.LabelTrial, strings.Join(trialNames[ns], ","))
+		selectors = append(selectors, []string{"jobs,configmaps", "--namespace", ns, "--selector", labelSelector})
+	}
+
+	// Namespaces are only deleted if they were created from the experiment's namespace template
+	namespaceSelector := redskyv1beta1.LabelExperiment + "=" + experimentName + "," + redskyv1beta1.LabelTrialRole + "=trialSetup"
+	for _, ns := range namespaces {
+		if ns == experimentNamespace {
+			continue
+		}
+		selectors = append(selectors, []string{"namespaces", "--selector", namespaceSelector, "--field-selector", "metadata.name=" + ns})
+	}
+	return selectors
 }
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCascadeSelectors(t *testing.T) {
+	trial := func(name, namespace string) redskyv1beta1.Trial {
+		return redskyv1beta1.Trial{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+
+	cases := []struct {
+		desc     string
+		trials   []redskyv1beta1.Trial
+		expected [][]string
+	}{
+		{
+			desc: "no trials",
+		},
+		{
+			desc:   "experiment namespace",
+			trials: []redskyv1beta1.Trial{trial("test-000", "default"), trial("test-001", "default")},
+			expected: [][]string{
+				{"jobs,configmaps", "--namespace", "default", "--selector", "redskyops.dev/experiment=test,redskyops.dev/trial in (test-000,test-001)"},
+			},
+		},
+		{
+			desc:   "trial namespaces",
+			trials: []redskyv1beta1.Trial{trial("test-001", "test-ns-b"), trial("test-000", "test-ns-a")},
+			expected: [][]string{
+				{"jobs,configmaps", "--namespace", "test-ns-a", "--selector", "redskyops.dev/experiment=test,redskyops.dev/trial in (test-000)"},
+				{"jobs,configmaps", "--namespace", "test-ns-b", "--selector", "redskyops.dev/experiment=test,redskyops.dev/trial in (test-001)"},
+				{"namespaces", "--selector", "redskyops.dev/experiment=test,redskyops.dev/trial-role=trialSetup", "--field-selector", "metadata.name=test-ns-a"},
+				{"namespaces", "--selector", "redskyops.dev/experiment=test,redskyops.dev/trial-role=trialSetup", "--field-selector", "metadata.name=test-ns-b"},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.expected, cascadeSelectors("default", "test", c.trials))
+		})
+	}
+}
+
+func TestExperimentTrials(t *testing.T) {
+	exp := &redskyv1beta1.Experiment{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"}}
+	tl := &redskyv1beta1.TrialList{
+		Items: []redskyv1beta1.Trial{
+			// Trial in the experiment namespace
+			{ObjectMeta: metav1.ObjectMeta{Name: "test-000", Namespace: "default", Labels: map[string]string{redskyv1beta1.LabelExperiment: "test"}}},
+			// Trial in a namespace created from the template
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-001", Namespace: "test-ns", Labels: map[string]string{redskyv1beta1.LabelExperiment: "test"}},
+				Spec:       redskyv1beta1.TrialSpec{ExperimentRef: &corev1.ObjectReference{Name: "test", Namespace: "default"}},
+			},
+			// Trial of a same-named experiment in another namespace
+			{ObjectMeta: metav1.ObjectMeta{Name: "test-000", Namespace: "other", Labels: map[string]string{redskyv1beta1.LabelExperiment: "test"}}},
+		},
+	}
+
+	var actual []string
+	for _, tr := range experimentTrials(exp, tl) {
+		actual = append(actual, tr.Namespace+"/"+tr.Name)
+	}
+	assert.Equal(t, []string{"default/test-000", "test-ns/test-001"}, actual)
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DescribeOptions includes the configuration for describing cluster objects
+type DescribeOptions struct {
+	Options
+}
+
+// NewDescribeCommand creates a new describe command
+func NewDescribeCommand(o *DescribeOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "describe (TYPE NAME | TYPE/NAME ...)",
+		Short: "Describe a Red Sky resource",
+		Long: "Describe Red Sky resources in the cluster. Trials are described using a timeline which merges the " +
+			"trial conditions with the events of the trial jobs and pods.",
+
+		ValidArgsFunction: o.completeNames,
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			return o.setNames(args)
+		},
+		RunE: commander.WithContextE(o.describe),
+	}
+
+	_ = cmd.MarkZshCompPositionalArgumentWords(1, validTypes()...)
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *DescribeOptions) describe(ctx context.Context) error {
+	for _, n := range o.Names {
+		switch n.Type {
+		case typeTrial:
+			if n.Name == "" || n.Number < 0 {
+				return fmt.Errorf("experiment name and trial number are required to describe a trial")
+			}
+			if err := o.describeTrial(ctx, n); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("cannot describe \"%s\"", n.Type)
+		}
+	}
+	return nil
+}
+
+func (o *DescribeOptions) describeTrial(ctx context.Context, n name) error {
+	t, err := o.getTrial(ctx, n)
+	if err != nil {
+		return err
+	}
+
+	// Collect the events for the trial, it's jobs and pods
+	uids := map[types.UID]bool{t.UID: true}
+	objs := &struct {
+		Items []struct {
+			Metadata struct {
+				UID types.UID `json:"uid"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}{}
+	if err := o.kubectlJSON(ctx, objs, "get", "jobs,pods", "--namespace", t.Namespace, "--selector", redskyv1beta1.LabelTrial+"="+t.Name); err != nil {
+		return err
+	}
+	for i := range objs.Items {
+		uids[objs.Items[i].Metadata.UID] = true
+	}
+
+	el := &corev1.EventList{}
+	if err := o.kubectlJSON(ctx, el, "get", "events", "--namespace", t.Namespace); err != nil {
+		return err
+	}
+	var events []corev1.Event
+	for i := range el.Items {
+		if uids[el.Items[i].InvolvedObject.UID] {
+			events = append(events, el.Items[i])
+		}
+	}
+
+	// Print the timeline
+	_, _ = fmt.Fprintf(o.Out, "Name:         %s\n", t.Name)
+	_, _ = fmt.Fprintf(o.Out, "Namespace:    %s\n", t.Namespace)
+	_, _ = fmt.Fprintf(o.Out, "Phase:        %s\n", t.Status.Phase)
+	_, _ = fmt.Fprintf(o.Out, "Assignments:  %s\n", t.Status.Assignments)
+	_, _ = fmt.Fprintf(o.Out, "Values:       %s\n", t.Status.Values)
+	_, _ = fmt.Fprintln(o.Out, "Timeline:")
+
+	w := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "  TIME\tOBJECT\tEVENT")
+	start := t.CreationTimestamp.Time
+	for _, e := range timeline(t, events) {
+		_, _ = fmt.Fprintf(w, "  +%s\t%s\t%s\n", e.time.Sub(start).Round(time.Second), e.object, e.message)
+	}
+	return w.Flush()
+}
+
+// getTrial finds the cluster trial for the supplied name
+func (o *Options) getTrial(ctx context.Context, n name) (*redskyv1beta1.Trial, error) {
+	tl := &redskyv1beta1.TrialList{}
+	if err := o.kubectlJSON(ctx, tl, "get", "trials.v1beta1.redskyops.dev", "--all-namespaces", "--selector", redskyv1beta1.LabelExperiment+"="+n.Name); err != nil {
+		return nil, err
+	}
+
+	suffix := fmt.Sprintf("%03d", n.Number)
+	for i := range tl.Items {
+		t := &tl.Items[i]
+		if t.Name == t.GenerateName+suffix || t.Name == n.Name+"-"+suffix {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find trial %d of experiment %s in the cluster", n.Number, n.Name)
+}
+
+// kubectlJSON runs kubectl and decodes the JSON output
+func (o *Options) kubectlJSON(ctx context.Context, obj interface{}, args ...string) error {
+	cmd, err := o.Config.Kubectl(ctx, append(args, "--output", "json")...)
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = o.ErrOut
+	data, err := cmd.Output()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, obj)
+}
+
+// timelineEntry is a single moment in the life of a trial
+type timelineEntry struct {
+	time    time.Time
+	object  string
+	message string
+}
+
+// timeline merges the trial state and events into a chronological list
+func timeline(t *redskyv1beta1.Trial, events []corev1.Event) []timelineEntry {
+	trialObject := "trial/" + t.Name
+	entries := []timelineEntry{{time: t.CreationTimestamp.Time, object: trialObject, message: "Trial created"}}
+
+	for _, c := range t.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		if msg := conditionMessage(t, &c); msg != "" {
+			entries = append(entries, timelineEntry{time: c.LastTransitionTime.Time, object: trialObject, message: msg})
+		}
+	}
+	if t.Status.WarmupStartTime != nil {
+		entries = append(entries, timelineEntry{time: t.Status.WarmupStartTime.Time, object: trialObject, message: "Trial run warm-up started"})
+	}
+	if t.Status.StartTime != nil {
+		entries = append(entries, timelineEntry{time: t.Status.StartTime.Time, object: trialObject, message: "Trial run started"})
+	}
+	if t.Status.CompletionTime != nil {
+		entries = append(entries, timelineEntry{time: t.Status.CompletionTime.Time, object: trialObject, message: "Trial run finished"})
+	}
+
+	for i := range events {
+		e := &events[i]
+		ts := e.LastTimestamp.Time
+		if ts.IsZero() {
+			ts = e.EventTime.Time
+		}
+		if ts.IsZero() {
+			ts = e.FirstTimestamp.Time
+		}
+		msg := e.Reason + ": " + e.Message
+		if e.Count > 1 {
+			msg = fmt.Sprintf("%s (x%d)", msg, e.Count)
+		}
+		entries = append(entries, timelineEntry{
+			time:    ts,
+			object:  strings.ToLower(e.InvolvedObject.Kind) + "/" + e.InvolvedObject.Name,
+			message: msg,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].time.Before(entries[j].time) })
+	return entries
+}
+
+// conditionMessage returns a narrative description of a trial condition
+func conditionMessage(t *redskyv1beta1.Trial, c *redskyv1beta1.TrialCondition) string {
+	switch c.Type {
+	case redskyv1beta1.TrialSetupCreated:
+		return "Setup tasks created"
+	case redskyv1beta1.TrialPatched:
+		return "Patches applied"
+	case redskyv1beta1.TrialReady:
+		return "Rollout complete"
+	case redskyv1beta1.TrialObserved:
+		if t.Status.Values != "" {
+			return "Metrics collected: " + t.Status.Values
+		}
+		return "Metrics collected"
+	case redskyv1beta1.TrialComplete:
+		if len(t.Finalizers) == 0 {
+			return "Trial completed, values reported"
+		}
+		return "Trial completed"
+	case redskyv1beta1.TrialSetupDeleted:
+		return "Setup tasks deleted"
+	case redskyv1beta1.TrialFailed:
+		return strings.TrimSpace(fmt.Sprintf("Trial failed: %s %s", c.Reason, c.Message))
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"testing"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTimeline(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) metav1.Time { return metav1.NewTime(now.Add(d)) }
+
+	tr := &redskyv1beta1.Trial{}
+	tr.Name = "test-001"
+	tr.CreationTimestamp = at(0)
+	start, completion := at(2*time.Minute), at(5*time.Minute)
+	tr.Status.StartTime = &start
+	tr.Status.CompletionTime = &completion
+	tr.Status.Conditions = []redskyv1beta1.TrialCondition{
+		{Type: redskyv1beta1.TrialPatched, Status: corev1.ConditionTrue, LastTransitionTime: at(time.Minute)},
+		{Type: redskyv1beta1.TrialReady, Status: corev1.ConditionFalse, LastTransitionTime: at(time.Minute)},
+		{Type: redskyv1beta1.TrialComplete, Status: corev1.ConditionTrue, LastTransitionTime: at(6 * time.Minute)},
+	}
+
+	events := []corev1.Event{
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Job", Name: "test-001"},
+			Reason:         "SuccessfulCreate",
+			Message:        "Created pod: test-001-abcde",
+			LastTimestamp:  at(90 * time.Second),
+		},
+	}
+
+	var messages []string
+	for _, e := range timeline(tr, events) {
+		messages = append(messages, e.object+" "+e.message)
+	}
+	assert.Equal(t, []string{
+		"trial/test-001 Trial created",
+		"trial/test-001 Patches applied",
+		"job/test-001 SuccessfulCreate: Created pod: test-001-abcde",
+		"trial/test-001 Trial run started",
+		"trial/test-001 Trial run finished",
+		"trial/test-001 Trial completed, values reported",
+	}, messages)
+}
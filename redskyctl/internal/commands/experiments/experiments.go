@@ -21,12 +21,17 @@ import (
 	"io"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/trial"
 	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/util/jsonpath"
 )
 
@@ -142,17 +147,18 @@ func parseNames(args []string) ([]name, error) {
 	return names, nil
 }
 
-// verbPrinter
+// verbPrinter reports the action taken on an experiment or trial
 type verbPrinter struct {
-	verb string
+	verb   commander.Message
+	output *commander.OutputMode
 }
 
 func (v *verbPrinter) PrintObj(obj interface{}, w io.Writer) error {
 	switch o := obj.(type) {
 	case *experimentsv1alpha1.Experiment:
-		_, _ = fmt.Fprintf(w, "experiment \"%s\" %s\n", o.DisplayName, v.verb)
+		_ = v.output.PrintAction(w, "experiment", o.DisplayName, v.verb)
 	case *experimentsv1alpha1.TrialItem:
-		_, _ = fmt.Fprintf(w, "trial \"%s-%03d\" %s\n", o.Experiment.DisplayName, o.Number, v.verb)
+		_ = v.output.PrintAction(w, "trial", fmt.Sprintf("%s-%03d", o.Experiment.DisplayName, o.Number), v.verb)
 	default:
 		return fmt.Errorf("could not print \"%s\" for: %T", v.verb, obj)
 	}
@@ -160,7 +166,13 @@ func (v *verbPrinter) PrintObj(obj interface{}, w io.Writer) error {
 }
 
 // experimentsMeta is the metadata extraction necessary for printing Red Sky Experiments API objects
-type experimentsMeta struct{}
+type experimentsMeta struct {
+	// clusterTrials are the Kubernetes trials (keyed by both the remote trial URL and the trial name) used to include
+	// timestamps and failure details in wide trial output
+	clusterTrials map[string]*redskyv1beta1.Trial
+	// now returns the current time, used to compute the duration of running trials
+	now func() time.Time
+}
 
 // ExtractList returns the items from an API list object
 func (m *experimentsMeta) ExtractList(obj interface{}) ([]interface{}, error) {
@@ -189,9 +201,14 @@ func (m *experimentsMeta) ExtractList(obj interface{}) ([]interface{}, error) {
 
 // Columns returns the column names to use
 func (m *experimentsMeta) Columns(obj interface{}, outputFormat string, showLabels bool) []string {
-	// Special case for trial list CSV to include everything as columns
-	if tl, ok := obj.(*experimentsv1alpha1.TrialList); ok && outputFormat == "csv" {
+	// Special case for trial list CSV to include everything as columns, the wide variants also include the labels and
+	// the details of the cluster trials
+	if tl, ok := obj.(*experimentsv1alpha1.TrialList); ok && isDataFormat(outputFormat) {
+		wide := outputFormat != "csv"
 		columns := []string{"experiment", "number", "status"}
+		if wide {
+			columns = append(columns, "started", "completed", "duration", "reason")
+		}
 
 		// CSV column names should correspond to the parameter and metric names
 		if tl.Experiment != nil {
@@ -204,16 +221,19 @@ func (m *experimentsMeta) Columns(obj interface{}, outputFormat string, showLabe
 		}
 
 		// CSV labels need to be split out into individual columns
-		if showLabels {
+		if showLabels || wide {
 			labels := make(map[string]bool)
 			for i := range tl.Trials {
 				for k := range tl.Trials[i].Labels {
 					labels[k] = true
 				}
 			}
+			labelColumns := make([]string, 0, len(labels))
 			for k := range labels {
-				columns = append(columns, "label_"+k)
+				labelColumns = append(labelColumns, "label_"+k)
 			}
+			sort.Strings(labelColumns)
+			columns = append(columns, labelColumns...)
 		}
 
 		return columns
@@ -225,6 +245,9 @@ func (m *experimentsMeta) Columns(obj interface{}, outputFormat string, showLabe
 
 	case *experimentsv1alpha1.TrialList, *experimentsv1alpha1.TrialItem:
 		columns = append(columns, "Status") // Title case the value
+		if outputFormat == "wide" {
+			columns = append(columns, "started", "completed", "duration", "reason")
+		}
 
 	case *experimentsv1alpha1.ExperimentList, *experimentsv1alpha1.ExperimentItem:
 		if outputFormat == "wide" {
@@ -275,6 +298,23 @@ func (m *experimentsMeta) ExtractValue(obj interface{}, column string) (string,
 			return string(o.Status), nil
 		case "Status":
 			return strings.Title(string(o.Status)), nil
+		case "started":
+			if t := m.clusterTrial(o); t != nil && t.Status.StartTime != nil {
+				return t.Status.StartTime.UTC().Format(time.RFC3339), nil
+			}
+			return "", nil
+		case "completed":
+			if t := m.clusterTrial(o); t != nil && t.Status.CompletionTime != nil {
+				return t.Status.CompletionTime.UTC().Format(time.RFC3339), nil
+			}
+			return "", nil
+		case "duration":
+			return m.duration(o), nil
+		case "reason":
+			if o.FailureReason != "" {
+				return o.FailureReason, nil
+			}
+			return failureReason(m.clusterTrial(o)), nil
 		case "labels":
 			var labels []string
 			for k, v := range o.Labels {
@@ -286,6 +326,9 @@ func (m *experimentsMeta) ExtractValue(obj interface{}, column string) (string,
 			if pn := strings.TrimPrefix(column, "parameter_"); pn != column {
 				for i := range o.Assignments {
 					if pn == o.Assignments[i].ParameterName {
+						if isSensitive(o.Experiment, pn) {
+							return trial.Redacted, nil
+						}
 						return o.Assignments[i].Value.String(), nil
 					}
 				}
@@ -313,14 +356,81 @@ func (m *experimentsMeta) ExtractValue(obj interface{}, column string) (string,
 	return "", fmt.Errorf("unable to get value for column %s", column)
 }
 
+// clusterTrial returns the Kubernetes trial corresponding to the supplied trial item, if it is available
+func (m *experimentsMeta) clusterTrial(item *experimentsv1alpha1.TrialItem) *redskyv1beta1.Trial {
+	if t, ok := m.clusterTrials[item.SelfURL]; ok && item.SelfURL != "" {
+		return t
+	}
+	if item.Experiment != nil {
+		return m.clusterTrials[fmt.Sprintf("%s-%03d", item.Experiment.Name(), item.Number)]
+	}
+	return nil
+}
+
+// duration returns the elapsed time of the trial run, running trials are measured against the current time
+func (m *experimentsMeta) duration(item *experimentsv1alpha1.TrialItem) string {
+	t := m.clusterTrial(item)
+	if t == nil || t.Status.StartTime == nil {
+		return ""
+	}
+
+	var end time.Time
+	switch {
+	case t.Status.CompletionTime != nil:
+		end = t.Status.CompletionTime.Time
+	case trial.IsFinished(t):
+		return ""
+	case m.now != nil:
+		end = m.now()
+	default:
+		end = time.Now()
+	}
+	return end.Sub(t.Status.StartTime.Time).Round(time.Second).String()
+}
+
+// failureReason returns the reason recorded on the failed condition of a Kubernetes trial
+func failureReason(t *redskyv1beta1.Trial) string {
+	if t == nil {
+		return ""
+	}
+	for _, c := range t.Status.Conditions {
+		if c.Type == redskyv1beta1.TrialFailed && c.Status == corev1.ConditionTrue {
+			return c.Reason
+		}
+	}
+	return ""
+}
+
+// isSensitive checks to see if the named parameter has values which should not be displayed
+func isSensitive(exp *experimentsv1alpha1.Experiment, name string) bool {
+	if exp == nil {
+		return false
+	}
+	for i := range exp.Parameters {
+		if exp.Parameters[i].Name == name {
+			return exp.Parameters[i].Sensitive
+		}
+	}
+	return false
+}
+
 // Header returns the header name to use for a column
 func (m *experimentsMeta) Header(outputFormat string, column string) string {
-	if strings.ToLower(outputFormat) == "csv" {
+	if isDataFormat(outputFormat) {
 		return column
 	}
 	return strings.ToUpper(column)
 }
 
+// isDataFormat checks for the output formats intended for data analysis tools rather then people
+func isDataFormat(outputFormat string) bool {
+	switch strings.ToLower(outputFormat) {
+	case "csv", "csv-wide", "parquet":
+		return true
+	}
+	return false
+}
+
 // sortByField sorts using a JSONPath expression
 func sortByField(sortBy string, item func(int) interface{}) func(int, int) bool {
 	// TODO We always wrap the items in maps now, can we simplify?
@@ -354,6 +464,17 @@ func sortByField(sortBy string, item func(int) interface{}) func(int, int) bool
 
 // isLess compares values, only int64, float64, and string are allowed
 func isLess(i, j reflect.Value) (bool, error) {
+	// Values found in an `interface{}` map need to be unwrapped (e.g. assignments)
+	if i.Kind() == reflect.Interface {
+		i = i.Elem()
+	}
+	if j.Kind() == reflect.Interface {
+		j = j.Elem()
+	}
+	if i.Kind() != j.Kind() {
+		return false, fmt.Errorf("incomparable types: %v and %v", i.Kind(), j.Kind())
+	}
+
 	switch i.Kind() {
 	case reflect.Int64:
 		return i.Int() < j.Int(), nil
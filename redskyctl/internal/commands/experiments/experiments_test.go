@@ -18,8 +18,13 @@ package experiments
 
 import (
 	"testing"
+	"time"
 
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestParseNames(t *testing.T) {
@@ -98,3 +103,75 @@ func TestParseNames(t *testing.T) {
 		})
 	}
 }
+
+func TestExperimentsMeta_Wide(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	start := metav1.NewTime(now.Add(-10 * time.Minute))
+	completion := metav1.NewTime(now.Add(-4 * time.Minute))
+	exp := &experimentsv1alpha1.Experiment{ExperimentMeta: experimentsv1alpha1.ExperimentMeta{SelfURL: "http://example.com/experiments/foo"}}
+
+	m := &experimentsMeta{
+		now: func() time.Time { return now },
+		clusterTrials: map[string]*redskyv1beta1.Trial{
+			"http://example.com/experiments/foo/trials/1": {
+				Status: redskyv1beta1.TrialStatus{StartTime: &start, CompletionTime: &completion},
+			},
+			"foo-002": {
+				Status: redskyv1beta1.TrialStatus{StartTime: &start},
+			},
+			"foo-003": {
+				Status: redskyv1beta1.TrialStatus{
+					StartTime: &start,
+					Conditions: []redskyv1beta1.TrialCondition{
+						{Type: redskyv1beta1.TrialFailed, Status: corev1.ConditionTrue, Reason: "JobFailed"},
+					},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		desc      string
+		item      experimentsv1alpha1.TrialItem
+		started   string
+		completed string
+		duration  string
+		reason    string
+	}{
+		{
+			desc:      "Completed",
+			item:      experimentsv1alpha1.TrialItem{TrialAssignments: experimentsv1alpha1.TrialAssignments{TrialMeta: experimentsv1alpha1.TrialMeta{SelfURL: "http://example.com/experiments/foo/trials/1"}}, Number: 1},
+			started:   "2020-06-01T11:50:00Z",
+			completed: "2020-06-01T11:56:00Z",
+			duration:  "6m0s",
+		},
+		{
+			desc:     "Running",
+			item:     experimentsv1alpha1.TrialItem{Number: 2},
+			started:  "2020-06-01T11:50:00Z",
+			duration: "10m0s",
+		},
+		{
+			desc:    "ClusterFailure",
+			item:    experimentsv1alpha1.TrialItem{Number: 3},
+			started: "2020-06-01T11:50:00Z",
+			reason:  "JobFailed",
+		},
+		{
+			desc:   "RemoteFailure",
+			item:   experimentsv1alpha1.TrialItem{Number: 4, TrialValues: experimentsv1alpha1.TrialValues{Failed: true, FailureReason: "Aborted"}},
+			reason: "Aborted",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			c.item.Experiment = exp
+			for column, expected := range map[string]string{"started": c.started, "completed": c.completed, "duration": c.duration, "reason": c.reason} {
+				actual, err := m.ExtractValue(&c.item, column)
+				if assert.NoError(t, err) {
+					assert.Equal(t, expected, actual, column)
+				}
+			}
+		})
+	}
+}
@@ -18,12 +18,19 @@ package experiments
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/analysis"
 	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
@@ -31,37 +38,66 @@ import (
 type GetOptions struct {
 	Options
 
-	ChunkSize int
-	SortBy    string
-	Selector  string
-	All       bool
+	ChunkSize     int
+	Offset        int
+	Limit         int
+	SortBy        string
+	SortOrder     string
+	Selector      string
+	FieldSelector string
+	All           bool
+	Pareto        bool
+	Watch         bool
+	WatchInterval time.Duration
+	Cluster       bool
+
+	meta *experimentsMeta
+	wide bool
 }
 
 // NewGetCommand creates a new get command
 func NewGetCommand(o *GetOptions) *cobra.Command {
+	o.meta = &experimentsMeta{}
+
 	cmd := &cobra.Command{
 		Use:   "get (TYPE NAME | TYPE/NAME ...)",
 		Short: "Display a Red Sky resource",
 		Long:  "Get Red Sky resources from the remote server",
 
+		ValidArgsFunction: o.completeNames,
+
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			commander.SetStreams(&o.IOStreams, cmd)
 			if err := commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd); err != nil {
 				return err
 			}
+			if f := cmd.Flags().Lookup("output"); f != nil {
+				switch f.Value.String() {
+				case "wide", "csv-wide", "parquet":
+					o.wide = true
+				}
+			}
 			return o.setNames(args)
 		},
 		RunE: commander.WithContextE(o.get),
 	}
 
 	cmd.Flags().IntVar(&o.ChunkSize, "chunk-size", o.ChunkSize, "Fetch large lists in chunks rather then all at once.")
+	cmd.Flags().IntVar(&o.Offset, "offset", o.Offset, "Skip this many experiments when listing experiments.")
+	cmd.Flags().IntVar(&o.Limit, "limit", o.Limit, "Only fetch a single page of at most this many experiments, unless all resources are included.")
 	cmd.Flags().StringVarP(&o.Selector, "selector", "l", o.Selector, "Selector (label `query`) to filter on.")
-	cmd.Flags().StringVar(&o.SortBy, "sort-by", o.SortBy, "Sort list types using this JSONPath `expression`.")
+	cmd.Flags().StringVar(&o.SortBy, "sort-by", o.SortBy, "Sort list types using this JSONPath `expression` or metric or parameter name.")
+	cmd.Flags().StringVar(&o.SortOrder, "sort-order", o.SortOrder, "Sort `order` for list types. One of: asc|desc")
+	cmd.Flags().StringVar(&o.FieldSelector, "field-selector", o.FieldSelector, "Selector (field `query`) to filter on, supports '=', '==', and '!='.")
 	cmd.Flags().BoolVarP(&o.All, "all", "A", false, "Include all resources.")
+	cmd.Flags().BoolVar(&o.Pareto, "pareto", o.Pareto, "Only include trials on the Pareto front of the experiment metrics.")
+	cmd.Flags().BoolVarP(&o.Watch, "watch", "w", o.Watch, "After listing the trials, watch for changes and print new rows as trials progress.")
+	cmd.Flags().DurationVar(&o.WatchInterval, "watch-interval", o.WatchInterval, "The `duration` between polls of the remote server when watching.")
+	cmd.Flags().BoolVar(&o.Cluster, "cluster", o.Cluster, "Watch the trials in the cluster instead of polling the remote server.")
 
 	_ = cmd.MarkZshCompPositionalArgumentWords(1, validTypes()...)
 
-	commander.SetPrinter(&experimentsMeta{}, &o.Printer, cmd)
+	commander.SetPrinter(o.meta, &o.Printer, cmd)
 	commander.ExitOnError(cmd)
 	return cmd
 }
@@ -70,19 +106,30 @@ func (o *GetOptions) get(ctx context.Context) error {
 	e := make([]experimentsv1alpha1.ExperimentName, 0, len(o.Names))
 	t := make(map[experimentsv1alpha1.ExperimentName][]int64)
 
+	if o.Watch && (len(o.Names) != 1 || o.Names[0].Type != typeTrial || o.Names[0].Number >= 0) {
+		return fmt.Errorf("watch is only supported when getting the trials of a single experiment")
+	}
+
 	for _, n := range o.Names {
 		switch n.Type {
 
 		case typeExperiment:
 			if n.Name == "" {
 				q := &experimentsv1alpha1.ExperimentListQuery{
-					Limit: o.ChunkSize,
+					Offset: o.Offset,
+					Limit:  o.ChunkSize,
+				}
+				if o.Limit > 0 {
+					q.Limit = o.Limit
 				}
-				return o.getExperimentList(ctx, q)
+				return o.getExperimentList(ctx, q, o.All || o.Limit <= 0)
 			}
 			e = append(e, n.experimentName())
 
 		case typeTrial:
+			if n.Number < 0 && o.Watch {
+				return o.watchTrialList(ctx, n.experimentName())
+			}
 			if n.Number < 0 {
 				return o.getTrialList(ctx, n.experimentName(), o.trialListQuery())
 			}
@@ -138,14 +185,20 @@ func (o *GetOptions) getExperiments(ctx context.Context, names []experimentsv1al
 	return o.Printer.PrintObj(l, o.Out)
 }
 
-func (o *GetOptions) getExperimentList(ctx context.Context, q *experimentsv1alpha1.ExperimentListQuery) error {
-	// Get all the experiments one page at a time
+func (o *GetOptions) getExperimentList(ctx context.Context, q *experimentsv1alpha1.ExperimentListQuery, follow bool) error {
+	// Get the first page of experiments
 	l, err := o.ExperimentsAPI.GetAllExperiments(ctx, q)
 	if err != nil {
 		return err
 	}
 
-	for l.Next != "" {
+	// Do not silently drop the remaining pages when only a single page was requested
+	if !follow && l.Next != "" {
+		_, _ = fmt.Fprintf(o.ErrOut, "Showing %d experiments, use --all to list every experiment\n", len(l.Experiments))
+	}
+
+	// Get the remaining experiments one page at a time
+	for follow && l.Next != "" {
 		n, err := o.ExperimentsAPI.GetAllExperimentsByPage(ctx, l.Next)
 		if err != nil {
 			return err
@@ -164,18 +217,23 @@ func (o *GetOptions) getExperimentList(ctx context.Context, q *experimentsv1alph
 func (o *GetOptions) getTrials(ctx context.Context, numbers map[experimentsv1alpha1.ExperimentName][]int64) error {
 	l := &experimentsv1alpha1.TrialList{}
 
+	progress := o.NewProgress("Fetching trials", len(numbers))
 	for n, nums := range numbers {
 		// Get the experiment
 		exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, n)
 		if err != nil {
+			progress.Done(err)
 			return err
 		}
 
 		// Get the trials
 		tl, err := o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, o.trialListQuery())
 		if err != nil {
+			progress.Done(err)
 			return err
 		}
+		progress.Add(1)
+		o.loadClusterTrials(ctx, exp.Name())
 
 		for i := range tl.Trials {
 			if hasTrialNumber(&tl.Trials[i], nums) {
@@ -185,6 +243,7 @@ func (o *GetOptions) getTrials(ctx context.Context, numbers map[experimentsv1alp
 			}
 		}
 	}
+	progress.Done(nil)
 
 	// If this was a request for a single object, just print it out (e.g. don't produce a JSON list for a single element)
 	if len(numbers) == 1 && len(l.Trials) == 1 { // TODO Also should check the length of the map value...
@@ -208,11 +267,15 @@ func (o *GetOptions) getTrialList(ctx context.Context, name experimentsv1alpha1.
 	// Fetch the trial data
 	var l experimentsv1alpha1.TrialList
 	if exp.TrialsURL != "" {
+		progress := o.NewProgress("Fetching trials", 0)
 		l, err = o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, q)
+		progress.Done(err)
 		if err != nil {
 			return err
 		}
 
+		o.loadClusterTrials(ctx, exp.Name())
+
 		// Store a back reference to the experiment on the list and every item in it
 		l.Experiment = &exp
 		for i := range l.Trials {
@@ -235,14 +298,56 @@ func (o *GetOptions) filterAndSortExperiments(l *experimentsv1alpha1.ExperimentL
 		l.Experiments = nil
 	}
 
+	// Filter the experiment list using Kubernetes field selectors
+	if sel, err := fields.ParseSelector(o.FieldSelector); err != nil {
+		return err
+	} else if !sel.Empty() {
+		var filtered []experimentsv1alpha1.ExperimentItem
+		for i := range l.Experiments {
+			if sel.Matches(experimentFields(&l.Experiments[i])) {
+				filtered = append(filtered, l.Experiments[i])
+			}
+		}
+		l.Experiments = filtered
+	}
+
 	// If sorting was requested, sort using maps with all the sortable keys
 	if o.SortBy != "" {
-		sort.Slice(l.Experiments, sortByField(o.SortBy, func(i int) interface{} { return sortableExperimentData(&l.Experiments[i]) }))
+		less, err := o.sortLess(o.SortBy, func(i int) interface{} { return sortableExperimentData(&l.Experiments[i]) })
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(l.Experiments, less)
 	}
 
 	return nil
 }
 
+// sortLess returns the comparison function for the requested sort order
+func (o *GetOptions) sortLess(sortBy string, item func(int) interface{}) (func(int, int) bool, error) {
+	less := sortByField(sortBy, item)
+	if less == nil {
+		return nil, fmt.Errorf("invalid sort expression: %s", sortBy)
+	}
+
+	switch strings.ToLower(o.SortOrder) {
+	case "", "asc":
+		return less, nil
+	case "desc":
+		return func(i, j int) bool { return less(j, i) }, nil
+	default:
+		return nil, fmt.Errorf("invalid sort order %q, must be one of: asc|desc", o.SortOrder)
+	}
+}
+
+// experimentFields returns the fields of an experiment item which can be used in a field selector
+func experimentFields(item *experimentsv1alpha1.ExperimentItem) fields.Set {
+	return fields.Set{
+		"name":         item.DisplayName,
+		"observations": strconv.FormatInt(item.Observations, 10),
+	}
+}
+
 // sortableExperimentData slightly modifies the schema of the experiment item to make it easier to specify sort orders
 func sortableExperimentData(item *experimentsv1alpha1.ExperimentItem) map[string]interface{} {
 	d := make(map[string]interface{}, 2)
@@ -266,20 +371,134 @@ func (o *GetOptions) filterAndSortTrials(l *experimentsv1alpha1.TrialList) error
 		l.Trials = filtered
 	}
 
+	// Filter the trial list using Kubernetes field selectors
+	if sel, err := fields.ParseSelector(o.FieldSelector); err != nil {
+		return err
+	} else if !sel.Empty() {
+		var filtered []experimentsv1alpha1.TrialItem
+		for i := range l.Trials {
+			if sel.Matches(trialFields(&l.Trials[i])) {
+				filtered = append(filtered, l.Trials[i])
+			}
+		}
+		l.Trials = filtered
+	}
+
+	// Reduce the trial list to the non-dominated trials of each experiment
+	if o.Pareto {
+		l.Trials = paretoTrials(l.Trials)
+	}
+
 	// If sorting was requested, sort using maps with all the sortable keys
 	if o.SortBy != "" {
-		sort.Slice(l.Trials, sortByField(o.SortBy, func(i int) interface{} { return sortableTrialData(&l.Trials[i]) }))
+		less, err := o.sortLess(trialSortField(o.SortBy, l.Trials), func(i int) interface{} { return sortableTrialData(&l.Trials[i]) })
+		if err != nil {
+			return err
+		}
+		sort.SliceStable(l.Trials, less)
 	}
 
 	return nil
 }
 
+// trialSortField expands a bare metric or parameter name into the JSONPath expression for its sortable value
+func trialSortField(sortBy string, trials []experimentsv1alpha1.TrialItem) string {
+	if strings.ContainsAny(sortBy, ".{[") {
+		return sortBy
+	}
+
+	for i := range trials {
+		for _, v := range trials[i].Values {
+			if v.MetricName == sortBy {
+				return "values." + sortBy + ".value"
+			}
+		}
+		for _, a := range trials[i].Assignments {
+			if a.ParameterName == sortBy {
+				return "assignments." + sortBy
+			}
+		}
+	}
+	return sortBy
+}
+
+// trialFields returns the fields of a trial item which can be used in a field selector
+func trialFields(item *experimentsv1alpha1.TrialItem) fields.Set {
+	s := fields.Set{
+		"number": strconv.FormatInt(item.Number, 10),
+		"status": string(item.Status),
+	}
+	for i := range item.Assignments {
+		s["assignments."+item.Assignments[i].ParameterName] = item.Assignments[i].Value.String()
+	}
+	for i := range item.Values {
+		s["values."+item.Values[i].MetricName] = strconv.FormatFloat(item.Values[i].Value, 'f', -1, 64)
+	}
+	return s
+}
+
+// loadClusterTrials fetches the Kubernetes trials of the named experiment so wide output can include trial timestamps
+// and failure details, errors are ignored since the remote server may be used without access to the cluster
+func (o *GetOptions) loadClusterTrials(ctx context.Context, experimentName string) {
+	if !o.wide || o.meta == nil || experimentName == "" {
+		return
+	}
+
+	get, err := o.Config.Kubectl(ctx, "get", "trials.v1beta1.redskyops.dev", "--all-namespaces", "--selector", redskyv1beta1.LabelExperiment+"="+experimentName, "--output", "json")
+	if err != nil {
+		return
+	}
+	data, err := get.Output()
+	if err != nil {
+		return
+	}
+	l := &redskyv1beta1.TrialList{}
+	if err := json.Unmarshal(data, l); err != nil {
+		return
+	}
+
+	if o.meta.clusterTrials == nil {
+		o.meta.clusterTrials = make(map[string]*redskyv1beta1.Trial, len(l.Items))
+	}
+	for i := range l.Items {
+		t := &l.Items[i]
+		o.meta.clusterTrials[t.Name] = t
+		if u := t.GetAnnotations()[redskyv1beta1.AnnotationReportTrialURL]; u != "" {
+			o.meta.clusterTrials[u] = t
+		}
+	}
+}
+
+// paretoTrials computes the Pareto front separately for the trials of each experiment
+func paretoTrials(trials []experimentsv1alpha1.TrialItem) []experimentsv1alpha1.TrialItem {
+	var exps []*experimentsv1alpha1.Experiment
+	byExp := make(map[*experimentsv1alpha1.Experiment][]experimentsv1alpha1.TrialItem)
+	for i := range trials {
+		exp := trials[i].Experiment
+		if exp == nil {
+			continue
+		}
+		if _, ok := byExp[exp]; !ok {
+			exps = append(exps, exp)
+		}
+		byExp[exp] = append(byExp[exp], trials[i])
+	}
+
+	var front []experimentsv1alpha1.TrialItem
+	for _, exp := range exps {
+		front = append(front, analysis.ParetoTrials(exp, byExp[exp])...)
+	}
+	return front
+}
+
 // sortableTrialData slightly modifies the schema of the trial item to make it easier to specify sort orders
 func sortableTrialData(item *experimentsv1alpha1.TrialItem) map[string]interface{} {
-	assignments := make(map[string]int64, len(item.Assignments))
+	assignments := make(map[string]interface{}, len(item.Assignments))
 	for i := range item.Assignments {
 		if a, err := item.Assignments[i].Value.Int64(); err == nil {
 			assignments[item.Assignments[i].ParameterName] = a
+		} else {
+			assignments[item.Assignments[i].ParameterName] = item.Assignments[i].Value.String()
 		}
 	}
 
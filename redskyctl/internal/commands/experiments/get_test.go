@@ -0,0 +1,106 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"encoding/json"
+	"testing"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterAndSortTrials(t *testing.T) {
+	trial := func(n int64, status experimentsv1alpha1.TrialStatus, replicas string, cost float64) experimentsv1alpha1.TrialItem {
+		return experimentsv1alpha1.TrialItem{
+			Number: n,
+			Status: status,
+			TrialAssignments: experimentsv1alpha1.TrialAssignments{
+				Assignments: []experimentsv1alpha1.Assignment{{ParameterName: "replicas", Value: json.Number(replicas)}},
+			},
+			TrialValues: experimentsv1alpha1.TrialValues{
+				Values: []experimentsv1alpha1.Value{{MetricName: "cost", Value: cost}},
+			},
+		}
+	}
+
+	cases := []struct {
+		desc     string
+		opts     GetOptions
+		expected []int64
+		err      bool
+	}{
+		{
+			desc:     "unsorted",
+			expected: []int64{1, 2, 3, 4},
+		},
+		{
+			desc:     "metric ascending",
+			opts:     GetOptions{SortBy: "cost"},
+			expected: []int64{2, 4, 1, 3},
+		},
+		{
+			desc:     "metric descending",
+			opts:     GetOptions{SortBy: "cost", SortOrder: "desc"},
+			expected: []int64{3, 1, 4, 2},
+		},
+		{
+			desc:     "parameter",
+			opts:     GetOptions{SortBy: "replicas"},
+			expected: []int64{3, 2, 1, 4},
+		},
+		{
+			desc:     "field selector status",
+			opts:     GetOptions{FieldSelector: "status=completed"},
+			expected: []int64{1, 2, 3},
+		},
+		{
+			desc:     "field selector parameter",
+			opts:     GetOptions{FieldSelector: "assignments.replicas!=2"},
+			expected: []int64{1, 3, 4},
+		},
+		{
+			desc: "invalid sort order",
+			opts: GetOptions{SortBy: "cost", SortOrder: "sideways"},
+			err:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			l := &experimentsv1alpha1.TrialList{
+				Trials: []experimentsv1alpha1.TrialItem{
+					trial(1, experimentsv1alpha1.TrialCompleted, "3", 1.5),
+					trial(2, experimentsv1alpha1.TrialCompleted, "2", 0.5),
+					trial(3, experimentsv1alpha1.TrialCompleted, "1", 2.5),
+					trial(4, experimentsv1alpha1.TrialFailed, "4", 1.0),
+				},
+			}
+			err := c.opts.filterAndSortTrials(l)
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				var actual []int64
+				for i := range l.Trials {
+					actual = append(actual, l.Trials[i].Number)
+				}
+				assert.Equal(t, c.expected, actual)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ package experiments
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
@@ -41,6 +42,17 @@ func NewLabelCommand(o *LabelOptions) *cobra.Command {
 		Short: "Label a Red Sky resource",
 		Long:  "Label Red Sky resources on the remote server",
 
+		Example: `# Mark trial 3 of "my-experiment" for further investigation
+redskyctl label trial my-experiment/3 investigate=true
+
+# Remove the label again
+redskyctl label trial my-experiment/3 investigate-
+
+# List the marked trials
+redskyctl get trials my-experiment -l investigate=true`,
+
+		ValidArgsFunction: o.completeNames,
+
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			commander.SetStreams(&o.IOStreams, cmd)
 			if err := commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd); err != nil {
@@ -53,7 +65,7 @@ func NewLabelCommand(o *LabelOptions) *cobra.Command {
 
 	_ = cmd.MarkZshCompPositionalArgumentWords(1, validTypes()...)
 
-	o.Printer = &verbPrinter{verb: "labeled"}
+	o.Printer = &verbPrinter{verb: commander.MsgLabeled, output: &o.Output}
 	commander.ExitOnError(cmd)
 	return cmd
 }
@@ -130,24 +142,23 @@ func (o *LabelOptions) labelTrials(ctx context.Context, numbers map[experimentsv
 
 		// Note that you can only label completed trials
 		q := &experimentsv1alpha1.TrialListQuery{Status: []experimentsv1alpha1.TrialStatus{experimentsv1alpha1.TrialCompleted}}
-		tl, err := o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, q)
-		if err != nil {
-			return err
-		}
-
 		var labeled int
-		for i := range tl.Trials {
-			if hasTrialNumber(&tl.Trials[i], nums) {
-				t := tl.Trials[i]
-				t.Experiment = &exp
-				if err := o.ExperimentsAPI.LabelTrial(ctx, t.LabelsURL, experimentsv1alpha1.TrialLabels{Labels: o.Labels}); err != nil {
-					return err
-				}
-				if err := o.Printer.PrintObj(&t, o.Out); err != nil {
-					return err
-				}
-				labeled++
+		err = experimentsv1alpha1.ForEachTrial(ctx, o.ExperimentsAPI, exp.TrialsURL, q, func(t *experimentsv1alpha1.TrialItem) error {
+			if !hasTrialNumber(t, nums) {
+				return nil
+			}
+			t.Experiment = &exp
+			if err := o.labelTrial(ctx, t.LabelsURL); err != nil {
+				return err
 			}
+			if err := o.Printer.PrintObj(t, o.Out); err != nil {
+				return err
+			}
+			labeled++
+			return nil
+		})
+		if err != nil {
+			return err
 		}
 
 		if len(nums) != labeled {
@@ -156,3 +167,29 @@ func (o *LabelOptions) labelTrials(ctx context.Context, numbers map[experimentsv
 	}
 	return nil
 }
+
+// labelTrial adds and removes the trial labels, an empty label value indicates the label should be removed
+func (o *LabelOptions) labelTrial(ctx context.Context, u string) error {
+	add := make(map[string]string, len(o.Labels))
+	var remove []string
+	for k, v := range o.Labels {
+		if v == "" {
+			remove = append(remove, k)
+		} else {
+			add[k] = v
+		}
+	}
+
+	if len(add) > 0 {
+		if err := o.ExperimentsAPI.AddTrialLabels(ctx, u, add); err != nil {
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		sort.Strings(remove)
+		if err := o.ExperimentsAPI.RemoveTrialLabels(ctx, u, remove...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
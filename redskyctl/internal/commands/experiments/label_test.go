@@ -0,0 +1,37 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1/fake"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelTrial(t *testing.T) {
+	api := fake.NewAPI()
+	u := "/experiments/test/trials/1/labels"
+	api.TrialLabels[u] = map[string]string{"investigate": "true", "keep": "true"}
+
+	o := &LabelOptions{Labels: map[string]string{"best": "true", "investigate": ""}}
+	o.ExperimentsAPI = api
+	if assert.NoError(t, o.labelTrial(context.TODO(), u)) {
+		assert.Equal(t, map[string]string{"best": "true", "keep": "true"}, api.Labels(u))
+	}
+}
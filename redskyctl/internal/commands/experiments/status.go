@@ -0,0 +1,279 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/server"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// StatusOptions includes the configuration for summarizing the status of an experiment
+type StatusOptions struct {
+	Options
+
+	// Name is the name of the experiment to summarize
+	Name string
+}
+
+// NewStatusCommand creates a new status command
+func NewStatusCommand(o *StatusOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status EXPERIMENT",
+		Short: "Summarize the progress of an experiment",
+		Long: "Summarize the progress of an experiment by combining the experiment status in the cluster with the " +
+			"trials recorded by the remote server; either source may be unavailable.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: o.completeExperimentName,
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.Name = args[0]
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.status),
+	}
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+// experimentSummary is the combined cluster and remote state of an experiment
+type experimentSummary struct {
+	name       string
+	phase      string
+	budget     int64
+	active     int
+	completed  int
+	failed     int
+	best       []bestValue
+	conditions []redskyv1beta1.ExperimentCondition
+	duration   time.Duration
+	eta        *time.Time
+	usage      corev1.ResourceList
+	cost       string
+}
+
+// bestValue is the best observed value of a single metric
+type bestValue struct {
+	metric string
+	value  float64
+	number int64
+}
+
+func (o *StatusOptions) status(ctx context.Context) error {
+	// The cluster state is optional since the remote server may be used without access to the cluster
+	clusterExperiment := o.getClusterExperiment(ctx)
+
+	// Prefer the remote server for the experiment and trials, falling back to the cluster
+	exp, trials, err := o.getRemoteTrials(ctx)
+	if err != nil {
+		if clusterExperiment == nil {
+			return err
+		}
+		_, exp = server.FromCluster(clusterExperiment)
+		if trials, err = o.getClusterTrials(ctx, clusterExperiment); err != nil {
+			return err
+		}
+	}
+
+	s := summarizeExperiment(exp, trials)
+	s.name = o.Name
+	if clusterExperiment != nil {
+		s.phase = clusterExperiment.Status.Phase
+		s.conditions = clusterExperiment.Status.Conditions
+		if d := clusterExperiment.Status.AverageTrialDuration; d != nil {
+			s.duration = d.Duration
+		}
+		if t := clusterExperiment.Status.EstimatedCompletionTime; t != nil {
+			s.eta = &t.Time
+		}
+		s.usage = clusterExperiment.Status.ResourceHours
+		s.cost = clusterExperiment.Status.Cost
+	}
+	return s.write(o.Out)
+}
+
+// getClusterExperiment returns the experiment from the cluster or nil if it cannot be found
+func (o *StatusOptions) getClusterExperiment(ctx context.Context) *redskyv1beta1.Experiment {
+	get, err := o.Config.Kubectl(ctx, "get", "experiments.v1beta1.redskyops.dev", o.Name, "--output", "json")
+	if err != nil {
+		return nil
+	}
+	data, err := get.Output()
+	if err != nil {
+		return nil
+	}
+	exp := &redskyv1beta1.Experiment{}
+	if err := json.Unmarshal(data, exp); err != nil {
+		return nil
+	}
+	return exp
+}
+
+// getRemoteTrials returns the experiment and trials from the remote server
+func (o *StatusOptions) getRemoteTrials(ctx context.Context) (*experimentsv1alpha1.Experiment, []experimentsv1alpha1.TrialItem, error) {
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName(o.Name))
+	if err != nil {
+		return nil, nil, err
+	}
+	if exp.TrialsURL == "" {
+		return &exp, nil, nil
+	}
+
+	q := &experimentsv1alpha1.TrialListQuery{
+		Status: []experimentsv1alpha1.TrialStatus{experimentsv1alpha1.TrialStaged, experimentsv1alpha1.TrialActive, experimentsv1alpha1.TrialCompleted, experimentsv1alpha1.TrialFailed},
+	}
+	tl, err := o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &exp, tl.Trials, nil
+}
+
+// getClusterTrials returns the trials of an experiment from the cluster; note that only trials which have not yet
+// been cleaned up are included
+func (o *StatusOptions) getClusterTrials(ctx context.Context, exp *redskyv1beta1.Experiment) ([]experimentsv1alpha1.TrialItem, error) {
+	tl := &redskyv1beta1.TrialList{}
+	if err := o.kubectlJSON(ctx, tl, "get", "trials.v1beta1.redskyops.dev", "--namespace", exp.Namespace, "--selector", redskyv1beta1.LabelExperiment+"="+exp.Name); err != nil {
+		return nil, err
+	}
+
+	w := newTrialWatch()
+	trials := make([]experimentsv1alpha1.TrialItem, 0, len(tl.Items))
+	for i := range tl.Items {
+		trials = append(trials, clusterTrialItem(&tl.Items[i], w.trialNumber(tl.Items[i].Name)))
+	}
+	return trials, nil
+}
+
+// summarizeExperiment computes the trial counts, budget and best metric values of an experiment
+func summarizeExperiment(exp *experimentsv1alpha1.Experiment, trials []experimentsv1alpha1.TrialItem) *experimentSummary {
+	s := &experimentSummary{}
+
+	for _, opt := range exp.Optimization {
+		if opt.Name == experimentsv1alpha1.OptimizationExperimentBudget {
+			s.budget, _ = strconv.ParseInt(opt.Value, 10, 64)
+		}
+	}
+
+	for i := range trials {
+		switch trials[i].Status {
+		case experimentsv1alpha1.TrialStaged, experimentsv1alpha1.TrialActive:
+			s.active++
+		case experimentsv1alpha1.TrialCompleted:
+			s.completed++
+		case experimentsv1alpha1.TrialFailed:
+			s.failed++
+		}
+	}
+
+	for _, m := range exp.Metrics {
+		var best *bestValue
+		for i := range trials {
+			if trials[i].Status != experimentsv1alpha1.TrialCompleted {
+				continue
+			}
+			for _, v := range trials[i].Values {
+				if v.MetricName != m.Name {
+					continue
+				}
+				if best == nil || (m.Minimize && v.Value < best.value) || (!m.Minimize && v.Value > best.value) {
+					best = &bestValue{metric: m.Name, value: v.Value, number: trials[i].Number}
+				}
+			}
+		}
+		if best != nil {
+			s.best = append(s.best, *best)
+		}
+	}
+
+	return s
+}
+
+// write prints the summary
+func (s *experimentSummary) write(out io.Writer) error {
+	phase := s.phase
+	if phase == "" {
+		phase = "<unknown>"
+	}
+
+	used := int64(s.completed + s.failed)
+	budget := fmt.Sprintf("%d trials used, no budget", used)
+	if s.budget > 0 {
+		remaining := s.budget - used
+		if remaining < 0 {
+			remaining = 0
+		}
+		budget = fmt.Sprintf("%d of %d trials used, %d remaining", used, s.budget, remaining)
+	}
+
+	_, _ = fmt.Fprintf(out, "Name:         %s\n", s.name)
+	_, _ = fmt.Fprintf(out, "Phase:        %s\n", phase)
+	_, _ = fmt.Fprintf(out, "Budget:       %s\n", budget)
+	_, _ = fmt.Fprintf(out, "Trials:       %d active, %d completed, %d failed\n", s.active, s.completed, s.failed)
+	if s.duration > 0 {
+		_, _ = fmt.Fprintf(out, "Duration:     %s per trial (average)\n", s.duration)
+	}
+	if s.eta != nil {
+		_, _ = fmt.Fprintf(out, "Completion:   %s (estimated)\n", s.eta.UTC().Format(time.RFC3339))
+	}
+	if len(s.usage) > 0 {
+		names := make([]string, 0, len(s.usage))
+		for name := range s.usage {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		usage := make([]string, 0, len(names))
+		for _, name := range names {
+			q := s.usage[corev1.ResourceName(name)]
+			usage = append(usage, fmt.Sprintf("%s %s-hours", q.String(), name))
+		}
+		_, _ = fmt.Fprintf(out, "Usage:        %s\n", strings.Join(usage, ", "))
+	}
+	if s.cost != "" {
+		_, _ = fmt.Fprintf(out, "Cost:         %s\n", s.cost)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	if len(s.best) > 0 {
+		_, _ = fmt.Fprintln(w, "Best Values:")
+		for _, b := range s.best {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t(%s-%03d)\n", b.metric, strconv.FormatFloat(b.value, 'f', -1, 64), s.name, b.number)
+		}
+	}
+	if len(s.conditions) > 0 {
+		_, _ = fmt.Fprintln(w, "Conditions:")
+		for _, c := range s.conditions {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", strings.TrimPrefix(string(c.Type), "redskyops.dev/"), c.Status, c.Message)
+		}
+	}
+	return w.Flush()
+}
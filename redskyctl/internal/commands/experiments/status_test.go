@@ -0,0 +1,121 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestExperimentSummary(t *testing.T) {
+	trial := func(n int64, status experimentsv1alpha1.TrialStatus, cost, throughput float64) experimentsv1alpha1.TrialItem {
+		return experimentsv1alpha1.TrialItem{
+			Number: n,
+			Status: status,
+			TrialValues: experimentsv1alpha1.TrialValues{
+				Values: []experimentsv1alpha1.Value{{MetricName: "cost", Value: cost}, {MetricName: "throughput", Value: throughput}},
+			},
+		}
+	}
+
+	exp := &experimentsv1alpha1.Experiment{
+		Optimization: []experimentsv1alpha1.Optimization{{Name: experimentsv1alpha1.OptimizationExperimentBudget, Value: "10"}},
+		Metrics:      []experimentsv1alpha1.Metric{{Name: "cost", Minimize: true}, {Name: "throughput"}},
+	}
+	trials := []experimentsv1alpha1.TrialItem{
+		trial(1, experimentsv1alpha1.TrialCompleted, 10, 100),
+		trial(2, experimentsv1alpha1.TrialCompleted, 8, 90),
+		trial(3, experimentsv1alpha1.TrialFailed, 1, 1000),
+		trial(4, experimentsv1alpha1.TrialCompleted, 12, 120),
+		trial(5, experimentsv1alpha1.TrialActive, 0, 0),
+	}
+
+	eta := time.Date(2020, 1, 1, 5, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		desc     string
+		exp      *experimentsv1alpha1.Experiment
+		phase    string
+		duration time.Duration
+		eta      *time.Time
+		usage    corev1.ResourceList
+		cost     string
+		expected string
+	}{
+		{
+			desc:     "budget",
+			exp:      exp,
+			phase:    "Running",
+			duration: 5*time.Minute + 30*time.Second,
+			eta:      &eta,
+			usage: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("12Gi"),
+				corev1.ResourceCPU:    resource.MustParse("1500m"),
+			},
+			cost: "4.25",
+			expected: `Name:         test
+Phase:        Running
+Budget:       4 of 10 trials used, 6 remaining
+Trials:       1 active, 3 completed, 1 failed
+Duration:     5m30s per trial (average)
+Completion:   2020-01-01T05:00:00Z (estimated)
+Usage:        1500m cpu-hours, 12Gi memory-hours
+Cost:         4.25
+Best Values:
+  cost         8     (test-002)
+  throughput   120   (test-004)
+Conditions:
+  converged   False   
+`,
+		},
+		{
+			desc: "no budget",
+			exp:  &experimentsv1alpha1.Experiment{},
+			expected: `Name:         test
+Phase:        <unknown>
+Budget:       4 trials used, no budget
+Trials:       1 active, 3 completed, 1 failed
+Conditions:
+  converged   False   
+`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			s := summarizeExperiment(c.exp, trials)
+			s.name = "test"
+			s.phase = c.phase
+			s.duration = c.duration
+			s.eta = c.eta
+			s.usage = c.usage
+			s.cost = c.cost
+			s.conditions = []redskyv1beta1.ExperimentCondition{{Type: redskyv1beta1.ExperimentConverged, Status: corev1.ConditionFalse}}
+
+			var buf bytes.Buffer
+			if assert.NoError(t, s.write(&buf)) {
+				assert.Equal(t, c.expected, buf.String())
+			}
+		})
+	}
+}
@@ -18,15 +18,21 @@ package experiments
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
 	"strconv"
+	"strings"
 
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/experiment"
+	"github.com/redskyops/redskyops-controller/internal/server"
 	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // TODO Accept suggestion inputs from standard input, what formats?
@@ -38,6 +44,7 @@ type SuggestOptions struct {
 	Assignments      map[string]string
 	AllowInteractive bool
 	DefaultBehavior  string
+	Cluster          bool
 }
 
 // NewSuggestCommand creates a new suggestion command
@@ -45,9 +52,17 @@ func NewSuggestCommand(o *SuggestOptions) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "suggest NAME",
 		Short: "Suggest assignments",
-		Long:  "Suggest assignments for a new trial run",
+		Long: "Suggest assignments for a new trial run. Integer parameters also accept Kubernetes quantities: millicore " +
+			"values (e.g. 500m) are converted to millis and binary sizes (e.g. 1Gi) are converted to mebibytes.",
 
-		Args: cobra.ExactArgs(1),
+		Example: `# Force evaluation of a known baseline configuration
+redskyctl suggest my-experiment --assign cpu=500m --assign memory=1Gi
+
+# Create the trial directly in the cluster for an experiment which is not connected to the Red Sky API
+redskyctl suggest my-experiment --cluster --assign cpu=500m --assign memory=1Gi`,
+
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: o.completeExperimentName,
 
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			o.Names = []name{{Type: typeExperiment, Name: args[0]}}
@@ -60,12 +75,17 @@ func NewSuggestCommand(o *SuggestOptions) *cobra.Command {
 	cmd.Flags().StringToStringVarP(&o.Assignments, "assign", "A", nil, "Assign an explicit value to a parameter.")
 	cmd.Flags().BoolVar(&o.AllowInteractive, "interactive", false, "Allow interactive prompts for unspecified parameter assignments.")
 	cmd.Flags().StringVar(&o.DefaultBehavior, "default", "", "Select the behavior for default values; one of: none|min|max|rand.")
+	cmd.Flags().BoolVar(&o.Cluster, "cluster", false, "Create the trial directly in the cluster instead of through the Red Sky API.")
 
 	commander.ExitOnError(cmd)
 	return cmd
 }
 
 func (o *SuggestOptions) suggest(ctx context.Context) error {
+	if o.Cluster {
+		return o.suggestCluster(ctx)
+	}
+
 	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, o.Names[0].experimentName())
 	if err != nil {
 		return err
@@ -80,6 +100,46 @@ func (o *SuggestOptions) suggest(ctx context.Context) error {
 	return err
 }
 
+// suggestCluster creates a trial resource in the cluster using the suggested assignments, this is only possible for
+// experiments which are not connected to the Red Sky API (otherwise the server would not know about the trial)
+func (o *SuggestOptions) suggestCluster(ctx context.Context) error {
+	exp := &redskyv1beta1.Experiment{}
+	if err := o.kubectlJSON(ctx, exp, "get", "experiments.v1beta1.redskyops.dev", o.Names[0].Name); err != nil {
+		return err
+	}
+	if exp.Annotations[redskyv1beta1.AnnotationExperimentURL] != "" {
+		return fmt.Errorf("experiment %s is connected to the Red Sky API, suggest assignments without --cluster", exp.Name)
+	}
+
+	_, serverExperiment := server.FromCluster(exp)
+	ta, err := o.SuggestAssignments(serverExperiment)
+	if err != nil {
+		return err
+	}
+
+	t := &redskyv1beta1.Trial{}
+	experiment.PopulateTrialFromTemplate(exp, t)
+	server.ToClusterTrial(t, ta, exp.Spec.Parameters)
+	if t.Namespace == "" {
+		t.Namespace = exp.Namespace
+	}
+
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	// Use create (not apply) since the trial only has a generated name
+	kubectlCreate, err := o.Config.Kubectl(ctx, "create", "-f", "-")
+	if err != nil {
+		return err
+	}
+	kubectlCreate.Stdout = o.Out
+	kubectlCreate.Stderr = o.ErrOut
+	kubectlCreate.Stdin = bytes.NewReader(b)
+	return kubectlCreate.Run()
+}
+
 // SuggestAssignments creates new assignments object based on the parameters of the supplied experiment
 func (o *SuggestOptions) SuggestAssignments(exp *experimentsv1alpha1.Experiment) (*experimentsv1alpha1.TrialAssignments, error) {
 	ta := &experimentsv1alpha1.TrialAssignments{}
@@ -97,7 +157,7 @@ func (o *SuggestOptions) SuggestAssignments(exp *experimentsv1alpha1.Experiment)
 func (o *SuggestOptions) assign(p *experimentsv1alpha1.Parameter) (json.Number, error) {
 	// Look for explicit assignments
 	if a, ok := o.Assignments[p.Name]; ok {
-		return checkValue(p, json.Number(a))
+		return checkValue(p, quantityValue(p, a))
 	}
 
 	// Compute a default value (may be needed for interactive prompt)
@@ -124,8 +184,16 @@ func (o *SuggestOptions) defaultValue(p *experimentsv1alpha1.Parameter) (*json.N
 	case "none":
 		return nil, nil
 	case "min":
+		if p.Type == experimentsv1alpha1.ParameterTypeCategorical && len(p.Values) > 0 {
+			v := json.Number(p.Values[0])
+			return &v, nil
+		}
 		return &p.Bounds.Min, nil
 	case "max":
+		if p.Type == experimentsv1alpha1.ParameterTypeCategorical && len(p.Values) > 0 {
+			v := json.Number(p.Values[len(p.Values)-1])
+			return &v, nil
+		}
 		return &p.Bounds.Max, nil
 	case "rand":
 		return randomValue(p)
@@ -135,10 +203,14 @@ func (o *SuggestOptions) defaultValue(p *experimentsv1alpha1.Parameter) (*json.N
 }
 
 func (o *SuggestOptions) assignInteractive(p *experimentsv1alpha1.Parameter, def *json.Number) (json.Number, error) {
+	domain := fmt.Sprintf("[%v,%v]", p.Bounds.Min, p.Bounds.Max)
+	if p.Type == experimentsv1alpha1.ParameterTypeCategorical {
+		domain = fmt.Sprintf("{%s}", strings.Join(p.Values, ","))
+	}
 	if def != nil {
-		_, _ = fmt.Fprintf(o.ErrOut, "Assignment for %v parameter '%s' [%v,%v] (%v): ", p.Type, p.Name, p.Bounds.Min, p.Bounds.Max, *def)
+		_, _ = fmt.Fprintf(o.ErrOut, "Assignment for %v parameter '%s' %s (%v): ", p.Type, p.Name, domain, *def)
 	} else {
-		_, _ = fmt.Fprintf(o.ErrOut, "Assignment for %v parameter '%s' [%v,%v]: ", p.Type, p.Name, p.Bounds.Min, p.Bounds.Max)
+		_, _ = fmt.Fprintf(o.ErrOut, "Assignment for %v parameter '%s' %s: ", p.Type, p.Name, domain)
 	}
 
 	s := bufio.NewScanner(o.In)
@@ -153,7 +225,7 @@ func (o *SuggestOptions) assignInteractive(p *experimentsv1alpha1.Parameter, def
 		if text == "" && def != nil {
 			return *def, nil
 		}
-		number, err := checkValue(p, json.Number(text))
+		number, err := checkValue(p, quantityValue(p, text))
 		if err != nil {
 			continue
 		}
@@ -166,6 +238,29 @@ func (o *SuggestOptions) assignInteractive(p *experimentsv1alpha1.Parameter, def
 	return "0", fmt.Errorf("no assignment for parameter: %s", p.Name)
 }
 
+// quantityValue converts Kubernetes quantities assigned to integer parameters, matching the conventional use of
+// "{{ .Values.cpu }}m" and "{{ .Values.memory }}Mi" in patch templates; other values are returned unchanged
+func quantityValue(p *experimentsv1alpha1.Parameter, s string) json.Number {
+	if p.Type != experimentsv1alpha1.ParameterTypeInteger {
+		return json.Number(s)
+	}
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return json.Number(s)
+	}
+
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return json.Number(s)
+	}
+	switch {
+	case strings.HasSuffix(s, "m"):
+		return json.Number(strconv.FormatInt(q.MilliValue(), 10))
+	case q.Format == resource.BinarySI && q.Value()%(1<<20) == 0:
+		return json.Number(strconv.FormatInt(q.Value()/(1<<20), 10))
+	}
+	return json.Number(s)
+}
+
 func checkValue(p *experimentsv1alpha1.Parameter, n json.Number) (json.Number, error) {
 	switch p.Type {
 	case experimentsv1alpha1.ParameterTypeInteger:
@@ -192,6 +287,13 @@ func checkValue(p *experimentsv1alpha1.Parameter, n json.Number) (json.Number, e
 		if v < min || v > max {
 			return "0.0", fmt.Errorf("value is not within experiment bounds [%f-%f]: %f", min, max, v)
 		}
+	case experimentsv1alpha1.ParameterTypeCategorical:
+		for _, v := range p.Values {
+			if v == n.String() {
+				return n, nil
+			}
+		}
+		return "", fmt.Errorf("value is not one of the experiment values [%s]: %s", strings.Join(p.Values, ", "), n)
 	}
 	return n, nil
 }
@@ -212,6 +314,12 @@ func randomValue(p *experimentsv1alpha1.Parameter) (*json.Number, error) {
 		}
 		r := json.Number(strconv.FormatFloat(rand.Float64()*max+min, 'f', -1, 64))
 		return &r, nil
+	case experimentsv1alpha1.ParameterTypeCategorical:
+		if len(p.Values) == 0 {
+			return nil, fmt.Errorf("no values for categorical parameter: %s", p.Name)
+		}
+		r := json.Number(p.Values[rand.Intn(len(p.Values))])
+		return &r, nil
 	}
 	return nil, fmt.Errorf("unable to produce random %v", p.Type)
 }
@@ -0,0 +1,51 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"encoding/json"
+	"testing"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantityValue(t *testing.T) {
+	integer := &experimentsv1alpha1.Parameter{Type: experimentsv1alpha1.ParameterTypeInteger}
+	categorical := &experimentsv1alpha1.Parameter{Type: experimentsv1alpha1.ParameterTypeCategorical}
+
+	cases := []struct {
+		desc      string
+		parameter *experimentsv1alpha1.Parameter
+		value     string
+		expected  json.Number
+	}{
+		{desc: "integer", parameter: integer, value: "250", expected: "250"},
+		{desc: "millicores", parameter: integer, value: "500m", expected: "500"},
+		{desc: "cores", parameter: integer, value: "1500m", expected: "1500"},
+		{desc: "gibibytes", parameter: integer, value: "1Gi", expected: "1024"},
+		{desc: "mebibytes", parameter: integer, value: "512Mi", expected: "512"},
+		{desc: "partial mebibytes", parameter: integer, value: "512Ki", expected: "512Ki"},
+		{desc: "not a quantity", parameter: integer, value: "large", expected: "large"},
+		{desc: "categorical", parameter: categorical, value: "1Gi", expected: "1Gi"},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.expected, quantityValue(c.parameter, c.value))
+		})
+	}
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/server"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+)
+
+// trialWatch tracks the last status reported for each trial so that only changes are printed
+type trialWatch struct {
+	// status is the last printed status of each trial number
+	status map[int64]experimentsv1alpha1.TrialStatus
+	// numbers are the trial numbers assigned to cluster trials whose names do not end with a number
+	numbers map[string]int64
+	// printed is true once the first list (with headers) has been printed
+	printed bool
+}
+
+func newTrialWatch() *trialWatch {
+	return &trialWatch{
+		status:  make(map[int64]experimentsv1alpha1.TrialStatus),
+		numbers: make(map[string]int64),
+	}
+}
+
+// changed returns the trials that were not seen before or whose status has changed since they were last seen
+func (w *trialWatch) changed(trials []experimentsv1alpha1.TrialItem) []experimentsv1alpha1.TrialItem {
+	var changed []experimentsv1alpha1.TrialItem
+	for i := range trials {
+		if s, ok := w.status[trials[i].Number]; ok && s == trials[i].Status {
+			continue
+		}
+		w.status[trials[i].Number] = trials[i].Status
+		changed = append(changed, trials[i])
+	}
+	return changed
+}
+
+// trialNumber returns the number of a cluster trial, names without a numeric suffix are numbered in the order they are seen
+func (w *trialWatch) trialNumber(name string) int64 {
+	if i := strings.LastIndex(name, "-"); i >= 0 {
+		if n, err := strconv.ParseInt(name[i+1:], 10, 64); err == nil {
+			return n
+		}
+	}
+	if n, ok := w.numbers[name]; ok {
+		return n
+	}
+	n := int64(len(w.numbers))
+	w.numbers[name] = n
+	return n
+}
+
+// print writes the changed trials, only the first list printed includes headers
+func (w *trialWatch) print(printer commander.ResourcePrinter, out io.Writer, l *experimentsv1alpha1.TrialList) error {
+	l.Trials = w.changed(l.Trials)
+	if w.printed {
+		if len(l.Trials) == 0 {
+			return nil
+		}
+		printer = commander.WithoutHeaders(printer)
+	}
+	w.printed = true
+	return printer.PrintObj(l, out)
+}
+
+// watchTrialList streams trial rows as the trials of an experiment change
+func (o *GetOptions) watchTrialList(ctx context.Context, name experimentsv1alpha1.ExperimentName) error {
+	if o.Cluster {
+		return o.watchClusterTrials(ctx, name)
+	}
+	return o.pollTrialList(ctx, name, o.trialListQuery())
+}
+
+// pollTrialList periodically fetches the trial list from the remote server; requests are made conditional using the
+// "Last-Modified" and "ETag" validators of the previous response so an unchanged list is not re-sent
+func (o *GetOptions) pollTrialList(ctx context.Context, name experimentsv1alpha1.ExperimentName, q *experimentsv1alpha1.TrialListQuery) error {
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if exp.TrialsURL == "" {
+		return fmt.Errorf("experiment %s does not have any trials", name)
+	}
+
+	w := newTrialWatch()
+	interval := o.WatchInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		l, err := o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, q)
+		if err != nil {
+			return err
+		}
+
+		l.Experiment = &exp
+		for i := range l.Trials {
+			l.Trials[i].Experiment = &exp
+		}
+
+		if err := o.filterAndSortTrials(&l); err != nil {
+			return err
+		}
+
+		if err := w.print(o.Printer, o.Out, &l); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchClusterTrials uses a Kubernetes watch on the trials of the experiment, this works for experiments which are not
+// connected to the remote server as well
+func (o *GetOptions) watchClusterTrials(ctx context.Context, name experimentsv1alpha1.ExperimentName) error {
+	exp := &redskyv1beta1.Experiment{}
+	if err := o.kubectlJSON(ctx, exp, "get", "experiments.v1beta1.redskyops.dev", name.Name()); err != nil {
+		return err
+	}
+	_, serverExperiment := server.FromCluster(exp)
+	serverExperiment.DisplayName = exp.Name
+
+	kubectlWatch, err := o.Config.Kubectl(ctx, "get", "trials.v1beta1.redskyops.dev",
+		"--namespace", exp.Namespace,
+		"--selector", redskyv1beta1.LabelExperiment+"="+exp.Name,
+		"--watch", "--output", "json")
+	if err != nil {
+		return err
+	}
+	kubectlWatch.Stderr = o.ErrOut
+	stdout, err := kubectlWatch.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := kubectlWatch.Start(); err != nil {
+		return err
+	}
+
+	w := newTrialWatch()
+	dec := json.NewDecoder(stdout)
+	for {
+		t := &redskyv1beta1.Trial{}
+		if err := dec.Decode(t); err == io.EOF || ctx.Err() != nil {
+			break
+		} else if err != nil {
+			_ = kubectlWatch.Process.Kill()
+			_ = kubectlWatch.Wait()
+			return err
+		}
+
+		item := clusterTrialItem(t, w.trialNumber(t.Name))
+		item.Experiment = serverExperiment
+		l := &experimentsv1alpha1.TrialList{Experiment: serverExperiment, Trials: []experimentsv1alpha1.TrialItem{item}}
+		if err := o.filterAndSortTrials(l); err != nil {
+			return err
+		}
+		if len(l.Trials) == 0 {
+			continue
+		}
+
+		if err := w.print(o.Printer, o.Out, l); err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return kubectlWatch.Wait()
+}
+
+// clusterTrialItem converts a Kubernetes trial into the trial representation used by the remote server
+func clusterTrialItem(t *redskyv1beta1.Trial, number int64) experimentsv1alpha1.TrialItem {
+	item := experimentsv1alpha1.TrialItem{Number: number, Status: experimentsv1alpha1.TrialActive}
+
+	for _, a := range t.Spec.Assignments {
+		item.Assignments = append(item.Assignments, experimentsv1alpha1.Assignment{
+			ParameterName: a.Name,
+			Value:         json.Number(a.Value.String()),
+		})
+	}
+
+	if trial.IsFinished(t) {
+		item.TrialValues = *server.FromClusterTrial(t)
+		item.Status = experimentsv1alpha1.TrialCompleted
+		if item.Failed {
+			item.Status = experimentsv1alpha1.TrialFailed
+		}
+	}
+
+	for k, v := range t.Labels {
+		if k == redskyv1beta1.LabelExperiment {
+			continue
+		}
+		if item.Labels == nil {
+			item.Labels = make(map[string]string)
+		}
+		item.Labels[k] = v
+	}
+
+	return item
+}
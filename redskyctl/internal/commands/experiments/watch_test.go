@@ -0,0 +1,135 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiments
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTrialWatchChanged(t *testing.T) {
+	item := func(n int64, s experimentsv1alpha1.TrialStatus) experimentsv1alpha1.TrialItem {
+		return experimentsv1alpha1.TrialItem{Number: n, Status: s}
+	}
+
+	w := newTrialWatch()
+	cases := []struct {
+		desc     string
+		trials   []experimentsv1alpha1.TrialItem
+		expected []int64
+	}{
+		{
+			desc:     "initial",
+			trials:   []experimentsv1alpha1.TrialItem{item(1, experimentsv1alpha1.TrialCompleted), item(2, experimentsv1alpha1.TrialActive)},
+			expected: []int64{1, 2},
+		},
+		{
+			desc:   "unchanged",
+			trials: []experimentsv1alpha1.TrialItem{item(1, experimentsv1alpha1.TrialCompleted), item(2, experimentsv1alpha1.TrialActive)},
+		},
+		{
+			desc:     "completed and new",
+			trials:   []experimentsv1alpha1.TrialItem{item(1, experimentsv1alpha1.TrialCompleted), item(2, experimentsv1alpha1.TrialFailed), item(3, experimentsv1alpha1.TrialActive)},
+			expected: []int64{2, 3},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			var actual []int64
+			for _, ti := range w.changed(c.trials) {
+				actual = append(actual, ti.Number)
+			}
+			assert.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestClusterTrialItem(t *testing.T) {
+	w := newTrialWatch()
+	cases := []struct {
+		desc     string
+		trial    redskyv1beta1.Trial
+		expected experimentsv1alpha1.TrialItem
+	}{
+		{
+			desc: "active",
+			trial: redskyv1beta1.Trial{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "test-007",
+					Labels: map[string]string{redskyv1beta1.LabelExperiment: "test", "best": "true"},
+				},
+				Spec: redskyv1beta1.TrialSpec{
+					Assignments: []redskyv1beta1.Assignment{{Name: "replicas", Value: redskyv1beta1.NewAssignmentValue(3)}},
+				},
+			},
+			expected: experimentsv1alpha1.TrialItem{
+				Number: 7,
+				Status: experimentsv1alpha1.TrialActive,
+				Labels: map[string]string{"best": "true"},
+				TrialAssignments: experimentsv1alpha1.TrialAssignments{
+					Assignments: []experimentsv1alpha1.Assignment{{ParameterName: "replicas", Value: "3"}},
+				},
+			},
+		},
+		{
+			desc: "completed",
+			trial: redskyv1beta1.Trial{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-x7k2p"},
+				Spec: redskyv1beta1.TrialSpec{
+					Values: []redskyv1beta1.Value{{Name: "cost", Value: "1.5"}},
+				},
+				Status: redskyv1beta1.TrialStatus{
+					Conditions: []redskyv1beta1.TrialCondition{{Type: redskyv1beta1.TrialComplete, Status: corev1.ConditionTrue}},
+				},
+			},
+			expected: experimentsv1alpha1.TrialItem{
+				Number: 0,
+				Status: experimentsv1alpha1.TrialCompleted,
+				TrialValues: experimentsv1alpha1.TrialValues{
+					Values: []experimentsv1alpha1.Value{{MetricName: "cost", Value: 1.5}},
+				},
+			},
+		},
+		{
+			desc: "failed",
+			trial: redskyv1beta1.Trial{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-q9w4z"},
+				Status: redskyv1beta1.TrialStatus{
+					Conditions: []redskyv1beta1.TrialCondition{{Type: redskyv1beta1.TrialFailed, Status: corev1.ConditionTrue, Reason: "Timeout"}},
+				},
+			},
+			expected: experimentsv1alpha1.TrialItem{
+				Number: 1,
+				Status: experimentsv1alpha1.TrialFailed,
+				TrialValues: experimentsv1alpha1.TrialValues{
+					Failed:        true,
+					FailureReason: "Timeout",
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.expected, clusterTrialItem(&c.trial, w.trialNumber(c.trial.Name)))
+		})
+	}
+}
@@ -0,0 +1,326 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/template"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// BestOptions is the configuration for displaying and applying the best trial of an experiment
+type BestOptions struct {
+	Options
+
+	// Weights are the relative importance of each metric when selecting from the Pareto front
+	Weights map[string]string
+	// Apply indicates that the patches of the best trial should be applied to the cluster
+	Apply bool
+	// DryRun prints the rendered patches instead of applying them
+	DryRun bool
+	// Filename is the experiment manifest, when empty the experiment is read from the cluster
+	Filename string
+}
+
+// NewBestCommand creates a new command for displaying and applying the best trial
+func NewBestCommand(o *BestOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "best EXPERIMENT",
+		Short: "Display the best trial",
+		Long: "Display the assignments of the best completed trial of an experiment and optionally apply them to the cluster.\n\n" +
+			"A trial labeled 'best' is used if one exists. Single metric experiments otherwise use the optimal trial, " +
+			"multiple metric experiments (or any experiment when weights are specified) use the trial on the Pareto " +
+			"front with the lowest weighted sum of normalized metric values.",
+		Example: `# Display the best trial
+redskyctl best my-experiment
+
+# Favor cost twice as much as latency and permanently apply the winning configuration
+redskyctl best my-experiment --metric cost=2 --metric latency=1 --apply`,
+
+		Args: cobra.ExactArgs(1),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.ExperimentName = args[0]
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.best),
+	}
+
+	cmd.Flags().StringToStringVar(&o.Weights, "metric", nil, "Weight of a metric when selecting from the Pareto front, as `NAME=WEIGHT` (unlisted metrics have a weight of 1).")
+	cmd.Flags().BoolVar(&o.Apply, "apply", false, "Render the patches of the experiment using the best trial and apply them to the cluster.")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", false, "Print the patches which would be applied instead of applying them.")
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", o.Filename, "File that contains the experiment (defaults to reading the experiment from the cluster).")
+
+	_ = cmd.MarkFlagFilename("filename", "yml", "yaml", "json")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *BestOptions) best(ctx context.Context) error {
+	weights, err := parseWeights(o.Weights)
+	if err != nil {
+		return err
+	}
+
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName(o.ExperimentName))
+	if err != nil {
+		return err
+	}
+
+	q := &experimentsv1alpha1.TrialListQuery{Status: []experimentsv1alpha1.TrialStatus{experimentsv1alpha1.TrialCompleted}}
+	tl, err := o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, q)
+	if err != nil {
+		return err
+	}
+
+	t, err := selectBest(&exp, tl.Trials, weights)
+	if err != nil {
+		return err
+	}
+
+	// Print the assignments so they can be passed back to "suggest --assign"
+	values := make([]string, 0, len(t.Values))
+	for _, v := range t.Values {
+		values = append(values, fmt.Sprintf("%s=%s", v.MetricName, strconv.FormatFloat(v.Value, 'f', -1, 64)))
+	}
+	_, _ = fmt.Fprintf(o.Out, "# Trial %s-%03d (%s)\n", o.ExperimentName, t.Number, strings.Join(values, ", "))
+	for _, a := range t.Assignments {
+		_, _ = fmt.Fprintf(o.Out, "%s=%s\n", a.ParameterName, a.Value.String())
+	}
+
+	if !o.Apply && !o.DryRun {
+		return nil
+	}
+
+	clusterExp, err := o.clusterExperiment(ctx, o.Filename)
+	if err != nil {
+		return err
+	}
+	ct := clusterTrial(clusterExp, t)
+
+	te := template.New()
+	for i := range clusterExp.Spec.Patches {
+		p := &clusterExp.Spec.Patches[i]
+		if o.DryRun {
+			data, err := renderPatch(te, p, ct)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(o.Out, "---\n%s", data)
+			continue
+		}
+		if err := o.applyPatch(ctx, te, p, ct); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyPatch renders a patch template and applies it using kubectl
+func (o *BestOptions) applyPatch(ctx context.Context, te *template.Engine, p *redskyv1beta1.PatchTemplate, t *redskyv1beta1.Trial) error {
+	data, err := te.RenderPatch(p, t)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+
+	// Determine the target the same way the controller does, possibly extracting it from the rendered data
+	ref := &corev1.ObjectReference{}
+	if p.TargetRef != nil {
+		p.TargetRef.DeepCopyInto(ref)
+	} else if p.Type == redskyv1beta1.PatchStrategic || p.Type == "" {
+		m := &struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+			Metadata   struct {
+				Name      string `json:"name"`
+				Namespace string `json:"namespace"`
+			} `json:"metadata"`
+		}{}
+		if err := json.Unmarshal(data, m); err == nil {
+			ref.APIVersion, ref.Kind, ref.Name, ref.Namespace = m.APIVersion, m.Kind, m.Metadata.Name, m.Metadata.Namespace
+		}
+	}
+	if ref.Name == "" || ref.Kind == "" {
+		return fmt.Errorf("invalid patch reference")
+	}
+	if ref.Namespace == "" {
+		ref.Namespace = t.Namespace
+	}
+
+	patchType := p.Type
+	if patchType == "" {
+		patchType = redskyv1beta1.PatchStrategic
+	}
+
+	kubectlPatch, err := o.Config.Kubectl(ctx, "patch", kubectlResource(ref.APIVersion, ref.Kind), ref.Name,
+		"--namespace", ref.Namespace, "--type", string(patchType), "--patch", string(data))
+	if err != nil {
+		return err
+	}
+	kubectlPatch.Stdout = o.Out
+	kubectlPatch.Stderr = o.ErrOut
+	return kubectlPatch.Run()
+}
+
+// kubectlResource returns the fully qualified resource argument for kubectl, e.g. "deployment.v1.apps"
+func kubectlResource(apiVersion, kind string) string {
+	gvk := schema.FromAPIVersionAndKind(apiVersion, kind)
+	if gvk.Group == "" || gvk.Version == "" {
+		return strings.ToLower(kind)
+	}
+	return strings.ToLower(gvk.Kind) + "." + gvk.Version + "." + gvk.Group
+}
+
+// parseWeights converts the metric weight flag values
+func parseWeights(in map[string]string) (map[string]float64, error) {
+	weights := make(map[string]float64, len(in))
+	for k, v := range in {
+		w, err := strconv.ParseFloat(v, 64)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("invalid weight for metric %s: %s", k, v)
+		}
+		weights[k] = w
+	}
+	return weights, nil
+}
+
+// selectBest returns the best trial; without weights a labeled trial or the optimal trial of a single metric experiment
+// is preferred, otherwise the trial on the Pareto front with the lowest weighted sum of normalized values is used
+func selectBest(exp *experimentsv1alpha1.Experiment, trials []experimentsv1alpha1.TrialItem, weights map[string]float64) (*experimentsv1alpha1.TrialItem, error) {
+	if len(weights) == 0 {
+		if t, err := bestTrial(exp, trials); err == nil || len(exp.Metrics) == 1 {
+			return t, err
+		}
+	}
+
+	// Compute the weights and orientation of each metric
+	w := make([]float64, len(exp.Metrics))
+	known := make(map[string]bool, len(exp.Metrics))
+	for i, m := range exp.Metrics {
+		known[m.Name] = true
+		w[i] = 1
+		if v, ok := weights[m.Name]; ok {
+			w[i] = v
+		}
+	}
+	for name := range weights {
+		if !known[name] {
+			return nil, fmt.Errorf("experiment %s has no metric named %s", exp.DisplayName, name)
+		}
+	}
+
+	// Collect the (minimized) metric values of each trial with a complete set of values
+	var candidates []int
+	var values [][]float64
+	for i := range trials {
+		if v, ok := metricVector(exp, &trials[i]); ok {
+			candidates = append(candidates, i)
+			values = append(values, v)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("experiment %s has no completed trials", exp.DisplayName)
+	}
+
+	// Restrict the candidates to the Pareto front
+	var front []int
+	for i := range values {
+		dominated := false
+		for j := range values {
+			if i != j && dominates(values[j], values[i]) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front = append(front, i)
+		}
+	}
+
+	// Normalize each metric over the front so the weights are comparable
+	min, max := make([]float64, len(w)), make([]float64, len(w))
+	for k := range w {
+		min[k], max[k] = math.Inf(1), math.Inf(-1)
+		for _, i := range front {
+			min[k], max[k] = math.Min(min[k], values[i][k]), math.Max(max[k], values[i][k])
+		}
+	}
+	score := func(i int) float64 {
+		var s float64
+		for k := range w {
+			if max[k] > min[k] {
+				s += w[k] * (values[i][k] - min[k]) / (max[k] - min[k])
+			}
+		}
+		return s
+	}
+
+	sort.SliceStable(front, func(a, b int) bool { return score(front[a]) < score(front[b]) })
+	return &trials[candidates[front[0]]], nil
+}
+
+// metricVector returns the values of the experiment metrics in order, negated for maximized metrics
+func metricVector(exp *experimentsv1alpha1.Experiment, t *experimentsv1alpha1.TrialItem) ([]float64, bool) {
+	v := make([]float64, len(exp.Metrics))
+	for i, m := range exp.Metrics {
+		found := false
+		for _, tv := range t.Values {
+			if tv.MetricName == m.Name {
+				v[i], found = tv.Value, true
+			}
+		}
+		if !found {
+			return nil, false
+		}
+		if !m.Minimize {
+			v[i] = -v[i]
+		}
+	}
+	return v, true
+}
+
+// dominates checks to see if the first set of (minimized) values is no worse than the second for every metric and
+// strictly better for at least one
+func dominates(a, b []float64) bool {
+	better := false
+	for i := range a {
+		if a[i] > b[i] {
+			return false
+		}
+		if a[i] < b[i] {
+			better = true
+		}
+	}
+	return better
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"testing"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectBest(t *testing.T) {
+	trial := func(n int64, cost, duration float64) experimentsv1alpha1.TrialItem {
+		return experimentsv1alpha1.TrialItem{
+			Number:      n,
+			TrialValues: experimentsv1alpha1.TrialValues{Values: []experimentsv1alpha1.Value{{MetricName: "cost", Value: cost}, {MetricName: "duration", Value: duration}}},
+		}
+	}
+	trials := []experimentsv1alpha1.TrialItem{trial(1, 5, 10), trial(2, 3, 20), trial(3, 4, 15), trial(4, 2, 30), trial(5, 6, 25)}
+	trials[3].Labels = map[string]string{BestLabel: "true"}
+
+	both := []experimentsv1alpha1.Metric{{Name: "cost", Minimize: true}, {Name: "duration", Minimize: true}}
+
+	cases := []struct {
+		desc    string
+		metrics []experimentsv1alpha1.Metric
+		weights map[string]float64
+		number  int64
+		err     bool
+	}{
+		{desc: "Labeled", metrics: both, number: 4},
+		{desc: "Favor cost", metrics: both, weights: map[string]float64{"cost": 10}, number: 4},
+		{desc: "Favor duration", metrics: both, weights: map[string]float64{"duration": 10}, number: 1},
+		{desc: "Balanced", metrics: both, weights: map[string]float64{"cost": 1, "duration": 1}, number: 2},
+		{desc: "Maximize", metrics: []experimentsv1alpha1.Metric{{Name: "cost", Minimize: true}, {Name: "duration"}}, weights: map[string]float64{"duration": 10}, number: 4},
+		{desc: "Unknown metric", metrics: both, weights: map[string]float64{"latency": 1}, err: true},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			exp := &experimentsv1alpha1.Experiment{Metrics: c.metrics}
+			best, err := selectBest(exp, trials, c.weights)
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.number, best.Number)
+			}
+		})
+	}
+}
+
+func TestKubectlResource(t *testing.T) {
+	assert.Equal(t, "deployment.v1.apps", kubectlResource("apps/v1", "Deployment"))
+	assert.Equal(t, "configmap", kubectlResource("v1", "ConfigMap"))
+}
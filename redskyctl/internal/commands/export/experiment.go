@@ -0,0 +1,267 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/template"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// ExperimentOptions is the configuration for exporting a complete experiment snapshot
+type ExperimentOptions struct {
+	Options
+
+	// Filename is the experiment manifest to include, when empty the manifest is only read from the cluster for archives
+	Filename string
+	// Format is the encoding of the snapshot, one of "yaml" or "json"
+	Format string
+	// Archive produces a gzipped tarball which also includes the rendered patches of every trial
+	Archive bool
+	// OutputFile is the name of the file to write, when empty the snapshot is written to standard output
+	OutputFile string
+}
+
+// snapshot is a self-contained export of an experiment and all of its trials
+type snapshot struct {
+	// Experiment is the experiment definition from the server
+	Experiment experimentsv1alpha1.Experiment `json:"experiment"`
+	// Manifest is the cluster experiment, if it was available
+	Manifest *redskyv1beta1.Experiment `json:"manifest,omitempty"`
+	// Trials are the trials of the experiment including the assignments and metric values
+	Trials []experimentsv1alpha1.TrialItem `json:"trials"`
+}
+
+// NewExperimentCommand creates a new command for exporting a complete experiment snapshot
+func NewExperimentCommand(o *ExperimentOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "experiment EXPERIMENT",
+		Short: "Export an experiment snapshot",
+		Long:  "Export the experiment definition along with every trial, assignment and metric value for offline analysis",
+		Example: `# Export an experiment as YAML
+redskyctl export experiment my-experiment > my-experiment.yaml
+
+# Export an archive including the rendered patches of every trial
+redskyctl export experiment my-experiment --archive -o my-experiment.tar.gz`,
+
+		Args: cobra.ExactArgs(1),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.ExperimentName = args[0]
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.experiment),
+	}
+
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", o.Filename, "File that contains the experiment manifest to include (defaults to reading the experiment from the cluster for archives).")
+	cmd.Flags().StringVar(&o.Format, "format", "yaml", "Encoding of the snapshot; one of: yaml|json.")
+	cmd.Flags().BoolVar(&o.Archive, "archive", o.Archive, "Produce a gzipped tarball which includes the rendered patches of every trial.")
+	cmd.Flags().StringVarP(&o.OutputFile, "output", "o", o.OutputFile, "Write the snapshot to the specified `file`.")
+
+	_ = cmd.MarkFlagFilename("filename", "yml", "yaml", "json")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *ExperimentOptions) experiment(ctx context.Context) error {
+	s, err := o.snapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := o.Out
+	if o.OutputFile != "" {
+		f, err := os.Create(o.OutputFile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if o.Archive {
+		return writeArchive(w, o.ExperimentName, o.Format, s, time.Now().UTC())
+	}
+
+	data, err := encodeSnapshot(s, o.Format)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// snapshot fetches the experiment and trials from the server
+func (o *ExperimentOptions) snapshot(ctx context.Context) (*snapshot, error) {
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName(o.ExperimentName))
+	if err != nil {
+		return nil, err
+	}
+
+	var tl experimentsv1alpha1.TrialList
+	if exp.TrialsURL != "" {
+		progress := o.NewProgress("Fetching trials", 0)
+		tl, err = o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, nil)
+		progress.Done(err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var manifest *redskyv1beta1.Experiment
+	if o.Filename != "" || o.Archive {
+		if manifest, err = o.clusterExperiment(ctx, o.Filename); err != nil {
+			return nil, err
+		}
+	}
+
+	return newSnapshot(&exp, tl.Trials, manifest), nil
+}
+
+// newSnapshot returns a snapshot of the supplied experiment, sensitive parameter assignments are redacted so the
+// snapshot can be safely shared
+func newSnapshot(exp *experimentsv1alpha1.Experiment, trials []experimentsv1alpha1.TrialItem, manifest *redskyv1beta1.Experiment) *snapshot {
+	s := &snapshot{Experiment: *exp, Manifest: manifest}
+
+	sensitive := make(map[string]bool, len(exp.Parameters))
+	for i := range exp.Parameters {
+		sensitive[exp.Parameters[i].Name] = exp.Parameters[i].Sensitive
+	}
+
+	for i := range trials {
+		t := trials[i]
+		t.Metadata = nil
+		t.Experiment = nil
+		t.Assignments = make([]experimentsv1alpha1.Assignment, len(trials[i].Assignments))
+		for j, a := range trials[i].Assignments {
+			if sensitive[a.ParameterName] {
+				a.Value = trial.Redacted
+			}
+			t.Assignments[j] = a
+		}
+		s.Trials = append(s.Trials, t)
+	}
+
+	return s
+}
+
+// encodeSnapshot returns the encoded snapshot
+func encodeSnapshot(s *snapshot, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "yaml", "":
+		return yaml.Marshal(s)
+	case "json":
+		data, err := json.MarshalIndent(s, "", "  ")
+		return append(data, '\n'), err
+	default:
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+}
+
+// writeArchive writes a gzipped tarball containing the snapshot and, if the manifest is available, the rendered
+// patches of every trial
+func writeArchive(w io.Writer, name, format string, s *snapshot, modTime time.Time) error {
+	data, err := encodeSnapshot(s, format)
+	if err != nil {
+		return err
+	}
+	if format == "" {
+		format = "yaml"
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	if err := writeFile(tw, path.Join(name, "experiment."+strings.ToLower(format)), modTime, data); err != nil {
+		return err
+	}
+
+	if s.Manifest != nil {
+		te := template.New()
+		for i := range s.Trials {
+			t := clusterTrial(s.Manifest, &s.Trials[i])
+			for j := range s.Manifest.Spec.Patches {
+				p := &s.Manifest.Spec.Patches[j]
+				patch, err := renderPatch(te, p, t)
+				if err != nil {
+					return fmt.Errorf("unable to render patch %d of trial %s: %w", j, t.Name, err)
+				}
+				if err := writeFile(tw, path.Join(name, "patches", t.Name, fmt.Sprintf("patch-%d.yaml", j)), modTime, patch); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// renderPatch evaluates a patch template for a trial, the result is YAML preceded by a comment describing the target
+func renderPatch(te *template.Engine, p *redskyv1beta1.PatchTemplate, t *redskyv1beta1.Trial) ([]byte, error) {
+	data, err := te.RenderPatch(p, t)
+	if err != nil {
+		return nil, err
+	}
+	data, err = yaml.JSONToYAML(data)
+	if err != nil {
+		return nil, err
+	}
+
+	patchType := p.Type
+	if patchType == "" {
+		patchType = redskyv1beta1.PatchStrategic
+	}
+
+	var buf bytes.Buffer
+	if p.TargetRef != nil {
+		_, _ = fmt.Fprintf(&buf, "# %s patch for %s %s\n", patchType, p.TargetRef.Kind, p.TargetRef.Name)
+	} else {
+		_, _ = fmt.Fprintf(&buf, "# %s patch\n", patchType)
+	}
+	buf.Write(data)
+	return buf.Bytes(), nil
+}
+
+// writeFile adds a file to the archive
+func writeFile(tw *tar.Writer, name string, modTime time.Time, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: modTime}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestWriteArchive(t *testing.T) {
+	exp := &experimentsv1alpha1.Experiment{
+		Parameters: []experimentsv1alpha1.Parameter{{Name: "memory"}, {Name: "password", Sensitive: true}},
+	}
+	trials := []experimentsv1alpha1.TrialItem{
+		{
+			Number: 1,
+			TrialAssignments: experimentsv1alpha1.TrialAssignments{Assignments: []experimentsv1alpha1.Assignment{
+				{ParameterName: "memory", Value: "512"},
+				{ParameterName: "password", Value: "secret"},
+			}},
+			Metadata: experimentsv1alpha1.Metadata{"Location": []string{"http://example.com/experiments/foo/trials/1"}},
+		},
+	}
+	manifest := &redskyv1beta1.Experiment{}
+	manifest.Name = "foo"
+	manifest.Spec.Patches = []redskyv1beta1.PatchTemplate{
+		{
+			TargetRef: &corev1.ObjectReference{Kind: "Deployment", Name: "app"},
+			Patch:     `{"spec":{"template":{"spec":{"containers":[{"name":"app","resources":{"limits":{"memory":"{{ .Values.memory }}Mi"}}}]}}}}`,
+		},
+	}
+
+	s := newSnapshot(exp, trials, manifest)
+	assert.Nil(t, s.Trials[0].Metadata)
+	assert.Equal(t, "512", s.Trials[0].Assignments[0].Value.String())
+	assert.Equal(t, trial.Redacted, s.Trials[0].Assignments[1].Value.String())
+	assert.Equal(t, "secret", trials[0].Assignments[1].Value.String(), "original trial must not be modified")
+
+	var buf bytes.Buffer
+	require.NoError(t, writeArchive(&buf, "foo", "json", s, time.Now()))
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	files := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		files[h.Name] = string(data)
+	}
+
+	assert.Contains(t, files, "foo/experiment.json")
+	assert.Equal(t, `# strategic patch for Deployment app
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        resources:
+          limits:
+            memory: 512Mi
+`, files["foo/patches/foo-001/patch-0.yaml"])
+}
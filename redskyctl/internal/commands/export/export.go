@@ -0,0 +1,183 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os/exec"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// BestLabel is the trial label used to identify the best trial of an experiment
+const BestLabel = "best"
+
+// Options are the common options for exporting experiment results
+type Options struct {
+	// Config is the Red Sky Configuration
+	Config config.Config
+	// ExperimentsAPI is used to interact with the Red Sky Experiments API
+	ExperimentsAPI experimentsv1alpha1.API
+	// Printer is the resource printer used to render exported objects
+	Printer commander.ResourcePrinter
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// ExperimentName is the name of the experiment to export from
+	ExperimentName string
+	// TrialNumber is the number of the trial to export, a negative number selects the best trial
+	TrialNumber int64
+}
+
+// NewCommand creates a new command for exporting experiment results
+func NewCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export experiment results",
+		Long:  "Export the results of an experiment for use outside of Red Sky Ops",
+	}
+
+	cmd.AddCommand(NewExperimentCommand(&ExperimentOptions{Options: Options{Config: o.Config}}))
+	cmd.AddCommand(NewRecommendationsCommand(&RecommendationsOptions{Options: Options{Config: o.Config}}))
+	cmd.AddCommand(NewValuesCommand(&ValuesOptions{Options: Options{Config: o.Config}}))
+	cmd.AddCommand(NewWarehouseCommand(&WarehouseOptions{Options: Options{Config: o.Config}}))
+
+	return cmd
+}
+
+// addTrialFlags registers the flags used to select a trial
+func (o *Options) addTrialFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64Var(&o.TrialNumber, "trial", -1, "Number of the trial to export (defaults to the best trial).")
+}
+
+// trial returns the selected trial of the experiment along with the experiment itself
+func (o *Options) trial(ctx context.Context) (*experimentsv1alpha1.TrialItem, error) {
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName(o.ExperimentName))
+	if err != nil {
+		return nil, err
+	}
+
+	q := &experimentsv1alpha1.TrialListQuery{Status: []experimentsv1alpha1.TrialStatus{experimentsv1alpha1.TrialCompleted}}
+	tl, err := o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, q)
+	if err != nil {
+		return nil, err
+	}
+
+	var t *experimentsv1alpha1.TrialItem
+	if o.TrialNumber >= 0 {
+		for i := range tl.Trials {
+			if tl.Trials[i].Number == o.TrialNumber {
+				t = &tl.Trials[i]
+				break
+			}
+		}
+		if t == nil {
+			return nil, fmt.Errorf("unable to find completed trial %d of experiment %s", o.TrialNumber, o.ExperimentName)
+		}
+	} else {
+		if t, err = bestTrial(&exp, tl.Trials); err != nil {
+			return nil, err
+		}
+	}
+
+	t.Experiment = &exp
+	return t, nil
+}
+
+// clusterExperiment returns the cluster experiment either from a file or by asking kubectl for it
+func (o *Options) clusterExperiment(ctx context.Context, filename string) (*redskyv1beta1.Experiment, error) {
+	var data []byte
+	var err error
+	switch filename {
+	case "":
+		var get *exec.Cmd
+		get, err = o.Config.Kubectl(ctx, "get", "experiments.v1beta1.redskyops.dev", o.ExperimentName, "-o", "json")
+		if err != nil {
+			return nil, err
+		}
+		get.Stderr = o.ErrOut
+		data, err = get.Output()
+	case "-":
+		data, err = ioutil.ReadAll(o.In)
+	default:
+		data, err = ioutil.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	exp := &redskyv1beta1.Experiment{}
+	if err := yaml.Unmarshal(data, exp); err != nil {
+		return nil, err
+	}
+	return exp, nil
+}
+
+// clusterTrial builds a cluster trial from the assignments of the supplied trial so templates can be evaluated the
+// same way they are in the cluster
+func clusterTrial(exp *redskyv1beta1.Experiment, t *experimentsv1alpha1.TrialItem) *redskyv1beta1.Trial {
+	trial := &redskyv1beta1.Trial{}
+	trial.Name = fmt.Sprintf("%s-%03d", exp.Name, t.Number)
+	trial.Namespace = exp.Namespace
+	for _, a := range t.Assignments {
+		v := redskyv1beta1.ParseAssignmentValue(exp.Spec.Parameters, a.ParameterName, a.Value.String())
+		trial.Spec.Assignments = append(trial.Spec.Assignments, redskyv1beta1.Assignment{Name: a.ParameterName, Value: v})
+	}
+	return trial
+}
+
+// bestTrial returns the trial explicitly labeled as best or, for single metric experiments, the optimal trial
+func bestTrial(exp *experimentsv1alpha1.Experiment, trials []experimentsv1alpha1.TrialItem) (*experimentsv1alpha1.TrialItem, error) {
+	for i := range trials {
+		if trials[i].Labels[BestLabel] == "true" {
+			return &trials[i], nil
+		}
+	}
+
+	if len(exp.Metrics) != 1 {
+		return nil, fmt.Errorf("unable to determine the best trial of experiment %s, label a trial with %s=true or specify a trial number", exp.DisplayName, BestLabel)
+	}
+
+	var best *experimentsv1alpha1.TrialItem
+	bestValue := math.Inf(1)
+	for i := range trials {
+		for _, v := range trials[i].Values {
+			if v.MetricName != exp.Metrics[0].Name {
+				continue
+			}
+			value := v.Value
+			if !exp.Metrics[0].Minimize {
+				value = -value
+			}
+			if value < bestValue {
+				best, bestValue = &trials[i], value
+			}
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("experiment %s has no completed trials", exp.DisplayName)
+	}
+	return best, nil
+}
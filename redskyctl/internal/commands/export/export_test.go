@@ -0,0 +1,154 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestBestTrial(t *testing.T) {
+	trials := []experimentsv1alpha1.TrialItem{
+		{Number: 1, TrialValues: experimentsv1alpha1.TrialValues{Values: []experimentsv1alpha1.Value{{MetricName: "cost", Value: 5}, {MetricName: "duration", Value: 10}}}},
+		{Number: 2, TrialValues: experimentsv1alpha1.TrialValues{Values: []experimentsv1alpha1.Value{{MetricName: "cost", Value: 3}, {MetricName: "duration", Value: 20}}}},
+		{Number: 3, TrialValues: experimentsv1alpha1.TrialValues{Values: []experimentsv1alpha1.Value{{MetricName: "cost", Value: 4}, {MetricName: "duration", Value: 15}}}},
+	}
+
+	cases := []struct {
+		desc    string
+		metrics []experimentsv1alpha1.Metric
+		labeled int
+		number  int64
+		err     bool
+	}{
+		{
+			desc:    "Minimize",
+			metrics: []experimentsv1alpha1.Metric{{Name: "cost", Minimize: true}},
+			labeled: -1,
+			number:  2,
+		},
+		{
+			desc:    "Maximize",
+			metrics: []experimentsv1alpha1.Metric{{Name: "duration"}},
+			labeled: -1,
+			number:  2,
+		},
+		{
+			desc:    "Labeled",
+			metrics: []experimentsv1alpha1.Metric{{Name: "cost", Minimize: true}, {Name: "duration", Minimize: true}},
+			labeled: 2,
+			number:  3,
+		},
+		{
+			desc:    "Ambiguous",
+			metrics: []experimentsv1alpha1.Metric{{Name: "cost", Minimize: true}, {Name: "duration", Minimize: true}},
+			labeled: -1,
+			err:     true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			tl := make([]experimentsv1alpha1.TrialItem, len(trials))
+			copy(tl, trials)
+			if c.labeled >= 0 {
+				tl[c.labeled].Labels = map[string]string{BestLabel: "true"}
+			}
+
+			exp := &experimentsv1alpha1.Experiment{Metrics: c.metrics}
+			best, err := bestTrial(exp, tl)
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.number, best.Number)
+			}
+		})
+	}
+}
+
+func TestResourceList(t *testing.T) {
+	trial := &experimentsv1alpha1.TrialItem{
+		TrialAssignments: experimentsv1alpha1.TrialAssignments{
+			Assignments: []experimentsv1alpha1.Assignment{
+				{ParameterName: "cpu", Value: "500"},
+				{ParameterName: "memory", Value: "1024"},
+				{ParameterName: "max_connections", Value: "20"},
+			},
+		},
+		Experiment: &experimentsv1alpha1.Experiment{DisplayName: "test"},
+	}
+
+	o := &RecommendationsOptions{}
+	rl, err := o.resourceList(trial)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "500m", rl.Cpu().String())
+		assert.Equal(t, "1Gi", rl.Memory().String())
+	}
+
+	o.CPUParameter = "max_connections"
+	rl, err = o.resourceList(trial)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "20m", rl.Cpu().String())
+	}
+
+	trial.Assignments = append(trial.Assignments, experimentsv1alpha1.Assignment{ParameterName: "sidecar_memory", Value: "64"})
+	_, err = o.resourceList(trial)
+	assert.Error(t, err)
+}
+
+func TestHelmValues(t *testing.T) {
+	task := &redskyv1beta1.SetupTask{
+		HelmChart: "stable/postgresql",
+		HelmValues: []redskyv1beta1.HelmValue{
+			{Name: "resources.requests.cpu", Value: intstr.FromString("{{ .Values.cpu }}m")},
+			{Name: "resources.requests.memory", Value: intstr.FromString("{{ .Values.memory }}Mi")},
+			{Name: "replicaCount", ValueFrom: &redskyv1beta1.HelmValueSource{ParameterRef: &redskyv1beta1.ParameterSelector{Name: "replicas"}}},
+			{Name: "image.tag", Value: intstr.FromString("11"), ForceString: true},
+		},
+	}
+	trial := &redskyv1beta1.Trial{}
+	trial.Spec.Assignments = []redskyv1beta1.Assignment{
+		{Name: "cpu", Value: redskyv1beta1.NewAssignmentValue(500)},
+		{Name: "memory", Value: redskyv1beta1.NewAssignmentValue(2048)},
+		{Name: "replicas", Value: redskyv1beta1.NewAssignmentValue(3)},
+	}
+
+	values, err := helmValues(task, trial)
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]interface{}{
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{
+					"cpu":    "500m",
+					"memory": "2048Mi",
+				},
+			},
+			"replicaCount": int64(3),
+			"image": map[string]interface{}{
+				"tag": "11",
+			},
+		}, values)
+	}
+
+	task.HelmValues = append(task.HelmValues, redskyv1beta1.HelmValue{Name: "resources.requests.cpu.foo", Value: intstr.FromInt(1)})
+	_, err = helmValues(task, trial)
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// FormatVPA exports recommendations as a VerticalPodAutoscaler
+	FormatVPA = "vpa"
+	// FormatLimitRange exports recommendations as a LimitRange
+	FormatLimitRange = "limitrange"
+)
+
+// RecommendationsOptions is the configuration for exporting resource recommendations
+type RecommendationsOptions struct {
+	Options
+
+	// Format is the type of object to export
+	Format string
+	// Target is the "KIND/NAME" of the controller the recommendations apply to
+	Target string
+	// ContainerName is the name of the container the recommendations apply to
+	ContainerName string
+	// CPUParameter is the name of the parameter holding CPU millicores
+	CPUParameter string
+	// MemoryParameter is the name of the parameter holding memory mebibytes
+	MemoryParameter string
+}
+
+// NewRecommendationsCommand creates a new command for exporting resource recommendations
+func NewRecommendationsCommand(o *RecommendationsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recommendations EXPERIMENT",
+		Short: "Export resource recommendations",
+		Long:  "Export the CPU and memory assignments of a trial as a VerticalPodAutoscaler or LimitRange",
+
+		Args: cobra.ExactArgs(1),
+
+		Annotations: map[string]string{
+			commander.PrinterAllowedFormats: "json,yaml",
+			commander.PrinterOutputFormat:   "yaml",
+			commander.PrinterHideStatus:     "true",
+		},
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.ExperimentName = args[0]
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.recommendations),
+	}
+
+	o.addTrialFlags(cmd)
+	cmd.Flags().StringVar(&o.Format, "as", FormatVPA, "Type of object to export; one of: vpa|limitrange.")
+	cmd.Flags().StringVar(&o.Target, "target", o.Target, "Controller the VerticalPodAutoscaler applies to, as `KIND/NAME`.")
+	cmd.Flags().StringVar(&o.ContainerName, "container", "*", "Name of the container the recommendations apply to.")
+	cmd.Flags().StringVar(&o.CPUParameter, "cpu-parameter", o.CPUParameter, "Name of the parameter holding CPU millicores (defaults to the parameter ending in 'cpu').")
+	cmd.Flags().StringVar(&o.MemoryParameter, "memory-parameter", o.MemoryParameter, "Name of the parameter holding memory mebibytes (defaults to the parameter ending in 'memory').")
+
+	commander.SetKubePrinter(&o.Printer, cmd)
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *RecommendationsOptions) recommendations(ctx context.Context) error {
+	t, err := o.trial(ctx)
+	if err != nil {
+		return err
+	}
+
+	resources, err := o.resourceList(t)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%03d", t.Experiment.DisplayName, t.Number)
+	switch strings.ToLower(o.Format) {
+	case FormatVPA:
+		vpa, err := o.verticalPodAutoscaler(name, resources)
+		if err != nil {
+			return err
+		}
+		return o.Printer.PrintObj(vpa, o.Out)
+	case FormatLimitRange:
+		return o.Printer.PrintObj(o.limitRange(name, resources), o.Out)
+	default:
+		return fmt.Errorf("unknown format: %s", o.Format)
+	}
+}
+
+// resourceList converts the trial assignments into a list of resources
+func (o *RecommendationsOptions) resourceList(t *experimentsv1alpha1.TrialItem) (corev1.ResourceList, error) {
+	rl := corev1.ResourceList{}
+	for _, a := range t.Assignments {
+		var r corev1.ResourceName
+		var format string
+		switch {
+		case o.CPUParameter == a.ParameterName, o.CPUParameter == "" && strings.HasSuffix(strings.ToLower(a.ParameterName), "cpu"):
+			r, format = corev1.ResourceCPU, "%sm"
+		case o.MemoryParameter == a.ParameterName, o.MemoryParameter == "" && strings.HasSuffix(strings.ToLower(a.ParameterName), "memory"):
+			r, format = corev1.ResourceMemory, "%sMi"
+		default:
+			continue
+		}
+
+		if _, ok := rl[r]; ok {
+			return nil, fmt.Errorf("multiple parameters match %s, specify the parameter name explicitly", r)
+		}
+		q, err := resource.ParseQuantity(fmt.Sprintf(format, a.Value.String()))
+		if err != nil {
+			return nil, err
+		}
+		rl[r] = q
+	}
+
+	if len(rl) == 0 {
+		return nil, fmt.Errorf("trial %s-%03d has no cpu or memory assignments", t.Experiment.DisplayName, t.Number)
+	}
+	return rl, nil
+}
+
+// verticalPodAutoscaler returns a VPA that only reports recommendations bounded by the supplied resources
+func (o *RecommendationsOptions) verticalPodAutoscaler(name string, rl corev1.ResourceList) (*unstructured.Unstructured, error) {
+	p := strings.SplitN(o.Target, "/", 2)
+	if len(p) != 2 || p[0] == "" || p[1] == "" {
+		return nil, fmt.Errorf("target must be specified as KIND/NAME")
+	}
+
+	resources := make(map[string]interface{}, len(rl))
+	for k, v := range rl {
+		resources[string(k)] = v.String()
+	}
+
+	vpa := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"targetRef": map[string]interface{}{
+				"apiVersion": "apps/v1",
+				"kind":       p[0],
+				"name":       p[1],
+			},
+			"updatePolicy": map[string]interface{}{
+				"updateMode": "Off",
+			},
+			"resourcePolicy": map[string]interface{}{
+				"containerPolicies": []interface{}{
+					map[string]interface{}{
+						"containerName": o.ContainerName,
+						"minAllowed":    resources,
+						"maxAllowed":    resources,
+					},
+				},
+			},
+		},
+	}}
+	vpa.SetAPIVersion("autoscaling.k8s.io/v1")
+	vpa.SetKind("VerticalPodAutoscaler")
+	vpa.SetName(name)
+	return vpa, nil
+}
+
+// limitRange returns a LimitRange which defaults container requests and limits to the supplied resources
+func (o *RecommendationsOptions) limitRange(name string, rl corev1.ResourceList) *corev1.LimitRange {
+	lr := &corev1.LimitRange{}
+	lr.APIVersion = "v1"
+	lr.Kind = "LimitRange"
+	lr.Name = name
+	lr.Spec.Limits = []corev1.LimitRangeItem{
+		{
+			Type:           corev1.LimitTypeContainer,
+			Default:        rl,
+			DefaultRequest: rl,
+		},
+	}
+	return lr
+}
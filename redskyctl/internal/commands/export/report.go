@@ -0,0 +1,276 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/redskyops/redskyops-controller/internal/analysis"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+// BaselineLabel is the trial label used to identify the baseline trial of an experiment
+const BaselineLabel = "baseline"
+
+// ReportOptions is the configuration for summarizing the savings of the best trial of an experiment
+type ReportOptions struct {
+	Options
+
+	// Baseline is the number of the baseline trial, a negative number selects the trial labeled as the baseline
+	Baseline int64
+	// Weights are the relative importance of each metric when selecting the best trial from the Pareto front
+	Weights map[string]string
+	// Confidence is the confidence level of the intervals computed from replicated trials
+	Confidence float64
+	// CostMetric is the name of the metric which represents the cost of running the application
+	CostMetric string
+	// MonthlyFactor is the number of cost metric units in a month, e.g. 730 when the cost metric is an hourly cost
+	MonthlyFactor float64
+	// Notify is a URL the JSON representation of the report is posted to
+	Notify string
+}
+
+// NewReportCommand creates a new command for summarizing the savings of the best trial of an experiment
+func NewReportCommand(o *ReportOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report EXPERIMENT",
+		Short: "Summarize experiment savings",
+		Long: "Summarize the difference in each metric between the baseline trial and the best trial of an experiment.\n\n" +
+			"The baseline is the trial labeled 'baseline' unless a trial number is specified, the best trial is selected " +
+			"the same way as the 'best' command. When trials have been replicated (using the same assignments) the " +
+			"metric values are averaged and confidence intervals of the differences are included.",
+		Example: `# Summarize the savings of an experiment whose cost metric is an hourly cost
+redskyctl report my-experiment --cost-metric cost --monthly-factor 730
+
+# Post the report to a webhook once the experiment has completed
+redskyctl report my-experiment --notify https://hooks.example.com/redsky`,
+
+		Args: cobra.ExactArgs(1),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.ExperimentName = args[0]
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.report),
+	}
+
+	cmd.Flags().Int64Var(&o.Baseline, "baseline", -1, "Number of the baseline trial (defaults to the trial labeled 'baseline').")
+	cmd.Flags().StringToStringVar(&o.Weights, "metric", nil, "Weight of a metric when selecting the best trial from the Pareto front, as `NAME=WEIGHT`.")
+	cmd.Flags().Float64Var(&o.Confidence, "confidence", 0.95, "Confidence level of the intervals computed from replicated trials.")
+	cmd.Flags().StringVar(&o.CostMetric, "cost-metric", "cost", "Name of the `metric` representing the cost of the application.")
+	cmd.Flags().Float64Var(&o.MonthlyFactor, "monthly-factor", 730, "Number of cost metric units in a month, e.g. 730 for an hourly cost.")
+	cmd.Flags().StringVar(&o.Notify, "notify", "", "Post the report as JSON to the specified `URL`.")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+// savingsReport summarizes the difference between the baseline and best trials of an experiment
+type savingsReport struct {
+	// Experiment is the name of the experiment
+	Experiment string `json:"experiment"`
+	// Baseline is the number of the baseline trial
+	Baseline int64 `json:"baseline"`
+	// Best is the number of the best trial
+	Best int64 `json:"best"`
+	// Confidence is the confidence level of the intervals
+	Confidence float64 `json:"confidence"`
+	// MonthlyCost is the change in the monthly cost, only present when the experiment has a cost metric
+	MonthlyCost *analysis.Delta `json:"monthlyCost,omitempty"`
+	// Metrics are the changes in each of the experiment metrics
+	Metrics []analysis.Delta `json:"metrics"`
+}
+
+func (o *ReportOptions) report(ctx context.Context) error {
+	weights, err := parseWeights(o.Weights)
+	if err != nil {
+		return err
+	}
+
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName(o.ExperimentName))
+	if err != nil {
+		return err
+	}
+
+	q := &experimentsv1alpha1.TrialListQuery{Status: []experimentsv1alpha1.TrialStatus{experimentsv1alpha1.TrialCompleted}}
+	tl, err := o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, q)
+	if err != nil {
+		return err
+	}
+
+	r, err := o.savings(&exp, tl.Trials, weights)
+	if err != nil {
+		return err
+	}
+
+	if err := r.write(o.Out); err != nil {
+		return err
+	}
+
+	if o.Notify != "" {
+		return postReport(ctx, o.Notify, r)
+	}
+	return nil
+}
+
+// savings computes the report for the supplied completed trials
+func (o *ReportOptions) savings(exp *experimentsv1alpha1.Experiment, trials []experimentsv1alpha1.TrialItem, weights map[string]float64) (*savingsReport, error) {
+	baseline, err := baselineTrial(exp, trials, o.Baseline)
+	if err != nil {
+		return nil, err
+	}
+	best, err := selectBest(exp, trials, weights)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &savingsReport{Experiment: o.ExperimentName, Baseline: baseline.Number, Best: best.Number, Confidence: o.Confidence}
+	for _, m := range exp.Metrics {
+		a, err := analysis.Replicates(trials, baseline.Number, m.Name)
+		if err != nil {
+			return nil, err
+		}
+		b, err := analysis.Replicates(trials, best.Number, m.Name)
+		if err != nil {
+			return nil, err
+		}
+		d, err := analysis.CompareMeans(m.Name, a, b, o.Confidence)
+		if err != nil {
+			return nil, err
+		}
+		r.Metrics = append(r.Metrics, *d)
+
+		if m.Name == o.CostMetric && o.MonthlyFactor > 0 {
+			r.MonthlyCost = scaleDelta(d, o.MonthlyFactor)
+		}
+	}
+	return r, nil
+}
+
+// baselineTrial returns the trial with the specified number or, for a negative number, the trial labeled as the baseline
+func baselineTrial(exp *experimentsv1alpha1.Experiment, trials []experimentsv1alpha1.TrialItem, number int64) (*experimentsv1alpha1.TrialItem, error) {
+	for i := range trials {
+		if (number >= 0 && trials[i].Number == number) || (number < 0 && trials[i].Labels[BaselineLabel] == "true") {
+			return &trials[i], nil
+		}
+	}
+
+	if number >= 0 {
+		return nil, fmt.Errorf("unable to find completed trial %d of experiment %s", number, exp.DisplayName)
+	}
+	return nil, fmt.Errorf("unable to determine the baseline trial of experiment %s, label a trial with %s=true or specify a trial number", exp.DisplayName, BaselineLabel)
+}
+
+// scaleDelta returns a copy of the delta with all of the absolute values multiplied by the supplied factor
+func scaleDelta(d *analysis.Delta, factor float64) *analysis.Delta {
+	s := *d
+	s.Baseline *= factor
+	s.Candidate *= factor
+	s.Difference *= factor
+	if d.Lower != nil && d.Upper != nil {
+		lower, upper := *d.Lower*factor, *d.Upper*factor
+		s.Lower, s.Upper = &lower, &upper
+	}
+	return &s
+}
+
+// write prints the report
+func (r *savingsReport) write(out io.Writer) error {
+	_, _ = fmt.Fprintf(out, "Experiment:   %s\n", r.Experiment)
+	_, _ = fmt.Fprintf(out, "Baseline:     %s-%03d\n", r.Experiment, r.Baseline)
+	_, _ = fmt.Fprintf(out, "Best:         %s-%03d\n", r.Experiment, r.Best)
+	if r.MonthlyCost != nil {
+		_, _ = fmt.Fprintf(out, "Monthly Cost: %s -> %s (%s%s)\n",
+			formatValue(r.MonthlyCost.Baseline), formatValue(r.MonthlyCost.Candidate), formatChange(r.MonthlyCost), r.formatInterval(r.MonthlyCost))
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "METRIC\tBASELINE\tBEST\tDELTA\tINTERVAL")
+	for i := range r.Metrics {
+		d := &r.Metrics[i]
+		interval := "-"
+		if d.Lower != nil && d.Upper != nil {
+			interval = fmt.Sprintf("[%s, %s]", formatValue(*d.Lower), formatValue(*d.Upper))
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", d.Metric, formatValue(d.Baseline), formatValue(d.Candidate), formatChange(d), interval)
+	}
+	return w.Flush()
+}
+
+// formatInterval returns the confidence interval of the delta as a suffix, or an empty string if there is no interval
+func (r *savingsReport) formatInterval(d *analysis.Delta) string {
+	if d.Lower == nil || d.Upper == nil {
+		return ""
+	}
+	return fmt.Sprintf(", %g%% interval %s to %s", r.Confidence*100, formatValue(*d.Lower), formatValue(*d.Upper))
+}
+
+// formatChange returns the absolute and relative difference of the delta
+func formatChange(d *analysis.Delta) string {
+	sign := ""
+	if d.Difference > 0 {
+		sign = "+"
+	}
+	if d.Baseline == 0 {
+		return sign + formatValue(d.Difference)
+	}
+	return fmt.Sprintf("%s%s, %s%.1f%%", sign, formatValue(d.Difference), sign, d.Relative*100)
+}
+
+// formatValue rounds a value for display
+func formatValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
+
+// postReport sends the JSON representation of the report to the supplied URL
+func postReport(ctx context.Context, u string, r *savingsReport) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ctxhttp.Do(ctx, nil, req)
+	if err != nil {
+		return err
+	}
+
+	_, _ = io.Copy(ioutil.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("report notification returned unexpected status: %v", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/redskyops/redskyops-controller/internal/analysis"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavings(t *testing.T) {
+	trial := func(n int64, a string, cost, latency float64) experimentsv1alpha1.TrialItem {
+		return experimentsv1alpha1.TrialItem{
+			Number:           n,
+			TrialAssignments: experimentsv1alpha1.TrialAssignments{Assignments: []experimentsv1alpha1.Assignment{{ParameterName: "a", Value: json.Number(a)}}},
+			TrialValues:      experimentsv1alpha1.TrialValues{Values: []experimentsv1alpha1.Value{{MetricName: "cost", Value: cost}, {MetricName: "latency", Value: latency}}},
+		}
+	}
+	trials := []experimentsv1alpha1.TrialItem{trial(1, "1", 10, 100), trial(2, "1", 12, 110), trial(3, "2", 6, 120), trial(4, "2", 8, 130)}
+	trials[0].Labels = map[string]string{BaselineLabel: "true"}
+	trials[2].Labels = map[string]string{BestLabel: "true"}
+	exp := &experimentsv1alpha1.Experiment{Metrics: []experimentsv1alpha1.Metric{{Name: "cost", Minimize: true}, {Name: "latency", Minimize: true}}}
+
+	o := &ReportOptions{Options: Options{ExperimentName: "test"}, Baseline: -1, Confidence: 0.95, CostMetric: "cost", MonthlyFactor: 730}
+	r, err := o.savings(exp, trials, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(1), r.Baseline)
+		assert.Equal(t, int64(3), r.Best)
+		if assert.Len(t, r.Metrics, 2) {
+			assert.InDelta(t, 11, r.Metrics[0].Baseline, 0.0001)
+			assert.InDelta(t, 7, r.Metrics[0].Candidate, 0.0001)
+			assert.InDelta(t, 20, r.Metrics[1].Difference, 0.0001)
+			assert.NotNil(t, r.Metrics[1].Lower)
+		}
+		if assert.NotNil(t, r.MonthlyCost) {
+			assert.InDelta(t, -2920, r.MonthlyCost.Difference, 0.0001)
+			assert.InDelta(t, -4.0/11, r.MonthlyCost.Relative, 0.0001)
+		}
+	}
+
+	// An explicit baseline that does not exist is an error
+	o.Baseline = 10
+	_, err = o.savings(exp, trials, nil)
+	assert.Error(t, err)
+
+	// Without a label there is no baseline
+	trials[0].Labels = nil
+	o.Baseline = -1
+	_, err = o.savings(exp, trials, nil)
+	assert.Error(t, err)
+}
+
+func TestSavingsReportWrite(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	r := &savingsReport{
+		Experiment:  "test",
+		Baseline:    1,
+		Best:        3,
+		Confidence:  0.95,
+		MonthlyCost: &analysis.Delta{Metric: "cost", Baseline: 8030, Candidate: 5110, Difference: -2920, Relative: -4.0 / 11, Lower: f(-7362), Upper: f(1522)},
+		Metrics: []analysis.Delta{
+			{Metric: "cost", Baseline: 11, Candidate: 7, Difference: -4, Relative: -4.0 / 11, Lower: f(-10), Upper: f(2)},
+			{Metric: "latency", Baseline: 105, Candidate: 125, Difference: 20, Relative: 20.0 / 105},
+		},
+	}
+
+	var buf bytes.Buffer
+	if assert.NoError(t, r.write(&buf)) {
+		assert.Equal(t, `Experiment:   test
+Baseline:     test-001
+Best:         test-003
+Monthly Cost: 8030 -> 5110 (-2920, -36.4%, 95% interval -7362 to 1522)
+METRIC    BASELINE   BEST   DELTA         INTERVAL
+cost      11         7      -4, -36.4%    [-10, 2]
+latency   105        125    +20, +19.0%   -
+`, buf.String())
+	}
+
+	var posted savingsReport
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&posted))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	if assert.NoError(t, postReport(context.Background(), srv.URL, r)) {
+		assert.Equal(t, "test", posted.Experiment)
+		assert.Len(t, posted.Metrics, 2)
+	}
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/template"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// ValuesOptions is the configuration for exporting Helm values
+type ValuesOptions struct {
+	Options
+
+	// Filename is the experiment manifest, when empty the experiment is read from the cluster
+	Filename string
+	// SetupTaskName is the name of the Helm setup task to export values for
+	SetupTaskName string
+	// OutputFile is the name of the values file to write, when empty values are written to standard output
+	OutputFile string
+}
+
+// NewValuesCommand creates a new command for exporting Helm values
+func NewValuesCommand(o *ValuesOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "values EXPERIMENT",
+		Short: "Export Helm values",
+		Long:  "Export the Helm values of a trial as a values file which can be merged with the chart values",
+
+		Args: cobra.ExactArgs(1),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.ExperimentName = args[0]
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.values),
+	}
+
+	o.addTrialFlags(cmd)
+	cmd.Flags().StringVarP(&o.Filename, "filename", "f", o.Filename, "File that contains the experiment (defaults to reading the experiment from the cluster).")
+	cmd.Flags().StringVar(&o.SetupTaskName, "setup-task", o.SetupTaskName, "Name of the Helm setup task (required if there are multiple).")
+	cmd.Flags().StringVarP(&o.OutputFile, "output", "o", o.OutputFile, "Write the values to the specified `file`.")
+
+	_ = cmd.MarkFlagFilename("filename", "yml", "yaml", "json")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *ValuesOptions) values(ctx context.Context) error {
+	exp, err := o.clusterExperiment(ctx, o.Filename)
+	if err != nil {
+		return err
+	}
+
+	task, err := o.setupTask(exp)
+	if err != nil {
+		return err
+	}
+
+	t, err := o.trial(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Build a cluster trial so Helm values are evaluated the same way as the setup job
+	values, err := helmValues(task, clusterTrial(exp, t))
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	if o.OutputFile != "" {
+		return ioutil.WriteFile(o.OutputFile, data, 0644)
+	}
+	_, err = o.Out.Write(data)
+	return err
+}
+
+// setupTask returns the Helm setup task to export values for
+func (o *ValuesOptions) setupTask(exp *redskyv1beta1.Experiment) (*redskyv1beta1.SetupTask, error) {
+	var task *redskyv1beta1.SetupTask
+	for i := range exp.Spec.TrialTemplate.Spec.SetupTasks {
+		st := &exp.Spec.TrialTemplate.Spec.SetupTasks[i]
+		if st.HelmChart == "" || (o.SetupTaskName != "" && st.Name != o.SetupTaskName) {
+			continue
+		}
+		if task != nil {
+			return nil, fmt.Errorf("experiment %s has multiple Helm setup tasks, specify the setup task name", exp.Name)
+		}
+		task = st
+	}
+	if task == nil {
+		return nil, fmt.Errorf("experiment %s does not have a Helm setup task", exp.Name)
+	}
+	return task, nil
+}
+
+// helmValues evaluates the setup task Helm values into a nested values map
+func helmValues(task *redskyv1beta1.SetupTask, trial *redskyv1beta1.Trial) (map[string]interface{}, error) {
+	te := template.New()
+	values := make(map[string]interface{})
+	for i := range task.HelmValues {
+		hv := &task.HelmValues[i]
+
+		var value interface{}
+		switch {
+		case hv.ValueFrom != nil && hv.ValueFrom.ParameterRef != nil:
+			v, ok := trial.GetAssignment(hv.ValueFrom.ParameterRef.Name)
+			if !ok {
+				return nil, fmt.Errorf("invalid parameter reference '%s' for Helm value '%s'", hv.ValueFrom.ParameterRef.Name, hv.Name)
+			}
+			if iv, ok := v.Int64Value(); ok {
+				value = iv
+			} else {
+				value = parseValue(v.StrVal, hv.ForceString)
+			}
+		case hv.ValueFrom != nil:
+			return nil, fmt.Errorf("unknown source for Helm value '%s'", hv.Name)
+		default:
+			v, err := te.RenderHelmValue(hv, trial)
+			if err != nil {
+				return nil, err
+			}
+			value = parseValue(v, hv.ForceString)
+		}
+
+		if err := setValue(values, hv.Name, value); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// parseValue converts a rendered value into the type Helm would use for `--set`
+func parseValue(v string, forceString bool) interface{} {
+	if forceString {
+		return v
+	}
+	if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return i
+	}
+	if b, err := strconv.ParseBool(v); err == nil {
+		return b
+	}
+	return v
+}
+
+// setValue assigns a value using a dotted Helm value name, e.g. "resources.limits.cpu"
+func setValue(values map[string]interface{}, name string, value interface{}) error {
+	path := strings.Split(name, ".")
+	m := values
+	for _, p := range path[:len(path)-1] {
+		switch child := m[p].(type) {
+		case map[string]interface{}:
+			m = child
+		case nil:
+			mm := make(map[string]interface{})
+			m[p] = mm
+			m = mm
+		default:
+			return fmt.Errorf("conflicting Helm value '%s'", name)
+		}
+	}
+	m[path[len(path)-1]] = value
+	return nil
+}
@@ -0,0 +1,379 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/shlex"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DialectPostgres generates SQL for PostgreSQL
+	DialectPostgres = "postgres"
+	// DialectBigQuery generates SQL for Google BigQuery
+	DialectBigQuery = "bigquery"
+	// DialectSnowflake generates SQL for Snowflake
+	DialectSnowflake = "snowflake"
+)
+
+// warehouseBatchSize is the maximum number of trial records in a single statement
+const warehouseBatchSize = 500
+
+// WarehouseOptions is the configuration for exporting trial records to a data warehouse
+type WarehouseOptions struct {
+	Options
+
+	// Dialect is the SQL dialect of the warehouse
+	Dialect string
+	// Table is the name of the warehouse table to export trial records into
+	Table string
+	// Exec is a command that executes SQL read from standard input, e.g. `psql "$DATABASE_URL"`; it is split into
+	// shell words and environment variables are expanded, however it is run directly and not through a shell
+	Exec string
+	// Retries is the number of times a failed execution is retried
+	Retries int
+}
+
+// NewWarehouseCommand creates a new command for exporting trial records to a data warehouse
+func NewWarehouseCommand(o *WarehouseOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "warehouse EXPERIMENT",
+		Short: "Export trial records to a data warehouse",
+		Long: `Export the finished trials of an experiment to a data warehouse table
+
+The generated SQL creates the table if necessary, adds columns for any new parameters or metrics and upserts one row
+per trial keyed by the experiment name and trial number; running the export again is always safe. The SQL is written
+to standard output unless a command is supplied to execute it, failed executions are retried.`,
+		Example: `# Export to PostgreSQL
+redskyctl export warehouse my-experiment --dialect postgres --exec 'psql --single-transaction "$DATABASE_URL"'
+
+# Export to BigQuery
+redskyctl export warehouse my-experiment --dialect bigquery --table my_dataset.trials --exec 'bq query --nouse_legacy_sql'
+
+# Export to Snowflake
+redskyctl export warehouse my-experiment --dialect snowflake --exec 'snowsql -o exit_on_error=true'`,
+
+		Args: cobra.ExactArgs(1),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.ExperimentName = args[0]
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.warehouse),
+	}
+
+	cmd.Flags().StringVar(&o.Dialect, "dialect", DialectPostgres, "SQL dialect of the warehouse; one of: postgres|bigquery|snowflake.")
+	cmd.Flags().StringVar(&o.Table, "table", "redskyops_trials", "Name of the warehouse `table`.")
+	cmd.Flags().StringVar(&o.Exec, "exec", o.Exec, "The `command` which executes the SQL read from standard input.")
+	cmd.Flags().IntVar(&o.Retries, "retries", 3, "Number of times to retry a failed execution.")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *WarehouseOptions) warehouse(ctx context.Context) error {
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName(o.ExperimentName))
+	if err != nil {
+		return err
+	}
+
+	var tl experimentsv1alpha1.TrialList
+	if exp.TrialsURL != "" {
+		q := &experimentsv1alpha1.TrialListQuery{Status: []experimentsv1alpha1.TrialStatus{experimentsv1alpha1.TrialCompleted, experimentsv1alpha1.TrialFailed}}
+		if tl, err = o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, q); err != nil {
+			return err
+		}
+	}
+
+	w, err := newWarehouseWriter(o.Dialect, o.Table, &exp)
+	if err != nil {
+		return err
+	}
+
+	var sql strings.Builder
+	w.writeSchema(&sql)
+	for i := 0; i < len(tl.Trials); i += warehouseBatchSize {
+		end := i + warehouseBatchSize
+		if end > len(tl.Trials) {
+			end = len(tl.Trials)
+		}
+		w.writeUpsert(&sql, exp.Name(), tl.Trials[i:end])
+	}
+
+	if o.Exec == "" {
+		_, err := io.WriteString(o.Out, sql.String())
+		return err
+	}
+	return o.execute(ctx, sql.String())
+}
+
+// execute runs the configured command with the supplied SQL on standard input, retrying with an exponential backoff
+func (o *WarehouseOptions) execute(ctx context.Context, sql string) error {
+	args, err := shlex.Split(o.Exec)
+	if err != nil {
+		return fmt.Errorf("invalid exec command: %w", err)
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("invalid exec command: %q", o.Exec)
+	}
+	for i := range args {
+		args[i] = os.ExpandEnv(args[i])
+	}
+
+	delay := time.Second
+	for attempt := 0; ; attempt++ {
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(sql)
+		cmd.Stdout = o.ErrOut
+		cmd.Stderr = o.ErrOut
+		err := cmd.Run()
+		if err == nil || attempt >= o.Retries {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(o.ErrOut, "Export failed (%v), retrying in %s\n", err, delay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// warehouseColumn is a column of the warehouse table
+type warehouseColumn struct {
+	name    string
+	sqlType string
+}
+
+// warehouseWriter generates dialect specific SQL for exporting trial records
+type warehouseWriter struct {
+	dialect string
+	table   string
+	// key columns identify a trial, base columns are always present, the remaining columns depend on the experiment
+	key, base, columns []warehouseColumn
+	// parameters and metrics map remote names to column names
+	parameters, metrics map[string]string
+
+	stringType    string
+	intType       string
+	floatType     string
+	timestampType string
+}
+
+// newWarehouseWriter returns a new writer for the experiment; sensitive parameters are never exported
+func newWarehouseWriter(dialect, table string, exp *experimentsv1alpha1.Experiment) (*warehouseWriter, error) {
+	w := &warehouseWriter{dialect: strings.ToLower(dialect), table: table, parameters: map[string]string{}, metrics: map[string]string{}}
+	switch w.dialect {
+	case DialectPostgres:
+		w.stringType, w.intType, w.floatType, w.timestampType = "TEXT", "BIGINT", "DOUBLE PRECISION", "TIMESTAMP WITH TIME ZONE"
+	case DialectBigQuery:
+		w.stringType, w.intType, w.floatType, w.timestampType = "STRING", "INT64", "FLOAT64", "TIMESTAMP"
+		w.table = "`" + strings.ReplaceAll(table, "`", "") + "`"
+	case DialectSnowflake:
+		w.stringType, w.intType, w.floatType, w.timestampType = "VARCHAR", "INTEGER", "FLOAT", "TIMESTAMP_TZ"
+	default:
+		return nil, fmt.Errorf("unknown dialect: %s", dialect)
+	}
+
+	w.key = []warehouseColumn{{"experiment", w.stringType}, {"trial", w.intType}}
+	w.base = []warehouseColumn{{"status", w.stringType}, {"failure_reason", w.stringType}, {"labels", w.stringType}, {"exported_at", w.timestampType}}
+	for i := range exp.Parameters {
+		p := &exp.Parameters[i]
+		if p.Sensitive {
+			continue
+		}
+		c := warehouseColumn{name: columnName("parameter_", p.Name), sqlType: w.floatType}
+		if p.Type == experimentsv1alpha1.ParameterTypeCategorical {
+			c.sqlType = w.stringType
+		}
+		w.parameters[p.Name] = c.name
+		w.columns = append(w.columns, c)
+	}
+	for i := range exp.Metrics {
+		c := warehouseColumn{name: columnName("metric_", exp.Metrics[i].Name), sqlType: w.floatType}
+		w.metrics[exp.Metrics[i].Name] = c.name
+		w.columns = append(w.columns, c)
+	}
+	return w, nil
+}
+
+// invalidColumnChars matches the characters which are not allowed in an unquoted column name
+var invalidColumnChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// columnName returns a column name which does not need to be quoted in any dialect
+func columnName(prefix, name string) string {
+	return prefix + invalidColumnChars.ReplaceAllString(strings.ToLower(name), "_")
+}
+
+// allColumns returns every column of the table
+func (w *warehouseWriter) allColumns() []warehouseColumn {
+	columns := make([]warehouseColumn, 0, len(w.key)+len(w.base)+len(w.columns))
+	columns = append(columns, w.key...)
+	columns = append(columns, w.base...)
+	return append(columns, w.columns...)
+}
+
+// writeSchema writes the statements which create the table and add any missing columns
+func (w *warehouseWriter) writeSchema(sql *strings.Builder) {
+	var defs []string
+	for _, c := range w.allColumns()[:len(w.key)+len(w.base)] {
+		defs = append(defs, c.name+" "+c.sqlType)
+	}
+	if w.dialect == DialectPostgres {
+		defs = append(defs, "PRIMARY KEY (experiment, trial)")
+	}
+	fmt.Fprintf(sql, "CREATE TABLE IF NOT EXISTS %s (%s);\n", w.table, strings.Join(defs, ", "))
+
+	for _, c := range w.columns {
+		fmt.Fprintf(sql, "ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s;\n", w.table, c.name, c.sqlType)
+	}
+}
+
+// writeUpsert writes a statement which inserts or updates the supplied trials
+func (w *warehouseWriter) writeUpsert(sql *strings.Builder, experimentName string, trials []experimentsv1alpha1.TrialItem) {
+	if len(trials) == 0 {
+		return
+	}
+
+	columns := w.allColumns()
+	names := make([]string, len(columns))
+	for i := range columns {
+		names[i] = columns[i].name
+	}
+
+	rows := make([][]string, len(trials))
+	for i := range trials {
+		rows[i] = w.values(columns, experimentName, &trials[i])
+	}
+
+	if w.dialect == DialectPostgres {
+		var values, updates []string
+		for _, row := range rows {
+			values = append(values, "("+strings.Join(row, ", ")+")")
+		}
+		for _, c := range columns[len(w.key):] {
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", c.name, c.name))
+		}
+		fmt.Fprintf(sql, "INSERT INTO %s (%s) VALUES %s ON CONFLICT (experiment, trial) DO UPDATE SET %s;\n",
+			w.table, strings.Join(names, ", "), strings.Join(values, ", "), strings.Join(updates, ", "))
+		return
+	}
+
+	// BigQuery and Snowflake both support MERGE from a sub-query
+	var selects, updates, sources []string
+	for _, row := range rows {
+		aliased := make([]string, len(row))
+		for i := range row {
+			aliased[i] = row[i] + " AS " + names[i]
+		}
+		selects = append(selects, "SELECT "+strings.Join(aliased, ", "))
+	}
+	for _, c := range columns[len(w.key):] {
+		updates = append(updates, fmt.Sprintf("%s = source.%s", c.name, c.name))
+	}
+	for _, n := range names {
+		sources = append(sources, "source."+n)
+	}
+	fmt.Fprintf(sql, "MERGE INTO %s AS target USING (%s) AS source ON target.experiment = source.experiment AND target.trial = source.trial WHEN MATCHED THEN UPDATE SET %s WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s);\n",
+		w.table, strings.Join(selects, " UNION ALL "), strings.Join(updates, ", "), strings.Join(names, ", "), strings.Join(sources, ", "))
+}
+
+// values returns the SQL literals for a trial record
+func (w *warehouseWriter) values(columns []warehouseColumn, experimentName string, t *experimentsv1alpha1.TrialItem) []string {
+	values := map[string]string{
+		"experiment": w.stringLiteral(experimentName),
+		"trial":      strconv.FormatInt(t.Number, 10),
+		"status":     w.stringLiteral(string(t.Status)),
+	}
+	if t.FailureReason != "" {
+		values["failure_reason"] = w.stringLiteral(t.FailureReason)
+	}
+	if len(t.Labels) > 0 {
+		if labels, err := json.Marshal(t.Labels); err == nil {
+			values["labels"] = w.stringLiteral(string(labels))
+		}
+	}
+	if w.dialect == DialectBigQuery {
+		values["exported_at"] = "CURRENT_TIMESTAMP()"
+	} else {
+		values["exported_at"] = "CURRENT_TIMESTAMP"
+	}
+	for _, a := range t.Assignments {
+		name, ok := w.parameters[a.ParameterName]
+		if !ok {
+			continue
+		}
+		if f, err := a.Value.Float64(); err == nil {
+			if isFinite(f) {
+				values[name] = strconv.FormatFloat(f, 'g', -1, 64)
+			}
+		} else {
+			values[name] = w.stringLiteral(a.Value.String())
+		}
+	}
+	for _, v := range t.Values {
+		// NaN and infinite values have no SQL literal, they are exported as NULL
+		if name, ok := w.metrics[v.MetricName]; ok && isFinite(v.Value) {
+			values[name] = strconv.FormatFloat(v.Value, 'g', -1, 64)
+		}
+	}
+
+	row := make([]string, len(columns))
+	for i, c := range columns {
+		if v, ok := values[c.name]; ok {
+			row[i] = v
+		} else {
+			row[i] = fmt.Sprintf("CAST(NULL AS %s)", c.sqlType)
+		}
+	}
+	return row
+}
+
+// isFinite returns true if the value can be written as a numeric literal
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// stringLiteral returns a quoted string literal
+func (w *warehouseWriter) stringLiteral(s string) string {
+	switch w.dialect {
+	case DialectBigQuery, DialectSnowflake:
+		// Backslash is an escape character in these dialects
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	if w.dialect == DialectBigQuery {
+		return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarehouseWriter(t *testing.T) {
+	exp := &experimentsv1alpha1.Experiment{
+		Parameters: []experimentsv1alpha1.Parameter{
+			{Name: "cpu", Type: experimentsv1alpha1.ParameterTypeInteger},
+			{Name: "tier", Type: experimentsv1alpha1.ParameterTypeCategorical},
+			{Name: "password", Type: experimentsv1alpha1.ParameterTypeCategorical, Sensitive: true},
+		},
+		Metrics: []experimentsv1alpha1.Metric{{Name: "p95-latency"}},
+	}
+	trials := []experimentsv1alpha1.TrialItem{
+		{
+			Number: 1,
+			Status: experimentsv1alpha1.TrialCompleted,
+			TrialAssignments: experimentsv1alpha1.TrialAssignments{Assignments: []experimentsv1alpha1.Assignment{
+				{ParameterName: "cpu", Value: "500"},
+				{ParameterName: "tier", Value: "o'brien"},
+				{ParameterName: "password", Value: "secret"},
+			}},
+			TrialValues: experimentsv1alpha1.TrialValues{Values: []experimentsv1alpha1.Value{{MetricName: "p95-latency", Value: 1.5}}},
+		},
+	}
+
+	cases := []struct {
+		dialect  string
+		table    string
+		expected []string
+		err      string
+	}{
+		{
+			dialect: DialectPostgres,
+			table:   "redskyops_trials",
+			expected: []string{
+				"CREATE TABLE IF NOT EXISTS redskyops_trials (experiment TEXT, trial BIGINT, status TEXT, failure_reason TEXT, labels TEXT, exported_at TIMESTAMP WITH TIME ZONE, PRIMARY KEY (experiment, trial));\n",
+				"ALTER TABLE redskyops_trials ADD COLUMN IF NOT EXISTS parameter_cpu DOUBLE PRECISION;\n",
+				"ALTER TABLE redskyops_trials ADD COLUMN IF NOT EXISTS parameter_tier TEXT;\n",
+				"ALTER TABLE redskyops_trials ADD COLUMN IF NOT EXISTS metric_p95_latency DOUBLE PRECISION;\n",
+				"VALUES ('foo', 1, 'completed', CAST(NULL AS TEXT), CAST(NULL AS TEXT), CURRENT_TIMESTAMP, 500, 'o''brien', 1.5) ON CONFLICT (experiment, trial) DO UPDATE SET status = EXCLUDED.status,",
+			},
+		},
+		{
+			dialect: DialectBigQuery,
+			table:   "my_dataset.trials",
+			expected: []string{
+				"CREATE TABLE IF NOT EXISTS `my_dataset.trials` (experiment STRING, trial INT64, status STRING, failure_reason STRING, labels STRING, exported_at TIMESTAMP);\n",
+				"ALTER TABLE `my_dataset.trials` ADD COLUMN IF NOT EXISTS metric_p95_latency FLOAT64;\n",
+				"MERGE INTO `my_dataset.trials` AS target USING (SELECT 'foo' AS experiment, 1 AS trial, 'completed' AS status,",
+				`'o\'brien' AS parameter_tier`,
+			},
+		},
+		{
+			dialect: DialectSnowflake,
+			table:   "trials",
+			expected: []string{
+				"CREATE TABLE IF NOT EXISTS trials (experiment VARCHAR, trial INTEGER, status VARCHAR, failure_reason VARCHAR, labels VARCHAR, exported_at TIMESTAMP_TZ);\n",
+				"WHEN NOT MATCHED THEN INSERT (experiment, trial, status, failure_reason, labels, exported_at, parameter_cpu, parameter_tier, metric_p95_latency) VALUES (source.experiment,",
+			},
+		},
+		{
+			dialect: "oracle",
+			err:     "unknown dialect: oracle",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.dialect, func(t *testing.T) {
+			w, err := newWarehouseWriter(c.dialect, c.table, exp)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			var sql strings.Builder
+			w.writeSchema(&sql)
+			w.writeUpsert(&sql, "foo", trials)
+			for _, e := range c.expected {
+				assert.Contains(t, sql.String(), e)
+			}
+			assert.NotContains(t, sql.String(), "password")
+			assert.NotContains(t, sql.String(), "secret")
+		})
+	}
+}
+
+func TestWarehouseWriter_NonFinite(t *testing.T) {
+	exp := &experimentsv1alpha1.Experiment{
+		Metrics: []experimentsv1alpha1.Metric{{Name: "nan"}, {Name: "inf"}},
+	}
+	trials := []experimentsv1alpha1.TrialItem{
+		{
+			Number: 1,
+			Status: experimentsv1alpha1.TrialCompleted,
+			TrialValues: experimentsv1alpha1.TrialValues{Values: []experimentsv1alpha1.Value{
+				{MetricName: "nan", Value: math.NaN()},
+				{MetricName: "inf", Value: math.Inf(1)},
+			}},
+		},
+	}
+
+	w, err := newWarehouseWriter(DialectPostgres, "trials", exp)
+	if assert.NoError(t, err) {
+		var sql strings.Builder
+		w.writeUpsert(&sql, "foo", trials)
+		assert.Contains(t, sql.String(), "CURRENT_TIMESTAMP, CAST(NULL AS DOUBLE PRECISION), CAST(NULL AS DOUBLE PRECISION))")
+		assert.NotContains(t, sql.String(), "NaN")
+		assert.NotContains(t, sql.String(), "Inf")
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/config"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// tunableEnv matches the names of environment variables which commonly hold tuning knobs
+var tunableEnv = regexp.MustCompile(`(?i)(THREADS|WORKERS|PROCESSES|CONNECTIONS|CONCURRENCY|POOL|HEAP|CACHE|BUFFER|QUEUE|BATCH|GOGC|GOMAXPROCS)`)
+
+// ExperimentOptions are the options for generating an experiment from a running workload
+type ExperimentOptions struct {
+	// Config is the Red Sky Configuration used to access the cluster
+	Config *config.RedSkyConfig
+	// Printer is the resource printer used to render generated objects
+	Printer commander.ResourcePrinter
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	From string
+	Name string
+}
+
+// workload is a controller with a pod template, e.g. a deployment, stateful set or daemon set
+type workload struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Template corev1.PodTemplateSpec `json:"template"`
+	} `json:"spec"`
+}
+
+// NewExperimentCommand creates a new command for generating an experiment from a running workload
+func NewExperimentCommand(o *ExperimentOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "experiment",
+		Short: "Generate an experiment",
+		Long: "Generate an experiment manifest from a running workload. The resources of each container and any " +
+			"numeric environment variables that look like tuning knobs become parameters; the generated metrics " +
+			"and trial job are placeholders which should be edited before the experiment is created.",
+
+		Example: `# Generate an experiment for the containers of a deployment
+redskyctl generate experiment --from deployment/my-app > experiment.yaml`,
+
+		Annotations: map[string]string{
+			commander.PrinterAllowedFormats: "json,yaml",
+			commander.PrinterOutputFormat:   "yaml",
+			commander.PrinterHideStatus:     "true",
+		},
+
+		PreRun: commander.StreamsPreRun(&o.IOStreams),
+		RunE:   commander.WithContextE(o.generate),
+	}
+
+	cmd.Flags().StringVar(&o.From, "from", o.From, "Workload to generate the experiment from, as `KIND/NAME`.")
+	cmd.Flags().StringVar(&o.Name, "name", o.Name, "Name of the generated experiment (default is to use the workload name).")
+
+	_ = cmd.MarkFlagRequired("from")
+
+	commander.SetKubePrinter(&o.Printer, cmd)
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *ExperimentOptions) generate(ctx context.Context) error {
+	cmd, err := o.Config.Kubectl(ctx, "get", o.From, "--output", "json")
+	if err != nil {
+		return err
+	}
+	cmd.Stderr = o.ErrOut
+	data, err := cmd.Output()
+	if err != nil {
+		return err
+	}
+
+	w := &workload{}
+	if err := json.Unmarshal(data, w); err != nil {
+		return err
+	}
+	if len(w.Spec.Template.Spec.Containers) == 0 {
+		return fmt.Errorf("%s does not have a pod template", o.From)
+	}
+
+	exp, err := newExperiment(w)
+	if err != nil {
+		return err
+	}
+	if o.Name != "" {
+		exp.Name = o.Name
+	}
+
+	return o.Printer.PrintObj(exp, o.Out)
+}
+
+// newExperiment returns an experiment which tunes the containers of the supplied workload
+func newExperiment(w *workload) (*redskyv1beta1.Experiment, error) {
+	exp := &redskyv1beta1.Experiment{}
+	exp.APIVersion = redskyv1beta1.GroupVersion.String()
+	exp.Kind = "Experiment"
+	exp.Name = w.Name
+	exp.Namespace = w.Namespace
+
+	var containers []interface{}
+	for i := range w.Spec.Template.Spec.Containers {
+		c := &w.Spec.Template.Spec.Containers[i]
+
+		// Only prefix the parameter names when there is more than one container
+		prefix := ""
+		if len(w.Spec.Template.Spec.Containers) > 1 {
+			prefix = workloadParameterName(c.Name) + "_"
+		}
+
+		container := map[string]interface{}{"name": c.Name}
+		cpu, memory := prefix+"cpu", prefix+"memory"
+		exp.Spec.Parameters = append(exp.Spec.Parameters,
+			newParameter(cpu, resourceValue(c, corev1.ResourceCPU), 100, 2000),
+			newParameter(memory, resourceValue(c, corev1.ResourceMemory), 64, 1024))
+		resources := map[string]interface{}{
+			"cpu":    fmt.Sprintf("{{ .Values.%s }}m", cpu),
+			"memory": fmt.Sprintf("{{ .Values.%s }}Mi", memory),
+		}
+		container["resources"] = map[string]interface{}{"requests": resources}
+		if len(c.Resources.Limits) > 0 {
+			container["resources"] = map[string]interface{}{"requests": resources, "limits": resources}
+		}
+
+		var env []interface{}
+		for _, e := range c.Env {
+			v, err := strconv.ParseInt(e.Value, 10, 64)
+			if err != nil || v <= 0 || e.ValueFrom != nil || !tunableEnv.MatchString(e.Name) {
+				continue
+			}
+			name := prefix + workloadParameterName(e.Name)
+			exp.Spec.Parameters = append(exp.Spec.Parameters, newParameter(name, v, 1, 1))
+			env = append(env, map[string]interface{}{"name": e.Name, "value": fmt.Sprintf("{{ .Values.%s }}", name)})
+		}
+		if len(env) > 0 {
+			container["env"] = env
+		}
+
+		containers = append(containers, container)
+	}
+
+	patch, err := yaml.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{"containers": containers},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	exp.Spec.Patches = []redskyv1beta1.PatchTemplate{{
+		TargetRef: &corev1.ObjectReference{Kind: w.Kind, APIVersion: w.APIVersion, Name: w.Name},
+		Patch:     string(patch),
+	}}
+
+	// Placeholder metrics, the run duration of the default trial job is not a meaningful measurement
+	exp.Spec.Metrics = []redskyv1beta1.Metric{
+		{
+			Name:     "duration",
+			Minimize: true,
+			Query:    "{{duration .StartTime .CompletionTime}}",
+		},
+		{
+			Name:     "cost",
+			Minimize: true,
+			Type:     redskyv1beta1.MetricPods,
+			Query:    `{{resourceRequests .Pods "cpu=0.017,memory=0.000000000003"}}`,
+			Selector: &metav1.LabelSelector{MatchLabels: w.Spec.Template.Labels},
+		},
+	}
+
+	return exp, nil
+}
+
+// newParameter returns a parameter ranging from half to twice the current value, the default range is used if there
+// is no current value
+func newParameter(name string, value, defaultMin, defaultMax int64) redskyv1beta1.Parameter {
+	if value <= 0 {
+		return redskyv1beta1.Parameter{Name: name, Min: redskyv1beta1.NewParameterBound(defaultMin), Max: redskyv1beta1.NewParameterBound(defaultMax)}
+	}
+	min := value / 2
+	if min < 1 {
+		min = 1
+	}
+	return redskyv1beta1.Parameter{Name: name, Min: redskyv1beta1.NewParameterBound(min), Max: redskyv1beta1.NewParameterBound(value * 2)}
+}
+
+// resourceValue returns the current request (or limit) of a container in millicores or mebibytes
+func resourceValue(c *corev1.Container, name corev1.ResourceName) int64 {
+	q, ok := c.Resources.Requests[name]
+	if !ok {
+		q, ok = c.Resources.Limits[name]
+	}
+	if !ok {
+		return 0
+	}
+	if name == corev1.ResourceCPU {
+		return q.MilliValue()
+	}
+	return q.Value() / (1024 * 1024)
+}
+
+// workloadParameterName converts a container or environment variable name into a parameter name
+func workloadParameterName(name string) string {
+	return strings.ToLower(strings.NewReplacer("-", "_", ".", "_").Replace(name))
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+func TestNewExperiment(t *testing.T) {
+	cases := []struct {
+		desc       string
+		containers []corev1.Container
+		parameters []redskyv1beta1.Parameter
+		patch      string
+	}{
+		{
+			desc: "requests and limits",
+			containers: []corev1.Container{
+				{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("500m"),
+							corev1.ResourceMemory: resource.MustParse("256Mi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("1"),
+						},
+					},
+					Env: []corev1.EnvVar{
+						{Name: "WORKER_THREADS", Value: "4"},
+						{Name: "LOG_LEVEL", Value: "debug"},
+						{Name: "MAX_CONNECTIONS", Value: "unlimited"},
+					},
+				},
+			},
+			parameters: []redskyv1beta1.Parameter{
+				{Name: "cpu", Min: redskyv1beta1.NewParameterBound(250), Max: redskyv1beta1.NewParameterBound(1000)},
+				{Name: "memory", Min: redskyv1beta1.NewParameterBound(128), Max: redskyv1beta1.NewParameterBound(512)},
+				{Name: "worker_threads", Min: redskyv1beta1.NewParameterBound(2), Max: redskyv1beta1.NewParameterBound(8)},
+			},
+			patch: `
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        env:
+        - name: WORKER_THREADS
+          value: "{{ .Values.worker_threads }}"
+        resources:
+          limits:
+            cpu: "{{ .Values.cpu }}m"
+            memory: "{{ .Values.memory }}Mi"
+          requests:
+            cpu: "{{ .Values.cpu }}m"
+            memory: "{{ .Values.memory }}Mi"
+`,
+		},
+		{
+			desc: "multiple containers",
+			containers: []corev1.Container{
+				{Name: "app"},
+				{Name: "log-shipper"},
+			},
+			parameters: []redskyv1beta1.Parameter{
+				{Name: "app_cpu", Min: redskyv1beta1.NewParameterBound(100), Max: redskyv1beta1.NewParameterBound(2000)},
+				{Name: "app_memory", Min: redskyv1beta1.NewParameterBound(64), Max: redskyv1beta1.NewParameterBound(1024)},
+				{Name: "log_shipper_cpu", Min: redskyv1beta1.NewParameterBound(100), Max: redskyv1beta1.NewParameterBound(2000)},
+				{Name: "log_shipper_memory", Min: redskyv1beta1.NewParameterBound(64), Max: redskyv1beta1.NewParameterBound(1024)},
+			},
+			patch: `
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        resources:
+          requests:
+            cpu: "{{ .Values.app_cpu }}m"
+            memory: "{{ .Values.app_memory }}Mi"
+      - name: log-shipper
+        resources:
+          requests:
+            cpu: "{{ .Values.log_shipper_cpu }}m"
+            memory: "{{ .Values.log_shipper_memory }}Mi"
+`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			w := &workload{}
+			w.APIVersion = "apps/v1"
+			w.Kind = "Deployment"
+			w.Name = "my-app"
+			w.Namespace = "default"
+			w.Spec.Template.Labels = map[string]string{"app": "my-app"}
+			w.Spec.Template.Spec.Containers = c.containers
+
+			exp, err := newExperiment(w)
+			require.NoError(t, err)
+			assert.Equal(t, "my-app", exp.Name)
+			assert.Equal(t, c.parameters, exp.Spec.Parameters)
+			if assert.Len(t, exp.Spec.Patches, 1) {
+				assert.Equal(t, "Deployment", exp.Spec.Patches[0].TargetRef.Kind)
+				assert.Equal(t, "my-app", exp.Spec.Patches[0].TargetRef.Name)
+
+				var expected, actual interface{}
+				require.NoError(t, yaml.Unmarshal([]byte(c.patch), &expected))
+				require.NoError(t, yaml.Unmarshal([]byte(exp.Spec.Patches[0].Patch), &actual))
+				assert.Equal(t, expected, actual)
+			}
+			if assert.Len(t, exp.Spec.Metrics, 2) {
+				assert.Equal(t, map[string]string{"app": "my-app"}, exp.Spec.Metrics[1].Selector.MatchLabels)
+			}
+		})
+	}
+}
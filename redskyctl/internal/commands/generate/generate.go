@@ -48,6 +48,8 @@ func NewCommand(o *Options) *cobra.Command {
 		Long:  "Generate Red Sky Ops object manifests",
 	}
 
+	cmd.AddCommand(NewExperimentCommand(&ExperimentOptions{Config: o.Config}))
+	cmd.AddCommand(NewHelmCommand(&HelmOptions{}))
 	cmd.AddCommand(NewRBACCommand(&RBACOptions{Config: o.Config, ClusterRole: true, ClusterRoleBinding: true}))
 	cmd.AddCommand(NewTrialCommand(&TrialOptions{}))
 
@@ -59,8 +61,8 @@ func NewCommand(o *Options) *cobra.Command {
 	return cmd
 }
 
-// readExperiments unmarshals experiment data
-func readExperiments(filename string, defaultReader io.Reader, list *redskyv1beta1.ExperimentList) error {
+// ReadExperiments unmarshals experiment data from the named file, "-" reads from the supplied default reader
+func ReadExperiments(filename string, defaultReader io.Reader, list *redskyv1beta1.ExperimentList) error {
 	if filename == "" {
 		return nil
 	}
@@ -0,0 +1,242 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// tunableValueName matches the names of numeric Helm values which are likely to impact performance
+var tunableValueName = regexp.MustCompile(`(?i)(replicas|replicaCount|size|pool|connections|workers|threads|heap|buffer|cache)`)
+
+// HelmOptions is the configuration for generating an experiment from a Helm chart
+type HelmOptions struct {
+	// Printer is the resource printer used to render generated objects
+	Printer commander.ResourcePrinter
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// Chart is the path to the chart directory
+	Chart string
+	// Name is the name of the generated experiment, defaults to the chart name
+	Name string
+}
+
+// NewHelmCommand creates a new command for generating an experiment from a Helm chart
+func NewHelmCommand(o *HelmOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "helm CHART",
+		Short: "Generate an experiment from a Helm chart",
+		Long:  "Generate an experiment by scanning the values of a Helm chart for tunable parameters",
+
+		Args: cobra.ExactArgs(1),
+
+		Annotations: map[string]string{
+			commander.PrinterAllowedFormats: "json,yaml",
+			commander.PrinterOutputFormat:   "yaml",
+			commander.PrinterHideStatus:     "true",
+		},
+
+		PreRun: func(cmd *cobra.Command, args []string) {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.Chart = args[0]
+		},
+		RunE: commander.WithoutArgsE(o.generate),
+	}
+
+	cmd.Flags().StringVar(&o.Name, "name", o.Name, "Name of the experiment to generate (defaults to the chart name).")
+
+	commander.SetKubePrinter(&o.Printer, cmd)
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+// helmValue is a tunable value discovered in the chart values
+type helmValue struct {
+	path      string
+	parameter redskyv1beta1.Parameter
+	template  string
+}
+
+func (o *HelmOptions) generate() error {
+	chart := struct {
+		Name string `json:"name"`
+	}{}
+	if err := readYAML(filepath.Join(o.Chart, "Chart.yaml"), &chart); err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{})
+	if err := readYAML(filepath.Join(o.Chart, "values.yaml"), &values); err != nil {
+		return err
+	}
+
+	schema := make(map[string]interface{})
+	if err := readYAML(filepath.Join(o.Chart, "values.schema.json"), &schema); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Scan the values for tunable parameters
+	var hvs []helmValue
+	scanValues(nil, values, schema, &hvs)
+	if len(hvs) == 0 {
+		return fmt.Errorf("no tunable values found in chart %s", chart.Name)
+	}
+	sort.Slice(hvs, func(i, j int) bool { return hvs[i].path < hvs[j].path })
+
+	// Build the experiment
+	exp := &redskyv1beta1.Experiment{}
+	exp.Name = o.Name
+	if exp.Name == "" {
+		exp.Name = chart.Name
+	}
+	exp.Spec.Metrics = []redskyv1beta1.Metric{
+		{Name: "duration", Minimize: true, Query: "{{duration .StartTime .CompletionTime}}"},
+	}
+	task := redskyv1beta1.SetupTask{Name: chart.Name, HelmChart: o.Chart}
+	for _, hv := range hvs {
+		exp.Spec.Parameters = append(exp.Spec.Parameters, hv.parameter)
+		task.HelmValues = append(task.HelmValues, redskyv1beta1.HelmValue{
+			Name:  hv.path,
+			Value: intstr.FromString(hv.template),
+		})
+	}
+	exp.Spec.TrialTemplate.Spec.SetupTasks = []redskyv1beta1.SetupTask{task}
+
+	return o.Printer.PrintObj(exp, o.Out)
+}
+
+// scanValues recursively searches the chart values for tunable numbers
+func scanValues(path []string, values, schema map[string]interface{}, hvs *[]helmValue) {
+	for k, v := range values {
+		p := append(append([]string(nil), path...), k)
+		s := schemaProperty(schema, k)
+
+		switch vv := v.(type) {
+		case map[string]interface{}:
+			scanValues(p, vv, s, hvs)
+		case float64:
+			if tunableValueName.MatchString(k) && vv == float64(int64(vv)) {
+				*hvs = append(*hvs, newIntegerValue(p, int64(vv), s))
+			}
+		case string:
+			// Only consider resources, e.g. "resources.requests.cpu" or "resources.limits.memory"
+			if len(p) >= 3 && p[len(p)-3] == "resources" {
+				if hv, ok := newResourceValue(p, vv); ok {
+					*hvs = append(*hvs, hv)
+				}
+			}
+		}
+	}
+}
+
+// newIntegerValue returns a tunable integer value, bounds are taken from the schema or default to half and double the current value
+func newIntegerValue(path []string, v int64, schema map[string]interface{}) helmValue {
+	hv := helmValue{
+		path:     strings.Join(path, "."),
+		template: fmt.Sprintf("{{ .Values.%s }}", parameterName(path)),
+	}
+	hv.parameter.Name = parameterName(path)
+	hv.parameter.Min, hv.parameter.Max = redskyv1beta1.NewParameterBound(v/2), redskyv1beta1.NewParameterBound(v*2)
+	if v < 2 {
+		hv.parameter.Min, hv.parameter.Max = redskyv1beta1.NewParameterBound(1), redskyv1beta1.NewParameterBound(4)
+	}
+	if min, ok := schema["minimum"].(float64); ok {
+		hv.parameter.Min = redskyv1beta1.NewParameterBound(int64(min))
+	}
+	if max, ok := schema["maximum"].(float64); ok {
+		hv.parameter.Max = redskyv1beta1.NewParameterBound(int64(max))
+	}
+	return hv
+}
+
+// newResourceValue returns a tunable CPU (in millicores) or memory (in mebibytes) value
+func newResourceValue(path []string, v string) (helmValue, bool) {
+	q, err := resource.ParseQuantity(v)
+	if err != nil {
+		return helmValue{}, false
+	}
+
+	var current int64
+	var suffix string
+	switch path[len(path)-1] {
+	case "cpu":
+		current, suffix = q.MilliValue(), "m"
+	case "memory":
+		current, suffix = q.Value()/(1024*1024), "Mi"
+	default:
+		return helmValue{}, false
+	}
+	if current < 2 {
+		return helmValue{}, false
+	}
+
+	hv := helmValue{
+		path:     strings.Join(path, "."),
+		template: fmt.Sprintf("{{ .Values.%s }}%s", parameterName(path), suffix),
+	}
+	hv.parameter.Name = parameterName(path)
+	hv.parameter.Min, hv.parameter.Max = redskyv1beta1.NewParameterBound(current/2), redskyv1beta1.NewParameterBound(current*2)
+	return hv, true
+}
+
+// parameterName converts a value path into a valid parameter name
+func parameterName(path []string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, strings.Join(path, "_"))
+}
+
+// schemaProperty returns the JSON schema of the named property
+func schemaProperty(schema map[string]interface{}, name string) map[string]interface{} {
+	if props, ok := schema["properties"].(map[string]interface{}); ok {
+		if s, ok := props[name].(map[string]interface{}); ok {
+			return s
+		}
+	}
+	return nil
+}
+
+// readYAML reads a YAML (or JSON) file into the supplied object
+func readYAML(filename string, obj interface{}) error {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	if filepath.Ext(filename) == ".json" {
+		return json.Unmarshal(data, obj)
+	}
+	return yaml.Unmarshal(data, obj)
+}
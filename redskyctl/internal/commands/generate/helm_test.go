@@ -0,0 +1,131 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generate
+
+import (
+	"sort"
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestScanValues(t *testing.T) {
+	cases := []struct {
+		desc     string
+		values   string
+		schema   string
+		expected []helmValue
+	}{
+		{
+			desc: "nested",
+			values: `
+replicaCount: 3
+server:
+  workers: 8
+  threadPool:
+    size: 16
+`,
+			expected: []helmValue{
+				{path: "replicaCount", template: "{{ .Values.replicaCount }}", parameter: redskyv1beta1.Parameter{Name: "replicaCount", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(6)}},
+				{path: "server.threadPool.size", template: "{{ .Values.server_threadPool_size }}", parameter: redskyv1beta1.Parameter{Name: "server_threadPool_size", Min: redskyv1beta1.NewParameterBound(8), Max: redskyv1beta1.NewParameterBound(32)}},
+				{path: "server.workers", template: "{{ .Values.server_workers }}", parameter: redskyv1beta1.Parameter{Name: "server_workers", Min: redskyv1beta1.NewParameterBound(4), Max: redskyv1beta1.NewParameterBound(16)}},
+			},
+		},
+		{
+			desc: "small values",
+			values: `
+replicas: 1
+`,
+			expected: []helmValue{
+				{path: "replicas", template: "{{ .Values.replicas }}", parameter: redskyv1beta1.Parameter{Name: "replicas", Min: redskyv1beta1.NewParameterBound(1), Max: redskyv1beta1.NewParameterBound(4)}},
+			},
+		},
+		{
+			desc: "schema bounds",
+			values: `
+replicas: 3
+cache:
+  size: 100
+`,
+			schema: `
+properties:
+  replicas:
+    type: integer
+    minimum: 2
+    maximum: 10
+  cache:
+    type: object
+    properties:
+      size:
+        type: integer
+        maximum: 1000
+`,
+			expected: []helmValue{
+				{path: "cache.size", template: "{{ .Values.cache_size }}", parameter: redskyv1beta1.Parameter{Name: "cache_size", Min: redskyv1beta1.NewParameterBound(50), Max: redskyv1beta1.NewParameterBound(1000)}},
+				{path: "replicas", template: "{{ .Values.replicas }}", parameter: redskyv1beta1.Parameter{Name: "replicas", Min: redskyv1beta1.NewParameterBound(2), Max: redskyv1beta1.NewParameterBound(10)}},
+			},
+		},
+		{
+			desc: "resources",
+			values: `
+resources:
+  requests:
+    cpu: 500m
+    memory: 256Mi
+  limits:
+    cpu: "1"
+    memory: 1Gi
+    ephemeral-storage: 1Gi
+`,
+			expected: []helmValue{
+				{path: "resources.limits.cpu", template: "{{ .Values.resources_limits_cpu }}m", parameter: redskyv1beta1.Parameter{Name: "resources_limits_cpu", Min: redskyv1beta1.NewParameterBound(500), Max: redskyv1beta1.NewParameterBound(2000)}},
+				{path: "resources.limits.memory", template: "{{ .Values.resources_limits_memory }}Mi", parameter: redskyv1beta1.Parameter{Name: "resources_limits_memory", Min: redskyv1beta1.NewParameterBound(512), Max: redskyv1beta1.NewParameterBound(2048)}},
+				{path: "resources.requests.cpu", template: "{{ .Values.resources_requests_cpu }}m", parameter: redskyv1beta1.Parameter{Name: "resources_requests_cpu", Min: redskyv1beta1.NewParameterBound(250), Max: redskyv1beta1.NewParameterBound(1000)}},
+				{path: "resources.requests.memory", template: "{{ .Values.resources_requests_memory }}Mi", parameter: redskyv1beta1.Parameter{Name: "resources_requests_memory", Min: redskyv1beta1.NewParameterBound(128), Max: redskyv1beta1.NewParameterBound(512)}},
+			},
+		},
+		{
+			desc: "not tunable",
+			values: `
+port: 8080
+image:
+  tag: "1.0"
+timeout: 30
+cache:
+  ratio: 0.5
+  enabled: true
+cpu: 500m
+`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			values := make(map[string]interface{})
+			require.NoError(t, yaml.Unmarshal([]byte(c.values), &values))
+			schema := make(map[string]interface{})
+			require.NoError(t, yaml.Unmarshal([]byte(c.schema), &schema))
+
+			var hvs []helmValue
+			scanValues(nil, values, schema, &hvs)
+			sort.Slice(hvs, func(i, j int) bool { return hvs[i].path < hvs[j].path })
+			assert.Equal(t, c.expected, hvs)
+		})
+	}
+}
@@ -98,14 +98,25 @@ func (o *RBACOptions) Complete() {
 func (o *RBACOptions) generate() error {
 	// Read the experiments
 	experimentList := &redskyv1beta1.ExperimentList{}
-	if err := readExperiments(o.Filename, o.In, experimentList); err != nil {
+	if err := ReadExperiments(o.Filename, o.In, experimentList); err != nil {
 		return err
 	}
 
+	// Build the roles and bindings
+	rbac, err := o.BuildRBAC(experimentList)
+	if err != nil || rbac == nil {
+		return err
+	}
+	return o.Printer.PrintObj(rbac, o.Out)
+}
+
+// BuildRBAC returns the roles and bindings needed to patch the targets of the supplied experiments, the result is nil
+// if the experiments do not require any permissions
+func (o *RBACOptions) BuildRBAC(experimentList *redskyv1beta1.ExperimentList) (*corev1.List, error) {
 	// Determine the binding targets
 	roleRef, subject, namespaces, err := o.bindingTargets(experimentList)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Discover the policy rules from the experiments and collapse them
@@ -118,12 +129,11 @@ func (o *RBACOptions) generate() error {
 		rules = mergeRule(rules, r)
 	}
 	if len(rules) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	// Add up all the objects and print them out
-	rbac := buildRBAC(roleRef, subject, rules, namespaces)
-	return o.Printer.PrintObj(rbac, o.Out)
+	// Add up all the objects
+	return buildRBAC(roleRef, subject, rules, namespaces), nil
 }
 
 func (o *RBACOptions) bindingTargets(experimentList *redskyv1beta1.ExperimentList) (*rbacv1.RoleRef, *rbacv1.Subject, []string, error) {
@@ -66,7 +66,7 @@ func NewTrialCommand(o *TrialOptions) *cobra.Command {
 func (o *TrialOptions) generate() error {
 	// Read the experiments
 	experimentList := &redskyv1beta1.ExperimentList{}
-	if err := readExperiments(o.Filename, o.In, experimentList); err != nil {
+	if err := ReadExperiments(o.Filename, o.In, experimentList); err != nil {
 		return err
 	}
 	if len(experimentList.Items) != 1 {
@@ -88,7 +88,7 @@ func (o *TrialOptions) generate() error {
 	// Build the trial
 	t := &redskyv1beta1.Trial{}
 	experiment.PopulateTrialFromTemplate(exp, t)
-	server.ToClusterTrial(t, sug)
+	server.ToClusterTrial(t, sug, exp.Spec.Parameters)
 
 	// NOTE: Leaving the trial name empty and generateName non-empty means that you MUST use `kubectl create` and not `apply`
 
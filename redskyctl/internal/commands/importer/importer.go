@@ -0,0 +1,144 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// FormatArchive reads trials from an archive produced by `redskyctl export experiment --archive`
+	FormatArchive = "archive"
+	// FormatSnapshot reads trials from a snapshot produced by `redskyctl export experiment`
+	FormatSnapshot = "snapshot"
+	// FormatCSV reads trials from a CSV file with the columns produced by `redskyctl get trials -o csv`
+	FormatCSV = "csv"
+)
+
+// reportBatchSize is the maximum number of trials reported in a single request
+const reportBatchSize = 100
+
+// Options is the configuration for importing historical trials
+type Options struct {
+	// Config is the Red Sky Configuration
+	Config config.Config
+	// ExperimentsAPI is used to interact with the Red Sky Experiments API
+	ExperimentsAPI experimentsv1alpha1.API
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// ExperimentName is the name of the experiment to import trials into
+	ExperimentName string
+	// Filename is the file to read trials from
+	Filename string
+	// Format is the format of the file, inferred from the file name when empty
+	Format string
+	// DryRun only reports the trials which would be imported
+	DryRun bool
+}
+
+// NewCommand creates a new command for importing historical trials
+func NewCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import EXPERIMENT FILE",
+		Short: "Import historical trials",
+		Long:  "Replay the finished trials from an exported experiment or CSV file so the experiment starts with prior knowledge",
+		Example: `# Seed a new experiment with the results of a previous experiment
+redskyctl export experiment my-experiment -o my-experiment.yaml
+redskyctl import my-new-experiment my-experiment.yaml
+
+# Import benchmark results using the same columns as 'redskyctl get trials -o csv'
+redskyctl import my-experiment benchmarks.csv`,
+
+		Args: cobra.ExactArgs(2),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.ExperimentName = args[0]
+			o.Filename = args[1]
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.importTrials),
+	}
+
+	cmd.Flags().StringVar(&o.Format, "format", o.Format, "Format of the file; one of: archive|snapshot|csv (defaults to the file extension).")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun, "Only report the trials which would be imported.")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *Options) importTrials(ctx context.Context) error {
+	trials, err := o.readTrials()
+	if err != nil {
+		return err
+	}
+
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName(o.ExperimentName))
+	if err != nil {
+		return err
+	}
+
+	reports, skipped := trialReports(&exp, trials)
+	for _, s := range skipped {
+		_, _ = fmt.Fprintf(o.ErrOut, "Skipping %s\n", s)
+	}
+
+	if o.DryRun {
+		_, _ = fmt.Fprintf(o.Out, "%d trials would be imported into experiment %s\n", len(reports), o.ExperimentName)
+		return nil
+	}
+
+	progress := o.NewProgress("Importing trials", len(reports))
+	for i := 0; i < len(reports); i += reportBatchSize {
+		end := i + reportBatchSize
+		if end > len(reports) {
+			end = len(reports)
+		}
+		if err := o.ExperimentsAPI.ReportTrials(ctx, exp.SelfURL, reports[i:end]); err != nil {
+			progress.Done(err)
+			return err
+		}
+		progress.Add(end - i)
+	}
+	progress.Done(nil)
+
+	_, _ = fmt.Fprintf(o.Out, "%d trials imported into experiment %s\n", len(reports), o.ExperimentName)
+	return nil
+}
+
+// readTrials reads the historical trials from the configured file
+func (o *Options) readTrials() ([]experimentsv1alpha1.TrialItem, error) {
+	var r io.Reader = o.In
+	if o.Filename != "-" {
+		f, err := os.Open(o.Filename)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return readTrials(r, o.Filename, o.Format)
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+// snapshot is the subset of an exported experiment snapshot needed to replay the trials
+type snapshot struct {
+	Trials []experimentsv1alpha1.TrialItem `json:"trials"`
+}
+
+// readTrials reads historical trials from an exported archive, an exported snapshot or a CSV file; when the format is
+// not specified it is inferred from the file name
+func readTrials(r io.Reader, filename, format string) ([]experimentsv1alpha1.TrialItem, error) {
+	if format == "" {
+		name := strings.ToLower(filename)
+		switch {
+		case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+			format = FormatArchive
+		case strings.HasSuffix(name, ".csv"):
+			format = FormatCSV
+		default:
+			format = FormatSnapshot
+		}
+	}
+
+	switch strings.ToLower(format) {
+	case FormatArchive:
+		return readArchive(r)
+	case FormatCSV:
+		return readCSV(r)
+	case FormatSnapshot:
+		return readSnapshot(r)
+	default:
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+}
+
+// readArchive reads the snapshot from an exported archive
+func readArchive(r io.Reader) ([]experimentsv1alpha1.TrialItem, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("archive does not contain an experiment snapshot")
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch path.Base(h.Name) {
+		case "experiment.yaml", "experiment.json":
+			return readSnapshot(tr)
+		}
+	}
+}
+
+// readSnapshot reads an exported experiment snapshot
+func readSnapshot(r io.Reader) ([]experimentsv1alpha1.TrialItem, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	s := &snapshot{}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s.Trials, nil
+}
+
+// readCSV reads trials using the same columns produced by `redskyctl get trials -o csv`: parameter assignments and
+// metric values use "parameter_" and "metric_" prefixed columns, labels use "label_" prefixed columns and the
+// optional "status" column identifies failed trials
+func readCSV(r io.Reader) ([]experimentsv1alpha1.TrialItem, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	var trials []experimentsv1alpha1.TrialItem
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return trials, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		t := experimentsv1alpha1.TrialItem{Status: experimentsv1alpha1.TrialCompleted}
+		for i, column := range header {
+			value := record[i]
+			if value == "" {
+				continue
+			}
+
+			switch {
+			case column == "number":
+				if t.Number, err = strconv.ParseInt(value, 10, 64); err != nil {
+					return nil, fmt.Errorf("invalid trial number on line %d: %w", line, err)
+				}
+			case column == "status":
+				t.Status = experimentsv1alpha1.TrialStatus(strings.ToLower(value))
+			case strings.HasPrefix(column, "parameter_"):
+				t.Assignments = append(t.Assignments, experimentsv1alpha1.Assignment{
+					ParameterName: strings.TrimPrefix(column, "parameter_"),
+					Value:         json.Number(value),
+				})
+			case strings.HasPrefix(column, "metric_"):
+				v, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value for %s on line %d: %w", column, line, err)
+				}
+				t.Values = append(t.Values, experimentsv1alpha1.Value{MetricName: strings.TrimPrefix(column, "metric_"), Value: v})
+			case strings.HasPrefix(column, "label_"):
+				if t.Labels == nil {
+					t.Labels = make(map[string]string)
+				}
+				t.Labels[strings.TrimPrefix(column, "label_")] = value
+			}
+		}
+		trials = append(trials, t)
+	}
+}
+
+// trialReports converts historical trials into reports for the supplied experiment, trials which cannot be replayed
+// (for example, because they are still running or are missing assignments) are returned as skipped
+func trialReports(exp *experimentsv1alpha1.Experiment, trials []experimentsv1alpha1.TrialItem) ([]experimentsv1alpha1.TrialReport, []string) {
+	var reports []experimentsv1alpha1.TrialReport
+	var skipped []string
+	for i := range trials {
+		t := &trials[i]
+		rpt, err := trialReport(exp, t)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("trial %d: %v", t.Number, err))
+			continue
+		}
+		reports = append(reports, *rpt)
+	}
+	return reports, skipped
+}
+
+// trialReport converts a single historical trial into a report
+func trialReport(exp *experimentsv1alpha1.Experiment, t *experimentsv1alpha1.TrialItem) (*experimentsv1alpha1.TrialReport, error) {
+	rpt := &experimentsv1alpha1.TrialReport{Labels: t.Labels}
+	switch t.Status {
+	case experimentsv1alpha1.TrialCompleted:
+	case experimentsv1alpha1.TrialFailed:
+		rpt.Failed = true
+		rpt.FailureReason = t.FailureReason
+		rpt.FailureMessage = t.FailureMessage
+	default:
+		return nil, fmt.Errorf("status is %q", t.Status)
+	}
+
+	assignments := make(map[string]json.Number, len(t.Assignments))
+	for _, a := range t.Assignments {
+		assignments[a.ParameterName] = a.Value
+	}
+	for i := range exp.Parameters {
+		p := &exp.Parameters[i]
+		v, ok := assignments[p.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing assignment for parameter %q", p.Name)
+		}
+		if v.String() == trial.Redacted {
+			return nil, fmt.Errorf("assignment for parameter %q is redacted", p.Name)
+		}
+		rpt.Assignments = append(rpt.Assignments, experimentsv1alpha1.Assignment{ParameterName: p.Name, Value: v})
+	}
+
+	if rpt.Failed {
+		return rpt, nil
+	}
+
+	values := make(map[string]experimentsv1alpha1.Value, len(t.Values))
+	for _, v := range t.Values {
+		values[v.MetricName] = v
+	}
+	for i := range exp.Metrics {
+		v, ok := values[exp.Metrics[i].Name]
+		if !ok {
+			return nil, fmt.Errorf("missing value for metric %q", exp.Metrics[i].Name)
+		}
+		rpt.Values = append(rpt.Values, v)
+	}
+	return rpt, nil
+}
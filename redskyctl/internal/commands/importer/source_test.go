@@ -0,0 +1,76 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrialReports(t *testing.T) {
+	exp := &experimentsv1alpha1.Experiment{
+		Parameters: []experimentsv1alpha1.Parameter{{Name: "cpu"}, {Name: "tier"}},
+		Metrics:    []experimentsv1alpha1.Metric{{Name: "cost"}},
+	}
+
+	cases := []struct {
+		desc    string
+		csv     string
+		reports []experimentsv1alpha1.TrialReport
+		skipped []string
+	}{
+		{
+			desc: "Completed",
+			csv:  "experiment,number,status,parameter_cpu,parameter_tier,parameter_extra,metric_cost,label_best\nold,1,completed,500,gold,1,2.5,true\n",
+			reports: []experimentsv1alpha1.TrialReport{
+				{
+					Assignments: []experimentsv1alpha1.Assignment{{ParameterName: "cpu", Value: "500"}, {ParameterName: "tier", Value: "gold"}},
+					TrialValues: experimentsv1alpha1.TrialValues{Values: []experimentsv1alpha1.Value{{MetricName: "cost", Value: 2.5}}},
+					Labels:      map[string]string{"best": "true"},
+				},
+			},
+		},
+		{
+			desc: "Failed",
+			csv:  "number,status,parameter_cpu,parameter_tier,metric_cost\n2,failed,100,silver,\n",
+			reports: []experimentsv1alpha1.TrialReport{
+				{
+					Assignments: []experimentsv1alpha1.Assignment{{ParameterName: "cpu", Value: "100"}, {ParameterName: "tier", Value: "silver"}},
+					TrialValues: experimentsv1alpha1.TrialValues{Failed: true},
+				},
+			},
+		},
+		{
+			desc:    "Skipped",
+			csv:     "number,status,parameter_cpu,parameter_tier,metric_cost\n3,active,100,silver,\n4,completed,100,,1\n5,completed,100,silver,\n",
+			skipped: []string{`trial 3: status is "active"`, `trial 4: missing assignment for parameter "tier"`, `trial 5: missing value for metric "cost"`},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			trials, err := readTrials(strings.NewReader(c.csv), "trials.csv", "")
+			if assert.NoError(t, err) {
+				reports, skipped := trialReports(exp, trials)
+				assert.Equal(t, c.reports, reports)
+				assert.Equal(t, c.skipped, skipped)
+			}
+		})
+	}
+}
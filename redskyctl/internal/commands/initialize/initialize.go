@@ -115,11 +115,14 @@ func (o *Options) initialize(ctx context.Context) error {
 
 	// Run `kubectl wait` to ensure the CRD is installed
 	if o.Wait {
-		kubectlWait, err := o.Config.Kubectl(ctx, "wait", "crd/experiments.redskyops.dev", "crd/trials.redskyops.dev", "--for", "condition=Established")
+		kubectlWait, err := o.Config.Kubectl(ctx, "wait", "crd/experiments.redskyops.dev", "crd/experimentschedules.redskyops.dev", "crd/trials.redskyops.dev", "--for", "condition=Established")
 		if err != nil {
 			return err
 		}
-		if err := kubectlWait.Run(); err != nil {
+		progress := o.NewProgress("Waiting for custom resource definitions", 0)
+		err = kubectlWait.Run()
+		progress.Done(err)
+		if err != nil {
 			return err
 		}
 	}
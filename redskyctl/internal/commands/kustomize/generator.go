@@ -0,0 +1,183 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/config"
+	"github.com/redskyops/redskyops-controller/internal/experiment"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/generate"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/recipes"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+)
+
+// ExperimentGenerator is the Kustomize generator configuration used to produce experiments
+type ExperimentGenerator struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Experiment is the file containing the experiment manifest, relative to the Kustomization root
+	Experiment string `json:"experiment,omitempty"`
+	// Recipe is the name of a catalog recipe used to create the experiment; the generator name and namespace identify the recipe target
+	Recipe string `json:"recipe,omitempty"`
+	// IncludeNames restricts the generated patching role to the names of the patched objects
+	IncludeNames bool `json:"includeNames,omitempty"`
+	// ClusterRole generates a cluster role and cluster role binding instead of namespaced objects
+	ClusterRole bool `json:"clusterRole,omitempty"`
+}
+
+// GeneratorOptions are the options for running as a Kustomize generator plugin
+type GeneratorOptions struct {
+	// Config is the Red Sky Configuration used to generate the role bindings
+	Config *config.RedSkyConfig
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	Filename string
+}
+
+// NewGeneratorCommand creates a new command for generating experiments from a Kustomization
+func NewGeneratorCommand(o *GeneratorOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate CONFIG",
+		Short: "Generate experiments for Kustomize",
+		Long:  "Generate an experiment along with the RBAC and setup manifests it requires from an ExperimentGenerator configuration, suitable for use as a Kustomize exec plugin",
+		Example: `# Install the exec plugin for Kustomize
+PLUGIN_DIR=${XDG_CONFIG_HOME:-$HOME/.config}/kustomize/plugin/redskyops.dev/v1beta1/experimentgenerator
+mkdir -p $PLUGIN_DIR
+printf '#!/bin/sh\nexec redskyctl kustomize generate "$@"\n' > $PLUGIN_DIR/ExperimentGenerator
+chmod +x $PLUGIN_DIR/ExperimentGenerator
+
+# Build a Kustomization which lists an ExperimentGenerator configuration under "generators"
+kustomize build --enable_alpha_plugins`,
+		Args: cobra.ExactArgs(1),
+
+		PreRun: func(cmd *cobra.Command, args []string) {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.Filename = args[0]
+		},
+		RunE: commander.WithoutArgsE(o.generate),
+	}
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *GeneratorOptions) generate() error {
+	// Read the generator configuration
+	data, err := ioutil.ReadFile(o.Filename)
+	if err != nil {
+		return err
+	}
+	g := &ExperimentGenerator{}
+	if err := yaml.Unmarshal(data, g); err != nil {
+		return err
+	}
+
+	// Produce the experiment manifest and parse it back out
+	manifest, err := g.manifest()
+	if err != nil {
+		return err
+	}
+	list := &redskyv1beta1.ExperimentList{}
+	if err := generate.ReadExperiments("-", bytes.NewReader(manifest), list); err != nil {
+		return err
+	}
+
+	// Collect the additional objects required to run the experiments
+	var objs []runtime.Object
+	rbac := &generate.RBACOptions{
+		Config:             o.Config,
+		IncludeNames:       g.IncludeNames,
+		ClusterRole:        g.ClusterRole,
+		ClusterRoleBinding: g.ClusterRole,
+	}
+	rbac.Complete()
+	rbacList, err := rbac.BuildRBAC(list)
+	if err != nil {
+		return err
+	}
+	if rbacList != nil {
+		for i := range rbacList.Items {
+			objs = append(objs, rbacList.Items[i].Object)
+		}
+	}
+	for i := range list.Items {
+		objs = append(objs, experiment.SetupObjects(&list.Items[i])...)
+	}
+
+	// Write the experiment as-is followed by the additional objects
+	if _, err := o.Out.Write(bytes.TrimRight(manifest, "\n")); err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if err := writeDocument(o.Out, obj); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintln(o.Out)
+	return err
+}
+
+// manifest returns the experiment manifest described by the generator
+func (g *ExperimentGenerator) manifest() ([]byte, error) {
+	if g.Kind != "ExperimentGenerator" {
+		return nil, fmt.Errorf("unexpected generator kind: %s", g.Kind)
+	}
+
+	switch {
+	case g.Experiment != "" && g.Recipe != "":
+		return nil, fmt.Errorf("only one of experiment or recipe can be specified")
+	case g.Experiment != "":
+		return ioutil.ReadFile(g.Experiment)
+	case g.Recipe != "":
+		for i := range recipes.Catalog {
+			if recipes.Catalog[i].Name == g.Recipe {
+				return recipes.Catalog[i].Render(g.Name, g.Namespace)
+			}
+		}
+		return nil, fmt.Errorf("unknown recipe: %s", g.Recipe)
+	default:
+		return nil, fmt.Errorf("one of experiment or recipe is required")
+	}
+}
+
+// writeDocument writes an additional YAML document, including the type information
+func writeDocument(w io.Writer, obj runtime.Object) error {
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return err
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvks[0])
+
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "\n---\n%s", bytes.TrimRight(data, "\n"))
+	return err
+}
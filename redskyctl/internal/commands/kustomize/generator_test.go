@@ -0,0 +1,89 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"testing"
+
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/recipes"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExperimentGeneratorManifest(t *testing.T) {
+	typeMeta := metav1.TypeMeta{APIVersion: "redskyops.dev/v1beta1", Kind: "ExperimentGenerator"}
+	recipe := recipes.Catalog[0].Name
+
+	cases := []struct {
+		desc      string
+		generator ExperimentGenerator
+		contains  string
+		err       string
+	}{
+		{
+			desc: "recipe",
+			generator: ExperimentGenerator{
+				TypeMeta:   typeMeta,
+				ObjectMeta: metav1.ObjectMeta{Name: "myapp", Namespace: "default"},
+				Recipe:     recipe,
+			},
+			contains: "name: myapp",
+		},
+		{
+			desc: "unknown recipe",
+			generator: ExperimentGenerator{
+				TypeMeta: typeMeta,
+				Recipe:   "not-a-recipe",
+			},
+			err: "unknown recipe: not-a-recipe",
+		},
+		{
+			desc: "wrong kind",
+			generator: ExperimentGenerator{
+				TypeMeta: metav1.TypeMeta{Kind: "SecretGenerator"},
+				Recipe:   recipe,
+			},
+			err: "unexpected generator kind: SecretGenerator",
+		},
+		{
+			desc: "both",
+			generator: ExperimentGenerator{
+				TypeMeta:   typeMeta,
+				Experiment: "experiment.yaml",
+				Recipe:     recipe,
+			},
+			err: "only one of experiment or recipe can be specified",
+		},
+		{
+			desc:      "neither",
+			generator: ExperimentGenerator{TypeMeta: typeMeta},
+			err:       "one of experiment or recipe is required",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			manifest, err := c.generator.manifest()
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Contains(t, string(manifest), c.contains)
+			}
+		})
+	}
+}
@@ -17,11 +17,18 @@ limitations under the License.
 package kustomize
 
 import (
+	"github.com/redskyops/redskyops-controller/internal/config"
 	"github.com/spf13/cobra"
 )
 
+// Options are the configuration options shared by the Kustomize integration commands
+type Options struct {
+	// Config is the Red Sky Configuration
+	Config *config.RedSkyConfig
+}
+
 // NewCommand returns a new Kustomization integration command
-func NewCommand() *cobra.Command {
+func NewCommand(o *Options) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "kustomize",
 		Short: "Kustomize integrations",
@@ -29,6 +36,7 @@ func NewCommand() *cobra.Command {
 	}
 
 	cmd.AddCommand(NewConfigCommand(&ConfigOptions{}))
+	cmd.AddCommand(NewGeneratorCommand(&GeneratorOptions{Config: o.Config}))
 
 	return cmd
 }
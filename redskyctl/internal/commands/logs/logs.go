@@ -0,0 +1,227 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Options is the configuration for displaying logs
+type Options struct {
+	// Config is the Red Sky Configuration
+	Config config.Config
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// Namespace is the namespace of the trial
+	Namespace string
+	// Manager selects the controller manager logs
+	Manager bool
+	// Trial is the name of the trial, or the experiment name and trial number separated by a slash
+	Trial string
+	// Follow streams the logs
+	Follow bool
+	// Tail is the number of lines to display from the end of each log, a negative value displays everything
+	Tail int64
+}
+
+// NewCommand creates a new command for displaying logs
+func NewCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs [trial NAME | manager]",
+		Short: "Display logs",
+		Long:  "Display the logs of the controller manager or of the pods running a trial",
+
+		Example: `# Follow the controller manager logs
+redskyctl logs --manager -f
+
+# Display the logs of every container used to run trial 3 of "my-experiment"
+redskyctl logs trial my-experiment/3
+
+# Follow the logs of the setup tasks and trial job of a trial
+redskyctl logs trial my-experiment-003 -f`,
+
+		Args: cobra.MaximumNArgs(2),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			if err := o.setArgs(args); err != nil {
+				return err
+			}
+			if o.Manager == (o.Trial != "") {
+				return fmt.Errorf("exactly one trial or the manager must be specified")
+			}
+			return nil
+		},
+		RunE: commander.WithContextE(o.logs),
+	}
+
+	cmd.Flags().BoolVar(&o.Manager, "manager", o.Manager, "Display the controller manager logs.")
+	cmd.Flags().StringVar(&o.Trial, "trial", o.Trial, "Display the logs of a `trial`, either by name or as EXPERIMENT/NUMBER.")
+	cmd.Flags().StringVarP(&o.Namespace, "namespace", "n", o.Namespace, "Namespace of the trial.")
+	cmd.Flags().BoolVarP(&o.Follow, "follow", "f", o.Follow, "Stream the logs.")
+	cmd.Flags().Int64Var(&o.Tail, "tail", -1, "Number of `lines` to display from the end of each log, -1 displays everything.")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *Options) logs(ctx context.Context) error {
+	var ns, selector string
+	if o.Manager {
+		sns, err := o.Config.SystemNamespace()
+		if err != nil {
+			return err
+		}
+		ns, selector = sns, "control-plane=controller-manager"
+	} else {
+		t, err := o.trial(ctx)
+		if err != nil {
+			return err
+		}
+		ns, selector = t.Namespace, redskyv1beta1.LabelTrial+"="+t.Name
+	}
+
+	// Merge the logs of every container (e.g. setup, job and helpers) in every matching pod
+	args := []string{"logs", "--namespace", ns, "--selector", selector, "--all-containers", "--prefix", "--max-log-requests", "20"}
+	args = append(args, "--tail", strconv.FormatInt(o.Tail, 10))
+	if o.Follow {
+		args = append(args, "--follow")
+	}
+
+	kubectlLogs, err := o.Config.Kubectl(ctx, args...)
+	if err != nil {
+		return err
+	}
+	kubectlLogs.Stdout = o.Out
+	kubectlLogs.Stderr = o.ErrOut
+	return kubectlLogs.Run()
+}
+
+// setArgs applies the positional arguments, which are an alternative to the "--trial" and "--manager" flags
+func (o *Options) setArgs(args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "trial", "trials", "tr":
+		if len(args) != 2 {
+			return fmt.Errorf("a trial name is required")
+		}
+		if o.Trial != "" && o.Trial != args[1] {
+			return fmt.Errorf("conflicting trial names %q and %q", o.Trial, args[1])
+		}
+		o.Trial = args[1]
+	case "manager":
+		if len(args) != 1 {
+			return fmt.Errorf("unexpected argument %q", args[1])
+		}
+		o.Manager = true
+	default:
+		return fmt.Errorf("cannot display logs for %q, must be one of: trial|manager", args[0])
+	}
+	return nil
+}
+
+// trial returns the trial selected by the user
+func (o *Options) trial(ctx context.Context) (*redskyv1beta1.Trial, error) {
+	expName, number := splitTrial(o.Trial)
+	if expName == "" {
+		// A plain trial name
+		t := &redskyv1beta1.Trial{}
+		if err := o.get(ctx, t, o.args("trials.v1beta1.redskyops.dev", o.Trial)...); err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+
+	// Search the trials of the experiment for the trial number
+	tl := &redskyv1beta1.TrialList{}
+	args := []string{"trials.v1beta1.redskyops.dev", "--selector", redskyv1beta1.LabelExperiment + "=" + expName}
+	if o.Namespace == "" {
+		args = append(args, "--all-namespaces")
+	}
+	if err := o.get(ctx, tl, o.args(args...)...); err != nil {
+		return nil, err
+	}
+	if t := findTrial(tl, number); t != nil {
+		return t, nil
+	}
+	return nil, fmt.Errorf("unable to find trial %d of experiment %s", number, expName)
+}
+
+// get unmarshals the output of `kubectl get` into the supplied object
+func (o *Options) get(ctx context.Context, obj interface{}, args ...string) error {
+	kubectlGet, err := o.Config.Kubectl(ctx, append(append([]string{"get"}, args...), "--output", "json")...)
+	if err != nil {
+		return err
+	}
+	kubectlGet.Stderr = o.ErrOut
+	data, err := kubectlGet.Output()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, obj)
+}
+
+// args appends the namespace (if specified) to the supplied kubectl arguments
+func (o *Options) args(args ...string) []string {
+	if o.Namespace != "" {
+		args = append(args, "--namespace", o.Namespace)
+	}
+	return args
+}
+
+// splitTrial splits an "EXPERIMENT/NUMBER" trial reference, the experiment name is empty if the reference is a name
+func splitTrial(ref string) (string, int64) {
+	i := strings.LastIndex(ref, "/")
+	if i < 0 {
+		return "", -1
+	}
+	number, err := strconv.ParseInt(ref[i+1:], 10, 64)
+	if err != nil {
+		return "", -1
+	}
+	return ref[:i], number
+}
+
+// findTrial returns the trial with the supplied number, the trial number is the last path segment of the URL the
+// trial is reported to
+func findTrial(tl *redskyv1beta1.TrialList, number int64) *redskyv1beta1.Trial {
+	for i := range tl.Items {
+		u, err := url.Parse(tl.Items[i].GetAnnotations()[redskyv1beta1.AnnotationReportTrialURL])
+		if err != nil || u.Path == "" {
+			continue
+		}
+		if path.Base(u.Path) == strconv.FormatInt(number, 10) {
+			return &tl.Items[i]
+		}
+	}
+	return nil
+}
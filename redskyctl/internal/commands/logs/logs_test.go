@@ -0,0 +1,97 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSplitTrial(t *testing.T) {
+	cases := []struct {
+		ref        string
+		experiment string
+		number     int64
+	}{
+		{ref: "my-experiment/3", experiment: "my-experiment", number: 3},
+		{ref: "my-experiment-abc12", number: -1},
+		{ref: "my-experiment/abc", number: -1},
+	}
+	for _, c := range cases {
+		t.Run(c.ref, func(t *testing.T) {
+			experiment, number := splitTrial(c.ref)
+			assert.Equal(t, c.experiment, experiment)
+			assert.Equal(t, c.number, number)
+		})
+	}
+}
+
+func TestSetArgs(t *testing.T) {
+	cases := []struct {
+		desc    string
+		args    []string
+		trial   string
+		manager bool
+		err     bool
+	}{
+		{desc: "none"},
+		{desc: "trial", args: []string{"trial", "my-experiment/3"}, trial: "my-experiment/3"},
+		{desc: "trial alias", args: []string{"tr", "my-experiment-003"}, trial: "my-experiment-003"},
+		{desc: "trial missing name", args: []string{"trial"}, err: true},
+		{desc: "manager", args: []string{"manager"}, manager: true},
+		{desc: "unknown", args: []string{"experiment", "my-experiment"}, err: true},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			o := &Options{}
+			err := o.setArgs(c.args)
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.trial, o.Trial)
+				assert.Equal(t, c.manager, o.Manager)
+			}
+		})
+	}
+}
+
+func TestFindTrial(t *testing.T) {
+	trial := func(name, reportTrialURL string) redskyv1beta1.Trial {
+		return redskyv1beta1.Trial{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{redskyv1beta1.AnnotationReportTrialURL: reportTrialURL},
+		}}
+	}
+	tl := &redskyv1beta1.TrialList{Items: []redskyv1beta1.Trial{
+		trial("pending", ""),
+		trial("first", "https://example.com/experiments/test/trials/1"),
+		trial("twelfth", "https://example.com/experiments/test/trials/12"),
+	}}
+
+	if tr := findTrial(tl, 12); assert.NotNil(t, tr) {
+		assert.Equal(t, "twelfth", tr.Name)
+	}
+	if tr := findTrial(tl, 1); assert.NotNil(t, tr) {
+		assert.Equal(t, "first", tr.Name)
+	}
+	assert.Nil(t, findTrial(tl, 2))
+}
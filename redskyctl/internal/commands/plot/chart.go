@@ -0,0 +1,218 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plot
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// point is a single observed metric value
+type point struct {
+	// Number is the trial number, used for the horizontal axis
+	Number int64
+	// Value is the observed metric value
+	Value float64
+}
+
+// series is the data needed to render a convergence chart
+type series struct {
+	// Title is displayed above the chart
+	Title string
+	// Minimize indicates lower values are better
+	Minimize bool
+	// Points are the observations, ordered by trial number
+	Points []point
+}
+
+// bestSoFar returns the best value observed up to and including each point
+func (s *series) bestSoFar() []float64 {
+	best := make([]float64, len(s.Points))
+	for i, p := range s.Points {
+		best[i] = p.Value
+		if i > 0 && (s.Minimize && best[i-1] < p.Value || !s.Minimize && best[i-1] > p.Value) {
+			best[i] = best[i-1]
+		}
+	}
+	return best
+}
+
+// bounds returns the range of both axes, empty ranges are widened so values can always be scaled
+func (s *series) bounds() (minX, maxX, minY, maxY float64) {
+	minX, maxX, minY, maxY = math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+	for _, p := range s.Points {
+		minX, maxX = math.Min(minX, float64(p.Number)), math.Max(maxX, float64(p.Number))
+		minY, maxY = math.Min(minY, p.Value), math.Max(maxY, p.Value)
+	}
+	if maxX == minX {
+		minX, maxX = minX-1, maxX+1
+	}
+	if maxY == minY {
+		minY, maxY = minY-1, maxY+1
+	}
+	return minX, maxX, minY, maxY
+}
+
+// glyphs are the characters used to draw a terminal chart
+type glyphs struct {
+	observation, best, line, axisY, axisX, corner string
+}
+
+var (
+	unicodeGlyphs = glyphs{observation: "·", best: "●", line: "─", axisY: "│", axisX: "─", corner: "└"}
+	asciiGlyphs   = glyphs{observation: ".", best: "*", line: "-", axisY: "|", axisX: "-", corner: "+"}
+)
+
+// renderText draws the chart using the supplied number of columns and rows for the plot area; observations that
+// improve on the best value so far are highlighted and the best-so-far value is drawn as a step line
+func renderText(w io.Writer, s *series, width, height int, ascii bool) error {
+	if len(s.Points) == 0 {
+		return fmt.Errorf("no values to plot")
+	}
+
+	g := unicodeGlyphs
+	if ascii {
+		g = asciiGlyphs
+	}
+
+	minX, maxX, minY, maxY := s.bounds()
+	col := func(x float64) int { return int(math.Round((x - minX) / (maxX - minX) * float64(width-1))) }
+	row := func(y float64) int { return height - 1 - int(math.Round((y-minY)/(maxY-minY)*float64(height-1))) }
+
+	grid := make([][]string, height)
+	for i := range grid {
+		grid[i] = make([]string, width)
+		for j := range grid[i] {
+			grid[i][j] = " "
+		}
+	}
+
+	// Draw the best-so-far line first so the observations are drawn on top of it
+	best := s.bestSoFar()
+	for i := range s.Points {
+		end := width - 1
+		if i+1 < len(s.Points) {
+			end = col(float64(s.Points[i+1].Number))
+		}
+		r := row(best[i])
+		for c := col(float64(s.Points[i].Number)); c <= end; c++ {
+			grid[r][c] = g.line
+		}
+	}
+	for i, p := range s.Points {
+		glyph := g.observation
+		if i == 0 || best[i] != best[i-1] {
+			glyph = g.best
+		}
+		grid[row(p.Value)][col(float64(p.Number))] = glyph
+	}
+
+	// Label the top, middle and bottom rows of the vertical axis
+	labels := map[int]string{0: formatValue(maxY), height / 2: formatValue((maxY + minY) / 2), height - 1: formatValue(minY)}
+	labelWidth := 0
+	for _, l := range labels {
+		if len(l) > labelWidth {
+			labelWidth = len(l)
+		}
+	}
+
+	var b strings.Builder
+	if s.Title != "" {
+		b.WriteString(s.Title + "\n")
+	}
+	for i := range grid {
+		_, _ = fmt.Fprintf(&b, "%*s %s%s\n", labelWidth, labels[i], g.axisY, strings.Join(grid[i], ""))
+	}
+	_, _ = fmt.Fprintf(&b, "%*s %s%s\n", labelWidth, "", g.corner, strings.Repeat(g.axisX, width))
+
+	// Label the first and last trial numbers of the horizontal axis
+	first, last := strconv.FormatInt(s.Points[0].Number, 10), strconv.FormatInt(s.Points[len(s.Points)-1].Number, 10)
+	gap := width - len(first) - len(last)
+	if gap < 1 {
+		gap = 1
+	}
+	_, _ = fmt.Fprintf(&b, "%*s  %s%s%s\n", labelWidth, "", first, strings.Repeat(" ", gap), last)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// renderSVG draws the chart as a standalone SVG document of the supplied pixel dimensions
+func renderSVG(w io.Writer, s *series, width, height int) error {
+	if len(s.Points) == 0 {
+		return fmt.Errorf("no values to plot")
+	}
+
+	const margin = 50.0
+	if float64(width) <= 2*margin || float64(height) <= 2*margin {
+		return fmt.Errorf("chart must be larger than %gx%g pixels", 2*margin, 2*margin)
+	}
+
+	minX, maxX, minY, maxY := s.bounds()
+	pw, ph := float64(width)-2*margin, float64(height)-2*margin
+	x := func(v float64) float64 { return margin + (v-minX)/(maxX-minX)*pw }
+	y := func(v float64) float64 { return margin + ph - (v-minY)/(maxY-minY)*ph }
+
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n", width, height, width, height)
+	_, _ = fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	if s.Title != "" {
+		_, _ = fmt.Fprintf(&b, `<text x="%g" y="%g" text-anchor="middle" font-size="14">%s</text>`+"\n", float64(width)/2, margin/2, html.EscapeString(s.Title))
+	}
+
+	// Axes and labels
+	_, _ = fmt.Fprintf(&b, `<path d="M%g %gV%gH%g" fill="none" stroke="black"/>`+"\n", margin, margin, margin+ph, margin+pw)
+	_, _ = fmt.Fprintf(&b, `<text x="%g" y="%g" text-anchor="end">%s</text>`+"\n", margin-5, margin+4, formatValue(maxY))
+	_, _ = fmt.Fprintf(&b, `<text x="%g" y="%g" text-anchor="end">%s</text>`+"\n", margin-5, margin+ph+4, formatValue(minY))
+	_, _ = fmt.Fprintf(&b, `<text x="%g" y="%g" text-anchor="middle">%d</text>`+"\n", x(float64(s.Points[0].Number)), margin+ph+18, s.Points[0].Number)
+	_, _ = fmt.Fprintf(&b, `<text x="%g" y="%g" text-anchor="middle">%d</text>`+"\n", x(float64(s.Points[len(s.Points)-1].Number)), margin+ph+18, s.Points[len(s.Points)-1].Number)
+	_, _ = fmt.Fprintf(&b, `<text x="%g" y="%g" text-anchor="middle">trial</text>`+"\n", margin+pw/2, margin+ph+36)
+
+	// Best-so-far step line
+	best := s.bestSoFar()
+	path := make([]string, 0, 2*len(s.Points))
+	for i, p := range s.Points {
+		if i == 0 {
+			path = append(path, fmt.Sprintf("M%.1f %.1f", x(float64(p.Number)), y(best[i])))
+			continue
+		}
+		path = append(path, fmt.Sprintf("H%.1f", x(float64(p.Number))), fmt.Sprintf("V%.1f", y(best[i])))
+	}
+	_, _ = fmt.Fprintf(&b, `<path d="%s" fill="none" stroke="#1f77b4" stroke-width="2"/>`+"\n", strings.Join(path, " "))
+
+	// Observations
+	for i, p := range s.Points {
+		fill := "#aaaaaa"
+		if i == 0 || best[i] != best[i-1] {
+			fill = "#1f77b4"
+		}
+		_, _ = fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>trial %d: %s</title></circle>`+"\n", x(float64(p.Number)), y(p.Value), fill, p.Number, formatValue(p.Value))
+	}
+
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatValue returns a compact representation of a metric value
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
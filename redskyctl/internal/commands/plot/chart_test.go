@@ -0,0 +1,65 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBestSoFar(t *testing.T) {
+	points := []point{{1, 50}, {2, 40}, {3, 45}, {4, 20}, {5, 30}}
+	assert.Equal(t, []float64{50, 40, 40, 20, 20}, (&series{Minimize: true, Points: points}).bestSoFar())
+	assert.Equal(t, []float64{50, 50, 50, 50, 50}, (&series{Points: points}).bestSoFar())
+}
+
+func TestRenderText(t *testing.T) {
+	s := &series{Title: "test: latency", Minimize: true, Points: []point{{1, 50}, {2, 40}, {3, 45}, {4, 20}, {5, 30}}}
+
+	var buf bytes.Buffer
+	if assert.NoError(t, renderText(&buf, s, 10, 5, true)) {
+		assert.Equal(t, strings.Join([]string{
+			"test: latency",
+			"50 |*--       ",
+			"   |  *--.--  ",
+			"35 |          ",
+			"   |         .",
+			"20 |       *--",
+			"   +----------",
+			"    1        5",
+			"",
+		}, "\n"), buf.String())
+	}
+
+	assert.Error(t, renderText(&buf, &series{}, 10, 5, true))
+}
+
+func TestRenderSVG(t *testing.T) {
+	s := &series{Title: "test: <latency>", Minimize: true, Points: []point{{1, 50}, {2, 40}, {3, 45}}}
+
+	var buf bytes.Buffer
+	if assert.NoError(t, renderSVG(&buf, s, 300, 200)) {
+		assert.True(t, strings.HasPrefix(buf.String(), "<svg "))
+		assert.Contains(t, buf.String(), "test: &lt;latency&gt;")
+		assert.Equal(t, 3, strings.Count(buf.String(), "<circle "))
+	}
+
+	assert.Error(t, renderSVG(&buf, s, 80, 80))
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Options is the configuration for plotting experiment convergence
+type Options struct {
+	// Config is the Red Sky Configuration used to access the remote server
+	Config config.Config
+	// ExperimentsAPI is used to fetch the experiment and trial data
+	ExperimentsAPI experimentsv1alpha1.API
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// ExperimentName is the name of the experiment to plot
+	ExperimentName string
+	// Metric is the name of the metric to plot, defaults to the first metric of the experiment
+	Metric string
+	// Width is the number of columns (or pixels for SVG output) used for the plot
+	Width int
+	// Height is the number of rows (or pixels for SVG output) used for the plot
+	Height int
+	// ASCII restricts the terminal chart to ASCII characters
+	ASCII bool
+	// SVGFile is the name of a file to write an SVG chart to instead of drawing in the terminal
+	SVGFile string
+}
+
+// NewCommand creates a new command for plotting experiment convergence
+func NewCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "plot EXPERIMENT",
+		Short: "Plot experiment convergence",
+		Long: "Plot the metric values of the completed trials of an experiment by trial number along with the best value " +
+			"found so far, trials which improved on the best value are highlighted",
+		Example: `# Plot the convergence of an experiment in the terminal
+redskyctl plot my-experiment --metric latency
+
+# Write the chart to a file for embedding in documentation
+redskyctl plot my-experiment --metric latency --svg latency.svg`,
+
+		Args: cobra.ExactArgs(1),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.ExperimentName = args[0]
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.plot),
+	}
+
+	cmd.Flags().StringVar(&o.Metric, "metric", o.Metric, "Name of the metric to plot (defaults to the first metric).")
+	cmd.Flags().IntVar(&o.Width, "width", 0, "Width of the chart in columns, or pixels for SVG output.")
+	cmd.Flags().IntVar(&o.Height, "height", 0, "Height of the chart in rows, or pixels for SVG output.")
+	cmd.Flags().BoolVar(&o.ASCII, "ascii", o.ASCII, "Only use ASCII characters to draw the chart.")
+	cmd.Flags().StringVar(&o.SVGFile, "svg", o.SVGFile, "Write the chart as SVG to the specified `file`.")
+
+	_ = cmd.MarkFlagFilename("svg", "svg")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *Options) plot(ctx context.Context) error {
+	exp, err := o.ExperimentsAPI.GetExperimentByName(ctx, experimentsv1alpha1.NewExperimentName(o.ExperimentName))
+	if err != nil {
+		return err
+	}
+
+	q := &experimentsv1alpha1.TrialListQuery{Status: []experimentsv1alpha1.TrialStatus{experimentsv1alpha1.TrialCompleted}}
+	tl, err := o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, q)
+	if err != nil {
+		return err
+	}
+
+	s, err := o.series(&exp, tl.Trials)
+	if err != nil {
+		return err
+	}
+
+	if o.SVGFile != "" {
+		f, err := os.Create(o.SVGFile)
+		if err != nil {
+			return err
+		}
+		if err := renderSVG(f, s, dimension(o.Width, 800), dimension(o.Height, 400)); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	return renderText(o.Out, s, dimension(o.Width, 72), dimension(o.Height, 20), o.ASCII)
+}
+
+// series extracts the values of the selected metric from the trials
+func (o *Options) series(exp *experimentsv1alpha1.Experiment, trials []experimentsv1alpha1.TrialItem) (*series, error) {
+	if len(exp.Metrics) == 0 {
+		return nil, fmt.Errorf("experiment %s has no metrics", o.ExperimentName)
+	}
+
+	metric := &exp.Metrics[0]
+	if o.Metric != "" {
+		metric = nil
+		for i := range exp.Metrics {
+			if exp.Metrics[i].Name == o.Metric {
+				metric = &exp.Metrics[i]
+			}
+		}
+		if metric == nil {
+			return nil, fmt.Errorf("experiment %s has no metric named %s", o.ExperimentName, o.Metric)
+		}
+	}
+
+	s := &series{Title: fmt.Sprintf("%s: %s", o.ExperimentName, metric.Name), Minimize: metric.Minimize}
+	for i := range trials {
+		for _, v := range trials[i].Values {
+			if v.MetricName == metric.Name {
+				s.Points = append(s.Points, point{Number: trials[i].Number, Value: v.Value})
+			}
+		}
+	}
+	if len(s.Points) == 0 {
+		return nil, fmt.Errorf("experiment %s has no completed trials with a value for %s", o.ExperimentName, metric.Name)
+	}
+
+	sort.Slice(s.Points, func(i, j int) bool { return s.Points[i].Number < s.Points[j].Number })
+	return s, nil
+}
+
+// dimension returns the requested size or the default if the requested size is too small to draw a chart
+func dimension(requested, def int) int {
+	if requested < 3 {
+		return def
+	}
+	return requested
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/redskyops/redskyops-controller/internal/trial"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+)
+
+// cache is the local mirror of the experiment and trial data used to answer queries
+type cache struct {
+	// Server is the experiments endpoint the data was fetched from
+	Server string `json:"server"`
+	// Fetched is the time the data was fetched
+	Fetched time.Time `json:"fetched"`
+	// Tables are the tables available to queries, keyed by name
+	Tables map[string]*table `json:"tables"`
+}
+
+// newCache returns an empty cache for the supplied server
+func newCache(server string, fetched time.Time) *cache {
+	return &cache{
+		Server:  server,
+		Fetched: fetched,
+		Tables: map[string]*table{
+			"experiments": {Columns: []string{"name", "display_name", "observations"}},
+			"trials":      {Columns: []string{"experiment", "number", "status", "failure_reason"}},
+		},
+	}
+}
+
+// defaultCacheFile returns the location of the cache file in the user's cache directory
+func defaultCacheFile() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "redskyctl", "results.json"), nil
+}
+
+// readCache reads the cache file, returning nil if it does not exist
+func readCache(filename string) (*cache, error) {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	c := &cache{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// write persists the cache to the supplied file
+func (c *cache) write(filename string) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0600)
+}
+
+// addExperiment adds an experiment and its trials to the cache
+func (c *cache) addExperiment(exp *experimentsv1alpha1.Experiment, trials []experimentsv1alpha1.TrialItem) {
+	et := c.Tables["experiments"]
+	row := map[string]interface{}{
+		"name":         exp.Name(),
+		"display_name": exp.DisplayName,
+		"observations": float64(exp.Observations),
+	}
+	for k, v := range exp.Labels {
+		row["label_"+k] = v
+	}
+	et.Rows = append(et.Rows, row)
+	et.Columns = appendColumns(et.Columns, row)
+
+	sensitive := make(map[string]bool, len(exp.Parameters))
+	for i := range exp.Parameters {
+		sensitive[exp.Parameters[i].Name] = exp.Parameters[i].Sensitive
+	}
+
+	tt := c.Tables["trials"]
+	for i := range trials {
+		t := &trials[i]
+		row := map[string]interface{}{
+			"experiment": exp.Name(),
+			"number":     float64(t.Number),
+			"status":     string(t.Status),
+		}
+		if t.FailureReason != "" {
+			row["failure_reason"] = t.FailureReason
+		}
+		for _, a := range t.Assignments {
+			switch f, err := a.Value.Float64(); {
+			case sensitive[a.ParameterName]:
+				row["parameter_"+a.ParameterName] = trial.Redacted
+			case err == nil:
+				row["parameter_"+a.ParameterName] = f
+			default:
+				row["parameter_"+a.ParameterName] = a.Value.String()
+			}
+		}
+		for _, v := range t.Values {
+			row["metric_"+v.MetricName] = v.Value
+		}
+		for k, v := range t.Labels {
+			row["label_"+k] = v
+		}
+		tt.Rows = append(tt.Rows, row)
+		tt.Columns = appendColumns(tt.Columns, row)
+	}
+}
+
+// appendColumns adds any columns from the row which are not already present, new columns are added in sorted order
+func appendColumns(columns []string, row map[string]interface{}) []string {
+	known := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		known[c] = true
+	}
+
+	var added []string
+	for k := range row {
+		if !known[k] {
+			added = append(added, k)
+		}
+	}
+	sort.Strings(added)
+	return append(columns, added...)
+}
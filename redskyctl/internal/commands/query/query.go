@@ -0,0 +1,235 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Options is the configuration for querying experiment results
+type Options struct {
+	// Config is the Red Sky Configuration used to access the remote server
+	Config config.Config
+	// ExperimentsAPI is used to fetch the experiment and trial data
+	ExperimentsAPI experimentsv1alpha1.API
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// Query is the SELECT statement to evaluate
+	Query string
+	// Output is the format used to display the results
+	Output string
+	// CacheFile is the location of the local results cache
+	CacheFile string
+	// Refresh forces the local cache to be re-populated from the remote server
+	Refresh bool
+	// NoCache disables the local cache, data is always fetched and never persisted
+	NoCache bool
+	// MaxAge is the age after which the local cache is automatically refreshed, 0 to never refresh automatically
+	MaxAge time.Duration
+}
+
+// NewCommand creates a new command for querying experiment results
+func NewCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query STATEMENT",
+		Short: "Query experiment results",
+		Long: `Query experiment results using SQL
+
+The experiment and trial data from the remote server is mirrored into a local cache and made available as the
+"experiments" and "trials" tables. Trial parameter assignments, metric values and labels are available as columns
+prefixed with "parameter_", "metric_" and "label_" respectively.
+
+The tables are loaded into an embedded, read-only SQLite database so any SQLite SELECT statement may be used; column
+names which are not valid SQL identifiers (such as most labels) must be double quoted.`,
+		Example: `# List the failed trials of an experiment using the most memory
+redskyctl query "SELECT number, metric_memory FROM trials WHERE experiment = 'my-experiment' AND status = 'failed' ORDER BY metric_memory DESC"
+
+# Summarize the trial outcomes of every experiment
+redskyctl query --refresh "SELECT experiment, status, count(*) FROM trials GROUP BY experiment, status"`,
+		Args: cobra.ExactArgs(1),
+
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.Query = args[0]
+			if err := o.Complete(); err != nil {
+				return err
+			}
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
+		},
+		RunE: commander.WithContextE(o.query),
+	}
+
+	cmd.Flags().StringVarP(&o.Output, "output", "o", o.Output, "Output `format`. One of: table|csv")
+	cmd.Flags().StringVar(&o.CacheFile, "cache-file", o.CacheFile, "Location of the local results cache `file`.")
+	cmd.Flags().BoolVar(&o.Refresh, "refresh", o.Refresh, "Re-populate the local results cache from the server.")
+	cmd.Flags().BoolVar(&o.NoCache, "no-cache", o.NoCache, "Query the server without using the local results cache.")
+	cmd.Flags().DurationVar(&o.MaxAge, "max-age", o.MaxAge, "Automatically refresh the local results cache when it is older than this `duration`.")
+
+	_ = cmd.MarkFlagFilename("cache-file")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+// Complete fills in the default values
+func (o *Options) Complete() error {
+	if o.CacheFile == "" && !o.NoCache {
+		f, err := defaultCacheFile()
+		if err != nil {
+			return err
+		}
+		o.CacheFile = f
+	}
+	return nil
+}
+
+func (o *Options) query(ctx context.Context) error {
+	c, err := o.results(ctx)
+	if err != nil {
+		return err
+	}
+
+	db, err := openDatabase(ctx, c.Tables)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	r, err := execute(ctx, db, o.Query)
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	return o.print(r)
+}
+
+// results returns the experiment and trial data, either from the local cache or from the server
+func (o *Options) results(ctx context.Context) (*cache, error) {
+	ep, err := o.Config.Endpoints()
+	if err != nil {
+		return nil, err
+	}
+	server := ep.Resolve("/experiments/").String()
+
+	if !o.NoCache && !o.Refresh {
+		c, err := readCache(o.CacheFile)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil && c.Server == server && (o.MaxAge <= 0 || time.Since(c.Fetched) < o.MaxAge) {
+			return c, nil
+		}
+	}
+
+	c, err := o.fetch(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+
+	if !o.NoCache {
+		if err := c.write(o.CacheFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// fetch mirrors the experiment and trial data from the server
+func (o *Options) fetch(ctx context.Context, server string) (*cache, error) {
+	l, err := o.ExperimentsAPI.GetAllExperiments(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for l.Next != "" {
+		n, err := o.ExperimentsAPI.GetAllExperimentsByPage(ctx, l.Next)
+		if err != nil {
+			return nil, err
+		}
+		l.Next = n.Next
+		l.Experiments = append(l.Experiments, n.Experiments...)
+	}
+
+	c := newCache(server, time.Now().UTC())
+	progress := o.NewProgress("Fetching trials", len(l.Experiments))
+	for i := range l.Experiments {
+		exp := &l.Experiments[i].Experiment
+
+		var tl experimentsv1alpha1.TrialList
+		if exp.TrialsURL != "" {
+			if tl, err = o.ExperimentsAPI.GetAllTrials(ctx, exp.TrialsURL, nil); err != nil {
+				progress.Done(err)
+				return nil, err
+			}
+		}
+
+		c.addExperiment(exp, tl.Trials)
+		progress.Add(1)
+	}
+	progress.Done(nil)
+
+	return c, nil
+}
+
+// print writes the query results using the configured output format
+func (o *Options) print(r *result) error {
+	switch strings.ToLower(o.Output) {
+	case "table", "":
+		tw := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+		_, _ = fmt.Fprintln(tw, strings.ToUpper(strings.Join(r.Columns, "\t")))
+		for _, row := range r.Rows {
+			_, _ = fmt.Fprintln(tw, strings.Join(formatRow(row), "\t"))
+		}
+		return tw.Flush()
+
+	case "csv":
+		w := csv.NewWriter(o.Out)
+		if err := w.Write(r.Columns); err != nil {
+			return err
+		}
+		for _, row := range r.Rows {
+			if err := w.Write(formatRow(row)); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		return w.Error()
+
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.Output)
+	}
+}
+
+// formatRow returns the display strings for a row of values
+func formatRow(row []interface{}) []string {
+	values := make([]string, len(row))
+	for i := range row {
+		values[i] = formatValue(row[i])
+	}
+	return values
+}
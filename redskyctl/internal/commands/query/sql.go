@@ -0,0 +1,168 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	// Register the pure Go SQLite driver (no cgo required)
+	_ "modernc.org/sqlite"
+)
+
+// table is a named collection of rows
+type table struct {
+	// Columns is the ordered list of column names
+	Columns []string `json:"columns"`
+	// Rows are the column values of each row, numbers are stored as float64 values
+	Rows []map[string]interface{} `json:"rows"`
+}
+
+// result is the outcome of evaluating a statement
+type result struct {
+	// Columns are the column headers
+	Columns []string
+	// Rows are the selected values
+	Rows [][]interface{}
+}
+
+// openDatabase loads the tables into a new in-memory SQLite database; once loaded, the database is read-only
+func openDatabase(ctx context.Context, tables map[string]*table) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each connection to an in-memory database gets its own copy, make sure there is only ever one
+	db.SetMaxOpenConns(1)
+
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := loadTable(ctx, db, name, tables[name]); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+
+	if _, err := db.ExecContext(ctx, "PRAGMA query_only = ON"); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// loadTable creates a table and inserts all of the rows, columns are not typed so values retain their storage class
+func loadTable(ctx context.Context, db *sql.DB, name string, t *table) error {
+	columns := make([]string, len(t.Columns))
+	params := make([]string, len(t.Columns))
+	for i := range t.Columns {
+		columns[i] = quoteIdentifier(t.Columns[i])
+		params[i] = "?"
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdentifier(name), strings.Join(columns, ", "))); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteIdentifier(name), strings.Join(columns, ", "), strings.Join(params, ", ")))
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	args := make([]interface{}, len(t.Columns))
+	for _, row := range t.Rows {
+		for i, c := range t.Columns {
+			args[i] = row[c]
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// execute evaluates the query against the database
+func execute(ctx context.Context, db *sql.DB, query string) (*result, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &result{Columns: columns}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		for i := range values {
+			if b, ok := values[i].([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		r.Rows = append(r.Rows, values)
+	}
+	return r, rows.Err()
+}
+
+// quoteIdentifier quotes a table or column name, labels in particular may contain characters that are not allowed
+// in a bare SQL identifier
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// formatValue returns the display string for a value
+func formatValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case string:
+		return t
+	}
+	return fmt.Sprintf("%v", v)
+}
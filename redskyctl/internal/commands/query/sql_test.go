@@ -0,0 +1,142 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecute(t *testing.T) {
+	tables := map[string]*table{
+		"trials": {
+			Columns: []string{"experiment", "number", "status", "metric_memory", "label_app.kubernetes.io/name"},
+			Rows: []map[string]interface{}{
+				{"experiment": "foo", "number": 1.0, "status": "completed", "metric_memory": 1024.0},
+				{"experiment": "foo", "number": 2.0, "status": "failed", "metric_memory": 3072.0},
+				{"experiment": "foo", "number": 3.0, "status": "failed"},
+				{"experiment": "bar", "number": 1.0, "status": "completed", "metric_memory": 2048.0, "label_app.kubernetes.io/name": "postgres"},
+			},
+		},
+	}
+
+	ctx := context.TODO()
+	db, err := openDatabase(ctx, tables)
+	require.NoError(t, err)
+	defer db.Close()
+
+	cases := []struct {
+		desc    string
+		query   string
+		columns []string
+		rows    [][]interface{}
+		err     string
+	}{
+		{
+			desc:    "Wildcard",
+			query:   "SELECT * FROM trials WHERE experiment = 'bar'",
+			columns: []string{"experiment", "number", "status", "metric_memory", "label_app.kubernetes.io/name"},
+			rows:    [][]interface{}{{"bar", 1.0, "completed", 2048.0, "postgres"}},
+		},
+		{
+			desc:    "FilterAndSort",
+			query:   "select number, metric_memory from trials where status = 'failed' or metric_memory > 2000 order by metric_memory desc",
+			columns: []string{"number", "metric_memory"},
+			rows:    [][]interface{}{{2.0, 3072.0}, {1.0, 2048.0}, {3.0, nil}},
+		},
+		{
+			desc:    "IsNull",
+			query:   "SELECT experiment, number FROM trials WHERE metric_memory IS NULL",
+			columns: []string{"experiment", "number"},
+			rows:    [][]interface{}{{"foo", 3.0}},
+		},
+		{
+			desc:    "Like",
+			query:   `SELECT number AS "trial" FROM trials WHERE experiment LIKE 'f%' AND NOT status LIKE 'comp%' LIMIT 1`,
+			columns: []string{"trial"},
+			rows:    [][]interface{}{{2.0}},
+		},
+		{
+			desc:    "Aggregate",
+			query:   "SELECT count(*), avg(metric_memory), max(metric_memory) FROM trials",
+			columns: []string{"count(*)", "avg(metric_memory)", "max(metric_memory)"},
+			rows:    [][]interface{}{{int64(4), 2048.0, 3072.0}},
+		},
+		{
+			desc:    "GroupBy",
+			query:   "SELECT experiment, count(metric_memory) AS measured FROM trials GROUP BY experiment ORDER BY experiment;",
+			columns: []string{"experiment", "measured"},
+			rows:    [][]interface{}{{"bar", int64(1)}, {"foo", int64(2)}},
+		},
+		{
+			desc:    "QuotedLabel",
+			query:   `SELECT number FROM trials WHERE "label_app.kubernetes.io/name" = 'postgres'`,
+			columns: []string{"number"},
+			rows:    [][]interface{}{{1.0}},
+		},
+		{
+			desc:  "UnknownTable",
+			query: "SELECT * FROM foo",
+			err:   "no such table: foo",
+		},
+		{
+			desc:  "Incomplete",
+			query: "SELECT number FROM trials WHERE",
+			err:   "incomplete input",
+		},
+		{
+			desc:  "ReadOnly",
+			query: "DELETE FROM trials",
+			err:   "readonly database",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			r, err := execute(ctx, db, c.query)
+			if c.err != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), c.err)
+				}
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, c.columns, r.Columns)
+				assert.Equal(t, c.rows, r.Rows)
+			}
+		})
+	}
+}
+
+func TestFormatValue(t *testing.T) {
+	cases := []struct {
+		desc     string
+		value    interface{}
+		expected string
+	}{
+		{desc: "null", value: nil, expected: ""},
+		{desc: "real", value: 1.5, expected: "1.5"},
+		{desc: "integral real", value: 2048.0, expected: "2048"},
+		{desc: "integer", value: int64(4), expected: "4"},
+		{desc: "text", value: "completed", expected: "completed"},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.expected, formatValue(c.value))
+		})
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Options is the configuration for working with the pending trial queue
+type Options struct {
+	// Config is the Red Sky Configuration
+	Config config.Config
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// Namespace is the namespace of the experiment or trials
+	Namespace string
+	// Experiment is the name of the experiment whose queue is being displayed
+	Experiment string
+	// Trials are the names of the pending trials being modified
+	Trials []string
+	// Priority is the priority assigned to trials
+	Priority int32
+}
+
+// NewCommand creates a new command for working with the pending trial queue
+func NewCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue EXPERIMENT",
+		Short: "Pending trials",
+		Long:  "Display the trials which have been suggested but have not yet started",
+
+		Args: cobra.ExactArgs(1),
+
+		PreRun: func(cmd *cobra.Command, args []string) {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.Experiment = args[0]
+		},
+		RunE: commander.WithContextE(o.list),
+	}
+
+	cmd.PersistentFlags().StringVarP(&o.Namespace, "namespace", "n", o.Namespace, "Namespace of the experiment or trials.")
+
+	cmd.AddCommand(newCancelCommand(o))
+	cmd.AddCommand(newPrioritizeCommand(o))
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func newCancelCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel TRIAL...",
+		Short: "Cancel pending trials",
+		Long:  "Cancel pending trials, the suggestions are abandoned and will not be reported to the server",
+
+		Args: cobra.MinimumNArgs(1),
+
+		PreRun: func(cmd *cobra.Command, args []string) {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.Trials = args
+		},
+		RunE: commander.WithContextE(o.cancel),
+	}
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func newPrioritizeCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prioritize TRIAL...",
+		Short: "Prioritize pending trials",
+		Long:  "Change the priority of pending trials, trials with a higher priority are started first",
+
+		Args: cobra.MinimumNArgs(1),
+
+		PreRun: func(cmd *cobra.Command, args []string) {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.Trials = args
+		},
+		RunE: commander.WithContextE(o.prioritize),
+	}
+
+	cmd.Flags().Int32Var(&o.Priority, "priority", 1, "Priority to assign to the trials.")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *Options) list(ctx context.Context) error {
+	exp := &redskyv1beta1.Experiment{}
+	kubectlGet, err := o.Config.Kubectl(ctx, o.args("get", "experiments.v1beta1.redskyops.dev", o.Experiment, "--output", "json")...)
+	if err != nil {
+		return err
+	}
+	kubectlGet.Stderr = o.ErrOut
+	data, err := kubectlGet.Output()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, exp); err != nil {
+		return err
+	}
+
+	if len(exp.Status.Queue) == 0 {
+		_, _ = fmt.Fprintf(o.Out, "No pending trials for experiment %s\n", exp.Name)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tNAMESPACE\tPRIORITY\tASSIGNMENTS")
+	for _, qt := range exp.Status.Queue {
+		var assignments []string
+		for _, a := range qt.Assignments {
+			assignments = append(assignments, fmt.Sprintf("%s=%s", a.Name, a.Value.String()))
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", qt.Name, qt.Namespace, qt.Priority, strings.Join(assignments, ", "))
+	}
+	return w.Flush()
+}
+
+func (o *Options) cancel(ctx context.Context) error {
+	return o.kubectl(ctx, o.args(append([]string{"delete", "trials.v1beta1.redskyops.dev"}, o.Trials...)...)...)
+}
+
+func (o *Options) prioritize(ctx context.Context) error {
+	args := append([]string{"annotate", "trials.v1beta1.redskyops.dev"}, o.Trials...)
+	args = append(args, "--overwrite", redskyv1beta1.AnnotationTrialPriority+"="+strconv.FormatInt(int64(o.Priority), 10))
+	return o.kubectl(ctx, o.args(args...)...)
+}
+
+// args appends the namespace (if specified) to the supplied kubectl arguments
+func (o *Options) args(args ...string) []string {
+	if o.Namespace != "" {
+		args = append(args, "--namespace", o.Namespace)
+	}
+	return args
+}
+
+// kubectl runs a kubectl command using the standard streams
+func (o *Options) kubectl(ctx context.Context, args ...string) error {
+	cmd, err := o.Config.Kubectl(ctx, args...)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = o.Out
+	cmd.Stderr = o.ErrOut
+	return cmd.Run()
+}
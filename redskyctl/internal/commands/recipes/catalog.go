@@ -0,0 +1,462 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipes
+
+// NOTE: Recipe templates use "[[" and "]]" as delimiters so the experiment patch templates can be left as-is
+
+// Catalog is the embedded list of recipes
+var Catalog = []Recipe{
+	{
+		Name:        "postgres",
+		Description: "Tune PostgreSQL memory and connection settings of a StatefulSet",
+		Template: `apiVersion: redskyops.dev/v1beta1
+kind: Experiment
+metadata:
+  name: [[ .Name ]]-postgres
+  namespace: [[ .Namespace ]]
+spec:
+  parameters:
+  - name: cpu
+    min: 500
+    max: 4000
+  - name: memory
+    min: 512
+    max: 8192
+  - name: max_connections
+    min: 20
+    max: 500
+  metrics:
+  - name: duration
+    minimize: true
+    query: "{{duration .StartTime .CompletionTime}}"
+  - name: cost
+    minimize: true
+    type: pods
+    query: "{{resourceRequests .Pods \"cpu=0.017,memory=0.000000000003\"}}"
+    selector:
+      matchLabels:
+        app.kubernetes.io/name: [[ .Name ]]
+  patches:
+  - targetRef:
+      kind: StatefulSet
+      apiVersion: apps/v1
+      name: [[ .Name ]]
+    patch: |
+      spec:
+        template:
+          spec:
+            containers:
+            - name: postgres
+              args: ["-c", "max_connections={{ .Values.max_connections }}"]
+              resources:
+                limits:
+                  cpu: "{{ .Values.cpu }}m"
+                  memory: "{{ .Values.memory }}Mi"
+                requests:
+                  cpu: "{{ .Values.cpu }}m"
+                  memory: "{{ .Values.memory }}Mi"
+  trialTemplate:
+    spec:
+      jobTemplate:
+        spec:
+          template:
+            spec:
+              containers:
+              - name: pgbench
+                image: postgres:12
+                command: ["pgbench", "--client=10", "--time=120", "--host=[[ .Name ]]", "--username=postgres", "postgres"]
+`,
+	},
+	{
+		Name:        "nginx",
+		Description: "Tune NGINX worker processes and resources of a Deployment",
+		Template: `apiVersion: redskyops.dev/v1beta1
+kind: Experiment
+metadata:
+  name: [[ .Name ]]-nginx
+  namespace: [[ .Namespace ]]
+spec:
+  parameters:
+  - name: cpu
+    min: 100
+    max: 2000
+  - name: memory
+    min: 64
+    max: 1024
+  - name: worker_processes
+    min: 1
+    max: 8
+  metrics:
+  - name: duration
+    minimize: true
+    query: "{{duration .StartTime .CompletionTime}}"
+  - name: cost
+    minimize: true
+    type: pods
+    query: "{{resourceRequests .Pods \"cpu=0.017,memory=0.000000000003\"}}"
+    selector:
+      matchLabels:
+        app.kubernetes.io/name: [[ .Name ]]
+  patches:
+  - targetRef:
+      kind: Deployment
+      apiVersion: apps/v1
+      name: [[ .Name ]]
+    patch: |
+      spec:
+        template:
+          spec:
+            containers:
+            - name: nginx
+              env:
+              - name: NGINX_WORKER_PROCESSES
+                value: "{{ .Values.worker_processes }}"
+              resources:
+                limits:
+                  cpu: "{{ .Values.cpu }}m"
+                  memory: "{{ .Values.memory }}Mi"
+                requests:
+                  cpu: "{{ .Values.cpu }}m"
+                  memory: "{{ .Values.memory }}Mi"
+  trialTemplate:
+    spec:
+      jobTemplate:
+        spec:
+          template:
+            spec:
+              containers:
+              - name: ab
+                image: httpd:2.4
+                command: ["ab", "-n", "100000", "-c", "50", "http://[[ .Name ]]/"]
+`,
+	},
+	{
+		Name:        "ingress-nginx",
+		Description: "Tune NGINX Ingress Controller workers, keepalive and buffer sizes using its ConfigMap",
+		Template: `apiVersion: redskyops.dev/v1beta1
+kind: Experiment
+metadata:
+  name: [[ .Name ]]-ingress-nginx
+  namespace: [[ .Namespace ]]
+spec:
+  parameters:
+  - name: cpu
+    min: 100
+    max: 2000
+  - name: memory
+    min: 90
+    max: 1024
+  - name: worker_processes
+    min: 1
+    max: 8
+  - name: keep_alive
+    min: 5
+    max: 120
+  - name: keep_alive_requests
+    min: 100
+    max: 10000
+  - name: upstream_keepalive_connections
+    min: 16
+    max: 1024
+  - name: proxy_buffer_size
+    min: 4
+    max: 64
+  metrics:
+  - name: duration
+    minimize: true
+    query: "{{duration .StartTime .CompletionTime}}"
+  - name: cost
+    minimize: true
+    type: pods
+    query: "{{resourceRequests .Pods \"cpu=0.017,memory=0.000000000003\"}}"
+    selector:
+      matchLabels:
+        app.kubernetes.io/name: ingress-nginx
+  patches:
+  - targetRef:
+      kind: ConfigMap
+      apiVersion: v1
+      name: [[ .Name ]]
+    patch: |
+      data:
+        worker-processes: "{{ .Values.worker_processes }}"
+        keep-alive: "{{ .Values.keep_alive }}"
+        keep-alive-requests: "{{ .Values.keep_alive_requests }}"
+        upstream-keepalive-connections: "{{ .Values.upstream_keepalive_connections }}"
+        proxy-buffer-size: "{{ .Values.proxy_buffer_size }}k"
+  - targetRef:
+      kind: Deployment
+      apiVersion: apps/v1
+      name: [[ .Name ]]
+    patch: |
+      spec:
+        template:
+          metadata:
+            annotations:
+              redskyops.dev/config-hash: "{{ configHash .Values }}"
+          spec:
+            containers:
+            - name: controller
+              resources:
+                limits:
+                  cpu: "{{ .Values.cpu }}m"
+                  memory: "{{ .Values.memory }}Mi"
+                requests:
+                  cpu: "{{ .Values.cpu }}m"
+                  memory: "{{ .Values.memory }}Mi"
+  trialTemplate:
+    spec:
+      jobTemplate:
+        spec:
+          template:
+            spec:
+              containers:
+              - name: ab
+                image: httpd:2.4
+                command: ["ab", "-k", "-n", "100000", "-c", "50", "http://[[ .Name ]]/"]
+`,
+	},
+	{
+		Name:        "envoy",
+		Description: "Tune Envoy front proxy concurrency, buffer limits and timeouts using a bootstrap ConfigMap",
+		Template: `apiVersion: redskyops.dev/v1beta1
+kind: Experiment
+metadata:
+  name: [[ .Name ]]-envoy
+  namespace: [[ .Namespace ]]
+spec:
+  parameters:
+  - name: cpu
+    min: 100
+    max: 2000
+  - name: memory
+    min: 64
+    max: 1024
+  - name: concurrency
+    min: 1
+    max: 8
+  - name: buffer_limit
+    min: 16
+    max: 1024
+  - name: idle_timeout
+    min: 10
+    max: 600
+  - name: max_connections
+    min: 100
+    max: 10000
+  metrics:
+  - name: duration
+    minimize: true
+    query: "{{duration .StartTime .CompletionTime}}"
+  - name: cost
+    minimize: true
+    type: pods
+    query: "{{resourceRequests .Pods \"cpu=0.017,memory=0.000000000003\"}}"
+    selector:
+      matchLabels:
+        app.kubernetes.io/name: [[ .Name ]]
+  patches:
+  - targetRef:
+      kind: ConfigMap
+      apiVersion: v1
+      name: [[ .Name ]]
+    patch: |
+      data:
+        envoy.yaml: |
+          static_resources:
+            listeners:
+            - name: ingress
+              address:
+                socket_address: { address: 0.0.0.0, port_value: 8080 }
+              per_connection_buffer_limit_bytes: {{ mul .Values.buffer_limit 1024 }}
+              filter_chains:
+              - filters:
+                - name: envoy.filters.network.http_connection_manager
+                  typed_config:
+                    "@type": type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager
+                    stat_prefix: ingress
+                    common_http_protocol_options:
+                      idle_timeout: {{ .Values.idle_timeout }}s
+                    route_config:
+                      virtual_hosts:
+                      - name: backend
+                        domains: ["*"]
+                        routes:
+                        - match: { prefix: "/" }
+                          route: { cluster: backend }
+                    http_filters:
+                    - name: envoy.filters.http.router
+                      typed_config:
+                        "@type": type.googleapis.com/envoy.extensions.filters.http.router.v3.Router
+            clusters:
+            - name: backend
+              type: STRICT_DNS
+              connect_timeout: 1s
+              per_connection_buffer_limit_bytes: {{ mul .Values.buffer_limit 1024 }}
+              circuit_breakers:
+                thresholds:
+                - max_connections: {{ .Values.max_connections }}
+              load_assignment:
+                cluster_name: backend
+                endpoints:
+                - lb_endpoints:
+                  - endpoint:
+                      address:
+                        socket_address: { address: [[ .Name ]]-backend, port_value: 80 }
+  - targetRef:
+      kind: Deployment
+      apiVersion: apps/v1
+      name: [[ .Name ]]
+    patch: |
+      spec:
+        template:
+          metadata:
+            annotations:
+              redskyops.dev/config-hash: "{{ configHash .Values }}"
+          spec:
+            containers:
+            - name: envoy
+              args: ["-c", "/etc/envoy/envoy.yaml", "--concurrency", "{{ .Values.concurrency }}"]
+              resources:
+                limits:
+                  cpu: "{{ .Values.cpu }}m"
+                  memory: "{{ .Values.memory }}Mi"
+                requests:
+                  cpu: "{{ .Values.cpu }}m"
+                  memory: "{{ .Values.memory }}Mi"
+  trialTemplate:
+    spec:
+      jobTemplate:
+        spec:
+          template:
+            spec:
+              containers:
+              - name: ab
+                image: httpd:2.4
+                command: ["ab", "-k", "-n", "100000", "-c", "50", "http://[[ .Name ]]:8080/"]
+`,
+	},
+	{
+		Name:        "jvm",
+		Description: "Tune JVM heap and garbage collection settings of a Deployment",
+		Template: `apiVersion: redskyops.dev/v1beta1
+kind: Experiment
+metadata:
+  name: [[ .Name ]]-jvm
+  namespace: [[ .Namespace ]]
+spec:
+  parameters:
+  - name: cpu
+    min: 500
+    max: 4000
+  - name: memory
+    min: 512
+    max: 8192
+  - name: heap_percent
+    min: 25
+    max: 90
+  - name: parallel_gc_threads
+    min: 1
+    max: 8
+  metrics:
+  - name: duration
+    minimize: true
+    query: "{{duration .StartTime .CompletionTime}}"
+  - name: cost
+    minimize: true
+    type: pods
+    query: "{{resourceRequests .Pods \"cpu=0.017,memory=0.000000000003\"}}"
+    selector:
+      matchLabels:
+        app.kubernetes.io/name: [[ .Name ]]
+  patches:
+  - targetRef:
+      kind: Deployment
+      apiVersion: apps/v1
+      name: [[ .Name ]]
+    patch: |
+      spec:
+        template:
+          spec:
+            containers:
+            - name: [[ .Name ]]
+              env:
+              - name: JAVA_TOOL_OPTIONS
+                value: "-XX:MaxRAMPercentage={{ .Values.heap_percent }} -XX:ParallelGCThreads={{ .Values.parallel_gc_threads }}"
+              resources:
+                limits:
+                  cpu: "{{ .Values.cpu }}m"
+                  memory: "{{ .Values.memory }}Mi"
+                requests:
+                  cpu: "{{ .Values.cpu }}m"
+                  memory: "{{ .Values.memory }}Mi"
+`,
+	},
+	{
+		Name:        "elasticsearch",
+		Description: "Tune Elasticsearch heap size and resources of a StatefulSet",
+		Template: `apiVersion: redskyops.dev/v1beta1
+kind: Experiment
+metadata:
+  name: [[ .Name ]]-elasticsearch
+  namespace: [[ .Namespace ]]
+spec:
+  parameters:
+  - name: cpu
+    min: 1000
+    max: 4000
+  - name: memory
+    min: 2048
+    max: 16384
+  - name: heap_percent
+    min: 25
+    max: 75
+  metrics:
+  - name: duration
+    minimize: true
+    query: "{{duration .StartTime .CompletionTime}}"
+  - name: cost
+    minimize: true
+    type: pods
+    query: "{{resourceRequests .Pods \"cpu=0.017,memory=0.000000000003\"}}"
+    selector:
+      matchLabels:
+        app: [[ .Name ]]
+  patches:
+  - targetRef:
+      kind: StatefulSet
+      apiVersion: apps/v1
+      name: [[ .Name ]]
+    patch: |
+      spec:
+        template:
+          spec:
+            containers:
+            - name: elasticsearch
+              env:
+              - name: ES_JAVA_OPTS
+                value: "-Xms{{ percent .Values.memory .Values.heap_percent }}m -Xmx{{ percent .Values.memory .Values.heap_percent }}m"
+              resources:
+                limits:
+                  cpu: "{{ .Values.cpu }}m"
+                  memory: "{{ .Values.memory }}Mi"
+                requests:
+                  cpu: "{{ .Values.cpu }}m"
+                  memory: "{{ .Values.memory }}Mi"
+`,
+	},
+}
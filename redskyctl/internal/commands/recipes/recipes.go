@@ -0,0 +1,232 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/redskyops/redskyops-controller/internal/version"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Recipe is a ready-made experiment template
+type Recipe struct {
+	// Name is the unique name of the recipe
+	Name string `json:"name"`
+	// Description is a short summary of what the recipe tunes
+	Description string `json:"description"`
+	// Template produces the experiment manifest given the target name and namespace
+	Template string `json:"template"`
+}
+
+// Options is the configuration for working with the recipe catalog
+type Options struct {
+	// Config is the Red Sky Configuration
+	Config config.Config
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// CatalogURL is the location of an additional catalog to merge with the embedded catalog
+	CatalogURL string
+	// Recipe is the name of the recipe to apply
+	Recipe string
+	// TargetName is the name of the application being tuned
+	TargetName string
+	// TargetNamespace is the namespace of the application being tuned
+	TargetNamespace string
+	// DryRun prints the experiment instead of applying it
+	DryRun bool
+}
+
+// NewCommand creates a new command for working with recipes
+func NewCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recipes",
+		Short: "Ready-made experiments",
+		Long:  "Work with the catalog of ready-made experiments",
+	}
+
+	cmd.PersistentFlags().StringVar(&o.CatalogURL, "catalog-url", o.CatalogURL, "Refresh the catalog from the specified `URL`.")
+
+	cmd.AddCommand(newListCommand(o))
+	cmd.AddCommand(newApplyCommand(o))
+
+	return cmd
+}
+
+func newListCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recipes",
+		Long:  "List the available recipes",
+
+		PreRun: commander.StreamsPreRun(&o.IOStreams),
+		RunE:   commander.WithContextE(o.list),
+	}
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func newApplyCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply NAME",
+		Short: "Apply a recipe",
+		Long:  "Create an experiment from a recipe",
+
+		Args: cobra.ExactArgs(1),
+
+		PreRun: func(cmd *cobra.Command, args []string) {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.Recipe = args[0]
+		},
+		RunE: commander.WithContextE(o.apply),
+	}
+
+	cmd.Flags().StringVar(&o.TargetName, "target", o.TargetName, "Name of the application to tune.")
+	cmd.Flags().StringVar(&o.TargetNamespace, "target-namespace", "default", "Namespace of the application to tune.")
+	cmd.Flags().BoolVar(&o.DryRun, "dry-run", o.DryRun, "Print the experiment instead of creating it.")
+
+	_ = cmd.MarkFlagRequired("target")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *Options) list(ctx context.Context) error {
+	catalog, err := o.catalog(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tDESCRIPTION")
+	for _, r := range catalog {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", r.Name, r.Description)
+	}
+	return w.Flush()
+}
+
+func (o *Options) apply(ctx context.Context) error {
+	catalog, err := o.catalog(ctx)
+	if err != nil {
+		return err
+	}
+
+	var recipe *Recipe
+	for i := range catalog {
+		if catalog[i].Name == o.Recipe {
+			recipe = &catalog[i]
+			break
+		}
+	}
+	if recipe == nil {
+		return fmt.Errorf("unknown recipe: %s", o.Recipe)
+	}
+
+	manifest, err := recipe.Render(o.TargetName, o.TargetNamespace)
+	if err != nil {
+		return err
+	}
+
+	if o.DryRun {
+		_, err := o.Out.Write(manifest)
+		return err
+	}
+
+	kubectlCreate, err := o.Config.Kubectl(ctx, "create", "-f", "-")
+	if err != nil {
+		return err
+	}
+	kubectlCreate.Stdout = o.Out
+	kubectlCreate.Stderr = o.ErrOut
+	kubectlCreate.Stdin = bytes.NewReader(manifest)
+	return kubectlCreate.Run()
+}
+
+// Render produces the experiment manifest for the supplied target
+func (r *Recipe) Render(name, namespace string) ([]byte, error) {
+	tmpl, err := template.New(r.Name).Delims("[[", "]]").Parse(r.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	data := struct{ Name, Namespace string }{Name: name, Namespace: namespace}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// catalog returns the embedded catalog merged with the remote catalog (if configured)
+func (o *Options) catalog(ctx context.Context) ([]Recipe, error) {
+	recipes := make(map[string]Recipe, len(Catalog))
+	for _, r := range Catalog {
+		recipes[r.Name] = r
+	}
+
+	if o.CatalogURL != "" {
+		remote, err := fetchCatalog(ctx, o.CatalogURL)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range remote {
+			recipes[r.Name] = r
+		}
+	}
+
+	result := make([]Recipe, 0, len(recipes))
+	for _, r := range recipes {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// fetchCatalog retrieves a JSON list of recipes from a remote location
+func fetchCatalog(ctx context.Context, url string) ([]Recipe, error) {
+	client := &http.Client{Transport: version.UserAgent("RedSkyOps", "", nil)}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unable to refresh catalog (%d)", resp.StatusCode)
+	}
+
+	var recipes []Recipe
+	if err := json.NewDecoder(resp.Body).Decode(&recipes); err != nil {
+		return nil, err
+	}
+	return recipes, nil
+}
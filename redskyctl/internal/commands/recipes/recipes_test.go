@@ -0,0 +1,48 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipes
+
+import (
+	"testing"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/yaml"
+)
+
+func TestCatalog(t *testing.T) {
+	for i := range Catalog {
+		r := &Catalog[i]
+		t.Run(r.Name, func(t *testing.T) {
+			manifest, err := r.Render("foo", "bar")
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			exp := &redskyv1beta1.Experiment{}
+			if assert.NoError(t, yaml.UnmarshalStrict(manifest, exp)) {
+				assert.Equal(t, "foo-"+r.Name, exp.Name)
+				assert.Equal(t, "bar", exp.Namespace)
+				assert.NotEmpty(t, exp.Spec.Parameters)
+				assert.NotEmpty(t, exp.Spec.Metrics)
+				for _, p := range exp.Spec.Patches {
+					assert.Equal(t, "foo", p.TargetRef.Name)
+				}
+			}
+		})
+	}
+}
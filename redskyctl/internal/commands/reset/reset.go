@@ -52,7 +52,7 @@ func NewCommand(o *Options) *cobra.Command {
 
 func (o *Options) reset(ctx context.Context) error {
 	// Delete the CRDs first to avoid issues with the controller being deleted before it can remove the finalizers
-	deleteCRD, err := o.Config.Kubectl(ctx, "delete", "--ignore-not-found", "crd", "trials.redskyops.dev", "experiments.redskyops.dev")
+	deleteCRD, err := o.Config.Kubectl(ctx, "delete", "--ignore-not-found", "crd", "trials.redskyops.dev", "experiments.redskyops.dev", "experimentschedules.redskyops.dev")
 	if err != nil {
 		return err
 	}
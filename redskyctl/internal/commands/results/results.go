@@ -18,14 +18,18 @@ package results
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httputil"
 	"os/user"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pkg/browser"
+	"github.com/redskyops/redskyops-controller/internal/analysis"
+	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
 	"github.com/redskyops/redskyops-ui/v2/ui"
@@ -36,6 +40,8 @@ import (
 type Options struct {
 	// Config is the Red Sky Configuration to proxy
 	Config config.Config
+	// ExperimentsAPI is used to answer analysis requests
+	ExperimentsAPI experimentsv1alpha1.API
 	// IOStreams are used to access the standard process streams
 	commander.IOStreams
 
@@ -53,9 +59,10 @@ func NewCommand(o *Options) *cobra.Command {
 		Use:   "results",
 		Short: "Serve a visualization of the results",
 
-		PreRun: func(cmd *cobra.Command, args []string) {
+		PreRunE: func(cmd *cobra.Command, args []string) error {
 			commander.SetStreams(&o.IOStreams, cmd)
 			o.Complete()
+			return commander.SetExperimentsAPI(&o.ExperimentsAPI, o.Config, cmd)
 		},
 		RunE: commander.WithContextE(o.results),
 	}
@@ -81,6 +88,7 @@ func (o *Options) results(ctx context.Context) error {
 	if err := o.handleAPI(router, "/v1/"); err != nil {
 		return err
 	}
+	o.handleSignificance(router, "/api/significance")
 	o.handleUI(router, "/ui/")
 	o.handleLiveness(router, "/health")
 
@@ -147,6 +155,36 @@ func (o *Options) handleAPI(serveMux *http.ServeMux, prefix string) error {
 	return nil
 }
 
+// handleSignificance compares two trials, e.g. "/api/significance?experiment=NAME&a=1&b=2&metric=cost&test=t-test&alpha=0.05"
+func (o *Options) handleSignificance(serveMux *http.ServeMux, prefix string) {
+	serveMux.HandleFunc(prefix, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		a, errA := strconv.ParseInt(q.Get("a"), 10, 64)
+		b, errB := strconv.ParseInt(q.Get("b"), 10, 64)
+		if q.Get("experiment") == "" || errA != nil || errB != nil {
+			http.Error(w, "experiment name and trial numbers 'a' and 'b' are required", http.StatusBadRequest)
+			return
+		}
+		alpha := 0.05
+		if v := q.Get("alpha"); v != "" {
+			var err error
+			if alpha, err = strconv.ParseFloat(v, 64); err != nil {
+				http.Error(w, "invalid significance level", http.StatusBadRequest)
+				return
+			}
+		}
+
+		s, err := analysis.CompareTrials(r.Context(), o.ExperimentsAPI, q.Get("experiment"), a, b, q.Get("metric"), analysis.Test(q.Get("test")), alpha)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s)
+	})
+}
+
 func (o *Options) handleUI(serveMux *http.ServeMux, prefix string) {
 	serveMux.Handle("/", http.RedirectHandler(prefix, http.StatusMovedPermanently))
 	serveMux.Handle(prefix, http.StripPrefix(prefix, http.FileServer(ui.Assets)))
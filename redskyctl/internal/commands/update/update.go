@@ -0,0 +1,230 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/redskyops/redskyops-controller/internal/version"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/check"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// ChannelStable only considers full releases
+	ChannelStable = "stable"
+	// ChannelBeta also considers pre-releases
+	ChannelBeta = "beta"
+)
+
+// Options is the configuration for updating the current executable
+type Options struct {
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// Channel is the release channel to update from
+	Channel string
+	// Force replaces the current executable even if it is already the latest version
+	Force bool
+}
+
+// NewCommand creates a new command for updating the current executable
+func NewCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update redskyctl",
+		Long:  "Update redskyctl to the latest version",
+
+		PreRun: commander.StreamsPreRun(&o.IOStreams),
+		RunE:   commander.WithContextE(o.update),
+	}
+
+	cmd.Flags().StringVar(&o.Channel, "channel", ChannelStable, "Release `channel` to update from; one of: stable|beta.")
+	cmd.Flags().BoolVar(&o.Force, "force", o.Force, "Replace the current executable even if it is already the latest version.")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *Options) update(ctx context.Context) error {
+	// Find the release to install
+	var releases check.ReleaseList
+	if err := check.GetJSON(ctx, check.GitHubReleasesURL, &releases); err != nil {
+		return err
+	}
+	release, err := o.latest(releases)
+	if err != nil {
+		return err
+	}
+
+	// Check if we are already up to date
+	cur := version.GetInfo()
+	if upToDate(release.TagName, cur.Version) && !o.Force {
+		_, _ = fmt.Fprintf(o.Out, "Version %s is the latest version\n", cur.String())
+		return nil
+	}
+
+	// Find the platform specific asset
+	asset, err := platformAsset(release, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+	name := asset.Name
+
+	// Only trust checksums which have a valid signature
+	checksums, err := release.VerifiedChecksums(ctx)
+	if err != nil {
+		return err
+	}
+	checksum, ok := checksums[name]
+	if !ok {
+		return fmt.Errorf("release %s has no checksum for %s", release.TagName, name)
+	}
+
+	// Locate the current executable
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return err
+	}
+
+	// Download and verify the new executable next to the current one, then swap them
+	tmp, err := o.download(ctx, asset.BrowserDownloadURL, checksum, filepath.Dir(exe))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	if err := os.Rename(tmp, exe); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(o.Out, "Updated to %s (from %s)\n", release.TagName, cur.String())
+	return nil
+}
+
+// latest returns the newest release on the configured channel
+func (o *Options) latest(releases check.ReleaseList) (*check.Release, error) {
+	switch o.Channel {
+	case ChannelStable, "":
+		if r := releases.Latest(); r != nil {
+			return r, nil
+		}
+	case ChannelBeta:
+		for i := range releases {
+			if !releases[i].Draft {
+				return &releases[i], nil
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown release channel: %s", o.Channel)
+	}
+	return nil, fmt.Errorf("unable to find latest version")
+}
+
+// upToDate checks to see if the release tag matches the current version, ignoring the optional "v" prefix
+func upToDate(tag, cur string) bool {
+	return strings.TrimPrefix(tag, "v") == strings.TrimPrefix(cur, "v")
+}
+
+// platformAsset returns the release archive for the supplied operating system and architecture
+func platformAsset(release *check.Release, goos, goarch string) (*check.Asset, error) {
+	asset := release.AssetByName(fmt.Sprintf("redskyctl-%s-%s.tar.gz", goos, goarch))
+	if asset == nil {
+		return nil, fmt.Errorf("release %s is not available for %s/%s", release.TagName, goos, goarch)
+	}
+	return asset, nil
+}
+
+// download fetches the archive, verifies the checksum and extracts the executable into a temporary file in the
+// supplied directory, returning the file name
+func (o *Options) download(ctx context.Context, url, checksum, dir string) (string, error) {
+	client := &http.Client{Transport: version.UserAgent("RedSkyOps", "", nil)}
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", &check.APIError{StatusCode: resp.StatusCode}
+	}
+
+	// Verify the archive checksum before we extract anything
+	archive, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(archive)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), checksum) {
+		return "", fmt.Errorf("checksum mismatch for %s", url)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return "", err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return "", fmt.Errorf("archive does not contain redskyctl")
+		} else if err != nil {
+			return "", err
+		}
+		if h.Typeflag != tar.TypeReg || filepath.Base(h.Name) != "redskyctl" {
+			continue
+		}
+
+		f, err := ioutil.TempFile(dir, ".redskyctl-update-")
+		if err != nil {
+			return "", err
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+			return "", err
+		}
+		if err := f.Close(); err != nil {
+			_ = os.Remove(f.Name())
+			return "", err
+		}
+		if err := os.Chmod(f.Name(), 0755); err != nil {
+			_ = os.Remove(f.Name())
+			return "", err
+		}
+		return f.Name(), nil
+	}
+}
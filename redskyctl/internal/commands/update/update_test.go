@@ -0,0 +1,200 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/check"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpToDate(t *testing.T) {
+	cases := []struct {
+		desc     string
+		tag      string
+		cur      string
+		expected bool
+	}{
+		{desc: "same", tag: "v1.2.3", cur: "v1.2.3", expected: true},
+		{desc: "missing prefix", tag: "1.2.3", cur: "v1.2.3", expected: true},
+		{desc: "older", tag: "v1.2.3", cur: "v1.2.2"},
+		{desc: "newer", tag: "v1.2.3", cur: "v1.3.0"},
+		{desc: "prerelease", tag: "v1.2.3", cur: "v1.2.3-beta.1"},
+		{desc: "source", tag: "v1.2.3", cur: "v0.0.0-source"},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			assert.Equal(t, c.expected, upToDate(c.tag, c.cur))
+		})
+	}
+}
+
+func TestOptions_Latest(t *testing.T) {
+	releases := check.ReleaseList{
+		{TagName: "v1.3.0", Draft: true},
+		{TagName: "v1.3.0-beta.1", Prerelease: true},
+		{TagName: "v1.2.3"},
+	}
+	cases := []struct {
+		desc     string
+		channel  string
+		releases check.ReleaseList
+		expected string
+		err      string
+	}{
+		{desc: "default", releases: releases, expected: "v1.2.3"},
+		{desc: "stable", channel: ChannelStable, releases: releases, expected: "v1.2.3"},
+		{desc: "beta", channel: ChannelBeta, releases: releases, expected: "v1.3.0-beta.1"},
+		{desc: "unknown channel", channel: "nightly", releases: releases, err: "unknown release channel: nightly"},
+		{desc: "no stable release", channel: ChannelStable, releases: releases[:2], err: "unable to find latest version"},
+		{desc: "no releases", channel: ChannelBeta, err: "unable to find latest version"},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			o := &Options{Channel: c.channel}
+			r, err := o.latest(c.releases)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, r.TagName)
+			}
+		})
+	}
+}
+
+func TestPlatformAsset(t *testing.T) {
+	release := &check.Release{
+		TagName: "v1.2.3",
+		Assets: []check.Asset{
+			{Name: "checksums.txt"},
+			{Name: "redskyctl-darwin-amd64.tar.gz", BrowserDownloadURL: "https://example.com/darwin"},
+			{Name: "redskyctl-linux-amd64.tar.gz", BrowserDownloadURL: "https://example.com/linux"},
+		},
+	}
+	cases := []struct {
+		desc     string
+		goos     string
+		goarch   string
+		expected string
+		err      string
+	}{
+		{desc: "linux", goos: "linux", goarch: "amd64", expected: "https://example.com/linux"},
+		{desc: "darwin", goos: "darwin", goarch: "amd64", expected: "https://example.com/darwin"},
+		{desc: "unsupported", goos: "linux", goarch: "arm64", err: "release v1.2.3 is not available for linux/arm64"},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			asset, err := platformAsset(release, c.goos, c.goarch)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, c.expected, asset.BrowserDownloadURL)
+			}
+		})
+	}
+}
+
+func TestOptions_Download(t *testing.T) {
+	archive := newArchive(t, map[string]string{"README.md": "readme", "redskyctl": "new executable"})
+	empty := newArchive(t, map[string]string{"README.md": "readme"})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redskyctl.tar.gz":
+			_, _ = w.Write(archive)
+		case "/empty.tar.gz":
+			_, _ = w.Write(empty)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cases := []struct {
+		desc     string
+		path     string
+		checksum string
+		err      string
+	}{
+		{desc: "verified", path: "/redskyctl.tar.gz", checksum: sha256Hex(archive)},
+		{desc: "verified upper case", path: "/redskyctl.tar.gz", checksum: strings.ToUpper(sha256Hex(archive))},
+		{desc: "checksum mismatch", path: "/redskyctl.tar.gz", checksum: sha256Hex(empty), err: "checksum mismatch for " + srv.URL + "/redskyctl.tar.gz"},
+		{desc: "missing executable", path: "/empty.tar.gz", checksum: sha256Hex(empty), err: "archive does not contain redskyctl"},
+		{desc: "not found", path: "/missing.tar.gz", err: "unexpected response (404)"},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "redskyctl-update-test-")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			o := &Options{}
+			name, err := o.download(context.TODO(), srv.URL+c.path, c.checksum, dir)
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				files, err := ioutil.ReadDir(dir)
+				require.NoError(t, err)
+				assert.Empty(t, files)
+				return
+			}
+			require.NoError(t, err)
+			data, err := ioutil.ReadFile(name)
+			require.NoError(t, err)
+			assert.Equal(t, "new executable", string(data))
+			info, err := os.Stat(name)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+		})
+	}
+}
+
+// newArchive returns a gzipped tar archive containing the supplied file names and contents
+func newArchive(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+// sha256Hex returns the hex encoded SHA-256 checksum of the data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
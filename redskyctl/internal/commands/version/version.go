@@ -18,8 +18,14 @@ package version
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"sort"
 	"strings"
 	"text/template"
 
@@ -27,6 +33,7 @@ import (
 	"github.com/redskyops/redskyops-controller/internal/version"
 	experimentsv1alpha1 "github.com/redskyops/redskyops-controller/redskyapi/experiments/v1alpha1"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/check"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/config"
 	"github.com/redskyops/redskyops-controller/redskyctl/internal/kustomize"
 	"github.com/spf13/cobra"
@@ -59,6 +66,8 @@ type Options struct {
 	ShowSetupToolsImage bool
 	// ShowControllerImage toggles the controller image information
 	ShowControllerImage bool
+	// Verify checks the signatures of the installed components instead of printing versions
+	Verify bool
 	// Debug enables error logging
 	Debug bool
 }
@@ -82,6 +91,7 @@ func NewCommand(o *Options) *cobra.Command {
 
 	cmd.Flags().BoolVar(&o.ShowSetupToolsImage, "setuptools-image", false, "Print only the name of the setuptools image.")
 	cmd.Flags().BoolVar(&o.ShowControllerImage, "controller-image", false, "Print only the name of the controller image.")
+	cmd.Flags().BoolVar(&o.Verify, "verify", o.Verify, "Verify the signature of the controller image (requires cosign).")
 	cmd.Flags().BoolVar(&o.Debug, "debug", o.Debug, "Display debugging information.")
 
 	commander.ExitOnError(cmd)
@@ -96,9 +106,12 @@ func (o *Options) version(ctx context.Context) error {
 	} else if o.ShowControllerImage {
 		_, _ = fmt.Fprintln(o.Out, kustomize.BuildImage)
 		return nil
+	} else if o.Verify {
+		return o.verify(ctx)
 	}
 
 	// Collect all the version information into a map
+	var featureGates map[string]bool
 	data := make(map[string]*version.Info, 3)
 	if o.Product != "" {
 		data[o.Product] = version.GetInfo()
@@ -109,7 +122,8 @@ func (o *Options) version(ctx context.Context) error {
 			_, _ = fmt.Fprintln(o.ErrOut, "controller:", err.Error())
 		}
 	} else if v != nil {
-		data["controller"] = v
+		data["controller"] = &v.Info
+		featureGates = v.FeatureGates
 	}
 	if v, err := o.apiVersion(ctx); err != nil {
 		if o.Debug {
@@ -120,11 +134,30 @@ func (o *Options) version(ctx context.Context) error {
 	}
 
 	// Format the template using the collected version information
-	return template.Must(template.New("version").Parse(defaultTemplate)).Execute(o.Out, data)
+	if err := template.Must(template.New("version").Parse(defaultTemplate)).Execute(o.Out, data); err != nil {
+		return err
+	}
+
+	// Include the feature gates reported by the controller
+	if len(featureGates) > 0 {
+		gates := make([]string, 0, len(featureGates))
+		for k, v := range featureGates {
+			gates = append(gates, fmt.Sprintf("%s=%t", k, v))
+		}
+		sort.Strings(gates)
+		_, _ = fmt.Fprintf(o.Out, "controller feature gates: %s\n", strings.Join(gates, ","))
+	}
+	return nil
+}
+
+// controllerInfo is the version information reported by the controller
+type controllerInfo struct {
+	version.Info
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
 }
 
 // controllerVersion looks for the controller pod and executes `/manager version` to extract the version information
-func (o *Options) controllerVersion(ctx context.Context) (*version.Info, error) {
+func (o *Options) controllerVersion(ctx context.Context) (*controllerInfo, error) {
 	// Get the namespace
 	ns, err := o.Config.SystemNamespace()
 	if err != nil {
@@ -153,13 +186,72 @@ func (o *Options) controllerVersion(ctx context.Context) (*version.Info, error)
 	}
 
 	// Unmarshal
-	info := &version.Info{}
+	info := &controllerInfo{}
 	if err := json.Unmarshal(output, info); err != nil {
 		return nil, err
 	}
 	return info, nil
 }
 
+// verify checks the signature of the image digest actually running in the controller pod
+func (o *Options) verify(ctx context.Context) error {
+	// Get the namespace
+	ns, err := o.Config.SystemNamespace()
+	if err != nil {
+		return err
+	}
+
+	// Get the image identifier (which includes the digest) of the manager container
+	get, err := o.Config.Kubectl(ctx, "--namespace", ns, "--request-timeout", "5s", "get", "pods", "--selector", "control-plane=controller-manager",
+		"--output", `jsonpath={.items[0].status.containerStatuses[?(@.name=="manager")].imageID}`)
+	if err != nil {
+		return err
+	}
+	output, err := get.Output()
+	if err != nil {
+		return err
+	}
+	image := strings.TrimSpace(string(output))
+	if i := strings.Index(image, "://"); i >= 0 {
+		image = image[i+3:]
+	}
+	if !strings.Contains(image, "@sha256:") {
+		return fmt.Errorf("unable to determine controller image digest")
+	}
+
+	// Write the release public key out so cosign can read it
+	if check.ReleasePublicKey == "" {
+		return fmt.Errorf("no release public key is available, signatures cannot be verified")
+	}
+	der, err := base64.StdEncoding.DecodeString(check.ReleasePublicKey)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile("", "redsky-release-*.pub")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := pem.Encode(f, &pem.Block{Type: "PUBLIC KEY", Bytes: der}); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// Verify the image signature
+	cosign := exec.CommandContext(ctx, "cosign", "verify", "--key", f.Name(), image)
+	if o.Debug {
+		cosign.Stderr = o.ErrOut
+	}
+	if err := cosign.Run(); err != nil {
+		return fmt.Errorf("unable to verify controller image %s: %w", image, err)
+	}
+
+	_, _ = fmt.Fprintf(o.Out, "controller image verified: %s\n", image)
+	return nil
+}
+
 // apiVersion gets the API server metadata via an HTTP OPTIONS request
 func (o *Options) apiVersion(ctx context.Context) (*version.Info, error) {
 	// Get the server metadata
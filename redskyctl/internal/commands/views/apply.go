@@ -0,0 +1,104 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package views
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/redskyops/redskyops-controller/internal/config"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commands/experiments"
+	"github.com/spf13/cobra"
+)
+
+// ApplyOptions are the options for displaying trials using a saved view
+type ApplyOptions struct {
+	// Config is the Red Sky Configuration containing the views
+	Config *config.RedSkyConfig
+	// GetOptions are the options used to get the trials
+	GetOptions experiments.GetOptions
+}
+
+// NewApplyCommand creates a new command for displaying trials using a saved view
+func NewApplyCommand(o *ApplyOptions) *cobra.Command {
+	if o.GetOptions.Config == nil {
+		o.GetOptions.Config = o.Config
+	}
+	if o.GetOptions.ChunkSize == 0 {
+		o.GetOptions.ChunkSize = 500
+	}
+
+	// Start with the get command so all of the same flags are available to override the view
+	cmd := experiments.NewGetCommand(&o.GetOptions)
+	cmd.Use = "apply VIEW EXPERIMENT"
+	cmd.Short = "Display trials using a saved view"
+	cmd.Long = "Display the trials of an experiment using a saved view, views saved locally take precedence over views saved on the experiment"
+	cmd.Example = `# Display the trials of an experiment using a saved view
+redskyctl views apply failed-memory my-experiment
+
+# Explicit flags override the saved view
+redskyctl views apply failed-memory my-experiment --sort-order asc`
+	cmd.Args = cobra.ExactArgs(2)
+
+	getPreRunE := cmd.PreRunE
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := o.applyView(cmd, args[0], args[1]); err != nil {
+			return err
+		}
+		return getPreRunE(cmd, []string{"trials", args[1]})
+	}
+
+	return cmd
+}
+
+// applyView sets the flags of the command to the values from the view, flags explicitly set are not changed
+func (o *ApplyOptions) applyView(cmd *cobra.Command, viewName, experimentName string) error {
+	v := findView(o.Config, viewName)
+	if v == nil {
+		var streams commander.IOStreams
+		commander.SetStreams(&streams, cmd)
+		var err error
+		if v, err = experimentView(cmd.Context(), o.Config, streams, experimentName, viewName); err != nil {
+			return err
+		}
+	}
+	if v == nil {
+		return fmt.Errorf("view %q not found", viewName)
+	}
+
+	values := map[string]string{
+		"selector":       v.Selector,
+		"field-selector": v.FieldSelector,
+		"sort-by":        v.SortBy,
+		"sort-order":     v.SortOrder,
+		"output":         v.Output,
+	}
+	if v.All {
+		values["all"] = strconv.FormatBool(v.All)
+	}
+
+	for name, value := range values {
+		if value == "" || cmd.Flags().Changed(name) {
+			continue
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package views
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/redskyops/redskyops-controller/internal/config"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+)
+
+// ListOptions are the options for listing the saved trial views
+type ListOptions struct {
+	// Config is the Red Sky Configuration containing the views
+	Config *config.RedSkyConfig
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+}
+
+// NewListCommand creates a new command for listing saved trial views
+func NewListCommand(o *ListOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved trial views",
+		Long:  "List the trial views saved in the configuration file",
+
+		PreRun: commander.StreamsPreRun(&o.IOStreams),
+		RunE:   commander.WithoutArgsE(o.list),
+	}
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *ListOptions) list() error {
+	tw := tabwriter.NewWriter(o.Out, 0, 0, 3, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAME\tSELECTOR\tFIELD SELECTOR\tSORT BY\tSORT ORDER\tOUTPUT\tALL")
+	for _, v := range o.Config.Views() {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%t\n", v.Name, v.View.Selector, v.View.FieldSelector, v.View.SortBy, v.View.SortOrder, v.View.Output, v.View.All)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package views
+
+import (
+	"context"
+	"encoding/json"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/config"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+)
+
+// SaveOptions are the options for saving a trial view
+type SaveOptions struct {
+	// Config is the Red Sky Configuration to save the view to
+	Config *config.RedSkyConfig
+	// IOStreams are used to access the standard process streams
+	commander.IOStreams
+
+	// Name is the name of the view to save
+	Name string
+	// View is the view being saved
+	View config.View
+	// Experiment is the name of a cluster experiment to also save the view to as an annotation
+	Experiment string
+}
+
+// NewSaveCommand creates a new command for saving a trial view
+func NewSaveCommand(o *SaveOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save NAME",
+		Short: "Save a trial view",
+		Long:  "Save a named combination of trial filters, sort order and output format",
+		Example: `# Save a view of failed trials using the most memory
+redskyctl views save failed-memory --field-selector status=failed --sort-by memory --sort-order desc
+
+# Share the view with everyone working on an experiment
+redskyctl views save failed-memory --field-selector status=failed --experiment my-experiment`,
+		Args: cobra.ExactArgs(1),
+
+		PreRun: func(cmd *cobra.Command, args []string) {
+			commander.SetStreams(&o.IOStreams, cmd)
+			o.Name = args[0]
+		},
+		RunE: commander.WithContextE(o.save),
+	}
+
+	cmd.Flags().StringVarP(&o.View.Selector, "selector", "l", o.View.Selector, "Selector (label `query`) to filter on.")
+	cmd.Flags().StringVar(&o.View.FieldSelector, "field-selector", o.View.FieldSelector, "Selector (field `query`) to filter on, supports '=', '==', and '!='.")
+	cmd.Flags().StringVar(&o.View.SortBy, "sort-by", o.View.SortBy, "Sort using this JSONPath `expression` or metric or parameter name.")
+	cmd.Flags().StringVar(&o.View.SortOrder, "sort-order", o.View.SortOrder, "Sort `order`. One of: asc|desc")
+	cmd.Flags().StringVarP(&o.View.Output, "output", "o", o.View.Output, "Output `format` used when the view is applied.")
+	cmd.Flags().BoolVarP(&o.View.All, "all", "A", o.View.All, "Include all trials.")
+	cmd.Flags().StringVar(&o.Experiment, "experiment", o.Experiment, "Also save the view as an annotation on the named cluster `experiment`.")
+
+	commander.ExitOnError(cmd)
+	return cmd
+}
+
+func (o *SaveOptions) save(ctx context.Context) error {
+	if err := o.Config.Update(config.SaveView(o.Name, &o.View)); err != nil {
+		return err
+	}
+
+	if err := o.Config.Write(); err != nil {
+		return err
+	}
+
+	if o.Experiment != "" {
+		return o.annotate(ctx)
+	}
+
+	return nil
+}
+
+// annotate records the view on the cluster experiment
+func (o *SaveOptions) annotate(ctx context.Context) error {
+	value, err := json.Marshal(&o.View)
+	if err != nil {
+		return err
+	}
+
+	annotation := redskyv1beta1.AnnotationViewPrefix + o.Name + "=" + string(value)
+	cmd, err := o.Config.Kubectl(ctx, "annotate", "experiments.v1beta1.redskyops.dev", o.Experiment, annotation, "--overwrite")
+	if err != nil {
+		return err
+	}
+	cmd.Stdout = o.Out
+	cmd.Stderr = o.ErrOut
+	return cmd.Run()
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 GramLabs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package views
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	redskyv1beta1 "github.com/redskyops/redskyops-controller/api/v1beta1"
+	"github.com/redskyops/redskyops-controller/internal/config"
+	"github.com/redskyops/redskyops-controller/redskyctl/internal/commander"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// Options includes the configuration for the subcommands
+type Options struct {
+	// Config is the Red Sky Configuration
+	Config *config.RedSkyConfig
+}
+
+// NewCommand creates a new command for working with saved trial views
+func NewCommand(o *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "views",
+		Short: "Work with saved trial views",
+		Long:  "Save, list and apply named combinations of trial filters, sort order and output format",
+	}
+
+	cmd.AddCommand(NewSaveCommand(&SaveOptions{Config: o.Config}))
+	cmd.AddCommand(NewListCommand(&ListOptions{Config: o.Config}))
+	cmd.AddCommand(NewApplyCommand(&ApplyOptions{Config: o.Config}))
+
+	return cmd
+}
+
+// experimentView reads a view from the annotations of a cluster experiment, returns nil if the view is not present
+func experimentView(ctx context.Context, cfg *config.RedSkyConfig, streams commander.IOStreams, experimentName, viewName string) (*config.View, error) {
+	get, err := cfg.Kubectl(ctx, "get", "experiments.v1beta1.redskyops.dev", experimentName, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	get.Stderr = streams.ErrOut
+	data, err := get.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	exp := &redskyv1beta1.Experiment{}
+	if err := yaml.Unmarshal(data, exp); err != nil {
+		return nil, err
+	}
+
+	value, ok := exp.Annotations[redskyv1beta1.AnnotationViewPrefix+viewName]
+	if !ok {
+		return nil, nil
+	}
+
+	v := &config.View{}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		return nil, fmt.Errorf("invalid view %q on experiment %q: %w", viewName, experimentName, err)
+	}
+	return v, nil
+}
+
+// findView returns the locally saved view with the supplied name, returns nil if the view does not exist
+func findView(cfg *config.RedSkyConfig, name string) *config.View {
+	for _, v := range cfg.Views() {
+		if v.Name == name {
+			return &v.View
+		}
+	}
+	return nil
+}
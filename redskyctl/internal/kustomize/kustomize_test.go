@@ -75,7 +75,7 @@ func Test(t *testing.T) {
 
 			res, err := k.Run(k.Base)
 			assert.NoError(t, err)
-			assert.Equal(t, res.Size(), 6)
+			assert.Equal(t, res.Size(), 7)
 
 			r, err := res.Select(types.Selector{Name: "redsky-controller-manager"})
 			assert.NoError(t, err)